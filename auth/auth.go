@@ -3,9 +3,11 @@ package auth
 import (
 	"context"
 	"crypto/rsa"
+	"crypto/tls"
 	"crypto/x509"
 	"fmt"
 	"io/ioutil"
+	"net/url"
 	"strings"
 
 	"golang.org/x/crypto/pkcs12"
@@ -19,13 +21,91 @@ type Authorizer interface {
 	Token() (*oauth2.Token, error)
 }
 
+// ContextAuthorizer is an Authorizer which can acquire an access token using a context supplied by the caller, so
+// that cancellation and deadlines also apply to acquiring or refreshing the token
+type ContextAuthorizer interface {
+	Authorizer
+	TokenWithContext(ctx context.Context) (*oauth2.Token, error)
+}
+
+// TenantAuthorizer is an Authorizer which can derive an Authorizer for a different tenant, reusing the same
+// credentials. This is useful for multi-tenant applications which need to make calls in their customers' tenants.
+// The application must be configured as multi-tenant, and must have been consented to in the target tenant.
+type TenantAuthorizer interface {
+	Authorizer
+	WithTenant(tenantId string) (Authorizer, error)
+}
+
+// CertificateAuthorizer is an Authorizer which authenticates using a client certificate, and which can report the
+// thumbprints of the certificate, e.g. to verify that it matches a certificate registered for the application
+type CertificateAuthorizer interface {
+	Authorizer
+	CertificateThumbprint() (*CertificateThumbprint, error)
+}
+
+// TokenWithContext returns an access token from the provided Authorizer, using the provided context if the Authorizer
+// is a ContextAuthorizer, otherwise falling back to Token()
+func TokenWithContext(ctx context.Context, authorizer Authorizer) (*oauth2.Token, error) {
+	if a, ok := authorizer.(ContextAuthorizer); ok {
+		return a.TokenWithContext(ctx)
+	}
+	return authorizer.Token()
+}
+
+// authorizerContext returns the context with which an authorizer was configured, for use by Token()
+func authorizerContext(ctx context.Context) context.Context {
+	if ctx == nil {
+		return context.Background()
+	}
+	return ctx
+}
+
 type Api int
 
 const (
 	MsGraph Api = iota
+
+	// Deprecated: Azure Active Directory Graph has been retired, use MsGraph instead.
 	AadGraph
+
+	ResourceManager
+	KeyVault
+	Storage
 )
 
+// validate returns an error if the Api is not one of the supported values
+func (a Api) validate() error {
+	switch a {
+	case MsGraph, AadGraph, ResourceManager, KeyVault, Storage:
+		return nil
+	}
+	return fmt.Errorf("unsupported Api: %d", a)
+}
+
+// environmentApi returns the configuration for the Api in the specified environment. An error is returned if the Api
+// is not supported, or if it has no endpoint in the environment.
+func environmentApi(env environments.Environment, api Api) (environments.Api, error) {
+	var a environments.Api
+	switch api {
+	case MsGraph:
+		a = env.MsGraph
+	case AadGraph:
+		a = env.AadGraph
+	case ResourceManager:
+		a = env.ResourceManager
+	case KeyVault:
+		a = env.KeyVault
+	case Storage:
+		a = env.Storage
+	default:
+		return a, api.validate()
+	}
+	if a.Endpoint == "" {
+		return a, fmt.Errorf("Api %d has no endpoint configured in the environment", api)
+	}
+	return a, nil
+}
+
 // NewAuthorizer returns a suitable Authorizer depending on what is defined in the Config
 // Authorizers are selected for authentication methods in the following preferential order:
 // - Client certificate authentication
@@ -37,49 +117,102 @@ const (
 //
 // For client certificate authentication, specify TenantID, ClientID and ClientCertData / ClientCertPath.
 // For client secret authentication, specify TenantID, ClientID and ClientSecret.
+// Since these methods obtain app-only tokens, TenantID must identify a specific tenant, and an error is returned if it
+// is one of the multi-tenant aliases `common`, `organizations` or `consumers`.
 // MSI authentication (if enabled) using the Azure Metadata Service is then attempted
 // Azure CLI authentication (if enabled) is attempted last
 //
+// When an Observer is set in the Config, it is notified each time the returned Authorizer returns a token. Likewise
+// OnTokenRefreshed is called each time the returned Authorizer acquires a new token.
+//
 // It's recommended to only enable the mechanisms you have configured and are known to work in the execution
 // environment. If any authentication mechanism fails due to misconfiguration or some other error, the function
 // will return (nil, error) and later mechanisms will not be attempted.
 func (c *Config) NewAuthorizer(ctx context.Context, api Api) (Authorizer, error) {
+	defaultScopes, r, err := c.audience(api)
+	if err != nil {
+		return nil, err
+	}
+	s, err := mergeScopes(defaultScopes, c.Scopes)
+	if err != nil {
+		return nil, fmt.Errorf("invalid scopes: %s", err)
+	}
+
+	retryPolicy := c.RetryPolicy
+	if retryPolicy == nil {
+		retryPolicy = DefaultRetryPolicy()
+	} else if err := retryPolicy.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid retry policy: %s", err)
+	}
+
+	tokenUrl, err := c.tokenUrl()
+	if err != nil {
+		return nil, fmt.Errorf("invalid token endpoint: %s", err)
+	}
+
 	if c.EnableClientCertAuth && strings.TrimSpace(c.TenantID) != "" && strings.TrimSpace(c.ClientID) != "" && (len(c.ClientCertData) > 0 || strings.TrimSpace(c.ClientCertPath) != "") {
-		a, err := NewClientCertificateAuthorizer(ctx, c.Environment, api, c.Version, c.TenantID, c.ClientID, c.ClientCertData, c.ClientCertPath, c.ClientCertPassword)
+		a, err := newClientCertificateAuthorizer(ctx, tokenUrl, r, c.Version, c.TenantID, c.ClientID, c.ClientCertData, c.ClientCertPath, c.ClientCertPassword, s, c.TLSConfig, retryPolicy, c.ClientCertAssertionClaims)
 		if err != nil {
 			return nil, fmt.Errorf("could not configure ClientCertificate Authorizer: %s", err)
 		}
 		if a != nil {
+			if c.Observer != nil {
+				setObserver(a, c.Observer)
+			}
+			if c.OnTokenRefreshed != nil {
+				setOnTokenRefreshed(a, c.OnTokenRefreshed)
+			}
 			return a, nil
 		}
 	}
 
 	if c.EnableClientSecretAuth && strings.TrimSpace(c.TenantID) != "" && strings.TrimSpace(c.ClientID) != "" && strings.TrimSpace(c.ClientSecret) != "" {
-		a, err := NewClientSecretAuthorizer(ctx, c.Environment, api, c.Version, c.TenantID, c.ClientID, c.ClientSecret)
+		a, err := newClientSecretAuthorizer(ctx, tokenUrl, r, c.Version, c.TenantID, c.ClientID, c.ClientSecret, s, c.TLSConfig, retryPolicy)
 		if err != nil {
 			return nil, fmt.Errorf("could not configure ClientCertificate Authorizer: %s", err)
 		}
 		if a != nil {
+			if c.Observer != nil {
+				setObserver(a, c.Observer)
+			}
+			if c.OnTokenRefreshed != nil {
+				setOnTokenRefreshed(a, c.OnTokenRefreshed)
+			}
 			return a, nil
 		}
 	}
 
 	if c.EnableMsiAuth {
-		a, err := NewMsiAuthorizer(ctx, c.Environment, api, c.MsiEndpoint)
+		a, err := newMsiAuthorizer(ctx, r, c.MsiEndpoint, c.TLSConfig, retryPolicy)
 		if err != nil {
 			return nil, fmt.Errorf("could not configure MSI Authorizer: %s", err)
 		}
 		if a != nil {
+			if c.Observer != nil {
+				setObserver(a, c.Observer)
+			}
+			if c.OnTokenRefreshed != nil {
+				setOnTokenRefreshed(a, c.OnTokenRefreshed)
+			}
 			return a, nil
 		}
 	}
 
 	if c.EnableAzureCliToken {
-		a, err := NewAzureCliAuthorizer(ctx, api, c.TenantID)
+		if c.Resource != "" {
+			return nil, fmt.Errorf("could not configure AzureCli Authorizer: Resource is not supported with Azure CLI authentication")
+		}
+		a, err := newAzureCliAuthorizer(ctx, api, c.TenantID, c.AzureCliPath, c.AzureCliMinimumVersion)
 		if err != nil {
-			return nil, fmt.Errorf("could not configure AzureCli Authorizer: %s", err)
+			return nil, fmt.Errorf("could not configure AzureCli Authorizer: %w", err)
 		}
 		if a != nil {
+			if c.Observer != nil {
+				setObserver(a, c.Observer)
+			}
+			if c.OnTokenRefreshed != nil {
+				setOnTokenRefreshed(a, c.OnTokenRefreshed)
+			}
 			return a, nil
 		}
 	}
@@ -87,9 +220,44 @@ func (c *Config) NewAuthorizer(ctx context.Context, api Api) (Authorizer, error)
 	return nil, fmt.Errorf("no Authorizer could be configured, please check your configuration")
 }
 
+// NewAuthorizerForVersion returns a suitable Authorizer as for NewAuthorizer, acquiring tokens of the specified
+// version instead of the Version set in the Config. This allows a single Config to be used for APIs which require
+// different token versions, e.g. v2 tokens for Microsoft Graph and v1 tokens for Azure Active Directory Graph. The
+// token endpoint, and whether a resource or scopes are requested, are determined by the specified version.
+func (c *Config) NewAuthorizerForVersion(ctx context.Context, api Api, version TokenVersion) (Authorizer, error) {
+	if version != TokenVersion1 && version != TokenVersion2 {
+		return nil, fmt.Errorf("unsupported token version: %d", version)
+	}
+	conf := *c
+	conf.Version = version
+	return conf.NewAuthorizer(ctx, api)
+}
+
+// NewAuthorizerForTenant returns a suitable Authorizer as for NewAuthorizer, acquiring app-only tokens for the
+// specified tenant instead of the TenantID set in the Config. This allows a multi-tenant application, such as a CSP
+// partner application, to operate in many customer tenants using a single credential. The application must have been
+// consented to in the customer tenant. Managed identities cannot obtain tokens for other tenants, so MSI
+// authentication is not considered.
+func (c *Config) NewAuthorizerForTenant(ctx context.Context, api Api, tenantId string) (Authorizer, error) {
+	if strings.TrimSpace(tenantId) == "" {
+		return nil, fmt.Errorf("tenantId must be specified")
+	}
+	if c.TokenURL != "" {
+		return nil, fmt.Errorf("cannot authorize for another tenant when TokenURL is specified")
+	}
+	conf := *c
+	conf.TenantID = tenantId
+	conf.EnableMsiAuth = false
+	return conf.NewAuthorizer(ctx, api)
+}
+
 // NewAzureCliAuthorizer returns an Authorizer which authenticates using the Azure CLI.
 func NewAzureCliAuthorizer(ctx context.Context, api Api, tenantId string) (Authorizer, error) {
-	conf, err := NewAzureCliConfig(api, tenantId)
+	return newAzureCliAuthorizer(ctx, api, tenantId, "", "")
+}
+
+func newAzureCliAuthorizer(ctx context.Context, api Api, tenantId, path, minimumVersion string) (Authorizer, error) {
+	conf, err := newAzureCliConfig(api, tenantId, path, minimumVersion)
 	if err != nil {
 		return nil, err
 	}
@@ -98,7 +266,25 @@ func NewAzureCliAuthorizer(ctx context.Context, api Api, tenantId string) (Autho
 
 // NewMsiAuthorizer returns an authorizer which uses managed service identity to for authentication.
 func NewMsiAuthorizer(ctx context.Context, environment environments.Environment, api Api, msiEndpoint string) (Authorizer, error) {
-	conf, err := NewMsiConfig(ctx, resource(environment, api), msiEndpoint)
+	r, err := resource(environment, api)
+	if err != nil {
+		return nil, err
+	}
+	return newMsiAuthorizer(ctx, r, msiEndpoint, nil, nil)
+}
+
+// NewMsiAuthorizerForResource returns an authorizer which uses managed service identity for authentication, acquiring
+// tokens for the specified resource instead of one of the APIs enumerated by Api, e.g. `https://example.com` or
+// `api://00000000-0000-0000-0000-000000000000` for a custom API.
+func NewMsiAuthorizerForResource(ctx context.Context, resource, msiEndpoint string) (Authorizer, error) {
+	if err := validateResource(resource); err != nil {
+		return nil, err
+	}
+	return newMsiAuthorizer(ctx, resource, msiEndpoint, nil, nil)
+}
+
+func newMsiAuthorizer(ctx context.Context, resource, msiEndpoint string, tlsConfig *tls.Config, retryPolicy *RetryPolicy) (Authorizer, error) {
+	conf, err := newMsiConfig(ctx, resource, msiEndpoint, tlsConfig, retryPolicy)
 	if err != nil {
 		return nil, err
 	}
@@ -107,6 +293,33 @@ func NewMsiAuthorizer(ctx context.Context, environment environments.Environment,
 
 // NewClientCertificateAuthorizer returns an authorizer which uses client certificate authentication.
 func NewClientCertificateAuthorizer(ctx context.Context, environment environments.Environment, api Api, tokenVersion TokenVersion, tenantId, clientId string, pfxData []byte, pfxPath, pfxPass string) (Authorizer, error) {
+	s, err := scopes(environment, api)
+	if err != nil {
+		return nil, err
+	}
+	r, err := resource(environment, api)
+	if err != nil {
+		return nil, err
+	}
+	return newClientCertificateAuthorizer(ctx, TokenEndpoint(environment.AzureADEndpoint, tenantId, tokenVersion), r, tokenVersion, tenantId, clientId, pfxData, pfxPath, pfxPass, s, nil, nil, nil)
+}
+
+// NewClientCertificateAuthorizerForResource returns an authorizer which uses client certificate authentication,
+// acquiring tokens for the specified resource instead of one of the APIs enumerated by Api. The resource must be a
+// URL, e.g. `https://example.com` or `api://00000000-0000-0000-0000-000000000000` for a custom API. For v1 tokens, the
+// resource is requested as given, and for v2 tokens the `<resource>/.default` scope is requested.
+func NewClientCertificateAuthorizerForResource(ctx context.Context, environment environments.Environment, tokenVersion TokenVersion, tenantId, clientId string, pfxData []byte, pfxPath, pfxPass, resource string) (Authorizer, error) {
+	if err := validateResource(resource); err != nil {
+		return nil, err
+	}
+	return newClientCertificateAuthorizer(ctx, TokenEndpoint(environment.AzureADEndpoint, tenantId, tokenVersion), resource, tokenVersion, tenantId, clientId, pfxData, pfxPath, pfxPass, resourceScopes(resource), nil, nil, nil)
+}
+
+func newClientCertificateAuthorizer(ctx context.Context, tokenUrl, resource string, tokenVersion TokenVersion, tenantId, clientId string, pfxData []byte, pfxPath, pfxPass string, scopes []string, tlsConfig *tls.Config, retryPolicy *RetryPolicy, assertionClaims func(map[string]interface{})) (Authorizer, error) {
+	if err := validateClientCredentialsTenant(tenantId); err != nil {
+		return nil, err
+	}
+
 	if len(pfxData) == 0 {
 		var err error
 		pfxData, err = ioutil.ReadFile(pfxPath)
@@ -126,37 +339,81 @@ func NewClientCertificateAuthorizer(ctx context.Context, environment environment
 	}
 
 	conf := ClientCredentialsConfig{
-		ClientID:    clientId,
-		PrivateKey:  x509.MarshalPKCS1PrivateKey(priv),
-		Certificate: cert.Raw,
-		Scopes:      scopes(environment, api),
-		TokenURL:    TokenEndpoint(environment.AzureADEndpoint, tenantId, tokenVersion),
+		ClientID:        clientId,
+		PrivateKey:      x509.MarshalPKCS1PrivateKey(priv),
+		Certificate:     cert.Raw,
+		Scopes:          scopes,
+		TokenURL:        tokenUrl,
+		TLSConfig:       tlsConfig,
+		RetryPolicy:     retryPolicy,
+		AssertionClaims: assertionClaims,
 	}
 	if tokenVersion == TokenVersion1 {
-		conf.Resource = resource(environment, api)
+		conf.Resource = resource
 	}
 	return conf.TokenSource(ctx, ClientCredentialsAssertionType), nil
 }
 
 // NewClientSecretAuthorizer returns an authorizer which uses client secret authentication.
 func NewClientSecretAuthorizer(ctx context.Context, environment environments.Environment, api Api, tokenVersion TokenVersion, tenantId, clientId, clientSecret string) (Authorizer, error) {
+	s, err := scopes(environment, api)
+	if err != nil {
+		return nil, err
+	}
+	r, err := resource(environment, api)
+	if err != nil {
+		return nil, err
+	}
+	return newClientSecretAuthorizer(ctx, TokenEndpoint(environment.AzureADEndpoint, tenantId, tokenVersion), r, tokenVersion, tenantId, clientId, clientSecret, s, nil, nil)
+}
+
+// NewClientSecretAuthorizerForResource returns an authorizer which uses client secret authentication, acquiring tokens
+// for the specified resource instead of one of the APIs enumerated by Api. The resource must be a URL, e.g.
+// `https://example.com` or `api://00000000-0000-0000-0000-000000000000` for a custom API. For v1 tokens, the resource is
+// requested as given, and for v2 tokens the `<resource>/.default` scope is requested.
+func NewClientSecretAuthorizerForResource(ctx context.Context, environment environments.Environment, tokenVersion TokenVersion, tenantId, clientId, clientSecret, resource string) (Authorizer, error) {
+	if err := validateResource(resource); err != nil {
+		return nil, err
+	}
+	return newClientSecretAuthorizer(ctx, TokenEndpoint(environment.AzureADEndpoint, tenantId, tokenVersion), resource, tokenVersion, tenantId, clientId, clientSecret, resourceScopes(resource), nil, nil)
+}
+
+func newClientSecretAuthorizer(ctx context.Context, tokenUrl, resource string, tokenVersion TokenVersion, tenantId, clientId, clientSecret string, scopes []string, tlsConfig *tls.Config, retryPolicy *RetryPolicy) (Authorizer, error) {
+	if err := validateClientCredentialsTenant(tenantId); err != nil {
+		return nil, err
+	}
+
 	conf := ClientCredentialsConfig{
 		ClientID:     clientId,
 		ClientSecret: clientSecret,
-		Scopes:       scopes(environment, api),
-		TokenURL:     TokenEndpoint(environment.AzureADEndpoint, tenantId, tokenVersion),
+		Scopes:       scopes,
+		TokenURL:     tokenUrl,
+		TLSConfig:    tlsConfig,
+		RetryPolicy:  retryPolicy,
 	}
 	if tokenVersion == TokenVersion1 {
-		conf.Resource = resource(environment, api)
+		conf.Resource = resource
 	}
 	return conf.TokenSource(ctx, ClientCredentialsSecretType), nil
 }
 
+// validateClientCredentialsTenant ensures that a specific tenant is targeted when using the client credentials flow,
+// since app-only tokens cannot be issued by the multi-tenant endpoints. These are only valid for interactive flows.
+func validateClientCredentialsTenant(tenantId string) error {
+	switch strings.ToLower(strings.TrimSpace(tenantId)) {
+	case "":
+		return fmt.Errorf("a tenant ID must be specified, since app-only tokens must be issued by a specific tenant")
+	case "common", "organizations", "consumers":
+		return fmt.Errorf("tenant %q cannot be used since app-only tokens must be issued by a specific tenant, please specify a tenant ID or domain name", tenantId)
+	}
+	return nil
+}
+
 func TokenEndpoint(endpoint environments.AzureADEndpoint, tenant string, version TokenVersion) (e string) {
 	if tenant == "" {
 		tenant = "common"
 	}
-	e = fmt.Sprintf("%s/%s/oauth2", endpoint, tenant)
+	e = fmt.Sprintf("%s/%s/oauth2", strings.TrimRight(string(endpoint), "/"), tenant)
 	if version == TokenVersion2 {
 		e = fmt.Sprintf("%s/%s", e, "v2.0")
 	}
@@ -164,22 +421,108 @@ func TokenEndpoint(endpoint environments.AzureADEndpoint, tenant string, version
 	return
 }
 
-func scopes(env environments.Environment, api Api) (s []string) {
-	switch api {
-	case MsGraph:
-		s = []string{fmt.Sprintf("%s/.default", env.MsGraph.Endpoint)}
-	case AadGraph:
-		s = []string{fmt.Sprintf("%s/.default", env.AadGraph.Endpoint)}
+// B2CTokenEndpoint returns the v2 token endpoint for the user flow or custom policy of an Azure AD B2C tenant, e.g.
+// `https://contoso.b2clogin.com/contoso.onmicrosoft.com/B2C_1_signin/oauth2/v2.0/token`, where endpoint is the B2C
+// authority host and tenant is the domain name or ID of the B2C tenant.
+func B2CTokenEndpoint(endpoint environments.AzureADEndpoint, tenant, policy string) string {
+	return fmt.Sprintf("%s/%s/%s/oauth2/v2.0/token", strings.TrimRight(string(endpoint), "/"), tenant, policy)
+}
+
+// audience returns the default scopes and resource for acquired tokens, which are for the Resource set in the Config
+// when specified, otherwise for the specified API
+func (c *Config) audience(api Api) ([]string, string, error) {
+	if c.Resource != "" {
+		if err := validateResource(c.Resource); err != nil {
+			return nil, "", err
+		}
+		return resourceScopes(c.Resource), c.Resource, nil
 	}
-	return
+	s, err := scopes(c.Environment, api)
+	if err != nil {
+		return nil, "", err
+	}
+	r, err := resource(c.Environment, api)
+	if err != nil {
+		return nil, "", err
+	}
+	return s, r, nil
 }
 
-func resource(env environments.Environment, api Api) (r string) {
-	switch api {
-	case MsGraph:
-		r = fmt.Sprintf("%s/", env.MsGraph.Endpoint)
-	case AadGraph:
-		r = fmt.Sprintf("%s/", env.AadGraph.Endpoint)
+func scopes(env environments.Environment, api Api) ([]string, error) {
+	a, err := environmentApi(env, api)
+	if err != nil {
+		return nil, err
 	}
-	return
+	return []string{fmt.Sprintf("%s/.default", a.Endpoint)}, nil
+}
+
+// oidcScopes are OpenID Connect scopes, which can be requested alongside any other scopes
+var oidcScopes = map[string]bool{
+	"email":          true,
+	"offline_access": true,
+	"openid":         true,
+	"profile":        true,
+}
+
+// mergeScopes appends additional scopes to the default scopes, omitting duplicates, and validates the result
+func mergeScopes(defaults, additional []string) ([]string, error) {
+	seen := make(map[string]bool, len(defaults)+len(additional))
+	s := make([]string, 0, len(defaults)+len(additional))
+	for _, scope := range append(append([]string{}, defaults...), additional...) {
+		scope = strings.TrimSpace(scope)
+		if scope == "" || seen[scope] {
+			continue
+		}
+		seen[scope] = true
+		s = append(s, scope)
+	}
+	if err := validateScopes(s); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// validateScopes ensures that a `.default` scope is not combined with specific permission scopes, since this is
+// rejected by Azure Active Directory. OpenID Connect scopes such as `offline_access` can be combined with either.
+func validateScopes(s []string) error {
+	var defaultScopes, specificScopes []string
+	for _, scope := range s {
+		switch {
+		case oidcScopes[scope]:
+			continue
+		case scope == ".default" || strings.HasSuffix(scope, "/.default"):
+			defaultScopes = append(defaultScopes, scope)
+		default:
+			specificScopes = append(specificScopes, scope)
+		}
+	}
+	if len(defaultScopes) > 0 && len(specificScopes) > 0 {
+		return fmt.Errorf("the %q scope cannot be combined with specific permission scopes (%s), only OpenID Connect scopes such as %q can be requested alongside it", defaultScopes[0], strings.Join(specificScopes, ", "), "offline_access")
+	}
+	return nil
+}
+
+// validateResource ensures that a resource is an absolute URL
+func validateResource(resource string) error {
+	u, err := url.Parse(resource)
+	if err != nil {
+		return fmt.Errorf("invalid resource %q: %v", resource, err)
+	}
+	if u.Scheme == "" || u.Host == "" {
+		return fmt.Errorf("invalid resource %q: must be an absolute URL, e.g. https://example.com", resource)
+	}
+	return nil
+}
+
+// resourceScopes returns the `.default` scope for a resource
+func resourceScopes(resource string) []string {
+	return []string{fmt.Sprintf("%s/.default", strings.TrimRight(resource, "/"))}
+}
+
+func resource(env environments.Environment, api Api) (string, error) {
+	a, err := environmentApi(env, api)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%s/", a.Endpoint), nil
 }
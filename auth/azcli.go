@@ -9,16 +9,33 @@ import (
 	"os"
 	"os/exec"
 	"regexp"
+	"runtime"
 	"strings"
 	"time"
 
 	"github.com/hashicorp/go-version"
 	"golang.org/x/oauth2"
+
+	"github.com/manicminer/hamilton/environments"
 )
 
 const (
 	azureCliMinimumVersion   = "2.0.81"
 	azureCliNextMajorVersion = "3.0.0"
+
+	// azureCliExpiresOnLayout is the layout of the local time `expiresOn` field in access tokens from the Azure CLI
+	azureCliExpiresOnLayout = "2006-01-02 15:04:05.999999"
+
+	// azureCliPathEnvVar is the environment variable used to specify the path to the Azure CLI executable
+	azureCliPathEnvVar = "AZURE_CLI_PATH"
+)
+
+var (
+	// ErrAzureCliNotFound is returned when the Azure CLI executable cannot be found
+	ErrAzureCliNotFound = errors.New("could not find Azure CLI executable")
+
+	// ErrAzureCliNotLoggedIn is returned when the Azure CLI has no logged in account, and `az login` should be run
+	ErrAzureCliNotLoggedIn = errors.New("not logged in to Azure CLI, please run `az login`")
 )
 
 // AzureCliAuthorizer is an Authorizer which supports the Azure CLI.
@@ -32,19 +49,23 @@ type AzureCliAuthorizer struct {
 
 // Token returns an access token using the Azure CLI as an authentication mechanism.
 func (a AzureCliAuthorizer) Token() (*oauth2.Token, error) {
+	return a.TokenWithContext(authorizerContext(a.ctx))
+}
+
+// TokenWithContext returns an access token using the Azure CLI as an authentication mechanism. The Azure CLI
+// process is killed if the context is cancelled before it completes.
+func (a AzureCliAuthorizer) TokenWithContext(ctx context.Context) (*oauth2.Token, error) {
 	var token struct {
-		AccessToken string `json:"accessToken"`
-		ExpiresOn   string `json:"expiresOn"`
-		Tenant      string `json:"tenant"`
-		TokenType   string `json:"tokenType"`
+		AccessToken        string `json:"accessToken"`
+		ExpiresOn          string `json:"expiresOn"`  // local time, without a time zone
+		ExpiresOnTimestamp int64  `json:"expires_on"` // epoch timestamp, only present in newer versions
+		Tenant             string `json:"tenant"`
+		TokenType          string `json:"tokenType"`
 	}
 
-	var resourceType string
-	switch a.conf.Api {
-	case MsGraph:
-		resourceType = "ms-graph"
-	case AadGraph:
-		resourceType = "aad-graph"
+	resourceType, err := azureCliResourceType(a.conf.Api)
+	if err != nil {
+		return nil, err
 	}
 
 	azArgs := []string{"account", "get-access-token", fmt.Sprintf("--resource-type=%s", resourceType)}
@@ -55,15 +76,80 @@ func (a AzureCliAuthorizer) Token() (*oauth2.Token, error) {
 		azArgs = append(azArgs, "--tenant", a.conf.TenantID)
 	}
 
-	err := jsonUnmarshalAzCmd(&token, azArgs...)
+	err = jsonUnmarshalAzCmd(ctx, a.conf.Path, &token, azArgs...)
 	if err != nil {
 		return nil, err
 	}
 
+	expiry, err := azureCliTokenExpiry(token.ExpiresOnTimestamp, token.ExpiresOn)
+	if err != nil {
+		return nil, fmt.Errorf("could not determine expiry of access token from Azure CLI version %s, please upgrade Azure CLI to %s or newer: %v", a.conf.Version, azureCliMinimumVersion, err)
+	}
+
 	return &oauth2.Token{
 		AccessToken: token.AccessToken,
 		TokenType:   token.TokenType,
-		Expiry:      time.Time{},
+		Expiry:      expiry,
+	}, nil
+}
+
+// azureCliTokenExpiry returns the expiry of an access token from the Azure CLI. Newer versions of Azure CLI include
+// an epoch timestamp which is preferred, whilst older versions only include the expiry in local time.
+func azureCliTokenExpiry(expiresOnTimestamp int64, expiresOn string) (time.Time, error) {
+	if expiresOnTimestamp > 0 {
+		return time.Unix(expiresOnTimestamp, 0), nil
+	}
+	if expiresOn == "" {
+		return time.Time{}, errors.New("token has no expiry")
+	}
+	expiry, err := azureCliParseLocalTime(expiresOn, time.Local)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("parsing token expiry %q: %v", expiresOn, err)
+	}
+	return expiry, nil
+}
+
+// azureCliParseLocalTime parses a time without a time zone, as emitted by the Azure CLI, in the specified location.
+// When the time is ambiguous, i.e. it occurs twice because clocks are turned back at the end of daylight saving time,
+// the earlier instant is returned so that tokens are never considered valid beyond their actual expiry.
+func azureCliParseLocalTime(value string, loc *time.Location) (time.Time, error) {
+	wall, err := time.Parse(azureCliExpiresOnLayout, value)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	// Consider the offsets in effect either side of the wall clock time, which differ around a transition
+	var ret time.Time
+	for _, t := range []time.Time{wall.Add(-24 * time.Hour), wall.Add(24 * time.Hour)} {
+		_, offset := t.In(loc).Zone()
+		candidate := wall.Add(-time.Duration(offset) * time.Second)
+		if candidate.In(loc).Format(azureCliExpiresOnLayout) != wall.Format(azureCliExpiresOnLayout) {
+			continue
+		}
+		if ret.IsZero() || candidate.Before(ret) {
+			ret = candidate
+		}
+	}
+	if ret.IsZero() {
+		// The wall clock time was skipped when clocks were turned forward, so fall back to the standard behavior
+		return time.ParseInLocation(azureCliExpiresOnLayout, value, loc)
+	}
+
+	return ret.In(loc), nil
+}
+
+// WithTenant returns an AzureCliAuthorizer which requests access tokens for the specified tenant using the same
+// logged in account.
+func (a AzureCliAuthorizer) WithTenant(tenantId string) (Authorizer, error) {
+	if strings.TrimSpace(tenantId) == "" {
+		return nil, errors.New("AzureCliAuthorizer: tenantId must not be empty")
+	}
+	conf := *a.conf
+	conf.TenantID = tenantId
+	return &AzureCliAuthorizer{
+		TenantID: tenantId,
+		ctx:      a.ctx,
+		conf:     &conf,
 	}, nil
 }
 
@@ -71,19 +157,38 @@ func (a AzureCliAuthorizer) Token() (*oauth2.Token, error) {
 type AzureCliConfig struct {
 	Api      Api
 	TenantID string
+
+	// Path is the path to the Azure CLI executable
+	Path string
+
+	// Version is the detected version of Azure CLI
+	Version string
 }
 
 // NewAzureCliConfig validates the supplied tenant ID and returns a new AzureCliConfig.
 func NewAzureCliConfig(api Api, tenantId string) (*AzureCliConfig, error) {
-	var err error
+	return newAzureCliConfig(api, tenantId, "", "")
+}
+
+func newAzureCliConfig(api Api, tenantId, path, minimumVersion string) (*AzureCliConfig, error) {
+	if _, err := azureCliResourceType(api); err != nil {
+		return nil, err
+	}
+
+	// locate az-cli
+	path, err := azureCliPath(path)
+	if err != nil {
+		return nil, err
+	}
 
 	// check az-cli version
-	if err = checkAzVersion(); err != nil {
+	cliVersion, err := checkAzVersion(path, minimumVersion)
+	if err != nil {
 		return nil, err
 	}
 
 	// check tenant id
-	tenantId, err = checkTenantId(tenantId)
+	tenantId, err = checkTenantId(path, tenantId)
 	if err != nil {
 		return nil, err
 	}
@@ -91,7 +196,7 @@ func NewAzureCliConfig(api Api, tenantId string) (*AzureCliConfig, error) {
 		return nil, errors.New("invalid tenantId or unable to determine tenantId")
 	}
 
-	return &AzureCliConfig{Api: api, TenantID: tenantId}, nil
+	return &AzureCliConfig{Api: api, TenantID: tenantId, Path: path, Version: cliVersion}, nil
 }
 
 // TokenSource provides a source for obtaining access tokens using AzureCliAuthorizer.
@@ -104,51 +209,91 @@ func (c *AzureCliConfig) TokenSource(ctx context.Context) Authorizer {
 	})
 }
 
-// checkAzVersion tries to determine the version of Azure CLI in the path and checks for a compatible version
-func checkAzVersion() error {
+// azureCliPath returns the path to the Azure CLI executable, which is either the specified path, the path set in the
+// AZURE_CLI_PATH environment variable, or else `az` as found in PATH. An error is returned if the executable cannot
+// be found or is not executable.
+func azureCliPath(path string) (string, error) {
+	if path == "" {
+		path = os.Getenv(azureCliPathEnvVar)
+	}
+	if path == "" {
+		p, err := exec.LookPath("az")
+		if err != nil {
+			return "", fmt.Errorf("%w `az` in PATH, please install Azure CLI or set the path to the executable: %v", ErrAzureCliNotFound, err)
+		}
+		return p, nil
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		return "", fmt.Errorf("%w at %q: %v", ErrAzureCliNotFound, path, err)
+	}
+	if info.IsDir() {
+		return "", fmt.Errorf("invalid Azure CLI path %q: is a directory", path)
+	}
+	if runtime.GOOS != "windows" && info.Mode()&0111 == 0 {
+		return "", fmt.Errorf("invalid Azure CLI path %q: file is not executable", path)
+	}
+	return path, nil
+}
+
+// checkAzVersion tries to determine the version of Azure CLI at the specified path and checks for a compatible version,
+// which is at least the specified minimum version if it's newer than the minimum version supported by this package.
+// The detected version is returned.
+func checkAzVersion(path, minimumVersion string) (string, error) {
 	var cliVersion *struct {
 		AzureCli          *string      `json:"azure-cli,omitempty"`
 		AzureCliCore      *string      `json:"azure-cli-core,omitempty"`
 		AzureCliTelemetry *string      `json:"azure-cli-telemetry,omitempty"`
 		Extensions        *interface{} `json:"extensions,omitempty"`
 	}
-	err := jsonUnmarshalAzCmd(&cliVersion, "version")
+	err := jsonUnmarshalAzCmd(context.Background(), path, &cliVersion, "version")
 	if err != nil {
-		return fmt.Errorf("could not parse Azure CLI version: %v", err)
+		return "", fmt.Errorf("could not parse Azure CLI version: %v", err)
 	}
 
 	if cliVersion.AzureCli == nil {
-		return fmt.Errorf("could not detect Azure CLI version. Please ensure you have installed Azure CLI version %s or newer", azureCliMinimumVersion)
+		return "", fmt.Errorf("could not detect Azure CLI version. Please ensure you have installed Azure CLI version %s or newer", azureCliMinimumVersion)
 	}
 
 	actual, err := version.NewVersion(*cliVersion.AzureCli)
 	if err != nil {
-		return fmt.Errorf("could not parse detected Azure CLI version %q: %+v", *cliVersion.AzureCli, err)
+		return "", fmt.Errorf("could not parse detected Azure CLI version %q: %+v", *cliVersion.AzureCli, err)
 	}
 
 	supported, err := version.NewVersion(azureCliMinimumVersion)
 	if err != nil {
-		return fmt.Errorf("could not parse supported Azure CLI version: %+v", err)
+		return "", fmt.Errorf("could not parse supported Azure CLI version: %+v", err)
+	}
+
+	if minimumVersion != "" {
+		minimum, err := version.NewVersion(minimumVersion)
+		if err != nil {
+			return "", fmt.Errorf("could not parse minimum Azure CLI version %q: %+v", minimumVersion, err)
+		}
+		if minimum.GreaterThan(supported) {
+			supported = minimum
+		}
 	}
 
 	nextMajor, err := version.NewVersion(azureCliNextMajorVersion)
 	if err != nil {
-		return fmt.Errorf("could not parse next major Azure CLI version: %+v", err)
+		return "", fmt.Errorf("could not parse next major Azure CLI version: %+v", err)
 	}
 
 	if nextMajor.LessThanOrEqual(actual) {
-		return fmt.Errorf("unsupported Azure CLI version %q detected, please install a version newer than %s but older than %s", actual, supported, nextMajor)
+		return "", fmt.Errorf("unsupported Azure CLI version %q detected, please install a version newer than %s but older than %s", actual, supported, nextMajor)
 	}
 
 	if actual.LessThan(supported) {
-		return fmt.Errorf("unsupported Azure CLI version %q detected, please install version %s or newer and ensure the `az` command is in your path", actual, supported)
+		return "", fmt.Errorf("unsupported Azure CLI version %q detected, please upgrade Azure CLI to %s or newer and ensure the `az` command is in your path", actual, supported)
 	}
 
-	return nil
+	return actual.String(), nil
 }
 
 // checkTenantId validates the supplied tenant ID, and tries to determine the default tenant if a valid one is not supplied.
-func checkTenantId(tenantId string) (string, error) {
+func checkTenantId(path, tenantId string) (string, error) {
 	validTenantId, err := regexp.MatchString("^[a-zA-Z0-9._-]+$", tenantId)
 	if err != nil {
 		return "", fmt.Errorf("could not parse tenant ID %q: %s", tenantId, err)
@@ -159,7 +304,7 @@ func checkTenantId(tenantId string) (string, error) {
 			ID       string `json:"id"`
 			TenantID string `json:"tenantId"`
 		}
-		err := jsonUnmarshalAzCmd(&account, "account", "show")
+		err := jsonUnmarshalAzCmd(context.Background(), path, &account, "account", "show")
 		if err != nil {
 			return "", fmt.Errorf("obtaining tenant ID: %s", err)
 		}
@@ -169,17 +314,20 @@ func checkTenantId(tenantId string) (string, error) {
 	return tenantId, nil
 }
 
-// jsonUnmarshalAzCmd executes an Azure CLI command and unmarshals the JSON output.
-func jsonUnmarshalAzCmd(i interface{}, arg ...string) error {
+// jsonUnmarshalAzCmd executes an Azure CLI command using the executable at the specified path, and unmarshals the JSON output.
+func jsonUnmarshalAzCmd(ctx context.Context, path string, i interface{}, arg ...string) error {
 	var stderr bytes.Buffer
 	var stdout bytes.Buffer
 
 	arg = append(arg, "-o=json")
-	cmd := exec.Command("az", arg...)
+	cmd := exec.CommandContext(ctx, path, arg...)
 	cmd.Stderr = &stderr
 	cmd.Stdout = &stdout
 
 	if err := cmd.Start(); err != nil {
+		if errors.Is(err, exec.ErrNotFound) || errors.Is(err, os.ErrNotExist) {
+			return fmt.Errorf("%w at %q: %v", ErrAzureCliNotFound, path, err)
+		}
 		err := fmt.Errorf("launching Azure CLI: %+v", err)
 		if stdErrStr := stderr.String(); stdErrStr != "" {
 			err = fmt.Errorf("%s: %s", err, strings.TrimSpace(stdErrStr))
@@ -188,6 +336,9 @@ func jsonUnmarshalAzCmd(i interface{}, arg ...string) error {
 	}
 
 	if err := cmd.Wait(); err != nil {
+		if stdErrStr := stderr.String(); strings.Contains(strings.ToLower(stdErrStr), "az login") {
+			return fmt.Errorf("%w: %s", ErrAzureCliNotLoggedIn, strings.TrimSpace(stdErrStr))
+		}
 		err := fmt.Errorf("running Azure CLI: %+v", err)
 		if stdErrStr := stderr.String(); stdErrStr != "" {
 			err = fmt.Errorf("%s: %s", err, strings.TrimSpace(stdErrStr))
@@ -201,3 +352,20 @@ func jsonUnmarshalAzCmd(i interface{}, arg ...string) error {
 
 	return nil
 }
+
+// azureCliResourceType returns the Azure CLI resource type for the specified Api, for use with
+// `az account get-access-token`. Not all APIs have a resource type, in which case an error is returned.
+func azureCliResourceType(api Api) (string, error) {
+	switch api {
+	case MsGraph:
+		return string(environments.MsGraphCliName), nil
+	case AadGraph:
+		return string(environments.AadGraphCliName), nil
+	case ResourceManager:
+		return string(environments.ResourceManagerCliName), nil
+	}
+	if err := api.validate(); err != nil {
+		return "", err
+	}
+	return "", fmt.Errorf("the requested Api (%d) is not supported with Azure CLI authentication", api)
+}
@@ -1,43 +1,216 @@
 package auth
 
 import (
+	"context"
+	"fmt"
+	"math/rand"
 	"sync"
+	"sync/atomic"
+	"time"
 
 	"golang.org/x/oauth2"
 )
 
+const (
+	// defaultRefreshJitter is the default maximum duration by which a cached token is refreshed ahead of its expiry
+	defaultRefreshJitter = 2 * time.Minute
+
+	// tokenExpiryDelta is the duration ahead of expiry at which a token is no longer considered valid, matching the
+	// behavior of oauth2.Token.Valid()
+	tokenExpiryDelta = 10 * time.Second
+)
+
+// CacheAwareAuthorizer is an Authorizer which can report whether the token it most recently returned was served from its cache
+type CacheAwareAuthorizer interface {
+	Authorizer
+	LastTokenFromCache() bool
+}
+
+// RefreshableAuthorizer is an Authorizer which caches tokens, and which can be instructed to discard its cached token
+// so that a new token is acquired on the next call to Token(), e.g. when a cached token is rejected before its expiry
+type RefreshableAuthorizer interface {
+	Authorizer
+	InvalidateToken()
+}
+
 // CachedAuthorizer caches a token until it expires, then acquires a new token from Source
 type CachedAuthorizer struct {
 	// Source contains the underlying Authorizer for obtaining tokens
 	Source Authorizer
 
-	mutex sync.RWMutex
-	token *oauth2.Token
+	// RefreshJitter is the maximum duration by which a token may be refreshed ahead of its expiry. A random duration
+	// up to this value is chosen for each acquired token, so that many processes sharing the same credentials do not
+	// all attempt to refresh their tokens at the same moment.
+	RefreshJitter time.Duration
+
+	// RefreshMargin is a fixed duration ahead of its expiry at which a token is refreshed, in addition to the random
+	// duration chosen using RefreshJitter.
+	RefreshMargin time.Duration
+
+	// Rand is an optional source of randomness used to calculate jitter. When nil, the global source from the
+	// math/rand package is used. Supply a seeded source to obtain deterministic refresh timing in tests.
+	Rand *rand.Rand
+
+	// Clock is an optional function returning the current time, which is used to determine whether the cached token
+	// should be refreshed. When nil, time.Now is used. Supply a fake clock to test refresh behavior deterministically.
+	Clock func() time.Time
+
+	// Observer is optionally notified each time a token is returned, whether from the cache or newly acquired from
+	// Source, and when acquiring a token fails.
+	Observer Observer
+
+	// OnTokenRefreshed is optionally called each time a new token is acquired from Source, e.g. to propagate it to
+	// other subsystems. It is called synchronously whilst the authorizer's lock is held, after the new token has been
+	// cached and before it is returned, so invocations are serialized and callers of Token() wait for it to return.
+	// It must not call Token() on the same authorizer.
+	OnTokenRefreshed func(*oauth2.Token)
+
+	mutex         sync.RWMutex
+	cachedToken   *oauth2.Token
+	refreshAt     time.Time
+	lastFromCache int32
 }
 
 // Token returns the current token if it's still valid, else will acquire a new token
 func (c *CachedAuthorizer) Token() (*oauth2.Token, error) {
+	return c.TokenWithContext(context.Background())
+}
+
+// TokenWithContext returns the current token if it's still valid, else will acquire a new token from Source using
+// the provided context
+func (c *CachedAuthorizer) TokenWithContext(ctx context.Context) (*oauth2.Token, error) {
+	if c.Observer == nil {
+		token, _, err := c.token(ctx)
+		return token, err
+	}
+
+	start := time.Now()
+	token, fromCache, err := c.token(ctx)
+	c.Observer.OnTokenAcquired(time.Since(start), fromCache, err)
+	return token, err
+}
+
+// token returns the current token if it's still valid, else will acquire a new token from Source, also indicating
+// whether the returned token was served from the cache
+func (c *CachedAuthorizer) token(ctx context.Context) (*oauth2.Token, bool, error) {
 	c.mutex.RLock()
-	valid := c.token != nil && c.token.Valid()
+	valid := c.valid()
+	cached := c.cachedToken
 	c.mutex.RUnlock()
 
 	if !valid {
 		c.mutex.Lock()
 		defer c.mutex.Unlock()
-		token, err := c.Source.Token()
+
+		// Another caller may have refreshed the token whilst we were waiting for the lock
+		if c.valid() {
+			atomic.StoreInt32(&c.lastFromCache, 1)
+			return c.cachedToken, true, nil
+		}
+
+		token, err := TokenWithContext(ctx, c.Source)
 		if err != nil {
-			return nil, err
+			return nil, false, err
+		}
+		c.cachedToken = token
+		c.refreshAt = time.Time{}
+		if !token.Expiry.IsZero() {
+			c.refreshAt = token.Expiry.Add(-c.RefreshMargin - c.jitter())
+		}
+		atomic.StoreInt32(&c.lastFromCache, 0)
+		if c.OnTokenRefreshed != nil {
+			c.OnTokenRefreshed(token)
 		}
-		c.token = token
+		return c.cachedToken, false, nil
 	}
 
-	return c.token, nil
+	atomic.StoreInt32(&c.lastFromCache, 1)
+	return cached, true, nil
+}
+
+// LastTokenFromCache returns true when the token most recently returned by Token() was served from the cache,
+// or false when it was freshly acquired from Source.
+func (c *CachedAuthorizer) LastTokenFromCache() bool {
+	return atomic.LoadInt32(&c.lastFromCache) == 1
+}
+
+// InvalidateToken discards the cached token, so that a new token is acquired from Source on the next call to Token()
+func (c *CachedAuthorizer) InvalidateToken() {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.cachedToken = nil
+	c.refreshAt = time.Time{}
+}
+
+// WithTenant returns a new CachedAuthorizer with its own cache, for a Source targeting the specified tenant. An error
+// is returned if the Source is not a TenantAuthorizer. Since a rand.Rand cannot be used concurrently, Rand is not
+// shared and the returned CachedAuthorizer uses the global source to calculate jitter.
+func (c *CachedAuthorizer) WithTenant(tenantId string) (Authorizer, error) {
+	src, ok := c.Source.(TenantAuthorizer)
+	if !ok {
+		return nil, fmt.Errorf("CachedAuthorizer: source authorizer %T does not support targeting another tenant", c.Source)
+	}
+	a, err := src.WithTenant(tenantId)
+	if err != nil {
+		return nil, err
+	}
+	return &CachedAuthorizer{
+		Source:           a,
+		RefreshMargin:    c.RefreshMargin,
+		RefreshJitter:    c.RefreshJitter,
+		Clock:            c.Clock,
+		Observer:         c.Observer,
+		OnTokenRefreshed: c.OnTokenRefreshed,
+	}, nil
+}
+
+// CertificateThumbprint returns the thumbprints of the client certificate used by Source. An error is returned if the
+// Source is not a CertificateAuthorizer.
+func (c *CachedAuthorizer) CertificateThumbprint() (*CertificateThumbprint, error) {
+	src, ok := c.Source.(CertificateAuthorizer)
+	if !ok {
+		return nil, fmt.Errorf("CachedAuthorizer: source authorizer %T does not authenticate using a certificate", c.Source)
+	}
+	return src.CertificateThumbprint()
+}
+
+// valid determines whether the cached token can continue to be used. The caller must hold the mutex.
+func (c *CachedAuthorizer) valid() bool {
+	if c.cachedToken == nil || c.cachedToken.AccessToken == "" {
+		return false
+	}
+	if c.cachedToken.Expiry.IsZero() {
+		return true
+	}
+	now := c.now()
+	return now.Before(c.cachedToken.Expiry.Add(-tokenExpiryDelta)) && (c.refreshAt.IsZero() || now.Before(c.refreshAt))
+}
+
+// now returns the current time according to Clock
+func (c *CachedAuthorizer) now() time.Time {
+	if c.Clock != nil {
+		return c.Clock()
+	}
+	return time.Now()
+}
+
+// jitter returns a random duration between zero and RefreshJitter. The caller must hold the mutex.
+func (c *CachedAuthorizer) jitter() time.Duration {
+	if c.RefreshJitter <= 0 {
+		return 0
+	}
+	if c.Rand != nil {
+		return time.Duration(c.Rand.Int63n(int64(c.RefreshJitter)))
+	}
+	return time.Duration(rand.Int63n(int64(c.RefreshJitter)))
 }
 
 // NewCachedAuthorizer returns an Authorizer that caches an access token for the duration of its validity.
-// If the cached token expires, a new one is acquired and cached.
+// If the cached token expires, a new one is acquired and cached. Tokens are refreshed a short, random
+// duration ahead of their expiry to avoid synchronized refreshes across many processes.
 func NewCachedAuthorizer(src Authorizer) Authorizer {
 	return &CachedAuthorizer{
-		Source: src,
+		Source:        src,
+		RefreshJitter: defaultRefreshJitter,
 	}
 }
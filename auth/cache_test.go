@@ -0,0 +1,304 @@
+package auth_test
+
+import (
+	"context"
+	"math/rand"
+	"testing"
+	"time"
+
+	"golang.org/x/oauth2"
+
+	"github.com/manicminer/hamilton/auth"
+)
+
+type countingAuthorizer struct {
+	calls  int
+	expiry time.Duration
+}
+
+func (a *countingAuthorizer) Token() (*oauth2.Token, error) {
+	a.calls++
+	return &oauth2.Token{
+		AccessToken: "access-token",
+		TokenType:   "Bearer",
+		Expiry:      time.Now().Add(a.expiry),
+	}, nil
+}
+
+type contextAuthorizer struct {
+	countingAuthorizer
+}
+
+func (a *contextAuthorizer) TokenWithContext(ctx context.Context) (*oauth2.Token, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return a.Token()
+}
+
+func TestCachedAuthorizer(t *testing.T) {
+	src := &countingAuthorizer{expiry: time.Hour}
+	a := auth.NewCachedAuthorizer(src)
+	for i := 0; i < 5; i++ {
+		if _, err := a.Token(); err != nil {
+			t.Fatalf("CachedAuthorizer.Token(): %v", err)
+		}
+	}
+	if src.calls != 1 {
+		t.Fatalf("CachedAuthorizer.Token(): expected 1 call to source, got %d", src.calls)
+	}
+}
+
+func TestCachedAuthorizer_refreshJitter(t *testing.T) {
+	// A token expiring within the jitter window must always be refreshed
+	src := &countingAuthorizer{expiry: 30 * time.Second}
+	a := &auth.CachedAuthorizer{
+		Source:        src,
+		RefreshJitter: time.Hour,
+		Rand:          rand.New(rand.NewSource(1)),
+	}
+	for i := 0; i < 5; i++ {
+		if _, err := a.Token(); err != nil {
+			t.Fatalf("CachedAuthorizer.Token(): %v", err)
+		}
+	}
+	if src.calls < 2 {
+		t.Fatalf("CachedAuthorizer.Token(): expected token to be refreshed ahead of expiry, got %d calls to source", src.calls)
+	}
+
+	// Identically seeded authorizers should make identical refresh decisions
+	results := make([]int, 2)
+	for n := range results {
+		src := &countingAuthorizer{expiry: 30 * time.Minute}
+		a := &auth.CachedAuthorizer{
+			Source:        src,
+			RefreshJitter: time.Hour,
+			Rand:          rand.New(rand.NewSource(42)),
+		}
+		for i := 0; i < 5; i++ {
+			if _, err := a.Token(); err != nil {
+				t.Fatalf("CachedAuthorizer.Token(): %v", err)
+			}
+		}
+		results[n] = src.calls
+	}
+	if results[0] != results[1] {
+		t.Fatalf("CachedAuthorizer.Token(): expected deterministic refresh with seeded source, got %d and %d calls", results[0], results[1])
+	}
+}
+
+func TestCachedAuthorizer_LastTokenFromCache(t *testing.T) {
+	src := &countingAuthorizer{expiry: time.Hour}
+	a, ok := auth.NewCachedAuthorizer(src).(auth.CacheAwareAuthorizer)
+	if !ok {
+		t.Fatal("NewCachedAuthorizer(): expected a CacheAwareAuthorizer")
+	}
+	if _, err := a.Token(); err != nil {
+		t.Fatalf("CachedAuthorizer.Token(): %v", err)
+	}
+	if a.LastTokenFromCache() {
+		t.Fatal("CachedAuthorizer.LastTokenFromCache(): expected first token to be freshly acquired")
+	}
+	if _, err := a.Token(); err != nil {
+		t.Fatalf("CachedAuthorizer.Token(): %v", err)
+	}
+	if !a.LastTokenFromCache() {
+		t.Fatal("CachedAuthorizer.LastTokenFromCache(): expected second token to be served from cache")
+	}
+}
+
+func TestCachedAuthorizer_InvalidateToken(t *testing.T) {
+	src := &countingAuthorizer{expiry: time.Hour}
+	a, ok := auth.NewCachedAuthorizer(src).(auth.RefreshableAuthorizer)
+	if !ok {
+		t.Fatal("NewCachedAuthorizer(): expected a RefreshableAuthorizer")
+	}
+	if _, err := a.Token(); err != nil {
+		t.Fatalf("CachedAuthorizer.Token(): %v", err)
+	}
+	a.InvalidateToken()
+	if _, err := a.Token(); err != nil {
+		t.Fatalf("CachedAuthorizer.Token(): %v", err)
+	}
+	if src.calls != 2 {
+		t.Fatalf("CachedAuthorizer.Token(): expected 2 calls to source after invalidation, got %d", src.calls)
+	}
+}
+
+func TestCachedAuthorizer_TokenWithContext(t *testing.T) {
+	src := &contextAuthorizer{countingAuthorizer{expiry: time.Hour}}
+	a, ok := auth.NewCachedAuthorizer(src).(auth.ContextAuthorizer)
+	if !ok {
+		t.Fatal("NewCachedAuthorizer(): expected a ContextAuthorizer")
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := a.TokenWithContext(ctx); err != context.Canceled {
+		t.Fatalf("CachedAuthorizer.TokenWithContext(): expected context.Canceled, got %v", err)
+	}
+	if src.calls != 0 {
+		t.Fatalf("CachedAuthorizer.TokenWithContext(): expected no tokens to be acquired, got %d", src.calls)
+	}
+
+	if _, err := auth.TokenWithContext(context.Background(), a); err != nil {
+		t.Fatalf("auth.TokenWithContext(): %v", err)
+	}
+	if src.calls != 1 {
+		t.Fatalf("auth.TokenWithContext(): expected 1 call to source, got %d", src.calls)
+	}
+}
+
+type fixedExpiryAuthorizer struct {
+	calls  int
+	expiry time.Time
+}
+
+func (a *fixedExpiryAuthorizer) Token() (*oauth2.Token, error) {
+	a.calls++
+	return &oauth2.Token{
+		AccessToken: "access-token",
+		TokenType:   "Bearer",
+		Expiry:      a.expiry,
+	}, nil
+}
+
+func TestCachedAuthorizer_Clock(t *testing.T) {
+	expiry := time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)
+	src := &fixedExpiryAuthorizer{expiry: expiry}
+	now := expiry.Add(-time.Hour)
+	a := &auth.CachedAuthorizer{
+		Source: src,
+		Clock:  func() time.Time { return now },
+	}
+
+	if _, err := a.Token(); err != nil {
+		t.Fatalf("CachedAuthorizer.Token(): %v", err)
+	}
+
+	// Without jitter, the token is refreshed exactly 10 seconds ahead of its expiry
+	now = expiry.Add(-10*time.Second - time.Nanosecond)
+	if _, err := a.Token(); err != nil {
+		t.Fatalf("CachedAuthorizer.Token(): %v", err)
+	}
+	if src.calls != 1 {
+		t.Fatalf("CachedAuthorizer.Token(): expected token to be cached until the expiry boundary, got %d calls to source", src.calls)
+	}
+	now = expiry.Add(-10 * time.Second)
+	if _, err := a.Token(); err != nil {
+		t.Fatalf("CachedAuthorizer.Token(): %v", err)
+	}
+	if src.calls != 2 {
+		t.Fatalf("CachedAuthorizer.Token(): expected token to be refreshed at the expiry boundary, got %d calls to source", src.calls)
+	}
+}
+
+func TestCachedAuthorizer_Observer(t *testing.T) {
+	type observation struct {
+		fromCache bool
+		err       error
+	}
+	var observations []observation
+	src := &contextAuthorizer{countingAuthorizer{expiry: time.Hour}}
+	a := &auth.CachedAuthorizer{
+		Source: src,
+		Observer: auth.ObserverFunc(func(duration time.Duration, fromCache bool, err error) {
+			if duration < 0 {
+				t.Errorf("Observer.OnTokenAcquired(): negative duration: %s", duration)
+			}
+			observations = append(observations, observation{fromCache, err})
+		}),
+	}
+
+	for i := 0; i < 2; i++ {
+		if _, err := a.Token(); err != nil {
+			t.Fatalf("CachedAuthorizer.Token(): %v", err)
+		}
+	}
+	a.InvalidateToken()
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := a.TokenWithContext(ctx); err == nil {
+		t.Fatal("CachedAuthorizer.TokenWithContext(): expected error with cancelled context")
+	}
+
+	if len(observations) != 3 {
+		t.Fatalf("Observer.OnTokenAcquired(): expected 3 observations, got %d", len(observations))
+	}
+	if o := observations[0]; o.fromCache || o.err != nil {
+		t.Fatalf("Observer.OnTokenAcquired(): expected first token to be acquired from source, got %+v", o)
+	}
+	if o := observations[1]; !o.fromCache || o.err != nil {
+		t.Fatalf("Observer.OnTokenAcquired(): expected second token to be served from cache, got %+v", o)
+	}
+	if o := observations[2]; o.fromCache || o.err == nil {
+		t.Fatalf("Observer.OnTokenAcquired(): expected failure to be observed, got %+v", o)
+	}
+}
+
+func TestCachedAuthorizer_OnTokenRefreshed(t *testing.T) {
+	var refreshed []*oauth2.Token
+	src := &countingAuthorizer{expiry: time.Hour}
+	a := &auth.CachedAuthorizer{
+		Source: src,
+		OnTokenRefreshed: func(token *oauth2.Token) {
+			refreshed = append(refreshed, token)
+		},
+	}
+
+	var tokens []*oauth2.Token
+	for i := 0; i < 3; i++ {
+		token, err := a.Token()
+		if err != nil {
+			t.Fatalf("CachedAuthorizer.Token(): %v", err)
+		}
+		tokens = append(tokens, token)
+	}
+	a.InvalidateToken()
+	token, err := a.Token()
+	if err != nil {
+		t.Fatalf("CachedAuthorizer.Token(): %v", err)
+	}
+	tokens = append(tokens, token)
+
+	if len(refreshed) != 2 {
+		t.Fatalf("OnTokenRefreshed(): expected 2 calls, got %d", len(refreshed))
+	}
+	if refreshed[0] != tokens[0] || refreshed[1] != tokens[3] {
+		t.Fatal("OnTokenRefreshed(): expected to be called with the newly acquired tokens")
+	}
+}
+
+type tenantAuthorizer struct {
+	countingAuthorizer
+	tenantId string
+}
+
+func (a *tenantAuthorizer) WithTenant(tenantId string) (auth.Authorizer, error) {
+	return &tenantAuthorizer{countingAuthorizer: a.countingAuthorizer, tenantId: tenantId}, nil
+}
+
+func TestCachedAuthorizer_WithTenant(t *testing.T) {
+	a := &auth.CachedAuthorizer{
+		Source:        &tenantAuthorizer{countingAuthorizer: countingAuthorizer{expiry: time.Hour}},
+		RefreshJitter: time.Minute,
+		Rand:          rand.New(rand.NewSource(1)),
+	}
+	other, err := a.WithTenant("22222222-2222-2222-2222-222222222222")
+	if err != nil {
+		t.Fatalf("CachedAuthorizer.WithTenant(): %v", err)
+	}
+	c, ok := other.(*auth.CachedAuthorizer)
+	if !ok {
+		t.Fatalf("CachedAuthorizer.WithTenant(): expected a *CachedAuthorizer, got %T", other)
+	}
+	if src, ok := c.Source.(*tenantAuthorizer); !ok || src.tenantId != "22222222-2222-2222-2222-222222222222" {
+		t.Fatal("CachedAuthorizer.WithTenant(): expected the source to target the specified tenant")
+	}
+	if c.RefreshJitter != a.RefreshJitter {
+		t.Fatalf("CachedAuthorizer.WithTenant(): expected RefreshJitter %s, got %s", a.RefreshJitter, c.RefreshJitter)
+	}
+	if c.Rand != nil {
+		t.Fatal("CachedAuthorizer.WithTenant(): expected Rand not to be shared with the derived authorizer")
+	}
+}
@@ -3,6 +3,7 @@ package auth
 import (
 	"encoding/base64"
 	"encoding/json"
+	"errors"
 	"strings"
 
 	"golang.org/x/oauth2"
@@ -32,6 +33,10 @@ func ParseClaims(token *oauth2.Token) (claims Claims, err error) {
 		return
 	}
 	jwt := strings.Split(token.AccessToken, ".")
+	if len(jwt) != 3 {
+		err = errors.New("access token is not a JWT")
+		return
+	}
 	payload, err := base64.RawURLEncoding.DecodeString(jwt[1])
 	if err != nil {
 		return
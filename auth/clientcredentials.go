@@ -8,8 +8,10 @@ import (
 	"crypto/rsa"
 	"crypto/sha1"
 	"crypto/sha256"
+	"crypto/tls"
 	"crypto/x509"
 	"encoding/base64"
+	"encoding/hex"
 	"encoding/json"
 	"encoding/pem"
 	"errors"
@@ -18,6 +20,7 @@ import (
 	"io/ioutil"
 	"net/http"
 	"net/url"
+	"reflect"
 	"strconv"
 	"strings"
 	"time"
@@ -73,6 +76,78 @@ type ClientCredentialsConfig struct {
 	// request.  If empty, the value of TokenURL is used as the
 	// intended audience.
 	Audience string
+
+	// AssertionClaims is optionally called with the claims of each JWT assertion before it is signed, when
+	// authenticating with a client certificate, and can add claims or override non-reserved claims such as nbf. The
+	// reserved iss, sub, aud, exp and jti claims cannot be changed, and an error is returned if they are; specify
+	// Audience to use a custom aud claim.
+	AssertionClaims func(claims map[string]interface{})
+
+	// TLSConfig optionally specifies the TLS configuration used when requesting tokens, e.g. to trust a private
+	// certificate authority. When nil, the default configuration is used.
+	TLSConfig *tls.Config
+
+	// RetryPolicy optionally specifies how failed token requests are retried. When nil, requests are not retried.
+	RetryPolicy *RetryPolicy
+}
+
+// withTenant returns a copy of the config with the TokenURL recomputed for the specified tenant.
+func (c *ClientCredentialsConfig) withTenant(tenantId string) (*ClientCredentialsConfig, error) {
+	if err := validateClientCredentialsTenant(tenantId); err != nil {
+		return nil, err
+	}
+	u, err := url.Parse(c.TokenURL)
+	if err != nil {
+		return nil, fmt.Errorf("could not parse TokenURL %q: %v", c.TokenURL, err)
+	}
+	// The tenant precedes the oauth2 segment, or the policy segment for B2C authorities
+	segments := strings.Split(strings.TrimPrefix(u.Path, "/"), "/")
+	if len(segments) < 3 || (segments[1] != "oauth2" && (len(segments) < 4 || segments[2] != "oauth2")) {
+		return nil, fmt.Errorf("could not determine tenant from TokenURL %q", c.TokenURL)
+	}
+	segments[0] = tenantId
+	u.Path = "/" + strings.Join(segments, "/")
+
+	conf := *c
+	conf.TokenURL = u.String()
+	if conf.Audience == c.TokenURL {
+		conf.Audience = conf.TokenURL
+	}
+	return &conf, nil
+}
+
+// certificate parses the configured Certificate, which may be PEM or DER encoded
+func (c *ClientCredentialsConfig) certificate() (*x509.Certificate, error) {
+	crt := c.Certificate
+	if der, _ := pem.Decode(c.Certificate); der != nil {
+		crt = der.Bytes
+	}
+	return x509.ParseCertificate(crt)
+}
+
+// CertificateThumbprint describes the thumbprints of a client certificate, which can be compared with those of the
+// certificates registered for an application.
+type CertificateThumbprint struct {
+	// SHA1 is the hex encoded SHA-1 thumbprint, as displayed in the Azure Portal and returned by the API as the
+	// customKeyIdentifier of a key credential
+	SHA1 string
+
+	// SHA256 is the hex encoded SHA-256 thumbprint
+	SHA256 string
+
+	// X5t is the base64url encoded SHA-1 thumbprint, which is sent to Azure Active Directory to identify the
+	// certificate used to sign client assertions
+	X5t string
+}
+
+func newCertificateThumbprint(cert *x509.Certificate) CertificateThumbprint {
+	s1 := sha1.Sum(cert.Raw)
+	s256 := sha256.Sum256(cert.Raw)
+	return CertificateThumbprint{
+		SHA1:   strings.ToUpper(hex.EncodeToString(s1[:])),
+		SHA256: strings.ToUpper(hex.EncodeToString(s256[:])),
+		X5t:    base64.URLEncoding.EncodeToString(s1[:]),
+	}
 }
 
 // TokenSource provides a source for obtaining access tokens using clientAssertionAuthorizer or clientSecretAuthorizer.
@@ -118,9 +193,42 @@ func (c *clientAssertionTokenClaims) encode() (string, error) {
 	return base64.RawURLEncoding.EncodeToString(b), nil
 }
 
+// reservedAssertionClaims are the claims of a client assertion which cannot be changed by AssertionClaims
+var reservedAssertionClaims = []string{"iss", "sub", "aud", "exp", "jti"}
+
+// encodeCustom encodes the claims after applying customize, ensuring that none of the reserved claims were changed
+func (c *clientAssertionTokenClaims) encodeCustom(customize func(map[string]interface{})) (string, error) {
+	b, err := json.Marshal(c)
+	if err != nil {
+		return "", err
+	}
+	claims := make(map[string]interface{})
+	if err := json.Unmarshal(b, &claims); err != nil {
+		return "", err
+	}
+	reserved := make(map[string]interface{}, len(reservedAssertionClaims))
+	for _, k := range reservedAssertionClaims {
+		reserved[k] = claims[k]
+	}
+
+	customize(claims)
+
+	for _, k := range reservedAssertionClaims {
+		if v, ok := claims[k]; !ok || !reflect.DeepEqual(v, reserved[k]) {
+			return "", fmt.Errorf("reserved claim %q cannot be changed by AssertionClaims", k)
+		}
+	}
+
+	if b, err = json.Marshal(claims); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
 type clientAssertionToken struct {
-	header clientAssertionTokenHeader
-	claims clientAssertionTokenClaims
+	header    clientAssertionTokenHeader
+	claims    clientAssertionTokenClaims
+	customize func(map[string]interface{})
 }
 
 func (c *clientAssertionToken) encode(key *rsa.PrivateKey) (string, error) {
@@ -149,7 +257,12 @@ func (c *clientAssertionToken) encode(key *rsa.PrivateKey) (string, error) {
 	}
 
 	// encode the claims
-	cs, err := c.claims.encode()
+	var cs string
+	if c.customize != nil {
+		cs, err = c.claims.encodeCustom(c.customize)
+	} else {
+		cs, err = c.claims.encode()
+	}
 	if err != nil {
 		return "", err
 	}
@@ -169,25 +282,47 @@ type clientAssertionAuthorizer struct {
 	conf *ClientCredentialsConfig
 }
 
-func (a clientAssertionAuthorizer) Token() (*oauth2.Token, error) {
-	crt := a.conf.Certificate
-	if der, _ := pem.Decode(a.conf.Certificate); der != nil {
-		crt = der.Bytes
+// WithTenant returns a clientAssertionAuthorizer using the same client certificate to authenticate in the specified tenant.
+func (a clientAssertionAuthorizer) WithTenant(tenantId string) (Authorizer, error) {
+	conf, err := a.conf.withTenant(tenantId)
+	if err != nil {
+		return nil, fmt.Errorf("clientAssertionAuthorizer: %v", err)
 	}
+	return &clientAssertionAuthorizer{a.ctx, conf}, nil
+}
 
-	cert, err := x509.ParseCertificate(crt)
+// CertificateThumbprint returns the thumbprints of the client certificate used to authenticate.
+func (a clientAssertionAuthorizer) CertificateThumbprint() (*CertificateThumbprint, error) {
+	cert, err := a.conf.certificate()
 	if err != nil {
 		return nil, fmt.Errorf("clientAssertionAuthorizer: cannot parse certificate: %v", err)
 	}
+	thumbprint := newCertificateThumbprint(cert)
+	return &thumbprint, nil
+}
 
-	keySig := sha1.Sum(cert.Raw)
-	keyId := base64.URLEncoding.EncodeToString(keySig[:])
+func (a clientAssertionAuthorizer) Token() (*oauth2.Token, error) {
+	return a.TokenWithContext(authorizerContext(a.ctx))
+}
+
+func (a clientAssertionAuthorizer) TokenWithContext(ctx context.Context) (*oauth2.Token, error) {
+	cert, err := a.conf.certificate()
+	if err != nil {
+		return nil, fmt.Errorf("clientAssertionAuthorizer: cannot parse certificate: %v", err)
+	}
+
+	keyId := newCertificateThumbprint(cert).X5t
 
 	privKey, err := parseKey(a.conf.PrivateKey)
 	if err != nil {
 		return nil, fmt.Errorf("clientAssertionAuthorizer: cannot parse private key: %v", err)
 	}
 
+	audience := a.conf.Audience
+	if audience == "" {
+		audience = a.conf.TokenURL
+	}
+
 	t := clientAssertionToken{
 		header: clientAssertionTokenHeader{
 			Algorithm: "RS256",
@@ -195,14 +330,15 @@ func (a clientAssertionAuthorizer) Token() (*oauth2.Token, error) {
 			KeyId:     keyId,
 		},
 		claims: clientAssertionTokenClaims{
-			Audience: a.conf.TokenURL,
+			Audience: audience,
 			Issuer:   a.conf.ClientID,
 			Subject:  a.conf.ClientID,
 		},
+		customize: a.conf.AssertionClaims,
 	}
 	assertion, err := t.encode(privKey)
 	if err != nil {
-		return nil, fmt.Errorf("clientAssertionAuthorizer: failed to encode and sign JWT assertion")
+		return nil, fmt.Errorf("clientAssertionAuthorizer: failed to encode and sign JWT assertion: %v", err)
 	}
 
 	v := url.Values{
@@ -217,7 +353,7 @@ func (a clientAssertionAuthorizer) Token() (*oauth2.Token, error) {
 		v["scope"] = []string{strings.Join(a.conf.Scopes, " ")}
 	}
 
-	return clientCredentialsToken(a.ctx, a.conf.TokenURL, &v)
+	return clientCredentialsToken(ctx, a.conf.TokenURL, a.conf.TLSConfig, a.conf.RetryPolicy, &v)
 }
 
 // parseKey returns an rsa.PrivateKey containing the provided binary key data.
@@ -246,7 +382,20 @@ type clientSecretAuthorizer struct {
 	conf *ClientCredentialsConfig
 }
 
+// WithTenant returns a clientSecretAuthorizer using the same client secret to authenticate in the specified tenant.
+func (a clientSecretAuthorizer) WithTenant(tenantId string) (Authorizer, error) {
+	conf, err := a.conf.withTenant(tenantId)
+	if err != nil {
+		return nil, fmt.Errorf("clientSecretAuthorizer: %v", err)
+	}
+	return &clientSecretAuthorizer{a.ctx, conf}, nil
+}
+
 func (a clientSecretAuthorizer) Token() (*oauth2.Token, error) {
+	return a.TokenWithContext(authorizerContext(a.ctx))
+}
+
+func (a clientSecretAuthorizer) TokenWithContext(ctx context.Context) (*oauth2.Token, error) {
 	v := url.Values{
 		"client_id":     {a.conf.ClientID},
 		"client_secret": {a.conf.ClientSecret},
@@ -258,10 +407,10 @@ func (a clientSecretAuthorizer) Token() (*oauth2.Token, error) {
 		v["scope"] = []string{strings.Join(a.conf.Scopes, " ")}
 	}
 
-	return clientCredentialsToken(a.ctx, a.conf.TokenURL, &v)
+	return clientCredentialsToken(ctx, a.conf.TokenURL, a.conf.TLSConfig, a.conf.RetryPolicy, &v)
 }
 
-func clientCredentialsToken(ctx context.Context, endpoint string, params *url.Values) (*oauth2.Token, error) {
+func clientCredentialsToken(ctx context.Context, endpoint string, tlsConfig *tls.Config, retryPolicy *RetryPolicy, params *url.Values) (*oauth2.Token, error) {
 	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewBuffer([]byte(params.Encode())))
 	if err != nil {
 		return nil, fmt.Errorf("clientCredentialsToken: failed to build request")
@@ -270,7 +419,7 @@ func clientCredentialsToken(ctx context.Context, endpoint string, params *url.Va
 	req.Header.Set("Accept", "application/json")
 	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
 
-	resp, err := http.DefaultClient.Do(req)
+	resp, err := doWithRetries(httpClient(tlsConfig, 0), req, retryPolicy)
 	if err != nil {
 		return nil, fmt.Errorf("clientCredentialsToken: cannot request token: %v", err)
 	}
@@ -282,7 +431,7 @@ func clientCredentialsToken(ctx context.Context, endpoint string, params *url.Va
 	}
 
 	if c := resp.StatusCode; c < 200 || c > 299 {
-		return nil, fmt.Errorf("clientCredentialsToken: received HTTP status %d with response: %s", resp.StatusCode, body)
+		return nil, credentialError(body, fmt.Errorf("clientCredentialsToken: received HTTP status %d with response: %s", resp.StatusCode, body))
 	}
 
 	// clientCredentialsToken response can arrive with numeric values as integers or strings :(
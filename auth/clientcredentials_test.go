@@ -0,0 +1,406 @@
+package auth_test
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha1"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/manicminer/hamilton/auth"
+	"github.com/manicminer/hamilton/environments"
+)
+
+func TestClientSecretAuthorizer_WithTenant(t *testing.T) {
+	var paths []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		paths = append(paths, r.URL.Path)
+		if err := r.ParseForm(); err != nil {
+			t.Fatalf("ParseForm(): %v", err)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprintf(w, `{"access_token":"token-%s","token_type":"Bearer","expires_in":3600}`, r.PostForm.Get("client_secret"))
+	}))
+	defer server.Close()
+
+	environment := environments.Global
+	environment.AzureADEndpoint = environments.AzureADEndpoint(server.URL)
+	a, err := auth.NewClientSecretAuthorizer(context.Background(), environment, auth.MsGraph, auth.TokenVersion2, "11111111-1111-1111-1111-111111111111", "00000000-0000-0000-0000-000000000000", "secret")
+	if err != nil {
+		t.Fatalf("NewClientSecretAuthorizer(): %v", err)
+	}
+	ta, ok := a.(auth.TenantAuthorizer)
+	if !ok {
+		t.Fatal("NewClientSecretAuthorizer(): expected a TenantAuthorizer")
+	}
+	other, err := ta.WithTenant("22222222-2222-2222-2222-222222222222")
+	if err != nil {
+		t.Fatalf("WithTenant(): %v", err)
+	}
+
+	for _, authorizer := range []auth.Authorizer{a, other} {
+		token, err := authorizer.Token()
+		if err != nil {
+			t.Fatalf("Token(): %v", err)
+		}
+		if token.AccessToken != "token-secret" {
+			t.Fatalf("Token(): expected token %q, got %q", "token-secret", token.AccessToken)
+		}
+	}
+
+	expected := []string{
+		"/11111111-1111-1111-1111-111111111111/oauth2/v2.0/token",
+		"/22222222-2222-2222-2222-222222222222/oauth2/v2.0/token",
+	}
+	if len(paths) != len(expected) || paths[0] != expected[0] || paths[1] != expected[1] {
+		t.Fatalf("WithTenant(): expected token requests to %v, got %v", expected, paths)
+	}
+
+	if _, err := ta.WithTenant(""); err == nil {
+		t.Fatal("WithTenant(): expected an error for an empty tenant ID")
+	}
+}
+
+func TestClientSecretAuthorizer_TLSConfig(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"access_token":"token","token_type":"Bearer","expires_in":3600}`)
+	}))
+	defer server.Close()
+
+	environment := environments.Global
+	environment.AzureADEndpoint = environments.AzureADEndpoint(server.URL)
+	roots := x509.NewCertPool()
+	roots.AddCert(server.Certificate())
+
+	for _, c := range []struct {
+		name      string
+		tlsConfig *tls.Config
+		valid     bool
+	}{
+		{name: "default", tlsConfig: nil, valid: false},
+		{name: "rootCAs", tlsConfig: &tls.Config{RootCAs: roots}, valid: true},
+		{name: "insecure", tlsConfig: auth.DangerouslyInsecureTLSConfig(), valid: true},
+	} {
+		config := auth.Config{
+			Environment:            environment,
+			TenantID:               "11111111-1111-1111-1111-111111111111",
+			ClientID:               "00000000-0000-0000-0000-000000000000",
+			ClientSecret:           "secret",
+			EnableClientSecretAuth: true,
+			TLSConfig:              c.tlsConfig,
+		}
+		a, err := config.NewAuthorizer(context.Background(), auth.MsGraph)
+		if err != nil {
+			t.Fatalf("%s: NewAuthorizer(): %v", c.name, err)
+		}
+		_, err = a.Token()
+		if c.valid && err != nil {
+			t.Fatalf("%s: Token(): %v", c.name, err)
+		}
+		if !c.valid && err == nil {
+			t.Fatalf("%s: Token(): expected certificate verification error", c.name)
+		}
+	}
+}
+
+func TestClientSecretAuthorizer_CertificateThumbprint(t *testing.T) {
+	a, err := auth.NewClientSecretAuthorizer(context.Background(), environments.Global, auth.MsGraph, auth.TokenVersion2, "11111111-1111-1111-1111-111111111111", "00000000-0000-0000-0000-000000000000", "secret")
+	if err != nil {
+		t.Fatalf("NewClientSecretAuthorizer(): %v", err)
+	}
+	if _, err := a.(auth.CertificateAuthorizer).CertificateThumbprint(); err == nil {
+		t.Fatal("CertificateThumbprint(): expected error for client secret authorizer")
+	}
+}
+
+func TestClientAssertionAuthorizer_CertificateThumbprint(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "hamilton-test"},
+		NotBefore:    time.Now(),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var kid string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseForm(); err != nil {
+			t.Fatalf("ParseForm(): %v", err)
+		}
+		header, err := base64.RawURLEncoding.DecodeString(strings.Split(r.PostForm.Get("client_assertion"), ".")[0])
+		if err != nil {
+			t.Fatalf("decoding assertion header: %v", err)
+		}
+		var h struct {
+			KeyId string `json:"kid"`
+		}
+		if err := json.Unmarshal(header, &h); err != nil {
+			t.Fatalf("json.Unmarshal(): %v", err)
+		}
+		kid = h.KeyId
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"access_token":"token","token_type":"Bearer","expires_in":3600}`)
+	}))
+	defer server.Close()
+
+	conf := auth.ClientCredentialsConfig{
+		ClientID:    "00000000-0000-0000-0000-000000000000",
+		PrivateKey:  x509.MarshalPKCS1PrivateKey(key),
+		Certificate: der,
+		Scopes:      []string{"https://graph.microsoft.com/.default"},
+		TokenURL:    auth.TokenEndpoint(environments.AzureADEndpoint(server.URL), "11111111-1111-1111-1111-111111111111", auth.TokenVersion2),
+	}
+	a := conf.TokenSource(context.Background(), auth.ClientCredentialsAssertionType)
+
+	ca, ok := a.(auth.CertificateAuthorizer)
+	if !ok {
+		t.Fatal("TokenSource(): expected a CertificateAuthorizer")
+	}
+	thumbprint, err := ca.CertificateThumbprint()
+	if err != nil {
+		t.Fatalf("CertificateThumbprint(): %v", err)
+	}
+	sum := sha1.Sum(der)
+	if expected := strings.ToUpper(hex.EncodeToString(sum[:])); thumbprint.SHA1 != expected {
+		t.Fatalf("CertificateThumbprint(): expected SHA1 %q, got %q", expected, thumbprint.SHA1)
+	}
+	if len(thumbprint.SHA256) != 64 {
+		t.Fatalf("CertificateThumbprint(): unexpected SHA256 %q", thumbprint.SHA256)
+	}
+
+	if _, err := a.Token(); err != nil {
+		t.Fatalf("Token(): %v", err)
+	}
+	if kid != thumbprint.X5t {
+		t.Fatalf("CertificateThumbprint(): expected X5t %q to match the assertion key ID %q", thumbprint.X5t, kid)
+	}
+}
+
+func TestClientAssertionAuthorizer_AssertionClaims(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "hamilton-test"},
+		NotBefore:    time.Now(),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var claims map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseForm(); err != nil {
+			t.Fatalf("ParseForm(): %v", err)
+		}
+		payload, err := base64.RawURLEncoding.DecodeString(strings.Split(r.PostForm.Get("client_assertion"), ".")[1])
+		if err != nil {
+			t.Fatalf("decoding assertion claims: %v", err)
+		}
+		claims = nil
+		if err := json.Unmarshal(payload, &claims); err != nil {
+			t.Fatalf("json.Unmarshal(): %v", err)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"access_token":"token","token_type":"Bearer","expires_in":3600}`)
+	}))
+	defer server.Close()
+
+	conf := auth.ClientCredentialsConfig{
+		ClientID:    "00000000-0000-0000-0000-000000000000",
+		PrivateKey:  x509.MarshalPKCS1PrivateKey(key),
+		Certificate: der,
+		Scopes:      []string{"https://graph.microsoft.com/.default"},
+		TokenURL:    auth.TokenEndpoint(environments.AzureADEndpoint(server.URL), "11111111-1111-1111-1111-111111111111", auth.TokenVersion2),
+		Audience:    "https://sts.contoso.com/token",
+		AssertionClaims: func(claims map[string]interface{}) {
+			claims["nbf"] = 1700000000
+			claims["xms_partner"] = "contoso"
+		},
+	}
+	if _, err := conf.TokenSource(context.Background(), auth.ClientCredentialsAssertionType).Token(); err != nil {
+		t.Fatalf("Token(): %v", err)
+	}
+	if claims["aud"] != "https://sts.contoso.com/token" || claims["nbf"] != float64(1700000000) || claims["xms_partner"] != "contoso" || claims["iss"] != conf.ClientID || claims["jti"] == "" {
+		t.Fatalf("Token(): unexpected assertion claims %v", claims)
+	}
+
+	for _, reserved := range []string{"iss", "sub", "aud", "exp", "jti"} {
+		claims = nil
+		conf.AssertionClaims = func(claims map[string]interface{}) {
+			claims[reserved] = "overridden"
+		}
+		_, err := conf.TokenSource(context.Background(), auth.ClientCredentialsAssertionType).Token()
+		if err == nil || !strings.Contains(err.Error(), fmt.Sprintf("reserved claim %q", reserved)) {
+			t.Fatalf("Token(): expected error overriding reserved claim %q, got: %v", reserved, err)
+		}
+		if claims != nil {
+			t.Fatalf("Token(): expected no token request when overriding reserved claim %q", reserved)
+		}
+	}
+}
+
+func TestClientSecretAuthorizer_CredentialErrors(t *testing.T) {
+	for _, c := range []struct {
+		name     string
+		response string
+		expected error
+		code     int
+	}{
+		{
+			name:     "invalidSecret",
+			response: `{"error":"invalid_client","error_description":"AADSTS7000215: Invalid client secret provided.","error_codes":[7000215]}`,
+			expected: auth.ErrInvalidClientSecret,
+			code:     7000215,
+		},
+		{
+			name:     "expiredSecret",
+			response: `{"error":"invalid_client","error_description":"AADSTS7000222: The provided client secret keys for app '00000000-0000-0000-0000-000000000000' are expired."}`,
+			expected: auth.ErrExpiredCredential,
+			code:     7000222,
+		},
+		{
+			name:     "applicationNotFound",
+			response: `{"error":"unauthorized_client","error_description":"AADSTS700016: Application with identifier '00000000-0000-0000-0000-000000000000' was not found in the directory.","error_codes":[700016]}`,
+			expected: auth.ErrApplicationNotFound,
+			code:     700016,
+		},
+		{
+			name:     "consentRequired",
+			response: `{"error":"invalid_grant","error_description":"AADSTS65001: The user or administrator has not consented to use the application.","error_codes":[65001]}`,
+			expected: auth.ErrConsentRequired,
+			code:     65001,
+		},
+		{
+			name:     "other",
+			response: `{"error":"invalid_request","error_description":"AADSTS90002: Tenant not found.","error_codes":[90002]}`,
+		},
+	} {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusUnauthorized)
+			fmt.Fprint(w, c.response)
+		}))
+
+		environment := environments.Global
+		environment.AzureADEndpoint = environments.AzureADEndpoint(server.URL)
+		a, err := auth.NewClientSecretAuthorizer(context.Background(), environment, auth.MsGraph, auth.TokenVersion2, "11111111-1111-1111-1111-111111111111", "00000000-0000-0000-0000-000000000000", "secret")
+		if err != nil {
+			t.Fatalf("%s: NewClientSecretAuthorizer(): %v", c.name, err)
+		}
+		_, err = a.Token()
+		server.Close()
+		if err == nil {
+			t.Fatalf("%s: Token(): expected error", c.name)
+		}
+
+		var credentialErr *auth.CredentialError
+		if c.expected == nil {
+			if errors.As(err, &credentialErr) {
+				t.Fatalf("%s: Token(): unexpected CredentialError: %v", c.name, err)
+			}
+			continue
+		}
+		if !errors.Is(err, c.expected) {
+			t.Fatalf("%s: Token(): expected error to be %v, got: %v", c.name, c.expected, err)
+		}
+		if !errors.As(err, &credentialErr) || credentialErr.Code != c.code {
+			t.Fatalf("%s: Token(): expected CredentialError with code %d, got: %v", c.name, c.code, err)
+		}
+		if unwrapped := errors.Unwrap(err); unwrapped == nil || !strings.Contains(unwrapped.Error(), fmt.Sprintf("AADSTS%d", c.code)) {
+			t.Fatalf("%s: Token(): expected original error to be wrapped, got: %v", c.name, unwrapped)
+		}
+	}
+}
+
+func TestClientSecretAuthorizer_EndpointPath(t *testing.T) {
+	var path string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path = r.URL.Path
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"access_token":"token","token_type":"Bearer","expires_in":3600}`)
+	}))
+	defer server.Close()
+
+	environment := environments.Global
+	environment.AzureADEndpoint = environments.AzureADEndpoint(server.URL + "/recordings/aad/")
+	a, err := auth.NewClientSecretAuthorizer(context.Background(), environment, auth.MsGraph, auth.TokenVersion2, "11111111-1111-1111-1111-111111111111", "00000000-0000-0000-0000-000000000000", "secret")
+	if err != nil {
+		t.Fatalf("NewClientSecretAuthorizer(): %v", err)
+	}
+	if _, err := a.Token(); err != nil {
+		t.Fatalf("Token(): %v", err)
+	}
+	if expected := "/recordings/aad/11111111-1111-1111-1111-111111111111/oauth2/v2.0/token"; path != expected {
+		t.Fatalf("Token(): expected request to %q, got %q", expected, path)
+	}
+}
+
+func TestClientSecretAuthorizer_ForResource(t *testing.T) {
+	var scope, resource string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseForm(); err != nil {
+			t.Errorf("could not parse form: %v", err)
+		}
+		scope, resource = r.PostForm.Get("scope"), r.PostForm.Get("resource")
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"access_token":"token","token_type":"Bearer","expires_in":3600}`)
+	}))
+	defer server.Close()
+
+	environment := environments.Environment{AzureADEndpoint: environments.AzureADEndpoint(server.URL)}
+	const customApi = "api://11111111-1111-1111-1111-111111111111/"
+
+	a, err := auth.NewClientSecretAuthorizerForResource(context.Background(), environment, auth.TokenVersion2, "00000000-0000-0000-0000-000000000000", "00000000-0000-0000-0000-000000000000", "secret", customApi)
+	if err != nil {
+		t.Fatalf("NewClientSecretAuthorizerForResource(): %v", err)
+	}
+	if _, err := a.Token(); err != nil {
+		t.Fatalf("Token(): %v", err)
+	}
+	if expected := "api://11111111-1111-1111-1111-111111111111/.default"; scope != expected || resource != "" {
+		t.Fatalf("Token(): expected v2 token request with scope %q, got scope %q and resource %q", expected, scope, resource)
+	}
+
+	a, err = auth.NewClientSecretAuthorizerForResource(context.Background(), environment, auth.TokenVersion1, "00000000-0000-0000-0000-000000000000", "00000000-0000-0000-0000-000000000000", "secret", customApi)
+	if err != nil {
+		t.Fatalf("NewClientSecretAuthorizerForResource(): %v", err)
+	}
+	if _, err := a.Token(); err != nil {
+		t.Fatalf("Token(): %v", err)
+	}
+	if resource != customApi || scope != "" {
+		t.Fatalf("Token(): expected v1 token request for resource %q, got resource %q and scope %q", customApi, resource, scope)
+	}
+
+	for _, invalid := range []string{"", "example.com", "/relative", "https://"} {
+		if _, err := auth.NewClientSecretAuthorizerForResource(context.Background(), environment, auth.TokenVersion2, "00000000-0000-0000-0000-000000000000", "00000000-0000-0000-0000-000000000000", "secret", invalid); err == nil {
+			t.Errorf("NewClientSecretAuthorizerForResource(): expected error for invalid resource %q", invalid)
+		}
+	}
+}
@@ -1,6 +1,15 @@
 package auth
 
-import "github.com/manicminer/hamilton/environments"
+import (
+	"crypto/tls"
+	"fmt"
+	"net/url"
+	"regexp"
+
+	"golang.org/x/oauth2"
+
+	"github.com/manicminer/hamilton/environments"
+)
 
 type TokenVersion int
 
@@ -11,7 +20,9 @@ const (
 
 // Config sets up NewAuthorizer to return an Authorizer based on the provided configuration.
 type Config struct {
-	// Specifies the national cloud environment to use
+	// Specifies the national cloud environment to use. Token requests with client certificate or client secret
+	// authentication are sent to Environment.AzureADEndpoint, which can be overridden with a URL that includes a path
+	// prefix, e.g. to send requests via a recording proxy for testing.
 	Environment environments.Environment
 
 	// Version specifies the token version  to acquire from Microsoft Identity Platform.
@@ -24,9 +35,46 @@ type Config struct {
 	// Client ID for the application used to authenticate the connection
 	ClientID string
 
+	// B2CPolicy optionally specifies the name of an Azure AD B2C user flow or custom policy, e.g. `B2C_1_signin`, which
+	// is inserted into the token endpoint path for client certificate and client secret authentication. Environment
+	// should specify the B2C authority host as its AzureADEndpoint, e.g. `https://contoso.b2clogin.com`. Only v2 tokens
+	// are supported.
+	B2CPolicy string
+
+	// TokenURL optionally overrides the token endpoint for client certificate and client secret authentication, for
+	// authorities which do not use the standard `/{tenant}/oauth2[/v2.0]/token` path. Cannot be combined with B2CPolicy
+	// or used with NewAuthorizerForTenant, since the tenant is not substituted into the URL.
+	TokenURL string
+
+	// Resource optionally specifies the audience of acquired tokens, overriding the API passed to NewAuthorizer. This
+	// must be an absolute URL, e.g. `https://example.com` or `api://00000000-0000-0000-0000-000000000000` for a custom
+	// API. For v1 tokens the resource is requested as given, and for v2 tokens the `<resource>/.default` scope is
+	// requested. Used with client certificate, client secret and MSI authentication, and not supported with Azure CLI
+	// authentication.
+	Resource string
+
+	// Scopes specifies additional scopes to request alongside the default `.default` scope for the API, for example
+	// `offline_access` to obtain a refresh token. Only OpenID Connect scopes (`offline_access`, `openid`, `profile`
+	// and `email`) can be combined with the `.default` scope. Used for v2 tokens with client certificate or client
+	// secret authentication.
+	//
+	// App-only tokens always include every application permission granted to the application, so specific
+	// permissions cannot be requested. This includes resource-specific consent (RSC) permissions such as
+	// `ChannelMessage.Read.Group`, which are granted when a Teams app is installed in a team or chat, and are then
+	// included in tokens for Microsoft Graph acquired using the default scope.
+	Scopes []string
+
 	// Enables authentication using Azure CLI
 	EnableAzureCliToken bool
 
+	// Specifies the path to the Azure CLI executable. When not set, the AZURE_CLI_PATH environment variable is used if
+	// set, otherwise `az` is located using the PATH environment variable.
+	AzureCliPath string
+
+	// Specifies the minimum version of Azure CLI to support. Ignored when older than the minimum version supported by
+	// this package.
+	AzureCliMinimumVersion string
+
 	// Enables authentication using managed service identity.
 	EnableMsiAuth bool
 
@@ -45,9 +93,62 @@ type Config struct {
 	// Specifies the encryption password to unlock a client certificate
 	ClientCertPassword string
 
+	// ClientCertAssertionClaims optionally customizes the claims of the JWT assertions used for client certificate
+	// authentication. See ClientCredentialsConfig.AssertionClaims for details.
+	ClientCertAssertionClaims func(claims map[string]interface{})
+
 	// Enables client secret authentication using client credentials
 	EnableClientSecretAuth bool
 
 	// Specifies the password to authenticate with using client secret authentication
 	ClientSecret string
+
+	// Observer is optionally notified about token acquisition by the configured Authorizer, for recording metrics
+	Observer Observer
+
+	// OnTokenRefreshed is optionally called each time the configured Authorizer acquires a new token, rather than
+	// returning a cached token. See CachedAuthorizer.OnTokenRefreshed for details of when it is called.
+	OnTokenRefreshed func(*oauth2.Token)
+
+	// TLSConfig optionally specifies the TLS configuration used when requesting tokens with client certificate, client
+	// secret or MSI authentication, e.g. to trust a private certificate authority. Ignored for Azure CLI authentication.
+	TLSConfig *tls.Config
+
+	// RetryPolicy optionally specifies how failed token requests are retried with client certificate, client secret or
+	// MSI authentication. When nil, DefaultRetryPolicy() is used. To disable retries, specify a RetryPolicy with a
+	// MaxRetries of zero. Ignored for Azure CLI authentication.
+	RetryPolicy *RetryPolicy
+}
+
+// b2cPolicyRegex matches valid names of B2C user flows and custom policies
+var b2cPolicyRegex = regexp.MustCompile(`^[A-Za-z0-9_-]+$`)
+
+// tokenUrl returns the token endpoint used for client certificate and client secret authentication
+func (c *Config) tokenUrl() (string, error) {
+	var tokenUrl string
+	switch {
+	case c.TokenURL != "" && c.B2CPolicy != "":
+		return "", fmt.Errorf("TokenURL and B2CPolicy cannot both be specified")
+	case c.TokenURL != "":
+		tokenUrl = c.TokenURL
+	case c.B2CPolicy != "":
+		if !b2cPolicyRegex.MatchString(c.B2CPolicy) {
+			return "", fmt.Errorf("invalid B2CPolicy %q, must contain only letters, numbers, underscores and hyphens", c.B2CPolicy)
+		}
+		if c.Version != TokenVersion2 {
+			return "", fmt.Errorf("B2CPolicy is only supported for v2 tokens")
+		}
+		tokenUrl = B2CTokenEndpoint(c.Environment.AzureADEndpoint, c.TenantID, c.B2CPolicy)
+	default:
+		return TokenEndpoint(c.Environment.AzureADEndpoint, c.TenantID, c.Version), nil
+	}
+
+	u, err := url.Parse(tokenUrl)
+	if err != nil {
+		return "", fmt.Errorf("could not parse %q: %v", tokenUrl, err)
+	}
+	if (u.Scheme != "https" && u.Scheme != "http") || u.Host == "" {
+		return "", fmt.Errorf("%q is not an absolute HTTP(S) URL", tokenUrl)
+	}
+	return tokenUrl, nil
 }
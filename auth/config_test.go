@@ -0,0 +1,640 @@
+package auth_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+	_ "time/tzdata"
+
+	"github.com/manicminer/hamilton/auth"
+	"github.com/manicminer/hamilton/environments"
+)
+
+func testConfigNewAuthorizer(scopes []string) (auth.Authorizer, error) {
+	conf := auth.Config{
+		Environment:            environments.Global,
+		TenantID:               "00000000-0000-0000-0000-000000000000",
+		ClientID:               "00000000-0000-0000-0000-000000000000",
+		ClientSecret:           "secret",
+		EnableClientSecretAuth: true,
+		Scopes:                 scopes,
+	}
+	return conf.NewAuthorizer(context.Background(), auth.MsGraph)
+}
+
+func TestConfig_Scopes(t *testing.T) {
+	for _, scopes := range [][]string{
+		nil,
+		{"offline_access"},
+		{"offline_access", "openid", "profile"},
+		{"https://graph.microsoft.com/.default"},
+	} {
+		a, err := testConfigNewAuthorizer(scopes)
+		if err != nil {
+			t.Fatalf("Config.NewAuthorizer(): unexpected error for scopes %v: %v", scopes, err)
+		}
+		if a == nil {
+			t.Fatalf("Config.NewAuthorizer(): authorizer was nil for scopes %v", scopes)
+		}
+	}
+}
+
+func TestConfig_ScopesDefaultWithSpecific(t *testing.T) {
+	for _, scopes := range [][]string{
+		{"https://graph.microsoft.com/User.Read"},
+		{"offline_access", "User.Read"},
+	} {
+		if _, err := testConfigNewAuthorizer(scopes); err == nil {
+			t.Fatalf("Config.NewAuthorizer(): expected an error when combining .default with scopes %v", scopes)
+		}
+	}
+}
+
+func TestConfig_Resource(t *testing.T) {
+	var form url.Values
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseForm(); err != nil {
+			t.Errorf("could not parse form: %v", err)
+		}
+		form = r.PostForm
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"access_token":"token","token_type":"Bearer","expires_in":3600}`)
+	}))
+	defer server.Close()
+
+	environment := environments.Global
+	environment.AzureADEndpoint = environments.AzureADEndpoint(server.URL)
+	const customApi = "api://11111111-1111-1111-1111-111111111111"
+	conf := auth.Config{
+		Environment:            environment,
+		Version:                auth.TokenVersion2,
+		TenantID:               "00000000-0000-0000-0000-000000000000",
+		ClientID:               "00000000-0000-0000-0000-000000000000",
+		ClientSecret:           "secret",
+		EnableClientSecretAuth: true,
+		Resource:               customApi,
+		Scopes:                 []string{"offline_access"},
+	}
+
+	a, err := conf.NewAuthorizer(context.Background(), auth.MsGraph)
+	if err != nil {
+		t.Fatalf("NewAuthorizer(): %v", err)
+	}
+	if _, err := a.Token(); err != nil {
+		t.Fatalf("Token(): %v", err)
+	}
+	if expected := customApi + "/.default offline_access"; form.Get("scope") != expected || form.Get("resource") != "" {
+		t.Fatalf("Token(): expected v2 token request with scope %q, got scope %q and resource %q", expected, form.Get("scope"), form.Get("resource"))
+	}
+
+	a, err = conf.NewAuthorizerForVersion(context.Background(), auth.MsGraph, auth.TokenVersion1)
+	if err != nil {
+		t.Fatalf("NewAuthorizerForVersion(): %v", err)
+	}
+	if _, err := a.Token(); err != nil {
+		t.Fatalf("Token(): %v", err)
+	}
+	if form.Get("resource") != customApi || form.Get("scope") != "" {
+		t.Fatalf("Token(): expected v1 token request for resource %q, got resource %q and scope %q", customApi, form.Get("resource"), form.Get("scope"))
+	}
+
+	conf.Resource = "example.com"
+	if _, err := conf.NewAuthorizer(context.Background(), auth.MsGraph); err == nil {
+		t.Fatal("NewAuthorizer(): expected error for invalid resource")
+	}
+
+	conf = auth.Config{
+		Environment:         environment,
+		TenantID:            "00000000-0000-0000-0000-000000000000",
+		EnableAzureCliToken: true,
+		Resource:            customApi,
+	}
+	if _, err := conf.NewAuthorizer(context.Background(), auth.MsGraph); err == nil {
+		t.Fatal("NewAuthorizer(): expected error for Resource with Azure CLI authentication")
+	}
+}
+
+// testAzureCliStub writes a shell script which emulates the Azure CLI, reporting the specified version and running
+// the specified shell command for `az account` subcommands. Returns the path to the script.
+func testAzureCliStub(t *testing.T, version, account string) string {
+	if runtime.GOOS == "windows" {
+		t.Skip("test uses a shell script in place of the Azure CLI")
+	}
+	stub := filepath.Join(t.TempDir(), "az")
+	script := fmt.Sprintf(`#!/bin/sh
+case "$1" in
+  version) echo '{"azure-cli": "%s"}' ;;
+  account) %s ;;
+esac
+`, version, account)
+	if err := os.WriteFile(stub, []byte(script), 0700); err != nil {
+		t.Fatal(err)
+	}
+	return stub
+}
+
+func testConfigNewAzureCliAuthorizer(path, minimumVersion string) (auth.Authorizer, error) {
+	conf := auth.Config{
+		Environment:            environments.Global,
+		TenantID:               "00000000-0000-0000-0000-000000000000",
+		EnableAzureCliToken:    true,
+		AzureCliPath:           path,
+		AzureCliMinimumVersion: minimumVersion,
+	}
+	return conf.NewAuthorizer(context.Background(), auth.MsGraph)
+}
+
+func TestConfig_UnsupportedApi(t *testing.T) {
+	api := auth.Api(42)
+	conf := auth.Config{
+		Environment:            environments.Global,
+		TenantID:               "00000000-0000-0000-0000-000000000000",
+		ClientID:               "00000000-0000-0000-0000-000000000000",
+		ClientSecret:           "secret",
+		EnableClientSecretAuth: true,
+	}
+	for _, version := range []auth.TokenVersion{auth.TokenVersion1, auth.TokenVersion2} {
+		conf.Version = version
+		if _, err := conf.NewAuthorizer(context.Background(), api); err == nil || !strings.Contains(err.Error(), "unsupported Api") {
+			t.Errorf("NewAuthorizer(): expected unsupported Api error for token version %d, got %v", version, err)
+		}
+	}
+	if _, err := auth.NewClientSecretAuthorizer(context.Background(), environments.Global, api, auth.TokenVersion1, conf.TenantID, conf.ClientID, conf.ClientSecret); err == nil {
+		t.Error("NewClientSecretAuthorizer(): expected unsupported Api error")
+	}
+	if _, err := auth.NewMsiAuthorizer(context.Background(), environments.Global, api, ""); err == nil || !strings.Contains(err.Error(), "unsupported Api") {
+		t.Errorf("NewMsiAuthorizer(): expected unsupported Api error, got %v", err)
+	}
+	if _, err := auth.NewAzureCliConfig(api, conf.TenantID); err == nil || !strings.Contains(err.Error(), "unsupported Api") {
+		t.Errorf("NewAzureCliConfig(): expected unsupported Api error, got %v", err)
+	}
+}
+
+func TestConfig_ApiWithoutEndpoint(t *testing.T) {
+	custom := environments.Environment{
+		AzureADEndpoint: environments.AzureADGlobal,
+		MsGraph:         environments.MsGraphGlobal,
+	}
+	for _, c := range []struct {
+		environment environments.Environment
+		api         auth.Api
+	}{
+		{environments.Canary, auth.AadGraph},
+		{custom, auth.ResourceManager},
+		{custom, auth.KeyVault},
+		{custom, auth.Storage},
+	} {
+		conf := auth.Config{
+			Environment:            c.environment,
+			TenantID:               "00000000-0000-0000-0000-000000000000",
+			ClientID:               "00000000-0000-0000-0000-000000000000",
+			ClientSecret:           "secret",
+			EnableClientSecretAuth: true,
+		}
+		for _, version := range []auth.TokenVersion{auth.TokenVersion1, auth.TokenVersion2} {
+			conf.Version = version
+			if _, err := conf.NewAuthorizer(context.Background(), c.api); err == nil || !strings.Contains(err.Error(), "no endpoint") {
+				t.Errorf("NewAuthorizer(): expected error for Api %d without an endpoint for token version %d, got %v", c.api, version, err)
+			}
+		}
+		if _, err := auth.NewClientSecretAuthorizer(context.Background(), c.environment, c.api, auth.TokenVersion2, conf.TenantID, conf.ClientID, conf.ClientSecret); err == nil || !strings.Contains(err.Error(), "no endpoint") {
+			t.Errorf("NewClientSecretAuthorizer(): expected error for Api %d without an endpoint, got %v", c.api, err)
+		}
+	}
+}
+
+func TestConfig_AzureCliPath(t *testing.T) {
+	stub := testAzureCliStub(t, "2.30.0", `echo '{"accessToken": "cli-token", "expires_on": 1893456000, "tokenType": "Bearer"}'`)
+	dir := t.TempDir()
+	nonExecutable := filepath.Join(dir, "az-non-executable")
+	if err := os.WriteFile(nonExecutable, []byte("#!/bin/sh\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	for path, expected := range map[string]string{
+		filepath.Join(dir, "missing"): "could not find Azure CLI executable",
+		dir:                           "is a directory",
+		nonExecutable:                 "not executable",
+	} {
+		if _, err := testConfigNewAzureCliAuthorizer(path, ""); err == nil || !strings.Contains(err.Error(), expected) {
+			t.Fatalf("Config.NewAuthorizer(): expected error containing %q for path %q, got %v", expected, path, err)
+		}
+	}
+
+	a, err := testConfigNewAzureCliAuthorizer(stub, "")
+	if err != nil {
+		t.Fatalf("Config.NewAuthorizer(): %v", err)
+	}
+	token, err := a.Token()
+	if err != nil {
+		t.Fatalf("AzureCliAuthorizer.Token(): %v", err)
+	}
+	if token.AccessToken != "cli-token" {
+		t.Fatalf("AzureCliAuthorizer.Token(): expected token %q, got %q", "cli-token", token.AccessToken)
+	}
+
+	os.Setenv("AZURE_CLI_PATH", stub)
+	defer os.Unsetenv("AZURE_CLI_PATH")
+	if _, err := testConfigNewAzureCliAuthorizer("", ""); err != nil {
+		t.Fatalf("Config.NewAuthorizer(): unexpected error using AZURE_CLI_PATH: %v", err)
+	}
+}
+
+func TestConfig_AzureCliErrors(t *testing.T) {
+	stub := testAzureCliStub(t, "2.30.0", `echo "ERROR: Please run 'az login' to setup account." >&2; exit 1`)
+
+	if _, err := testConfigNewAzureCliAuthorizer(filepath.Join(t.TempDir(), "missing"), ""); !errors.Is(err, auth.ErrAzureCliNotFound) {
+		t.Fatalf("Config.NewAuthorizer(): expected ErrAzureCliNotFound, got %v", err)
+	}
+
+	a, err := testConfigNewAzureCliAuthorizer(stub, "")
+	if err != nil {
+		t.Fatalf("Config.NewAuthorizer(): %v", err)
+	}
+	if _, err := a.Token(); !errors.Is(err, auth.ErrAzureCliNotLoggedIn) {
+		t.Fatalf("AzureCliAuthorizer.Token(): expected ErrAzureCliNotLoggedIn, got %v", err)
+	}
+}
+
+func TestConfig_AzureCliMinimumVersion(t *testing.T) {
+	stub := testAzureCliStub(t, "2.30.0", `echo '{"accessToken": "cli-token", "expires_on": 1893456000, "tokenType": "Bearer"}'`)
+
+	for _, minimumVersion := range []string{"", "2.0.0", "2.30.0"} {
+		if _, err := testConfigNewAzureCliAuthorizer(stub, minimumVersion); err != nil {
+			t.Fatalf("Config.NewAuthorizer(): unexpected error for minimum version %q: %v", minimumVersion, err)
+		}
+	}
+	if _, err := testConfigNewAzureCliAuthorizer(stub, "2.54.0"); err == nil || !strings.Contains(err.Error(), "please upgrade Azure CLI to 2.54.0 or newer") {
+		t.Fatalf("Config.NewAuthorizer(): expected upgrade error for minimum version 2.54.0, got %v", err)
+	}
+}
+
+func TestConfig_AzureCliTokenExpiry(t *testing.T) {
+	expiry := time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	for name, token := range map[string]string{
+		"epoch":      fmt.Sprintf(`{"accessToken": "cli-token", "expiresOn": "ignored", "expires_on": %d, "tokenType": "Bearer"}`, expiry.Unix()),
+		"local time": fmt.Sprintf(`{"accessToken": "cli-token", "expiresOn": "%s", "tokenType": "Bearer"}`, expiry.In(time.Local).Format("2006-01-02 15:04:05.000000")),
+	} {
+		stub := testAzureCliStub(t, "2.30.0", fmt.Sprintf("echo '%s'", token))
+		a, err := testConfigNewAzureCliAuthorizer(stub, "")
+		if err != nil {
+			t.Fatalf("Config.NewAuthorizer(): %v", err)
+		}
+		tok, err := a.Token()
+		if err != nil {
+			t.Fatalf("AzureCliAuthorizer.Token(): unexpected error for %s expiry: %v", name, err)
+		}
+		if !tok.Expiry.Equal(expiry) {
+			t.Fatalf("AzureCliAuthorizer.Token(): expected %s expiry %s, got %s", name, expiry, tok.Expiry)
+		}
+	}
+
+	stub := testAzureCliStub(t, "2.30.0", `echo '{"accessToken": "cli-token", "tokenType": "Bearer"}'`)
+	a, err := testConfigNewAzureCliAuthorizer(stub, "")
+	if err != nil {
+		t.Fatalf("Config.NewAuthorizer(): %v", err)
+	}
+	if _, err := a.Token(); err == nil || !strings.Contains(err.Error(), "please upgrade Azure CLI") {
+		t.Fatalf("AzureCliAuthorizer.Token(): expected upgrade error for token without expiry, got %v", err)
+	}
+}
+
+func TestConfig_AzureCliTokenExpiryDST(t *testing.T) {
+	loc, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Fatalf("time.LoadLocation(): %v", err)
+	}
+	local := time.Local
+	time.Local = loc
+	defer func() { time.Local = local }()
+
+	for expiresOn, expected := range map[string]time.Time{
+		// Shortly after clocks are turned forward
+		"2021-03-14 03:30:00.000000": time.Date(2021, 3, 14, 7, 30, 0, 0, time.UTC),
+		// Shortly before clocks are turned back, so this time occurs twice and the earlier instant is expected
+		"2021-11-07 01:30:00.000000": time.Date(2021, 11, 7, 5, 30, 0, 0, time.UTC),
+		// Shortly after clocks are turned back
+		"2021-11-07 02:30:00.123456": time.Date(2021, 11, 7, 7, 30, 0, 123456000, time.UTC),
+	} {
+		stub := testAzureCliStub(t, "2.30.0", fmt.Sprintf(`echo '{"accessToken": "cli-token", "expiresOn": "%s", "tokenType": "Bearer"}'`, expiresOn))
+		a, err := testConfigNewAzureCliAuthorizer(stub, "")
+		if err != nil {
+			t.Fatalf("Config.NewAuthorizer(): %v", err)
+		}
+		token, err := a.Token()
+		if err != nil {
+			t.Fatalf("AzureCliAuthorizer.Token(): %v", err)
+		}
+		if !token.Expiry.Equal(expected) {
+			t.Fatalf("AzureCliAuthorizer.Token(): expected expiry %s for expiresOn %q, got %s", expected, expiresOn, token.Expiry.UTC())
+		}
+	}
+}
+
+func TestConfig_ClientCredentialsTenant(t *testing.T) {
+	for _, tenantId := range []string{"common", "Organizations", "consumers"} {
+		for _, c := range []auth.Config{
+			{ClientID: "00000000-0000-0000-0000-000000000000", ClientSecret: "secret", EnableClientSecretAuth: true},
+			{ClientID: "00000000-0000-0000-0000-000000000000", ClientCertData: []byte("pfx"), EnableClientCertAuth: true},
+		} {
+			c.Environment = environments.Global
+			c.TenantID = tenantId
+			if _, err := c.NewAuthorizer(context.Background(), auth.MsGraph); err == nil || !strings.Contains(err.Error(), "specific tenant") {
+				t.Fatalf("NewAuthorizer(): expected tenant error for tenant %q, got: %v", tenantId, err)
+			}
+		}
+	}
+
+	for _, tenantId := range []string{"", "common"} {
+		if _, err := auth.NewClientSecretAuthorizer(context.Background(), environments.Global, auth.MsGraph, auth.TokenVersion1, tenantId, "00000000-0000-0000-0000-000000000000", "secret"); err == nil || !strings.Contains(err.Error(), "specific tenant") {
+			t.Fatalf("NewClientSecretAuthorizer(): expected tenant error for tenant %q, got: %v", tenantId, err)
+		}
+		if _, err := auth.NewClientCertificateAuthorizer(context.Background(), environments.Global, auth.MsGraph, auth.TokenVersion1, tenantId, "00000000-0000-0000-0000-000000000000", []byte("pfx"), "", ""); err == nil || !strings.Contains(err.Error(), "specific tenant") {
+			t.Fatalf("NewClientCertificateAuthorizer(): expected tenant error for tenant %q, got: %v", tenantId, err)
+		}
+	}
+
+	a, err := testConfigNewAuthorizer(nil)
+	if err != nil {
+		t.Fatalf("NewAuthorizer(): unexpected error for a specific tenant: %v", err)
+	}
+	if _, err := a.(auth.TenantAuthorizer).WithTenant("common"); err == nil {
+		t.Fatal("WithTenant(): expected error for tenant \"common\"")
+	}
+
+	// Azure CLI authentication can be used without a specific tenant
+	stub := testAzureCliStub(t, "2.30.0", `echo '{"accessToken": "cli-token", "expires_on": 1893456000, "tenantId": "00000000-0000-0000-0000-000000000000", "tokenType": "Bearer"}'`)
+	conf := auth.Config{
+		Environment:         environments.Global,
+		EnableAzureCliToken: true,
+		AzureCliPath:        stub,
+	}
+	if _, err := conf.NewAuthorizer(context.Background(), auth.MsGraph); err != nil {
+		t.Fatalf("NewAuthorizer(): unexpected error for Azure CLI without a tenant: %v", err)
+	}
+
+	// Client credentials are skipped without a tenant, so that later authentication methods are attempted
+	conf.ClientID = "00000000-0000-0000-0000-000000000000"
+	conf.ClientSecret = "secret"
+	conf.EnableClientSecretAuth = true
+	a, err = conf.NewAuthorizer(context.Background(), auth.MsGraph)
+	if err != nil {
+		t.Fatalf("NewAuthorizer(): unexpected error for client secret without a tenant: %v", err)
+	}
+	if token, err := a.Token(); err != nil || token.AccessToken != "cli-token" {
+		t.Fatalf("NewAuthorizer(): expected Azure CLI authentication without a tenant, got token %v (%v)", token, err)
+	}
+}
+
+func TestConfig_NewAuthorizerForVersion(t *testing.T) {
+	var path string
+	var form url.Values
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path = r.URL.Path
+		if err := r.ParseForm(); err != nil {
+			t.Errorf("could not parse form: %v", err)
+		}
+		form = r.PostForm
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"access_token":"token","token_type":"Bearer","expires_in":3600}`)
+	}))
+	defer server.Close()
+
+	environment := environments.Global
+	environment.AzureADEndpoint = environments.AzureADEndpoint(server.URL)
+	conf := auth.Config{
+		Environment:            environment,
+		Version:                auth.TokenVersion2,
+		TenantID:               "00000000-0000-0000-0000-000000000000",
+		ClientID:               "00000000-0000-0000-0000-000000000000",
+		ClientSecret:           "secret",
+		EnableClientSecretAuth: true,
+	}
+
+	a, err := conf.NewAuthorizerForVersion(context.Background(), auth.AadGraph, auth.TokenVersion1)
+	if err != nil {
+		t.Fatalf("NewAuthorizerForVersion(): %v", err)
+	}
+	if _, err := a.Token(); err != nil {
+		t.Fatalf("Token(): %v", err)
+	}
+	if expected := "/00000000-0000-0000-0000-000000000000/oauth2/token"; path != expected {
+		t.Fatalf("Token(): expected v1 token request to %q, got %q", expected, path)
+	}
+	if !strings.HasPrefix(form.Get("resource"), string(environment.AadGraph.Endpoint)) || form.Get("scope") != "" {
+		t.Fatalf("Token(): expected v1 token request for resource %q, got resource %q and scope %q", environment.AadGraph.Endpoint, form.Get("resource"), form.Get("scope"))
+	}
+
+	a, err = conf.NewAuthorizer(context.Background(), auth.MsGraph)
+	if err != nil {
+		t.Fatalf("NewAuthorizer(): %v", err)
+	}
+	if _, err := a.Token(); err != nil {
+		t.Fatalf("Token(): %v", err)
+	}
+	if expected := "/00000000-0000-0000-0000-000000000000/oauth2/v2.0/token"; path != expected {
+		t.Fatalf("Token(): expected Config to be unchanged and v2 token requested from %q, got %q", expected, path)
+	}
+	if form.Get("resource") != "" || form.Get("scope") == "" {
+		t.Fatalf("Token(): expected v2 token request with scope, got resource %q and scope %q", form.Get("resource"), form.Get("scope"))
+	}
+
+	if _, err := conf.NewAuthorizerForVersion(context.Background(), auth.MsGraph, auth.TokenVersion(5)); err == nil {
+		t.Fatal("NewAuthorizerForVersion(): expected error for unsupported token version")
+	}
+}
+
+func TestConfig_NewAuthorizerForTenant(t *testing.T) {
+	var path string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path = r.URL.Path
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"access_token":"token","token_type":"Bearer","expires_in":3600}`)
+	}))
+	defer server.Close()
+
+	environment := environments.Global
+	environment.AzureADEndpoint = environments.AzureADEndpoint(server.URL)
+	conf := auth.Config{
+		Environment:            environment,
+		Version:                auth.TokenVersion2,
+		TenantID:               "00000000-0000-0000-0000-000000000000",
+		ClientID:               "00000000-0000-0000-0000-000000000000",
+		ClientSecret:           "secret",
+		EnableClientSecretAuth: true,
+	}
+
+	a, err := conf.NewAuthorizerForTenant(context.Background(), auth.MsGraph, "11111111-1111-1111-1111-111111111111")
+	if err != nil {
+		t.Fatalf("NewAuthorizerForTenant(): %v", err)
+	}
+	if _, err := a.Token(); err != nil {
+		t.Fatalf("Token(): %v", err)
+	}
+	if expected := "/11111111-1111-1111-1111-111111111111/oauth2/v2.0/token"; path != expected {
+		t.Fatalf("Token(): expected token request to %q, got %q", expected, path)
+	}
+	if conf.TenantID != "00000000-0000-0000-0000-000000000000" {
+		t.Fatalf("NewAuthorizerForTenant(): expected Config to be unchanged, got TenantID %q", conf.TenantID)
+	}
+
+	if _, err := conf.NewAuthorizerForTenant(context.Background(), auth.MsGraph, ""); err == nil {
+		t.Fatal("NewAuthorizerForTenant(): expected error for empty tenantId")
+	}
+	msiConf := auth.Config{Environment: environment, EnableMsiAuth: true}
+	if _, err := msiConf.NewAuthorizerForTenant(context.Background(), auth.MsGraph, "11111111-1111-1111-1111-111111111111"); err == nil {
+		t.Fatal("NewAuthorizerForTenant(): expected error when only MSI authentication is enabled")
+	}
+}
+
+func TestConfig_TokenURL(t *testing.T) {
+	var path string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path = r.URL.Path
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"access_token":"token","token_type":"Bearer","expires_in":3600}`)
+	}))
+	defer server.Close()
+
+	environment := environments.Global
+	environment.AzureADEndpoint = environments.AzureADEndpoint(server.URL)
+	newConfig := func() auth.Config {
+		return auth.Config{
+			Environment:            environment,
+			Version:                auth.TokenVersion2,
+			TenantID:               "contoso.onmicrosoft.com",
+			ClientID:               "00000000-0000-0000-0000-000000000000",
+			ClientSecret:           "secret",
+			EnableClientSecretAuth: true,
+		}
+	}
+
+	conf := newConfig()
+	conf.B2CPolicy = "B2C_1A_client_credentials"
+	a, err := conf.NewAuthorizer(context.Background(), auth.MsGraph)
+	if err != nil {
+		t.Fatalf("NewAuthorizer(): %v", err)
+	}
+	if _, err := a.Token(); err != nil {
+		t.Fatalf("Token(): %v", err)
+	}
+	if expected := "/contoso.onmicrosoft.com/B2C_1A_client_credentials/oauth2/v2.0/token"; path != expected {
+		t.Fatalf("Token(): expected token request to %q, got %q", expected, path)
+	}
+
+	a, err = conf.NewAuthorizerForTenant(context.Background(), auth.MsGraph, "fabrikam.onmicrosoft.com")
+	if err != nil {
+		t.Fatalf("NewAuthorizerForTenant(): %v", err)
+	}
+	if _, err := a.Token(); err != nil {
+		t.Fatalf("Token(): %v", err)
+	}
+	if expected := "/fabrikam.onmicrosoft.com/B2C_1A_client_credentials/oauth2/v2.0/token"; path != expected {
+		t.Fatalf("Token(): expected token request to %q, got %q", expected, path)
+	}
+
+	conf = newConfig()
+	conf.TokenURL = server.URL + "/custom/authority/token"
+	a, err = conf.NewAuthorizer(context.Background(), auth.MsGraph)
+	if err != nil {
+		t.Fatalf("NewAuthorizer(): %v", err)
+	}
+	if _, err := a.Token(); err != nil {
+		t.Fatalf("Token(): %v", err)
+	}
+	if expected := "/custom/authority/token"; path != expected {
+		t.Fatalf("Token(): expected token request to %q, got %q", expected, path)
+	}
+	if _, err := conf.NewAuthorizerForTenant(context.Background(), auth.MsGraph, "fabrikam.onmicrosoft.com"); err == nil {
+		t.Fatal("NewAuthorizerForTenant(): expected error when TokenURL is specified")
+	}
+
+	for name, configure := range map[string]func(*auth.Config){
+		"relativeTokenURL":  func(c *auth.Config) { c.TokenURL = "/custom/authority/token" },
+		"unsupportedScheme": func(c *auth.Config) { c.TokenURL = "ftp://login.contoso.com/token" },
+		"both": func(c *auth.Config) {
+			c.TokenURL = server.URL + "/custom/authority/token"
+			c.B2CPolicy = "B2C_1_signin"
+		},
+		"invalidPolicy": func(c *auth.Config) { c.B2CPolicy = "B2C_1_signin/../other" },
+		"policyWithV1":  func(c *auth.Config) { c.B2CPolicy, c.Version = "B2C_1_signin", auth.TokenVersion1 },
+	} {
+		conf := newConfig()
+		configure(&conf)
+		if _, err := conf.NewAuthorizer(context.Background(), auth.MsGraph); err == nil || !strings.Contains(err.Error(), "invalid token endpoint") {
+			t.Errorf("%s: NewAuthorizer(): expected invalid token endpoint error, got: %v", name, err)
+		}
+	}
+}
+
+func TestConfig_ResourceApis(t *testing.T) {
+	var form url.Values
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseForm(); err != nil {
+			t.Errorf("could not parse form: %v", err)
+		}
+		form = r.PostForm
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"access_token":"token","token_type":"Bearer","expires_in":3600}`)
+	}))
+	defer server.Close()
+
+	conf := auth.Config{
+		TenantID:               "00000000-0000-0000-0000-000000000000",
+		ClientID:               "00000000-0000-0000-0000-000000000000",
+		ClientSecret:           "secret",
+		EnableClientSecretAuth: true,
+	}
+
+	for _, environment := range []environments.Environment{environments.Global, environments.Canary} {
+		environment.AzureADEndpoint = environments.AzureADEndpoint(server.URL)
+		conf.Environment = environment
+
+		for _, c := range []struct {
+			api      auth.Api
+			endpoint string
+		}{
+			{auth.ResourceManager, "https://management.azure.com"},
+			{auth.KeyVault, "https://vault.azure.net"},
+			{auth.Storage, "https://storage.azure.com"},
+		} {
+			conf.Version = auth.TokenVersion2
+			a, err := conf.NewAuthorizer(context.Background(), c.api)
+			if err != nil {
+				t.Fatalf("NewAuthorizer(): %v", err)
+			}
+			if _, err := a.Token(); err != nil {
+				t.Fatalf("Token(): %v", err)
+			}
+			if expected := c.endpoint + "/.default"; form.Get("scope") != expected {
+				t.Errorf("Token(): expected scope %q for Api %d, got %q", expected, c.api, form.Get("scope"))
+			}
+
+			conf.Version = auth.TokenVersion1
+			a, err = conf.NewAuthorizer(context.Background(), c.api)
+			if err != nil {
+				t.Fatalf("NewAuthorizer(): %v", err)
+			}
+			if _, err := a.Token(); err != nil {
+				t.Fatalf("Token(): %v", err)
+			}
+			if expected := c.endpoint + "/"; form.Get("resource") != expected {
+				t.Errorf("Token(): expected resource %q for Api %d, got %q", expected, c.api, form.Get("resource"))
+			}
+		}
+	}
+
+	if _, err := auth.NewAzureCliConfig(auth.Storage, conf.TenantID); err == nil || !strings.Contains(err.Error(), "Azure CLI") {
+		t.Errorf("NewAzureCliConfig(): expected error for Api without Azure CLI resource type, got %v", err)
+	}
+}
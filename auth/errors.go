@@ -0,0 +1,95 @@
+package auth
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+)
+
+var (
+	// ErrInvalidClientSecret indicates that the client secret presented is not valid for the application (AADSTS7000215).
+	ErrInvalidClientSecret = errors.New("invalid client secret")
+
+	// ErrExpiredCredential indicates that the client secret presented has expired and should be rotated (AADSTS7000222).
+	ErrExpiredCredential = errors.New("expired client credential")
+
+	// ErrApplicationNotFound indicates that the application was not found in the tenant, which may be due to an
+	// incorrect client ID or tenant ID, or the application not having been consented in the tenant (AADSTS700016).
+	ErrApplicationNotFound = errors.New("application not found in directory")
+
+	// ErrConsentRequired indicates that an administrator has not consented to the permissions requested by the
+	// application (AADSTS65001).
+	ErrConsentRequired = errors.New("consent required")
+)
+
+// credentialErrorCodes maps AADSTS error codes to the corresponding credential errors
+var credentialErrorCodes = map[int]error{
+	7000215: ErrInvalidClientSecret,
+	7000222: ErrExpiredCredential,
+	700016:  ErrApplicationNotFound,
+	65001:   ErrConsentRequired,
+}
+
+var aadstsCodeRegexp = regexp.MustCompile(`AADSTS(\d+)`)
+
+// CredentialError is returned when a token request is rejected because of a problem with the client credentials or
+// the application, such as an expired client secret. Use errors.Is to test for the specific cause, e.g.
+// ErrExpiredCredential. The original error, including the response from Azure Active Directory, is accessible via
+// errors.Unwrap.
+type CredentialError struct {
+	// Code is the AADSTS error code returned by Azure Active Directory.
+	Code int
+
+	// Description is the error description returned by Azure Active Directory.
+	Description string
+
+	kind error
+	err  error
+}
+
+// Error returns an error string for CredentialError, which includes the original error.
+func (e *CredentialError) Error() string {
+	return fmt.Sprintf("%v: %v", e.kind, e.err)
+}
+
+// Is reports whether target is the credential error indicated by the AADSTS error code.
+func (e *CredentialError) Is(target error) bool {
+	return target == e.kind
+}
+
+// Unwrap returns the original error.
+func (e *CredentialError) Unwrap() error {
+	return e.err
+}
+
+// credentialError inspects the body of a failed token response, returning a CredentialError wrapping err when it
+// contains a recognised AADSTS error code. Otherwise, err is returned unchanged.
+func credentialError(body []byte, err error) error {
+	var errRes struct {
+		ErrorDescription string `json:"error_description"`
+		ErrorCodes       []int  `json:"error_codes"`
+	}
+	if json.Unmarshal(body, &errRes) != nil {
+		return err
+	}
+
+	codes := errRes.ErrorCodes
+	for _, m := range aadstsCodeRegexp.FindAllStringSubmatch(errRes.ErrorDescription, -1) {
+		if code, e := strconv.Atoi(m[1]); e == nil {
+			codes = append(codes, code)
+		}
+	}
+	for _, code := range codes {
+		if kind, ok := credentialErrorCodes[code]; ok {
+			return &CredentialError{
+				Code:        code,
+				Description: errRes.ErrorDescription,
+				kind:        kind,
+				err:         err,
+			}
+		}
+	}
+	return err
+}
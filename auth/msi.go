@@ -2,6 +2,7 @@ package auth
 
 import (
 	"context"
+	"crypto/tls"
 	"encoding/json"
 	"fmt"
 	"io/ioutil"
@@ -17,6 +18,9 @@ const (
 	msiDefaultApiVersion = "2018-02-01"
 	msiDefaultEndpoint   = "http://169.254.169.254/metadata/identity/oauth2/token"
 	msiDefaultTimeout    = 10 * time.Second
+
+	// msiRefreshMargin is the duration ahead of expiry at which a cached managed identity token is refreshed
+	msiRefreshMargin = 5 * time.Minute
 )
 
 // MsiAuthorizer is an Authorizer which supports managed service identity.
@@ -27,13 +31,34 @@ type MsiAuthorizer struct {
 
 // Token returns an access token acquired from the metadata endpoint.
 func (a *MsiAuthorizer) Token() (*oauth2.Token, error) {
+	return a.TokenWithContext(authorizerContext(a.ctx))
+}
+
+// TokenWithContext returns an access token acquired from the metadata endpoint using the provided context.
+func (a *MsiAuthorizer) TokenWithContext(ctx context.Context) (*oauth2.Token, error) {
+	return a.token(ctx, a.tokenUrl())
+}
+
+// now returns the current time according to the configured Clock
+func (a *MsiAuthorizer) now() time.Time {
+	if a.conf.Clock != nil {
+		return a.conf.Clock()
+	}
+	return time.Now()
+}
+
+// tokenUrl returns the metadata endpoint URL for requesting a token for the target resource
+func (a *MsiAuthorizer) tokenUrl() string {
 	query := url.Values{
 		"api-version": []string{a.conf.MsiApiVersion},
 		"resource":    []string{a.conf.Resource},
 	}
-	url := fmt.Sprintf("%s?%s", a.conf.MsiEndpoint, query.Encode())
+	return fmt.Sprintf("%s?%s", a.conf.MsiEndpoint, query.Encode())
+}
 
-	body, err := azureMetadata(a.ctx, url)
+// token requests a new access token from the metadata endpoint
+func (a *MsiAuthorizer) token(ctx context.Context, url string) (*oauth2.Token, error) {
+	body, err := azureMetadata(ctx, url, a.conf.TLSConfig, a.conf.RetryPolicy)
 	if err != nil {
 		return nil, fmt.Errorf("MsiAuthorizer: failed to request token from metadata endpoint: %v", err)
 	}
@@ -57,6 +82,12 @@ func (a *MsiAuthorizer) Token() (*oauth2.Token, error) {
 		TokenType:   tokenRes.TokenType,
 	}
 
+	// Prefer the absolute expiry timestamp, falling back to the relative duration
+	if exp := msiParseSeconds(tokenRes.ExpiresOn); exp > 0 {
+		token.Expiry = time.Unix(exp, 0)
+		return token, nil
+	}
+
 	var secs time.Duration
 	if exp, ok := tokenRes.ExpiresIn.(string); ok && exp != "" {
 		if v, err := strconv.Atoi(exp); err == nil {
@@ -68,21 +99,57 @@ func (a *MsiAuthorizer) Token() (*oauth2.Token, error) {
 		secs = time.Duration(exp)
 	}
 	if secs > 0 {
-		token.Expiry = time.Now().Add(secs * time.Second)
+		token.Expiry = a.now().Add(secs * time.Second)
 	}
 
 	return token, nil
 }
 
+// msiParseSeconds parses a number of seconds from a metadata token response, which may be a string or a number
+func msiParseSeconds(v interface{}) int64 {
+	switch val := v.(type) {
+	case string:
+		if i, err := strconv.ParseInt(val, 10, 64); err == nil {
+			return i
+		}
+	case float64:
+		return int64(val)
+	}
+	return 0
+}
+
 // MsiConfig configures an MsiAuthorizer.
 type MsiConfig struct {
 	MsiApiVersion string
 	MsiEndpoint   string
 	Resource      string
+
+	// Clock is an optional function returning the current time, which is used by the authorizer returned by
+	// TokenSource() to determine whether its cached token should be refreshed. When nil, time.Now is used.
+	Clock func() time.Time
+
+	// Observer is optionally notified each time the authorizer returned by TokenSource() returns a token, whether from
+	// its cache or newly acquired from the metadata endpoint, and when acquiring a token fails.
+	Observer Observer
+
+	// OnTokenRefreshed is optionally called each time the authorizer returned by TokenSource() acquires a new token from
+	// the metadata endpoint. See CachedAuthorizer.OnTokenRefreshed for details of when it is called.
+	OnTokenRefreshed func(*oauth2.Token)
+
+	// TLSConfig optionally specifies the TLS configuration used when connecting to the metadata endpoint. When nil,
+	// the default configuration is used.
+	TLSConfig *tls.Config
+
+	// RetryPolicy optionally specifies how failed token requests are retried. When nil, requests are not retried.
+	RetryPolicy *RetryPolicy
 }
 
 // NewMsiConfig returns a new MsiConfig with a configured metadata endpoint and resource.
 func NewMsiConfig(ctx context.Context, resource string, msiEndpoint string) (*MsiConfig, error) {
+	return newMsiConfig(ctx, resource, msiEndpoint, nil, nil)
+}
+
+func newMsiConfig(ctx context.Context, resource, msiEndpoint string, tlsConfig *tls.Config, retryPolicy *RetryPolicy) (*MsiConfig, error) {
 	endpoint := msiDefaultEndpoint
 	if msiEndpoint != "" {
 		endpoint = msiEndpoint
@@ -101,7 +168,8 @@ func NewMsiConfig(ctx context.Context, resource string, msiEndpoint string) (*Ms
 		"format":      []string{"text"},
 	}.Encode()
 
-	_, err = azureMetadata(ctx, e.String())
+	// the endpoint is probed without retries, so that an unavailable endpoint is reported promptly
+	_, err = azureMetadata(ctx, e.String(), tlsConfig, nil)
 	if err != nil {
 		return nil, fmt.Errorf("NewMsiConfig: could not validate MSI endpoint: %v", err)
 	}
@@ -110,15 +178,23 @@ func NewMsiConfig(ctx context.Context, resource string, msiEndpoint string) (*Ms
 		Resource:      resource,
 		MsiApiVersion: msiDefaultApiVersion,
 		MsiEndpoint:   endpoint,
+		TLSConfig:     tlsConfig,
+		RetryPolicy:   retryPolicy,
 	}, nil
 }
 
-// TokenSource provides a source for obtaining access tokens using MsiAuthorizer.
+// TokenSource provides a source for obtaining access tokens using MsiAuthorizer. Tokens are cached and refreshed 5
+// minutes ahead of their expiry, plus a short random duration.
 func (c *MsiConfig) TokenSource(ctx context.Context) Authorizer {
-	return NewCachedAuthorizer(&MsiAuthorizer{ctx: ctx, conf: c})
+	a := NewCachedAuthorizer(&MsiAuthorizer{ctx: ctx, conf: c}).(*CachedAuthorizer)
+	a.RefreshMargin = msiRefreshMargin
+	a.Clock = c.Clock
+	a.Observer = c.Observer
+	a.OnTokenRefreshed = c.OnTokenRefreshed
+	return a
 }
 
-func azureMetadata(ctx context.Context, url string) (body []byte, err error) {
+func azureMetadata(ctx context.Context, url string, tlsConfig *tls.Config, retryPolicy *RetryPolicy) (body []byte, err error) {
 	var req *http.Request
 	req, err = http.NewRequestWithContext(ctx, http.MethodGet, url, http.NoBody)
 	if err != nil {
@@ -127,11 +203,9 @@ func azureMetadata(ctx context.Context, url string) (body []byte, err error) {
 	req.Header = http.Header{
 		"Metadata": []string{"true"},
 	}
-	client := &http.Client{
-		Timeout: msiDefaultTimeout,
-	}
+	client := httpClient(tlsConfig, msiDefaultTimeout)
 	var resp *http.Response
-	resp, err = client.Do(req)
+	resp, err = doWithRetries(client, req, retryPolicy)
 	if err != nil {
 		return
 	}
@@ -0,0 +1,141 @@
+package auth_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/manicminer/hamilton/auth"
+)
+
+func testMsiServer(expiresIn time.Duration, fetches *int32) *httptest.Server {
+	handler := http.NewServeMux()
+	handler.HandleFunc("/metadata/identity/oauth2/token", func(w http.ResponseWriter, r *http.Request) {
+		n := atomic.AddInt32(fetches, 1)
+		w.Header().Set("Content-Type", "application/json; charset=utf-8")
+		fmt.Fprintf(w, `{"access_token":"token-%d","expires_in":"%d","expires_on":"%d","resource":"%s","token_type":"Bearer"}`, n, int(expiresIn.Seconds()), time.Now().Add(expiresIn).Unix(), r.URL.Query().Get("resource"))
+	})
+	handler.HandleFunc("/metadata", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain")
+	})
+	return httptest.NewServer(handler)
+}
+
+func testMsiAuthorizer(ctx context.Context, t *testing.T, endpoint, resource string) auth.Authorizer {
+	conf, err := auth.NewMsiConfig(ctx, resource, endpoint)
+	if err != nil {
+		t.Fatalf("NewMsiConfig(): %v", err)
+	}
+	return conf.TokenSource(ctx)
+}
+
+func TestMsiAuthorizer_Cache(t *testing.T) {
+	ctx := context.Background()
+	var fetches int32
+	server := testMsiServer(time.Hour, &fetches)
+	defer server.Close()
+	endpoint := fmt.Sprintf("%s/metadata/identity/oauth2/token", server.URL)
+
+	a := testMsiAuthorizer(ctx, t, endpoint, "https://graph.microsoft.com/")
+	c, ok := a.(auth.CacheAwareAuthorizer)
+	if !ok {
+		t.Fatalf("MsiConfig.TokenSource(): expected a CacheAwareAuthorizer, got %T", a)
+	}
+	for i := 0; i < 3; i++ {
+		token, err := c.Token()
+		if err != nil {
+			t.Fatalf("MsiAuthorizer.Token(): %v", err)
+		}
+		if token.AccessToken != "token-1" {
+			t.Fatalf("MsiAuthorizer.Token(): expected cached token %q, got %q", "token-1", token.AccessToken)
+		}
+		if fromCache := c.LastTokenFromCache(); fromCache != (i > 0) {
+			t.Fatalf("MsiAuthorizer.LastTokenFromCache(): expected %t, got %t", i > 0, fromCache)
+		}
+	}
+	if n := atomic.LoadInt32(&fetches); n != 1 {
+		t.Fatalf("MsiAuthorizer.Token(): expected 1 fetch from metadata endpoint, got %d", n)
+	}
+
+	// Tokens are not shared between authorizers, so invalidating a token only affects its own authorizer
+	other := testMsiAuthorizer(ctx, t, endpoint, "https://graph.microsoft.com/")
+	if token, err := other.Token(); err != nil {
+		t.Fatalf("MsiAuthorizer.Token(): %v", err)
+	} else if token.AccessToken != "token-2" {
+		t.Fatalf("MsiAuthorizer.Token(): expected new token %q, got %q", "token-2", token.AccessToken)
+	}
+	other.(auth.RefreshableAuthorizer).InvalidateToken()
+	if token, err := a.Token(); err != nil {
+		t.Fatalf("MsiAuthorizer.Token(): %v", err)
+	} else if token.AccessToken != "token-1" {
+		t.Fatalf("MsiAuthorizer.Token(): expected cached token %q, got %q", "token-1", token.AccessToken)
+	}
+	if n := atomic.LoadInt32(&fetches); n != 2 {
+		t.Fatalf("MsiAuthorizer.Token(): expected 2 fetches from metadata endpoint, got %d", n)
+	}
+}
+
+func TestMsiAuthorizer_CacheRefresh(t *testing.T) {
+	ctx := context.Background()
+	var fetches int32
+
+	// Tokens expiring within the refresh window should not be reused
+	server := testMsiServer(2*time.Minute, &fetches)
+	defer server.Close()
+	endpoint := fmt.Sprintf("%s/metadata/identity/oauth2/token", server.URL)
+
+	a := testMsiAuthorizer(ctx, t, endpoint, "https://graph.microsoft.com/")
+	for i := 0; i < 3; i++ {
+		if _, err := a.Token(); err != nil {
+			t.Fatalf("MsiAuthorizer.Token(): %v", err)
+		}
+	}
+	if n := atomic.LoadInt32(&fetches); n != 3 {
+		t.Fatalf("MsiAuthorizer.Token(): expected 3 fetches from metadata endpoint, got %d", n)
+	}
+}
+
+func TestMsiAuthorizer_Clock(t *testing.T) {
+	ctx := context.Background()
+	var fetches int32
+	server := testMsiServer(time.Hour, &fetches)
+	defer server.Close()
+
+	now := time.Now()
+	conf, err := auth.NewMsiConfig(ctx, "https://management.azure.com/", fmt.Sprintf("%s/metadata/identity/oauth2/token", server.URL))
+	if err != nil {
+		t.Fatalf("NewMsiConfig(): %v", err)
+	}
+	conf.Clock = func() time.Time { return now }
+	a := conf.TokenSource(ctx)
+	c, ok := a.(*auth.CachedAuthorizer)
+	if !ok {
+		t.Fatalf("MsiConfig.TokenSource(): expected a *CachedAuthorizer, got %T", a)
+	}
+	c.RefreshJitter = 0
+
+	token, err := a.Token()
+	if err != nil {
+		t.Fatalf("MsiAuthorizer.Token(): %v", err)
+	}
+
+	// Without jitter, cached tokens are refreshed exactly 5 minutes ahead of their expiry
+	now = token.Expiry.Add(-5*time.Minute - time.Second)
+	if _, err := a.Token(); err != nil {
+		t.Fatalf("MsiAuthorizer.Token(): %v", err)
+	}
+	if n := atomic.LoadInt32(&fetches); n != 1 {
+		t.Fatalf("MsiAuthorizer.Token(): expected token to be cached before the refresh window, got %d fetches", n)
+	}
+	now = token.Expiry.Add(-5 * time.Minute)
+	if _, err := a.Token(); err != nil {
+		t.Fatalf("MsiAuthorizer.Token(): %v", err)
+	}
+	if n := atomic.LoadInt32(&fetches); n != 2 {
+		t.Fatalf("MsiAuthorizer.Token(): expected token to be refreshed at the start of the refresh window, got %d fetches", n)
+	}
+}
@@ -0,0 +1,40 @@
+package auth
+
+import (
+	"time"
+
+	"golang.org/x/oauth2"
+)
+
+// Observer is notified about token acquisition by authorizers which support it, and can be used to record metrics
+// such as the latency and failure rate of acquiring tokens. Implementations must be safe for concurrent use.
+type Observer interface {
+	// OnTokenAcquired is called after an authorizer returns a token, or fails to. The duration includes any time
+	// spent waiting for a concurrent refresh. fromCache is true when a cached token was returned, and err is non-nil
+	// when a token could not be acquired.
+	OnTokenAcquired(duration time.Duration, fromCache bool, err error)
+}
+
+// ObserverFunc is an adapter to allow the use of an ordinary function as an Observer.
+type ObserverFunc func(duration time.Duration, fromCache bool, err error)
+
+// OnTokenAcquired calls f(duration, fromCache, err).
+func (f ObserverFunc) OnTokenAcquired(duration time.Duration, fromCache bool, err error) {
+	f(duration, fromCache, err)
+}
+
+// setObserver configures the Observer for an authorizer returned by Config.NewAuthorizer
+func setObserver(authorizer Authorizer, observer Observer) {
+	switch a := authorizer.(type) {
+	case *CachedAuthorizer:
+		a.Observer = observer
+	}
+}
+
+// setOnTokenRefreshed configures the token refresh callback for an authorizer returned by Config.NewAuthorizer
+func setOnTokenRefreshed(authorizer Authorizer, fn func(*oauth2.Token)) {
+	switch a := authorizer.(type) {
+	case *CachedAuthorizer:
+		a.OnTokenRefreshed = fn
+	}
+}
@@ -0,0 +1,42 @@
+package auth
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+)
+
+// GenerateState returns a cryptographically random value, encoded using unpadded base64url, for use as the state
+// parameter of an authorization request, which should be verified when handling the redirect to protect against
+// cross-site request forgery.
+func GenerateState() (string, error) {
+	return randomString(32)
+}
+
+// GeneratePKCE returns a cryptographically random code verifier and its S256 code challenge, for use with Proof Key
+// for Code Exchange (RFC 7636). The challenge is sent in the authorization request with a code_challenge_method of
+// `S256`, and the verifier is sent when redeeming the authorization code. Both are encoded using unpadded base64url.
+func GeneratePKCE() (verifier, challenge string, err error) {
+	// 32 bytes of entropy are encoded as 43 characters, the minimum length of a verifier
+	if verifier, err = randomString(32); err != nil {
+		return "", "", err
+	}
+	return verifier, PKCEChallenge(verifier), nil
+}
+
+// PKCEChallenge returns the S256 code challenge for a PKCE code verifier, which is the unpadded base64url encoding of
+// the SHA-256 hash of the verifier.
+func PKCEChallenge(verifier string) string {
+	sum := sha256.Sum256([]byte(verifier))
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
+
+// randomString returns n cryptographically random bytes encoded using unpadded base64url
+func randomString(n int) (string, error) {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("generating random bytes: %v", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
@@ -0,0 +1,48 @@
+package auth_test
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/manicminer/hamilton/auth"
+)
+
+// unreservedRegex matches the unpadded base64url alphabet, which is a subset of the characters permitted in a PKCE
+// verifier
+var unreservedRegex = regexp.MustCompile(`^[A-Za-z0-9_-]+$`)
+
+func TestGenerateState(t *testing.T) {
+	state, err := auth.GenerateState()
+	if err != nil {
+		t.Fatalf("GenerateState(): %v", err)
+	}
+	if len(state) != 43 || !unreservedRegex.MatchString(state) {
+		t.Fatalf("GenerateState(): expected 43 URL-safe characters, got %q", state)
+	}
+	if other, _ := auth.GenerateState(); other == state {
+		t.Fatalf("GenerateState(): expected unique values, got %q twice", state)
+	}
+}
+
+func TestGeneratePKCE(t *testing.T) {
+	verifier, challenge, err := auth.GeneratePKCE()
+	if err != nil {
+		t.Fatalf("GeneratePKCE(): %v", err)
+	}
+	if len(verifier) < 43 || len(verifier) > 128 || !unreservedRegex.MatchString(verifier) {
+		t.Fatalf("GeneratePKCE(): invalid verifier %q", verifier)
+	}
+	if len(challenge) != 43 || !unreservedRegex.MatchString(challenge) {
+		t.Fatalf("GeneratePKCE(): invalid challenge %q", challenge)
+	}
+	if expected := auth.PKCEChallenge(verifier); challenge != expected {
+		t.Fatalf("GeneratePKCE(): expected challenge %q, got %q", expected, challenge)
+	}
+}
+
+func TestPKCEChallenge(t *testing.T) {
+	// Example from RFC 7636 Appendix B
+	if challenge := auth.PKCEChallenge("dBjftJeZ4CVP-mB92K27uhbUJU1p1r_wW1gFWFOEjXk"); challenge != "E9Melhoa2OwvFrEMTJguCHaoeK1t8URWbuGJSstw-cM" {
+		t.Fatalf("PKCEChallenge(): expected %q, got %q", "E9Melhoa2OwvFrEMTJguCHaoeK1t8URWbuGJSstw-cM", challenge)
+	}
+}
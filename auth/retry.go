@@ -0,0 +1,242 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+)
+
+const (
+	defaultRetryMaxRetries = 4
+	defaultRetryBaseDelay  = 1 * time.Second
+	defaultRetryMaxDelay   = 30 * time.Second
+)
+
+// RetryPolicy configures how failed HTTP requests are retried. The same policy can be used for token requests made
+// by authorizers (see Config.RetryPolicy) and for requests made by msgraph clients (see msgraph.Client.WithHTTPRetryPolicy),
+// so that retry behavior can be tuned in one place for each environment.
+//
+// Delays increase exponentially from BaseDelay, doubling with each retry up to MaxDelay. Fields other than MaxRetries
+// and RespectRetryAfter take the values from DefaultRetryPolicy when left unset.
+type RetryPolicy struct {
+	// MaxRetries is the maximum number of times a request is retried after the initial attempt. Zero disables retries.
+	MaxRetries int
+
+	// BaseDelay is the delay before the first retry.
+	BaseDelay time.Duration
+
+	// MaxDelay is the maximum delay between retries.
+	MaxDelay time.Duration
+
+	// RetryableStatusCodes lists the response status codes for which a request is retried. Requests which fail
+	// without receiving a response are retried for transient network errors only (see ErrorRetryable).
+	RetryableStatusCodes []int
+
+	// RetryableMethods lists the HTTP methods of requests which can be retried. When empty, requests using any method
+	// can be retried.
+	RetryableMethods []string
+
+	// RespectRetryAfter causes the delay requested by a Retry-After response header to be used in place of the
+	// calculated delay, even when it exceeds MaxDelay.
+	RespectRetryAfter bool
+}
+
+// DefaultRetryPolicy returns a RetryPolicy which retries requests up to 4 times, waiting between 1 and 30 seconds,
+// when they are throttled or fail with a transient server error, honoring any Retry-After header.
+func DefaultRetryPolicy() *RetryPolicy {
+	return &RetryPolicy{
+		MaxRetries: defaultRetryMaxRetries,
+		BaseDelay:  defaultRetryBaseDelay,
+		MaxDelay:   defaultRetryMaxDelay,
+		RetryableStatusCodes: []int{
+			http.StatusTooManyRequests,
+			http.StatusInternalServerError,
+			http.StatusBadGateway,
+			http.StatusServiceUnavailable,
+			http.StatusGatewayTimeout,
+		},
+		RespectRetryAfter: true,
+	}
+}
+
+// Validate returns an error if the policy contains negative values or a MaxDelay shorter than the BaseDelay.
+func (p RetryPolicy) Validate() error {
+	if p.MaxRetries < 0 {
+		return fmt.Errorf("MaxRetries cannot be negative")
+	}
+	if p.BaseDelay < 0 || p.MaxDelay < 0 {
+		return fmt.Errorf("BaseDelay and MaxDelay cannot be negative")
+	}
+	if p.MaxDelay > 0 && p.MaxDelay < p.BaseDelay {
+		return fmt.Errorf("MaxDelay (%s) cannot be shorter than BaseDelay (%s)", p.MaxDelay, p.BaseDelay)
+	}
+	return nil
+}
+
+// withDefaults returns a copy of the policy with unset fields populated from DefaultRetryPolicy
+func (p RetryPolicy) withDefaults() RetryPolicy {
+	defaults := DefaultRetryPolicy()
+	if p.BaseDelay == 0 {
+		p.BaseDelay = defaults.BaseDelay
+	}
+	if p.MaxDelay == 0 {
+		p.MaxDelay = defaults.MaxDelay
+		if p.MaxDelay < p.BaseDelay {
+			p.MaxDelay = p.BaseDelay
+		}
+	}
+	if len(p.RetryableStatusCodes) == 0 {
+		p.RetryableStatusCodes = defaults.RetryableStatusCodes
+	}
+	return p
+}
+
+// Backoff returns the delay before the specified retry, where zero is the first retry. When RespectRetryAfter is
+// set and resp carries a Retry-After header, the requested delay is returned instead.
+func (p RetryPolicy) Backoff(attempt int, resp *http.Response) time.Duration {
+	p = p.withDefaults()
+	if p.RespectRetryAfter && resp != nil {
+		if delay, ok := retryAfter(resp.Header.Get("Retry-After")); ok {
+			return delay
+		}
+	}
+	delay := p.BaseDelay
+	for i := 0; i < attempt; i++ {
+		delay *= 2
+		if delay >= p.MaxDelay || delay <= 0 {
+			return p.MaxDelay
+		}
+	}
+	if delay > p.MaxDelay {
+		return p.MaxDelay
+	}
+	return delay
+}
+
+// MethodRetryable returns whether requests using the specified HTTP method can be retried.
+func (p RetryPolicy) MethodRetryable(method string) bool {
+	if len(p.RetryableMethods) == 0 {
+		return true
+	}
+	for _, m := range p.RetryableMethods {
+		if strings.EqualFold(m, method) {
+			return true
+		}
+	}
+	return false
+}
+
+// StatusRetryable returns whether a response with the specified status code should be retried.
+func (p RetryPolicy) StatusRetryable(statusCode int) bool {
+	for _, c := range p.withDefaults().RetryableStatusCodes {
+		if c == statusCode {
+			return true
+		}
+	}
+	return false
+}
+
+// ErrorRetryable returns whether a request which failed without receiving a response should be retried. Only transient
+// network errors are retried, being timeouts, connections which were refused, reset or closed unexpectedly, and
+// temporary DNS failures. Other errors, such as a host which does not exist or an untrusted certificate, are not retried.
+func (p RetryPolicy) ErrorRetryable(err error) bool {
+	if err == nil || errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+
+	var dnsErr *net.DNSError
+	if errors.As(err, &dnsErr) {
+		return dnsErr.IsTemporary || dnsErr.IsTimeout
+	}
+
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return true
+	}
+
+	return errors.Is(err, syscall.ECONNRESET) ||
+		errors.Is(err, syscall.ECONNABORTED) ||
+		errors.Is(err, syscall.ECONNREFUSED) ||
+		errors.Is(err, io.EOF) ||
+		errors.Is(err, io.ErrUnexpectedEOF)
+}
+
+// retryAfter parses the value of a Retry-After header, which can be a number of seconds or an HTTP date
+func retryAfter(v string) (time.Duration, bool) {
+	if v == "" {
+		return 0, false
+	}
+	if secs, err := strconv.Atoi(v); err == nil {
+		if secs < 0 {
+			return 0, false
+		}
+		return time.Duration(secs) * time.Second, true
+	}
+	if t, err := http.ParseTime(v); err == nil {
+		if d := time.Until(t); d > 0 {
+			return d, true
+		}
+		return 0, true
+	}
+	return 0, false
+}
+
+// doWithRetries sends req using client, retrying according to policy. When policy is nil, the request is sent once.
+func doWithRetries(client *http.Client, req *http.Request, policy *RetryPolicy) (*http.Response, error) {
+	if policy == nil {
+		return client.Do(req)
+	}
+	p := policy.withDefaults()
+	ctx := req.Context()
+
+	for attempt := 0; ; attempt++ {
+		if attempt > 0 && req.GetBody != nil {
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, err
+			}
+			req.Body = body
+		}
+
+		resp, err := client.Do(req)
+		if attempt >= p.MaxRetries || !p.MethodRetryable(req.Method) || ctx.Err() != nil {
+			return resp, err
+		}
+		if err != nil && !p.ErrorRetryable(err) {
+			return nil, err
+		}
+		if err == nil && !p.StatusRetryable(resp.StatusCode) {
+			return resp, nil
+		}
+
+		delay := p.Backoff(attempt, resp)
+		if resp != nil {
+			_, _ = io.Copy(ioutil.Discard, io.LimitReader(resp.Body, 1<<20))
+			resp.Body.Close()
+		}
+
+		if err := sleep(ctx, delay); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// sleep waits for the specified duration, returning early with an error if ctx is cancelled
+func sleep(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
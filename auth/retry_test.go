@@ -0,0 +1,237 @@
+package auth_test
+
+import (
+	"context"
+	"crypto/x509"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/manicminer/hamilton/auth"
+	"github.com/manicminer/hamilton/environments"
+)
+
+func TestRetryPolicy_Backoff(t *testing.T) {
+	policy := auth.RetryPolicy{
+		MaxRetries: 6,
+		BaseDelay:  100 * time.Millisecond,
+		MaxDelay:   1 * time.Second,
+	}
+	expected := []time.Duration{
+		100 * time.Millisecond,
+		200 * time.Millisecond,
+		400 * time.Millisecond,
+		800 * time.Millisecond,
+		1 * time.Second,
+		1 * time.Second,
+	}
+	for attempt, want := range expected {
+		if got := policy.Backoff(attempt, nil); got != want {
+			t.Fatalf("Backoff(%d): expected %s, got %s", attempt, want, got)
+		}
+	}
+
+	if got := policy.Backoff(100, nil); got != policy.MaxDelay {
+		t.Fatalf("Backoff(100): expected delay to be capped at %s, got %s", policy.MaxDelay, got)
+	}
+
+	defaults := auth.RetryPolicy{}
+	if got := defaults.Backoff(0, nil); got != auth.DefaultRetryPolicy().BaseDelay {
+		t.Fatalf("Backoff(0): expected default base delay %s, got %s", auth.DefaultRetryPolicy().BaseDelay, got)
+	}
+}
+
+func TestRetryPolicy_RetryAfter(t *testing.T) {
+	resp := &http.Response{
+		StatusCode: http.StatusTooManyRequests,
+		Header:     http.Header{"Retry-After": []string{"5"}},
+	}
+
+	policy := auth.RetryPolicy{BaseDelay: 100 * time.Millisecond, MaxDelay: 1 * time.Second}
+	if got := policy.Backoff(0, resp); got != 100*time.Millisecond {
+		t.Fatalf("Backoff(): expected Retry-After to be ignored, got %s", got)
+	}
+
+	policy.RespectRetryAfter = true
+	if got := policy.Backoff(0, resp); got != 5*time.Second {
+		t.Fatalf("Backoff(): expected Retry-After delay of 5s, got %s", got)
+	}
+
+	resp.Header.Set("Retry-After", "invalid")
+	if got := policy.Backoff(1, resp); got != 200*time.Millisecond {
+		t.Fatalf("Backoff(): expected invalid Retry-After to be ignored, got %s", got)
+	}
+}
+
+func TestRetryPolicy_Validate(t *testing.T) {
+	for _, c := range []struct {
+		policy auth.RetryPolicy
+		valid  bool
+	}{
+		{policy: *auth.DefaultRetryPolicy(), valid: true},
+		{policy: auth.RetryPolicy{}, valid: true},
+		{policy: auth.RetryPolicy{MaxRetries: -1}, valid: false},
+		{policy: auth.RetryPolicy{BaseDelay: -time.Second}, valid: false},
+		{policy: auth.RetryPolicy{BaseDelay: time.Minute, MaxDelay: time.Second}, valid: false},
+	} {
+		err := c.policy.Validate()
+		if c.valid && err != nil {
+			t.Fatalf("Validate(%#v): unexpected error: %v", c.policy, err)
+		}
+		if !c.valid && err == nil {
+			t.Fatalf("Validate(%#v): expected error", c.policy)
+		}
+	}
+}
+
+func TestClientSecretAuthorizer_RetryPolicy(t *testing.T) {
+	for _, c := range []struct {
+		name     string
+		policy   *auth.RetryPolicy
+		attempts int
+		valid    bool
+	}{
+		{name: "default", policy: nil, attempts: 3, valid: true},
+		{name: "none", policy: &auth.RetryPolicy{}, attempts: 1, valid: false},
+		{name: "retries", policy: &auth.RetryPolicy{MaxRetries: 3, BaseDelay: time.Millisecond}, attempts: 3, valid: true},
+		{name: "exhausted", policy: &auth.RetryPolicy{MaxRetries: 1, BaseDelay: time.Millisecond}, attempts: 2, valid: false},
+		{name: "methods", policy: &auth.RetryPolicy{MaxRetries: 3, BaseDelay: time.Millisecond, RetryableMethods: []string{http.MethodGet}}, attempts: 1, valid: false},
+	} {
+		attempts := 0
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			attempts++
+			if err := r.ParseForm(); err != nil || r.PostForm.Get("client_secret") != "secret" {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			if attempts < 3 {
+				// Retry-After is only honored by the default policy, which would otherwise wait at least a second
+				w.Header().Set("Retry-After", "0")
+				w.WriteHeader(http.StatusServiceUnavailable)
+				return
+			}
+			w.Header().Set("Content-Type", "application/json")
+			fmt.Fprint(w, `{"access_token":"token","token_type":"Bearer","expires_in":3600}`)
+		}))
+
+		environment := environments.Global
+		environment.AzureADEndpoint = environments.AzureADEndpoint(server.URL)
+		config := auth.Config{
+			Environment:            environment,
+			TenantID:               "11111111-1111-1111-1111-111111111111",
+			ClientID:               "00000000-0000-0000-0000-000000000000",
+			ClientSecret:           "secret",
+			EnableClientSecretAuth: true,
+			RetryPolicy:            c.policy,
+		}
+		a, err := config.NewAuthorizer(context.Background(), auth.MsGraph)
+		if err != nil {
+			t.Fatalf("%s: NewAuthorizer(): %v", c.name, err)
+		}
+		_, err = a.Token()
+		server.Close()
+
+		if c.valid && err != nil {
+			t.Fatalf("%s: Token(): %v", c.name, err)
+		}
+		if !c.valid && err == nil {
+			t.Fatalf("%s: Token(): expected error", c.name)
+		}
+		if attempts != c.attempts {
+			t.Fatalf("%s: Token(): expected %d attempts, got %d", c.name, c.attempts, attempts)
+		}
+	}
+}
+
+func TestRetryPolicy_ErrorRetryable(t *testing.T) {
+	reset := &url.Error{Op: "Post", URL: "https://login.microsoftonline.com", Err: &net.OpError{Op: "read", Net: "tcp", Err: os.NewSyscallError("read", syscall.ECONNRESET)}}
+	for _, c := range []struct {
+		name      string
+		err       error
+		retryable bool
+	}{
+		{name: "reset", err: reset, retryable: true},
+		{name: "eof", err: &url.Error{Op: "Post", URL: "https://login.microsoftonline.com", Err: io.EOF}, retryable: true},
+		{name: "timeout", err: &net.OpError{Op: "dial", Net: "tcp", Err: &timeoutError{}}, retryable: true},
+		{name: "dns temporary", err: &net.DNSError{Err: "server misbehaving", Name: "login.microsoftonline.com", IsTemporary: true}, retryable: true},
+		{name: "dns not found", err: &net.DNSError{Err: "no such host", Name: "login.microsoftonline.com", IsNotFound: true}, retryable: false},
+		{name: "certificate", err: &url.Error{Op: "Post", URL: "https://login.microsoftonline.com", Err: x509.UnknownAuthorityError{}}, retryable: false},
+		{name: "cancelled", err: &url.Error{Op: "Post", URL: "https://login.microsoftonline.com", Err: context.Canceled}, retryable: false},
+	} {
+		if retryable := auth.DefaultRetryPolicy().ErrorRetryable(c.err); retryable != c.retryable {
+			t.Errorf("%s: ErrorRetryable(): expected %t, got %t", c.name, c.retryable, retryable)
+		}
+	}
+}
+
+type timeoutError struct{}
+
+func (timeoutError) Error() string   { return "i/o timeout" }
+func (timeoutError) Timeout() bool   { return true }
+func (timeoutError) Temporary() bool { return true }
+
+func TestClientSecretAuthorizer_RetryConnectionReset(t *testing.T) {
+	for _, c := range []struct {
+		name     string
+		policy   *auth.RetryPolicy
+		attempts int
+		valid    bool
+	}{
+		{name: "none", policy: &auth.RetryPolicy{}, attempts: 1, valid: false},
+		{name: "retries", policy: &auth.RetryPolicy{MaxRetries: 3, BaseDelay: time.Millisecond}, attempts: 3, valid: true},
+	} {
+		attempts := 0
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			attempts++
+			if attempts < 3 {
+				// Abruptly reset the connection without sending a response
+				conn, _, err := w.(http.Hijacker).Hijack()
+				if err != nil {
+					t.Errorf("Hijack(): %v", err)
+					return
+				}
+				if tcp, ok := conn.(*net.TCPConn); ok {
+					tcp.SetLinger(0)
+				}
+				conn.Close()
+				return
+			}
+			w.Header().Set("Content-Type", "application/json")
+			fmt.Fprint(w, `{"access_token":"token","token_type":"Bearer","expires_in":3600}`)
+		}))
+
+		environment := environments.Global
+		environment.AzureADEndpoint = environments.AzureADEndpoint(server.URL)
+		config := auth.Config{
+			Environment:            environment,
+			TenantID:               "11111111-1111-1111-1111-111111111111",
+			ClientID:               "00000000-0000-0000-0000-000000000000",
+			ClientSecret:           "secret",
+			EnableClientSecretAuth: true,
+			RetryPolicy:            c.policy,
+		}
+		a, err := config.NewAuthorizer(context.Background(), auth.MsGraph)
+		if err != nil {
+			t.Fatalf("%s: NewAuthorizer(): %v", c.name, err)
+		}
+		_, err = a.Token()
+		server.Close()
+
+		if c.valid && err != nil {
+			t.Fatalf("%s: Token(): %v", c.name, err)
+		}
+		if !c.valid && err == nil {
+			t.Fatalf("%s: Token(): expected error", c.name)
+		}
+		if attempts != c.attempts {
+			t.Fatalf("%s: Token(): expected %d attempts, got %d", c.name, c.attempts, attempts)
+		}
+	}
+}
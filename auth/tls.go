@@ -0,0 +1,31 @@
+package auth
+
+import (
+	"crypto/tls"
+	"net/http"
+	"time"
+)
+
+// DangerouslyInsecureTLSConfig returns a tls.Config which disables verification of server certificates and host
+// names. This makes connections vulnerable to interception, and must only be used when testing against an emulator
+// or mock service. To trust a private certificate authority, supply a tls.Config with RootCAs instead.
+func DangerouslyInsecureTLSConfig() *tls.Config {
+	return &tls.Config{InsecureSkipVerify: true}
+}
+
+// httpClient returns an http.Client using the specified TLS configuration and timeout. When tlsConfig is nil, the
+// default transport is used.
+func httpClient(tlsConfig *tls.Config, timeout time.Duration) *http.Client {
+	if tlsConfig == nil {
+		if timeout == 0 {
+			return http.DefaultClient
+		}
+		return &http.Client{Timeout: timeout}
+	}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.TLSClientConfig = tlsConfig
+	return &http.Client{
+		Timeout:   timeout,
+		Transport: transport,
+	}
+}
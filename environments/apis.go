@@ -13,21 +13,35 @@ const (
 	MsGraphUSGovL4Endpoint  ApiEndpoint = "https://graph.microsoft.us"
 	MsGraphUSGovL5Endpoint  ApiEndpoint = "https://dod-graph.microsoft.us"
 	MsGraphCanaryEndpoint   ApiEndpoint = "https://canary.graph.microsoft.com"
+
+	KeyVaultGlobalEndpoint  ApiEndpoint = "https://vault.azure.net"
+	KeyVaultGermanyEndpoint ApiEndpoint = "https://vault.microsoftazure.de"
+	KeyVaultChinaEndpoint   ApiEndpoint = "https://vault.azure.cn"
+	KeyVaultUSGovEndpoint   ApiEndpoint = "https://vault.usgovcloudapi.net"
+
+	ResourceManagerGlobalEndpoint  ApiEndpoint = "https://management.azure.com"
+	ResourceManagerGermanyEndpoint ApiEndpoint = "https://management.microsoftazure.de"
+	ResourceManagerChinaEndpoint   ApiEndpoint = "https://management.chinacloudapi.cn"
+	ResourceManagerUSGovEndpoint   ApiEndpoint = "https://management.usgovcloudapi.net"
+
+	// StorageEndpoint is the token audience for Azure Storage, which is the same in all clouds.
+	StorageEndpoint ApiEndpoint = "https://storage.azure.com"
 )
 
 type ApiCliName string
 
 const (
-	AadGraphCliName ApiCliName = "aad-graph"
-	MsGraphCliName  ApiCliName = "ms-graph"
+	AadGraphCliName        ApiCliName = "aad-graph"
+	MsGraphCliName         ApiCliName = "ms-graph"
+	ResourceManagerCliName ApiCliName = "arm"
 )
 
-// API represent an API configuration for Microsoft Graph or Azure Active Directory Graph.
+// API represent an API configuration, such as for Microsoft Graph or Azure Resource Manager.
 type Api struct {
 	// The Application ID for the API.
 	AppId ApiAppId
 
-	// The Azure CLI codename for the API. Used with `az account get-access-token`.
+	// The Azure CLI codename for the API. Used with `az account get-access-token`. Not all APIs have a codename.
 	CliName ApiCliName
 
 	// The endpoint for the API, including scheme.
@@ -94,4 +108,53 @@ var (
 		CliName:  AadGraphCliName,
 		Endpoint: AadGraphUSGovEndpoint,
 	}
+
+	KeyVaultGlobal = Api{
+		AppId:    PublishedApis["AzureKeyVault"],
+		Endpoint: KeyVaultGlobalEndpoint,
+	}
+
+	KeyVaultGermany = Api{
+		AppId:    PublishedApis["AzureKeyVault"],
+		Endpoint: KeyVaultGermanyEndpoint,
+	}
+
+	KeyVaultChina = Api{
+		AppId:    PublishedApis["AzureKeyVault"],
+		Endpoint: KeyVaultChinaEndpoint,
+	}
+
+	KeyVaultUSGov = Api{
+		AppId:    PublishedApis["AzureKeyVault"],
+		Endpoint: KeyVaultUSGovEndpoint,
+	}
+
+	ResourceManagerGlobal = Api{
+		AppId:    PublishedApis["AzureServiceManagement"],
+		CliName:  ResourceManagerCliName,
+		Endpoint: ResourceManagerGlobalEndpoint,
+	}
+
+	ResourceManagerGermany = Api{
+		AppId:    PublishedApis["AzureServiceManagement"],
+		CliName:  ResourceManagerCliName,
+		Endpoint: ResourceManagerGermanyEndpoint,
+	}
+
+	ResourceManagerChina = Api{
+		AppId:    PublishedApis["AzureServiceManagement"],
+		CliName:  ResourceManagerCliName,
+		Endpoint: ResourceManagerChinaEndpoint,
+	}
+
+	ResourceManagerUSGov = Api{
+		AppId:    PublishedApis["AzureServiceManagement"],
+		CliName:  ResourceManagerCliName,
+		Endpoint: ResourceManagerUSGovEndpoint,
+	}
+
+	Storage = Api{
+		AppId:    PublishedApis["AzureStorage"],
+		Endpoint: StorageEndpoint,
+	}
 )
@@ -10,6 +10,15 @@ type Environment struct {
 
 	// The Azure Active Directory Graph configuration for an environment.
 	AadGraph Api
+
+	// The Azure Resource Manager configuration for an environment.
+	ResourceManager Api
+
+	// The Azure Key Vault configuration for an environment.
+	KeyVault Api
+
+	// The Azure Storage configuration for an environment.
+	Storage Api
 }
 
 var (
@@ -17,34 +26,52 @@ var (
 		AzureADEndpoint: AzureADGlobal,
 		MsGraph:         MsGraphGlobal,
 		AadGraph:        AadGraphGlobal,
+		ResourceManager: ResourceManagerGlobal,
+		KeyVault:        KeyVaultGlobal,
+		Storage:         Storage,
 	}
 
 	Germany = Environment{
 		AzureADEndpoint: AzureADGermany,
 		MsGraph:         MsGraphGermany,
 		AadGraph:        AadGraphGermany,
+		ResourceManager: ResourceManagerGermany,
+		KeyVault:        KeyVaultGermany,
+		Storage:         Storage,
 	}
 
 	China = Environment{
 		AzureADEndpoint: AzureADChina,
 		MsGraph:         MsGraphChina,
 		AadGraph:        AadGraphChina,
+		ResourceManager: ResourceManagerChina,
+		KeyVault:        KeyVaultChina,
+		Storage:         Storage,
 	}
 
 	USGovernmentL4 = Environment{
 		AzureADEndpoint: AzureADUSGov,
 		MsGraph:         MsGraphUSGovL4,
 		AadGraph:        AadGraphUSGov,
+		ResourceManager: ResourceManagerUSGov,
+		KeyVault:        KeyVaultUSGov,
+		Storage:         Storage,
 	}
 
 	USGovernmentL5 = Environment{
 		AzureADEndpoint: AzureADUSGov,
 		MsGraph:         MsGraphUSGovL5,
 		AadGraph:        AadGraphUSGov,
+		ResourceManager: ResourceManagerUSGov,
+		KeyVault:        KeyVaultUSGov,
+		Storage:         Storage,
 	}
 
 	Canary = Environment{
 		AzureADEndpoint: AzureADGlobal,
 		MsGraph:         MsGraphCanary,
+		ResourceManager: ResourceManagerGlobal,
+		KeyVault:        KeyVaultGlobal,
+		Storage:         Storage,
 	}
 )
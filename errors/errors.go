@@ -12,3 +12,37 @@ type AlreadyExistsError struct {
 func (e AlreadyExistsError) Error() string {
 	return fmt.Sprintf("%s with ID %q already exists", e.Obj, e.Id)
 }
+
+// DelegatedAuthRequiredError is an error returned when an endpoint for the signed-in user, such as /me, is called
+// using an app-only token, which has no signed-in user.
+type DelegatedAuthRequiredError struct {
+	Entity string
+}
+
+// Error returns an error string for DelegatedAuthRequiredError.
+func (e DelegatedAuthRequiredError) Error() string {
+	return fmt.Sprintf("%s can only be called with a delegated token for a signed-in user, but an app-only token was used", e.Entity)
+}
+
+// LastOwnerError is an error returned when attempting to remove the last remaining owner of an object, which is
+// rejected by the API.
+type LastOwnerError struct {
+	Obj     string
+	Id      string
+	OwnerId string
+}
+
+// Error returns an error string for LastOwnerError.
+func (e LastOwnerError) Error() string {
+	return fmt.Sprintf("cannot remove owner %q from %s with ID %q as it is the last owner", e.OwnerId, e.Obj, e.Id)
+}
+
+// NoManagerError is an error returned when retrieving or removing the manager of a user who does not have a manager.
+type NoManagerError struct {
+	UserId string
+}
+
+// Error returns an error string for NoManagerError.
+func (e NoManagerError) Error() string {
+	return fmt.Sprintf("user with ID %q does not have a manager", e.UserId)
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"log"
 	"net/http"
+	"time"
 )
 
 func MsiStubServer(ctx context.Context, port int, token string) chan bool {
@@ -12,7 +13,8 @@ func MsiStubServer(ctx context.Context, port int, token string) chan bool {
 
 	handler.HandleFunc("/metadata/identity/oauth2/token", func(w http.ResponseWriter, r *http.Request) {
 		w.Header().Set("Content-Type", "application/json; charset=utf-8")
-		fmt.Fprintf(w, `{"access_token":"%s","client_id":"00000000-0000-0000-0000-000000000000","expires_in":"86391","expires_on":"1611701390","ext_expires_in":"86399","not_before":"1611614690","resource":"https://graph.microsoft.com/","token_type":"Bearer"}`, token)
+		now := time.Now()
+		fmt.Fprintf(w, `{"access_token":"%s","client_id":"00000000-0000-0000-0000-000000000000","expires_in":"86391","expires_on":"%d","ext_expires_in":"86399","not_before":"%d","resource":"https://graph.microsoft.com/","token_type":"Bearer"}`, token, now.Add(86391*time.Second).Unix(), now.Unix())
 	})
 
 	handler.HandleFunc("/metadata", func(w http.ResponseWriter, r *http.Request) {
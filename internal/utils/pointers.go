@@ -15,6 +15,11 @@ func Int32Ptr(i int32) *int32 {
 	return &i
 }
 
+// Int64Ptr returns a pointer to the provided int64 variable.
+func Int64Ptr(i int64) *int64 {
+	return &i
+}
+
 // StringPtr returns a pointer to the provided string variable.
 func StringPtr(s string) *string {
 	return &s
@@ -0,0 +1,369 @@
+package msgraph
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/manicminer/hamilton/odata"
+)
+
+// entitlementManagementEntity is the base path for Entitlement Management resources.
+const entitlementManagementEntity = "/identityGovernance/entitlementManagement"
+
+// AccessPackagesClient performs operations on AccessPackages, and the AccessPackageCatalogs and
+// AccessPackageAssignmentPolicies they belong to, in Entitlement Management.
+// Entitlement Management is only available in the beta API.
+type AccessPackagesClient struct {
+	BaseClient Client
+}
+
+// NewAccessPackagesClient returns a new AccessPackagesClient.
+func NewAccessPackagesClient(tenantId string) *AccessPackagesClient {
+	return &AccessPackagesClient{
+		BaseClient: NewClient(VersionBeta, tenantId),
+	}
+}
+
+// List returns a list of AccessPackages, optionally queried using OData.
+func (c *AccessPackagesClient) List(ctx context.Context, query odata.Query) (*[]AccessPackage, int, error) {
+	resp, status, _, err := c.BaseClient.Get(ctx, GetHttpRequestInput{
+		ConsistencyLevel: query.ConsistencyLevel,
+		DisablePaging:    query.Top > 0,
+		ValidStatusCodes: []int{http.StatusOK},
+		Uri: Uri{
+			Entity:      entitlementManagementEntity + "/accessPackages",
+			Params:      query.Values(),
+			HasTenantId: true,
+		},
+	})
+	if err != nil {
+		return nil, status, fmt.Errorf("AccessPackagesClient.BaseClient.Get(): %w", err)
+	}
+
+	defer resp.Body.Close()
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, status, fmt.Errorf("io.ReadAll(): %v", err)
+	}
+
+	var data struct {
+		AccessPackages []AccessPackage `json:"value"`
+	}
+	if err := c.BaseClient.decode(respBody, &data); err != nil {
+		return nil, status, fmt.Errorf("json.Unmarshal(): %v", err)
+	}
+
+	return &data.AccessPackages, status, nil
+}
+
+// Create creates a new AccessPackage. The CatalogId must be set to the ID of an existing
+// AccessPackageCatalog.
+func (c *AccessPackagesClient) Create(ctx context.Context, accessPackage AccessPackage) (*AccessPackage, int, error) {
+	var status int
+	body, err := json.Marshal(accessPackage)
+	if err != nil {
+		return nil, status, fmt.Errorf("json.Marshal(): %v", err)
+	}
+
+	resp, status, _, err := c.BaseClient.Post(ctx, PostHttpRequestInput{
+		Body:             body,
+		ValidStatusCodes: []int{http.StatusCreated},
+		Uri: Uri{
+			Entity:      entitlementManagementEntity + "/accessPackages",
+			HasTenantId: true,
+		},
+	})
+	if err != nil {
+		return nil, status, fmt.Errorf("AccessPackagesClient.BaseClient.Post(): %w", err)
+	}
+
+	defer resp.Body.Close()
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, status, fmt.Errorf("io.ReadAll(): %v", err)
+	}
+
+	var newAccessPackage AccessPackage
+	if err := c.BaseClient.decode(respBody, &newAccessPackage); err != nil {
+		return nil, status, fmt.Errorf("json.Unmarshal(): %v", err)
+	}
+
+	return &newAccessPackage, status, nil
+}
+
+// Get retrieves an AccessPackage.
+func (c *AccessPackagesClient) Get(ctx context.Context, id string, query odata.Query) (*AccessPackage, int, error) {
+	resp, status, _, err := c.BaseClient.Get(ctx, GetHttpRequestInput{
+		ConsistencyFailureFunc: RetryOn404ConsistencyFailureFunc,
+		ConsistencyLevel:       query.ConsistencyLevel,
+		ValidStatusCodes:       []int{http.StatusOK},
+		Uri: Uri{
+			Entity:      fmt.Sprintf("%s/accessPackages/%s", entitlementManagementEntity, id),
+			Params:      query.Values(),
+			HasTenantId: true,
+		},
+	})
+	if err != nil {
+		return nil, status, fmt.Errorf("AccessPackagesClient.BaseClient.Get(): %w", err)
+	}
+
+	defer resp.Body.Close()
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, status, fmt.Errorf("io.ReadAll(): %v", err)
+	}
+
+	var accessPackage AccessPackage
+	if err := c.BaseClient.decode(respBody, &accessPackage); err != nil {
+		return nil, status, fmt.Errorf("json.Unmarshal(): %v", err)
+	}
+
+	return &accessPackage, status, nil
+}
+
+// Delete removes an AccessPackage. Any assignment policies must be deleted first.
+func (c *AccessPackagesClient) Delete(ctx context.Context, id string) (int, error) {
+	_, status, _, err := c.BaseClient.Delete(ctx, DeleteHttpRequestInput{
+		ConsistencyFailureFunc: RetryOn404ConsistencyFailureFunc,
+		ValidStatusCodes:       []int{http.StatusNoContent},
+		Uri: Uri{
+			Entity:      fmt.Sprintf("%s/accessPackages/%s", entitlementManagementEntity, id),
+			HasTenantId: true,
+		},
+	})
+	if err != nil {
+		return status, fmt.Errorf("AccessPackagesClient.BaseClient.Delete(): %w", err)
+	}
+
+	return status, nil
+}
+
+// ListCatalogs returns a list of AccessPackageCatalogs, optionally queried using OData.
+func (c *AccessPackagesClient) ListCatalogs(ctx context.Context, query odata.Query) (*[]AccessPackageCatalog, int, error) {
+	resp, status, _, err := c.BaseClient.Get(ctx, GetHttpRequestInput{
+		ConsistencyLevel: query.ConsistencyLevel,
+		DisablePaging:    query.Top > 0,
+		ValidStatusCodes: []int{http.StatusOK},
+		Uri: Uri{
+			Entity:      entitlementManagementEntity + "/accessPackageCatalogs",
+			Params:      query.Values(),
+			HasTenantId: true,
+		},
+	})
+	if err != nil {
+		return nil, status, fmt.Errorf("AccessPackagesClient.BaseClient.Get(): %w", err)
+	}
+
+	defer resp.Body.Close()
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, status, fmt.Errorf("io.ReadAll(): %v", err)
+	}
+
+	var data struct {
+		Catalogs []AccessPackageCatalog `json:"value"`
+	}
+	if err := c.BaseClient.decode(respBody, &data); err != nil {
+		return nil, status, fmt.Errorf("json.Unmarshal(): %v", err)
+	}
+
+	return &data.Catalogs, status, nil
+}
+
+// CreateCatalog creates a new AccessPackageCatalog.
+func (c *AccessPackagesClient) CreateCatalog(ctx context.Context, catalog AccessPackageCatalog) (*AccessPackageCatalog, int, error) {
+	var status int
+	body, err := json.Marshal(catalog)
+	if err != nil {
+		return nil, status, fmt.Errorf("json.Marshal(): %v", err)
+	}
+
+	resp, status, _, err := c.BaseClient.Post(ctx, PostHttpRequestInput{
+		Body:             body,
+		ValidStatusCodes: []int{http.StatusCreated},
+		Uri: Uri{
+			Entity:      entitlementManagementEntity + "/accessPackageCatalogs",
+			HasTenantId: true,
+		},
+	})
+	if err != nil {
+		return nil, status, fmt.Errorf("AccessPackagesClient.BaseClient.Post(): %w", err)
+	}
+
+	defer resp.Body.Close()
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, status, fmt.Errorf("io.ReadAll(): %v", err)
+	}
+
+	var newCatalog AccessPackageCatalog
+	if err := c.BaseClient.decode(respBody, &newCatalog); err != nil {
+		return nil, status, fmt.Errorf("json.Unmarshal(): %v", err)
+	}
+
+	return &newCatalog, status, nil
+}
+
+// GetCatalog retrieves an AccessPackageCatalog.
+func (c *AccessPackagesClient) GetCatalog(ctx context.Context, id string, query odata.Query) (*AccessPackageCatalog, int, error) {
+	resp, status, _, err := c.BaseClient.Get(ctx, GetHttpRequestInput{
+		ConsistencyFailureFunc: RetryOn404ConsistencyFailureFunc,
+		ConsistencyLevel:       query.ConsistencyLevel,
+		ValidStatusCodes:       []int{http.StatusOK},
+		Uri: Uri{
+			Entity:      fmt.Sprintf("%s/accessPackageCatalogs/%s", entitlementManagementEntity, id),
+			Params:      query.Values(),
+			HasTenantId: true,
+		},
+	})
+	if err != nil {
+		return nil, status, fmt.Errorf("AccessPackagesClient.BaseClient.Get(): %w", err)
+	}
+
+	defer resp.Body.Close()
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, status, fmt.Errorf("io.ReadAll(): %v", err)
+	}
+
+	var catalog AccessPackageCatalog
+	if err := c.BaseClient.decode(respBody, &catalog); err != nil {
+		return nil, status, fmt.Errorf("json.Unmarshal(): %v", err)
+	}
+
+	return &catalog, status, nil
+}
+
+// DeleteCatalog removes an AccessPackageCatalog. Any access packages in the catalog must be deleted first.
+func (c *AccessPackagesClient) DeleteCatalog(ctx context.Context, id string) (int, error) {
+	_, status, _, err := c.BaseClient.Delete(ctx, DeleteHttpRequestInput{
+		ConsistencyFailureFunc: RetryOn404ConsistencyFailureFunc,
+		ValidStatusCodes:       []int{http.StatusNoContent},
+		Uri: Uri{
+			Entity:      fmt.Sprintf("%s/accessPackageCatalogs/%s", entitlementManagementEntity, id),
+			HasTenantId: true,
+		},
+	})
+	if err != nil {
+		return status, fmt.Errorf("AccessPackagesClient.BaseClient.Delete(): %w", err)
+	}
+
+	return status, nil
+}
+
+// ListAssignmentPolicies returns a list of AccessPackageAssignmentPolicies, optionally queried using OData.
+func (c *AccessPackagesClient) ListAssignmentPolicies(ctx context.Context, query odata.Query) (*[]AccessPackageAssignmentPolicy, int, error) {
+	resp, status, _, err := c.BaseClient.Get(ctx, GetHttpRequestInput{
+		ConsistencyLevel: query.ConsistencyLevel,
+		DisablePaging:    query.Top > 0,
+		ValidStatusCodes: []int{http.StatusOK},
+		Uri: Uri{
+			Entity:      entitlementManagementEntity + "/accessPackageAssignmentPolicies",
+			Params:      query.Values(),
+			HasTenantId: true,
+		},
+	})
+	if err != nil {
+		return nil, status, fmt.Errorf("AccessPackagesClient.BaseClient.Get(): %w", err)
+	}
+
+	defer resp.Body.Close()
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, status, fmt.Errorf("io.ReadAll(): %v", err)
+	}
+
+	var data struct {
+		Policies []AccessPackageAssignmentPolicy `json:"value"`
+	}
+	if err := c.BaseClient.decode(respBody, &data); err != nil {
+		return nil, status, fmt.Errorf("json.Unmarshal(): %v", err)
+	}
+
+	return &data.Policies, status, nil
+}
+
+// CreateAssignmentPolicy creates a new AccessPackageAssignmentPolicy. The AccessPackageId must be set to the ID of an
+// existing AccessPackage.
+func (c *AccessPackagesClient) CreateAssignmentPolicy(ctx context.Context, policy AccessPackageAssignmentPolicy) (*AccessPackageAssignmentPolicy, int, error) {
+	var status int
+	body, err := json.Marshal(policy)
+	if err != nil {
+		return nil, status, fmt.Errorf("json.Marshal(): %v", err)
+	}
+
+	resp, status, _, err := c.BaseClient.Post(ctx, PostHttpRequestInput{
+		Body:             body,
+		ValidStatusCodes: []int{http.StatusCreated},
+		Uri: Uri{
+			Entity:      entitlementManagementEntity + "/accessPackageAssignmentPolicies",
+			HasTenantId: true,
+		},
+	})
+	if err != nil {
+		return nil, status, fmt.Errorf("AccessPackagesClient.BaseClient.Post(): %w", err)
+	}
+
+	defer resp.Body.Close()
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, status, fmt.Errorf("io.ReadAll(): %v", err)
+	}
+
+	var newPolicy AccessPackageAssignmentPolicy
+	if err := c.BaseClient.decode(respBody, &newPolicy); err != nil {
+		return nil, status, fmt.Errorf("json.Unmarshal(): %v", err)
+	}
+
+	return &newPolicy, status, nil
+}
+
+// GetAssignmentPolicy retrieves an AccessPackageAssignmentPolicy.
+func (c *AccessPackagesClient) GetAssignmentPolicy(ctx context.Context, id string, query odata.Query) (*AccessPackageAssignmentPolicy, int, error) {
+	resp, status, _, err := c.BaseClient.Get(ctx, GetHttpRequestInput{
+		ConsistencyFailureFunc: RetryOn404ConsistencyFailureFunc,
+		ConsistencyLevel:       query.ConsistencyLevel,
+		ValidStatusCodes:       []int{http.StatusOK},
+		Uri: Uri{
+			Entity:      fmt.Sprintf("%s/accessPackageAssignmentPolicies/%s", entitlementManagementEntity, id),
+			Params:      query.Values(),
+			HasTenantId: true,
+		},
+	})
+	if err != nil {
+		return nil, status, fmt.Errorf("AccessPackagesClient.BaseClient.Get(): %w", err)
+	}
+
+	defer resp.Body.Close()
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, status, fmt.Errorf("io.ReadAll(): %v", err)
+	}
+
+	var policy AccessPackageAssignmentPolicy
+	if err := c.BaseClient.decode(respBody, &policy); err != nil {
+		return nil, status, fmt.Errorf("json.Unmarshal(): %v", err)
+	}
+
+	return &policy, status, nil
+}
+
+// DeleteAssignmentPolicy removes an AccessPackageAssignmentPolicy.
+func (c *AccessPackagesClient) DeleteAssignmentPolicy(ctx context.Context, id string) (int, error) {
+	_, status, _, err := c.BaseClient.Delete(ctx, DeleteHttpRequestInput{
+		ConsistencyFailureFunc: RetryOn404ConsistencyFailureFunc,
+		ValidStatusCodes:       []int{http.StatusNoContent},
+		Uri: Uri{
+			Entity:      fmt.Sprintf("%s/accessPackageAssignmentPolicies/%s", entitlementManagementEntity, id),
+			HasTenantId: true,
+		},
+	})
+	if err != nil {
+		return status, fmt.Errorf("AccessPackagesClient.BaseClient.Delete(): %w", err)
+	}
+
+	return status, nil
+}
@@ -0,0 +1,226 @@
+package msgraph_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/manicminer/hamilton/auth"
+	"github.com/manicminer/hamilton/internal/test"
+	"github.com/manicminer/hamilton/internal/utils"
+	"github.com/manicminer/hamilton/msgraph"
+	"github.com/manicminer/hamilton/odata"
+)
+
+type AccessPackagesClientTest struct {
+	connection   *test.Connection
+	client       *msgraph.AccessPackagesClient
+	randomString string
+}
+
+func TestAccessPackagesClient(t *testing.T) {
+	c := AccessPackagesClientTest{
+		connection:   test.NewConnection(auth.MsGraph, auth.TokenVersion2),
+		randomString: test.RandomString(),
+	}
+	c.client = msgraph.NewAccessPackagesClient(c.connection.AuthConfig.TenantID)
+	c.client.BaseClient.Authorizer = c.connection.Authorizer
+
+	catalog := testAccessPackagesClient_CreateCatalog(t, c, msgraph.AccessPackageCatalog{
+		DisplayName:         utils.StringPtr(fmt.Sprintf("test-catalog-%s", c.randomString)),
+		Description:         utils.StringPtr("test catalog"),
+		IsExternallyVisible: utils.BoolPtr(false),
+	})
+	testAccessPackagesClient_GetCatalog(t, c, *catalog.ID)
+	testAccessPackagesClient_ListCatalogs(t, c)
+
+	accessPackage := testAccessPackagesClient_Create(t, c, msgraph.AccessPackage{
+		CatalogId:   catalog.ID,
+		DisplayName: utils.StringPtr(fmt.Sprintf("test-access-package-%s", c.randomString)),
+		Description: utils.StringPtr("test access package"),
+		IsHidden:    utils.BoolPtr(true),
+	})
+	testAccessPackagesClient_Get(t, c, *accessPackage.ID)
+	testAccessPackagesClient_List(t, c)
+
+	scopeType := msgraph.RequestorSettingsScopeTypeNoSubjects
+	approvalMode := msgraph.ApprovalModeNoApproval
+	policy := testAccessPackagesClient_CreateAssignmentPolicy(t, c, msgraph.AccessPackageAssignmentPolicy{
+		AccessPackageId: accessPackage.ID,
+		DisplayName:     utils.StringPtr(fmt.Sprintf("test-policy-%s", c.randomString)),
+		Description:     utils.StringPtr("test assignment policy"),
+		DurationInDays:  utils.Int32Ptr(30),
+		RequestorSettings: &msgraph.RequestorSettings{
+			AcceptRequests: utils.BoolPtr(false),
+			ScopeType:      &scopeType,
+		},
+		RequestApprovalSettings: &msgraph.ApprovalSettings{
+			ApprovalMode:       &approvalMode,
+			IsApprovalRequired: utils.BoolPtr(false),
+		},
+	})
+	testAccessPackagesClient_GetAssignmentPolicy(t, c, *policy.ID)
+	testAccessPackagesClient_ListAssignmentPolicies(t, c)
+
+	testAccessPackagesClient_DeleteAssignmentPolicy(t, c, *policy.ID)
+	testAccessPackagesClient_Delete(t, c, *accessPackage.ID)
+	testAccessPackagesClient_DeleteCatalog(t, c, *catalog.ID)
+}
+
+func testAccessPackagesClient_Create(t *testing.T, c AccessPackagesClientTest, a msgraph.AccessPackage) (accessPackage *msgraph.AccessPackage) {
+	accessPackage, status, err := c.client.Create(c.connection.Context, a)
+	if err != nil {
+		t.Fatalf("AccessPackagesClient.Create(): %v", err)
+	}
+	if status < 200 || status >= 300 {
+		t.Fatalf("AccessPackagesClient.Create(): invalid status: %d", status)
+	}
+	if accessPackage == nil {
+		t.Fatal("AccessPackagesClient.Create(): accessPackage was nil")
+	}
+	if accessPackage.ID == nil {
+		t.Fatal("AccessPackagesClient.Create(): accessPackage.ID was nil")
+	}
+	return
+}
+
+func testAccessPackagesClient_Get(t *testing.T, c AccessPackagesClientTest, id string) (accessPackage *msgraph.AccessPackage) {
+	accessPackage, status, err := c.client.Get(c.connection.Context, id, odata.Query{})
+	if err != nil {
+		t.Fatalf("AccessPackagesClient.Get(): %v", err)
+	}
+	if status < 200 || status >= 300 {
+		t.Fatalf("AccessPackagesClient.Get(): invalid status: %d", status)
+	}
+	if accessPackage == nil {
+		t.Fatal("AccessPackagesClient.Get(): accessPackage was nil")
+	}
+	if accessPackage.IsHidden == nil || !*accessPackage.IsHidden {
+		t.Fatal("AccessPackagesClient.Get(): expected accessPackage.IsHidden to be true")
+	}
+	return
+}
+
+func testAccessPackagesClient_List(t *testing.T, c AccessPackagesClientTest) (accessPackages *[]msgraph.AccessPackage) {
+	accessPackages, _, err := c.client.List(c.connection.Context, odata.Query{Top: 10})
+	if err != nil {
+		t.Fatalf("AccessPackagesClient.List(): %v", err)
+	}
+	if accessPackages == nil {
+		t.Fatal("AccessPackagesClient.List(): accessPackages was nil")
+	}
+	return
+}
+
+func testAccessPackagesClient_Delete(t *testing.T, c AccessPackagesClientTest, id string) {
+	status, err := c.client.Delete(c.connection.Context, id)
+	if err != nil {
+		t.Fatalf("AccessPackagesClient.Delete(): %v", err)
+	}
+	if status < 200 || status >= 300 {
+		t.Fatalf("AccessPackagesClient.Delete(): invalid status: %d", status)
+	}
+}
+
+func testAccessPackagesClient_CreateCatalog(t *testing.T, c AccessPackagesClientTest, a msgraph.AccessPackageCatalog) (catalog *msgraph.AccessPackageCatalog) {
+	catalog, status, err := c.client.CreateCatalog(c.connection.Context, a)
+	if err != nil {
+		t.Fatalf("AccessPackagesClient.CreateCatalog(): %v", err)
+	}
+	if status < 200 || status >= 300 {
+		t.Fatalf("AccessPackagesClient.CreateCatalog(): invalid status: %d", status)
+	}
+	if catalog == nil {
+		t.Fatal("AccessPackagesClient.CreateCatalog(): catalog was nil")
+	}
+	if catalog.ID == nil {
+		t.Fatal("AccessPackagesClient.CreateCatalog(): catalog.ID was nil")
+	}
+	return
+}
+
+func testAccessPackagesClient_GetCatalog(t *testing.T, c AccessPackagesClientTest, id string) (catalog *msgraph.AccessPackageCatalog) {
+	catalog, status, err := c.client.GetCatalog(c.connection.Context, id, odata.Query{})
+	if err != nil {
+		t.Fatalf("AccessPackagesClient.GetCatalog(): %v", err)
+	}
+	if status < 200 || status >= 300 {
+		t.Fatalf("AccessPackagesClient.GetCatalog(): invalid status: %d", status)
+	}
+	if catalog == nil {
+		t.Fatal("AccessPackagesClient.GetCatalog(): catalog was nil")
+	}
+	return
+}
+
+func testAccessPackagesClient_ListCatalogs(t *testing.T, c AccessPackagesClientTest) (catalogs *[]msgraph.AccessPackageCatalog) {
+	catalogs, _, err := c.client.ListCatalogs(c.connection.Context, odata.Query{Top: 10})
+	if err != nil {
+		t.Fatalf("AccessPackagesClient.ListCatalogs(): %v", err)
+	}
+	if catalogs == nil {
+		t.Fatal("AccessPackagesClient.ListCatalogs(): catalogs was nil")
+	}
+	return
+}
+
+func testAccessPackagesClient_DeleteCatalog(t *testing.T, c AccessPackagesClientTest, id string) {
+	status, err := c.client.DeleteCatalog(c.connection.Context, id)
+	if err != nil {
+		t.Fatalf("AccessPackagesClient.DeleteCatalog(): %v", err)
+	}
+	if status < 200 || status >= 300 {
+		t.Fatalf("AccessPackagesClient.DeleteCatalog(): invalid status: %d", status)
+	}
+}
+
+func testAccessPackagesClient_CreateAssignmentPolicy(t *testing.T, c AccessPackagesClientTest, p msgraph.AccessPackageAssignmentPolicy) (policy *msgraph.AccessPackageAssignmentPolicy) {
+	policy, status, err := c.client.CreateAssignmentPolicy(c.connection.Context, p)
+	if err != nil {
+		t.Fatalf("AccessPackagesClient.CreateAssignmentPolicy(): %v", err)
+	}
+	if status < 200 || status >= 300 {
+		t.Fatalf("AccessPackagesClient.CreateAssignmentPolicy(): invalid status: %d", status)
+	}
+	if policy == nil {
+		t.Fatal("AccessPackagesClient.CreateAssignmentPolicy(): policy was nil")
+	}
+	if policy.ID == nil {
+		t.Fatal("AccessPackagesClient.CreateAssignmentPolicy(): policy.ID was nil")
+	}
+	return
+}
+
+func testAccessPackagesClient_GetAssignmentPolicy(t *testing.T, c AccessPackagesClientTest, id string) (policy *msgraph.AccessPackageAssignmentPolicy) {
+	policy, status, err := c.client.GetAssignmentPolicy(c.connection.Context, id, odata.Query{})
+	if err != nil {
+		t.Fatalf("AccessPackagesClient.GetAssignmentPolicy(): %v", err)
+	}
+	if status < 200 || status >= 300 {
+		t.Fatalf("AccessPackagesClient.GetAssignmentPolicy(): invalid status: %d", status)
+	}
+	if policy == nil {
+		t.Fatal("AccessPackagesClient.GetAssignmentPolicy(): policy was nil")
+	}
+	return
+}
+
+func testAccessPackagesClient_ListAssignmentPolicies(t *testing.T, c AccessPackagesClientTest) (policies *[]msgraph.AccessPackageAssignmentPolicy) {
+	policies, _, err := c.client.ListAssignmentPolicies(c.connection.Context, odata.Query{Top: 10})
+	if err != nil {
+		t.Fatalf("AccessPackagesClient.ListAssignmentPolicies(): %v", err)
+	}
+	if policies == nil {
+		t.Fatal("AccessPackagesClient.ListAssignmentPolicies(): policies was nil")
+	}
+	return
+}
+
+func testAccessPackagesClient_DeleteAssignmentPolicy(t *testing.T, c AccessPackagesClientTest, id string) {
+	status, err := c.client.DeleteAssignmentPolicy(c.connection.Context, id)
+	if err != nil {
+		t.Fatalf("AccessPackagesClient.DeleteAssignmentPolicy(): %v", err)
+	}
+	if status < 200 || status >= 300 {
+		t.Fatalf("AccessPackagesClient.DeleteAssignmentPolicy(): invalid status: %d", status)
+	}
+}
@@ -0,0 +1,191 @@
+package msgraph
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/manicminer/hamilton/odata"
+)
+
+// AccessReviewsClient performs operations on AccessReviewScheduleDefinitions and their instances.
+// Access reviews are only available in the beta API.
+type AccessReviewsClient struct {
+	BaseClient Client
+}
+
+// NewAccessReviewsClient returns a new AccessReviewsClient.
+func NewAccessReviewsClient(tenantId string) *AccessReviewsClient {
+	return &AccessReviewsClient{
+		BaseClient: NewClient(VersionBeta, tenantId),
+	}
+}
+
+// List returns a list of AccessReviewScheduleDefinitions, optionally queried using OData.
+func (c *AccessReviewsClient) List(ctx context.Context, query odata.Query) (*[]AccessReviewScheduleDefinition, int, error) {
+	resp, status, _, err := c.BaseClient.Get(ctx, GetHttpRequestInput{
+		ConsistencyLevel: query.ConsistencyLevel,
+		DisablePaging:    query.Top > 0,
+		ValidStatusCodes: []int{http.StatusOK},
+		Uri: Uri{
+			Entity:      "/identityGovernance/accessReviews/definitions",
+			Params:      query.Values(),
+			HasTenantId: true,
+		},
+	})
+	if err != nil {
+		return nil, status, fmt.Errorf("AccessReviewsClient.BaseClient.Get(): %w", err)
+	}
+
+	defer resp.Body.Close()
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, status, fmt.Errorf("io.ReadAll(): %v", err)
+	}
+
+	var data struct {
+		Definitions []AccessReviewScheduleDefinition `json:"value"`
+	}
+	if err := c.BaseClient.decode(respBody, &data); err != nil {
+		return nil, status, fmt.Errorf("json.Unmarshal(): %v", err)
+	}
+
+	return &data.Definitions, status, nil
+}
+
+// Create creates a new AccessReviewScheduleDefinition. When the definition has no recurrence, a single instance of
+// the review is started on the start date, otherwise instances are started according to the recurrence.
+func (c *AccessReviewsClient) Create(ctx context.Context, definition AccessReviewScheduleDefinition) (*AccessReviewScheduleDefinition, int, error) {
+	var status int
+	body, err := json.Marshal(definition)
+	if err != nil {
+		return nil, status, fmt.Errorf("json.Marshal(): %v", err)
+	}
+
+	resp, status, _, err := c.BaseClient.Post(ctx, PostHttpRequestInput{
+		Body:             body,
+		ValidStatusCodes: []int{http.StatusCreated},
+		Uri: Uri{
+			Entity:      "/identityGovernance/accessReviews/definitions",
+			HasTenantId: true,
+		},
+	})
+	if err != nil {
+		return nil, status, fmt.Errorf("AccessReviewsClient.BaseClient.Post(): %w", err)
+	}
+
+	defer resp.Body.Close()
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, status, fmt.Errorf("io.ReadAll(): %v", err)
+	}
+
+	var newDefinition AccessReviewScheduleDefinition
+	if err := c.BaseClient.decode(respBody, &newDefinition); err != nil {
+		return nil, status, fmt.Errorf("json.Unmarshal(): %v", err)
+	}
+
+	return &newDefinition, status, nil
+}
+
+// Get retrieves an AccessReviewScheduleDefinition.
+func (c *AccessReviewsClient) Get(ctx context.Context, id string, query odata.Query) (*AccessReviewScheduleDefinition, int, error) {
+	resp, status, _, err := c.BaseClient.Get(ctx, GetHttpRequestInput{
+		ConsistencyFailureFunc: RetryOn404ConsistencyFailureFunc,
+		ConsistencyLevel:       query.ConsistencyLevel,
+		ValidStatusCodes:       []int{http.StatusOK},
+		Uri: Uri{
+			Entity:      fmt.Sprintf("/identityGovernance/accessReviews/definitions/%s", id),
+			Params:      query.Values(),
+			HasTenantId: true,
+		},
+	})
+	if err != nil {
+		return nil, status, fmt.Errorf("AccessReviewsClient.BaseClient.Get(): %w", err)
+	}
+
+	defer resp.Body.Close()
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, status, fmt.Errorf("io.ReadAll(): %v", err)
+	}
+
+	var definition AccessReviewScheduleDefinition
+	if err := c.BaseClient.decode(respBody, &definition); err != nil {
+		return nil, status, fmt.Errorf("json.Unmarshal(): %v", err)
+	}
+
+	return &definition, status, nil
+}
+
+// Delete removes an AccessReviewScheduleDefinition, including all of its instances.
+func (c *AccessReviewsClient) Delete(ctx context.Context, id string) (int, error) {
+	_, status, _, err := c.BaseClient.Delete(ctx, DeleteHttpRequestInput{
+		ConsistencyFailureFunc: RetryOn404ConsistencyFailureFunc,
+		ValidStatusCodes:       []int{http.StatusNoContent},
+		Uri: Uri{
+			Entity:      fmt.Sprintf("/identityGovernance/accessReviews/definitions/%s", id),
+			HasTenantId: true,
+		},
+	})
+	if err != nil {
+		return status, fmt.Errorf("AccessReviewsClient.BaseClient.Delete(): %w", err)
+	}
+
+	return status, nil
+}
+
+// ListInstances returns the instances of an AccessReviewScheduleDefinition, optionally queried using OData.
+// definitionId is the ID of the access review definition.
+func (c *AccessReviewsClient) ListInstances(ctx context.Context, definitionId string, query odata.Query) (*[]AccessReviewInstance, int, error) {
+	resp, status, _, err := c.BaseClient.Get(ctx, GetHttpRequestInput{
+		ConsistencyFailureFunc: RetryOn404ConsistencyFailureFunc,
+		ConsistencyLevel:       query.ConsistencyLevel,
+		DisablePaging:          query.Top > 0,
+		ValidStatusCodes:       []int{http.StatusOK},
+		Uri: Uri{
+			Entity:      fmt.Sprintf("/identityGovernance/accessReviews/definitions/%s/instances", definitionId),
+			Params:      query.Values(),
+			HasTenantId: true,
+		},
+	})
+	if err != nil {
+		return nil, status, fmt.Errorf("AccessReviewsClient.BaseClient.Get(): %w", err)
+	}
+
+	defer resp.Body.Close()
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, status, fmt.Errorf("io.ReadAll(): %v", err)
+	}
+
+	var data struct {
+		Instances []AccessReviewInstance `json:"value"`
+	}
+	if err := c.BaseClient.decode(respBody, &data); err != nil {
+		return nil, status, fmt.Errorf("json.Unmarshal(): %v", err)
+	}
+
+	return &data.Instances, status, nil
+}
+
+// StopInstance stops an in-progress instance of an access review, so that no further decisions can be recorded.
+// definitionId is the ID of the access review definition.
+// instanceId is the ID of the instance to stop.
+func (c *AccessReviewsClient) StopInstance(ctx context.Context, definitionId, instanceId string) (int, error) {
+	_, status, _, err := c.BaseClient.Post(ctx, PostHttpRequestInput{
+		ConsistencyFailureFunc: RetryOn404ConsistencyFailureFunc,
+		ValidStatusCodes:       []int{http.StatusNoContent},
+		Uri: Uri{
+			Entity:      fmt.Sprintf("/identityGovernance/accessReviews/definitions/%s/instances/%s/stop", definitionId, instanceId),
+			HasTenantId: true,
+		},
+	})
+	if err != nil {
+		return status, fmt.Errorf("AccessReviewsClient.BaseClient.Post(): %w", err)
+	}
+
+	return status, nil
+}
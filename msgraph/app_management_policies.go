@@ -0,0 +1,330 @@
+package msgraph
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/manicminer/hamilton/odata"
+)
+
+// AppManagementPoliciesClient performs operations on AppManagementPolicies and the TenantAppManagementPolicy.
+type AppManagementPoliciesClient struct {
+	BaseClient Client
+}
+
+// NewAppManagementPoliciesClient returns a new AppManagementPoliciesClient.
+func NewAppManagementPoliciesClient(tenantId string) *AppManagementPoliciesClient {
+	return &AppManagementPoliciesClient{
+		BaseClient: NewClient(Version10, tenantId),
+	}
+}
+
+// List returns a list of AppManagementPolicies, optionally queried using OData.
+func (c *AppManagementPoliciesClient) List(ctx context.Context, query odata.Query) (*[]AppManagementPolicy, int, error) {
+	resp, status, _, err := c.BaseClient.Get(ctx, GetHttpRequestInput{
+		ConsistencyLevel: query.ConsistencyLevel,
+		DisablePaging:    query.Top > 0,
+		ValidStatusCodes: []int{http.StatusOK},
+		Uri: Uri{
+			Entity:      "/policies/appManagementPolicies",
+			Params:      query.Values(),
+			HasTenantId: true,
+		},
+	})
+	if err != nil {
+		return nil, status, fmt.Errorf("AppManagementPoliciesClient.BaseClient.Get(): %w", err)
+	}
+
+	defer resp.Body.Close()
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, status, fmt.Errorf("io.ReadAll(): %v", err)
+	}
+
+	var data struct {
+		Policies []AppManagementPolicy `json:"value"`
+	}
+	if err := c.BaseClient.decode(respBody, &data); err != nil {
+		return nil, status, fmt.Errorf("json.Unmarshal(): %v", err)
+	}
+
+	return &data.Policies, status, nil
+}
+
+// Create creates a new AppManagementPolicy.
+func (c *AppManagementPoliciesClient) Create(ctx context.Context, policy AppManagementPolicy) (*AppManagementPolicy, int, error) {
+	var status int
+
+	body, err := json.Marshal(policy)
+	if err != nil {
+		return nil, status, fmt.Errorf("json.Marshal(): %v", err)
+	}
+
+	resp, status, _, err := c.BaseClient.Post(ctx, PostHttpRequestInput{
+		Body:             body,
+		ValidStatusCodes: []int{http.StatusCreated},
+		Uri: Uri{
+			Entity:      "/policies/appManagementPolicies",
+			HasTenantId: true,
+		},
+	})
+	if err != nil {
+		return nil, status, fmt.Errorf("AppManagementPoliciesClient.BaseClient.Post(): %w", err)
+	}
+
+	defer resp.Body.Close()
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, status, fmt.Errorf("io.ReadAll(): %v", err)
+	}
+
+	var newPolicy AppManagementPolicy
+	if err := c.BaseClient.decode(respBody, &newPolicy); err != nil {
+		return nil, status, fmt.Errorf("json.Unmarshal(): %v", err)
+	}
+
+	return &newPolicy, status, nil
+}
+
+// Get retrieves an AppManagementPolicy.
+func (c *AppManagementPoliciesClient) Get(ctx context.Context, id string, query odata.Query) (*AppManagementPolicy, int, error) {
+	resp, status, _, err := c.BaseClient.Get(ctx, GetHttpRequestInput{
+		ConsistencyFailureFunc: RetryOn404ConsistencyFailureFunc,
+		ConsistencyLevel:       query.ConsistencyLevel,
+		ValidStatusCodes:       []int{http.StatusOK},
+		Uri: Uri{
+			Entity:      fmt.Sprintf("/policies/appManagementPolicies/%s", id),
+			Params:      query.Values(),
+			HasTenantId: true,
+		},
+	})
+	if err != nil {
+		return nil, status, fmt.Errorf("AppManagementPoliciesClient.BaseClient.Get(): %w", err)
+	}
+
+	defer resp.Body.Close()
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, status, fmt.Errorf("io.ReadAll(): %v", err)
+	}
+
+	var policy AppManagementPolicy
+	if err := c.BaseClient.decode(respBody, &policy); err != nil {
+		return nil, status, fmt.Errorf("json.Unmarshal(): %v", err)
+	}
+
+	return &policy, status, nil
+}
+
+// Update amends an existing AppManagementPolicy.
+func (c *AppManagementPoliciesClient) Update(ctx context.Context, policy AppManagementPolicy) (int, error) {
+	var status int
+
+	if policy.ID == nil {
+		return status, errors.New("AppManagementPoliciesClient.Update(): cannot update policy with nil ID")
+	}
+
+	body, err := json.Marshal(policy)
+	if err != nil {
+		return status, fmt.Errorf("json.Marshal(): %v", err)
+	}
+
+	_, status, _, err = c.BaseClient.Patch(ctx, PatchHttpRequestInput{
+		Body:                   body,
+		ConsistencyFailureFunc: RetryOn404ConsistencyFailureFunc,
+		ValidStatusCodes:       []int{http.StatusNoContent},
+		Uri: Uri{
+			Entity:      fmt.Sprintf("/policies/appManagementPolicies/%s", *policy.ID),
+			HasTenantId: true,
+		},
+	})
+	if err != nil {
+		return status, fmt.Errorf("AppManagementPoliciesClient.BaseClient.Patch(): %w", err)
+	}
+
+	return status, nil
+}
+
+// Delete removes an AppManagementPolicy.
+func (c *AppManagementPoliciesClient) Delete(ctx context.Context, id string) (int, error) {
+	_, status, _, err := c.BaseClient.Delete(ctx, DeleteHttpRequestInput{
+		ConsistencyFailureFunc: RetryOn404ConsistencyFailureFunc,
+		ValidStatusCodes:       []int{http.StatusNoContent},
+		Uri: Uri{
+			Entity:      fmt.Sprintf("/policies/appManagementPolicies/%s", id),
+			HasTenantId: true,
+		},
+	})
+	if err != nil {
+		return status, fmt.Errorf("AppManagementPoliciesClient.BaseClient.Delete(): %w", err)
+	}
+
+	return status, nil
+}
+
+// ListAppliesTo returns the applications and service principals to which an AppManagementPolicy is assigned.
+func (c *AppManagementPoliciesClient) ListAppliesTo(ctx context.Context, id string, query odata.Query) (*[]DirectoryObject, int, error) {
+	resp, status, _, err := c.BaseClient.Get(ctx, GetHttpRequestInput{
+		ConsistencyFailureFunc: RetryOn404ConsistencyFailureFunc,
+		ConsistencyLevel:       query.ConsistencyLevel,
+		DisablePaging:          query.Top > 0,
+		ValidStatusCodes:       []int{http.StatusOK},
+		Uri: Uri{
+			Entity:      fmt.Sprintf("/policies/appManagementPolicies/%s/appliesTo", id),
+			Params:      query.Values(),
+			HasTenantId: true,
+		},
+	})
+	if err != nil {
+		return nil, status, fmt.Errorf("AppManagementPoliciesClient.BaseClient.Get(): %w", err)
+	}
+
+	defer resp.Body.Close()
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, status, fmt.Errorf("io.ReadAll(): %v", err)
+	}
+
+	var data struct {
+		Objects []DirectoryObject `json:"value"`
+	}
+	if err := c.BaseClient.decode(respBody, &data); err != nil {
+		return nil, status, fmt.Errorf("json.Unmarshal(): %v", err)
+	}
+
+	return &data.Objects, status, nil
+}
+
+// AssignToApplication applies an AppManagementPolicy to an application. An application can have at most one policy.
+// id is the ID of the policy.
+// applicationId is the object ID of the application.
+func (c *AppManagementPoliciesClient) AssignToApplication(ctx context.Context, id, applicationId string) (int, error) {
+	return c.assign(ctx, id, fmt.Sprintf("/applications/%s", applicationId))
+}
+
+// RemoveFromApplication removes an AppManagementPolicy from an application.
+// id is the ID of the policy.
+// applicationId is the object ID of the application.
+func (c *AppManagementPoliciesClient) RemoveFromApplication(ctx context.Context, id, applicationId string) (int, error) {
+	return c.remove(ctx, id, fmt.Sprintf("/applications/%s", applicationId))
+}
+
+// AssignToServicePrincipal applies an AppManagementPolicy to a service principal. A service principal can have at
+// most one policy.
+// id is the ID of the policy.
+// servicePrincipalId is the object ID of the service principal.
+func (c *AppManagementPoliciesClient) AssignToServicePrincipal(ctx context.Context, id, servicePrincipalId string) (int, error) {
+	return c.assign(ctx, id, fmt.Sprintf("/servicePrincipals/%s", servicePrincipalId))
+}
+
+// RemoveFromServicePrincipal removes an AppManagementPolicy from a service principal.
+// id is the ID of the policy.
+// servicePrincipalId is the object ID of the service principal.
+func (c *AppManagementPoliciesClient) RemoveFromServicePrincipal(ctx context.Context, id, servicePrincipalId string) (int, error) {
+	return c.remove(ctx, id, fmt.Sprintf("/servicePrincipals/%s", servicePrincipalId))
+}
+
+// assign adds a reference to the policy to the appManagementPolicies of the object at entity
+func (c *AppManagementPoliciesClient) assign(ctx context.Context, id, entity string) (int, error) {
+	var status int
+
+	data := struct {
+		Policy odata.Id `json:"@odata.id"`
+	}{
+		Policy: odata.Id(fmt.Sprintf("%s/%s/policies/appManagementPolicies/%s", c.BaseClient.Endpoint, c.BaseClient.ApiVersion, id)),
+	}
+	body, err := json.Marshal(data)
+	if err != nil {
+		return status, fmt.Errorf("json.Marshal(): %v", err)
+	}
+
+	_, status, _, err = c.BaseClient.Post(ctx, PostHttpRequestInput{
+		Body:                   body,
+		ConsistencyFailureFunc: RetryOn404ConsistencyFailureFunc,
+		ValidStatusCodes:       []int{http.StatusNoContent},
+		Uri: Uri{
+			Entity:      fmt.Sprintf("%s/appManagementPolicies/$ref", entity),
+			HasTenantId: true,
+		},
+	})
+	if err != nil {
+		return status, fmt.Errorf("AppManagementPoliciesClient.BaseClient.Post(): %w", err)
+	}
+
+	return status, nil
+}
+
+// remove deletes the reference to the policy from the appManagementPolicies of the object at entity
+func (c *AppManagementPoliciesClient) remove(ctx context.Context, id, entity string) (int, error) {
+	_, status, _, err := c.BaseClient.Delete(ctx, DeleteHttpRequestInput{
+		ConsistencyFailureFunc: RetryOn404ConsistencyFailureFunc,
+		ValidStatusCodes:       []int{http.StatusNoContent},
+		Uri: Uri{
+			Entity:      fmt.Sprintf("%s/appManagementPolicies/%s/$ref", entity, id),
+			HasTenantId: true,
+		},
+	})
+	if err != nil {
+		return status, fmt.Errorf("AppManagementPoliciesClient.BaseClient.Delete(): %w", err)
+	}
+
+	return status, nil
+}
+
+// GetDefault retrieves the TenantAppManagementPolicy, which applies to all applications and service principals
+// without an assigned AppManagementPolicy.
+func (c *AppManagementPoliciesClient) GetDefault(ctx context.Context, query odata.Query) (*TenantAppManagementPolicy, int, error) {
+	resp, status, _, err := c.BaseClient.Get(ctx, GetHttpRequestInput{
+		ConsistencyLevel: query.ConsistencyLevel,
+		ValidStatusCodes: []int{http.StatusOK},
+		Uri: Uri{
+			Entity:      "/policies/defaultAppManagementPolicy",
+			Params:      query.Values(),
+			HasTenantId: true,
+		},
+	})
+	if err != nil {
+		return nil, status, fmt.Errorf("AppManagementPoliciesClient.BaseClient.Get(): %w", err)
+	}
+
+	defer resp.Body.Close()
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, status, fmt.Errorf("io.ReadAll(): %v", err)
+	}
+
+	var policy TenantAppManagementPolicy
+	if err := c.BaseClient.decode(respBody, &policy); err != nil {
+		return nil, status, fmt.Errorf("json.Unmarshal(): %v", err)
+	}
+
+	return &policy, status, nil
+}
+
+// UpdateDefault amends the TenantAppManagementPolicy.
+func (c *AppManagementPoliciesClient) UpdateDefault(ctx context.Context, policy TenantAppManagementPolicy) (int, error) {
+	var status int
+
+	body, err := json.Marshal(policy)
+	if err != nil {
+		return status, fmt.Errorf("json.Marshal(): %v", err)
+	}
+
+	_, status, _, err = c.BaseClient.Patch(ctx, PatchHttpRequestInput{
+		Body:             body,
+		ValidStatusCodes: []int{http.StatusNoContent},
+		Uri: Uri{
+			Entity:      "/policies/defaultAppManagementPolicy",
+			HasTenantId: true,
+		},
+	})
+	if err != nil {
+		return status, fmt.Errorf("AppManagementPoliciesClient.BaseClient.Patch(): %w", err)
+	}
+
+	return status, nil
+}
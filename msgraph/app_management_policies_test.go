@@ -0,0 +1,180 @@
+package msgraph_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/manicminer/hamilton/auth"
+	"github.com/manicminer/hamilton/internal/test"
+	"github.com/manicminer/hamilton/internal/utils"
+	"github.com/manicminer/hamilton/msgraph"
+	"github.com/manicminer/hamilton/odata"
+)
+
+type AppManagementPoliciesClientTest struct {
+	connection   *test.Connection
+	client       *msgraph.AppManagementPoliciesClient
+	randomString string
+}
+
+func TestAppManagementPoliciesClient(t *testing.T) {
+	rs := test.RandomString()
+	c := AppManagementPoliciesClientTest{
+		connection:   test.NewConnection(auth.MsGraph, auth.TokenVersion2),
+		randomString: rs,
+	}
+	c.client = msgraph.NewAppManagementPoliciesClient(c.connection.AuthConfig.TenantID)
+	c.client.BaseClient.Authorizer = c.connection.Authorizer
+
+	a := ApplicationsClientTest{
+		connection:   test.NewConnection(auth.MsGraph, auth.TokenVersion2),
+		randomString: rs,
+	}
+	a.client = msgraph.NewApplicationsClient(a.connection.AuthConfig.TenantID)
+	a.client.BaseClient.Authorizer = a.connection.Authorizer
+
+	app := testApplicationsClient_Create(t, a, msgraph.Application{
+		DisplayName: utils.StringPtr(fmt.Sprintf("test-application-appManagementPolicy-%s", c.randomString)),
+	})
+
+	passwordLifetime := msgraph.AppCredentialRestrictionPasswordLifetime
+	policy := testAppManagementPoliciesClient_Create(t, c, msgraph.AppManagementPolicy{
+		DisplayName: utils.StringPtr(fmt.Sprintf("test-appManagementPolicy-%s", c.randomString)),
+		Description: utils.StringPtr("restrict secrets to 90 days"),
+		IsEnabled:   utils.BoolPtr(true),
+		Restrictions: &msgraph.AppManagementConfiguration{
+			PasswordCredentials: &[]msgraph.PasswordCredentialConfiguration{
+				{
+					RestrictionType: &passwordLifetime,
+					MaxLifetime:     utils.StringPtr("P90D"),
+				},
+			},
+		},
+	})
+	testAppManagementPoliciesClient_Get(t, c, *policy.ID)
+	policy.Description = utils.StringPtr("restrict secrets to 90 days (updated)")
+	testAppManagementPoliciesClient_Update(t, c, *policy)
+	testAppManagementPoliciesClient_List(t, c)
+	testAppManagementPoliciesClient_AssignToApplication(t, c, *policy.ID, *app.ID)
+	testAppManagementPoliciesClient_ListAppliesTo(t, c, *policy.ID, *app.ID)
+	testAppManagementPoliciesClient_RemoveFromApplication(t, c, *policy.ID, *app.ID)
+	testAppManagementPoliciesClient_Delete(t, c, *policy.ID)
+	testAppManagementPoliciesClient_GetDefault(t, c)
+
+	testApplicationsClient_Delete(t, a, *app.ID)
+}
+
+func testAppManagementPoliciesClient_Create(t *testing.T, c AppManagementPoliciesClientTest, p msgraph.AppManagementPolicy) (policy *msgraph.AppManagementPolicy) {
+	policy, status, err := c.client.Create(c.connection.Context, p)
+	if err != nil {
+		t.Fatalf("AppManagementPoliciesClient.Create(): %v", err)
+	}
+	if status < 200 || status >= 300 {
+		t.Fatalf("AppManagementPoliciesClient.Create(): invalid status: %d", status)
+	}
+	if policy == nil {
+		t.Fatal("AppManagementPoliciesClient.Create(): policy was nil")
+	}
+	if policy.ID == nil {
+		t.Fatal("AppManagementPoliciesClient.Create(): policy.ID was nil")
+	}
+	return
+}
+
+func testAppManagementPoliciesClient_Get(t *testing.T, c AppManagementPoliciesClientTest, id string) (policy *msgraph.AppManagementPolicy) {
+	policy, status, err := c.client.Get(c.connection.Context, id, odata.Query{})
+	if err != nil {
+		t.Fatalf("AppManagementPoliciesClient.Get(): %v", err)
+	}
+	if status < 200 || status >= 300 {
+		t.Fatalf("AppManagementPoliciesClient.Get(): invalid status: %d", status)
+	}
+	if policy == nil {
+		t.Fatal("AppManagementPoliciesClient.Get(): policy was nil")
+	}
+	if policy.Restrictions == nil || policy.Restrictions.PasswordCredentials == nil || len(*policy.Restrictions.PasswordCredentials) != 1 {
+		t.Fatal("AppManagementPoliciesClient.Get(): expected one password credential restriction")
+	}
+	return
+}
+
+func testAppManagementPoliciesClient_Update(t *testing.T, c AppManagementPoliciesClientTest, p msgraph.AppManagementPolicy) {
+	status, err := c.client.Update(c.connection.Context, p)
+	if err != nil {
+		t.Fatalf("AppManagementPoliciesClient.Update(): %v", err)
+	}
+	if status < 200 || status >= 300 {
+		t.Fatalf("AppManagementPoliciesClient.Update(): invalid status: %d", status)
+	}
+}
+
+func testAppManagementPoliciesClient_List(t *testing.T, c AppManagementPoliciesClientTest) (policies *[]msgraph.AppManagementPolicy) {
+	policies, _, err := c.client.List(c.connection.Context, odata.Query{Top: 10})
+	if err != nil {
+		t.Fatalf("AppManagementPoliciesClient.List(): %v", err)
+	}
+	if policies == nil {
+		t.Fatal("AppManagementPoliciesClient.List(): policies was nil")
+	}
+	return
+}
+
+func testAppManagementPoliciesClient_AssignToApplication(t *testing.T, c AppManagementPoliciesClientTest, id, applicationId string) {
+	status, err := c.client.AssignToApplication(c.connection.Context, id, applicationId)
+	if err != nil {
+		t.Fatalf("AppManagementPoliciesClient.AssignToApplication(): %v", err)
+	}
+	if status < 200 || status >= 300 {
+		t.Fatalf("AppManagementPoliciesClient.AssignToApplication(): invalid status: %d", status)
+	}
+}
+
+func testAppManagementPoliciesClient_ListAppliesTo(t *testing.T, c AppManagementPoliciesClientTest, id, applicationId string) {
+	objects, _, err := c.client.ListAppliesTo(c.connection.Context, id, odata.Query{})
+	if err != nil {
+		t.Fatalf("AppManagementPoliciesClient.ListAppliesTo(): %v", err)
+	}
+	if objects == nil {
+		t.Fatal("AppManagementPoliciesClient.ListAppliesTo(): objects was nil")
+	}
+	for _, o := range *objects {
+		if o.ID != nil && *o.ID == applicationId {
+			return
+		}
+	}
+	t.Fatalf("AppManagementPoliciesClient.ListAppliesTo(): application %q was not found", applicationId)
+}
+
+func testAppManagementPoliciesClient_RemoveFromApplication(t *testing.T, c AppManagementPoliciesClientTest, id, applicationId string) {
+	status, err := c.client.RemoveFromApplication(c.connection.Context, id, applicationId)
+	if err != nil {
+		t.Fatalf("AppManagementPoliciesClient.RemoveFromApplication(): %v", err)
+	}
+	if status < 200 || status >= 300 {
+		t.Fatalf("AppManagementPoliciesClient.RemoveFromApplication(): invalid status: %d", status)
+	}
+}
+
+func testAppManagementPoliciesClient_Delete(t *testing.T, c AppManagementPoliciesClientTest, id string) {
+	status, err := c.client.Delete(c.connection.Context, id)
+	if err != nil {
+		t.Fatalf("AppManagementPoliciesClient.Delete(): %v", err)
+	}
+	if status < 200 || status >= 300 {
+		t.Fatalf("AppManagementPoliciesClient.Delete(): invalid status: %d", status)
+	}
+}
+
+func testAppManagementPoliciesClient_GetDefault(t *testing.T, c AppManagementPoliciesClientTest) (policy *msgraph.TenantAppManagementPolicy) {
+	policy, status, err := c.client.GetDefault(c.connection.Context, odata.Query{})
+	if err != nil {
+		t.Fatalf("AppManagementPoliciesClient.GetDefault(): %v", err)
+	}
+	if status < 200 || status >= 300 {
+		t.Fatalf("AppManagementPoliciesClient.GetDefault(): invalid status: %d", status)
+	}
+	if policy == nil {
+		t.Fatal("AppManagementPoliciesClient.GetDefault(): policy was nil")
+	}
+	return
+}
@@ -56,7 +56,7 @@ func (c *AppRoleAssignmentsClient) List(ctx context.Context, id string) (*[]AppR
 		},
 	})
 	if err != nil {
-		return nil, status, fmt.Errorf("AppRoleAssignmentsClient.BaseClient.Get(): %v", err)
+		return nil, status, fmt.Errorf("AppRoleAssignmentsClient.BaseClient.Get(): %w", err)
 	}
 
 	defer resp.Body.Close()
@@ -68,7 +68,7 @@ func (c *AppRoleAssignmentsClient) List(ctx context.Context, id string) (*[]AppR
 	var data struct {
 		AppRoleAssignments []AppRoleAssignment `json:"value"`
 	}
-	if err := json.Unmarshal(respBody, &data); err != nil {
+	if err := c.BaseClient.decode(respBody, &data); err != nil {
 		return nil, status, fmt.Errorf("json.Unmarshal(): %v", err)
 	}
 
@@ -86,7 +86,7 @@ func (c *AppRoleAssignmentsClient) Remove(ctx context.Context, id, appRoleAssign
 		},
 	})
 	if err != nil {
-		return status, fmt.Errorf("AppRoleAssignmentsClient.BaseClient.Delete(): %v", err)
+		return status, fmt.Errorf("AppRoleAssignmentsClient.BaseClient.Delete(): %w", err)
 	}
 
 	return status, nil
@@ -121,7 +121,7 @@ func (c *AppRoleAssignmentsClient) Assign(ctx context.Context, clientServicePrin
 		},
 	})
 	if err != nil {
-		return nil, status, fmt.Errorf("AppRoleAssignmentsClient.BaseClient.Post(): %v", err)
+		return nil, status, fmt.Errorf("AppRoleAssignmentsClient.BaseClient.Post(): %w", err)
 	}
 
 	defer resp.Body.Close()
@@ -131,7 +131,7 @@ func (c *AppRoleAssignmentsClient) Assign(ctx context.Context, clientServicePrin
 	}
 
 	var appRoleAssignment AppRoleAssignment
-	if err := json.Unmarshal(respBody, &appRoleAssignment); err != nil {
+	if err := c.BaseClient.decode(respBody, &appRoleAssignment); err != nil {
 		return nil, status, fmt.Errorf("json.Unmarshal(): %v", err)
 	}
 
@@ -0,0 +1,212 @@
+package msgraph
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/manicminer/hamilton/internal/utils"
+	"github.com/manicminer/hamilton/odata"
+)
+
+// applicationPermissionsSelect are the properties retrieved for resource service principals when resolving permissions.
+var applicationPermissionsSelect = []string{"id", "appId", "displayName", "appRoles", "publishedPermissionScopes"}
+
+// ApplicationPermissionsClient resolves the permissions requested by Applications to the app roles and permission
+// scopes published by the service principals of the respective resource applications. Resource service principals
+// are cached for the lifetime of the client, so a single client should be reused when resolving permissions for many
+// applications.
+type ApplicationPermissionsClient struct {
+	BaseClient Client
+
+	mutex     sync.Mutex
+	resources map[string]*ServicePrincipal
+}
+
+// NewApplicationPermissionsClient returns a new ApplicationPermissionsClient.
+func NewApplicationPermissionsClient(tenantId string) *ApplicationPermissionsClient {
+	return &ApplicationPermissionsClient{
+		BaseClient: NewClient(VersionBeta, tenantId),
+	}
+}
+
+// Resolve returns a ResolvedPermission for each permission in requiredResourceAccess, e.g. as found in the
+// RequiredResourceAccess field of an Application. Permissions which are not published by the resource service
+// principal, or whose resource application has no service principal in the tenant, are returned with a nil Value.
+func (c *ApplicationPermissionsClient) Resolve(ctx context.Context, requiredResourceAccess []RequiredResourceAccess) (*[]ResolvedPermission, int, error) {
+	var status int
+
+	ret := make([]ResolvedPermission, 0)
+	for _, rra := range requiredResourceAccess {
+		if rra.ResourceAppId == nil || rra.ResourceAccess == nil {
+			continue
+		}
+
+		var resource *ServicePrincipal
+		var err error
+		resource, status, err = c.resource(ctx, *rra.ResourceAppId)
+		if err != nil {
+			return nil, status, err
+		}
+
+		for _, ra := range *rra.ResourceAccess {
+			permission := ResolvedPermission{
+				ID:            ra.ID,
+				ResourceAppId: rra.ResourceAppId,
+				Type:          ra.Type,
+			}
+			if resource != nil {
+				permission.ResourceDisplayName = resource.DisplayName
+				if ra.ID != nil {
+					resolvePermission(&permission, resource, *ra.ID)
+				}
+			}
+			ret = append(ret, permission)
+		}
+	}
+
+	return &ret, status, nil
+}
+
+// BuildRequiredResourceAccess returns the RequiredResourceAccess for the specified permissions, suitable for the
+// RequiredResourceAccess field of an Application when creating or updating it. Permission values, such as
+// `User.Read.All`, are resolved to the IDs of the app roles and permission scopes published by the service principal
+// of each resource application. Permissions for the same resource application are combined, and duplicates are
+// ignored. An error is returned if a resource application has no service principal in the tenant, or if a permission
+// is not published by it.
+func (c *ApplicationPermissionsClient) BuildRequiredResourceAccess(ctx context.Context, permissions []RequestedPermissions) (*[]RequiredResourceAccess, int, error) {
+	var status int
+
+	ret := make([]RequiredResourceAccess, 0)
+	indexes := make(map[string]int)
+	seen := make(map[string]bool)
+	for _, p := range permissions {
+		var resource *ServicePrincipal
+		var err error
+		resource, status, err = c.resource(ctx, p.ResourceAppId)
+		if err != nil {
+			return nil, status, err
+		}
+		if resource == nil {
+			return nil, status, fmt.Errorf("no service principal was found for resource application %q", p.ResourceAppId)
+		}
+
+		i, ok := indexes[p.ResourceAppId]
+		if !ok {
+			i = len(ret)
+			indexes[p.ResourceAppId] = i
+			ret = append(ret, RequiredResourceAccess{
+				ResourceAccess: &[]ResourceAccess{},
+				ResourceAppId:  utils.StringPtr(p.ResourceAppId),
+			})
+		}
+
+		for _, v := range p.Roles {
+			id := appRoleId(resource, v)
+			if id == nil {
+				return nil, status, fmt.Errorf("app role %q is not published by resource application %q", v, p.ResourceAppId)
+			}
+			if key := p.ResourceAppId + "/" + *id; !seen[key] {
+				seen[key] = true
+				*ret[i].ResourceAccess = append(*ret[i].ResourceAccess, ResourceAccess{ID: id, Type: ResourceAccessTypeRole})
+			}
+		}
+
+		for _, v := range p.Scopes {
+			id := permissionScopeId(resource, v)
+			if id == nil {
+				return nil, status, fmt.Errorf("permission scope %q is not published by resource application %q", v, p.ResourceAppId)
+			}
+			if key := p.ResourceAppId + "/" + *id; !seen[key] {
+				seen[key] = true
+				*ret[i].ResourceAccess = append(*ret[i].ResourceAccess, ResourceAccess{ID: id, Type: ResourceAccessTypeScope})
+			}
+		}
+	}
+
+	return &ret, status, nil
+}
+
+// resource returns the service principal for the specified resource application, retrieving it if it is not already
+// cached. A nil service principal is returned (and cached) when the resource application has no service principal.
+func (c *ApplicationPermissionsClient) resource(ctx context.Context, appId string) (*ServicePrincipal, int, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if resource, ok := c.resources[appId]; ok {
+		return resource, 0, nil
+	}
+
+	client := &ServicePrincipalsClient{BaseClient: c.BaseClient}
+	servicePrincipals, status, err := client.List(ctx, odata.Query{
+		Filter: fmt.Sprintf("appId eq '%s'", appId),
+		Select: applicationPermissionsSelect,
+	})
+	if err != nil {
+		return nil, status, fmt.Errorf("ServicePrincipalsClient.List(): %v", err)
+	}
+
+	var resource *ServicePrincipal
+	if servicePrincipals != nil && len(*servicePrincipals) > 0 {
+		resource = &(*servicePrincipals)[0]
+	}
+
+	if c.resources == nil {
+		c.resources = make(map[string]*ServicePrincipal)
+	}
+	c.resources[appId] = resource
+
+	return resource, status, nil
+}
+
+// appRoleId returns the ID of the app role with the specified value published by the resource service principal
+func appRoleId(resource *ServicePrincipal, value string) *string {
+	if resource.AppRoles != nil {
+		for _, r := range *resource.AppRoles {
+			if r.Value != nil && *r.Value == value {
+				return r.ID
+			}
+		}
+	}
+	return nil
+}
+
+// permissionScopeId returns the ID of the permission scope with the specified value published by the resource service
+// principal
+func permissionScopeId(resource *ServicePrincipal, value string) *string {
+	if resource.PublishedPermissionScopes != nil {
+		for _, s := range *resource.PublishedPermissionScopes {
+			if s.Value != nil && *s.Value == value {
+				return s.ID
+			}
+		}
+	}
+	return nil
+}
+
+// resolvePermission populates the Value and DisplayName of a ResolvedPermission from the matching app role or
+// permission scope of the resource service principal.
+func resolvePermission(permission *ResolvedPermission, resource *ServicePrincipal, id string) {
+	switch permission.Type {
+	case ResourceAccessTypeRole:
+		if resource.AppRoles != nil {
+			for _, r := range *resource.AppRoles {
+				if r.ID != nil && *r.ID == id {
+					permission.Value = r.Value
+					permission.DisplayName = r.DisplayName
+					return
+				}
+			}
+		}
+	case ResourceAccessTypeScope:
+		if resource.PublishedPermissionScopes != nil {
+			for _, s := range *resource.PublishedPermissionScopes {
+				if s.ID != nil && *s.ID == id {
+					permission.Value = s.Value
+					permission.DisplayName = s.AdminConsentDisplayName
+					return
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,135 @@
+package msgraph_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/manicminer/hamilton/environments"
+	"github.com/manicminer/hamilton/internal/utils"
+	"github.com/manicminer/hamilton/msgraph"
+)
+
+func testApplicationPermissionsServer(requests *int) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		*requests++
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Query().Get("$filter") {
+		case "appId eq '00000003-0000-0000-c000-000000000000'":
+			w.Write([]byte(`{"value":[{"id":"sp1","appId":"00000003-0000-0000-c000-000000000000","displayName":"Microsoft Graph",
+				"appRoles":[{"id":"df021288-bdef-4463-88db-98f22de89214","value":"User.Read.All","displayName":"Read all users' full profiles"}],
+				"publishedPermissionScopes":[{"id":"e1fe6dd8-ba31-4d61-89e7-88639da4683d","value":"User.Read","adminConsentDisplayName":"Sign in and read user profile"}]}]}`))
+		default:
+			w.Write([]byte(`{"value":[]}`))
+		}
+	}))
+}
+
+func TestApplicationPermissionsClient_Resolve(t *testing.T) {
+	requests := 0
+	server := testApplicationPermissionsServer(&requests)
+	defer server.Close()
+
+	c := msgraph.NewApplicationPermissionsClient("00000000-0000-0000-0000-000000000000")
+	c.BaseClient.Endpoint = environments.ApiEndpoint(server.URL)
+
+	requiredResourceAccess := []msgraph.RequiredResourceAccess{
+		{
+			ResourceAppId: utils.StringPtr("00000003-0000-0000-c000-000000000000"),
+			ResourceAccess: &[]msgraph.ResourceAccess{
+				{ID: utils.StringPtr("df021288-bdef-4463-88db-98f22de89214"), Type: msgraph.ResourceAccessTypeRole},
+				{ID: utils.StringPtr("e1fe6dd8-ba31-4d61-89e7-88639da4683d"), Type: msgraph.ResourceAccessTypeScope},
+				{ID: utils.StringPtr("11111111-1111-1111-1111-111111111111"), Type: msgraph.ResourceAccessTypeRole},
+			},
+		},
+		{
+			ResourceAppId: utils.StringPtr("22222222-2222-2222-2222-222222222222"),
+			ResourceAccess: &[]msgraph.ResourceAccess{
+				{ID: utils.StringPtr("33333333-3333-3333-3333-333333333333"), Type: msgraph.ResourceAccessTypeScope},
+			},
+		},
+	}
+
+	permissions, _, err := c.Resolve(context.Background(), requiredResourceAccess)
+	if err != nil {
+		t.Fatalf("ApplicationPermissionsClient.Resolve(): %v", err)
+	}
+	if permissions == nil || len(*permissions) != 4 {
+		t.Fatalf("ApplicationPermissionsClient.Resolve(): expected 4 permissions, got %v", permissions)
+	}
+
+	expected := []string{
+		"User.Read.All (application)",
+		"User.Read (delegated)",
+		"11111111-1111-1111-1111-111111111111 (application)",
+		"33333333-3333-3333-3333-333333333333 (delegated)",
+	}
+	for i, p := range *permissions {
+		if p.String() != expected[i] {
+			t.Errorf("ApplicationPermissionsClient.Resolve(): expected permission %d to be %q, got %q", i, expected[i], p.String())
+		}
+	}
+	if p := (*permissions)[0]; p.ResourceDisplayName == nil || *p.ResourceDisplayName != "Microsoft Graph" {
+		t.Errorf("ApplicationPermissionsClient.Resolve(): expected resource display name to be resolved, got %v", p.ResourceDisplayName)
+	}
+
+	if _, _, err := c.Resolve(context.Background(), requiredResourceAccess); err != nil {
+		t.Fatalf("ApplicationPermissionsClient.Resolve(): %v", err)
+	}
+	if requests != 2 {
+		t.Fatalf("ApplicationPermissionsClient.Resolve(): expected resource service principals to be cached, got %d requests", requests)
+	}
+}
+
+func TestApplicationPermissionsClient_BuildRequiredResourceAccess(t *testing.T) {
+	requests := 0
+	server := testApplicationPermissionsServer(&requests)
+	defer server.Close()
+
+	c := msgraph.NewApplicationPermissionsClient("00000000-0000-0000-0000-000000000000")
+	c.BaseClient.Endpoint = environments.ApiEndpoint(server.URL)
+
+	requiredResourceAccess, _, err := c.BuildRequiredResourceAccess(context.Background(), []msgraph.RequestedPermissions{
+		{
+			ResourceAppId: environments.PublishedApis["MicrosoftGraph"],
+			Roles:         []string{"User.Read.All"},
+		},
+		{
+			ResourceAppId: environments.PublishedApis["MicrosoftGraph"],
+			Roles:         []string{"User.Read.All"},
+			Scopes:        []string{"User.Read"},
+		},
+	})
+	if err != nil {
+		t.Fatalf("ApplicationPermissionsClient.BuildRequiredResourceAccess(): %v", err)
+	}
+
+	expected := []msgraph.RequiredResourceAccess{
+		{
+			ResourceAppId: utils.StringPtr("00000003-0000-0000-c000-000000000000"),
+			ResourceAccess: &[]msgraph.ResourceAccess{
+				{ID: utils.StringPtr("df021288-bdef-4463-88db-98f22de89214"), Type: msgraph.ResourceAccessTypeRole},
+				{ID: utils.StringPtr("e1fe6dd8-ba31-4d61-89e7-88639da4683d"), Type: msgraph.ResourceAccessTypeScope},
+			},
+		},
+	}
+	if !reflect.DeepEqual(*requiredResourceAccess, expected) {
+		t.Fatalf("ApplicationPermissionsClient.BuildRequiredResourceAccess(): expected %+v, got %+v", expected, *requiredResourceAccess)
+	}
+
+	if _, _, err := c.BuildRequiredResourceAccess(context.Background(), []msgraph.RequestedPermissions{
+		{ResourceAppId: environments.PublishedApis["MicrosoftGraph"], Scopes: []string{"User.Read.All"}},
+	}); err == nil {
+		t.Fatal("ApplicationPermissionsClient.BuildRequiredResourceAccess(): expected an error for an unpublished permission scope")
+	}
+	if _, _, err := c.BuildRequiredResourceAccess(context.Background(), []msgraph.RequestedPermissions{
+		{ResourceAppId: "22222222-2222-2222-2222-222222222222", Scopes: []string{"User.Read"}},
+	}); err == nil {
+		t.Fatal("ApplicationPermissionsClient.BuildRequiredResourceAccess(): expected an error for a resource application without a service principal")
+	}
+	if requests != 2 {
+		t.Fatalf("ApplicationPermissionsClient.BuildRequiredResourceAccess(): expected resource service principals to be cached, got %d requests", requests)
+	}
+}
@@ -26,6 +26,7 @@ func NewApplicationTemplatesClient(tenantId string) *ApplicationTemplatesClient
 // List returns a list of ApplicationTemplates, optionally queried using OData.
 func (c *ApplicationTemplatesClient) List(ctx context.Context, query odata.Query) (*[]ApplicationTemplate, int, error) {
 	resp, status, _, err := c.BaseClient.Get(ctx, GetHttpRequestInput{
+		ConsistencyLevel: query.ConsistencyLevel,
 		DisablePaging:    query.Top > 0,
 		ValidStatusCodes: []int{http.StatusOK},
 		Uri: Uri{
@@ -35,7 +36,7 @@ func (c *ApplicationTemplatesClient) List(ctx context.Context, query odata.Query
 		},
 	})
 	if err != nil {
-		return nil, status, fmt.Errorf("ApplicationTemplatesClient.BaseClient.Get(): %v", err)
+		return nil, status, fmt.Errorf("ApplicationTemplatesClient.BaseClient.Get(): %w", err)
 	}
 
 	defer resp.Body.Close()
@@ -47,7 +48,7 @@ func (c *ApplicationTemplatesClient) List(ctx context.Context, query odata.Query
 	var data struct {
 		ApplicationTemplates []ApplicationTemplate `json:"value"`
 	}
-	if err := json.Unmarshal(respBody, &data); err != nil {
+	if err := c.BaseClient.decode(respBody, &data); err != nil {
 		return nil, status, fmt.Errorf("json.Unmarshal(): %v", err)
 	}
 
@@ -57,6 +58,7 @@ func (c *ApplicationTemplatesClient) List(ctx context.Context, query odata.Query
 // Get retrieves an ApplicationTemplate
 func (c *ApplicationTemplatesClient) Get(ctx context.Context, id string, query odata.Query) (*ApplicationTemplate, int, error) {
 	resp, status, _, err := c.BaseClient.Get(ctx, GetHttpRequestInput{
+		ConsistencyLevel: query.ConsistencyLevel,
 		ValidStatusCodes: []int{http.StatusOK},
 		Uri: Uri{
 			Entity:      fmt.Sprintf("/applicationTemplates/%s", id),
@@ -65,7 +67,7 @@ func (c *ApplicationTemplatesClient) Get(ctx context.Context, id string, query o
 		},
 	})
 	if err != nil {
-		return nil, status, fmt.Errorf("ApplicationTemplatesClient.BaseClient.Get(): %v", err)
+		return nil, status, fmt.Errorf("ApplicationTemplatesClient.BaseClient.Get(): %w", err)
 	}
 
 	defer resp.Body.Close()
@@ -75,7 +77,7 @@ func (c *ApplicationTemplatesClient) Get(ctx context.Context, id string, query o
 	}
 
 	var applicationTemplate ApplicationTemplate
-	if err := json.Unmarshal(respBody, &applicationTemplate); err != nil {
+	if err := c.BaseClient.decode(respBody, &applicationTemplate); err != nil {
 		return nil, status, fmt.Errorf("json.Unmarshal(): %v", err)
 	}
 
@@ -83,7 +85,8 @@ func (c *ApplicationTemplatesClient) Get(ctx context.Context, id string, query o
 }
 
 // Instantiate instantiates an ApplicationTemplate, which creates an Application and Service Principal in the tenant.
-// The created Application and ServicePrincipal are provided in the response.
+// The created Application and ServicePrincipal are provided in the response. If the Service Principal does not become
+// available, the response is returned together with the error, so that the created objects can be cleaned up.
 func (c *ApplicationTemplatesClient) Instantiate(ctx context.Context, applicationTemplate ApplicationTemplate) (*ApplicationTemplate, int, error) {
 	var status int
 
@@ -105,7 +108,7 @@ func (c *ApplicationTemplatesClient) Instantiate(ctx context.Context, applicatio
 		},
 	})
 	if err != nil {
-		return nil, status, fmt.Errorf("ApplicationTemplatesClient.BaseClient.Post(): %v", err)
+		return nil, status, fmt.Errorf("ApplicationTemplatesClient.BaseClient.Post(): %w", err)
 	}
 
 	defer resp.Body.Close()
@@ -115,9 +118,55 @@ func (c *ApplicationTemplatesClient) Instantiate(ctx context.Context, applicatio
 	}
 
 	var newApplicationTemplate ApplicationTemplate
-	if err := json.Unmarshal(respBody, &newApplicationTemplate); err != nil {
+	if err := c.BaseClient.decode(respBody, &newApplicationTemplate); err != nil {
 		return nil, status, fmt.Errorf("json.Unmarshal(): %v", err)
 	}
 
+	// The service principal is provisioned asynchronously, so wait for it to become available before returning
+	if newApplicationTemplate.ServicePrincipal != nil && newApplicationTemplate.ServicePrincipal.ID != nil {
+		servicePrincipal, _, err := c.getServicePrincipal(ctx, *newApplicationTemplate.ServicePrincipal.ID)
+		if err != nil {
+			return &newApplicationTemplate, status, fmt.Errorf("ApplicationTemplatesClient.getServicePrincipal(): %v", err)
+		}
+		newApplicationTemplate.ServicePrincipal = servicePrincipal
+	}
+
 	return &newApplicationTemplate, status, nil
 }
+
+// InstantiateFromTemplate instantiates the ApplicationTemplate with the specified ID, creating an Application and Service
+// Principal in the tenant with the specified display name. The created Application and ServicePrincipal are provided in the response.
+func (c *ApplicationTemplatesClient) InstantiateFromTemplate(ctx context.Context, templateId, displayName string) (*ApplicationTemplate, int, error) {
+	return c.Instantiate(ctx, ApplicationTemplate{
+		ID:          &templateId,
+		DisplayName: &displayName,
+	})
+}
+
+// getServicePrincipal retrieves a ServicePrincipal created by instantiating an ApplicationTemplate, retrying until it is available.
+func (c *ApplicationTemplatesClient) getServicePrincipal(ctx context.Context, id string) (*ServicePrincipal, int, error) {
+	resp, status, _, err := c.BaseClient.Get(ctx, GetHttpRequestInput{
+		ConsistencyFailureFunc: RetryOn404ConsistencyFailureFunc,
+		ValidStatusCodes:       []int{http.StatusOK},
+		Uri: Uri{
+			Entity:      fmt.Sprintf("/servicePrincipals/%s", id),
+			HasTenantId: true,
+		},
+	})
+	if err != nil {
+		return nil, status, fmt.Errorf("ApplicationTemplatesClient.BaseClient.Get(): %w", err)
+	}
+
+	defer resp.Body.Close()
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, status, fmt.Errorf("io.ReadAll(): %v", err)
+	}
+
+	var servicePrincipal ServicePrincipal
+	if err := c.BaseClient.decode(respBody, &servicePrincipal); err != nil {
+		return nil, status, fmt.Errorf("json.Unmarshal(): %v", err)
+	}
+
+	return &servicePrincipal, status, nil
+}
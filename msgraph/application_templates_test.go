@@ -1,10 +1,14 @@
 package msgraph_test
 
 import (
+	"context"
 	"fmt"
+	"net/http"
+	"net/http/httptest"
 	"testing"
 
 	"github.com/manicminer/hamilton/auth"
+	"github.com/manicminer/hamilton/environments"
 	"github.com/manicminer/hamilton/internal/test"
 	"github.com/manicminer/hamilton/internal/utils"
 	"github.com/manicminer/hamilton/msgraph"
@@ -54,6 +58,11 @@ func TestApplicationTemplatesClient(t *testing.T) {
 
 	testApplicationsClient_Delete(t, a, *app.Application.ID)
 	testApplicationsClient_DeletePermanently(t, a, *app.Application.ID)
+
+	app = testApplicationTemplatesClient_InstantiateFromTemplate(t, c, *template.ID, fmt.Sprintf("test-applicationTemplate-fromTemplate-%s", c.randomString))
+	testServicePrincipalsClient_Delete(t, s, *app.ServicePrincipal.ID)
+	testApplicationsClient_Delete(t, a, *app.Application.ID)
+	testApplicationsClient_DeletePermanently(t, a, *app.Application.ID)
 }
 
 func testApplicationTemplatesClient_List(t *testing.T, c ApplicationTemplatesClientTest, o odata.Query) (applicationTemplates []msgraph.ApplicationTemplate) {
@@ -110,3 +119,58 @@ func testApplicationTemplatesClient_Instantiate(t *testing.T, c ApplicationTempl
 	}
 	return
 }
+
+func testApplicationTemplatesClient_InstantiateFromTemplate(t *testing.T, c ApplicationTemplatesClientTest, templateId, displayName string) (applicationTemplate *msgraph.ApplicationTemplate) {
+	applicationTemplate, status, err := c.client.InstantiateFromTemplate(c.connection.Context, templateId, displayName)
+	if err != nil {
+		t.Fatalf("ApplicationTemplatesClient.InstantiateFromTemplate(): %v", err)
+	}
+	if status < 200 || status >= 300 {
+		t.Fatalf("ApplicationTemplatesClient.InstantiateFromTemplate(): invalid status: %d", status)
+	}
+	if applicationTemplate == nil {
+		t.Fatal("ApplicationTemplatesClient.InstantiateFromTemplate(): applicationTemplate was nil")
+	}
+	if applicationTemplate.Application == nil || applicationTemplate.Application.ID == nil {
+		t.Fatal("ApplicationTemplatesClient.InstantiateFromTemplate(): applicationTemplate.Application was nil")
+	}
+	if applicationTemplate.ServicePrincipal == nil || applicationTemplate.ServicePrincipal.ID == nil {
+		t.Fatal("ApplicationTemplatesClient.InstantiateFromTemplate(): applicationTemplate.ServicePrincipal was nil")
+	}
+	if applicationTemplate.ServicePrincipal.DisplayName == nil || *applicationTemplate.ServicePrincipal.DisplayName != displayName {
+		t.Fatal("ApplicationTemplatesClient.InstantiateFromTemplate(): applicationTemplate.ServicePrincipal.DisplayName was not as expected")
+	}
+	return
+}
+
+func TestApplicationTemplatesClient_InstantiateServicePrincipalUnavailable(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/v1.0/00000000-0000-0000-0000-000000000000/applicationTemplates/template1/instantiate":
+			w.WriteHeader(http.StatusCreated)
+			fmt.Fprint(w, `{"application":{"id":"app1","appId":"11111111-1111-1111-1111-111111111111"},"servicePrincipal":{"id":"sp1"}}`)
+		case "/v1.0/00000000-0000-0000-0000-000000000000/servicePrincipals/sp1":
+			w.WriteHeader(http.StatusForbidden)
+			fmt.Fprint(w, `{"error":{"code":"Authorization_RequestDenied","message":"Insufficient privileges to complete the operation."}}`)
+		default:
+			t.Errorf("unexpected request: %s %s", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	c := msgraph.NewApplicationTemplatesClient("00000000-0000-0000-0000-000000000000")
+	c.BaseClient.Endpoint = environments.ApiEndpoint(server.URL)
+
+	template, _, err := c.InstantiateFromTemplate(context.Background(), "template1", "test-app")
+	if err == nil {
+		t.Fatal("ApplicationTemplatesClient.InstantiateFromTemplate(): expected an error when the service principal is unavailable")
+	}
+	if template == nil || template.Application == nil || template.Application.ID == nil || *template.Application.ID != "app1" {
+		t.Fatalf("ApplicationTemplatesClient.InstantiateFromTemplate(): expected created application to be returned with the error, got %+v", template)
+	}
+	if template.ServicePrincipal == nil || template.ServicePrincipal.ID == nil || *template.ServicePrincipal.ID != "sp1" {
+		t.Fatalf("ApplicationTemplatesClient.InstantiateFromTemplate(): expected created service principal to be returned with the error, got %+v", template.ServicePrincipal)
+	}
+}
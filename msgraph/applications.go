@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"strings"
 
 	"github.com/manicminer/hamilton/odata"
 )
@@ -26,6 +27,7 @@ func NewApplicationsClient(tenantId string) *ApplicationsClient {
 // List returns a list of Applications, optionally queried using OData.
 func (c *ApplicationsClient) List(ctx context.Context, query odata.Query) (*[]Application, int, error) {
 	resp, status, _, err := c.BaseClient.Get(ctx, GetHttpRequestInput{
+		ConsistencyLevel: query.ConsistencyLevel,
 		DisablePaging:    query.Top > 0,
 		ValidStatusCodes: []int{http.StatusOK},
 		Uri: Uri{
@@ -35,7 +37,7 @@ func (c *ApplicationsClient) List(ctx context.Context, query odata.Query) (*[]Ap
 		},
 	})
 	if err != nil {
-		return nil, status, fmt.Errorf("ApplicationsClient.BaseClient.Get(): %v", err)
+		return nil, status, fmt.Errorf("ApplicationsClient.BaseClient.Get(): %w", err)
 	}
 
 	defer resp.Body.Close()
@@ -47,7 +49,7 @@ func (c *ApplicationsClient) List(ctx context.Context, query odata.Query) (*[]Ap
 	var data struct {
 		Applications []Application `json:"value"`
 	}
-	if err := json.Unmarshal(respBody, &data); err != nil {
+	if err := c.BaseClient.decode(respBody, &data); err != nil {
 		return nil, status, fmt.Errorf("json.Unmarshal(): %v", err)
 	}
 
@@ -72,7 +74,7 @@ func (c *ApplicationsClient) Create(ctx context.Context, application Application
 		},
 	})
 	if err != nil {
-		return nil, status, fmt.Errorf("ApplicationsClient.BaseClient.Post(): %v", err)
+		return nil, status, fmt.Errorf("ApplicationsClient.BaseClient.Post(): %w", err)
 	}
 
 	defer resp.Body.Close()
@@ -82,7 +84,7 @@ func (c *ApplicationsClient) Create(ctx context.Context, application Application
 	}
 
 	var newApplication Application
-	if err := json.Unmarshal(respBody, &newApplication); err != nil {
+	if err := c.BaseClient.decode(respBody, &newApplication); err != nil {
 		return nil, status, fmt.Errorf("json.Unmarshal(): %v", err)
 	}
 
@@ -93,6 +95,7 @@ func (c *ApplicationsClient) Create(ctx context.Context, application Application
 func (c *ApplicationsClient) Get(ctx context.Context, id string, query odata.Query) (*Application, int, error) {
 	resp, status, _, err := c.BaseClient.Get(ctx, GetHttpRequestInput{
 		ConsistencyFailureFunc: RetryOn404ConsistencyFailureFunc,
+		ConsistencyLevel:       query.ConsistencyLevel,
 		ValidStatusCodes:       []int{http.StatusOK},
 		Uri: Uri{
 			Entity:      fmt.Sprintf("/applications/%s", id),
@@ -101,7 +104,7 @@ func (c *ApplicationsClient) Get(ctx context.Context, id string, query odata.Que
 		},
 	})
 	if err != nil {
-		return nil, status, fmt.Errorf("ApplicationsClient.BaseClient.Get(): %v", err)
+		return nil, status, fmt.Errorf("ApplicationsClient.BaseClient.Get(): %w", err)
 	}
 
 	defer resp.Body.Close()
@@ -111,7 +114,7 @@ func (c *ApplicationsClient) Get(ctx context.Context, id string, query odata.Que
 	}
 
 	var application Application
-	if err := json.Unmarshal(respBody, &application); err != nil {
+	if err := c.BaseClient.decode(respBody, &application); err != nil {
 		return nil, status, fmt.Errorf("json.Unmarshal(): %v", err)
 	}
 
@@ -123,6 +126,7 @@ func (c *ApplicationsClient) Get(ctx context.Context, id string, query odata.Que
 func (c *ApplicationsClient) GetDeleted(ctx context.Context, id string, query odata.Query) (*Application, int, error) {
 	resp, status, _, err := c.BaseClient.Get(ctx, GetHttpRequestInput{
 		ConsistencyFailureFunc: RetryOn404ConsistencyFailureFunc,
+		ConsistencyLevel:       query.ConsistencyLevel,
 		ValidStatusCodes:       []int{http.StatusOK},
 		Uri: Uri{
 			Entity:      fmt.Sprintf("/directory/deletedItems/%s", id),
@@ -131,7 +135,7 @@ func (c *ApplicationsClient) GetDeleted(ctx context.Context, id string, query od
 		},
 	})
 	if err != nil {
-		return nil, status, fmt.Errorf("ApplicationsClient.BaseClient.Get(): %v", err)
+		return nil, status, fmt.Errorf("ApplicationsClient.BaseClient.Get(): %w", err)
 	}
 
 	defer resp.Body.Close()
@@ -141,7 +145,7 @@ func (c *ApplicationsClient) GetDeleted(ctx context.Context, id string, query od
 	}
 
 	var application Application
-	if err := json.Unmarshal(respBody, &application); err != nil {
+	if err := c.BaseClient.decode(respBody, &application); err != nil {
 		return nil, status, fmt.Errorf("json.Unmarshal(): %v", err)
 	}
 
@@ -171,12 +175,87 @@ func (c *ApplicationsClient) Update(ctx context.Context, application Application
 		},
 	})
 	if err != nil {
-		return status, fmt.Errorf("ApplicationsClient.BaseClient.Patch(): %v", err)
+		return status, fmt.Errorf("ApplicationsClient.BaseClient.Patch(): %w", err)
 	}
 
 	return status, nil
 }
 
+// UpdateAndReturn amends an existing Application as for Update, and returns the updated Application as represented by
+// the API, which saves retrieving it afterwards. Where the API does not return the updated object, the specified
+// application is returned instead.
+func (c *ApplicationsClient) UpdateAndReturn(ctx context.Context, application Application) (*Application, int, error) {
+	var status int
+
+	if application.ID == nil {
+		return nil, status, errors.New("ApplicationsClient.UpdateAndReturn(): cannot update application with nil ID")
+	}
+
+	body, err := json.Marshal(application)
+	if err != nil {
+		return nil, status, fmt.Errorf("json.Marshal(): %v", err)
+	}
+
+	var updated Application
+	returned, status, err := c.BaseClient.patchAndReturn(ctx, PatchHttpRequestInput{
+		Body:                   body,
+		ConsistencyFailureFunc: RetryOn404ConsistencyFailureFunc,
+		Uri: Uri{
+			Entity:      fmt.Sprintf("/applications/%s", *application.ID),
+			HasTenantId: true,
+		},
+	}, &updated)
+	if err != nil {
+		return nil, status, fmt.Errorf("ApplicationsClient.BaseClient.Patch(): %w", err)
+	}
+	if !returned {
+		return &application, status, nil
+	}
+
+	return &updated, status, nil
+}
+
+// Upsert creates an Application, or updates an existing Application when one matches the specified OData filter, so
+// that provisioning can be repeated safely. When filter is empty, an existing application is matched by DisplayName.
+// Returns the resulting Application and whether it was newly created. An error is returned if more than one existing
+// application matches, since the application to update cannot be determined.
+func (c *ApplicationsClient) Upsert(ctx context.Context, application Application, filter string) (*Application, bool, int, error) {
+	var status int
+
+	if filter == "" {
+		if application.DisplayName == nil || *application.DisplayName == "" {
+			return nil, false, status, errors.New("ApplicationsClient.Upsert(): a filter must be specified when the application has no DisplayName")
+		}
+		filter = fmt.Sprintf("displayName eq '%s'", strings.ReplaceAll(*application.DisplayName, "'", "''"))
+	}
+
+	existing, status, err := c.List(ctx, odata.Query{Filter: filter})
+	if err != nil {
+		return nil, false, status, fmt.Errorf("ApplicationsClient.List(): %v", err)
+	}
+
+	switch len(*existing) {
+	case 0:
+		newApplication, status, err := c.Create(ctx, application)
+		if err != nil {
+			return nil, false, status, fmt.Errorf("ApplicationsClient.Create(): %v", err)
+		}
+		return newApplication, true, status, nil
+	case 1:
+		application.ID = (*existing)[0].ID
+		if status, err := c.Update(ctx, application); err != nil {
+			return nil, false, status, fmt.Errorf("ApplicationsClient.Update(): %v", err)
+		}
+		updated, status, err := c.Get(ctx, *application.ID, odata.Query{})
+		if err != nil {
+			return nil, false, status, fmt.Errorf("ApplicationsClient.Get(): %v", err)
+		}
+		return updated, false, status, nil
+	default:
+		return nil, false, status, fmt.Errorf("ApplicationsClient.Upsert(): %d applications match the filter %q, expected at most one", len(*existing), filter)
+	}
+}
+
 // Delete removes an Application.
 func (c *ApplicationsClient) Delete(ctx context.Context, id string) (int, error) {
 	_, status, _, err := c.BaseClient.Delete(ctx, DeleteHttpRequestInput{
@@ -188,7 +267,7 @@ func (c *ApplicationsClient) Delete(ctx context.Context, id string) (int, error)
 		},
 	})
 	if err != nil {
-		return status, fmt.Errorf("ApplicationsClient.BaseClient.Delete(): %v", err)
+		return status, fmt.Errorf("ApplicationsClient.BaseClient.Delete(): %w", err)
 	}
 
 	return status, nil
@@ -206,7 +285,7 @@ func (c *ApplicationsClient) DeletePermanently(ctx context.Context, id string) (
 		},
 	})
 	if err != nil {
-		return status, fmt.Errorf("ApplicationsClient.BaseClient.Delete(): %v", err)
+		return status, fmt.Errorf("ApplicationsClient.BaseClient.Delete(): %w", err)
 	}
 
 	return status, nil
@@ -215,6 +294,7 @@ func (c *ApplicationsClient) DeletePermanently(ctx context.Context, id string) (
 // ListDeleted retrieves a list of recently deleted applications, optionally queried using OData.
 func (c *ApplicationsClient) ListDeleted(ctx context.Context, query odata.Query) (*[]Application, int, error) {
 	resp, status, _, err := c.BaseClient.Get(ctx, GetHttpRequestInput{
+		ConsistencyLevel: query.ConsistencyLevel,
 		DisablePaging:    query.Top > 0,
 		ValidStatusCodes: []int{http.StatusOK},
 		Uri: Uri{
@@ -232,7 +312,7 @@ func (c *ApplicationsClient) ListDeleted(ctx context.Context, query odata.Query)
 	var data struct {
 		DeletedApps []Application `json:"value"`
 	}
-	if err = json.Unmarshal(respBody, &data); err != nil {
+	if err = c.BaseClient.decode(respBody, &data); err != nil {
 		return nil, status, err
 	}
 
@@ -251,7 +331,7 @@ func (c *ApplicationsClient) RestoreDeleted(ctx context.Context, id string) (*Ap
 		},
 	})
 	if err != nil {
-		return nil, status, fmt.Errorf("ApplicationsClient.BaseClient.Post(): %v", err)
+		return nil, status, fmt.Errorf("ApplicationsClient.BaseClient.Post(): %w", err)
 	}
 
 	defer resp.Body.Close()
@@ -261,7 +341,7 @@ func (c *ApplicationsClient) RestoreDeleted(ctx context.Context, id string) (*Ap
 	}
 
 	var restoredApplication Application
-	if err = json.Unmarshal(respBody, &restoredApplication); err != nil {
+	if err = c.BaseClient.decode(respBody, &restoredApplication); err != nil {
 		return nil, status, fmt.Errorf("json.Unmarshal(): %v", err)
 	}
 
@@ -291,7 +371,7 @@ func (c *ApplicationsClient) AddPassword(ctx context.Context, applicationId stri
 		},
 	})
 	if err != nil {
-		return nil, status, fmt.Errorf("ApplicationsClient.BaseClient.Post(): %v", err)
+		return nil, status, fmt.Errorf("ApplicationsClient.BaseClient.Post(): %w", err)
 	}
 
 	defer resp.Body.Close()
@@ -301,7 +381,7 @@ func (c *ApplicationsClient) AddPassword(ctx context.Context, applicationId stri
 	}
 
 	var newPasswordCredential PasswordCredential
-	if err := json.Unmarshal(respBody, &newPasswordCredential); err != nil {
+	if err := c.BaseClient.decode(respBody, &newPasswordCredential); err != nil {
 		return nil, status, fmt.Errorf("json.Unmarshal(): %v", err)
 	}
 
@@ -331,7 +411,7 @@ func (c *ApplicationsClient) RemovePassword(ctx context.Context, applicationId s
 		},
 	})
 	if err != nil {
-		return status, fmt.Errorf("ApplicationsClient.BaseClient.Post(): %v", err)
+		return status, fmt.Errorf("ApplicationsClient.BaseClient.Post(): %w", err)
 	}
 
 	return status, nil
@@ -350,7 +430,7 @@ func (c *ApplicationsClient) ListOwners(ctx context.Context, id string) (*[]stri
 		},
 	})
 	if err != nil {
-		return nil, status, fmt.Errorf("ApplicationsClient.BaseClient.Get(): %v", err)
+		return nil, status, fmt.Errorf("ApplicationsClient.BaseClient.Get(): %w", err)
 	}
 
 	defer resp.Body.Close()
@@ -365,7 +445,7 @@ func (c *ApplicationsClient) ListOwners(ctx context.Context, id string) (*[]stri
 			Id   string `json:"id"`
 		} `json:"value"`
 	}
-	if err := json.Unmarshal(respBody, &data); err != nil {
+	if err := c.BaseClient.decode(respBody, &data); err != nil {
 		return nil, status, fmt.Errorf("json.Unmarshal(): %v", err)
 	}
 
@@ -391,7 +471,7 @@ func (c *ApplicationsClient) GetOwner(ctx context.Context, applicationId, ownerI
 		},
 	})
 	if err != nil {
-		return nil, status, fmt.Errorf("ApplicationsClient.BaseClient.Get(): %v", err)
+		return nil, status, fmt.Errorf("ApplicationsClient.BaseClient.Get(): %w", err)
 	}
 
 	defer resp.Body.Close()
@@ -406,7 +486,7 @@ func (c *ApplicationsClient) GetOwner(ctx context.Context, applicationId, ownerI
 		Id      string `json:"id"`
 		Url     string `json:"url"`
 	}
-	if err := json.Unmarshal(respBody, &data); err != nil {
+	if err := c.BaseClient.decode(respBody, &data); err != nil {
 		return nil, status, fmt.Errorf("json.Unmarshal(): %v", err)
 	}
 
@@ -450,7 +530,7 @@ func (c *ApplicationsClient) AddOwners(ctx context.Context, application *Applica
 			},
 		})
 		if err != nil {
-			return status, fmt.Errorf("ApplicationsClient.BaseClient.Post(): %v", err)
+			return status, fmt.Errorf("ApplicationsClient.BaseClient.Post(): %w", err)
 		}
 	}
 
@@ -495,16 +575,19 @@ func (c *ApplicationsClient) RemoveOwners(ctx context.Context, applicationId str
 			},
 		})
 		if err != nil {
-			return status, fmt.Errorf("ApplicationsClient.BaseClient.Delete(): %v", err)
+			return status, fmt.Errorf("ApplicationsClient.BaseClient.Delete(): %w", err)
 		}
 	}
 
 	return status, nil
 }
 
+// ListExtensions returns a list of directory extension properties defined by an Application.
+// id is the object ID of the application.
 func (c *ApplicationsClient) ListExtensions(ctx context.Context, id string, query odata.Query) (*[]ApplicationExtension, int, error) {
 	resp, status, _, err := c.BaseClient.Get(ctx, GetHttpRequestInput{
 		ConsistencyFailureFunc: RetryOn404ConsistencyFailureFunc,
+		ConsistencyLevel:       query.ConsistencyLevel,
 		ValidStatusCodes:       []int{http.StatusOK},
 		Uri: Uri{
 			Entity:      fmt.Sprintf("/applications/%s/extensionProperties", id),
@@ -525,14 +608,16 @@ func (c *ApplicationsClient) ListExtensions(ctx context.Context, id string, quer
 	var data struct {
 		ApplicationExtension []ApplicationExtension `json:"value"`
 	}
-	if err := json.Unmarshal(respBody, &data); err != nil {
+	if err := c.BaseClient.decode(respBody, &data); err != nil {
 		return nil, status, fmt.Errorf("json.Unmarshal(): %v", err)
 	}
 
 	return &data.ApplicationExtension, status, nil
 }
 
-// Create creates a new ApplicationExtension.
+// CreateExtension defines a new directory extension property for an Application.
+// id is the object ID of the application. The Name of the returned ApplicationExtension is the full name of the
+// property, in the form `extension_{appId}_{name}`, which can be used to set its value on the target objects.
 func (c *ApplicationsClient) CreateExtension(ctx context.Context, applicationExtension ApplicationExtension, id string) (*ApplicationExtension, int, error) {
 	var status int
 
@@ -550,7 +635,7 @@ func (c *ApplicationsClient) CreateExtension(ctx context.Context, applicationExt
 		},
 	})
 	if err != nil {
-		return nil, status, fmt.Errorf("ApplicationsClient.BaseClient.Post(): %v", err)
+		return nil, status, fmt.Errorf("ApplicationsClient.BaseClient.Post(): %w", err)
 	}
 
 	defer resp.Body.Close()
@@ -560,14 +645,14 @@ func (c *ApplicationsClient) CreateExtension(ctx context.Context, applicationExt
 	}
 
 	var newApplicationExtension ApplicationExtension
-	if err := json.Unmarshal(respBody, &newApplicationExtension); err != nil {
+	if err := c.BaseClient.decode(respBody, &newApplicationExtension); err != nil {
 		return nil, status, fmt.Errorf("json.Unmarshal(): %v", err)
 	}
 
 	return &newApplicationExtension, status, nil
 }
 
-// DeleteExtension removes an Application Extension.
+// DeleteExtension removes a directory extension property from an Application.
 func (c *ApplicationsClient) DeleteExtension(ctx context.Context, applicationId, extensionId string) (int, error) {
 	_, status, _, err := c.BaseClient.Delete(ctx, DeleteHttpRequestInput{
 		ConsistencyFailureFunc: RetryOn404ConsistencyFailureFunc,
@@ -578,7 +663,7 @@ func (c *ApplicationsClient) DeleteExtension(ctx context.Context, applicationId,
 		},
 	})
 	if err != nil {
-		return status, fmt.Errorf("ApplicationsClient.BaseClient.Delete(): %v", err)
+		return status, fmt.Errorf("ApplicationsClient.BaseClient.Delete(): %w", err)
 	}
 
 	return status, nil
@@ -1,10 +1,15 @@
 package msgraph_test
 
 import (
+	"context"
 	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
 	"testing"
 
 	"github.com/manicminer/hamilton/auth"
+	"github.com/manicminer/hamilton/environments"
 	"github.com/manicminer/hamilton/internal/test"
 	"github.com/manicminer/hamilton/internal/utils"
 	"github.com/manicminer/hamilton/msgraph"
@@ -370,3 +375,66 @@ func testApplicationsClient_ListDeleted(t *testing.T, c ApplicationsClientTest,
 	}
 	return
 }
+
+func TestApplicationsClient_Upsert(t *testing.T) {
+	var existing bool
+	var requests []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, fmt.Sprintf("%s %s", r.Method, r.URL.Path))
+		if r.Method != http.MethodPatch {
+			w.Header().Set("Content-Type", "application/json")
+		}
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/beta/00000000-0000-0000-0000-000000000000/applications":
+			if expected := "displayName eq 'test-app''s name'"; r.URL.Query().Get("$filter") != expected {
+				t.Errorf("expected filter %q, got %q", expected, r.URL.Query().Get("$filter"))
+			}
+			if existing {
+				fmt.Fprint(w, `{"value":[{"id":"11111111-1111-1111-1111-111111111111","displayName":"test-app's name"}]}`)
+			} else {
+				fmt.Fprint(w, `{"value":[]}`)
+			}
+		case r.Method == http.MethodPost:
+			w.WriteHeader(http.StatusCreated)
+			fmt.Fprint(w, `{"id":"11111111-1111-1111-1111-111111111111","displayName":"test-app's name"}`)
+		case r.Method == http.MethodPatch:
+			w.WriteHeader(http.StatusNoContent)
+		case r.Method == http.MethodGet:
+			fmt.Fprint(w, `{"id":"11111111-1111-1111-1111-111111111111","displayName":"test-app's name","signInAudience":"AzureADMyOrg"}`)
+		}
+	}))
+	defer server.Close()
+
+	c := msgraph.NewApplicationsClient("00000000-0000-0000-0000-000000000000")
+	c.BaseClient.Endpoint = environments.ApiEndpoint(server.URL)
+
+	app := msgraph.Application{DisplayName: utils.StringPtr("test-app's name")}
+	result, created, _, err := c.Upsert(context.Background(), app, "")
+	if err != nil {
+		t.Fatalf("ApplicationsClient.Upsert(): %v", err)
+	}
+	if !created || result.ID == nil {
+		t.Fatalf("ApplicationsClient.Upsert(): expected application to be created")
+	}
+
+	existing = true
+	requests = nil
+	result, created, _, err = c.Upsert(context.Background(), app, "")
+	if err != nil {
+		t.Fatalf("ApplicationsClient.Upsert(): %v", err)
+	}
+	if created {
+		t.Fatal("ApplicationsClient.Upsert(): expected existing application to be updated")
+	}
+	if result.SignInAudience == nil {
+		t.Fatal("ApplicationsClient.Upsert(): expected the updated application to be retrieved")
+	}
+	expected := []string{
+		"GET /beta/00000000-0000-0000-0000-000000000000/applications",
+		"PATCH /beta/00000000-0000-0000-0000-000000000000/applications/11111111-1111-1111-1111-111111111111",
+		"GET /beta/00000000-0000-0000-0000-000000000000/applications/11111111-1111-1111-1111-111111111111",
+	}
+	if !reflect.DeepEqual(requests, expected) {
+		t.Fatalf("ApplicationsClient.Upsert(): expected requests %v, got %v", expected, requests)
+	}
+}
@@ -26,6 +26,7 @@ func NewAuthenticationMethodsClient(tenantId string) *AuthenticationMethodsClien
 // List all authentication methods
 func (c *AuthenticationMethodsClient) List(ctx context.Context, userID string, query odata.Query) (*[]AuthenticationMethod, int, error) {
 	resp, status, _, err := c.BaseClient.Get(ctx, GetHttpRequestInput{
+		ConsistencyLevel: query.ConsistencyLevel,
 		DisablePaging:    query.Top > 0,
 		ValidStatusCodes: []int{http.StatusOK},
 		Uri: Uri{
@@ -35,7 +36,7 @@ func (c *AuthenticationMethodsClient) List(ctx context.Context, userID string, q
 		},
 	})
 	if err != nil {
-		return nil, status, fmt.Errorf("ApplicationsClient.BaseClient.Get(): %v", err)
+		return nil, status, fmt.Errorf("ApplicationsClient.BaseClient.Get(): %w", err)
 	}
 
 	defer resp.Body.Close()
@@ -48,7 +49,7 @@ func (c *AuthenticationMethodsClient) List(ctx context.Context, userID string, q
 		AuthenticationMethods *[]json.RawMessage `json:"value"`
 	}
 
-	if err := json.Unmarshal(respBody, &data); err != nil {
+	if err := c.BaseClient.decode(respBody, &data); err != nil {
 		return nil, status, fmt.Errorf("json.Unmarshal(): %v", err)
 	}
 
@@ -120,6 +121,7 @@ func (c *AuthenticationMethodsClient) List(ctx context.Context, userID string, q
 
 func (c *AuthenticationMethodsClient) ListFido2Methods(ctx context.Context, userID string, query odata.Query) (*[]Fido2AuthenticationMethod, int, error) {
 	resp, status, _, err := c.BaseClient.Get(ctx, GetHttpRequestInput{
+		ConsistencyLevel: query.ConsistencyLevel,
 		DisablePaging:    query.Top > 0,
 		ValidStatusCodes: []int{http.StatusOK},
 		Uri: Uri{
@@ -129,7 +131,7 @@ func (c *AuthenticationMethodsClient) ListFido2Methods(ctx context.Context, user
 		},
 	})
 	if err != nil {
-		return nil, status, fmt.Errorf("AuthenticationMethodsClient.BaseClient.Get(): %v", err)
+		return nil, status, fmt.Errorf("AuthenticationMethodsClient.BaseClient.Get(): %w", err)
 	}
 
 	defer resp.Body.Close()
@@ -141,7 +143,7 @@ func (c *AuthenticationMethodsClient) ListFido2Methods(ctx context.Context, user
 	var data struct {
 		Fido2Methods []Fido2AuthenticationMethod `json:"value"`
 	}
-	if err := json.Unmarshal(respBody, &data); err != nil {
+	if err := c.BaseClient.decode(respBody, &data); err != nil {
 		return nil, status, fmt.Errorf("json.Unmarshal(): %v", err)
 	}
 
@@ -151,6 +153,7 @@ func (c *AuthenticationMethodsClient) ListFido2Methods(ctx context.Context, user
 func (c *AuthenticationMethodsClient) GetFido2Method(ctx context.Context, userID, id string, query odata.Query) (*Fido2AuthenticationMethod, int, error) {
 	resp, status, _, err := c.BaseClient.Get(ctx, GetHttpRequestInput{
 		ConsistencyFailureFunc: RetryOn404ConsistencyFailureFunc,
+		ConsistencyLevel:       query.ConsistencyLevel,
 		ValidStatusCodes:       []int{http.StatusOK},
 		Uri: Uri{
 			Entity:      fmt.Sprintf("/users/%s/authentication/fido2Methods/%s", userID, id),
@@ -159,7 +162,7 @@ func (c *AuthenticationMethodsClient) GetFido2Method(ctx context.Context, userID
 		},
 	})
 	if err != nil {
-		return nil, status, fmt.Errorf("AuthenticationMethodsClient.BaseClient.Get(): %v", err)
+		return nil, status, fmt.Errorf("AuthenticationMethodsClient.BaseClient.Get(): %w", err)
 	}
 
 	defer resp.Body.Close()
@@ -169,7 +172,7 @@ func (c *AuthenticationMethodsClient) GetFido2Method(ctx context.Context, userID
 	}
 
 	var fido2Method Fido2AuthenticationMethod
-	if err := json.Unmarshal(respBody, &fido2Method); err != nil {
+	if err := c.BaseClient.decode(respBody, &fido2Method); err != nil {
 		return nil, status, fmt.Errorf("json.Unmarshal(): %v", err)
 	}
 
@@ -186,7 +189,7 @@ func (c *AuthenticationMethodsClient) DeleteFido2Method(ctx context.Context, use
 		},
 	})
 	if err != nil {
-		return status, fmt.Errorf("AuthenticationMethodsClient.BaseClient.Delete(): %v", err)
+		return status, fmt.Errorf("AuthenticationMethodsClient.BaseClient.Delete(): %w", err)
 	}
 
 	return status, nil
@@ -194,6 +197,7 @@ func (c *AuthenticationMethodsClient) DeleteFido2Method(ctx context.Context, use
 
 func (c *AuthenticationMethodsClient) ListMicrosoftAuthenticatorMethods(ctx context.Context, userID string, query odata.Query) (*[]MicrosoftAuthenticatorAuthenticationMethod, int, error) {
 	resp, status, _, err := c.BaseClient.Get(ctx, GetHttpRequestInput{
+		ConsistencyLevel: query.ConsistencyLevel,
 		DisablePaging:    query.Top > 0,
 		ValidStatusCodes: []int{http.StatusOK},
 		Uri: Uri{
@@ -203,7 +207,7 @@ func (c *AuthenticationMethodsClient) ListMicrosoftAuthenticatorMethods(ctx cont
 		},
 	})
 	if err != nil {
-		return nil, status, fmt.Errorf("AuthenticationMethodsClient.BaseClient.Get(): %v", err)
+		return nil, status, fmt.Errorf("AuthenticationMethodsClient.BaseClient.Get(): %w", err)
 	}
 
 	defer resp.Body.Close()
@@ -215,7 +219,7 @@ func (c *AuthenticationMethodsClient) ListMicrosoftAuthenticatorMethods(ctx cont
 	var data struct {
 		MicrosoftAuthenticatorMethods []MicrosoftAuthenticatorAuthenticationMethod `json:"value"`
 	}
-	if err := json.Unmarshal(respBody, &data); err != nil {
+	if err := c.BaseClient.decode(respBody, &data); err != nil {
 		return nil, status, fmt.Errorf("json.Unmarshal(): %v", err)
 	}
 
@@ -225,6 +229,7 @@ func (c *AuthenticationMethodsClient) ListMicrosoftAuthenticatorMethods(ctx cont
 func (c *AuthenticationMethodsClient) GetMicrosoftAuthenticatorMethod(ctx context.Context, userID, id string, query odata.Query) (*MicrosoftAuthenticatorAuthenticationMethod, int, error) {
 	resp, status, _, err := c.BaseClient.Get(ctx, GetHttpRequestInput{
 		ConsistencyFailureFunc: RetryOn404ConsistencyFailureFunc,
+		ConsistencyLevel:       query.ConsistencyLevel,
 		ValidStatusCodes:       []int{http.StatusOK},
 		Uri: Uri{
 			Entity:      fmt.Sprintf("/users/%s/authentication/microsoftAuthenticatorMethods/%s", userID, id),
@@ -233,7 +238,7 @@ func (c *AuthenticationMethodsClient) GetMicrosoftAuthenticatorMethod(ctx contex
 		},
 	})
 	if err != nil {
-		return nil, status, fmt.Errorf("AuthenticationMethodsClient.BaseClient.Get(): %v", err)
+		return nil, status, fmt.Errorf("AuthenticationMethodsClient.BaseClient.Get(): %w", err)
 	}
 
 	defer resp.Body.Close()
@@ -243,7 +248,7 @@ func (c *AuthenticationMethodsClient) GetMicrosoftAuthenticatorMethod(ctx contex
 	}
 
 	var microsoftAuthenticatorMethod MicrosoftAuthenticatorAuthenticationMethod
-	if err := json.Unmarshal(respBody, &microsoftAuthenticatorMethod); err != nil {
+	if err := c.BaseClient.decode(respBody, &microsoftAuthenticatorMethod); err != nil {
 		return nil, status, fmt.Errorf("json.Unmarshal(): %v", err)
 	}
 
@@ -260,7 +265,7 @@ func (c *AuthenticationMethodsClient) DeleteMicrosoftAuthenticatorMethod(ctx con
 		},
 	})
 	if err != nil {
-		return status, fmt.Errorf("AuthenticationMethodsClient.BaseClient.Delete(): %v", err)
+		return status, fmt.Errorf("AuthenticationMethodsClient.BaseClient.Delete(): %w", err)
 	}
 
 	return status, nil
@@ -268,6 +273,7 @@ func (c *AuthenticationMethodsClient) DeleteMicrosoftAuthenticatorMethod(ctx con
 
 func (c *AuthenticationMethodsClient) ListWindowsHelloMethods(ctx context.Context, userID string, query odata.Query) (*[]WindowsHelloForBusinessAuthenticationMethod, int, error) {
 	resp, status, _, err := c.BaseClient.Get(ctx, GetHttpRequestInput{
+		ConsistencyLevel: query.ConsistencyLevel,
 		DisablePaging:    query.Top > 0,
 		ValidStatusCodes: []int{http.StatusOK},
 		Uri: Uri{
@@ -277,7 +283,7 @@ func (c *AuthenticationMethodsClient) ListWindowsHelloMethods(ctx context.Contex
 		},
 	})
 	if err != nil {
-		return nil, status, fmt.Errorf("AuthenticationMethodsClient.BaseClient.Get(): %v", err)
+		return nil, status, fmt.Errorf("AuthenticationMethodsClient.BaseClient.Get(): %w", err)
 	}
 
 	defer resp.Body.Close()
@@ -289,7 +295,7 @@ func (c *AuthenticationMethodsClient) ListWindowsHelloMethods(ctx context.Contex
 	var data struct {
 		WindowsHelloForBusinessMethods []WindowsHelloForBusinessAuthenticationMethod `json:"value"`
 	}
-	if err := json.Unmarshal(respBody, &data); err != nil {
+	if err := c.BaseClient.decode(respBody, &data); err != nil {
 		return nil, status, fmt.Errorf("json.Unmarshal(): %v", err)
 	}
 
@@ -299,6 +305,7 @@ func (c *AuthenticationMethodsClient) ListWindowsHelloMethods(ctx context.Contex
 func (c *AuthenticationMethodsClient) GetWindowsHelloMethod(ctx context.Context, userID, id string, query odata.Query) (*WindowsHelloForBusinessAuthenticationMethod, int, error) {
 	resp, status, _, err := c.BaseClient.Get(ctx, GetHttpRequestInput{
 		ConsistencyFailureFunc: RetryOn404ConsistencyFailureFunc,
+		ConsistencyLevel:       query.ConsistencyLevel,
 		ValidStatusCodes:       []int{http.StatusOK},
 		Uri: Uri{
 			Entity:      fmt.Sprintf("/users/%s/authentication/windowsHelloForBusinessMethods/%s", userID, id),
@@ -307,7 +314,7 @@ func (c *AuthenticationMethodsClient) GetWindowsHelloMethod(ctx context.Context,
 		},
 	})
 	if err != nil {
-		return nil, status, fmt.Errorf("AuthenticationMethodsClient.BaseClient.Get(): %v", err)
+		return nil, status, fmt.Errorf("AuthenticationMethodsClient.BaseClient.Get(): %w", err)
 	}
 
 	defer resp.Body.Close()
@@ -317,7 +324,7 @@ func (c *AuthenticationMethodsClient) GetWindowsHelloMethod(ctx context.Context,
 	}
 
 	var windowsHelloForBusinessMethod WindowsHelloForBusinessAuthenticationMethod
-	if err := json.Unmarshal(respBody, &windowsHelloForBusinessMethod); err != nil {
+	if err := c.BaseClient.decode(respBody, &windowsHelloForBusinessMethod); err != nil {
 		return nil, status, fmt.Errorf("json.Unmarshal(): %v", err)
 	}
 
@@ -334,7 +341,7 @@ func (c *AuthenticationMethodsClient) DeleteWindowsHelloMethod(ctx context.Conte
 		},
 	})
 	if err != nil {
-		return status, fmt.Errorf("AuthenticationMethodsClient.BaseClient.Delete(): %v", err)
+		return status, fmt.Errorf("AuthenticationMethodsClient.BaseClient.Delete(): %w", err)
 	}
 
 	return status, nil
@@ -342,6 +349,7 @@ func (c *AuthenticationMethodsClient) DeleteWindowsHelloMethod(ctx context.Conte
 
 func (c *AuthenticationMethodsClient) ListTemporaryAccessPassMethods(ctx context.Context, userID string, query odata.Query) (*[]TemporaryAccessPassAuthenticationMethod, int, error) {
 	resp, status, _, err := c.BaseClient.Get(ctx, GetHttpRequestInput{
+		ConsistencyLevel: query.ConsistencyLevel,
 		DisablePaging:    query.Top > 0,
 		ValidStatusCodes: []int{http.StatusOK},
 		Uri: Uri{
@@ -351,7 +359,7 @@ func (c *AuthenticationMethodsClient) ListTemporaryAccessPassMethods(ctx context
 		},
 	})
 	if err != nil {
-		return nil, status, fmt.Errorf("AuthenticationMethodsClient.BaseClient.Get(): %v", err)
+		return nil, status, fmt.Errorf("AuthenticationMethodsClient.BaseClient.Get(): %w", err)
 	}
 
 	defer resp.Body.Close()
@@ -363,7 +371,7 @@ func (c *AuthenticationMethodsClient) ListTemporaryAccessPassMethods(ctx context
 	var data struct {
 		TempAccessPassMethods []TemporaryAccessPassAuthenticationMethod `json:"value"`
 	}
-	if err := json.Unmarshal(respBody, &data); err != nil {
+	if err := c.BaseClient.decode(respBody, &data); err != nil {
 		return nil, status, fmt.Errorf("json.Unmarshal(): %v", err)
 	}
 
@@ -373,6 +381,7 @@ func (c *AuthenticationMethodsClient) ListTemporaryAccessPassMethods(ctx context
 func (c *AuthenticationMethodsClient) GetTemporaryAccessPassMethod(ctx context.Context, userID, id string, query odata.Query) (*TemporaryAccessPassAuthenticationMethod, int, error) {
 	resp, status, _, err := c.BaseClient.Get(ctx, GetHttpRequestInput{
 		ConsistencyFailureFunc: RetryOn404ConsistencyFailureFunc,
+		ConsistencyLevel:       query.ConsistencyLevel,
 		ValidStatusCodes:       []int{http.StatusOK},
 		Uri: Uri{
 			Entity:      fmt.Sprintf("/users/%s/authentication/temporaryAccessPassMethods/%s", userID, id),
@@ -381,7 +390,7 @@ func (c *AuthenticationMethodsClient) GetTemporaryAccessPassMethod(ctx context.C
 		},
 	})
 	if err != nil {
-		return nil, status, fmt.Errorf("AuthenticationMethodsClient.BaseClient.Get(): %v", err)
+		return nil, status, fmt.Errorf("AuthenticationMethodsClient.BaseClient.Get(): %w", err)
 	}
 
 	defer resp.Body.Close()
@@ -391,7 +400,7 @@ func (c *AuthenticationMethodsClient) GetTemporaryAccessPassMethod(ctx context.C
 	}
 
 	var temporaryAccessPassMethod TemporaryAccessPassAuthenticationMethod
-	if err := json.Unmarshal(respBody, &temporaryAccessPassMethod); err != nil {
+	if err := c.BaseClient.decode(respBody, &temporaryAccessPassMethod); err != nil {
 		return nil, status, fmt.Errorf("json.Unmarshal(): %v", err)
 	}
 
@@ -415,7 +424,7 @@ func (c *AuthenticationMethodsClient) CreateTemporaryAccessPassMethod(ctx contex
 		},
 	})
 	if err != nil {
-		return nil, status, fmt.Errorf("AuthenticationMethodsClient.BaseClient.Post(): %v", err)
+		return nil, status, fmt.Errorf("AuthenticationMethodsClient.BaseClient.Post(): %w", err)
 	}
 
 	defer resp.Body.Close()
@@ -425,7 +434,7 @@ func (c *AuthenticationMethodsClient) CreateTemporaryAccessPassMethod(ctx contex
 	}
 
 	var newTempAccessPassAuthMethod TemporaryAccessPassAuthenticationMethod
-	if err := json.Unmarshal(respBody, &newTempAccessPassAuthMethod); err != nil {
+	if err := c.BaseClient.decode(respBody, &newTempAccessPassAuthMethod); err != nil {
 		return nil, status, fmt.Errorf("json.Unmarshal(): %v", err)
 	}
 
@@ -442,7 +451,7 @@ func (c *AuthenticationMethodsClient) DeleteTemporaryAccessPassMethod(ctx contex
 		},
 	})
 	if err != nil {
-		return status, fmt.Errorf("AuthenticationMethodsClient.BaseClient.Delete(): %v", err)
+		return status, fmt.Errorf("AuthenticationMethodsClient.BaseClient.Delete(): %w", err)
 	}
 
 	return status, nil
@@ -450,6 +459,7 @@ func (c *AuthenticationMethodsClient) DeleteTemporaryAccessPassMethod(ctx contex
 
 func (c *AuthenticationMethodsClient) ListPhoneMethods(ctx context.Context, userID string, query odata.Query) (*[]PhoneAuthenticationMethod, int, error) {
 	resp, status, _, err := c.BaseClient.Get(ctx, GetHttpRequestInput{
+		ConsistencyLevel: query.ConsistencyLevel,
 		DisablePaging:    query.Top > 0,
 		ValidStatusCodes: []int{http.StatusOK},
 		Uri: Uri{
@@ -459,7 +469,7 @@ func (c *AuthenticationMethodsClient) ListPhoneMethods(ctx context.Context, user
 		},
 	})
 	if err != nil {
-		return nil, status, fmt.Errorf("AuthenticationMethodsClient.BaseClient.Get(): %v", err)
+		return nil, status, fmt.Errorf("AuthenticationMethodsClient.BaseClient.Get(): %w", err)
 	}
 
 	defer resp.Body.Close()
@@ -471,7 +481,7 @@ func (c *AuthenticationMethodsClient) ListPhoneMethods(ctx context.Context, user
 	var data struct {
 		PhoneAuthenticationMethods []PhoneAuthenticationMethod `json:"value"`
 	}
-	if err := json.Unmarshal(respBody, &data); err != nil {
+	if err := c.BaseClient.decode(respBody, &data); err != nil {
 		return nil, status, fmt.Errorf("json.Unmarshal(): %v", err)
 	}
 
@@ -481,6 +491,7 @@ func (c *AuthenticationMethodsClient) ListPhoneMethods(ctx context.Context, user
 func (c *AuthenticationMethodsClient) GetPhoneMethod(ctx context.Context, userID, id string, query odata.Query) (*PhoneAuthenticationMethod, int, error) {
 	resp, status, _, err := c.BaseClient.Get(ctx, GetHttpRequestInput{
 		ConsistencyFailureFunc: RetryOn404ConsistencyFailureFunc,
+		ConsistencyLevel:       query.ConsistencyLevel,
 		ValidStatusCodes:       []int{http.StatusOK},
 		Uri: Uri{
 			Entity:      fmt.Sprintf("/users/%s/authentication/phoneMethods/%s", userID, id),
@@ -489,7 +500,7 @@ func (c *AuthenticationMethodsClient) GetPhoneMethod(ctx context.Context, userID
 		},
 	})
 	if err != nil {
-		return nil, status, fmt.Errorf("AuthenticationMethodsClient.BaseClient.Get(): %v", err)
+		return nil, status, fmt.Errorf("AuthenticationMethodsClient.BaseClient.Get(): %w", err)
 	}
 
 	defer resp.Body.Close()
@@ -499,7 +510,7 @@ func (c *AuthenticationMethodsClient) GetPhoneMethod(ctx context.Context, userID
 	}
 
 	var phoneMethod PhoneAuthenticationMethod
-	if err := json.Unmarshal(respBody, &phoneMethod); err != nil {
+	if err := c.BaseClient.decode(respBody, &phoneMethod); err != nil {
 		return nil, status, fmt.Errorf("json.Unmarshal(): %v", err)
 	}
 
@@ -523,7 +534,7 @@ func (c *AuthenticationMethodsClient) CreatePhoneMethod(ctx context.Context, use
 		},
 	})
 	if err != nil {
-		return nil, status, fmt.Errorf("AuthenticationMethodsClient.BaseClient.Post(): %v", err)
+		return nil, status, fmt.Errorf("AuthenticationMethodsClient.BaseClient.Post(): %w", err)
 	}
 
 	defer resp.Body.Close()
@@ -533,7 +544,7 @@ func (c *AuthenticationMethodsClient) CreatePhoneMethod(ctx context.Context, use
 	}
 
 	var newPhoneMethod PhoneAuthenticationMethod
-	if err := json.Unmarshal(respBody, &newPhoneMethod); err != nil {
+	if err := c.BaseClient.decode(respBody, &newPhoneMethod); err != nil {
 		return nil, status, fmt.Errorf("json.Unmarshal(): %v", err)
 	}
 
@@ -550,7 +561,7 @@ func (c *AuthenticationMethodsClient) DeletePhoneMethod(ctx context.Context, use
 		},
 	})
 	if err != nil {
-		return status, fmt.Errorf("AuthenticationMethodsClient.BaseClient.Delete(): %v", err)
+		return status, fmt.Errorf("AuthenticationMethodsClient.BaseClient.Delete(): %w", err)
 	}
 
 	return status, nil
@@ -578,7 +589,7 @@ func (c *AuthenticationMethodsClient) UpdatePhoneMethod(ctx context.Context, use
 		},
 	})
 	if err != nil {
-		return status, fmt.Errorf("AuthenticationMethodsClient.BaseClient.Put(): %v", err)
+		return status, fmt.Errorf("AuthenticationMethodsClient.BaseClient.Put(): %w", err)
 	}
 
 	return status, nil
@@ -596,7 +607,7 @@ func (c *AuthenticationMethodsClient) EnablePhoneSMS(ctx context.Context, userID
 		},
 	})
 	if err != nil {
-		return status, fmt.Errorf("AuthenticationMethodsClient.BaseClient.Post(): %v", err)
+		return status, fmt.Errorf("AuthenticationMethodsClient.BaseClient.Post(): %w", err)
 	}
 
 	return status, nil
@@ -614,7 +625,7 @@ func (c *AuthenticationMethodsClient) DisablePhoneSMS(ctx context.Context, userI
 		},
 	})
 	if err != nil {
-		return status, fmt.Errorf("AuthenticationMethodsClient.BaseClient.Post(): %v", err)
+		return status, fmt.Errorf("AuthenticationMethodsClient.BaseClient.Post(): %w", err)
 	}
 
 	return status, nil
@@ -622,6 +633,7 @@ func (c *AuthenticationMethodsClient) DisablePhoneSMS(ctx context.Context, userI
 
 func (c *AuthenticationMethodsClient) ListEmailMethods(ctx context.Context, userID string, query odata.Query) (*[]EmailAuthenticationMethod, int, error) {
 	resp, status, _, err := c.BaseClient.Get(ctx, GetHttpRequestInput{
+		ConsistencyLevel: query.ConsistencyLevel,
 		DisablePaging:    query.Top > 0,
 		ValidStatusCodes: []int{http.StatusOK},
 		Uri: Uri{
@@ -631,7 +643,7 @@ func (c *AuthenticationMethodsClient) ListEmailMethods(ctx context.Context, user
 		},
 	})
 	if err != nil {
-		return nil, status, fmt.Errorf("AuthenticationMethodsClient.BaseClient.Get(): %v", err)
+		return nil, status, fmt.Errorf("AuthenticationMethodsClient.BaseClient.Get(): %w", err)
 	}
 
 	defer resp.Body.Close()
@@ -643,7 +655,7 @@ func (c *AuthenticationMethodsClient) ListEmailMethods(ctx context.Context, user
 	var data struct {
 		EmailAuthMethods []EmailAuthenticationMethod `json:"value"`
 	}
-	if err := json.Unmarshal(respBody, &data); err != nil {
+	if err := c.BaseClient.decode(respBody, &data); err != nil {
 		return nil, status, fmt.Errorf("json.Unmarshal(): %v", err)
 	}
 
@@ -653,6 +665,7 @@ func (c *AuthenticationMethodsClient) ListEmailMethods(ctx context.Context, user
 func (c *AuthenticationMethodsClient) GetEmailMethod(ctx context.Context, userID, id string, query odata.Query) (*EmailAuthenticationMethod, int, error) {
 	resp, status, _, err := c.BaseClient.Get(ctx, GetHttpRequestInput{
 		ConsistencyFailureFunc: RetryOn404ConsistencyFailureFunc,
+		ConsistencyLevel:       query.ConsistencyLevel,
 		ValidStatusCodes:       []int{http.StatusOK},
 		Uri: Uri{
 			Entity:      fmt.Sprintf("/users/%s/authentication/emailMethods/%s", userID, id),
@@ -661,7 +674,7 @@ func (c *AuthenticationMethodsClient) GetEmailMethod(ctx context.Context, userID
 		},
 	})
 	if err != nil {
-		return nil, status, fmt.Errorf("AuthenticationMethodsClient.BaseClient.Get(): %v", err)
+		return nil, status, fmt.Errorf("AuthenticationMethodsClient.BaseClient.Get(): %w", err)
 	}
 
 	defer resp.Body.Close()
@@ -671,7 +684,7 @@ func (c *AuthenticationMethodsClient) GetEmailMethod(ctx context.Context, userID
 	}
 
 	var emailMethod EmailAuthenticationMethod
-	if err := json.Unmarshal(respBody, &emailMethod); err != nil {
+	if err := c.BaseClient.decode(respBody, &emailMethod); err != nil {
 		return nil, status, fmt.Errorf("json.Unmarshal(): %v", err)
 	}
 
@@ -700,7 +713,7 @@ func (c *AuthenticationMethodsClient) UpdateEmailMethod(ctx context.Context, use
 		},
 	})
 	if err != nil {
-		return status, fmt.Errorf("AuthenticationMethodsClient.BaseClient.Put(): %v", err)
+		return status, fmt.Errorf("AuthenticationMethodsClient.BaseClient.Put(): %w", err)
 	}
 
 	return status, nil
@@ -716,7 +729,7 @@ func (c *AuthenticationMethodsClient) DeleteEmailMethod(ctx context.Context, use
 		},
 	})
 	if err != nil {
-		return status, fmt.Errorf("AuthenticationMethodsClient.BaseClient.Delete(): %v", err)
+		return status, fmt.Errorf("AuthenticationMethodsClient.BaseClient.Delete(): %w", err)
 	}
 
 	return status, nil
@@ -739,7 +752,7 @@ func (c *AuthenticationMethodsClient) CreateEmailMethod(ctx context.Context, use
 		},
 	})
 	if err != nil {
-		return nil, status, fmt.Errorf("AuthenticationMethodsClient.BaseClient.Post(): %v", err)
+		return nil, status, fmt.Errorf("AuthenticationMethodsClient.BaseClient.Post(): %w", err)
 	}
 
 	defer resp.Body.Close()
@@ -749,7 +762,7 @@ func (c *AuthenticationMethodsClient) CreateEmailMethod(ctx context.Context, use
 	}
 
 	var newEmailMethod EmailAuthenticationMethod
-	if err := json.Unmarshal(respBody, &newEmailMethod); err != nil {
+	if err := c.BaseClient.decode(respBody, &newEmailMethod); err != nil {
 		return nil, status, fmt.Errorf("json.Unmarshal(): %v", err)
 	}
 
@@ -758,6 +771,7 @@ func (c *AuthenticationMethodsClient) CreateEmailMethod(ctx context.Context, use
 
 func (c *AuthenticationMethodsClient) ListPasswordMethods(ctx context.Context, userID string, query odata.Query) (*[]PasswordAuthenticationMethod, int, error) {
 	resp, status, _, err := c.BaseClient.Get(ctx, GetHttpRequestInput{
+		ConsistencyLevel: query.ConsistencyLevel,
 		DisablePaging:    query.Top > 0,
 		ValidStatusCodes: []int{http.StatusOK},
 		Uri: Uri{
@@ -767,7 +781,7 @@ func (c *AuthenticationMethodsClient) ListPasswordMethods(ctx context.Context, u
 		},
 	})
 	if err != nil {
-		return nil, status, fmt.Errorf("AuthenticationMethodsClient.BaseClient.Get(): %v", err)
+		return nil, status, fmt.Errorf("AuthenticationMethodsClient.BaseClient.Get(): %w", err)
 	}
 
 	defer resp.Body.Close()
@@ -779,7 +793,7 @@ func (c *AuthenticationMethodsClient) ListPasswordMethods(ctx context.Context, u
 	var data struct {
 		PasswordMethods []PasswordAuthenticationMethod `json:"value"`
 	}
-	if err := json.Unmarshal(respBody, &data); err != nil {
+	if err := c.BaseClient.decode(respBody, &data); err != nil {
 		return nil, status, fmt.Errorf("json.Unmarshal(): %v", err)
 	}
 
@@ -789,6 +803,7 @@ func (c *AuthenticationMethodsClient) ListPasswordMethods(ctx context.Context, u
 func (c *AuthenticationMethodsClient) GetPasswordMethod(ctx context.Context, userID, id string, query odata.Query) (*PasswordAuthenticationMethod, int, error) {
 	resp, status, _, err := c.BaseClient.Get(ctx, GetHttpRequestInput{
 		ConsistencyFailureFunc: RetryOn404ConsistencyFailureFunc,
+		ConsistencyLevel:       query.ConsistencyLevel,
 		ValidStatusCodes:       []int{http.StatusOK},
 		Uri: Uri{
 			Entity:      fmt.Sprintf("/users/%s/authentication/passwordMethods/%s", userID, id),
@@ -797,7 +812,7 @@ func (c *AuthenticationMethodsClient) GetPasswordMethod(ctx context.Context, use
 		},
 	})
 	if err != nil {
-		return nil, status, fmt.Errorf("AuthenticationMethodsClient.BaseClient.Get(): %v", err)
+		return nil, status, fmt.Errorf("AuthenticationMethodsClient.BaseClient.Get(): %w", err)
 	}
 
 	defer resp.Body.Close()
@@ -807,7 +822,7 @@ func (c *AuthenticationMethodsClient) GetPasswordMethod(ctx context.Context, use
 	}
 
 	var passwordMethod PasswordAuthenticationMethod
-	if err := json.Unmarshal(respBody, &passwordMethod); err != nil {
+	if err := c.BaseClient.decode(respBody, &passwordMethod); err != nil {
 		return nil, status, fmt.Errorf("json.Unmarshal(): %v", err)
 	}
 
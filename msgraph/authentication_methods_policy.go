@@ -0,0 +1,209 @@
+package msgraph
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/manicminer/hamilton/internal/utils"
+	"github.com/manicminer/hamilton/odata"
+)
+
+// AuthenticationMethodsPolicyClient performs operations on the Authentication Methods Policy of a tenant, which
+// determines the authentication methods that users can register and use, such as FIDO2 security keys, the Microsoft
+// Authenticator app and text messages.
+//
+// This client requires the Policy.Read.All permission to read the policy, and the
+// Policy.ReadWrite.AuthenticationMethod permission to update it.
+type AuthenticationMethodsPolicyClient struct {
+	BaseClient Client
+}
+
+// NewAuthenticationMethodsPolicyClient returns a new AuthenticationMethodsPolicyClient.
+func NewAuthenticationMethodsPolicyClient(tenantId string) *AuthenticationMethodsPolicyClient {
+	return &AuthenticationMethodsPolicyClient{
+		BaseClient: NewClient(Version10, tenantId),
+	}
+}
+
+// Get retrieves the AuthenticationMethodsPolicy, including the configuration of each authentication method.
+func (c *AuthenticationMethodsPolicyClient) Get(ctx context.Context) (*AuthenticationMethodsPolicy, int, error) {
+	resp, status, _, err := c.BaseClient.Get(ctx, GetHttpRequestInput{
+		ValidStatusCodes: []int{http.StatusOK},
+		Uri: Uri{
+			Entity:      "/policies/authenticationMethodsPolicy",
+			HasTenantId: true,
+		},
+	})
+	if err != nil {
+		return nil, status, fmt.Errorf("AuthenticationMethodsPolicyClient.BaseClient.Get(): %w", err)
+	}
+
+	defer resp.Body.Close()
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, status, fmt.Errorf("io.ReadAll(): %v", err)
+	}
+
+	var policy AuthenticationMethodsPolicy
+	if err := c.BaseClient.decode(respBody, &policy); err != nil {
+		return nil, status, fmt.Errorf("json.Unmarshal(): %v", err)
+	}
+
+	return &policy, status, nil
+}
+
+// Update amends the AuthenticationMethodsPolicy. Each method configuration included in
+// AuthenticationMethodConfigurations must specify its ID and ODataType.
+func (c *AuthenticationMethodsPolicyClient) Update(ctx context.Context, policy AuthenticationMethodsPolicy) (int, error) {
+	var status int
+
+	body, err := json.Marshal(policy)
+	if err != nil {
+		return status, fmt.Errorf("json.Marshal(): %v", err)
+	}
+
+	_, status, _, err = c.BaseClient.Patch(ctx, PatchHttpRequestInput{
+		Body:             body,
+		ValidStatusCodes: []int{http.StatusOK, http.StatusNoContent},
+		Uri: Uri{
+			Entity:      "/policies/authenticationMethodsPolicy",
+			HasTenantId: true,
+		},
+	})
+	if err != nil {
+		return status, fmt.Errorf("AuthenticationMethodsPolicyClient.BaseClient.Patch(): %w", err)
+	}
+
+	return status, nil
+}
+
+// GetMethodConfiguration retrieves the configuration of an authentication method, e.g. `Fido2`, which can be type
+// asserted to the configuration type for the method.
+func (c *AuthenticationMethodsPolicyClient) GetMethodConfiguration(ctx context.Context, id string) (*AuthenticationMethodConfiguration, int, error) {
+	resp, status, _, err := c.BaseClient.Get(ctx, GetHttpRequestInput{
+		ConsistencyFailureFunc: RetryOn404ConsistencyFailureFunc,
+		ValidStatusCodes:       []int{http.StatusOK},
+		Uri: Uri{
+			Entity:      fmt.Sprintf("/policies/authenticationMethodsPolicy/authenticationMethodConfigurations/%s", id),
+			HasTenantId: true,
+		},
+	})
+	if err != nil {
+		return nil, status, fmt.Errorf("AuthenticationMethodsPolicyClient.BaseClient.Get(): %w", err)
+	}
+
+	defer resp.Body.Close()
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, status, fmt.Errorf("io.ReadAll(): %v", err)
+	}
+
+	config, err := unmarshalAuthenticationMethodConfiguration(respBody)
+	if err != nil {
+		return nil, status, fmt.Errorf("json.Unmarshal(): %v", err)
+	}
+
+	return &config, status, nil
+}
+
+// UpdateFido2 amends the configuration of FIDO2 security keys. The ID defaults to `Fido2` when not specified.
+func (c *AuthenticationMethodsPolicyClient) UpdateFido2(ctx context.Context, config Fido2AuthenticationMethodConfiguration) (int, error) {
+	if config.BaseAuthenticationMethodConfiguration == nil {
+		config.BaseAuthenticationMethodConfiguration = &BaseAuthenticationMethodConfiguration{}
+	}
+	config.setDefaults(odata.TypeFido2AuthenticationMethodConfiguration, "Fido2")
+	return c.updateMethodConfiguration(ctx, *config.ID, config)
+}
+
+// UpdateMicrosoftAuthenticator amends the configuration of the Microsoft Authenticator app. The ID defaults to
+// `MicrosoftAuthenticator` when not specified.
+func (c *AuthenticationMethodsPolicyClient) UpdateMicrosoftAuthenticator(ctx context.Context, config MicrosoftAuthenticatorAuthenticationMethodConfiguration) (int, error) {
+	if config.BaseAuthenticationMethodConfiguration == nil {
+		config.BaseAuthenticationMethodConfiguration = &BaseAuthenticationMethodConfiguration{}
+	}
+	config.setDefaults(odata.TypeMicrosoftAuthenticatorAuthenticationMethodConfiguration, "MicrosoftAuthenticator")
+	return c.updateMethodConfiguration(ctx, *config.ID, config)
+}
+
+// UpdateSms amends the configuration of text messages. The ID defaults to `Sms` when not specified.
+func (c *AuthenticationMethodsPolicyClient) UpdateSms(ctx context.Context, config SmsAuthenticationMethodConfiguration) (int, error) {
+	if config.BaseAuthenticationMethodConfiguration == nil {
+		config.BaseAuthenticationMethodConfiguration = &BaseAuthenticationMethodConfiguration{}
+	}
+	config.setDefaults(odata.TypeSmsAuthenticationMethodConfiguration, "Sms")
+	return c.updateMethodConfiguration(ctx, *config.ID, config)
+}
+
+// updateMethodConfiguration amends the configuration of the authentication method with the specified ID
+func (c *AuthenticationMethodsPolicyClient) updateMethodConfiguration(ctx context.Context, id string, config AuthenticationMethodConfiguration) (int, error) {
+	var status int
+
+	body, err := json.Marshal(config)
+	if err != nil {
+		return status, fmt.Errorf("json.Marshal(): %v", err)
+	}
+
+	_, status, _, err = c.BaseClient.Patch(ctx, PatchHttpRequestInput{
+		Body:                   body,
+		ConsistencyFailureFunc: RetryOn404ConsistencyFailureFunc,
+		ValidStatusCodes:       []int{http.StatusNoContent},
+		Uri: Uri{
+			Entity:      fmt.Sprintf("/policies/authenticationMethodsPolicy/authenticationMethodConfigurations/%s", id),
+			HasTenantId: true,
+		},
+	})
+	if err != nil {
+		return status, fmt.Errorf("AuthenticationMethodsPolicyClient.BaseClient.Patch(): %w", err)
+	}
+
+	return status, nil
+}
+
+// setDefaults sets the type of a method configuration, and its ID when not specified
+func (c *BaseAuthenticationMethodConfiguration) setDefaults(odataType odata.Type, id string) {
+	c.ODataType = utils.StringPtr(odataType)
+	if c.ID == nil || *c.ID == "" {
+		c.ID = utils.StringPtr(id)
+	}
+}
+
+// unmarshalAuthenticationMethodConfiguration decodes a method configuration into the model for its type
+func unmarshalAuthenticationMethodConfiguration(data []byte) (AuthenticationMethodConfiguration, error) {
+	var o odata.OData
+	if err := json.Unmarshal(data, &o); err != nil {
+		return nil, err
+	}
+
+	var odataType odata.Type
+	if o.Type != nil {
+		odataType = *o.Type
+	}
+
+	var config AuthenticationMethodConfiguration
+	var err error
+	switch odataType {
+	case odata.TypeFido2AuthenticationMethodConfiguration:
+		var fido2 Fido2AuthenticationMethodConfiguration
+		err = json.Unmarshal(data, &fido2)
+		config = fido2
+	case odata.TypeMicrosoftAuthenticatorAuthenticationMethodConfiguration:
+		var authenticator MicrosoftAuthenticatorAuthenticationMethodConfiguration
+		err = json.Unmarshal(data, &authenticator)
+		config = authenticator
+	case odata.TypeSmsAuthenticationMethodConfiguration:
+		var sms SmsAuthenticationMethodConfiguration
+		err = json.Unmarshal(data, &sms)
+		config = sms
+	default:
+		var base BaseAuthenticationMethodConfiguration
+		err = json.Unmarshal(data, &base)
+		config = base
+	}
+	if err != nil {
+		return nil, err
+	}
+	return config, nil
+}
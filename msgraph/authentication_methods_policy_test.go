@@ -0,0 +1,128 @@
+package msgraph_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/manicminer/hamilton/environments"
+	"github.com/manicminer/hamilton/internal/utils"
+	"github.com/manicminer/hamilton/msgraph"
+	"github.com/manicminer/hamilton/odata"
+)
+
+func TestAuthenticationMethodsPolicyClient(t *testing.T) {
+	const basePath = "/v1.0/00000000-0000-0000-0000-000000000000/policies/authenticationMethodsPolicy"
+	patches := make(map[string]map[string]interface{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == basePath:
+			w.Header().Set("Content-Type", "application/json")
+			fmt.Fprint(w, `{
+				"id": "authenticationMethodsPolicy",
+				"displayName": "Authentication Methods Policy",
+				"policyVersion": "1.5",
+				"authenticationMethodConfigurations": [
+					{"@odata.type":"#microsoft.graph.fido2AuthenticationMethodConfiguration","id":"Fido2","state":"enabled","isAttestationEnforced":true,"isSelfServiceRegistrationAllowed":true,"includeTargets":[{"targetType":"group","id":"all_users","isRegistrationRequired":false}],"excludeTargets":[]},
+					{"@odata.type":"#microsoft.graph.microsoftAuthenticatorAuthenticationMethodConfiguration","id":"MicrosoftAuthenticator","state":"enabled","includeTargets":[{"targetType":"group","id":"all_users","authenticationMode":"any"}],"excludeTargets":[{"targetType":"group","id":"11111111-1111-1111-1111-111111111111"}]},
+					{"@odata.type":"#microsoft.graph.smsAuthenticationMethodConfiguration","id":"Sms","state":"disabled","includeTargets":[{"targetType":"group","id":"all_users","isUsableForSignIn":true}]},
+					{"@odata.type":"#microsoft.graph.voiceAuthenticationMethodConfiguration","id":"Voice","state":"disabled"}
+				]
+			}`)
+		case r.Method == http.MethodGet && r.URL.Path == basePath+"/authenticationMethodConfigurations/Sms":
+			w.Header().Set("Content-Type", "application/json")
+			fmt.Fprint(w, `{"@odata.type":"#microsoft.graph.smsAuthenticationMethodConfiguration","id":"Sms","state":"disabled"}`)
+		case r.Method == http.MethodPatch:
+			var body map[string]interface{}
+			if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+				t.Errorf("could not decode request body: %v", err)
+			}
+			patches[r.URL.Path] = body
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprint(w, `{"error":{"code":"NotFound","message":"not found"}}`)
+		}
+	}))
+	defer server.Close()
+
+	c := msgraph.NewAuthenticationMethodsPolicyClient("00000000-0000-0000-0000-000000000000")
+	c.BaseClient.Endpoint = environments.ApiEndpoint(server.URL)
+
+	policy, _, err := c.Get(context.Background())
+	if err != nil {
+		t.Fatalf("AuthenticationMethodsPolicyClient.Get(): %v", err)
+	}
+	if policy.AuthenticationMethodConfigurations == nil || len(*policy.AuthenticationMethodConfigurations) != 4 {
+		t.Fatalf("AuthenticationMethodsPolicyClient.Get(): expected 4 method configurations, got %v", policy.AuthenticationMethodConfigurations)
+	}
+	configs := *policy.AuthenticationMethodConfigurations
+	if fido2, ok := configs[0].(msgraph.Fido2AuthenticationMethodConfiguration); !ok || fido2.IsAttestationEnforced == nil || !*fido2.IsAttestationEnforced || *fido2.State != msgraph.AuthenticationMethodStateEnabled {
+		t.Fatalf("AuthenticationMethodsPolicyClient.Get(): unexpected FIDO2 configuration %#v", configs[0])
+	}
+	if authenticator, ok := configs[1].(msgraph.MicrosoftAuthenticatorAuthenticationMethodConfiguration); !ok || len(*authenticator.ExcludeTargets) != 1 || *(*authenticator.IncludeTargets)[0].AuthenticationMode != msgraph.MicrosoftAuthenticatorAuthenticationModeAny {
+		t.Fatalf("AuthenticationMethodsPolicyClient.Get(): unexpected Microsoft Authenticator configuration %#v", configs[1])
+	}
+	if sms, ok := configs[2].(msgraph.SmsAuthenticationMethodConfiguration); !ok || *sms.State != msgraph.AuthenticationMethodStateDisabled || !*(*sms.IncludeTargets)[0].IsUsableForSignIn {
+		t.Fatalf("AuthenticationMethodsPolicyClient.Get(): unexpected SMS configuration %#v", configs[2])
+	}
+	if voice, ok := configs[3].(msgraph.BaseAuthenticationMethodConfiguration); !ok || *voice.ID != "Voice" {
+		t.Fatalf("AuthenticationMethodsPolicyClient.Get(): unexpected Voice configuration %#v", configs[3])
+	}
+
+	config, _, err := c.GetMethodConfiguration(context.Background(), "Sms")
+	if err != nil {
+		t.Fatalf("AuthenticationMethodsPolicyClient.GetMethodConfiguration(): %v", err)
+	}
+	if _, ok := (*config).(msgraph.SmsAuthenticationMethodConfiguration); !ok {
+		t.Fatalf("AuthenticationMethodsPolicyClient.GetMethodConfiguration(): expected SmsAuthenticationMethodConfiguration, got %T", *config)
+	}
+
+	if _, err := c.UpdateFido2(context.Background(), msgraph.Fido2AuthenticationMethodConfiguration{
+		BaseAuthenticationMethodConfiguration: &msgraph.BaseAuthenticationMethodConfiguration{
+			State: utils.StringPtr(msgraph.AuthenticationMethodStateDisabled),
+			ExcludeTargets: &[]msgraph.ExcludeTarget{
+				{ID: utils.StringPtr("11111111-1111-1111-1111-111111111111"), TargetType: utils.StringPtr(msgraph.AuthenticationMethodTargetTypeGroup)},
+			},
+		},
+	}); err != nil {
+		t.Fatalf("AuthenticationMethodsPolicyClient.UpdateFido2(): %v", err)
+	}
+	fido2 := patches[basePath+"/authenticationMethodConfigurations/Fido2"]
+	if fido2["@odata.type"] != odata.TypeFido2AuthenticationMethodConfiguration || fido2["state"] != "disabled" || len(fido2["excludeTargets"].([]interface{})) != 1 {
+		t.Fatalf("AuthenticationMethodsPolicyClient.UpdateFido2(): unexpected request body %v", fido2)
+	}
+
+	if _, err := c.UpdateMicrosoftAuthenticator(context.Background(), msgraph.MicrosoftAuthenticatorAuthenticationMethodConfiguration{
+		IncludeTargets: &[]msgraph.AuthenticationMethodTarget{
+			{ID: utils.StringPtr("all_users"), TargetType: utils.StringPtr(msgraph.AuthenticationMethodTargetTypeGroup), AuthenticationMode: utils.StringPtr(msgraph.MicrosoftAuthenticatorAuthenticationModePush)},
+		},
+	}); err != nil {
+		t.Fatalf("AuthenticationMethodsPolicyClient.UpdateMicrosoftAuthenticator(): %v", err)
+	}
+	if authenticator := patches[basePath+"/authenticationMethodConfigurations/MicrosoftAuthenticator"]; authenticator["@odata.type"] != odata.TypeMicrosoftAuthenticatorAuthenticationMethodConfiguration {
+		t.Fatalf("AuthenticationMethodsPolicyClient.UpdateMicrosoftAuthenticator(): unexpected request body %v", authenticator)
+	}
+
+	if _, err := c.UpdateSms(context.Background(), msgraph.SmsAuthenticationMethodConfiguration{
+		BaseAuthenticationMethodConfiguration: &msgraph.BaseAuthenticationMethodConfiguration{
+			State: utils.StringPtr(msgraph.AuthenticationMethodStateEnabled),
+		},
+	}); err != nil {
+		t.Fatalf("AuthenticationMethodsPolicyClient.UpdateSms(): %v", err)
+	}
+	if sms := patches[basePath+"/authenticationMethodConfigurations/Sms"]; sms["state"] != "enabled" {
+		t.Fatalf("AuthenticationMethodsPolicyClient.UpdateSms(): unexpected request body %v", sms)
+	}
+
+	if _, err := c.Update(context.Background(), msgraph.AuthenticationMethodsPolicy{ReconfirmationInDays: utils.IntPtr(90)}); err != nil {
+		t.Fatalf("AuthenticationMethodsPolicyClient.Update(): %v", err)
+	}
+	if p := patches[basePath]; p["reconfirmationInDays"] != float64(90) {
+		t.Fatalf("AuthenticationMethodsPolicyClient.Update(): unexpected request body %v", p)
+	}
+}
@@ -0,0 +1,256 @@
+package msgraph
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/manicminer/hamilton/odata"
+)
+
+// batchMaxRequests is the maximum number of requests which can be sent in a single JSON batch
+const batchMaxRequests = 20
+
+// batchDefaultRetryAfter is the delay before retrying throttled requests that did not specify a Retry-After header
+const batchDefaultRetryAfter = 5 * time.Second
+
+// batchDeleteMaxRetries is the number of times DeleteMany re-submits throttled requests in each batch
+const batchDeleteMaxRetries = 5
+
+// BatchClient sends multiple requests to Microsoft Graph in a single HTTP request using JSON batching.
+type BatchClient struct {
+	BaseClient Client
+}
+
+// NewBatchClient returns a new BatchClient.
+func NewBatchClient(tenantId string) *BatchClient {
+	return &BatchClient{
+		BaseClient: NewClient(Version10, tenantId),
+	}
+}
+
+// Send submits up to 20 requests in a single batch and returns the individual responses.
+// Note that the returned status reflects the batch request as a whole, the status of each request is contained in its BatchResponse.
+func (c *BatchClient) Send(ctx context.Context, requests []BatchRequest) (*[]BatchResponse, int, error) {
+	var status int
+
+	if len(requests) == 0 {
+		return nil, status, fmt.Errorf("BatchClient.Send(): no requests were specified")
+	}
+	if len(requests) > batchMaxRequests {
+		return nil, status, fmt.Errorf("BatchClient.Send(): a batch cannot contain more than %d requests, got %d", batchMaxRequests, len(requests))
+	}
+
+	body, err := json.Marshal(struct {
+		Requests []BatchRequest `json:"requests"`
+	}{
+		Requests: requests,
+	})
+	if err != nil {
+		return nil, status, fmt.Errorf("json.Marshal(): %v", err)
+	}
+
+	resp, status, _, err := c.BaseClient.Post(ctx, PostHttpRequestInput{
+		Body:             body,
+		ValidStatusCodes: []int{http.StatusOK},
+		Uri: Uri{
+			Entity:      "/$batch",
+			HasTenantId: true,
+		},
+	})
+	if err != nil {
+		return nil, status, fmt.Errorf("BatchClient.BaseClient.Post(): %w", err)
+	}
+
+	defer resp.Body.Close()
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, status, fmt.Errorf("io.ReadAll(): %v", err)
+	}
+
+	var data struct {
+		Responses []BatchResponse `json:"responses"`
+	}
+	if err := c.BaseClient.decode(respBody, &data); err != nil {
+		return nil, status, fmt.Errorf("json.Unmarshal(): %v", err)
+	}
+
+	return &data.Responses, status, nil
+}
+
+// SendWithRetries submits up to 20 requests in a single batch. Any requests which are throttled are re-submitted after
+// honoring their Retry-After header, up to maxRetries times, along with any requests which failed only because they
+// depend on a throttled request. The final responses are returned keyed by request ID, together with a sorted list of
+// the IDs of requests which did not ultimately succeed.
+func (c *BatchClient) SendWithRetries(ctx context.Context, requests []BatchRequest, maxRetries int) (map[string]BatchResponse, []string, int, error) {
+	responses := make(map[string]BatchResponse, len(requests))
+	pending := requests
+	var status int
+
+	for attempt := 0; ; attempt++ {
+		resp, s, err := c.Send(ctx, pending)
+		status = s
+		if err != nil {
+			return nil, nil, status, fmt.Errorf("BatchClient.Send(): %v", err)
+		}
+
+		throttled := make(map[string]bool)
+		var retryAfter time.Duration
+		for _, r := range *resp {
+			responses[r.ID] = r
+			if r.Status == http.StatusTooManyRequests || r.Status == http.StatusServiceUnavailable {
+				throttled[r.ID] = true
+				if d := batchRetryAfter(r); d > retryAfter {
+					retryAfter = d
+				}
+			}
+		}
+		if len(throttled) == 0 || attempt >= maxRetries {
+			break
+		}
+
+		pending = batchRetryRequests(requests, responses, throttled)
+
+		select {
+		case <-ctx.Done():
+			return nil, nil, status, fmt.Errorf("BatchClient.SendWithRetries(): %v", ctx.Err())
+		case <-time.After(retryAfter):
+		}
+	}
+
+	failed := make([]string, 0)
+	for _, r := range requests {
+		if resp, ok := responses[r.ID]; !ok || resp.Status < 200 || resp.Status >= 300 {
+			failed = append(failed, r.ID)
+		}
+	}
+	sort.Strings(failed)
+
+	return responses, failed, status, nil
+}
+
+// DeleteMany deletes the objects at the specified relative URLs, e.g. `/users/{id}`, sending up to 20 requests in each
+// batch. Throttled requests are re-submitted after honoring their Retry-After header. Objects which are not found are
+// assumed to have already been deleted and are counted separately. The returned result describes the outcome for each
+// object, and an error is only returned when a batch could not be sent, in which case later batches are not sent.
+func (c *BatchClient) DeleteMany(ctx context.Context, refs []string) (*BatchDeleteResult, int, error) {
+	result := BatchDeleteResult{
+		Failures: make([]BatchDeleteFailure, 0),
+	}
+	var status int
+
+	for start := 0; start < len(refs); start += batchMaxRequests {
+		end := start + batchMaxRequests
+		if end > len(refs) {
+			end = len(refs)
+		}
+
+		requests := make([]BatchRequest, 0, end-start)
+		for i, ref := range refs[start:end] {
+			if !strings.HasPrefix(ref, "/") {
+				ref = "/" + ref
+			}
+			requests = append(requests, BatchRequest{
+				ID:     strconv.Itoa(i),
+				Method: http.MethodDelete,
+				Url:    ref,
+			})
+		}
+
+		responses, _, s, err := c.SendWithRetries(ctx, requests, batchDeleteMaxRetries)
+		status = s
+		if err != nil {
+			return nil, status, fmt.Errorf("BatchClient.SendWithRetries(): %v", err)
+		}
+
+		for i, ref := range refs[start:end] {
+			resp, ok := responses[strconv.Itoa(i)]
+			switch {
+			case ok && resp.Status >= 200 && resp.Status < 300:
+				result.Deleted++
+			case ok && resp.Status == http.StatusNotFound:
+				result.NotFound++
+			default:
+				result.Failures = append(result.Failures, BatchDeleteFailure{
+					Ref:    ref,
+					Status: resp.Status,
+					Error:  batchResponseError(resp),
+				})
+			}
+		}
+	}
+
+	return &result, status, nil
+}
+
+// batchResponseError returns the error contained in the body of a failed BatchResponse, if any
+func batchResponseError(r BatchResponse) *odata.Error {
+	if len(r.Body) == 0 {
+		return nil
+	}
+	var o odata.OData
+	if err := json.Unmarshal(r.Body, &o); err != nil {
+		return nil
+	}
+	return o.Error
+}
+
+// batchRetryAfter returns the delay requested by the Retry-After header of a throttled response
+func batchRetryAfter(r BatchResponse) time.Duration {
+	for k, v := range r.Headers {
+		if strings.EqualFold(k, "Retry-After") {
+			if secs, err := strconv.Atoi(strings.TrimSpace(v)); err == nil && secs >= 0 {
+				return time.Duration(secs) * time.Second
+			}
+		}
+	}
+	return batchDefaultRetryAfter
+}
+
+// batchRetryRequests returns the original requests to re-submit, being those which were throttled and those which
+// failed due to a dependency on a throttled request. Dependencies on requests that are not being re-submitted are removed.
+func batchRetryRequests(requests []BatchRequest, responses map[string]BatchResponse, throttled map[string]bool) []BatchRequest {
+	retry := make(map[string]bool, len(throttled))
+	for id := range throttled {
+		retry[id] = true
+	}
+
+	// Requests can depend on one another in a chain, so repeat until no further dependents are found
+	for found := true; found; {
+		found = false
+		for _, r := range requests {
+			if retry[r.ID] || responses[r.ID].Status != http.StatusFailedDependency {
+				continue
+			}
+			for _, d := range r.DependsOn {
+				if retry[d] {
+					retry[r.ID] = true
+					found = true
+					break
+				}
+			}
+		}
+	}
+
+	ret := make([]BatchRequest, 0, len(retry))
+	for _, r := range requests {
+		if !retry[r.ID] {
+			continue
+		}
+		var dependsOn []string
+		for _, d := range r.DependsOn {
+			if retry[d] {
+				dependsOn = append(dependsOn, d)
+			}
+		}
+		r.DependsOn = dependsOn
+		ret = append(ret, r)
+	}
+	return ret
+}
@@ -0,0 +1,161 @@
+package msgraph_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/manicminer/hamilton/environments"
+	"github.com/manicminer/hamilton/msgraph"
+)
+
+func TestBatchClient_SendWithRetries(t *testing.T) {
+	var submitted [][]string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !strings.HasSuffix(r.URL.Path, "/$batch") {
+			t.Errorf("unexpected request path: %s", r.URL.Path)
+		}
+		var batch struct {
+			Requests []msgraph.BatchRequest `json:"requests"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&batch); err != nil {
+			t.Errorf("json.Decode(): %v", err)
+		}
+
+		ids := make([]string, 0)
+		responses := make([]msgraph.BatchResponse, 0)
+		for _, req := range batch.Requests {
+			ids = append(ids, req.ID)
+			resp := msgraph.BatchResponse{ID: req.ID, Status: http.StatusOK}
+			switch {
+			case req.ID == "b" && len(submitted) == 0:
+				// Throttled on the first attempt only
+				resp.Status = http.StatusTooManyRequests
+				resp.Headers = map[string]string{"Retry-After": "0"}
+			case req.ID == "c" && len(submitted) == 0:
+				resp.Status = http.StatusFailedDependency
+			case req.ID == "d":
+				// Always throttled
+				resp.Status = http.StatusTooManyRequests
+				resp.Headers = map[string]string{"retry-after": "0"}
+			}
+			responses = append(responses, resp)
+		}
+		submitted = append(submitted, ids)
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{"responses": responses})
+	}))
+	defer server.Close()
+
+	c := msgraph.NewBatchClient("00000000-0000-0000-0000-000000000000")
+	c.BaseClient.Endpoint = environments.ApiEndpoint(server.URL)
+
+	requests := []msgraph.BatchRequest{
+		{ID: "a", Method: http.MethodGet, Url: "/users/a"},
+		{ID: "b", Method: http.MethodGet, Url: "/users/b"},
+		{ID: "c", Method: http.MethodGet, Url: "/users/c", DependsOn: []string{"a", "b"}},
+		{ID: "d", Method: http.MethodGet, Url: "/users/d"},
+	}
+	responses, failed, _, err := c.SendWithRetries(context.Background(), requests, 2)
+	if err != nil {
+		t.Fatalf("BatchClient.SendWithRetries(): %v", err)
+	}
+
+	expectedSubmitted := [][]string{{"a", "b", "c", "d"}, {"b", "c", "d"}, {"d"}}
+	if !reflect.DeepEqual(submitted, expectedSubmitted) {
+		t.Fatalf("BatchClient.SendWithRetries(): expected batches %v, got %v", expectedSubmitted, submitted)
+	}
+	for _, id := range []string{"a", "b", "c"} {
+		if responses[id].Status != http.StatusOK {
+			t.Fatalf("BatchClient.SendWithRetries(): expected status 200 for request %q, got %d", id, responses[id].Status)
+		}
+	}
+	if expected := []string{"d"}; !reflect.DeepEqual(failed, expected) {
+		t.Fatalf("BatchClient.SendWithRetries(): expected failed requests %v, got %v", expected, failed)
+	}
+}
+
+func TestBatchClient_SendTooManyRequests(t *testing.T) {
+	c := msgraph.NewBatchClient("00000000-0000-0000-0000-000000000000")
+	requests := make([]msgraph.BatchRequest, 0)
+	for i := 0; i < 21; i++ {
+		requests = append(requests, msgraph.BatchRequest{ID: fmt.Sprintf("%d", i), Method: http.MethodGet, Url: "/me"})
+	}
+	if _, _, err := c.Send(context.Background(), requests); err == nil {
+		t.Fatal("BatchClient.Send(): expected an error for more than 20 requests")
+	}
+}
+
+func TestBatchClient_DeleteMany(t *testing.T) {
+	var batches int
+	throttled := false
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var batch struct {
+			Requests []msgraph.BatchRequest `json:"requests"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&batch); err != nil {
+			t.Errorf("json.Decode(): %v", err)
+		}
+		batches++
+
+		responses := make([]msgraph.BatchResponse, 0)
+		for _, req := range batch.Requests {
+			if req.Method != http.MethodDelete {
+				t.Errorf("unexpected method %q for request %q", req.Method, req.Url)
+			}
+			resp := msgraph.BatchResponse{ID: req.ID, Status: http.StatusNoContent}
+			switch req.Url {
+			case "/users/user3":
+				resp.Status = http.StatusNotFound
+			case "/users/user7":
+				resp.Status = http.StatusForbidden
+				resp.Body = json.RawMessage(`{"error":{"code":"Authorization_RequestDenied","message":"Insufficient privileges to complete the operation."}}`)
+			case "/users/user25":
+				if !throttled {
+					throttled = true
+					resp.Status = http.StatusTooManyRequests
+					resp.Headers = map[string]string{"Retry-After": "0"}
+				}
+			}
+			responses = append(responses, resp)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{"responses": responses})
+	}))
+	defer server.Close()
+
+	c := msgraph.NewBatchClient("00000000-0000-0000-0000-000000000000")
+	c.BaseClient.Endpoint = environments.ApiEndpoint(server.URL)
+
+	refs := make([]string, 0)
+	for i := 0; i < 45; i++ {
+		refs = append(refs, fmt.Sprintf("users/user%d", i))
+	}
+	result, _, err := c.DeleteMany(context.Background(), refs)
+	if err != nil {
+		t.Fatalf("BatchClient.DeleteMany(): %v", err)
+	}
+	if result == nil {
+		t.Fatal("BatchClient.DeleteMany(): result was nil")
+	}
+
+	if batches != 4 {
+		t.Fatalf("BatchClient.DeleteMany(): expected 4 batches including a retry, got %d", batches)
+	}
+	if result.Deleted != 43 || result.NotFound != 1 {
+		t.Fatalf("BatchClient.DeleteMany(): expected 43 deleted and 1 not found, got %d and %d", result.Deleted, result.NotFound)
+	}
+	if len(result.Failures) != 1 {
+		t.Fatalf("BatchClient.DeleteMany(): expected 1 failure, got %d", len(result.Failures))
+	}
+	if f := result.Failures[0]; f.Ref != "users/user7" || f.Status != http.StatusForbidden || f.Error == nil || f.Error.Code == nil || *f.Error.Code != "Authorization_RequestDenied" {
+		t.Fatalf("BatchClient.DeleteMany(): unexpected failure %+v", f)
+	}
+}
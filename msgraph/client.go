@@ -3,17 +3,26 @@ package msgraph
 import (
 	"bytes"
 	"context"
+	"crypto/tls"
 	"encoding/json"
 	"fmt"
 	"io"
+	"log"
+	"math/rand"
+	"mime"
 	"net/http"
 	"net/url"
+	"reflect"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/hashicorp/go-retryablehttp"
+	"github.com/hashicorp/go-uuid"
 
 	"github.com/manicminer/hamilton/auth"
 	"github.com/manicminer/hamilton/environments"
+	"github.com/manicminer/hamilton/errors"
 	"github.com/manicminer/hamilton/odata"
 )
 
@@ -38,6 +47,186 @@ func RetryOn404ConsistencyFailureFunc(resp *http.Response, _ *odata.OData) bool
 	return resp != nil && resp.StatusCode == http.StatusNotFound
 }
 
+type contextKey string
+
+const (
+	// correlationIdContextKey is the context key under which a correlation ID is stored by WithCorrelationId
+	correlationIdContextKey contextKey = "correlationId"
+
+	// headersContextKey is the context key under which custom request headers are stored by WithHeaders
+	headersContextKey contextKey = "headers"
+
+	// pageInfoContextKey is the context key under which a PageInfo is stored by WithPageInfo
+	pageInfoContextKey contextKey = "pageInfo"
+)
+
+// WithCorrelationId returns a copy of ctx carrying the specified correlation ID. All requests made using the returned
+// context will send this ID in the client-request-id header, so that they can be correlated in the tenant's logs.
+// When a request is made using a context without a correlation ID, a random ID is generated for that request.
+func WithCorrelationId(ctx context.Context, correlationId string) context.Context {
+	return context.WithValue(ctx, correlationIdContextKey, correlationId)
+}
+
+// CorrelationIdFromContext returns the correlation ID carried by ctx, or an empty string if none is present.
+func CorrelationIdFromContext(ctx context.Context) string {
+	if v, ok := ctx.Value(correlationIdContextKey).(string); ok {
+		return v
+	}
+	return ""
+}
+
+// WithHeaders returns a copy of ctx carrying additional HTTP headers, which are sent with all requests made using the
+// returned context. Headers already carried by ctx are retained, with new values for the same header being appended.
+// This can be used to send headers required by some API features, such as Prefer or ConsistencyLevel.
+//
+// Custom headers are merged with the headers set by the client. Where a conflicting header is supplied, the value set
+// by the client takes precedence, which applies to the Accept, Authorization, Content-Type and User-Agent headers. A
+// client-request-id header is honored unless a correlation ID has been set using WithCorrelationId.
+func WithHeaders(ctx context.Context, headers http.Header) context.Context {
+	merged := HeadersFromContext(ctx).Clone()
+	if merged == nil {
+		merged = http.Header{}
+	}
+	for k, v := range headers {
+		for _, val := range v {
+			merged.Add(k, val)
+		}
+	}
+	return context.WithValue(ctx, headersContextKey, merged)
+}
+
+// WithAnchorMailbox returns a copy of ctx carrying the X-AnchorMailbox header with the specified user principal name,
+// which is sent with all requests made using the returned context. When operating on a user's mailbox data, such as
+// their messages, events or contacts, this allows Microsoft Graph to route requests directly to the Exchange backend
+// hosting the mailbox, reducing latency and the likelihood of requests being throttled. Any anchor mailbox already
+// carried by ctx is replaced.
+func WithAnchorMailbox(ctx context.Context, userPrincipalName string) context.Context {
+	headers := HeadersFromContext(ctx).Clone()
+	if headers == nil {
+		headers = http.Header{}
+	}
+	headers.Set("X-AnchorMailbox", userPrincipalName)
+	return context.WithValue(ctx, headersContextKey, headers)
+}
+
+// HeadersFromContext returns the custom HTTP headers carried by ctx, or nil if none are present.
+func HeadersFromContext(ctx context.Context) http.Header {
+	if v, ok := ctx.Value(headersContextKey).(http.Header); ok {
+		return v
+	}
+	return nil
+}
+
+// PageInfo describes the final page of results returned by a list request, for use when paging manually.
+type PageInfo struct {
+	// NextLink is the URL of the next page of results, or nil when there are no further pages.
+	NextLink *string
+
+	// SkipToken is the $skiptoken parameter from NextLink, which can be set as the SkipToken of an odata.Query to
+	// retrieve the next page. Empty when there are no further pages.
+	SkipToken string
+}
+
+// WithPageInfo returns a copy of ctx and a PageInfo, which is populated after each list request made using the returned
+// context. This can be combined with odata.Query's Top and SkipToken fields to page through a collection one page at
+// a time, e.g. to store a compact cursor rather than the full next link. When several requests are made using the
+// same context, the PageInfo describes the most recent response.
+func WithPageInfo(ctx context.Context) (context.Context, *PageInfo) {
+	info := &PageInfo{}
+	return context.WithValue(ctx, pageInfoContextKey, info), info
+}
+
+// PagedResult is the error returned when retrieving a further page of a collection fails, after one or more pages were
+// retrieved successfully. It carries the items retrieved before the failure, and the link for the page which failed,
+// so that progress can be persisted and the enumeration resumed later, e.g. by supplying SkipToken() as the SkipToken
+// of an otherwise identical odata.Query. List methods wrap this error, so use errors.As to retrieve it.
+type PagedResult struct {
+	// Items are the JSON encoded items from the pages which were retrieved successfully.
+	Items []json.RawMessage
+
+	// NextLink is the URL of the page which could not be retrieved.
+	NextLink string
+
+	// Err is the error encountered when retrieving the page.
+	Err error
+}
+
+// newPagedResult returns a PagedResult for a failure to retrieve the page at nextLink, which follows the page in
+// respBody. When err is a PagedResult for a subsequent page, the items from respBody are prepended to it.
+func newPagedResult(respBody []byte, nextLink string, err error) error {
+	var page struct {
+		Value []json.RawMessage `json:"value"`
+	}
+	if e := json.Unmarshal(respBody, &page); e != nil {
+		return err
+	}
+	if result, ok := err.(*PagedResult); ok {
+		result.Items = append(page.Value, result.Items...)
+		return result
+	}
+	return &PagedResult{
+		Items:    page.Value,
+		NextLink: nextLink,
+		Err:      err,
+	}
+}
+
+// Error returns an error string for PagedResult.
+func (r *PagedResult) Error() string {
+	return fmt.Sprintf("retrieving page after %d items: %v", len(r.Items), r.Err)
+}
+
+// Unwrap returns the error encountered when retrieving the page.
+func (r *PagedResult) Unwrap() error {
+	return r.Err
+}
+
+// SkipToken returns the $skiptoken parameter from NextLink, which can be set as the SkipToken of an odata.Query to
+// resume the enumeration from the page which failed.
+func (r *PagedResult) SkipToken() string {
+	return odata.OData{NextLink: &r.NextLink}.SkipToken()
+}
+
+// Unmarshal decodes the Items into v, which should be a pointer to a slice of the appropriate model, e.g. *[]User.
+func (r *PagedResult) Unmarshal(v interface{}) error {
+	data, err := json.Marshal(r.Items)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, v)
+}
+
+// JitterBackoff returns a retryablehttp.Backoff that applies random jitter to the exponential backoff calculated by
+// retryablehttp.DefaultBackoff, so that many clients retrying at the same time do not retry in lockstep. The delay
+// requested by a Retry-After header is always honored without jitter. Supply a seeded source for deterministic results.
+func JitterBackoff(source *rand.Rand) retryablehttp.Backoff {
+	var mutex sync.Mutex
+	return func(min, max time.Duration, attemptNum int, resp *http.Response) time.Duration {
+		backoff := retryablehttp.DefaultBackoff(min, max, attemptNum, resp)
+		if resp != nil && (resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode == http.StatusServiceUnavailable) && resp.Header.Get("Retry-After") != "" {
+			return backoff
+		}
+		if half := int64(backoff / 2); half > 0 {
+			mutex.Lock()
+			backoff = time.Duration(half + source.Int63n(half))
+			mutex.Unlock()
+		}
+		return backoff
+	}
+}
+
+// checkRetryContextKey is the context key for the retry check of an individual request.
+type checkRetryContextKey struct{}
+
+// checkRetry invokes the retry check attached to the request context by performRequest, so that the retryable client
+// can be shared by concurrent requests.
+func checkRetry(ctx context.Context, resp *http.Response, err error) (bool, error) {
+	if f, ok := ctx.Value(checkRetryContextKey{}).(retryablehttp.CheckRetry); ok {
+		return f(ctx, resp, err)
+	}
+	return retryablehttp.DefaultRetryPolicy(ctx, resp, err)
+}
+
 // ValidStatusFunc is a function that tests whether an HTTP response is considered valid for the particular request.
 type ValidStatusFunc func(*http.Response, *odata.OData) bool
 
@@ -58,7 +247,9 @@ type Uri struct {
 // Client is a base client to be used by clients for specific entities.
 // It can send GET, POST, PUT, PATCH and DELETE requests to Microsoft Graph and is API version and tenant aware.
 type Client struct {
-	// Endpoint is the base endpoint for Microsoft Graph, usually "https://graph.microsoft.com".
+	// Endpoint is the base endpoint for Microsoft Graph, usually "https://graph.microsoft.com". This can include a path,
+	// e.g. "http://localhost:8080/graph" to send requests via a recording proxy for testing, in which case the API
+	// version and entity path are appended to it. Links to further pages of results are also rewritten to use it.
 	Endpoint environments.ApiEndpoint
 
 	// ApiVersion is the Microsoft Graph API version to use.
@@ -83,14 +274,46 @@ type Client struct {
 	// ResponseMiddlewares is a slice of functions that are called in order before a response is parsed and returned
 	ResponseMiddlewares *[]ResponseMiddleware
 
+	// Metadata specifies the amount of OData control information, such as @odata.type, to request in responses.
+	// When not set, the API default is used, which is equivalent to odata.MetadataMinimal.
+	Metadata odata.Metadata
+
+	// StrictDecode causes an error to be returned when a response contains properties that are not mapped by the
+	// corresponding model. This is intended to help detect models that are out of date with the API, and should not
+	// be enabled in production since Microsoft Graph may add new properties at any time. OData annotations such as
+	// @odata.context are always permitted, as are properties with dynamic names, such as the directory extension
+	// properties of a User and the schema extensions of a Group or User.
+	StrictDecode bool
+
+	// MaxResponseBytes is the maximum size of a response body, and of the combined bodies of the pages of a paged
+	// response, that will be read. Larger responses cause an error wrapping ErrResponseTooLarge to be returned, which
+	// protects long-running services from exhausting memory when a query unexpectedly matches a very large collection.
+	// When zero, the size of responses is not limited.
+	MaxResponseBytes int64
+
+	// DryRun prevents requests which could modify data, i.e. any request other than GET, from being sent. Instead, the
+	// method, URL and body of each such request is logged, and an error wrapping ErrDryRun is returned, whilst GET
+	// requests are sent as usual. This can be used to validate a series of changes against a production tenant
+	// without making them. Note that batch requests are also not sent, since these are made using POST.
+	DryRun bool
+
+	// DryRunLogger is used to log requests which are not sent when DryRun is enabled. When nil, the standard logger
+	// is used.
+	DryRunLogger *log.Logger
+
 	// HttpClient is the underlying http.Client, which by default uses a retryable client
 	HttpClient      *http.Client
 	RetryableClient *retryablehttp.Client
+
+	// retryPolicy is the policy set by WithHTTPRetryPolicy, which determines the responses that are retried
+	retryPolicy *auth.RetryPolicy
 }
 
 // NewClient returns a new Client configured with the specified API version and tenant ID.
 func NewClient(apiVersion ApiVersion, tenantId string) Client {
 	r := retryablehttp.NewClient()
+	r.Backoff = JitterBackoff(rand.New(rand.NewSource(time.Now().UnixNano())))
+	r.CheckRetry = checkRetry
 	r.Logger = nil
 
 	return Client{
@@ -103,13 +326,219 @@ func NewClient(apiVersion ApiVersion, tenantId string) Client {
 	}
 }
 
+// SetTLSConfig configures the TLS settings used when connecting to Microsoft Graph, e.g. to trust a private certificate
+// authority when testing against a mock service. See auth.DangerouslyInsecureTLSConfig() to disable verification.
+// An error is returned if the client has been configured with a custom transport, which should be configured directly.
+func (c *Client) SetTLSConfig(tlsConfig *tls.Config) error {
+	if c.RetryableClient == nil || c.RetryableClient.HTTPClient == nil {
+		return fmt.Errorf("client does not have a retryable HTTP client")
+	}
+	var transport *http.Transport
+	switch t := c.RetryableClient.HTTPClient.Transport.(type) {
+	case nil:
+		transport = http.DefaultTransport.(*http.Transport).Clone()
+	case *http.Transport:
+		transport = t.Clone()
+	default:
+		return fmt.Errorf("cannot configure TLS for custom transport %T", t)
+	}
+	transport.TLSClientConfig = tlsConfig
+	c.RetryableClient.HTTPClient.Transport = transport
+	return nil
+}
+
+// WithHTTPRetryPolicy configures the number of retries, the delay between them, and the responses and methods for which
+// requests are retried, replacing the default retry behavior of the client. Retries due to eventual consistency, which
+// can be disabled with DisableRetries, remain subject to the policy's MaxRetries and delays. Delays are calculated
+// without jitter, so that the schedule is predictable. The same policy can be used for token requests by setting
+// auth.Config.RetryPolicy.
+func (c *Client) WithHTTPRetryPolicy(policy auth.RetryPolicy) error {
+	if err := policy.Validate(); err != nil {
+		return fmt.Errorf("invalid retry policy: %v", err)
+	}
+	if c.RetryableClient == nil {
+		return fmt.Errorf("client does not have a retryable HTTP client")
+	}
+	c.RetryableClient.RetryMax = policy.MaxRetries
+	c.RetryableClient.RetryWaitMin = policy.BaseDelay
+	c.RetryableClient.RetryWaitMax = policy.MaxDelay
+	c.RetryableClient.Backoff = func(_, _ time.Duration, attemptNum int, resp *http.Response) time.Duration {
+		return policy.Backoff(attemptNum, resp)
+	}
+	c.retryPolicy = &policy
+	return nil
+}
+
+// decode unmarshals a response body into v. When StrictDecode is enabled, an error is returned if the response
+// contains any properties which are not mapped by v.
+func (c Client) decode(data []byte, v interface{}) error {
+	if err := json.Unmarshal(data, v); err != nil {
+		if _, ok := err.(*json.SyntaxError); ok {
+			if trimmed := bytes.TrimSpace(data); len(trimmed) > 0 && trimmed[0] == '<' {
+				return fmt.Errorf("response is not JSON, the request may have been intercepted by a proxy: %s", bodySnippet(trimmed))
+			}
+		}
+		return err
+	}
+	if !c.StrictDecode {
+		return nil
+	}
+
+	// Compare the properties in the response with the decoded value, with annotations removed since these are not
+	// modelled. The decoded value is walked instead of decoding again using json.Decoder.DisallowUnknownFields, which
+	// has no effect on models having a custom UnmarshalJSON method.
+	var raw interface{}
+	if err := unmarshalUseNumber(data, &raw); err != nil {
+		return err
+	}
+	return checkUnmappedProperties(stripODataAnnotations(raw), reflect.ValueOf(v))
+}
+
+// dynamicPropertiesModel is implemented by models which accept properties whose names are not known in advance, such
+// as directory extension properties, so that these are permitted when StrictDecode is enabled.
+type dynamicPropertiesModel interface {
+	isDynamicProperty(name string) bool
+}
+
+// checkUnmappedProperties returns an error when a decoded JSON value contains any properties which are not mapped by
+// the fields of the corresponding Go value, including those of nested objects.
+func checkUnmappedProperties(raw interface{}, v reflect.Value) error {
+	for v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			if v.Kind() == reflect.Interface {
+				return nil
+			}
+			v = reflect.New(v.Type().Elem())
+		}
+		v = v.Elem()
+	}
+
+	switch val := raw.(type) {
+	case map[string]interface{}:
+		switch v.Kind() {
+		case reflect.Map:
+			for k, item := range val {
+				var elem reflect.Value
+				if v.Type().Key().Kind() == reflect.String {
+					elem = v.MapIndex(reflect.ValueOf(k).Convert(v.Type().Key()))
+				}
+				if !elem.IsValid() {
+					elem = reflect.New(v.Type().Elem()).Elem()
+				}
+				if err := checkUnmappedProperties(item, elem); err != nil {
+					return err
+				}
+			}
+		case reflect.Struct:
+			// copy the value so that its methods can be called regardless of whether it is addressable
+			model := reflect.New(v.Type())
+			model.Elem().Set(v)
+			dynamic, _ := model.Interface().(dynamicPropertiesModel)
+
+			fields := jsonFields(v)
+			for k, item := range val {
+				field, ok := fields[strings.ToLower(k)]
+				if !ok {
+					if dynamic != nil && dynamic.isDynamicProperty(k) {
+						continue
+					}
+					return fmt.Errorf("json: unknown field %q", k)
+				}
+				if err := checkUnmappedProperties(item, field); err != nil {
+					return err
+				}
+			}
+		}
+	case []interface{}:
+		if v.Kind() == reflect.Slice || v.Kind() == reflect.Array {
+			for i, item := range val {
+				elem := reflect.New(v.Type().Elem()).Elem()
+				if i < v.Len() {
+					elem = v.Index(i)
+				}
+				if err := checkUnmappedProperties(item, elem); err != nil {
+					return err
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+// jsonFields returns the exported fields of a struct value keyed by their lower-cased JSON names, including the
+// fields of embedded structs, matching the case-insensitive behavior of json.Unmarshal
+func jsonFields(v reflect.Value) map[string]reflect.Value {
+	fields := make(map[string]reflect.Value)
+	var embedded []reflect.Value
+	for i := 0; i < v.NumField(); i++ {
+		f := v.Type().Field(i)
+		tag := f.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name := strings.Split(tag, ",")[0]
+		if f.Anonymous && name == "" {
+			ev := v.Field(i)
+			if ev.Kind() == reflect.Ptr {
+				if ev.IsNil() {
+					ev = reflect.New(ev.Type().Elem())
+				}
+				ev = ev.Elem()
+			}
+			if ev.Kind() == reflect.Struct {
+				embedded = append(embedded, ev)
+				continue
+			}
+		}
+		if f.PkgPath != "" {
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+		fields[strings.ToLower(name)] = v.Field(i)
+	}
+
+	// fields of the outer struct take precedence over those of embedded structs
+	for _, ev := range embedded {
+		for k, field := range jsonFields(ev) {
+			if _, ok := fields[k]; !ok {
+				fields[k] = field
+			}
+		}
+	}
+
+	return fields
+}
+
+// stripODataAnnotations recursively removes OData annotations, e.g. @odata.context, from a decoded JSON value.
+// Annotations which are mapped by models, namely @odata.id and @odata.type, are retained.
+func stripODataAnnotations(v interface{}) interface{} {
+	switch val := v.(type) {
+	case map[string]interface{}:
+		for k, item := range val {
+			if strings.Contains(k, "@") && k != "@odata.id" && k != "@odata.type" {
+				delete(val, k)
+				continue
+			}
+			val[k] = stripODataAnnotations(item)
+		}
+	case []interface{}:
+		for i, item := range val {
+			val[i] = stripODataAnnotations(item)
+		}
+	}
+	return v
+}
+
 // buildUri is used by the package to build a complete URI string for API requests.
 func (c Client) buildUri(uri Uri) (string, error) {
 	newUrl, err := url.Parse(string(c.Endpoint))
 	if err != nil {
 		return "", err
 	}
-	newUrl.Path = "/" + string(c.ApiVersion)
+	newUrl.Path = fmt.Sprintf("%s/%s", strings.TrimRight(newUrl.Path, "/"), c.ApiVersion)
 	if uri.HasTenantId {
 		newUrl.Path = fmt.Sprintf("%s/%s", newUrl.Path, c.TenantId)
 	}
@@ -120,24 +549,79 @@ func (c Client) buildUri(uri Uri) (string, error) {
 	return newUrl.String(), nil
 }
 
+// rebaseUri rewrites an absolute URI returned by the API, such as an @odata.nextLink, to use the scheme, host and path
+// prefix of the configured Endpoint when it refers to a different host. This ensures that all requests are sent to the
+// same service, e.g. a recording proxy, even when links refer to the canonical Microsoft Graph host.
+func (c Client) rebaseUri(uri string) (string, error) {
+	endpoint, err := url.Parse(string(c.Endpoint))
+	if err != nil {
+		return "", err
+	}
+	u, err := url.Parse(uri)
+	if err != nil {
+		return "", err
+	}
+	if u.Host == "" || (strings.EqualFold(u.Scheme, endpoint.Scheme) && strings.EqualFold(u.Host, endpoint.Host)) {
+		return uri, nil
+	}
+	u.Scheme = endpoint.Scheme
+	u.Host = endpoint.Host
+	u.Path = strings.TrimRight(endpoint.Path, "/") + u.Path
+	u.RawPath = ""
+	return u.String(), nil
+}
+
 // performRequest is used by the package to send an HTTP request to the API.
 func (c Client) performRequest(req *http.Request, input HttpRequestInput) (*http.Response, int, *odata.OData, error) {
 	var status int
 
+	if c.DryRun && req.Method != http.MethodGet && req.Method != http.MethodHead {
+		resp, status, err := c.dryRun(req)
+		return resp, status, nil, err
+	}
+
+	// Custom headers are applied first, so that headers set by the client take precedence
+	for k, v := range HeadersFromContext(req.Context()) {
+		for _, val := range v {
+			req.Header.Add(k, val)
+		}
+	}
+
 	if c.Authorizer != nil {
-		token, err := c.Authorizer.Token()
+		token, err := auth.TokenWithContext(req.Context(), c.Authorizer)
 		if err != nil {
 			return nil, status, nil, err
 		}
 		token.SetAuthHeader(req)
 	}
 
-	req.Header.Add("Accept", "application/json")
-	req.Header.Add("Content-Type", "application/json; charset=utf-8")
+	accept := "application/json"
+	if c.Metadata != "" {
+		accept = fmt.Sprintf("%s;odata.metadata=%s", accept, c.Metadata)
+	}
+	req.Header.Set("Accept", accept)
+	contentType := "application/json; charset=utf-8"
+	if i, ok := input.(PutHttpRequestInput); ok && i.ContentType != "" {
+		contentType = i.ContentType
+	}
+	req.Header.Set("Content-Type", contentType)
 	//req.Header.Add("ConsistencyLevel", "eventual")
 
 	if c.UserAgent != "" {
-		req.Header.Add("User-Agent", c.UserAgent)
+		req.Header.Set("User-Agent", c.UserAgent)
+	}
+
+	correlationId := CorrelationIdFromContext(req.Context())
+	if correlationId == "" {
+		correlationId = req.Header.Get("client-request-id")
+	}
+	if correlationId == "" {
+		if id, err := uuid.GenerateUUID(); err == nil {
+			correlationId = id
+		}
+	}
+	if correlationId != "" {
+		req.Header.Set("client-request-id", correlationId)
 	}
 
 	var resp *http.Response
@@ -152,7 +636,11 @@ func (c Client) performRequest(req *http.Request, input HttpRequestInput) (*http
 		}
 	}
 
-	c.RetryableClient.CheckRetry = func(ctx context.Context, resp *http.Response, err error) (bool, error) {
+	method := req.Method
+	var retry retryablehttp.CheckRetry = func(ctx context.Context, resp *http.Response, err error) (bool, error) {
+		if err := limitResponseBody(resp, c.MaxResponseBytes); err != nil {
+			return false, err
+		}
 		if resp != nil && !c.DisableRetries {
 			if resp.StatusCode == http.StatusFailedDependency {
 				return true, nil
@@ -168,8 +656,12 @@ func (c Client) performRequest(req *http.Request, input HttpRequestInput) (*http
 				return true, nil
 			}
 		}
+		if c.retryPolicy != nil {
+			return c.checkRetryPolicy(ctx, method, resp, err)
+		}
 		return retryablehttp.DefaultRetryPolicy(ctx, resp, err)
 	}
+	req = req.WithContext(context.WithValue(req.Context(), checkRetryContextKey{}, retry))
 
 	req.Body = io.NopCloser(bytes.NewBuffer(reqBody))
 
@@ -188,6 +680,30 @@ func (c Client) performRequest(req *http.Request, input HttpRequestInput) (*http
 		return nil, status, nil, err
 	}
 
+	// A token may be rejected before its expected expiry, e.g. due to clock skew, in which case the cached token is
+	// discarded and the request is retried once with a newly acquired token
+	if a, ok := c.Authorizer.(auth.RefreshableAuthorizer); ok && resp.StatusCode == http.StatusUnauthorized {
+		o, err := odata.FromResponse(resp)
+		if err == nil && o != nil && o.Error != nil && o.Error.Code != nil && *o.Error.Code == "InvalidAuthenticationToken" {
+			a.InvalidateToken()
+			token, err := auth.TokenWithContext(req.Context(), a)
+			if err != nil {
+				return nil, status, nil, err
+			}
+			resp.Body.Close()
+			token.SetAuthHeader(req)
+			req.Body = io.NopCloser(bytes.NewBuffer(reqBody))
+			resp, err = c.HttpClient.Do(req)
+			if err != nil {
+				return nil, status, nil, err
+			}
+		}
+	}
+
+	if err := limitResponseBody(resp, c.MaxResponseBytes); err != nil {
+		return nil, resp.StatusCode, nil, err
+	}
+
 	if c.ResponseMiddlewares != nil {
 		for _, m := range *c.ResponseMiddlewares {
 			r, err := m(req, resp)
@@ -198,6 +714,10 @@ func (c Client) performRequest(req *http.Request, input HttpRequestInput) (*http
 		}
 	}
 
+	if err := checkContentType(resp); err != nil {
+		return nil, resp.StatusCode, nil, err
+	}
+
 	o, err = odata.FromResponse(resp)
 	if err != nil {
 		return nil, status, o, err
@@ -234,6 +754,87 @@ func (c Client) performRequest(req *http.Request, input HttpRequestInput) (*http
 	return resp, status, o, nil
 }
 
+// checkRetryPolicy determines whether a request should be retried according to the policy set by WithHTTPRetryPolicy
+func (c Client) checkRetryPolicy(ctx context.Context, method string, resp *http.Response, err error) (bool, error) {
+	if ctx.Err() != nil {
+		return false, ctx.Err()
+	}
+	if !c.retryPolicy.MethodRetryable(method) {
+		return false, nil
+	}
+	if err != nil {
+		return retryablehttp.DefaultRetryPolicy(ctx, nil, err)
+	}
+	return c.retryPolicy.StatusRetryable(resp.StatusCode), nil
+}
+
+// requireDelegatedToken returns a DelegatedAuthRequiredError when the client is authorized with an app-only token, which
+// cannot be used with endpoints for the signed-in user such as /me. Tokens which cannot be acquired or parsed are not
+// checked here, so that any error is reported by the request itself.
+func (c Client) requireDelegatedToken(ctx context.Context, entity string) error {
+	if c.Authorizer == nil {
+		return nil
+	}
+	token, err := auth.TokenWithContext(ctx, c.Authorizer)
+	if err != nil {
+		return nil
+	}
+	claims, err := auth.ParseClaims(token)
+	if err != nil {
+		return nil
+	}
+	if claims.IdType == "app" || claims.Scopes == "" {
+		return errors.DelegatedAuthRequiredError{Entity: entity}
+	}
+	return nil
+}
+
+// delegatedAuthRequired returns whether a request was rejected because it requires a delegated token
+func delegatedAuthRequired(status int, o *odata.OData) bool {
+	return status == http.StatusBadRequest && o != nil && o.Error != nil && o.Error.Match(odata.ErrorDelegatedAuthenticationRequired)
+}
+
+// checkContentType returns a descriptive error when a response cannot be decoded as JSON. Microsoft Graph never returns
+// HTML, so an HTML response usually indicates that the request was intercepted, e.g. by a proxy serving a login page,
+// in which case the start of the body is included in the error. JSON responses must also be encoded as UTF-8.
+func checkContentType(resp *http.Response) error {
+	if resp == nil {
+		return nil
+	}
+	contentType := resp.Header.Get("Content-Type")
+	if contentType == "" {
+		return nil
+	}
+	mediaType, params, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return nil
+	}
+
+	switch {
+	case mediaType == "text/html" || mediaType == "application/xhtml+xml":
+		body, err := io.ReadAll(io.LimitReader(resp.Body, 512))
+		resp.Body.Close()
+		if err != nil {
+			return fmt.Errorf("received HTML response with status %d instead of JSON, the request may have been intercepted by a proxy", resp.StatusCode)
+		}
+		return fmt.Errorf("received HTML response with status %d instead of JSON, the request may have been intercepted by a proxy: %s", resp.StatusCode, bodySnippet(body))
+	case mediaType == "application/json" || strings.HasSuffix(mediaType, "+json"):
+		if charset := strings.ToLower(params["charset"]); charset != "" && charset != "utf-8" && charset != "utf8" && charset != "us-ascii" {
+			return fmt.Errorf("received JSON response with unsupported charset %q, only UTF-8 is supported", params["charset"])
+		}
+	}
+	return nil
+}
+
+// bodySnippet returns the start of a response body with whitespace collapsed, for inclusion in error messages
+func bodySnippet(body []byte) string {
+	snippet := strings.Join(strings.Fields(string(body)), " ")
+	if len(snippet) > 200 {
+		snippet = snippet[:200] + "..."
+	}
+	return snippet
+}
+
 // containsStatusCode determines whether the returned status code is in the []int of expected status codes.
 func containsStatusCode(expected []int, actual int) bool {
 	for _, v := range expected {
@@ -289,11 +890,13 @@ func (c Client) Delete(ctx context.Context, input DeleteHttpRequestInput) (*http
 // GetHttpRequestInput configures a GET request.
 type GetHttpRequestInput struct {
 	ConsistencyFailureFunc ConsistencyFailureFunc
+	ConsistencyLevel       odata.ConsistencyLevel
 	DisablePaging          bool
 	ValidStatusCodes       []int
 	ValidStatusFunc        ValidStatusFunc
 	Uri                    Uri
 	rawUri                 string
+	pagedBytes             int64
 }
 
 // GetConsistencyFailureFunc returns a function used to evaluate whether a failed request is due to eventual consistency and should be retried.
@@ -325,11 +928,26 @@ func (c Client) Get(ctx context.Context, input GetHttpRequestInput) (*http.Respo
 		}
 	}
 
+	// Advanced queries are only supported with eventual consistency, which may also be requested using a custom header
+	if input.Uri.Params != nil {
+		consistencyLevel := input.ConsistencyLevel
+		if consistencyLevel == "" {
+			consistencyLevel = odata.ConsistencyLevel(HeadersFromContext(ctx).Get("ConsistencyLevel"))
+		}
+		query := odata.Query{ConsistencyLevel: consistencyLevel, Filter: input.Uri.Params.Get("$filter")}
+		if err := query.Validate(); err != nil {
+			return nil, status, nil, fmt.Errorf("invalid query: %v", err)
+		}
+	}
+
 	// Build a new request
 	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, http.NoBody)
 	if err != nil {
 		return nil, status, nil, err
 	}
+	if input.ConsistencyLevel != "" {
+		req.Header.Set("ConsistencyLevel", string(input.ConsistencyLevel))
+	}
 
 	// Perform the request
 	resp, status, o, err := c.performRequest(req, input)
@@ -347,6 +965,12 @@ func (c Client) Get(ctx context.Context, input GetHttpRequestInput) (*http.Respo
 		}
 		resp.Body.Close()
 
+		// Pages are combined into a single response, so their combined size must not exceed the limit
+		pagedBytes := input.pagedBytes + int64(len(respBody))
+		if c.MaxResponseBytes > 0 && pagedBytes > c.MaxResponseBytes {
+			return nil, status, o, fmt.Errorf("%w: paged response exceeds the limit of %d bytes", ErrResponseTooLarge, c.MaxResponseBytes)
+		}
+
 		// Unmarshall firstOdata
 		var firstOdata odata.OData
 		if err := json.Unmarshal(respBody, &firstOdata); err != nil {
@@ -355,6 +979,11 @@ func (c Client) Get(ctx context.Context, input GetHttpRequestInput) (*http.Respo
 
 		firstValue, ok := firstOdata.Value.([]interface{})
 		if input.DisablePaging || firstOdata.NextLink == nil || firstValue == nil || !ok {
+			if info, ok := ctx.Value(pageInfoContextKey).(*PageInfo); ok {
+				info.NextLink = firstOdata.NextLink
+				info.SkipToken = firstOdata.SkipToken()
+			}
+
 			// No more pages, reassign response body and return
 			resp.Body = io.NopCloser(bytes.NewBuffer(respBody))
 			return resp, status, o, nil
@@ -362,10 +991,14 @@ func (c Client) Get(ctx context.Context, input GetHttpRequestInput) (*http.Respo
 
 		// Get the next page, recursively
 		nextInput := input
-		nextInput.rawUri = *firstOdata.NextLink
+		nextInput.pagedBytes = pagedBytes
+		nextInput.rawUri, err = c.rebaseUri(*firstOdata.NextLink)
+		if err != nil {
+			return nil, status, o, fmt.Errorf("invalid next link %q: %v", *firstOdata.NextLink, err)
+		}
 		nextResp, status, o, err := c.Get(ctx, nextInput)
 		if err != nil {
-			return resp, status, o, err
+			return nil, status, o, newPagedResult(respBody, *firstOdata.NextLink, err)
 		}
 
 		// Read the next page response body and close it
@@ -407,6 +1040,10 @@ type PatchHttpRequestInput struct {
 	ValidStatusCodes       []int
 	ValidStatusFunc        ValidStatusFunc
 	Uri                    Uri
+
+	// NullProperties are the names of top-level properties to be sent as an explicit JSON null, which clears them.
+	// The Body must be a JSON object which does not also contain these properties.
+	NullProperties []string
 }
 
 // GetConsistencyFailureFunc returns a function used to evaluate whether a failed request is due to eventual consistency and should be retried.
@@ -431,7 +1068,13 @@ func (c Client) Patch(ctx context.Context, input PatchHttpRequestInput) (*http.R
 	if err != nil {
 		return nil, status, nil, fmt.Errorf("unable to make request: %v", err)
 	}
-	req, err := http.NewRequestWithContext(ctx, http.MethodPatch, url, bytes.NewBuffer(input.Body))
+	body := input.Body
+	if len(input.NullProperties) > 0 {
+		if body, err = withNullProperties(body, input.NullProperties); err != nil {
+			return nil, status, nil, fmt.Errorf("unable to make request: %v", err)
+		}
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPatch, url, bytes.NewBuffer(body))
 	if err != nil {
 		return nil, status, nil, err
 	}
@@ -442,6 +1085,50 @@ func (c Client) Patch(ctx context.Context, input PatchHttpRequestInput) (*http.R
 	return resp, status, o, nil
 }
 
+// withNullProperties adds the specified properties to a JSON object with null values. Since models omit empty fields
+// when marshaled, this is necessary to clear properties with a PATCH request.
+func withNullProperties(body []byte, properties []string) ([]byte, error) {
+	obj := make(map[string]json.RawMessage)
+	if len(bytes.TrimSpace(body)) > 0 {
+		if err := json.Unmarshal(body, &obj); err != nil {
+			return nil, fmt.Errorf("request body is not a JSON object: %v", err)
+		}
+	}
+	for _, p := range properties {
+		if v, ok := obj[p]; ok && string(v) != "null" {
+			return nil, fmt.Errorf("property %q cannot be both updated and cleared", p)
+		}
+		obj[p] = json.RawMessage("null")
+	}
+	return json.Marshal(obj)
+}
+
+// patchAndReturn performs a PATCH request with the `Prefer: return=representation` header, asking for the updated
+// object to be returned, and decodes it into out. Returns false when the API disregards the preference and responds
+// with no content, in which case out is unchanged.
+func (c Client) patchAndReturn(ctx context.Context, input PatchHttpRequestInput, out interface{}) (bool, int, error) {
+	input.ValidStatusCodes = []int{http.StatusOK, http.StatusNoContent}
+	resp, status, _, err := c.Patch(WithHeaders(ctx, http.Header{"Prefer": []string{"return=representation"}}), input)
+	if err != nil {
+		return false, status, err
+	}
+
+	defer resp.Body.Close()
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return false, status, fmt.Errorf("io.ReadAll(): %v", err)
+	}
+	if status == http.StatusNoContent || len(bytes.TrimSpace(respBody)) == 0 {
+		return false, status, nil
+	}
+
+	if err := c.decode(respBody, out); err != nil {
+		return false, status, fmt.Errorf("json.Unmarshal(): %v", err)
+	}
+
+	return true, status, nil
+}
+
 // PostHttpRequestInput configures a POST request.
 type PostHttpRequestInput struct {
 	Body                   []byte
@@ -491,6 +1178,10 @@ type PutHttpRequestInput struct {
 	ValidStatusCodes       []int
 	ValidStatusFunc        ValidStatusFunc
 	Uri                    Uri
+
+	// ContentType is the media type of the Body, and defaults to JSON. It is set when uploading binary content such
+	// as images.
+	ContentType string
 }
 
 // GetConsistencyFailureFunc returns a function used to evaluate whether a failed request is due to eventual consistency and should be retried.
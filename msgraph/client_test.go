@@ -0,0 +1,898 @@
+package msgraph_test
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"golang.org/x/oauth2"
+
+	"github.com/manicminer/hamilton/auth"
+	"github.com/manicminer/hamilton/environments"
+	"github.com/manicminer/hamilton/internal/utils"
+	"github.com/manicminer/hamilton/msgraph"
+	"github.com/manicminer/hamilton/odata"
+)
+
+func TestClient_StrictDecode(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"@odata.context":"https://graph.microsoft.com/v1.0/$metadata#domains/$entity","id":"example.com","isDefault":true,"unmappedProperty":"value"}`))
+	}))
+	defer server.Close()
+
+	c := msgraph.NewDomainsClient("00000000-0000-0000-0000-000000000000")
+	c.BaseClient.Endpoint = environments.ApiEndpoint(server.URL)
+
+	domain, _, err := c.Get(context.Background(), "example.com", odata.Query{})
+	if err != nil {
+		t.Fatalf("DomainsClient.Get(): unexpected error with StrictDecode disabled: %v", err)
+	}
+	if domain == nil || domain.ID == nil || *domain.ID != "example.com" {
+		t.Fatal("DomainsClient.Get(): domain was not decoded")
+	}
+
+	c.BaseClient.StrictDecode = true
+	if _, _, err := c.Get(context.Background(), "example.com", odata.Query{}); err == nil {
+		t.Fatal("DomainsClient.Get(): expected an error for unmapped property with StrictDecode enabled")
+	}
+}
+
+func TestClient_StrictDecodeCustomUnmarshal(t *testing.T) {
+	var body string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(body))
+	}))
+	defer server.Close()
+
+	c := msgraph.NewUsersClient("00000000-0000-0000-0000-000000000000")
+	c.BaseClient.Endpoint = environments.ApiEndpoint(server.URL)
+	c.BaseClient.StrictDecode = true
+
+	body = `{"@odata.context":"https://graph.microsoft.com/beta/$metadata#users/$entity","id":"user1","displayName":"User","passwordProfile":{"forceChangePasswordNextSignIn":true},"extension_0000000000000000000000000000000a_hrCode":"HR-A"}`
+	if _, _, err := c.Get(context.Background(), "user1", odata.Query{}); err != nil {
+		t.Fatalf("UsersClient.Get(): unexpected error with StrictDecode enabled: %v", err)
+	}
+
+	for _, body = range []string{
+		`{"id":"user1","displayName":"User","unmappedProperty":"value"}`,
+		`{"id":"user1","passwordProfile":{"unmappedProperty":"value"}}`,
+	} {
+		if _, _, err := c.Get(context.Background(), "user1", odata.Query{}); err == nil || !strings.Contains(err.Error(), "unmappedProperty") {
+			t.Fatalf("UsersClient.Get(): expected an error for unmapped property in %s with StrictDecode enabled, got %v", body, err)
+		}
+	}
+}
+
+func TestClient_StrictDecodeAnnotations(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"@odata.context":"https://graph.microsoft.com/v1.0/$metadata#domains","value":[{"id":"example.com","isDefault":true,"supportedServices@odata.type":"#Collection(String)","supportedServices":["Email"]}]}`))
+	}))
+	defer server.Close()
+
+	c := msgraph.NewDomainsClient("00000000-0000-0000-0000-000000000000")
+	c.BaseClient.Endpoint = environments.ApiEndpoint(server.URL)
+	c.BaseClient.StrictDecode = true
+
+	domains, _, err := c.List(context.Background(), odata.Query{})
+	if err != nil {
+		t.Fatalf("DomainsClient.List(): unexpected error for OData annotations with StrictDecode enabled: %v", err)
+	}
+	if domains == nil || len(*domains) != 1 {
+		t.Fatal("DomainsClient.List(): domains were not decoded")
+	}
+}
+
+func TestClient_CorrelationId(t *testing.T) {
+	var received []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received = append(received, r.Header.Get("client-request-id"))
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"id":"example.com"}`))
+	}))
+	defer server.Close()
+
+	c := msgraph.NewDomainsClient("00000000-0000-0000-0000-000000000000")
+	c.BaseClient.Endpoint = environments.ApiEndpoint(server.URL)
+
+	ctx := msgraph.WithCorrelationId(context.Background(), "11111111-1111-1111-1111-111111111111")
+	for i := 0; i < 2; i++ {
+		if _, _, err := c.Get(ctx, "example.com", odata.Query{}); err != nil {
+			t.Fatalf("DomainsClient.Get(): %v", err)
+		}
+	}
+	for i := 0; i < 2; i++ {
+		if _, _, err := c.Get(context.Background(), "example.com", odata.Query{}); err != nil {
+			t.Fatalf("DomainsClient.Get(): %v", err)
+		}
+	}
+
+	if received[0] != "11111111-1111-1111-1111-111111111111" || received[1] != received[0] {
+		t.Fatalf("DomainsClient.Get(): expected correlation ID from context to be sent, got %v", received[:2])
+	}
+	if received[2] == "" || received[3] == "" || received[2] == received[3] || received[2] == received[0] {
+		t.Fatalf("DomainsClient.Get(): expected a unique generated correlation ID for each request, got %v", received[2:])
+	}
+}
+
+func TestClient_Metadata(t *testing.T) {
+	var accept string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		accept = r.Header.Get("Accept")
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"id":"example.com"}`))
+	}))
+	defer server.Close()
+
+	c := msgraph.NewDomainsClient("00000000-0000-0000-0000-000000000000")
+	c.BaseClient.Endpoint = environments.ApiEndpoint(server.URL)
+
+	for metadata, expected := range map[odata.Metadata]string{
+		"":                    "application/json",
+		odata.MetadataFull:    "application/json;odata.metadata=full",
+		odata.MetadataMinimal: "application/json;odata.metadata=minimal",
+		odata.MetadataNone:    "application/json;odata.metadata=none",
+	} {
+		c.BaseClient.Metadata = metadata
+		if _, _, err := c.Get(context.Background(), "example.com", odata.Query{}); err != nil {
+			t.Fatalf("DomainsClient.Get(): %v", err)
+		}
+		if accept != expected {
+			t.Fatalf("DomainsClient.Get(): expected Accept header %q, got %q", expected, accept)
+		}
+	}
+}
+
+type testTokenSource struct {
+	calls int
+}
+
+func (s *testTokenSource) Token() (*oauth2.Token, error) {
+	s.calls++
+	return &oauth2.Token{
+		AccessToken: fmt.Sprintf("token-%d", s.calls),
+		TokenType:   "Bearer",
+		Expiry:      time.Now().Add(time.Hour),
+	}, nil
+}
+
+func TestClient_RefreshTokenOnUnauthorized(t *testing.T) {
+	for _, tc := range []struct {
+		name           string
+		status         func(authorization string) int
+		expectError    bool
+		expectRequests int
+		expectTokens   int
+	}{
+		{
+			name: "rejected once",
+			status: func(authorization string) int {
+				if authorization == "Bearer token-1" {
+					return http.StatusUnauthorized
+				}
+				return http.StatusOK
+			},
+			expectRequests: 2,
+			expectTokens:   2,
+		},
+		{
+			name:           "always rejected",
+			status:         func(string) int { return http.StatusUnauthorized },
+			expectError:    true,
+			expectRequests: 2,
+			expectTokens:   2,
+		},
+		{
+			name:           "forbidden",
+			status:         func(string) int { return http.StatusForbidden },
+			expectError:    true,
+			expectRequests: 1,
+			expectTokens:   1,
+		},
+	} {
+		requests := 0
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			requests++
+			w.Header().Set("Content-Type", "application/json")
+			switch status := tc.status(r.Header.Get("Authorization")); status {
+			case http.StatusUnauthorized:
+				w.WriteHeader(status)
+				w.Write([]byte(`{"error":{"code":"InvalidAuthenticationToken","message":"Access token has expired or is not yet valid."}}`))
+			case http.StatusForbidden:
+				w.WriteHeader(status)
+				w.Write([]byte(`{"error":{"code":"Authorization_RequestDenied","message":"Insufficient privileges to complete the operation."}}`))
+			default:
+				w.Write([]byte(`{"id":"example.com"}`))
+			}
+		}))
+
+		src := &testTokenSource{}
+		c := msgraph.NewDomainsClient("00000000-0000-0000-0000-000000000000")
+		c.BaseClient.Endpoint = environments.ApiEndpoint(server.URL)
+		c.BaseClient.Authorizer = auth.NewCachedAuthorizer(src)
+		c.BaseClient.DisableRetries = true
+
+		_, _, err := c.Get(context.Background(), "example.com", odata.Query{})
+		server.Close()
+		if tc.expectError && err == nil {
+			t.Fatalf("%s: DomainsClient.Get(): expected an error", tc.name)
+		}
+		if !tc.expectError && err != nil {
+			t.Fatalf("%s: DomainsClient.Get(): %v", tc.name, err)
+		}
+		if requests != tc.expectRequests {
+			t.Fatalf("%s: DomainsClient.Get(): expected %d requests, got %d", tc.name, tc.expectRequests, requests)
+		}
+		if src.calls != tc.expectTokens {
+			t.Fatalf("%s: DomainsClient.Get(): expected %d tokens to be acquired, got %d", tc.name, tc.expectTokens, src.calls)
+		}
+	}
+}
+
+func TestClient_Headers(t *testing.T) {
+	var received http.Header
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received = r.Header.Clone()
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"id":"example.com"}`))
+	}))
+	defer server.Close()
+
+	c := msgraph.NewDomainsClient("00000000-0000-0000-0000-000000000000")
+	c.BaseClient.Endpoint = environments.ApiEndpoint(server.URL)
+
+	ctx := msgraph.WithHeaders(context.Background(), http.Header{
+		"Prefer": []string{"outlook.timezone=\"UTC\""},
+		"Accept": []string{"text/plain"},
+	})
+	ctx = msgraph.WithHeaders(ctx, http.Header{
+		"Prefer":           []string{"return=minimal"},
+		"Consistencylevel": []string{"eventual"},
+	})
+	if _, _, err := c.Get(ctx, "example.com", odata.Query{}); err != nil {
+		t.Fatalf("DomainsClient.Get(): %v", err)
+	}
+
+	if expected := []string{"outlook.timezone=\"UTC\"", "return=minimal"}; !reflect.DeepEqual(received.Values("Prefer"), expected) {
+		t.Fatalf("DomainsClient.Get(): expected Prefer headers %v, got %v", expected, received.Values("Prefer"))
+	}
+	if v := received.Get("ConsistencyLevel"); v != "eventual" {
+		t.Fatalf("DomainsClient.Get(): expected ConsistencyLevel header %q, got %q", "eventual", v)
+	}
+	if expected := []string{"application/json"}; !reflect.DeepEqual(received.Values("Accept"), expected) {
+		t.Fatalf("DomainsClient.Get(): expected Accept header set by client to take precedence, got %v", received.Values("Accept"))
+	}
+}
+
+func TestClient_AnchorMailbox(t *testing.T) {
+	var received http.Header
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received = r.Header.Clone()
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"id":"00000000-0000-0000-0000-000000000000"}`))
+	}))
+	defer server.Close()
+
+	c := msgraph.NewUsersClient("00000000-0000-0000-0000-000000000000")
+	c.BaseClient.Endpoint = environments.ApiEndpoint(server.URL)
+
+	ctx := msgraph.WithHeaders(context.Background(), http.Header{"Prefer": []string{"outlook.timezone=\"UTC\""}})
+	ctx = msgraph.WithAnchorMailbox(ctx, "first@example.com")
+	ctx = msgraph.WithAnchorMailbox(ctx, "second@example.com")
+	if _, _, err := c.Get(ctx, "second@example.com", odata.Query{}); err != nil {
+		t.Fatalf("UsersClient.Get(): %v", err)
+	}
+
+	if expected := []string{"second@example.com"}; !reflect.DeepEqual(received.Values("X-AnchorMailbox"), expected) {
+		t.Fatalf("UsersClient.Get(): expected X-AnchorMailbox headers %v, got %v", expected, received.Values("X-AnchorMailbox"))
+	}
+	if v := received.Get("Prefer"); v != "outlook.timezone=\"UTC\"" {
+		t.Fatalf("UsersClient.Get(): expected existing Prefer header to be retained, got %q", v)
+	}
+}
+
+func TestClient_ConsistencyLevel(t *testing.T) {
+	var requests int
+	var consistencyLevel, count string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		consistencyLevel = r.Header.Get("ConsistencyLevel")
+		count = r.URL.Query().Get("$count")
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"value":[{"id":"11111111-1111-1111-1111-111111111111"}]}`))
+	}))
+	defer server.Close()
+
+	c := msgraph.NewGroupsClient("00000000-0000-0000-0000-000000000000")
+	c.BaseClient.Endpoint = environments.ApiEndpoint(server.URL)
+
+	filter := odata.CountFilter("members", "eq", 0)
+	if _, _, err := c.List(context.Background(), odata.Query{Filter: filter}); err == nil {
+		t.Fatal("GroupsClient.List(): expected an error for a count filter without eventual consistency")
+	}
+	if requests != 0 {
+		t.Fatalf("GroupsClient.List(): expected invalid query not to be sent, got %d requests", requests)
+	}
+
+	groups, _, err := c.List(context.Background(), odata.Query{ConsistencyLevel: odata.ConsistencyLevelEventual, Filter: filter})
+	if err != nil {
+		t.Fatalf("GroupsClient.List(): %v", err)
+	}
+	if groups == nil || len(*groups) != 1 {
+		t.Fatal("GroupsClient.List(): expected 1 group")
+	}
+	if consistencyLevel != "eventual" {
+		t.Fatalf("GroupsClient.List(): expected ConsistencyLevel header %q, got %q", "eventual", consistencyLevel)
+	}
+	if count != "true" {
+		t.Fatalf("GroupsClient.List(): expected $count=true, got %q", count)
+	}
+
+	// Eventual consistency may instead be requested with a custom header
+	consistencyLevel = ""
+	ctx := msgraph.WithHeaders(context.Background(), http.Header{"ConsistencyLevel": []string{"eventual"}})
+	if _, _, err := c.List(ctx, odata.Query{Filter: filter}); err != nil {
+		t.Fatalf("GroupsClient.List(): unexpected error for a count filter with a ConsistencyLevel header: %v", err)
+	}
+	if consistencyLevel != "eventual" {
+		t.Fatalf("GroupsClient.List(): expected ConsistencyLevel header %q, got %q", "eventual", consistencyLevel)
+	}
+}
+
+func TestClient_AdvancedQuery(t *testing.T) {
+	var consistencyLevel, count string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		consistencyLevel = r.Header.Get("ConsistencyLevel")
+		count = r.URL.Query().Get("$count")
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"value":[]}`))
+	}))
+	defer server.Close()
+
+	c := msgraph.NewUsersClient("00000000-0000-0000-0000-000000000000")
+	c.BaseClient.Endpoint = environments.ApiEndpoint(server.URL)
+
+	for filter, expected := range map[string]string{
+		"userType eq 'Guest'":                                            "",
+		"userType ne 'Member'":                                           "eventual",
+		"endsWith(mail,'@example.com')":                                  "eventual",
+		"displayName eq 'not a problem'":                                 "",
+		"extension_b7d8e648520f41d3b9c0fdeb91768a0a_jobGroup eq 'Sales'": "eventual",
+	} {
+		if _, _, err := c.List(context.Background(), odata.Query{Filter: filter}); err != nil {
+			t.Fatalf("UsersClient.List(): %v", err)
+		}
+		if consistencyLevel != expected {
+			t.Fatalf("UsersClient.List(): expected ConsistencyLevel header %q for filter %q, got %q", expected, filter, consistencyLevel)
+		}
+		if expectedCount := map[bool]string{true: "true", false: ""}[expected != ""]; count != expectedCount {
+			t.Fatalf("UsersClient.List(): expected $count %q for filter %q, got %q", expectedCount, filter, count)
+		}
+	}
+}
+
+func TestClient_AdvancedQueryCount(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("$count") != "true" {
+			t.Errorf("expected $count=true, got %q", r.URL.Query().Get("$count"))
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"@odata.context":"https://graph.microsoft.com/beta/$metadata#users","@odata.count":3,"value":[{"id":"user1","userType":"Guest"},{"id":"user2","userType":"Guest"},{"id":"user3","userType":"Guest"}]}`))
+	}))
+	defer server.Close()
+
+	c := msgraph.NewUsersClient("00000000-0000-0000-0000-000000000000")
+	c.BaseClient.Endpoint = environments.ApiEndpoint(server.URL)
+
+	users, _, err := c.List(context.Background(), odata.Query{Filter: "userType ne 'Member'"})
+	if err != nil {
+		t.Fatalf("UsersClient.List(): %v", err)
+	}
+	if users == nil || len(*users) != 3 {
+		t.Fatalf("UsersClient.List(): expected 3 users, got %v", users)
+	}
+}
+
+func TestClient_Ping(t *testing.T) {
+	var path, query string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path, query = r.URL.Path, r.URL.RawQuery
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"value":[{"id":"00000000-0000-0000-0000-000000000000"}]}`))
+	}))
+	defer server.Close()
+
+	c := msgraph.NewClient(msgraph.Version10, "00000000-0000-0000-0000-000000000000")
+	c.Endpoint = environments.ApiEndpoint(server.URL)
+
+	result, status, err := c.Ping(context.Background(), nil)
+	if err != nil {
+		t.Fatalf("Client.Ping(): %v", err)
+	}
+	if status != http.StatusOK || result.Status != http.StatusOK {
+		t.Fatalf("Client.Ping(): expected status %d, got %d", http.StatusOK, status)
+	}
+	if path != "/v1.0/00000000-0000-0000-0000-000000000000/organization" || query != "%24select=id" {
+		t.Fatalf("Client.Ping(): unexpected probe request %s?%s", path, query)
+	}
+	if result.TenantId != "00000000-0000-0000-0000-000000000000" {
+		t.Fatalf("Client.Ping(): unexpected tenant ID %q", result.TenantId)
+	}
+	if result.Latency <= 0 {
+		t.Fatalf("Client.Ping(): expected a positive latency, got %s", result.Latency)
+	}
+
+	if _, _, err := c.Ping(context.Background(), &msgraph.Uri{Entity: "/me"}); err != nil {
+		t.Fatalf("Client.Ping(): %v", err)
+	}
+	if path != "/v1.0/me" {
+		t.Fatalf("Client.Ping(): expected custom probe request to /v1.0/me, got %s", path)
+	}
+}
+
+func TestClient_Count(t *testing.T) {
+	var path, consistencyLevel string
+	var query url.Values
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path, query, consistencyLevel = r.URL.Path, r.URL.Query(), r.Header.Get("ConsistencyLevel")
+		if strings.HasSuffix(path, "/$count") {
+			w.Header().Set("Content-Type", "text/plain")
+			w.Write([]byte("\ufeff42"))
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"@odata.count":17,"value":[]}`))
+	}))
+	defer server.Close()
+
+	c := msgraph.NewClient(msgraph.Version10, "00000000-0000-0000-0000-000000000000")
+	c.Endpoint = environments.ApiEndpoint(server.URL)
+
+	count, _, err := c.Count(context.Background(), "/identityProtection/riskyUsers", odata.Query{Filter: "riskLevel eq 'high'", Top: 10})
+	if err != nil {
+		t.Fatalf("Client.Count(): %v", err)
+	}
+	if count != 17 {
+		t.Fatalf("Client.Count(): expected 17, got %d", count)
+	}
+	if path != "/v1.0/00000000-0000-0000-0000-000000000000/identityProtection/riskyUsers" || query.Get("$top") != "0" || query.Get("$count") != "true" || query.Get("$filter") != "riskLevel eq 'high'" {
+		t.Fatalf("Client.Count(): unexpected request %s?%s", path, query.Encode())
+	}
+
+	count, _, err = c.CountSegment(context.Background(), "/users", odata.Query{Filter: "accountEnabled eq false", Top: 10})
+	if err != nil {
+		t.Fatalf("Client.CountSegment(): %v", err)
+	}
+	if count != 42 {
+		t.Fatalf("Client.CountSegment(): expected 42, got %d", count)
+	}
+	if path != "/v1.0/00000000-0000-0000-0000-000000000000/users/$count" || query.Encode() != "%24filter=accountEnabled+eq+false" {
+		t.Fatalf("Client.CountSegment(): unexpected request %s?%s", path, query.Encode())
+	}
+	if consistencyLevel != "eventual" {
+		t.Fatalf("Client.CountSegment(): expected eventual consistency, got %q", consistencyLevel)
+	}
+}
+
+func TestClient_GetInto(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/beta/00000000-0000-0000-0000-000000000000/identityGovernance/lifecycleWorkflows/workflows/1":
+			fmt.Fprint(w, `{"@odata.context":"https://graph.microsoft.com/beta/$metadata#workflows/$entity","id":"1","displayName":"Onboarding","isEnabled":true}`)
+		case "/beta/00000000-0000-0000-0000-000000000000/identityGovernance/lifecycleWorkflows/workflows/1/displayName":
+			fmt.Fprint(w, `{"@odata.context":"https://graph.microsoft.com/beta/$metadata#workflows('1')/displayName","value":"Onboarding"}`)
+		case "/beta/00000000-0000-0000-0000-000000000000/identityGovernance/lifecycleWorkflows/workflows":
+			if r.URL.Query().Get("$skiptoken") == "" {
+				fmt.Fprint(w, `{"@odata.nextLink":"https://graph.microsoft.com/beta/00000000-0000-0000-0000-000000000000/identityGovernance/lifecycleWorkflows/workflows?$skiptoken=page2","value":[{"id":"1","displayName":"Onboarding","isEnabled":true}]}`)
+				return
+			}
+			fmt.Fprint(w, `{"value":[{"id":"2","displayName":"Offboarding","isEnabled":false}]}`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprint(w, `{"error":{"code":"NotFound","message":"not found"}}`)
+		}
+	}))
+	defer server.Close()
+
+	c := msgraph.NewClient(msgraph.VersionBeta, "00000000-0000-0000-0000-000000000000")
+	c.Endpoint = environments.ApiEndpoint(server.URL)
+
+	type workflow struct {
+		ID          string `json:"id"`
+		DisplayName string `json:"displayName"`
+		IsEnabled   bool   `json:"isEnabled"`
+	}
+
+	var w workflow
+	if _, err := c.GetInto(context.Background(), "/identityGovernance/lifecycleWorkflows/workflows/1", odata.Query{}, &w); err != nil {
+		t.Fatalf("Client.GetInto(): %v", err)
+	}
+	if w != (workflow{ID: "1", DisplayName: "Onboarding", IsEnabled: true}) {
+		t.Fatalf("Client.GetInto(): unexpected result %+v", w)
+	}
+
+	var displayName string
+	if _, err := c.GetInto(context.Background(), "/identityGovernance/lifecycleWorkflows/workflows/1/displayName", odata.Query{}, &displayName); err != nil {
+		t.Fatalf("Client.GetInto(): %v", err)
+	}
+	if displayName != "Onboarding" {
+		t.Fatalf("Client.GetInto(): expected property value %q, got %q", "Onboarding", displayName)
+	}
+
+	if _, err := c.GetInto(context.Background(), "/identityGovernance/lifecycleWorkflows/workflows/1", odata.Query{}, w); err == nil {
+		t.Fatal("Client.GetInto(): expected error for non-pointer out")
+	}
+
+	var workflows []workflow
+	if _, err := c.GetCollectionInto(context.Background(), "/identityGovernance/lifecycleWorkflows/workflows", odata.Query{}, &workflows); err != nil {
+		t.Fatalf("Client.GetCollectionInto(): %v", err)
+	}
+	if len(workflows) != 2 || workflows[1] != (workflow{ID: "2", DisplayName: "Offboarding"}) {
+		t.Fatalf("Client.GetCollectionInto(): unexpected result %+v", workflows)
+	}
+
+	if _, err := c.GetCollectionInto(context.Background(), "/identityGovernance/lifecycleWorkflows/workflows", odata.Query{}, &w); err == nil {
+		t.Fatal("Client.GetCollectionInto(): expected error for non-slice out")
+	}
+}
+
+func TestClient_PatchNullProperties(t *testing.T) {
+	var body string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		b, _ := io.ReadAll(r.Body)
+		body = string(b)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	c := msgraph.NewUsersClient("00000000-0000-0000-0000-000000000000")
+	c.BaseClient.Endpoint = environments.ApiEndpoint(server.URL)
+
+	user := msgraph.User{
+		DirectoryObject: msgraph.DirectoryObject{ID: utils.StringPtr("user1")},
+		DisplayName:     utils.StringPtr("Test User"),
+	}
+	if _, err := c.UpdateAndClear(context.Background(), user, []string{"department", "jobTitle"}); err != nil {
+		t.Fatalf("UsersClient.UpdateAndClear(): %v", err)
+	}
+	if expected := `{"department":null,"displayName":"Test User","id":"user1","jobTitle":null}`; body != expected {
+		t.Fatalf("UsersClient.UpdateAndClear(): unexpected request body\nexpected: %s\nreceived: %s", expected, body)
+	}
+
+	if _, err := c.UpdateAndClear(context.Background(), user, []string{"displayName"}); err == nil {
+		t.Fatal("UsersClient.UpdateAndClear(): expected error when clearing a property that is also being updated")
+	}
+}
+
+func TestClient_TLSConfig(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"value":[{"id":"00000000-0000-0000-0000-000000000000"}]}`)
+	}))
+	defer server.Close()
+
+	roots := x509.NewCertPool()
+	roots.AddCert(server.Certificate())
+
+	for _, c := range []struct {
+		name      string
+		tlsConfig *tls.Config
+		valid     bool
+	}{
+		{name: "default", tlsConfig: nil, valid: false},
+		{name: "rootCAs", tlsConfig: &tls.Config{RootCAs: roots}, valid: true},
+		{name: "insecure", tlsConfig: auth.DangerouslyInsecureTLSConfig(), valid: true},
+	} {
+		client := msgraph.NewClient(msgraph.Version10, "00000000-0000-0000-0000-000000000000")
+		client.Endpoint = environments.ApiEndpoint(server.URL)
+		client.RetryableClient.RetryMax = 0
+		if c.tlsConfig != nil {
+			if err := client.SetTLSConfig(c.tlsConfig); err != nil {
+				t.Fatalf("%s: Client.SetTLSConfig(): %v", c.name, err)
+			}
+		}
+		_, _, err := client.Ping(context.Background(), nil)
+		if c.valid && err != nil {
+			t.Fatalf("%s: Client.Ping(): %v", c.name, err)
+		}
+		if !c.valid && err == nil {
+			t.Fatalf("%s: Client.Ping(): expected certificate verification error", c.name)
+		}
+	}
+}
+
+func TestClient_WithHTTPRetryPolicy(t *testing.T) {
+	var attempts int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		if attempts < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"value":[]}`)
+	}))
+	defer server.Close()
+
+	c := msgraph.NewUsersClient("00000000-0000-0000-0000-000000000000")
+	c.BaseClient.Endpoint = environments.ApiEndpoint(server.URL)
+
+	if err := c.BaseClient.WithHTTPRetryPolicy(auth.RetryPolicy{MaxRetries: -1}); err == nil {
+		t.Fatal("Client.WithHTTPRetryPolicy(): expected error for invalid policy")
+	}
+
+	policy := auth.RetryPolicy{
+		MaxRetries:       3,
+		BaseDelay:        time.Millisecond,
+		MaxDelay:         5 * time.Millisecond,
+		RetryableMethods: []string{http.MethodGet},
+	}
+	if err := c.BaseClient.WithHTTPRetryPolicy(policy); err != nil {
+		t.Fatalf("Client.WithHTTPRetryPolicy(): %v", err)
+	}
+
+	if _, _, err := c.List(context.Background(), odata.Query{}); err != nil {
+		t.Fatalf("UsersClient.List(): %v", err)
+	}
+	if attempts != 3 {
+		t.Fatalf("UsersClient.List(): expected 3 attempts, got %d", attempts)
+	}
+
+	attempts = 0
+	if _, err := c.Delete(context.Background(), "11111111-1111-1111-1111-111111111111"); err == nil {
+		t.Fatal("UsersClient.Delete(): expected error")
+	}
+	if attempts != 1 {
+		t.Fatalf("UsersClient.Delete(): expected DELETE not to be retried, got %d attempts", attempts)
+	}
+}
+
+func TestClient_EndpointPath(t *testing.T) {
+	var paths []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		paths = append(paths, r.URL.Path)
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Query().Get("$skiptoken") == "" {
+			fmt.Fprint(w, `{"@odata.nextLink":"https://graph.microsoft.com/beta/00000000-0000-0000-0000-000000000000/users?$skiptoken=page2","value":[{"id":"11111111-1111-1111-1111-111111111111"}]}`)
+			return
+		}
+		fmt.Fprint(w, `{"value":[{"id":"22222222-2222-2222-2222-222222222222"}]}`)
+	}))
+	defer server.Close()
+
+	c := msgraph.NewUsersClient("00000000-0000-0000-0000-000000000000")
+	c.BaseClient.Endpoint = environments.ApiEndpoint(server.URL + "/recordings/graph/")
+
+	users, _, err := c.List(context.Background(), odata.Query{})
+	if err != nil {
+		t.Fatalf("UsersClient.List(): %v", err)
+	}
+	if users == nil || len(*users) != 2 {
+		t.Fatalf("UsersClient.List(): expected 2 users from both pages, got %v", users)
+	}
+	expected := []string{
+		"/recordings/graph/beta/00000000-0000-0000-0000-000000000000/users",
+		"/recordings/graph/beta/00000000-0000-0000-0000-000000000000/users",
+	}
+	if !reflect.DeepEqual(paths, expected) {
+		t.Fatalf("UsersClient.List(): expected requests to %v, got %v", expected, paths)
+	}
+}
+
+func TestClient_PageInfo(t *testing.T) {
+	var received url.Values
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received = r.URL.Query()
+		w.Header().Set("Content-Type", "application/json")
+		if received.Get("$skiptoken") == "" {
+			fmt.Fprint(w, `{"@odata.nextLink":"https://graph.microsoft.com/beta/00000000-0000-0000-0000-000000000000/users?$top=1&$skiptoken=page2","value":[{"id":"11111111-1111-1111-1111-111111111111"}]}`)
+			return
+		}
+		fmt.Fprint(w, `{"value":[{"id":"22222222-2222-2222-2222-222222222222"}]}`)
+	}))
+	defer server.Close()
+
+	c := msgraph.NewUsersClient("00000000-0000-0000-0000-000000000000")
+	c.BaseClient.Endpoint = environments.ApiEndpoint(server.URL)
+
+	ctx, page := msgraph.WithPageInfo(context.Background())
+	users, _, err := c.List(ctx, odata.Query{Top: 1})
+	if err != nil {
+		t.Fatalf("UsersClient.List(): %v", err)
+	}
+	if users == nil || len(*users) != 1 {
+		t.Fatalf("UsersClient.List(): expected a single page of users, got %v", users)
+	}
+	if page.SkipToken != "page2" || page.NextLink == nil {
+		t.Fatalf("UsersClient.List(): expected skip token %q, got %q", "page2", page.SkipToken)
+	}
+
+	users, _, err = c.List(ctx, odata.Query{Top: 1, SkipToken: page.SkipToken})
+	if err != nil {
+		t.Fatalf("UsersClient.List(): %v", err)
+	}
+	if v := received.Get("$skiptoken"); v != "page2" {
+		t.Fatalf("UsersClient.List(): expected $skiptoken %q to be sent, got %q", "page2", v)
+	}
+	if users == nil || len(*users) != 1 || *(*users)[0].ID != "22222222-2222-2222-2222-222222222222" {
+		t.Fatalf("UsersClient.List(): unexpected second page: %v", users)
+	}
+	if page.SkipToken != "" || page.NextLink != nil {
+		t.Fatalf("UsersClient.List(): expected no further pages, got skip token %q", page.SkipToken)
+	}
+}
+
+func TestClient_PagedResult(t *testing.T) {
+	failing := true
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Query().Get("$skiptoken") {
+		case "":
+			fmt.Fprint(w, `{"@odata.nextLink":"https://graph.microsoft.com/beta/00000000-0000-0000-0000-000000000000/users?$skiptoken=page2","value":[{"id":"11111111-1111-1111-1111-111111111111"}]}`)
+		case "page2":
+			fmt.Fprint(w, `{"@odata.nextLink":"https://graph.microsoft.com/beta/00000000-0000-0000-0000-000000000000/users?$skiptoken=page3","value":[{"id":"22222222-2222-2222-2222-222222222222"}]}`)
+		case "page3":
+			if failing {
+				w.WriteHeader(http.StatusBadRequest)
+				fmt.Fprint(w, `{"error":{"code":"Request_BadRequest","message":"Simulated failure."}}`)
+				return
+			}
+			fmt.Fprint(w, `{"value":[{"id":"33333333-3333-3333-3333-333333333333"}]}`)
+		}
+	}))
+	defer server.Close()
+
+	c := msgraph.NewUsersClient("00000000-0000-0000-0000-000000000000")
+	c.BaseClient.Endpoint = environments.ApiEndpoint(server.URL)
+
+	_, _, err := c.List(context.Background(), odata.Query{})
+	var result *msgraph.PagedResult
+	if !errors.As(err, &result) {
+		t.Fatalf("UsersClient.List(): expected PagedResult error, got: %v", err)
+	}
+	if len(result.Items) != 2 {
+		t.Fatalf("PagedResult: expected 2 items, got %d", len(result.Items))
+	}
+	if token := result.SkipToken(); token != "page3" {
+		t.Fatalf("PagedResult.SkipToken(): expected %q, got %q", "page3", token)
+	}
+	var partial []msgraph.User
+	if err := result.Unmarshal(&partial); err != nil {
+		t.Fatalf("PagedResult.Unmarshal(): %v", err)
+	}
+	if len(partial) != 2 || *partial[1].ID != "22222222-2222-2222-2222-222222222222" {
+		t.Fatalf("PagedResult.Unmarshal(): unexpected users: %v", partial)
+	}
+
+	failing = false
+	remaining, _, err := c.List(context.Background(), odata.Query{SkipToken: result.SkipToken()})
+	if err != nil {
+		t.Fatalf("UsersClient.List(): %v", err)
+	}
+	if remaining == nil || len(*remaining) != 1 || *(*remaining)[0].ID != "33333333-3333-3333-3333-333333333333" {
+		t.Fatalf("UsersClient.List(): unexpected users when resuming: %v", remaining)
+	}
+}
+
+func TestClient_MaxResponseBytes(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Query().Get("$skiptoken") {
+		case "":
+			fmt.Fprint(w, `{"@odata.nextLink":"https://graph.microsoft.com/beta/00000000-0000-0000-0000-000000000000/users?$skiptoken=page2","value":[{"id":"11111111-1111-1111-1111-111111111111"}]}`)
+		case "page2":
+			fmt.Fprint(w, `{"value":[{"id":"22222222-2222-2222-2222-222222222222"}]}`)
+		}
+	}))
+	defer server.Close()
+
+	c := msgraph.NewUsersClient("00000000-0000-0000-0000-000000000000")
+	c.BaseClient.Endpoint = environments.ApiEndpoint(server.URL)
+
+	// No limit by default
+	users, _, err := c.List(context.Background(), odata.Query{})
+	if err != nil {
+		t.Fatalf("UsersClient.List(): %v", err)
+	}
+	if users == nil || len(*users) != 2 {
+		t.Fatalf("UsersClient.List(): expected 2 users, got %v", users)
+	}
+
+	// Each page is within the limit, but the combined pages are not
+	c.BaseClient.MaxResponseBytes = 200
+	if _, _, err = c.List(context.Background(), odata.Query{}); !errors.Is(err, msgraph.ErrResponseTooLarge) {
+		t.Fatalf("UsersClient.List(): expected ErrResponseTooLarge for paged response, got: %v", err)
+	}
+
+	// A single page exceeds the limit
+	c.BaseClient.MaxResponseBytes = 64
+	if _, _, err = c.List(context.Background(), odata.Query{}); !errors.Is(err, msgraph.ErrResponseTooLarge) {
+		t.Fatalf("UsersClient.List(): expected ErrResponseTooLarge, got: %v", err)
+	}
+}
+
+func TestClient_DryRun(t *testing.T) {
+	var methods []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		methods = append(methods, r.Method)
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"id":"11111111-1111-1111-1111-111111111111","displayName":"test-user"}`)
+	}))
+	defer server.Close()
+
+	var logs bytes.Buffer
+	c := msgraph.NewUsersClient("00000000-0000-0000-0000-000000000000")
+	c.BaseClient.Endpoint = environments.ApiEndpoint(server.URL)
+	c.BaseClient.DryRun = true
+	c.BaseClient.DryRunLogger = log.New(&logs, "", 0)
+
+	if _, _, err := c.Get(context.Background(), "11111111-1111-1111-1111-111111111111", odata.Query{}); err != nil {
+		t.Fatalf("UsersClient.Get(): %v", err)
+	}
+
+	_, _, err := c.Create(context.Background(), msgraph.User{DisplayName: utils.StringPtr("test-user")})
+	if !errors.Is(err, msgraph.ErrDryRun) {
+		t.Fatalf("UsersClient.Create(): expected ErrDryRun, got: %v", err)
+	}
+	if _, err := c.Delete(context.Background(), "11111111-1111-1111-1111-111111111111"); !errors.Is(err, msgraph.ErrDryRun) {
+		t.Fatalf("UsersClient.Delete(): expected ErrDryRun, got: %v", err)
+	}
+
+	if !reflect.DeepEqual(methods, []string{http.MethodGet}) {
+		t.Fatalf("expected only a GET request to be sent, got %v", methods)
+	}
+	expected := fmt.Sprintf("[DRY RUN] POST %[1]s/beta/00000000-0000-0000-0000-000000000000/users {\"displayName\":\"test-user\"}\n[DRY RUN] DELETE %[1]s/beta/00000000-0000-0000-0000-000000000000/users/11111111-1111-1111-1111-111111111111\n", server.URL)
+	if logs.String() != expected {
+		t.Fatalf("expected dry run log:\n%s\ngot:\n%s", expected, logs.String())
+	}
+}
+
+func TestClient_HTMLResponse(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		w.Write([]byte("<!DOCTYPE html>\n<html>\n  <head><title>Proxy Login</title></head>\n</html>"))
+	}))
+	defer server.Close()
+
+	c := msgraph.NewUsersClient("00000000-0000-0000-0000-000000000000")
+	c.BaseClient.Endpoint = environments.ApiEndpoint(server.URL)
+
+	_, _, err := c.Get(context.Background(), "00000000-0000-0000-0000-000000000000", odata.Query{})
+	if err == nil {
+		t.Fatal("UsersClient.Get(): expected an error for an HTML response")
+	}
+	if !strings.Contains(err.Error(), "HTML response") || !strings.Contains(err.Error(), "<html> <head><title>Proxy Login</title>") {
+		t.Fatalf("UsersClient.Get(): expected error describing the HTML response, got: %v", err)
+	}
+}
+
+func TestClient_UnsupportedCharset(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json; charset=iso-8859-1")
+		w.Write([]byte(`{"id":"00000000-0000-0000-0000-000000000000"}`))
+	}))
+	defer server.Close()
+
+	c := msgraph.NewUsersClient("00000000-0000-0000-0000-000000000000")
+	c.BaseClient.Endpoint = environments.ApiEndpoint(server.URL)
+
+	_, _, err := c.Get(context.Background(), "00000000-0000-0000-0000-000000000000", odata.Query{})
+	if err == nil || !strings.Contains(err.Error(), "unsupported charset") {
+		t.Fatalf("UsersClient.Get(): expected unsupported charset error, got: %v", err)
+	}
+}
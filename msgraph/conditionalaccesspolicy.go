@@ -26,6 +26,7 @@ func NewConditionalAccessPolicyClient(tenantId string) *ConditionalAccessPolicyC
 // List returns a list of ConditionalAccessPolicy, optionally queried using OData.
 func (c *ConditionalAccessPolicyClient) List(ctx context.Context, query odata.Query) (*[]ConditionalAccessPolicy, int, error) {
 	resp, status, _, err := c.BaseClient.Get(ctx, GetHttpRequestInput{
+		ConsistencyLevel: query.ConsistencyLevel,
 		DisablePaging:    query.Top > 0,
 		ValidStatusCodes: []int{http.StatusOK},
 		Uri: Uri{
@@ -35,7 +36,7 @@ func (c *ConditionalAccessPolicyClient) List(ctx context.Context, query odata.Qu
 		},
 	})
 	if err != nil {
-		return nil, status, fmt.Errorf("ConditionalAccessPolicyClient.BaseClient.Get(): %v", err)
+		return nil, status, fmt.Errorf("ConditionalAccessPolicyClient.BaseClient.Get(): %w", err)
 	}
 
 	defer resp.Body.Close()
@@ -47,7 +48,7 @@ func (c *ConditionalAccessPolicyClient) List(ctx context.Context, query odata.Qu
 	var data struct {
 		ConditionalAccessPolicys []ConditionalAccessPolicy `json:"value"`
 	}
-	if err := json.Unmarshal(respBody, &data); err != nil {
+	if err := c.BaseClient.decode(respBody, &data); err != nil {
 		return nil, status, fmt.Errorf("json.Unmarshal(): %v", err)
 	}
 
@@ -71,7 +72,7 @@ func (c *ConditionalAccessPolicyClient) Create(ctx context.Context, conditionalA
 		},
 	})
 	if err != nil {
-		return nil, status, fmt.Errorf("ConditionalAccessPolicyClient.BaseClient.Post(): %v", err)
+		return nil, status, fmt.Errorf("ConditionalAccessPolicyClient.BaseClient.Post(): %w", err)
 	}
 
 	defer resp.Body.Close()
@@ -81,7 +82,7 @@ func (c *ConditionalAccessPolicyClient) Create(ctx context.Context, conditionalA
 	}
 
 	var newConditionalAccessPolicy ConditionalAccessPolicy
-	if err := json.Unmarshal(respBody, &newConditionalAccessPolicy); err != nil {
+	if err := c.BaseClient.decode(respBody, &newConditionalAccessPolicy); err != nil {
 		return nil, status, fmt.Errorf("json.Unmarshal(): %v", err)
 	}
 
@@ -92,6 +93,7 @@ func (c *ConditionalAccessPolicyClient) Create(ctx context.Context, conditionalA
 func (c *ConditionalAccessPolicyClient) Get(ctx context.Context, id string, query odata.Query) (*ConditionalAccessPolicy, int, error) {
 	resp, status, _, err := c.BaseClient.Get(ctx, GetHttpRequestInput{
 		ConsistencyFailureFunc: RetryOn404ConsistencyFailureFunc,
+		ConsistencyLevel:       query.ConsistencyLevel,
 		ValidStatusCodes:       []int{http.StatusOK},
 		Uri: Uri{
 			Entity:      fmt.Sprintf("/identity/conditionalAccess/policies/%s", id),
@@ -100,7 +102,7 @@ func (c *ConditionalAccessPolicyClient) Get(ctx context.Context, id string, quer
 		},
 	})
 	if err != nil {
-		return nil, status, fmt.Errorf("ConditionalAccessPolicyClient.BaseClient.Get(): %v", err)
+		return nil, status, fmt.Errorf("ConditionalAccessPolicyClient.BaseClient.Get(): %w", err)
 	}
 
 	defer resp.Body.Close()
@@ -110,7 +112,7 @@ func (c *ConditionalAccessPolicyClient) Get(ctx context.Context, id string, quer
 	}
 
 	var conditionalAccessPolicy ConditionalAccessPolicy
-	if err := json.Unmarshal(respBody, &conditionalAccessPolicy); err != nil {
+	if err := c.BaseClient.decode(respBody, &conditionalAccessPolicy); err != nil {
 		return nil, status, fmt.Errorf("json.Unmarshal(): %v", err)
 	}
 
@@ -140,7 +142,7 @@ func (c *ConditionalAccessPolicyClient) Update(ctx context.Context, conditionalA
 		},
 	})
 	if err != nil {
-		return status, fmt.Errorf("ConditionalAccessPolicyClient.BaseClient.Patch(): %v", err)
+		return status, fmt.Errorf("ConditionalAccessPolicyClient.BaseClient.Patch(): %w", err)
 	}
 
 	return status, nil
@@ -157,7 +159,7 @@ func (c *ConditionalAccessPolicyClient) Delete(ctx context.Context, id string) (
 		},
 	})
 	if err != nil {
-		return status, fmt.Errorf("ConditionalAccessPolicyClient.BaseClient.Delete(): %v", err)
+		return status, fmt.Errorf("ConditionalAccessPolicyClient.BaseClient.Delete(): %w", err)
 	}
 
 	return status, nil
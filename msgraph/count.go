@@ -0,0 +1,101 @@
+package msgraph
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/manicminer/hamilton/odata"
+)
+
+// Count returns the number of items in the collection at entity, e.g. `/identityProtection/riskyUsers`, matching the
+// optional Filter or Search in query. The count is requested with `$top=0&$count=true`, so that no items are returned
+// and only the @odata.count annotation is read, which avoids retrieving a page of results when only the count is needed.
+// Any Top, Skip or SkipToken in query is ignored.
+//
+// Directory object collections, such as users, groups, applications, service principals and devices, do not accept
+// a page size of zero, and must instead be counted using CountSegment(). Other collections which support $count, such
+// as risk detections, sign-in logs and Intune managed devices, support this method.
+func (c Client) Count(ctx context.Context, entity string, query odata.Query) (int, int, error) {
+	query.Top, query.Skip, query.SkipToken = 0, 0, ""
+	params := query.Values()
+	params.Set("$count", "true")
+	params.Set("$top", "0")
+
+	resp, status, _, err := c.Get(ctx, GetHttpRequestInput{
+		ConsistencyLevel: query.ConsistencyLevel,
+		DisablePaging:    true,
+		ValidStatusCodes: []int{http.StatusOK},
+		Uri: Uri{
+			Entity:      entity,
+			Params:      params,
+			HasTenantId: true,
+		},
+	})
+	if err != nil {
+		return 0, status, fmt.Errorf("Client.Get(): %w", err)
+	}
+
+	defer resp.Body.Close()
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return 0, status, fmt.Errorf("io.ReadAll(): %v", err)
+	}
+
+	var data struct {
+		Count *int `json:"@odata.count"`
+	}
+	if err := c.decode(respBody, &data); err != nil {
+		return 0, status, fmt.Errorf("json.Unmarshal(): %v", err)
+	}
+	if data.Count == nil {
+		return 0, status, fmt.Errorf("response did not include @odata.count, the collection may not support $count")
+	}
+
+	return *data.Count, status, nil
+}
+
+// CountSegment returns the number of items in the collection at entity, e.g. `/users`, matching the optional Filter or
+// Search in query, by requesting the `/$count` path segment of the collection. This is required for directory object
+// collections, which only support counting with eventual consistency, so ConsistencyLevelEventual is always used.
+// Only the Filter and Search in query are used.
+func (c Client) CountSegment(ctx context.Context, entity string, query odata.Query) (int, int, error) {
+	query = odata.Query{
+		ConsistencyLevel: odata.ConsistencyLevelEventual,
+		Filter:           query.Filter,
+		Search:           query.Search,
+	}
+	params := query.Values()
+	params.Del("$count")
+
+	resp, status, _, err := c.Get(ctx, GetHttpRequestInput{
+		ConsistencyLevel: query.ConsistencyLevel,
+		DisablePaging:    true,
+		ValidStatusCodes: []int{http.StatusOK},
+		Uri: Uri{
+			Entity:      fmt.Sprintf("%s/$count", strings.TrimRight(entity, "/")),
+			Params:      params,
+			HasTenantId: true,
+		},
+	})
+	if err != nil {
+		return 0, status, fmt.Errorf("Client.Get(): %w", err)
+	}
+
+	defer resp.Body.Close()
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return 0, status, fmt.Errorf("io.ReadAll(): %v", err)
+	}
+
+	// The count is returned as plain text, which may be preceded by a byte order mark
+	count, err := strconv.Atoi(strings.TrimSpace(strings.TrimPrefix(string(respBody), "\ufeff")))
+	if err != nil {
+		return 0, status, fmt.Errorf("parsing count: %v", err)
+	}
+
+	return count, status, nil
+}
@@ -0,0 +1,78 @@
+package msgraph
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/manicminer/hamilton/odata"
+)
+
+// credentialAuditSelect are the properties retrieved when auditing credentials.
+var credentialAuditSelect = []string{"id", "appId", "displayName", "keyCredentials", "passwordCredentials"}
+
+// CredentialAuditClient reports on the expiry of credentials belonging to Applications and Service Principals.
+type CredentialAuditClient struct {
+	BaseClient Client
+}
+
+// NewCredentialAuditClient returns a new CredentialAuditClient.
+func NewCredentialAuditClient(tenantId string) *CredentialAuditClient {
+	return &CredentialAuditClient{
+		BaseClient: NewClient(Version10, tenantId),
+	}
+}
+
+// ListApplications returns a CredentialReport for each Application having credentials that have expired or will
+// expire within the specified number of days. Only the matching credentials are included in each report.
+func (c *CredentialAuditClient) ListApplications(ctx context.Context, days int) (*[]CredentialReport, int, error) {
+	client := &ApplicationsClient{BaseClient: c.BaseClient}
+	applications, status, err := client.List(ctx, odata.Query{Select: credentialAuditSelect})
+	if err != nil {
+		return nil, status, fmt.Errorf("ApplicationsClient.List(): %v", err)
+	}
+
+	now := time.Now()
+	ret := make([]CredentialReport, 0)
+	for _, a := range *applications {
+		report := CredentialReport{
+			ID:          a.ID,
+			AppId:       a.AppId,
+			DisplayName: a.DisplayName,
+			Credentials: CredentialStatuses(a.KeyCredentials, a.PasswordCredentials, now),
+		}
+		if expiring := report.Expiring(days); len(expiring) > 0 {
+			report.Credentials = expiring
+			ret = append(ret, report)
+		}
+	}
+
+	return &ret, status, nil
+}
+
+// ListServicePrincipals returns a CredentialReport for each Service Principal having credentials that have expired or
+// will expire within the specified number of days. Only the matching credentials are included in each report.
+func (c *CredentialAuditClient) ListServicePrincipals(ctx context.Context, days int) (*[]CredentialReport, int, error) {
+	client := &ServicePrincipalsClient{BaseClient: c.BaseClient}
+	servicePrincipals, status, err := client.List(ctx, odata.Query{Select: credentialAuditSelect})
+	if err != nil {
+		return nil, status, fmt.Errorf("ServicePrincipalsClient.List(): %v", err)
+	}
+
+	now := time.Now()
+	ret := make([]CredentialReport, 0)
+	for _, s := range *servicePrincipals {
+		report := CredentialReport{
+			ID:          s.ID,
+			AppId:       s.AppId,
+			DisplayName: s.DisplayName,
+			Credentials: CredentialStatuses(s.KeyCredentials, s.PasswordCredentials, now),
+		}
+		if expiring := report.Expiring(days); len(expiring) > 0 {
+			report.Credentials = expiring
+			ret = append(ret, report)
+		}
+	}
+
+	return &ret, status, nil
+}
@@ -0,0 +1,61 @@
+package msgraph_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/manicminer/hamilton/environments"
+	"github.com/manicminer/hamilton/msgraph"
+)
+
+func TestCredentialAuditClient_ListApplications(t *testing.T) {
+	now := time.Now().UTC()
+	expired := now.Add(-47 * time.Hour).Format(time.RFC3339)
+	expiring := now.Add(10*24*time.Hour + time.Hour).Format(time.RFC3339)
+	valid := now.Add(365 * 24 * time.Hour).Format(time.RFC3339)
+
+	var selected string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		selected = r.URL.Query().Get("$select")
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprintf(w, `{"value":[
+			{"id":"app1","keyCredentials":[{"keyId":"key1","endDateTime":%q,"type":"AsymmetricX509Cert","usage":"Verify"}],"passwordCredentials":[{"keyId":"pwd1","endDateTime":%q}]},
+			{"id":"app2","passwordCredentials":[{"keyId":"pwd2","endDateTime":%q}]}
+		]}`, expired, expiring, valid)
+	}))
+	defer server.Close()
+
+	c := msgraph.NewCredentialAuditClient("00000000-0000-0000-0000-000000000000")
+	c.BaseClient.Endpoint = environments.ApiEndpoint(server.URL)
+
+	reports, _, err := c.ListApplications(context.Background(), 30)
+	if err != nil {
+		t.Fatalf("CredentialAuditClient.ListApplications(): %v", err)
+	}
+	if selected != "id,appId,displayName,keyCredentials,passwordCredentials" {
+		t.Fatalf("CredentialAuditClient.ListApplications(): unexpected $select: %q", selected)
+	}
+	if reports == nil || len(*reports) != 1 {
+		t.Fatalf("CredentialAuditClient.ListApplications(): expected 1 report, got %v", reports)
+	}
+
+	report := (*reports)[0]
+	if *report.ID != "app1" || len(report.Credentials) != 2 {
+		t.Fatalf("CredentialAuditClient.ListApplications(): unexpected report: %#v", report)
+	}
+	key, pwd := report.Credentials[0], report.Credentials[1]
+	if key.Type != msgraph.CredentialStatusTypeCertificate || !key.Expired() || key.DaysUntilExpiry != -2 {
+		t.Fatalf("CredentialAuditClient.ListApplications(): unexpected key credential status: %#v", key)
+	}
+	if pwd.Type != msgraph.CredentialStatusTypePassword || pwd.Expired() || pwd.DaysUntilExpiry != 10 {
+		t.Fatalf("CredentialAuditClient.ListApplications(): unexpected password credential status: %#v", pwd)
+	}
+
+	if expired := report.Expiring(0); len(expired) != 1 || *expired[0].KeyId != "key1" {
+		t.Fatalf("CredentialReport.Expiring(): expected only key1 to have expired, got %#v", expired)
+	}
+}
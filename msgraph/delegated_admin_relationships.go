@@ -0,0 +1,96 @@
+package msgraph
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/manicminer/hamilton/odata"
+)
+
+// DelegatedAdminRelationshipsClient performs operations on the granular delegated admin privileges (GDAP)
+// relationships of a Cloud Solution Provider (CSP) partner tenant.
+//
+// This client must be used with a token for the partner tenant, having the DelegatedAdminRelationship.Read.All or
+// DelegatedAdminRelationship.ReadWrite.All permission. Relationships are created in the Partner Center and become
+// active once approved by an administrator of the customer tenant.
+//
+// To make calls in a customer tenant, use an Authorizer obtained with auth.Config.NewAuthorizerForTenant() or
+// auth.TenantAuthorizer.WithTenant(). App-only tokens for a customer tenant require the partner application to be
+// multi-tenant and to have been consented to in the customer tenant, which is typically achieved by the partner
+// granting consent on behalf of the customer using an active relationship that includes the Cloud Application
+// Administrator role. The roles included in a relationship govern delegated access by the partner's users; they do
+// not grant permissions to the application itself.
+type DelegatedAdminRelationshipsClient struct {
+	BaseClient Client
+}
+
+// NewDelegatedAdminRelationshipsClient returns a new DelegatedAdminRelationshipsClient.
+func NewDelegatedAdminRelationshipsClient(tenantId string) *DelegatedAdminRelationshipsClient {
+	return &DelegatedAdminRelationshipsClient{
+		BaseClient: NewClient(Version10, tenantId),
+	}
+}
+
+// List returns a list of DelegatedAdminRelationships, optionally queried using OData.
+func (c *DelegatedAdminRelationshipsClient) List(ctx context.Context, query odata.Query) (*[]DelegatedAdminRelationship, int, error) {
+	resp, status, _, err := c.BaseClient.Get(ctx, GetHttpRequestInput{
+		ConsistencyLevel: query.ConsistencyLevel,
+		DisablePaging:    query.Top > 0,
+		ValidStatusCodes: []int{http.StatusOK},
+		Uri: Uri{
+			Entity:      "/tenantRelationships/delegatedAdminRelationships",
+			Params:      query.Values(),
+			HasTenantId: true,
+		},
+	})
+	if err != nil {
+		return nil, status, fmt.Errorf("DelegatedAdminRelationshipsClient.BaseClient.Get(): %w", err)
+	}
+
+	defer resp.Body.Close()
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, status, fmt.Errorf("io.ReadAll(): %v", err)
+	}
+
+	var data struct {
+		Relationships []DelegatedAdminRelationship `json:"value"`
+	}
+	if err := c.BaseClient.decode(respBody, &data); err != nil {
+		return nil, status, fmt.Errorf("json.Unmarshal(): %v", err)
+	}
+
+	return &data.Relationships, status, nil
+}
+
+// Get retrieves a DelegatedAdminRelationship.
+func (c *DelegatedAdminRelationshipsClient) Get(ctx context.Context, id string, query odata.Query) (*DelegatedAdminRelationship, int, error) {
+	resp, status, _, err := c.BaseClient.Get(ctx, GetHttpRequestInput{
+		ConsistencyFailureFunc: RetryOn404ConsistencyFailureFunc,
+		ConsistencyLevel:       query.ConsistencyLevel,
+		ValidStatusCodes:       []int{http.StatusOK},
+		Uri: Uri{
+			Entity:      fmt.Sprintf("/tenantRelationships/delegatedAdminRelationships/%s", id),
+			Params:      query.Values(),
+			HasTenantId: true,
+		},
+	})
+	if err != nil {
+		return nil, status, fmt.Errorf("DelegatedAdminRelationshipsClient.BaseClient.Get(): %w", err)
+	}
+
+	defer resp.Body.Close()
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, status, fmt.Errorf("io.ReadAll(): %v", err)
+	}
+
+	var relationship DelegatedAdminRelationship
+	if err := c.BaseClient.decode(respBody, &relationship); err != nil {
+		return nil, status, fmt.Errorf("json.Unmarshal(): %v", err)
+	}
+
+	return &relationship, status, nil
+}
@@ -0,0 +1,56 @@
+package msgraph_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/manicminer/hamilton/environments"
+	"github.com/manicminer/hamilton/msgraph"
+	"github.com/manicminer/hamilton/odata"
+)
+
+func TestDelegatedAdminRelationshipsClient(t *testing.T) {
+	const relationship = `{"id":"5d027261-d21f-4aa9-b7db-7fa1f56fb163-8777b240-c6f0-4469-9e98-a3205431b836","displayName":"Contoso admin","status":"active","duration":"P730D",
+		"customer":{"tenantId":"11111111-1111-1111-1111-111111111111","displayName":"Contoso"},
+		"accessDetails":{"unifiedRoles":[{"roleDefinitionId":"158c047a-c907-4556-b7ef-446551a6b5f7"}]}}`
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/v1.0/00000000-0000-0000-0000-000000000000/tenantRelationships/delegatedAdminRelationships":
+			w.Write([]byte(`{"value":[` + relationship + `]}`))
+		case "/v1.0/00000000-0000-0000-0000-000000000000/tenantRelationships/delegatedAdminRelationships/5d027261-d21f-4aa9-b7db-7fa1f56fb163-8777b240-c6f0-4469-9e98-a3205431b836":
+			w.Write([]byte(relationship))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"error":{"code":"NotFound","message":"not found"}}`))
+		}
+	}))
+	defer server.Close()
+
+	c := msgraph.NewDelegatedAdminRelationshipsClient("00000000-0000-0000-0000-000000000000")
+	c.BaseClient.Endpoint = environments.ApiEndpoint(server.URL)
+
+	relationships, _, err := c.List(context.Background(), odata.Query{Filter: odata.Eq("status", msgraph.DelegatedAdminRelationshipStatusActive).String()})
+	if err != nil {
+		t.Fatalf("DelegatedAdminRelationshipsClient.List(): %v", err)
+	}
+	if relationships == nil || len(*relationships) != 1 {
+		t.Fatalf("DelegatedAdminRelationshipsClient.List(): expected 1 relationship, got %v", relationships)
+	}
+
+	r, _, err := c.Get(context.Background(), *(*relationships)[0].ID, odata.Query{})
+	if err != nil {
+		t.Fatalf("DelegatedAdminRelationshipsClient.Get(): %v", err)
+	}
+	if r.Customer == nil || r.Customer.TenantId == nil || *r.Customer.TenantId != "11111111-1111-1111-1111-111111111111" {
+		t.Fatalf("DelegatedAdminRelationshipsClient.Get(): expected customer tenant, got %+v", r.Customer)
+	}
+	if r.Status == nil || *r.Status != msgraph.DelegatedAdminRelationshipStatusActive {
+		t.Fatalf("DelegatedAdminRelationshipsClient.Get(): expected active status, got %v", r.Status)
+	}
+	if r.AccessDetails == nil || r.AccessDetails.UnifiedRoles == nil || len(*r.AccessDetails.UnifiedRoles) != 1 {
+		t.Fatalf("DelegatedAdminRelationshipsClient.Get(): expected 1 role, got %+v", r.AccessDetails)
+	}
+}
@@ -0,0 +1,323 @@
+package msgraph
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/manicminer/hamilton/odata"
+)
+
+// deletedItemsRestoreMaxRetries is the number of times RestoreMany re-submits throttled requests in each batch
+const deletedItemsRestoreMaxRetries = 5
+
+// DeletedItemsClient performs operations on soft-deleted directory objects.
+// Deleted applications, groups, service principals and users are retained for 30 days before being permanently removed.
+type DeletedItemsClient struct {
+	BaseClient Client
+}
+
+// NewDeletedItemsClient returns a new DeletedItemsClient.
+func NewDeletedItemsClient(tenantId string) *DeletedItemsClient {
+	return &DeletedItemsClient{
+		BaseClient: NewClient(Version10, tenantId),
+	}
+}
+
+// List returns a list of deleted objects of the specified type, optionally queried using OData.
+// objectType is the short type name of the objects to list, e.g. odata.ShortTypeUser.
+func (c *DeletedItemsClient) List(ctx context.Context, objectType odata.ShortType, query odata.Query) (*[]DeletedItem, int, error) {
+	resp, status, _, err := c.BaseClient.Get(ctx, GetHttpRequestInput{
+		ConsistencyLevel: query.ConsistencyLevel,
+		DisablePaging:    query.Top > 0,
+		ValidStatusCodes: []int{http.StatusOK},
+		Uri: Uri{
+			Entity:      fmt.Sprintf("/directory/deletedItems/microsoft.graph.%s", objectType),
+			Params:      query.Values(),
+			HasTenantId: true,
+		},
+	})
+	if err != nil {
+		return nil, status, fmt.Errorf("DeletedItemsClient.BaseClient.Get(): %w", err)
+	}
+
+	defer resp.Body.Close()
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, status, fmt.Errorf("io.ReadAll(): %v", err)
+	}
+
+	var data struct {
+		DeletedItems *[]json.RawMessage `json:"value"`
+	}
+	if err := c.BaseClient.decode(respBody, &data); err != nil {
+		return nil, status, fmt.Errorf("json.Unmarshal(): %v", err)
+	}
+
+	ret := make([]DeletedItem, 0)
+
+	if data.DeletedItems == nil {
+		// Treat this as no result
+		return &ret, status, nil
+	}
+
+	// Items in a type-cast collection may omit their @odata.type, so fall back to the requested type
+	for _, item := range *data.DeletedItems {
+		deletedItem, err := unmarshalDeletedItem(item, fmt.Sprintf("#microsoft.graph.%s", objectType))
+		if err != nil {
+			return nil, status, err
+		}
+		ret = append(ret, deletedItem)
+	}
+
+	return &ret, status, nil
+}
+
+// Count returns the number of deleted objects of the specified type, optionally matching the Filter or Search in
+// query, e.g. using odata.DateRangeFilter() with the deletedDateTime property. Counting is an advanced query, so
+// eventual consistency is always used.
+// objectType is the short type name of the objects to count, e.g. odata.ShortTypeUser.
+func (c *DeletedItemsClient) Count(ctx context.Context, objectType odata.ShortType, query odata.Query) (int, int, error) {
+	count, status, err := c.BaseClient.CountSegment(ctx, fmt.Sprintf("/directory/deletedItems/microsoft.graph.%s", objectType), query)
+	if err != nil {
+		return 0, status, fmt.Errorf("DeletedItemsClient.BaseClient.CountSegment(): %w", err)
+	}
+
+	return count, status, nil
+}
+
+// GetByIds retrieves multiple deleted objects from a list of IDs, optionally restricted to the specified types. Each
+// object can be type asserted back to the appropriate model. IDs which do not match a deleted object are omitted.
+func (c *DeletedItemsClient) GetByIds(ctx context.Context, ids []string, types []odata.ShortType) (*[]DeletedItem, int, error) {
+	var status int
+
+	body, err := json.Marshal(struct {
+		IDs   []string          `json:"ids"`
+		Types []odata.ShortType `json:"types,omitempty"`
+	}{
+		IDs:   ids,
+		Types: types,
+	})
+	if err != nil {
+		return nil, status, fmt.Errorf("json.Marshal(): %v", err)
+	}
+
+	resp, status, _, err := c.BaseClient.Post(ctx, PostHttpRequestInput{
+		Body:             body,
+		ValidStatusCodes: []int{http.StatusOK},
+		Uri: Uri{
+			Entity:      "/directory/deletedItems/getByIds",
+			HasTenantId: true,
+		},
+	})
+	if err != nil {
+		return nil, status, fmt.Errorf("DeletedItemsClient.BaseClient.Post(): %w", err)
+	}
+
+	defer resp.Body.Close()
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, status, fmt.Errorf("io.ReadAll(): %v", err)
+	}
+
+	var data struct {
+		DeletedItems []json.RawMessage `json:"value"`
+	}
+	if err := c.BaseClient.decode(respBody, &data); err != nil {
+		return nil, status, fmt.Errorf("json.Unmarshal(): %v", err)
+	}
+
+	ret := make([]DeletedItem, 0, len(data.DeletedItems))
+	for _, item := range data.DeletedItems {
+		deletedItem, err := unmarshalDeletedItem(item, "")
+		if err != nil {
+			return nil, status, err
+		}
+		ret = append(ret, deletedItem)
+	}
+
+	return &ret, status, nil
+}
+
+// Get retrieves a deleted object, which can be type asserted back to the appropriate model.
+// id is the object ID of the deleted object.
+func (c *DeletedItemsClient) Get(ctx context.Context, id string, query odata.Query) (*DeletedItem, int, error) {
+	resp, status, _, err := c.BaseClient.Get(ctx, GetHttpRequestInput{
+		ConsistencyFailureFunc: RetryOn404ConsistencyFailureFunc,
+		ConsistencyLevel:       query.ConsistencyLevel,
+		ValidStatusCodes:       []int{http.StatusOK},
+		Uri: Uri{
+			Entity:      fmt.Sprintf("/directory/deletedItems/%s", id),
+			Params:      query.Values(),
+			HasTenantId: true,
+		},
+	})
+	if err != nil {
+		return nil, status, fmt.Errorf("DeletedItemsClient.BaseClient.Get(): %w", err)
+	}
+
+	defer resp.Body.Close()
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, status, fmt.Errorf("io.ReadAll(): %v", err)
+	}
+
+	deletedItem, err := unmarshalDeletedItem(respBody, "")
+	if err != nil {
+		return nil, status, err
+	}
+
+	return &deletedItem, status, nil
+}
+
+// Restore restores a recently deleted object, which can be type asserted back to the appropriate model.
+// id is the object ID of the deleted object.
+func (c *DeletedItemsClient) Restore(ctx context.Context, id string) (*DeletedItem, int, error) {
+	resp, status, _, err := c.BaseClient.Post(ctx, PostHttpRequestInput{
+		ConsistencyFailureFunc: RetryOn404ConsistencyFailureFunc,
+		ValidStatusCodes:       []int{http.StatusOK},
+		Uri: Uri{
+			Entity:      fmt.Sprintf("/directory/deletedItems/%s/restore", id),
+			HasTenantId: true,
+		},
+	})
+	if err != nil {
+		return nil, status, fmt.Errorf("DeletedItemsClient.BaseClient.Post(): %w", err)
+	}
+
+	defer resp.Body.Close()
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, status, fmt.Errorf("io.ReadAll(): %v", err)
+	}
+
+	restoredItem, err := unmarshalDeletedItem(respBody, "")
+	if err != nil {
+		return nil, status, err
+	}
+
+	return &restoredItem, status, nil
+}
+
+// RestoreMany restores multiple recently deleted objects using JSON batching, sending up to 20 requests in each batch.
+// Throttled requests are re-submitted after honoring their Retry-After header. The returned result contains the
+// restored objects, which can be type asserted back to the appropriate models, and describes any objects which could
+// not be restored. An error is only returned when a batch could not be sent, in which case later batches are not sent.
+func (c *DeletedItemsClient) RestoreMany(ctx context.Context, ids []string) (*DeletedItemsRestoreResult, int, error) {
+	batchClient := &BatchClient{BaseClient: c.BaseClient}
+	result := DeletedItemsRestoreResult{
+		Restored: make([]DeletedItem, 0),
+		Failures: make([]DeletedItemsRestoreFailure, 0),
+	}
+	var status int
+
+	for start := 0; start < len(ids); start += batchMaxRequests {
+		end := start + batchMaxRequests
+		if end > len(ids) {
+			end = len(ids)
+		}
+
+		requests := make([]BatchRequest, 0, end-start)
+		for i, id := range ids[start:end] {
+			requests = append(requests, BatchRequest{
+				ID:     strconv.Itoa(i),
+				Method: http.MethodPost,
+				Url:    fmt.Sprintf("/directory/deletedItems/%s/restore", id),
+			})
+		}
+
+		responses, _, s, err := batchClient.SendWithRetries(ctx, requests, deletedItemsRestoreMaxRetries)
+		status = s
+		if err != nil {
+			return nil, status, fmt.Errorf("BatchClient.SendWithRetries(): %v", err)
+		}
+
+		for i, id := range ids[start:end] {
+			resp, ok := responses[strconv.Itoa(i)]
+			if ok && resp.Status >= 200 && resp.Status < 300 {
+				if restoredItem, err := unmarshalDeletedItem(resp.Body, ""); err == nil {
+					result.Restored = append(result.Restored, restoredItem)
+					continue
+				}
+			}
+			result.Failures = append(result.Failures, DeletedItemsRestoreFailure{
+				ID:     id,
+				Status: resp.Status,
+				Error:  batchResponseError(resp),
+			})
+		}
+	}
+
+	return &result, status, nil
+}
+
+// DeletePermanently removes a deleted object permanently.
+// id is the object ID of the deleted object.
+func (c *DeletedItemsClient) DeletePermanently(ctx context.Context, id string) (int, error) {
+	_, status, _, err := c.BaseClient.Delete(ctx, DeleteHttpRequestInput{
+		ConsistencyFailureFunc: RetryOn404ConsistencyFailureFunc,
+		ValidStatusCodes:       []int{http.StatusNoContent},
+		Uri: Uri{
+			Entity:      fmt.Sprintf("/directory/deletedItems/%s", id),
+			HasTenantId: true,
+		},
+	})
+	if err != nil {
+		return status, fmt.Errorf("DeletedItemsClient.BaseClient.Delete(): %w", err)
+	}
+
+	return status, nil
+}
+
+// unmarshalDeletedItem matches up a deleted object to the appropriate model using its @odata.type.
+// defaultType is used when the object does not specify its own type.
+func unmarshalDeletedItem(data []byte, defaultType odata.Type) (DeletedItem, error) {
+	var o odata.OData
+	if err := json.Unmarshal(data, &o); err != nil {
+		return nil, fmt.Errorf("json.Unmarshal(): %v", err)
+	}
+
+	objectType := defaultType
+	if o.Type != nil {
+		objectType = *o.Type
+	}
+
+	var ret DeletedItem
+	switch objectType {
+	case odata.TypeApplication:
+		var application Application
+		if err := json.Unmarshal(data, &application); err != nil {
+			return nil, fmt.Errorf("json.Unmarshal(): %v", err)
+		}
+		ret = application
+	case odata.TypeGroup:
+		var group Group
+		if err := json.Unmarshal(data, &group); err != nil {
+			return nil, fmt.Errorf("json.Unmarshal(): %v", err)
+		}
+		ret = group
+	case odata.TypeServicePrincipal:
+		var servicePrincipal ServicePrincipal
+		if err := json.Unmarshal(data, &servicePrincipal); err != nil {
+			return nil, fmt.Errorf("json.Unmarshal(): %v", err)
+		}
+		ret = servicePrincipal
+	case odata.TypeUser:
+		var user User
+		if err := json.Unmarshal(data, &user); err != nil {
+			return nil, fmt.Errorf("json.Unmarshal(): %v", err)
+		}
+		ret = user
+	default:
+		var directoryObject DirectoryObject
+		if err := json.Unmarshal(data, &directoryObject); err != nil {
+			return nil, fmt.Errorf("json.Unmarshal(): %v", err)
+		}
+		ret = directoryObject
+	}
+
+	return ret, nil
+}
@@ -0,0 +1,213 @@
+package msgraph_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/manicminer/hamilton/auth"
+	"github.com/manicminer/hamilton/environments"
+	"github.com/manicminer/hamilton/internal/test"
+	"github.com/manicminer/hamilton/internal/utils"
+	"github.com/manicminer/hamilton/msgraph"
+	"github.com/manicminer/hamilton/odata"
+)
+
+type DeletedItemsClientTest struct {
+	connection   *test.Connection
+	client       *msgraph.DeletedItemsClient
+	randomString string
+}
+
+func TestDeletedItemsClient(t *testing.T) {
+	rs := test.RandomString()
+	c := DeletedItemsClientTest{
+		connection:   test.NewConnection(auth.MsGraph, auth.TokenVersion2),
+		randomString: rs,
+	}
+	c.client = msgraph.NewDeletedItemsClient(c.connection.AuthConfig.TenantID)
+	c.client.BaseClient.Authorizer = c.connection.Authorizer
+
+	u := UsersClientTest{
+		connection:   test.NewConnection(auth.MsGraph, auth.TokenVersion2),
+		randomString: rs,
+	}
+	u.client = msgraph.NewUsersClient(u.connection.AuthConfig.TenantID)
+	u.client.BaseClient.Authorizer = u.connection.Authorizer
+
+	user := testUsersClient_Create(t, u, msgraph.User{
+		AccountEnabled:    utils.BoolPtr(true),
+		DisplayName:       utils.StringPtr("test-user-deleteditems"),
+		MailNickname:      utils.StringPtr(fmt.Sprintf("test-user-deleteditems-%s", c.randomString)),
+		UserPrincipalName: utils.StringPtr(fmt.Sprintf("test-user-deleteditems-%s@%s", c.randomString, c.connection.DomainName)),
+		PasswordProfile: &msgraph.UserPasswordProfile{
+			Password: utils.StringPtr(fmt.Sprintf("IrPa55w0rd%s", c.randomString)),
+		},
+	})
+	testUsersClient_Delete(t, u, *user.ID)
+
+	testDeletedItemsClient_List(t, c, odata.ShortTypeUser, *user.ID)
+	testDeletedItemsClient_Get(t, c, *user.ID)
+	testDeletedItemsClient_Restore(t, c, *user.ID)
+	testUsersClient_Delete(t, u, *user.ID)
+	testDeletedItemsClient_DeletePermanently(t, c, *user.ID)
+}
+
+func testDeletedItemsClient_List(t *testing.T, c DeletedItemsClientTest, objectType odata.ShortType, expectedId string) (deletedItems *[]msgraph.DeletedItem) {
+	deletedItems, status, err := c.client.List(c.connection.Context, objectType, odata.Query{
+		Filter: fmt.Sprintf("id eq '%s'", expectedId),
+	})
+	if err != nil {
+		t.Fatalf("DeletedItemsClient.List(): %v", err)
+	}
+	if status < 200 || status >= 300 {
+		t.Fatalf("DeletedItemsClient.List(): invalid status: %d", status)
+	}
+	if deletedItems == nil {
+		t.Fatal("DeletedItemsClient.List(): deletedItems was nil")
+	}
+	found := false
+	for _, item := range *deletedItems {
+		if user, ok := item.(msgraph.User); ok && user.ID != nil && *user.ID == expectedId {
+			found = true
+			break
+		}
+	}
+	if !found {
+		t.Fatalf("DeletedItemsClient.List(): expected user %q in result", expectedId)
+	}
+	return
+}
+
+func testDeletedItemsClient_Get(t *testing.T, c DeletedItemsClientTest, id string) (deletedItem *msgraph.DeletedItem) {
+	deletedItem, status, err := c.client.Get(c.connection.Context, id, odata.Query{})
+	if err != nil {
+		t.Fatalf("DeletedItemsClient.Get(): %v", err)
+	}
+	if status < 200 || status >= 300 {
+		t.Fatalf("DeletedItemsClient.Get(): invalid status: %d", status)
+	}
+	if deletedItem == nil {
+		t.Fatal("DeletedItemsClient.Get(): deletedItem was nil")
+	}
+	if _, ok := (*deletedItem).(msgraph.User); !ok {
+		t.Fatalf("DeletedItemsClient.Get(): expected a User, got %T", *deletedItem)
+	}
+	return
+}
+
+func testDeletedItemsClient_Restore(t *testing.T, c DeletedItemsClientTest, id string) {
+	restoredItem, status, err := c.client.Restore(c.connection.Context, id)
+	if err != nil {
+		t.Fatalf("DeletedItemsClient.Restore(): %v", err)
+	}
+	if status < 200 || status >= 300 {
+		t.Fatalf("DeletedItemsClient.Restore(): invalid status: %d", status)
+	}
+	if restoredItem == nil {
+		t.Fatal("DeletedItemsClient.Restore(): restoredItem was nil")
+	}
+	user, ok := (*restoredItem).(msgraph.User)
+	if !ok {
+		t.Fatalf("DeletedItemsClient.Restore(): expected a User, got %T", *restoredItem)
+	}
+	if user.ID == nil || *user.ID != id {
+		t.Fatal("DeletedItemsClient.Restore(): user ids do not match")
+	}
+}
+
+func testDeletedItemsClient_DeletePermanently(t *testing.T, c DeletedItemsClientTest, id string) {
+	status, err := c.client.DeletePermanently(c.connection.Context, id)
+	if err != nil {
+		t.Fatalf("DeletedItemsClient.DeletePermanently(): %v", err)
+	}
+	if status < 200 || status >= 300 {
+		t.Fatalf("DeletedItemsClient.DeletePermanently(): invalid status: %d", status)
+	}
+}
+
+func TestDeletedItemsClient_Recovery(t *testing.T) {
+	const basePath = "/v1.0/00000000-0000-0000-0000-000000000000"
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case basePath + "/directory/deletedItems/microsoft.graph.user/$count":
+			if r.Header.Get("ConsistencyLevel") != "eventual" {
+				t.Errorf("expected eventual consistency for count, got %q", r.Header.Get("ConsistencyLevel"))
+			}
+			w.Header().Set("Content-Type", "text/plain")
+			w.Write([]byte("42"))
+		case basePath + "/directory/deletedItems/getByIds":
+			w.Header().Set("Content-Type", "application/json")
+			w.Write([]byte(`{"value":[{"@odata.type":"#microsoft.graph.user","id":"user1","displayName":"Alice"},{"@odata.type":"#microsoft.graph.group","id":"group1","displayName":"Sales"}]}`))
+		case basePath + "/$batch":
+			var batch struct {
+				Requests []msgraph.BatchRequest `json:"requests"`
+			}
+			if err := json.NewDecoder(r.Body).Decode(&batch); err != nil {
+				t.Errorf("json.Decode(): %v", err)
+			}
+			responses := make([]msgraph.BatchResponse, 0)
+			for _, req := range batch.Requests {
+				id := strings.TrimSuffix(strings.TrimPrefix(req.Url, "/directory/deletedItems/"), "/restore")
+				if req.Method != http.MethodPost || id == req.Url {
+					t.Errorf("unexpected batch request %s %s", req.Method, req.Url)
+				}
+				resp := msgraph.BatchResponse{ID: req.ID, Status: http.StatusOK, Body: json.RawMessage(fmt.Sprintf(`{"@odata.type":"#microsoft.graph.user","id":%q}`, id))}
+				if id == "user2" {
+					resp = msgraph.BatchResponse{ID: req.ID, Status: http.StatusBadRequest, Body: json.RawMessage(`{"error":{"code":"Request_BadRequest","message":"Another object with the same value for property userPrincipalName already exists."}}`)}
+				}
+				responses = append(responses, resp)
+			}
+			w.Header().Set("Content-Type", "application/json")
+			json.NewEncoder(w).Encode(map[string]interface{}{"responses": responses})
+		default:
+			t.Errorf("unexpected request: %s %s", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	c := msgraph.NewDeletedItemsClient("00000000-0000-0000-0000-000000000000")
+	c.BaseClient.Endpoint = environments.ApiEndpoint(server.URL)
+
+	count, _, err := c.Count(context.Background(), odata.ShortTypeUser, odata.Query{})
+	if err != nil {
+		t.Fatalf("DeletedItemsClient.Count(): %v", err)
+	}
+	if count != 42 {
+		t.Fatalf("DeletedItemsClient.Count(): expected 42, got %d", count)
+	}
+
+	items, _, err := c.GetByIds(context.Background(), []string{"user1", "group1"}, nil)
+	if err != nil {
+		t.Fatalf("DeletedItemsClient.GetByIds(): %v", err)
+	}
+	if items == nil || len(*items) != 2 {
+		t.Fatalf("DeletedItemsClient.GetByIds(): expected 2 items, got %v", items)
+	}
+	if user, ok := (*items)[0].(msgraph.User); !ok || *user.DisplayName != "Alice" {
+		t.Fatalf("DeletedItemsClient.GetByIds(): expected a User, got %#v", (*items)[0])
+	}
+	if _, ok := (*items)[1].(msgraph.Group); !ok {
+		t.Fatalf("DeletedItemsClient.GetByIds(): expected a Group, got %#v", (*items)[1])
+	}
+
+	ids := make([]string, 0)
+	for i := 1; i <= 25; i++ {
+		ids = append(ids, fmt.Sprintf("user%d", i))
+	}
+	result, _, err := c.RestoreMany(context.Background(), ids)
+	if err != nil {
+		t.Fatalf("DeletedItemsClient.RestoreMany(): %v", err)
+	}
+	if len(result.Restored) != 24 {
+		t.Fatalf("DeletedItemsClient.RestoreMany(): expected 24 restored objects, got %d", len(result.Restored))
+	}
+	if len(result.Failures) != 1 || result.Failures[0].ID != "user2" || result.Failures[0].Status != http.StatusBadRequest || result.Failures[0].Error == nil {
+		t.Fatalf("DeletedItemsClient.RestoreMany(): unexpected failures %+v", result.Failures)
+	}
+}
@@ -2,7 +2,6 @@ package msgraph
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
@@ -25,6 +24,7 @@ func NewDirectoryAuditReportsClient(tenantId string) *DirectoryAuditReportsClien
 // List returns a list of Directory audit report logs, optionally queried using OData.
 func (c *DirectoryAuditReportsClient) List(ctx context.Context, query odata.Query) (*[]DirectoryAudit, int, error) {
 	resp, status, _, err := c.BaseClient.Get(ctx, GetHttpRequestInput{
+		ConsistencyLevel: query.ConsistencyLevel,
 		DisablePaging:    query.Top > 0,
 		ValidStatusCodes: []int{http.StatusOK},
 		Uri: Uri{
@@ -34,7 +34,7 @@ func (c *DirectoryAuditReportsClient) List(ctx context.Context, query odata.Quer
 		},
 	})
 	if err != nil {
-		return nil, status, fmt.Errorf("DirectoryAuditReportsClient.BaseClient.Get(): %v", err)
+		return nil, status, fmt.Errorf("DirectoryAuditReportsClient.BaseClient.Get(): %w", err)
 	}
 
 	defer resp.Body.Close()
@@ -46,7 +46,7 @@ func (c *DirectoryAuditReportsClient) List(ctx context.Context, query odata.Quer
 	var data struct {
 		DirectoryAuditReports []DirectoryAudit `json:"value"`
 	}
-	if err := json.Unmarshal(respBody, &data); err != nil {
+	if err := c.BaseClient.decode(respBody, &data); err != nil {
 		return nil, status, fmt.Errorf("json.Unmarshal(): %v", err)
 	}
 
@@ -57,6 +57,7 @@ func (c *DirectoryAuditReportsClient) List(ctx context.Context, query odata.Quer
 func (c *DirectoryAuditReportsClient) Get(ctx context.Context, id string, query odata.Query) (*DirectoryAudit, int, error) {
 	resp, status, _, err := c.BaseClient.Get(ctx, GetHttpRequestInput{
 		ConsistencyFailureFunc: RetryOn404ConsistencyFailureFunc,
+		ConsistencyLevel:       query.ConsistencyLevel,
 		ValidStatusCodes:       []int{http.StatusOK},
 		Uri: Uri{
 			Entity:      fmt.Sprintf("/auditLogs/directoryAudits/%s", id),
@@ -65,7 +66,7 @@ func (c *DirectoryAuditReportsClient) Get(ctx context.Context, id string, query
 		},
 	})
 	if err != nil {
-		return nil, status, fmt.Errorf("DirectoryAuditReportsClient.BaseClient.Get(): %v", err)
+		return nil, status, fmt.Errorf("DirectoryAuditReportsClient.BaseClient.Get(): %w", err)
 	}
 
 	defer resp.Body.Close()
@@ -75,7 +76,7 @@ func (c *DirectoryAuditReportsClient) Get(ctx context.Context, id string, query
 	}
 
 	var directoryAuditReport DirectoryAudit
-	if err := json.Unmarshal(respBody, &directoryAuditReport); err != nil {
+	if err := c.BaseClient.decode(respBody, &directoryAuditReport); err != nil {
 		return nil, status, fmt.Errorf("json.Unmarshal(): %v", err)
 	}
 
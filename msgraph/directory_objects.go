@@ -6,7 +6,9 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"time"
 
+	"github.com/manicminer/hamilton/auth"
 	"github.com/manicminer/hamilton/internal/utils"
 	"github.com/manicminer/hamilton/odata"
 )
@@ -27,6 +29,7 @@ func NewDirectoryObjectsClient(tenantId string) *DirectoryObjectsClient {
 func (c *DirectoryObjectsClient) Get(ctx context.Context, id string, query odata.Query) (*DirectoryObject, int, error) {
 	resp, status, _, err := c.BaseClient.Get(ctx, GetHttpRequestInput{
 		ConsistencyFailureFunc: RetryOn404ConsistencyFailureFunc,
+		ConsistencyLevel:       query.ConsistencyLevel,
 		ValidStatusCodes:       []int{http.StatusOK},
 		Uri: Uri{
 			Entity:      fmt.Sprintf("/directoryObjects/%s", id),
@@ -35,7 +38,7 @@ func (c *DirectoryObjectsClient) Get(ctx context.Context, id string, query odata
 		},
 	})
 	if err != nil {
-		return nil, status, fmt.Errorf("DirectoryObjects.BaseClient.Get(): %v", err)
+		return nil, status, fmt.Errorf("DirectoryObjects.BaseClient.Get(): %w", err)
 	}
 
 	defer resp.Body.Close()
@@ -45,13 +48,72 @@ func (c *DirectoryObjectsClient) Get(ctx context.Context, id string, query odata
 	}
 
 	var directoryObject DirectoryObject
-	if err := json.Unmarshal(respBody, &directoryObject); err != nil {
+	if err := c.BaseClient.decode(respBody, &directoryObject); err != nil {
 		return nil, status, fmt.Errorf("json.Unmarshal(): %v", err)
 	}
 
 	return &directoryObject, status, nil
 }
 
+// WaitForObject polls for a DirectoryObject until it is visible, or until the timeout elapses, and returns the object
+// once found. Newly created objects such as groups and applications may not be immediately visible due to eventual
+// consistency, so this can be used before performing dependent operations such as adding members. The delay between
+// attempts increases according to the retry policy configured with WithHTTPRetryPolicy, or auth.DefaultRetryPolicy
+// if none is configured. When the timeout elapses, the returned error wraps context.DeadlineExceeded.
+// id is the object ID of the directory object.
+func (c *DirectoryObjectsClient) WaitForObject(ctx context.Context, id string, timeout time.Duration) (*DirectoryObject, int, error) {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	policy := auth.DefaultRetryPolicy()
+	if c.BaseClient.retryPolicy != nil {
+		policy = c.BaseClient.retryPolicy
+	}
+
+	for attempt := 0; ; attempt++ {
+		resp, status, _, err := c.BaseClient.Get(ctx, GetHttpRequestInput{
+			ValidStatusCodes: []int{http.StatusOK},
+			ValidStatusFunc: func(resp *http.Response, _ *odata.OData) bool {
+				return resp.StatusCode == http.StatusNotFound
+			},
+			Uri: Uri{
+				Entity:      fmt.Sprintf("/directoryObjects/%s", id),
+				HasTenantId: true,
+			},
+		})
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil, status, fmt.Errorf("timed out waiting for directory object %q: %w", id, ctx.Err())
+			}
+			return nil, status, fmt.Errorf("DirectoryObjects.BaseClient.Get(): %w", err)
+		}
+
+		if status == http.StatusOK {
+			defer resp.Body.Close()
+			respBody, err := io.ReadAll(resp.Body)
+			if err != nil {
+				return nil, status, fmt.Errorf("io.ReadAll(): %v", err)
+			}
+
+			var directoryObject DirectoryObject
+			if err := c.BaseClient.decode(respBody, &directoryObject); err != nil {
+				return nil, status, fmt.Errorf("json.Unmarshal(): %v", err)
+			}
+
+			return &directoryObject, status, nil
+		}
+		resp.Body.Close()
+
+		timer := time.NewTimer(policy.Backoff(attempt, nil))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, status, fmt.Errorf("timed out waiting for directory object %q: %w", id, ctx.Err())
+		case <-timer.C:
+		}
+	}
+}
+
 // GetByIds retrieves multiple DirectoryObjects from a list of IDs.
 func (c *DirectoryObjectsClient) GetByIds(ctx context.Context, ids []string, types []odata.ShortType) (*[]DirectoryObject, int, error) {
 	var status int
@@ -77,7 +139,7 @@ func (c *DirectoryObjectsClient) GetByIds(ctx context.Context, ids []string, typ
 		},
 	})
 	if err != nil {
-		return nil, status, fmt.Errorf("DirectoryObjects.BaseClient.Post(): %v", err)
+		return nil, status, fmt.Errorf("DirectoryObjects.BaseClient.Post(): %w", err)
 	}
 
 	defer resp.Body.Close()
@@ -89,7 +151,7 @@ func (c *DirectoryObjectsClient) GetByIds(ctx context.Context, ids []string, typ
 	var data struct {
 		Objects []DirectoryObject `json:"value"`
 	}
-	if err := json.Unmarshal(respBody, &data); err != nil {
+	if err := c.BaseClient.decode(respBody, &data); err != nil {
 		return nil, status, fmt.Errorf("json.Unmarshal(): %v", err)
 	}
 
@@ -107,7 +169,7 @@ func (c *DirectoryObjectsClient) Delete(ctx context.Context, id string) (int, er
 		},
 	})
 	if err != nil {
-		return status, fmt.Errorf("DirectoryObjects.BaseClient.Get(): %v", err)
+		return status, fmt.Errorf("DirectoryObjects.BaseClient.Get(): %w", err)
 	}
 
 	return status, nil
@@ -137,7 +199,7 @@ func (c *DirectoryObjectsClient) GetMemberGroups(ctx context.Context, id string,
 		},
 	})
 	if err != nil {
-		return nil, status, fmt.Errorf("DirectoryObjectsClient.BaseClient.Post(): %v", err)
+		return nil, status, fmt.Errorf("DirectoryObjectsClient.BaseClient.Post(): %w", err)
 	}
 
 	defer resp.Body.Close()
@@ -149,7 +211,7 @@ func (c *DirectoryObjectsClient) GetMemberGroups(ctx context.Context, id string,
 	var data struct {
 		IDs []string `json:"value"`
 	}
-	if err := json.Unmarshal(respBody, &data); err != nil {
+	if err := c.BaseClient.decode(respBody, &data); err != nil {
 		return nil, status, fmt.Errorf("json.Unmarshal(): %v", err)
 	}
 
@@ -185,7 +247,7 @@ func (c *DirectoryObjectsClient) GetMemberObjects(ctx context.Context, id string
 		},
 	})
 	if err != nil {
-		return nil, status, fmt.Errorf("DirectoryObjectsClient.BaseClient.Post(): %v", err)
+		return nil, status, fmt.Errorf("DirectoryObjectsClient.BaseClient.Post(): %w", err)
 	}
 
 	defer resp.Body.Close()
@@ -197,7 +259,7 @@ func (c *DirectoryObjectsClient) GetMemberObjects(ctx context.Context, id string
 	var data struct {
 		IDs []string `json:"value"`
 	}
-	if err := json.Unmarshal(respBody, &data); err != nil {
+	if err := c.BaseClient.decode(respBody, &data); err != nil {
 		return nil, status, fmt.Errorf("json.Unmarshal(): %v", err)
 	}
 
@@ -1,10 +1,16 @@
 package msgraph_test
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"net/http"
+	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/manicminer/hamilton/auth"
+	"github.com/manicminer/hamilton/environments"
 	"github.com/manicminer/hamilton/internal/test"
 	"github.com/manicminer/hamilton/internal/utils"
 	"github.com/manicminer/hamilton/msgraph"
@@ -188,3 +194,40 @@ func testDirectoryObjectsClient_Delete(t *testing.T, c DirectoryObjectsClientTes
 		t.Fatalf("DirectoryObjectsClient.Delete(): invalid status: %d", status)
 	}
 }
+
+func TestDirectoryObjectsClient_WaitForObject(t *testing.T) {
+	var requests int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Path != "/v1.0/00000000-0000-0000-0000-000000000000/directoryObjects/11111111-1111-1111-1111-111111111111" || requests < 3 {
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprint(w, `{"error":{"code":"Request_ResourceNotFound","message":"Resource '11111111-1111-1111-1111-111111111111' does not exist or one of its queried reference-property objects are not present."}}`)
+			return
+		}
+		fmt.Fprint(w, `{"@odata.type":"#microsoft.graph.group","id":"11111111-1111-1111-1111-111111111111"}`)
+	}))
+	defer server.Close()
+
+	c := msgraph.NewDirectoryObjectsClient("00000000-0000-0000-0000-000000000000")
+	c.BaseClient.Endpoint = environments.ApiEndpoint(server.URL)
+	if err := c.BaseClient.WithHTTPRetryPolicy(auth.RetryPolicy{BaseDelay: time.Millisecond, MaxDelay: 5 * time.Millisecond}); err != nil {
+		t.Fatalf("Client.WithHTTPRetryPolicy(): %v", err)
+	}
+
+	obj, _, err := c.WaitForObject(context.Background(), "11111111-1111-1111-1111-111111111111", time.Second)
+	if err != nil {
+		t.Fatalf("DirectoryObjectsClient.WaitForObject(): %v", err)
+	}
+	if obj == nil || obj.ID == nil || *obj.ID != "11111111-1111-1111-1111-111111111111" {
+		t.Fatalf("DirectoryObjectsClient.WaitForObject(): unexpected object: %#v", obj)
+	}
+	if requests != 3 {
+		t.Fatalf("DirectoryObjectsClient.WaitForObject(): expected 3 requests, got %d", requests)
+	}
+
+	_, _, err = c.WaitForObject(context.Background(), "22222222-2222-2222-2222-222222222222", 50*time.Millisecond)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("DirectoryObjectsClient.WaitForObject(): expected timeout error, got: %v", err)
+	}
+}
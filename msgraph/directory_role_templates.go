@@ -2,10 +2,10 @@ package msgraph
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
+	"strings"
 )
 
 // DirectoryRoleTemplatesClient performs operations on DirectoryRoleTemplates.
@@ -30,7 +30,7 @@ func (c *DirectoryRoleTemplatesClient) List(ctx context.Context) (*[]DirectoryRo
 		},
 	})
 	if err != nil {
-		return nil, status, fmt.Errorf("DirectoryRoleTemplatesClient.BaseClient.Get(): %v", err)
+		return nil, status, fmt.Errorf("DirectoryRoleTemplatesClient.BaseClient.Get(): %w", err)
 	}
 
 	defer resp.Body.Close()
@@ -42,7 +42,7 @@ func (c *DirectoryRoleTemplatesClient) List(ctx context.Context) (*[]DirectoryRo
 	var data struct {
 		DirectoryRoleTemplates []DirectoryRoleTemplate `json:"value"`
 	}
-	if err := json.Unmarshal(respBody, &data); err != nil {
+	if err := c.BaseClient.decode(respBody, &data); err != nil {
 		return nil, status, fmt.Errorf("json.Unmarshal(): %v", err)
 	}
 
@@ -60,7 +60,7 @@ func (c *DirectoryRoleTemplatesClient) Get(ctx context.Context, id string) (*Dir
 		},
 	})
 	if err != nil {
-		return nil, status, fmt.Errorf("DirectoryRoleTemplatesClient.BaseClient.Get(): %v", err)
+		return nil, status, fmt.Errorf("DirectoryRoleTemplatesClient.BaseClient.Get(): %w", err)
 	}
 
 	defer resp.Body.Close()
@@ -70,9 +70,27 @@ func (c *DirectoryRoleTemplatesClient) Get(ctx context.Context, id string) (*Dir
 	}
 
 	var dirRoleTemplate DirectoryRoleTemplate
-	if err := json.Unmarshal(respBody, &dirRoleTemplate); err != nil {
+	if err := c.BaseClient.decode(respBody, &dirRoleTemplate); err != nil {
 		return nil, status, fmt.Errorf("json.Unmarshal(): %v", err)
 	}
 
 	return &dirRoleTemplate, status, nil
 }
+
+// GetByDisplayName retrieves the DirectoryRoleTemplate with the specified display name, e.g. "Global Administrator",
+// so that its ID can be used to activate the role. Display names are matched case-insensitively. An error is returned
+// when no template has the display name.
+func (c *DirectoryRoleTemplatesClient) GetByDisplayName(ctx context.Context, displayName string) (*DirectoryRoleTemplate, int, error) {
+	templates, status, err := c.List(ctx)
+	if err != nil {
+		return nil, status, err
+	}
+
+	for _, template := range *templates {
+		if template.DisplayName != nil && strings.EqualFold(*template.DisplayName, displayName) {
+			return &template, status, nil
+		}
+	}
+
+	return nil, status, fmt.Errorf("DirectoryRoleTemplatesClient.GetByDisplayName(): no directory role template found with display name %q", displayName)
+}
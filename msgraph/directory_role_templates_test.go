@@ -1,7 +1,6 @@
 package msgraph_test
 
 import (
-	"strings"
 	"testing"
 
 	"github.com/manicminer/hamilton/auth"
@@ -39,13 +38,8 @@ func TestDirectoryRoleTemplatesClient(t *testing.T) {
 
 	// activate a directory role in the tenant using role template id if not already activated
 	// https://docs.microsoft.com/en-us/azure/active-directory/roles/permissions-reference
-	var globalReaderRoleId string
-	for _, template := range *directoryRoleTemplates {
-		if strings.EqualFold(*template.DisplayName, "Global Reader") {
-			globalReaderRoleId = *template.ID
-		}
-	}
-	testDirectoryRolesClient_Activate(t, dirRolesClient, globalReaderRoleId)
+	globalReaderRoleTemplate := testDirectoryRoleTemplatesClient_GetByDisplayName(t, dirRoleTemplatesClient, "global reader")
+	testDirectoryRolesClient_Activate(t, dirRolesClient, *globalReaderRoleTemplate.ID)
 }
 
 func testDirectoryRoleTemplatesClient_List(t *testing.T, c DirectoryRoleTemplatesClientTest) (directoryRoleTemplates *[]msgraph.DirectoryRoleTemplate) {
@@ -73,6 +67,17 @@ func testDirectoryRoleTemplatesClient_Get(t *testing.T, c DirectoryRoleTemplates
 	return
 }
 
+func testDirectoryRoleTemplatesClient_GetByDisplayName(t *testing.T, c DirectoryRoleTemplatesClientTest, displayName string) (directoryRoleTemplate *msgraph.DirectoryRoleTemplate) {
+	directoryRoleTemplate, _, err := c.client.GetByDisplayName(c.connection.Context, displayName)
+	if err != nil {
+		t.Fatalf("DirectoryRoleTemplatesClient.GetByDisplayName(): %v", err)
+	}
+	if directoryRoleTemplate == nil || directoryRoleTemplate.ID == nil {
+		t.Fatal("DirectoryRoleTemplatesClient.GetByDisplayName(): directoryRoleTemplate.ID was nil")
+	}
+	return
+}
+
 func testDirectoryRolesClient_Activate(t *testing.T, c DirectoryRolesClientTest, roleTemplateId string) (directoryRole *msgraph.DirectoryRole) {
 	// list all activated directory roles in the tenant
 	directoryRoles, _, err := c.client.List(c.connection.Context)
@@ -33,7 +33,7 @@ func (c *DirectoryRolesClient) List(ctx context.Context) (*[]DirectoryRole, int,
 		},
 	})
 	if err != nil {
-		return nil, status, fmt.Errorf("DirectoryRolesClient.BaseClient.Get(): %v", err)
+		return nil, status, fmt.Errorf("DirectoryRolesClient.BaseClient.Get(): %w", err)
 	}
 
 	defer resp.Body.Close()
@@ -45,7 +45,7 @@ func (c *DirectoryRolesClient) List(ctx context.Context) (*[]DirectoryRole, int,
 	var data struct {
 		DirectoryRoles []DirectoryRole `json:"value"`
 	}
-	if err := json.Unmarshal(respBody, &data); err != nil {
+	if err := c.BaseClient.decode(respBody, &data); err != nil {
 		return nil, status, fmt.Errorf("json.Unmarshal(): %v", err)
 	}
 
@@ -62,7 +62,7 @@ func (c *DirectoryRolesClient) Get(ctx context.Context, id string) (*DirectoryRo
 		},
 	})
 	if err != nil {
-		return nil, status, fmt.Errorf("DirectoryRolesClient.BaseClient.Get(): %v", err)
+		return nil, status, fmt.Errorf("DirectoryRolesClient.BaseClient.Get(): %w", err)
 	}
 
 	defer resp.Body.Close()
@@ -72,7 +72,7 @@ func (c *DirectoryRolesClient) Get(ctx context.Context, id string) (*DirectoryRo
 	}
 
 	var dirRole DirectoryRole
-	if err := json.Unmarshal(respBody, &dirRole); err != nil {
+	if err := c.BaseClient.decode(respBody, &dirRole); err != nil {
 		return nil, status, fmt.Errorf("json.Unmarshal(): %v", err)
 	}
 
@@ -91,7 +91,7 @@ func (c *DirectoryRolesClient) ListMembers(ctx context.Context, id string) (*[]s
 		},
 	})
 	if err != nil {
-		return nil, status, fmt.Errorf("DirectoryRolesClient.BaseClient.Get(): %v", err)
+		return nil, status, fmt.Errorf("DirectoryRolesClient.BaseClient.Get(): %w", err)
 	}
 
 	defer resp.Body.Close()
@@ -106,7 +106,7 @@ func (c *DirectoryRolesClient) ListMembers(ctx context.Context, id string) (*[]s
 			Id   string `json:"id"`
 		} `json:"value"`
 	}
-	if err := json.Unmarshal(respBody, &data); err != nil {
+	if err := c.BaseClient.decode(respBody, &data); err != nil {
 		return nil, status, fmt.Errorf("json.Unmarshal(): %v", err)
 	}
 
@@ -158,7 +158,7 @@ func (c *DirectoryRolesClient) AddMembers(ctx context.Context, directoryRole *Di
 			},
 		})
 		if err != nil {
-			return status, fmt.Errorf("DirectoryRolesClient.BaseClient.Post(): %v", err)
+			return status, fmt.Errorf("DirectoryRolesClient.BaseClient.Post(): %w", err)
 		}
 	}
 
@@ -193,7 +193,7 @@ func (c *DirectoryRolesClient) RemoveMembers(ctx context.Context, directoryRoleI
 			},
 		})
 		if err != nil {
-			return status, fmt.Errorf("DirectoryRolesClient.BaseClient.Delete(): %v", err)
+			return status, fmt.Errorf("DirectoryRolesClient.BaseClient.Delete(): %w", err)
 		}
 	}
 
@@ -213,7 +213,7 @@ func (c *DirectoryRolesClient) GetMember(ctx context.Context, directoryRoleId, m
 		},
 	})
 	if err != nil {
-		return nil, status, fmt.Errorf("DirectoryRolesClient.BaseClient.Get(): %v", err)
+		return nil, status, fmt.Errorf("DirectoryRolesClient.BaseClient.Get(): %w", err)
 	}
 
 	defer resp.Body.Close()
@@ -228,7 +228,7 @@ func (c *DirectoryRolesClient) GetMember(ctx context.Context, directoryRoleId, m
 		Id      string `json:"id"`
 		Url     string `json:"url"`
 	}
-	if err := json.Unmarshal(respBody, &data); err != nil {
+	if err := c.BaseClient.decode(respBody, &data); err != nil {
 		return nil, status, fmt.Errorf("json.Unmarshal(): %v", err)
 	}
 
@@ -268,7 +268,7 @@ func (c *DirectoryRolesClient) Activate(ctx context.Context, roleTemplateID stri
 		},
 	})
 	if err != nil {
-		return nil, status, fmt.Errorf("DirectoryRolesClient.BaseClient.Post(): %v", err)
+		return nil, status, fmt.Errorf("DirectoryRolesClient.BaseClient.Post(): %w", err)
 	}
 
 	defer resp.Body.Close()
@@ -278,7 +278,7 @@ func (c *DirectoryRolesClient) Activate(ctx context.Context, roleTemplateID stri
 	}
 
 	var newDirRole DirectoryRole
-	if err := json.Unmarshal(respBody, &newDirRole); err != nil {
+	if err := c.BaseClient.decode(respBody, &newDirRole); err != nil {
 		return nil, status, fmt.Errorf("json.Unmarshal(): %v", err)
 	}
 
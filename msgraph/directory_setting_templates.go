@@ -0,0 +1,83 @@
+package msgraph
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/manicminer/hamilton/odata"
+)
+
+// DirectorySettingTemplatesClient performs operations on DirectorySettingTemplates.
+type DirectorySettingTemplatesClient struct {
+	BaseClient Client
+}
+
+// NewDirectorySettingTemplatesClient returns a new DirectorySettingTemplatesClient
+func NewDirectorySettingTemplatesClient(tenantId string) *DirectorySettingTemplatesClient {
+	return &DirectorySettingTemplatesClient{
+		BaseClient: NewClient(Version10, tenantId),
+	}
+}
+
+// List returns a list of DirectorySettingTemplates, optionally queried using OData.
+func (c *DirectorySettingTemplatesClient) List(ctx context.Context, query odata.Query) (*[]DirectorySettingTemplate, int, error) {
+	resp, status, _, err := c.BaseClient.Get(ctx, GetHttpRequestInput{
+		ConsistencyLevel: query.ConsistencyLevel,
+		DisablePaging:    query.Top > 0,
+		ValidStatusCodes: []int{http.StatusOK},
+		Uri: Uri{
+			Entity:      "/groupSettingTemplates",
+			Params:      query.Values(),
+			HasTenantId: true,
+		},
+	})
+	if err != nil {
+		return nil, status, fmt.Errorf("DirectorySettingTemplatesClient.BaseClient.Get(): %w", err)
+	}
+
+	defer resp.Body.Close()
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, status, fmt.Errorf("io.ReadAll(): %v", err)
+	}
+
+	var data struct {
+		DirectorySettingTemplates []DirectorySettingTemplate `json:"value"`
+	}
+	if err := c.BaseClient.decode(respBody, &data); err != nil {
+		return nil, status, fmt.Errorf("json.Unmarshal(): %v", err)
+	}
+
+	return &data.DirectorySettingTemplates, status, nil
+}
+
+// Get retrieves a DirectorySettingTemplate.
+func (c *DirectorySettingTemplatesClient) Get(ctx context.Context, id string, query odata.Query) (*DirectorySettingTemplate, int, error) {
+	resp, status, _, err := c.BaseClient.Get(ctx, GetHttpRequestInput{
+		ConsistencyLevel: query.ConsistencyLevel,
+		ValidStatusCodes: []int{http.StatusOK},
+		Uri: Uri{
+			Entity:      fmt.Sprintf("/groupSettingTemplates/%s", id),
+			Params:      query.Values(),
+			HasTenantId: true,
+		},
+	})
+	if err != nil {
+		return nil, status, fmt.Errorf("DirectorySettingTemplatesClient.BaseClient.Get(): %w", err)
+	}
+
+	defer resp.Body.Close()
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, status, fmt.Errorf("io.ReadAll(): %v", err)
+	}
+
+	var template DirectorySettingTemplate
+	if err := c.BaseClient.decode(respBody, &template); err != nil {
+		return nil, status, fmt.Errorf("json.Unmarshal(): %v", err)
+	}
+
+	return &template, status, nil
+}
@@ -2,7 +2,6 @@ package msgraph
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
@@ -26,6 +25,7 @@ func NewDomainsClient(tenantId string) *DomainsClient {
 func (c *DomainsClient) List(ctx context.Context, query odata.Query) (*[]Domain, int, error) {
 	var status int
 	resp, status, _, err := c.BaseClient.Get(ctx, GetHttpRequestInput{
+		ConsistencyLevel: query.ConsistencyLevel,
 		ValidStatusCodes: []int{http.StatusOK},
 		Uri: Uri{
 			Entity:      "/domains",
@@ -34,7 +34,7 @@ func (c *DomainsClient) List(ctx context.Context, query odata.Query) (*[]Domain,
 		},
 	})
 	if err != nil {
-		return nil, status, fmt.Errorf("DomainsClient.BaseClient.Get(): %v", err)
+		return nil, status, fmt.Errorf("DomainsClient.BaseClient.Get(): %w", err)
 	}
 
 	defer resp.Body.Close()
@@ -46,7 +46,7 @@ func (c *DomainsClient) List(ctx context.Context, query odata.Query) (*[]Domain,
 	var data struct {
 		Domains []Domain `json:"value"`
 	}
-	if err := json.Unmarshal(respBody, &data); err != nil {
+	if err := c.BaseClient.decode(respBody, &data); err != nil {
 		return nil, status, fmt.Errorf("json.Unmarshal(): %v", err)
 	}
 
@@ -59,6 +59,7 @@ func (c *DomainsClient) Get(ctx context.Context, id string, query odata.Query) (
 
 	resp, status, _, err := c.BaseClient.Get(ctx, GetHttpRequestInput{
 		ConsistencyFailureFunc: RetryOn404ConsistencyFailureFunc,
+		ConsistencyLevel:       query.ConsistencyLevel,
 		ValidStatusCodes:       []int{http.StatusOK},
 		Uri: Uri{
 			Entity:      fmt.Sprintf("/domains/%s", id),
@@ -67,7 +68,7 @@ func (c *DomainsClient) Get(ctx context.Context, id string, query odata.Query) (
 		},
 	})
 	if err != nil {
-		return nil, status, fmt.Errorf("DomainsClient.BaseClient.Get(): %v", err)
+		return nil, status, fmt.Errorf("DomainsClient.BaseClient.Get(): %w", err)
 	}
 
 	defer resp.Body.Close()
@@ -77,7 +78,7 @@ func (c *DomainsClient) Get(ctx context.Context, id string, query odata.Query) (
 	}
 
 	var domain Domain
-	if err := json.Unmarshal(respBody, &domain); err != nil {
+	if err := c.BaseClient.decode(respBody, &domain); err != nil {
 		return nil, status, fmt.Errorf("json.Unmarshal(): %v", err)
 	}
 
@@ -0,0 +1,36 @@
+package msgraph
+
+import (
+	"errors"
+	"io"
+	"log"
+	"net/http"
+)
+
+// ErrDryRun is returned in place of a response for requests which were not sent because DryRun is enabled for the
+// Client.
+var ErrDryRun = errors.New("request not sent in dry run mode")
+
+// dryRun logs a request which would modify data, instead of sending it
+func (c Client) dryRun(req *http.Request) (*http.Response, int, error) {
+	var body []byte
+	if req.Body != nil {
+		var err error
+		if body, err = io.ReadAll(req.Body); err != nil {
+			return nil, 0, err
+		}
+		req.Body.Close()
+	}
+
+	logger := c.DryRunLogger
+	if logger == nil {
+		logger = log.Default()
+	}
+	if len(body) > 0 {
+		logger.Printf("[DRY RUN] %s %s %s", req.Method, req.URL, body)
+	} else {
+		logger.Printf("[DRY RUN] %s %s", req.Method, req.URL)
+	}
+
+	return nil, 0, ErrDryRun
+}
@@ -0,0 +1,451 @@
+package msgraph
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/manicminer/hamilton/odata"
+)
+
+// EducationClient performs operations on the schools and classes of an education tenant, and their members.
+//
+// This client requires the EduRoster.ReadWrite.All permission to manage schools, classes and their members, and the
+// EduRoster.Read.All or EduRoster.ReadBasic.All permission to read them.
+type EducationClient struct {
+	BaseClient Client
+}
+
+// NewEducationClient returns a new EducationClient.
+func NewEducationClient(tenantId string) *EducationClient {
+	return &EducationClient{
+		BaseClient: NewClient(Version10, tenantId),
+	}
+}
+
+// ListSchools returns a list of EducationSchools, optionally queried using OData.
+func (c *EducationClient) ListSchools(ctx context.Context, query odata.Query) (*[]EducationSchool, int, error) {
+	resp, status, _, err := c.BaseClient.Get(ctx, GetHttpRequestInput{
+		ConsistencyLevel: query.ConsistencyLevel,
+		DisablePaging:    query.Top > 0,
+		ValidStatusCodes: []int{http.StatusOK},
+		Uri: Uri{
+			Entity:      "/education/schools",
+			Params:      query.Values(),
+			HasTenantId: true,
+		},
+	})
+	if err != nil {
+		return nil, status, fmt.Errorf("EducationClient.BaseClient.Get(): %w", err)
+	}
+
+	defer resp.Body.Close()
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, status, fmt.Errorf("io.ReadAll(): %v", err)
+	}
+
+	var data struct {
+		Schools []EducationSchool `json:"value"`
+	}
+	if err := c.BaseClient.decode(respBody, &data); err != nil {
+		return nil, status, fmt.Errorf("json.Unmarshal(): %v", err)
+	}
+
+	return &data.Schools, status, nil
+}
+
+// CreateSchool creates a new EducationSchool.
+func (c *EducationClient) CreateSchool(ctx context.Context, school EducationSchool) (*EducationSchool, int, error) {
+	var status int
+
+	body, err := json.Marshal(school)
+	if err != nil {
+		return nil, status, fmt.Errorf("json.Marshal(): %v", err)
+	}
+
+	resp, status, _, err := c.BaseClient.Post(ctx, PostHttpRequestInput{
+		Body:             body,
+		ValidStatusCodes: []int{http.StatusCreated},
+		Uri: Uri{
+			Entity:      "/education/schools",
+			HasTenantId: true,
+		},
+	})
+	if err != nil {
+		return nil, status, fmt.Errorf("EducationClient.BaseClient.Post(): %w", err)
+	}
+
+	defer resp.Body.Close()
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, status, fmt.Errorf("io.ReadAll(): %v", err)
+	}
+
+	var newSchool EducationSchool
+	if err := c.BaseClient.decode(respBody, &newSchool); err != nil {
+		return nil, status, fmt.Errorf("json.Unmarshal(): %v", err)
+	}
+
+	return &newSchool, status, nil
+}
+
+// GetSchool retrieves an EducationSchool.
+func (c *EducationClient) GetSchool(ctx context.Context, id string, query odata.Query) (*EducationSchool, int, error) {
+	resp, status, _, err := c.BaseClient.Get(ctx, GetHttpRequestInput{
+		ConsistencyFailureFunc: RetryOn404ConsistencyFailureFunc,
+		ConsistencyLevel:       query.ConsistencyLevel,
+		ValidStatusCodes:       []int{http.StatusOK},
+		Uri: Uri{
+			Entity:      fmt.Sprintf("/education/schools/%s", id),
+			Params:      query.Values(),
+			HasTenantId: true,
+		},
+	})
+	if err != nil {
+		return nil, status, fmt.Errorf("EducationClient.BaseClient.Get(): %w", err)
+	}
+
+	defer resp.Body.Close()
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, status, fmt.Errorf("io.ReadAll(): %v", err)
+	}
+
+	var school EducationSchool
+	if err := c.BaseClient.decode(respBody, &school); err != nil {
+		return nil, status, fmt.Errorf("json.Unmarshal(): %v", err)
+	}
+
+	return &school, status, nil
+}
+
+// UpdateSchool amends an existing EducationSchool.
+func (c *EducationClient) UpdateSchool(ctx context.Context, school EducationSchool) (int, error) {
+	if school.ID == nil {
+		return 0, fmt.Errorf("cannot update school with nil ID")
+	}
+	return c.update(ctx, fmt.Sprintf("/education/schools/%s", *school.ID), school)
+}
+
+// DeleteSchool removes an EducationSchool.
+func (c *EducationClient) DeleteSchool(ctx context.Context, id string) (int, error) {
+	return c.delete(ctx, fmt.Sprintf("/education/schools/%s", id))
+}
+
+// ListSchoolUsers returns a list of the EducationUsers in a school, optionally queried using OData.
+func (c *EducationClient) ListSchoolUsers(ctx context.Context, schoolId string, query odata.Query) (*[]EducationUser, int, error) {
+	return c.listUsers(ctx, fmt.Sprintf("/education/schools/%s/users", schoolId), query)
+}
+
+// AddSchoolUsers adds users to a school. Users who are already in the school are ignored.
+func (c *EducationClient) AddSchoolUsers(ctx context.Context, schoolId string, userIds []string) (int, error) {
+	return c.addUsers(ctx, fmt.Sprintf("/education/schools/%s/users", schoolId), userIds)
+}
+
+// RemoveSchoolUsers removes users from a school.
+func (c *EducationClient) RemoveSchoolUsers(ctx context.Context, schoolId string, userIds []string) (int, error) {
+	return c.removeUsers(ctx, fmt.Sprintf("/education/schools/%s/users", schoolId), userIds)
+}
+
+// ListClasses returns a list of EducationClasses, optionally queried using OData.
+func (c *EducationClient) ListClasses(ctx context.Context, query odata.Query) (*[]EducationClass, int, error) {
+	resp, status, _, err := c.BaseClient.Get(ctx, GetHttpRequestInput{
+		ConsistencyLevel: query.ConsistencyLevel,
+		DisablePaging:    query.Top > 0,
+		ValidStatusCodes: []int{http.StatusOK},
+		Uri: Uri{
+			Entity:      "/education/classes",
+			Params:      query.Values(),
+			HasTenantId: true,
+		},
+	})
+	if err != nil {
+		return nil, status, fmt.Errorf("EducationClient.BaseClient.Get(): %w", err)
+	}
+
+	defer resp.Body.Close()
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, status, fmt.Errorf("io.ReadAll(): %v", err)
+	}
+
+	var data struct {
+		Classes []EducationClass `json:"value"`
+	}
+	if err := c.BaseClient.decode(respBody, &data); err != nil {
+		return nil, status, fmt.Errorf("json.Unmarshal(): %v", err)
+	}
+
+	return &data.Classes, status, nil
+}
+
+// CreateClass creates a new EducationClass. The DisplayName, MailNickname and Description are required.
+func (c *EducationClient) CreateClass(ctx context.Context, class EducationClass) (*EducationClass, int, error) {
+	var status int
+
+	body, err := json.Marshal(class)
+	if err != nil {
+		return nil, status, fmt.Errorf("json.Marshal(): %v", err)
+	}
+
+	resp, status, _, err := c.BaseClient.Post(ctx, PostHttpRequestInput{
+		Body:             body,
+		ValidStatusCodes: []int{http.StatusCreated},
+		Uri: Uri{
+			Entity:      "/education/classes",
+			HasTenantId: true,
+		},
+	})
+	if err != nil {
+		return nil, status, fmt.Errorf("EducationClient.BaseClient.Post(): %w", err)
+	}
+
+	defer resp.Body.Close()
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, status, fmt.Errorf("io.ReadAll(): %v", err)
+	}
+
+	var newClass EducationClass
+	if err := c.BaseClient.decode(respBody, &newClass); err != nil {
+		return nil, status, fmt.Errorf("json.Unmarshal(): %v", err)
+	}
+
+	return &newClass, status, nil
+}
+
+// GetClass retrieves an EducationClass.
+func (c *EducationClient) GetClass(ctx context.Context, id string, query odata.Query) (*EducationClass, int, error) {
+	resp, status, _, err := c.BaseClient.Get(ctx, GetHttpRequestInput{
+		ConsistencyFailureFunc: RetryOn404ConsistencyFailureFunc,
+		ConsistencyLevel:       query.ConsistencyLevel,
+		ValidStatusCodes:       []int{http.StatusOK},
+		Uri: Uri{
+			Entity:      fmt.Sprintf("/education/classes/%s", id),
+			Params:      query.Values(),
+			HasTenantId: true,
+		},
+	})
+	if err != nil {
+		return nil, status, fmt.Errorf("EducationClient.BaseClient.Get(): %w", err)
+	}
+
+	defer resp.Body.Close()
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, status, fmt.Errorf("io.ReadAll(): %v", err)
+	}
+
+	var class EducationClass
+	if err := c.BaseClient.decode(respBody, &class); err != nil {
+		return nil, status, fmt.Errorf("json.Unmarshal(): %v", err)
+	}
+
+	return &class, status, nil
+}
+
+// UpdateClass amends an existing EducationClass.
+func (c *EducationClient) UpdateClass(ctx context.Context, class EducationClass) (int, error) {
+	if class.ID == nil {
+		return 0, fmt.Errorf("cannot update class with nil ID")
+	}
+	return c.update(ctx, fmt.Sprintf("/education/classes/%s", *class.ID), class)
+}
+
+// DeleteClass removes an EducationClass.
+func (c *EducationClient) DeleteClass(ctx context.Context, id string) (int, error) {
+	return c.delete(ctx, fmt.Sprintf("/education/classes/%s", id))
+}
+
+// AddClassToSchool adds an existing class to a school.
+func (c *EducationClient) AddClassToSchool(ctx context.Context, schoolId, classId string) (int, error) {
+	return c.addReference(ctx, fmt.Sprintf("/education/schools/%s/classes", schoolId), fmt.Sprintf("education/classes/%s", classId))
+}
+
+// ListClassMembers returns a list of the students in a class, optionally queried using OData.
+func (c *EducationClient) ListClassMembers(ctx context.Context, classId string, query odata.Query) (*[]EducationUser, int, error) {
+	return c.listUsers(ctx, fmt.Sprintf("/education/classes/%s/members", classId), query)
+}
+
+// AddClassMembers adds students to a class. Students who are already members are ignored.
+func (c *EducationClient) AddClassMembers(ctx context.Context, classId string, userIds []string) (int, error) {
+	return c.addUsers(ctx, fmt.Sprintf("/education/classes/%s/members", classId), userIds)
+}
+
+// RemoveClassMembers removes students from a class.
+func (c *EducationClient) RemoveClassMembers(ctx context.Context, classId string, userIds []string) (int, error) {
+	return c.removeUsers(ctx, fmt.Sprintf("/education/classes/%s/members", classId), userIds)
+}
+
+// ListClassTeachers returns a list of the teachers of a class, optionally queried using OData.
+func (c *EducationClient) ListClassTeachers(ctx context.Context, classId string, query odata.Query) (*[]EducationUser, int, error) {
+	return c.listUsers(ctx, fmt.Sprintf("/education/classes/%s/teachers", classId), query)
+}
+
+// AddClassTeachers adds teachers to a class. Teachers who are already assigned to the class are ignored.
+func (c *EducationClient) AddClassTeachers(ctx context.Context, classId string, userIds []string) (int, error) {
+	return c.addUsers(ctx, fmt.Sprintf("/education/classes/%s/teachers", classId), userIds)
+}
+
+// RemoveClassTeachers removes teachers from a class.
+func (c *EducationClient) RemoveClassTeachers(ctx context.Context, classId string, userIds []string) (int, error) {
+	return c.removeUsers(ctx, fmt.Sprintf("/education/classes/%s/teachers", classId), userIds)
+}
+
+// update amends the school or class at entity
+func (c *EducationClient) update(ctx context.Context, entity string, v interface{}) (int, error) {
+	var status int
+
+	body, err := json.Marshal(v)
+	if err != nil {
+		return status, fmt.Errorf("json.Marshal(): %v", err)
+	}
+
+	_, status, _, err = c.BaseClient.Patch(ctx, PatchHttpRequestInput{
+		Body:                   body,
+		ConsistencyFailureFunc: RetryOn404ConsistencyFailureFunc,
+		ValidStatusCodes:       []int{http.StatusOK, http.StatusNoContent},
+		Uri: Uri{
+			Entity:      entity,
+			HasTenantId: true,
+		},
+	})
+	if err != nil {
+		return status, fmt.Errorf("EducationClient.BaseClient.Patch(): %w", err)
+	}
+
+	return status, nil
+}
+
+// delete removes the school or class at entity
+func (c *EducationClient) delete(ctx context.Context, entity string) (int, error) {
+	_, status, _, err := c.BaseClient.Delete(ctx, DeleteHttpRequestInput{
+		ConsistencyFailureFunc: RetryOn404ConsistencyFailureFunc,
+		ValidStatusCodes:       []int{http.StatusNoContent},
+		Uri: Uri{
+			Entity:      entity,
+			HasTenantId: true,
+		},
+	})
+	if err != nil {
+		return status, fmt.Errorf("EducationClient.BaseClient.Delete(): %w", err)
+	}
+
+	return status, nil
+}
+
+// listUsers returns the users in the collection at entity, retrieving all pages unless query.Top is specified
+func (c *EducationClient) listUsers(ctx context.Context, entity string, query odata.Query) (*[]EducationUser, int, error) {
+	resp, status, _, err := c.BaseClient.Get(ctx, GetHttpRequestInput{
+		ConsistencyFailureFunc: RetryOn404ConsistencyFailureFunc,
+		ConsistencyLevel:       query.ConsistencyLevel,
+		DisablePaging:          query.Top > 0,
+		ValidStatusCodes:       []int{http.StatusOK},
+		Uri: Uri{
+			Entity:      entity,
+			Params:      query.Values(),
+			HasTenantId: true,
+		},
+	})
+	if err != nil {
+		return nil, status, fmt.Errorf("EducationClient.BaseClient.Get(): %w", err)
+	}
+
+	defer resp.Body.Close()
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, status, fmt.Errorf("io.ReadAll(): %v", err)
+	}
+
+	var data struct {
+		Users []EducationUser `json:"value"`
+	}
+	if err := c.BaseClient.decode(respBody, &data); err != nil {
+		return nil, status, fmt.Errorf("json.Unmarshal(): %v", err)
+	}
+
+	return &data.Users, status, nil
+}
+
+// addUsers adds references to the specified users to the collection at entity
+func (c *EducationClient) addUsers(ctx context.Context, entity string, userIds []string) (int, error) {
+	var status int
+
+	if len(userIds) == 0 {
+		return status, fmt.Errorf("no users specified")
+	}
+
+	for _, userId := range userIds {
+		var err error
+		if status, err = c.addReference(ctx, entity, fmt.Sprintf("education/users/%s", userId)); err != nil {
+			return status, err
+		}
+	}
+
+	return status, nil
+}
+
+// addReference adds a reference to the object at path, relative to the API version, to the collection at entity
+func (c *EducationClient) addReference(ctx context.Context, entity, path string) (int, error) {
+	// don't fail if the reference already exists
+	checkReferenceAlreadyExists := func(resp *http.Response, o *odata.OData) bool {
+		if resp.StatusCode == http.StatusBadRequest && o != nil && o.Error != nil {
+			return o.Error.Match(odata.ErrorAddedObjectReferencesAlreadyExist)
+		}
+		return false
+	}
+
+	body, err := json.Marshal(struct {
+		Reference odata.Id `json:"@odata.id"`
+	}{
+		Reference: odata.Id(fmt.Sprintf("%s/%s/%s", c.BaseClient.Endpoint, c.BaseClient.ApiVersion, path)),
+	})
+	if err != nil {
+		return 0, fmt.Errorf("json.Marshal(): %v", err)
+	}
+
+	_, status, _, err := c.BaseClient.Post(ctx, PostHttpRequestInput{
+		Body:                   body,
+		ConsistencyFailureFunc: RetryOn404ConsistencyFailureFunc,
+		ValidStatusCodes:       []int{http.StatusNoContent},
+		ValidStatusFunc:        checkReferenceAlreadyExists,
+		Uri: Uri{
+			Entity:      fmt.Sprintf("%s/$ref", entity),
+			HasTenantId: true,
+		},
+	})
+	if err != nil {
+		return status, fmt.Errorf("EducationClient.BaseClient.Post(): %w", err)
+	}
+
+	return status, nil
+}
+
+// removeUsers removes references to the specified users from the collection at entity
+func (c *EducationClient) removeUsers(ctx context.Context, entity string, userIds []string) (int, error) {
+	var status int
+
+	if len(userIds) == 0 {
+		return status, fmt.Errorf("no users specified")
+	}
+
+	for _, userId := range userIds {
+		var err error
+		_, status, _, err = c.BaseClient.Delete(ctx, DeleteHttpRequestInput{
+			ConsistencyFailureFunc: RetryOn404ConsistencyFailureFunc,
+			ValidStatusCodes:       []int{http.StatusNoContent},
+			Uri: Uri{
+				Entity:      fmt.Sprintf("%s/%s/$ref", entity, userId),
+				HasTenantId: true,
+			},
+		})
+		if err != nil {
+			return status, fmt.Errorf("EducationClient.BaseClient.Delete(): %w", err)
+		}
+	}
+
+	return status, nil
+}
@@ -0,0 +1,155 @@
+package msgraph_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/manicminer/hamilton/environments"
+	"github.com/manicminer/hamilton/internal/utils"
+	"github.com/manicminer/hamilton/msgraph"
+	"github.com/manicminer/hamilton/odata"
+)
+
+func TestEducationClient(t *testing.T) {
+	const basePath = "/v1.0/00000000-0000-0000-0000-000000000000/education"
+	var requests []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]interface{}
+		if r.Method == http.MethodPost || r.Method == http.MethodPatch {
+			if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+				t.Errorf("could not decode request body: %v", err)
+			}
+		}
+		requests = append(requests, fmt.Sprintf("%s %s %v", r.Method, r.URL.Path, body["@odata.id"]))
+
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == basePath+"/schools":
+			w.Header().Set("Content-Type", "application/json")
+			if r.URL.Query().Get("$skiptoken") == "" {
+				fmt.Fprintf(w, `{"@odata.nextLink":"https://graph.microsoft.com/v1.0/00000000-0000-0000-0000-000000000000/education/schools?$skiptoken=page2","value":[{"id":"school1","displayName":"Fabrikam High School","externalSource":"sis"}]}`)
+				return
+			}
+			fmt.Fprint(w, `{"value":[{"id":"school2","displayName":"Fabrikam Middle School","externalSource":"manual"}]}`)
+		case r.Method == http.MethodPost && r.URL.Path == basePath+"/classes":
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusCreated)
+			fmt.Fprintf(w, `{"id":"class1","displayName":%q,"mailNickname":%q,"classCode":%q}`, body["displayName"], body["mailNickname"], body["classCode"])
+		case r.Method == http.MethodGet && r.URL.Path == basePath+"/classes/class1/teachers":
+			w.Header().Set("Content-Type", "application/json")
+			fmt.Fprint(w, `{"value":[{"id":"teacher1","displayName":"Adele Vance","primaryRole":"teacher"}]}`)
+		case r.Method == http.MethodPost && r.URL.Path == basePath+"/classes/class1/members/$ref" && strings.HasSuffix(fmt.Sprint(body["@odata.id"]), "/v1.0/education/users/student2"):
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusBadRequest)
+			fmt.Fprint(w, `{"error":{"code":"Request_BadRequest","message":"One or more added object references already exist for the following modified properties: 'members'."}}`)
+		case r.Method == http.MethodPost || r.Method == http.MethodDelete || r.Method == http.MethodPatch:
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprint(w, `{"error":{"code":"NotFound","message":"not found"}}`)
+		}
+	}))
+	defer server.Close()
+
+	c := msgraph.NewEducationClient("00000000-0000-0000-0000-000000000000")
+	c.BaseClient.Endpoint = environments.ApiEndpoint(server.URL)
+
+	schools, _, err := c.ListSchools(context.Background(), odata.Query{})
+	if err != nil {
+		t.Fatalf("EducationClient.ListSchools(): %v", err)
+	}
+	if schools == nil || len(*schools) != 2 || *(*schools)[1].ExternalSource != msgraph.EducationExternalSourceManual {
+		t.Fatalf("EducationClient.ListSchools(): expected 2 schools across pages, got %v", schools)
+	}
+
+	class, _, err := c.CreateClass(context.Background(), msgraph.EducationClass{
+		DisplayName:  utils.StringPtr("Physics 101"),
+		Description:  utils.StringPtr("Introductory physics"),
+		MailNickname: utils.StringPtr("physics101"),
+		ClassCode:    utils.StringPtr("PHY101"),
+	})
+	if err != nil {
+		t.Fatalf("EducationClient.CreateClass(): %v", err)
+	}
+	if class.ID == nil || *class.ClassCode != "PHY101" {
+		t.Fatalf("EducationClient.CreateClass(): unexpected class %+v", class)
+	}
+
+	requests = nil
+	if _, err := c.AddClassToSchool(context.Background(), "school1", *class.ID); err != nil {
+		t.Fatalf("EducationClient.AddClassToSchool(): %v", err)
+	}
+	if _, err := c.AddClassMembers(context.Background(), *class.ID, []string{"student1", "student2"}); err != nil {
+		t.Fatalf("EducationClient.AddClassMembers(): %v", err)
+	}
+	if _, err := c.AddClassTeachers(context.Background(), *class.ID, []string{"teacher1"}); err != nil {
+		t.Fatalf("EducationClient.AddClassTeachers(): %v", err)
+	}
+	if _, err := c.RemoveClassMembers(context.Background(), *class.ID, []string{"student1"}); err != nil {
+		t.Fatalf("EducationClient.RemoveClassMembers(): %v", err)
+	}
+	if _, err := c.RemoveClassTeachers(context.Background(), *class.ID, []string{"teacher1"}); err != nil {
+		t.Fatalf("EducationClient.RemoveClassTeachers(): %v", err)
+	}
+	if _, err := c.UpdateClass(context.Background(), msgraph.EducationClass{ID: class.ID, Grade: utils.StringPtr("9")}); err != nil {
+		t.Fatalf("EducationClient.UpdateClass(): %v", err)
+	}
+
+	expected := []string{
+		"POST " + basePath + "/schools/school1/classes/$ref " + server.URL + "/v1.0/education/classes/class1",
+		"POST " + basePath + "/classes/class1/members/$ref " + server.URL + "/v1.0/education/users/student1",
+		"POST " + basePath + "/classes/class1/members/$ref " + server.URL + "/v1.0/education/users/student2",
+		"POST " + basePath + "/classes/class1/teachers/$ref " + server.URL + "/v1.0/education/users/teacher1",
+		"DELETE " + basePath + "/classes/class1/members/student1/$ref <nil>",
+		"DELETE " + basePath + "/classes/class1/teachers/teacher1/$ref <nil>",
+		"PATCH " + basePath + "/classes/class1 <nil>",
+	}
+	if !reflect.DeepEqual(requests, expected) {
+		t.Fatalf("EducationClient: unexpected requests:\n%v\nexpected:\n%v", requests, expected)
+	}
+
+	teachers, _, err := c.ListClassTeachers(context.Background(), *class.ID, odata.Query{})
+	if err != nil {
+		t.Fatalf("EducationClient.ListClassTeachers(): %v", err)
+	}
+	if teachers == nil || len(*teachers) != 1 || *(*teachers)[0].PrimaryRole != msgraph.EducationUserRoleTeacher {
+		t.Fatalf("EducationClient.ListClassTeachers(): unexpected teachers %v", teachers)
+	}
+}
+
+func TestEducationClient_ConsistencyLevel(t *testing.T) {
+	var consistencyLevels []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		consistencyLevels = append(consistencyLevels, r.Header.Get("ConsistencyLevel"))
+		w.Header().Set("Content-Type", "application/json")
+		if strings.HasSuffix(r.URL.Path, "/classes") {
+			fmt.Fprint(w, `{"@odata.count":1,"value":[{"id":"class1","displayName":"Physics 101"}]}`)
+			return
+		}
+		fmt.Fprint(w, `{"value":[{"id":"student1","displayName":"Megan Bowen"}]}`)
+	}))
+	defer server.Close()
+
+	c := msgraph.NewEducationClient("00000000-0000-0000-0000-000000000000")
+	c.BaseClient.Endpoint = environments.ApiEndpoint(server.URL)
+
+	query := odata.Query{
+		ConsistencyLevel: odata.ConsistencyLevelEventual,
+		Filter:           "members/$count gt 0",
+	}
+	if _, _, err := c.ListClasses(context.Background(), query); err != nil {
+		t.Fatalf("EducationClient.ListClasses(): %v", err)
+	}
+	if _, _, err := c.ListClassMembers(context.Background(), "class1", odata.Query{ConsistencyLevel: odata.ConsistencyLevelEventual, Count: true}); err != nil {
+		t.Fatalf("EducationClient.ListClassMembers(): %v", err)
+	}
+	if expected := []string{"eventual", "eventual"}; !reflect.DeepEqual(consistencyLevels, expected) {
+		t.Fatalf("EducationClient: expected ConsistencyLevel headers %v, got %v", expected, consistencyLevels)
+	}
+}
@@ -0,0 +1,106 @@
+package msgraph
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"reflect"
+	"strings"
+
+	"github.com/manicminer/hamilton/odata"
+)
+
+// GetInto retrieves the object at path, e.g. `/identityGovernance/lifecycleWorkflows/workflows/{id}`, optionally
+// queried using OData, and decodes it into out, which must be a non-nil pointer. This provides typed access to
+// endpoints, such as those in preview, for which there is no dedicated client. When the response is an OData envelope
+// containing only a `value`, as is returned when retrieving a single property, the value is decoded into out.
+func (c Client) GetInto(ctx context.Context, path string, query odata.Query, out interface{}) (int, error) {
+	if v := reflect.ValueOf(out); v.Kind() != reflect.Ptr || v.IsNil() {
+		return 0, fmt.Errorf("Client.GetInto(): out must be a non-nil pointer, got %T", out)
+	}
+
+	resp, status, _, err := c.Get(ctx, GetHttpRequestInput{
+		ConsistencyFailureFunc: RetryOn404ConsistencyFailureFunc,
+		ConsistencyLevel:       query.ConsistencyLevel,
+		ValidStatusCodes:       []int{http.StatusOK},
+		Uri: Uri{
+			Entity:      path,
+			Params:      query.Values(),
+			HasTenantId: true,
+		},
+	})
+	if err != nil {
+		return status, fmt.Errorf("Client.Get(): %w", err)
+	}
+
+	defer resp.Body.Close()
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return status, fmt.Errorf("io.ReadAll(): %v", err)
+	}
+
+	if value, ok := envelopeValue(respBody); ok {
+		respBody = value
+	}
+	if err := c.decode(respBody, out); err != nil {
+		return status, fmt.Errorf("json.Unmarshal(): %v", err)
+	}
+
+	return status, nil
+}
+
+// GetCollectionInto retrieves the collection at path, e.g. `/identityGovernance/lifecycleWorkflows/workflows`,
+// optionally queried using OData, and appends each item to out, which must be a pointer to a slice, e.g.
+// `*[]MyWorkflow`. All pages are retrieved unless query.Top is specified.
+func (c Client) GetCollectionInto(ctx context.Context, path string, query odata.Query, out interface{}) (int, error) {
+	v := reflect.ValueOf(out)
+	if v.Kind() != reflect.Ptr || v.IsNil() || v.Elem().Kind() != reflect.Slice {
+		return 0, fmt.Errorf("Client.GetCollectionInto(): out must be a non-nil pointer to a slice, got %T", out)
+	}
+	slice := v.Elem()
+	elemType := slice.Type().Elem()
+
+	status, err := c.getEach(ctx, GetHttpRequestInput{
+		ConsistencyLevel: query.ConsistencyLevel,
+		DisablePaging:    query.Top > 0,
+		ValidStatusCodes: []int{http.StatusOK},
+		Uri: Uri{
+			Entity:      path,
+			Params:      query.Values(),
+			HasTenantId: true,
+		},
+	}, func(item json.RawMessage) error {
+		elem := reflect.New(elemType)
+		if err := c.decode(item, elem.Interface()); err != nil {
+			return fmt.Errorf("json.Unmarshal(): %v", err)
+		}
+		slice.Set(reflect.Append(slice, elem.Elem()))
+		return nil
+	})
+	if err != nil {
+		return status, fmt.Errorf("Client.Get(): %w", err)
+	}
+
+	return status, nil
+}
+
+// envelopeValue returns the `value` of an OData envelope, when data is an object containing only a value and
+// annotations such as @odata.context
+func envelopeValue(data []byte) (json.RawMessage, bool) {
+	var envelope map[string]json.RawMessage
+	if err := json.Unmarshal(data, &envelope); err != nil {
+		return nil, false
+	}
+	value, ok := envelope["value"]
+	if !ok {
+		return nil, false
+	}
+	for k := range envelope {
+		if k != "value" && !strings.HasPrefix(k, "@") {
+			return nil, false
+		}
+	}
+	return value, true
+}
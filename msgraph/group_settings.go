@@ -0,0 +1,225 @@
+package msgraph
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/manicminer/hamilton/odata"
+)
+
+// GroupSettingsClient performs operations on tenant-wide and group-specific DirectorySettings.
+type GroupSettingsClient struct {
+	BaseClient Client
+}
+
+// NewGroupSettingsClient returns a new GroupSettingsClient.
+func NewGroupSettingsClient(tenantId string) *GroupSettingsClient {
+	return &GroupSettingsClient{
+		BaseClient: NewClient(Version10, tenantId),
+	}
+}
+
+// List returns a list of tenant-wide DirectorySettings, optionally queried using OData.
+func (c *GroupSettingsClient) List(ctx context.Context, query odata.Query) (*[]DirectorySetting, int, error) {
+	return c.list(ctx, "/groupSettings", query)
+}
+
+// ListForGroup returns a list of DirectorySettings for the specified Group, optionally queried using OData.
+// groupId is the object ID of the group.
+func (c *GroupSettingsClient) ListForGroup(ctx context.Context, groupId string, query odata.Query) (*[]DirectorySetting, int, error) {
+	return c.list(ctx, fmt.Sprintf("/groups/%s/settings", groupId), query)
+}
+
+func (c *GroupSettingsClient) list(ctx context.Context, entity string, query odata.Query) (*[]DirectorySetting, int, error) {
+	resp, status, _, err := c.BaseClient.Get(ctx, GetHttpRequestInput{
+		ConsistencyLevel: query.ConsistencyLevel,
+		DisablePaging:    query.Top > 0,
+		ValidStatusCodes: []int{http.StatusOK},
+		Uri: Uri{
+			Entity:      entity,
+			Params:      query.Values(),
+			HasTenantId: true,
+		},
+	})
+	if err != nil {
+		return nil, status, fmt.Errorf("GroupSettingsClient.BaseClient.Get(): %w", err)
+	}
+
+	defer resp.Body.Close()
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, status, fmt.Errorf("io.ReadAll(): %v", err)
+	}
+
+	var data struct {
+		DirectorySettings []DirectorySetting `json:"value"`
+	}
+	if err := c.BaseClient.decode(respBody, &data); err != nil {
+		return nil, status, fmt.Errorf("json.Unmarshal(): %v", err)
+	}
+
+	return &data.DirectorySettings, status, nil
+}
+
+// Get retrieves a tenant-wide DirectorySetting.
+func (c *GroupSettingsClient) Get(ctx context.Context, id string, query odata.Query) (*DirectorySetting, int, error) {
+	return c.get(ctx, fmt.Sprintf("/groupSettings/%s", id), query)
+}
+
+// GetForGroup retrieves a DirectorySetting for the specified Group.
+// groupId is the object ID of the group.
+func (c *GroupSettingsClient) GetForGroup(ctx context.Context, groupId, id string, query odata.Query) (*DirectorySetting, int, error) {
+	return c.get(ctx, fmt.Sprintf("/groups/%s/settings/%s", groupId, id), query)
+}
+
+func (c *GroupSettingsClient) get(ctx context.Context, entity string, query odata.Query) (*DirectorySetting, int, error) {
+	resp, status, _, err := c.BaseClient.Get(ctx, GetHttpRequestInput{
+		ConsistencyFailureFunc: RetryOn404ConsistencyFailureFunc,
+		ConsistencyLevel:       query.ConsistencyLevel,
+		ValidStatusCodes:       []int{http.StatusOK},
+		Uri: Uri{
+			Entity:      entity,
+			Params:      query.Values(),
+			HasTenantId: true,
+		},
+	})
+	if err != nil {
+		return nil, status, fmt.Errorf("GroupSettingsClient.BaseClient.Get(): %w", err)
+	}
+
+	defer resp.Body.Close()
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, status, fmt.Errorf("io.ReadAll(): %v", err)
+	}
+
+	var setting DirectorySetting
+	if err := c.BaseClient.decode(respBody, &setting); err != nil {
+		return nil, status, fmt.Errorf("json.Unmarshal(): %v", err)
+	}
+
+	return &setting, status, nil
+}
+
+// Create creates a new tenant-wide DirectorySetting. The TemplateId field must be set.
+func (c *GroupSettingsClient) Create(ctx context.Context, setting DirectorySetting) (*DirectorySetting, int, error) {
+	return c.create(ctx, "/groupSettings", setting)
+}
+
+// CreateForGroup creates a new DirectorySetting for the specified Group. The TemplateId field must be set.
+// groupId is the object ID of the group.
+func (c *GroupSettingsClient) CreateForGroup(ctx context.Context, groupId string, setting DirectorySetting) (*DirectorySetting, int, error) {
+	return c.create(ctx, fmt.Sprintf("/groups/%s/settings", groupId), setting)
+}
+
+func (c *GroupSettingsClient) create(ctx context.Context, entity string, setting DirectorySetting) (*DirectorySetting, int, error) {
+	var status int
+
+	if setting.TemplateId == nil {
+		return nil, status, errors.New("GroupSettingsClient.Create(): cannot create setting with nil TemplateId")
+	}
+
+	body, err := json.Marshal(setting)
+	if err != nil {
+		return nil, status, fmt.Errorf("json.Marshal(): %v", err)
+	}
+
+	resp, status, _, err := c.BaseClient.Post(ctx, PostHttpRequestInput{
+		Body:                   body,
+		ConsistencyFailureFunc: RetryOn404ConsistencyFailureFunc,
+		ValidStatusCodes:       []int{http.StatusCreated},
+		Uri: Uri{
+			Entity:      entity,
+			HasTenantId: true,
+		},
+	})
+	if err != nil {
+		return nil, status, fmt.Errorf("GroupSettingsClient.BaseClient.Post(): %w", err)
+	}
+
+	defer resp.Body.Close()
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, status, fmt.Errorf("io.ReadAll(): %v", err)
+	}
+
+	var newSetting DirectorySetting
+	if err := c.BaseClient.decode(respBody, &newSetting); err != nil {
+		return nil, status, fmt.Errorf("json.Unmarshal(): %v", err)
+	}
+
+	return &newSetting, status, nil
+}
+
+// Update amends the values of an existing tenant-wide DirectorySetting.
+func (c *GroupSettingsClient) Update(ctx context.Context, setting DirectorySetting) (int, error) {
+	if setting.ID == nil {
+		return 0, errors.New("GroupSettingsClient.Update(): cannot update setting with nil ID")
+	}
+	return c.update(ctx, fmt.Sprintf("/groupSettings/%s", *setting.ID), setting)
+}
+
+// UpdateForGroup amends the values of an existing DirectorySetting for the specified Group.
+// groupId is the object ID of the group.
+func (c *GroupSettingsClient) UpdateForGroup(ctx context.Context, groupId string, setting DirectorySetting) (int, error) {
+	if setting.ID == nil {
+		return 0, errors.New("GroupSettingsClient.UpdateForGroup(): cannot update setting with nil ID")
+	}
+	return c.update(ctx, fmt.Sprintf("/groups/%s/settings/%s", groupId, *setting.ID), setting)
+}
+
+func (c *GroupSettingsClient) update(ctx context.Context, entity string, setting DirectorySetting) (int, error) {
+	var status int
+
+	// Only the values can be updated
+	body, err := json.Marshal(DirectorySetting{Values: setting.Values})
+	if err != nil {
+		return status, fmt.Errorf("json.Marshal(): %v", err)
+	}
+
+	_, status, _, err = c.BaseClient.Patch(ctx, PatchHttpRequestInput{
+		Body:                   body,
+		ConsistencyFailureFunc: RetryOn404ConsistencyFailureFunc,
+		ValidStatusCodes:       []int{http.StatusNoContent},
+		Uri: Uri{
+			Entity:      entity,
+			HasTenantId: true,
+		},
+	})
+	if err != nil {
+		return status, fmt.Errorf("GroupSettingsClient.BaseClient.Patch(): %w", err)
+	}
+
+	return status, nil
+}
+
+// Delete removes a tenant-wide DirectorySetting.
+func (c *GroupSettingsClient) Delete(ctx context.Context, id string) (int, error) {
+	return c.delete(ctx, fmt.Sprintf("/groupSettings/%s", id))
+}
+
+// DeleteForGroup removes a DirectorySetting from the specified Group.
+// groupId is the object ID of the group.
+func (c *GroupSettingsClient) DeleteForGroup(ctx context.Context, groupId, id string) (int, error) {
+	return c.delete(ctx, fmt.Sprintf("/groups/%s/settings/%s", groupId, id))
+}
+
+func (c *GroupSettingsClient) delete(ctx context.Context, entity string) (int, error) {
+	_, status, _, err := c.BaseClient.Delete(ctx, DeleteHttpRequestInput{
+		ConsistencyFailureFunc: RetryOn404ConsistencyFailureFunc,
+		ValidStatusCodes:       []int{http.StatusNoContent},
+		Uri: Uri{
+			Entity:      entity,
+			HasTenantId: true,
+		},
+	})
+	if err != nil {
+		return status, fmt.Errorf("GroupSettingsClient.BaseClient.Delete(): %w", err)
+	}
+
+	return status, nil
+}
@@ -0,0 +1,186 @@
+package msgraph_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/manicminer/hamilton/auth"
+	"github.com/manicminer/hamilton/internal/test"
+	"github.com/manicminer/hamilton/internal/utils"
+	"github.com/manicminer/hamilton/msgraph"
+	"github.com/manicminer/hamilton/odata"
+)
+
+type GroupSettingsClientTest struct {
+	connection   *test.Connection
+	client       *msgraph.GroupSettingsClient
+	randomString string
+}
+
+type DirectorySettingTemplatesClientTest struct {
+	connection   *test.Connection
+	client       *msgraph.DirectorySettingTemplatesClient
+	randomString string
+}
+
+func TestGroupSettingsClient(t *testing.T) {
+	rs := test.RandomString()
+	c := GroupSettingsClientTest{
+		connection:   test.NewConnection(auth.MsGraph, auth.TokenVersion2),
+		randomString: rs,
+	}
+	c.client = msgraph.NewGroupSettingsClient(c.connection.AuthConfig.TenantID)
+	c.client.BaseClient.Authorizer = c.connection.Authorizer
+
+	tc := DirectorySettingTemplatesClientTest{
+		connection:   test.NewConnection(auth.MsGraph, auth.TokenVersion2),
+		randomString: rs,
+	}
+	tc.client = msgraph.NewDirectorySettingTemplatesClient(tc.connection.AuthConfig.TenantID)
+	tc.client.BaseClient.Authorizer = tc.connection.Authorizer
+
+	g := GroupsClientTest{
+		connection:   test.NewConnection(auth.MsGraph, auth.TokenVersion2),
+		randomString: rs,
+	}
+	g.client = msgraph.NewGroupsClient(g.connection.AuthConfig.TenantID)
+	g.client.BaseClient.Authorizer = g.connection.Authorizer
+
+	templates := testDirectorySettingTemplatesClient_List(t, tc)
+	var guestTemplateId string
+	for _, tmpl := range *templates {
+		if tmpl.DisplayName != nil && *tmpl.DisplayName == "Group.Unified.Guest" {
+			guestTemplateId = *tmpl.ID
+		}
+	}
+	if guestTemplateId == "" {
+		t.Fatal("could not find Group.Unified.Guest template")
+	}
+	template := testDirectorySettingTemplatesClient_Get(t, tc, guestTemplateId)
+
+	testGroupSettingsClient_List(t, c)
+
+	group := testGroupsClient_Create(t, g, msgraph.Group{
+		DisplayName:     utils.StringPtr("test-group-settings"),
+		GroupTypes:      []msgraph.GroupType{msgraph.GroupTypeUnified},
+		MailEnabled:     utils.BoolPtr(true),
+		MailNickname:    utils.StringPtr(fmt.Sprintf("test-group-settings-%s", c.randomString)),
+		SecurityEnabled: utils.BoolPtr(true),
+	})
+
+	newSetting := template.NewSetting()
+	newSetting.SetValue("AllowToAddGuests", "false")
+	setting := testGroupSettingsClient_CreateForGroup(t, c, *group.ID, newSetting)
+	setting.SetValue("AllowToAddGuests", "true")
+	testGroupSettingsClient_UpdateForGroup(t, c, *group.ID, *setting)
+	setting = testGroupSettingsClient_GetForGroup(t, c, *group.ID, *setting.ID)
+	if v := setting.GetValue("AllowToAddGuests"); v == nil || *v != "true" {
+		t.Fatalf("GroupSettingsClient.GetForGroup(): expected AllowToAddGuests to be %q, got %v", "true", v)
+	}
+	testGroupSettingsClient_ListForGroup(t, c, *group.ID)
+	testGroupSettingsClient_DeleteForGroup(t, c, *group.ID, *setting.ID)
+
+	testGroupsClient_Delete(t, g, *group.ID)
+	testGroupsClient_DeletePermanently(t, g, *group.ID)
+}
+
+func testDirectorySettingTemplatesClient_List(t *testing.T, c DirectorySettingTemplatesClientTest) (templates *[]msgraph.DirectorySettingTemplate) {
+	templates, _, err := c.client.List(c.connection.Context, odata.Query{})
+	if err != nil {
+		t.Fatalf("DirectorySettingTemplatesClient.List(): %v", err)
+	}
+	if templates == nil {
+		t.Fatal("DirectorySettingTemplatesClient.List(): templates was nil")
+	}
+	return
+}
+
+func testDirectorySettingTemplatesClient_Get(t *testing.T, c DirectorySettingTemplatesClientTest, id string) (template *msgraph.DirectorySettingTemplate) {
+	template, status, err := c.client.Get(c.connection.Context, id, odata.Query{})
+	if err != nil {
+		t.Fatalf("DirectorySettingTemplatesClient.Get(): %v", err)
+	}
+	if status < 200 || status >= 300 {
+		t.Fatalf("DirectorySettingTemplatesClient.Get(): invalid status: %d", status)
+	}
+	if template == nil {
+		t.Fatal("DirectorySettingTemplatesClient.Get(): template was nil")
+	}
+	return
+}
+
+func testGroupSettingsClient_List(t *testing.T, c GroupSettingsClientTest) (settings *[]msgraph.DirectorySetting) {
+	settings, _, err := c.client.List(c.connection.Context, odata.Query{})
+	if err != nil {
+		t.Fatalf("GroupSettingsClient.List(): %v", err)
+	}
+	if settings == nil {
+		t.Fatal("GroupSettingsClient.List(): settings was nil")
+	}
+	return
+}
+
+func testGroupSettingsClient_ListForGroup(t *testing.T, c GroupSettingsClientTest, groupId string) (settings *[]msgraph.DirectorySetting) {
+	settings, _, err := c.client.ListForGroup(c.connection.Context, groupId, odata.Query{})
+	if err != nil {
+		t.Fatalf("GroupSettingsClient.ListForGroup(): %v", err)
+	}
+	if settings == nil {
+		t.Fatal("GroupSettingsClient.ListForGroup(): settings was nil")
+	}
+	if len(*settings) == 0 {
+		t.Fatal("GroupSettingsClient.ListForGroup(): expected at least 1 setting. was: 0")
+	}
+	return
+}
+
+func testGroupSettingsClient_CreateForGroup(t *testing.T, c GroupSettingsClientTest, groupId string, s msgraph.DirectorySetting) (setting *msgraph.DirectorySetting) {
+	setting, status, err := c.client.CreateForGroup(c.connection.Context, groupId, s)
+	if err != nil {
+		t.Fatalf("GroupSettingsClient.CreateForGroup(): %v", err)
+	}
+	if status < 200 || status >= 300 {
+		t.Fatalf("GroupSettingsClient.CreateForGroup(): invalid status: %d", status)
+	}
+	if setting == nil {
+		t.Fatal("GroupSettingsClient.CreateForGroup(): setting was nil")
+	}
+	if setting.ID == nil {
+		t.Fatal("GroupSettingsClient.CreateForGroup(): setting.ID was nil")
+	}
+	return
+}
+
+func testGroupSettingsClient_GetForGroup(t *testing.T, c GroupSettingsClientTest, groupId, id string) (setting *msgraph.DirectorySetting) {
+	setting, status, err := c.client.GetForGroup(c.connection.Context, groupId, id, odata.Query{})
+	if err != nil {
+		t.Fatalf("GroupSettingsClient.GetForGroup(): %v", err)
+	}
+	if status < 200 || status >= 300 {
+		t.Fatalf("GroupSettingsClient.GetForGroup(): invalid status: %d", status)
+	}
+	if setting == nil {
+		t.Fatal("GroupSettingsClient.GetForGroup(): setting was nil")
+	}
+	return
+}
+
+func testGroupSettingsClient_UpdateForGroup(t *testing.T, c GroupSettingsClientTest, groupId string, s msgraph.DirectorySetting) {
+	status, err := c.client.UpdateForGroup(c.connection.Context, groupId, s)
+	if err != nil {
+		t.Fatalf("GroupSettingsClient.UpdateForGroup(): %v", err)
+	}
+	if status < 200 || status >= 300 {
+		t.Fatalf("GroupSettingsClient.UpdateForGroup(): invalid status: %d", status)
+	}
+}
+
+func testGroupSettingsClient_DeleteForGroup(t *testing.T, c GroupSettingsClientTest, groupId, id string) {
+	status, err := c.client.DeleteForGroup(c.connection.Context, groupId, id)
+	if err != nil {
+		t.Fatalf("GroupSettingsClient.DeleteForGroup(): %v", err)
+	}
+	if status < 200 || status >= 300 {
+		t.Fatalf("GroupSettingsClient.DeleteForGroup(): invalid status: %d", status)
+	}
+}
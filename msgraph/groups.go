@@ -6,10 +6,19 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"strconv"
+	"strings"
 
+	"github.com/manicminer/hamilton/errors"
 	"github.com/manicminer/hamilton/odata"
 )
 
+// groupMembersBindMaxMembers is the maximum number of members which can be added in a single request
+const groupMembersBindMaxMembers = 20
+
+// groupMembersReconcileMaxRetries is the number of times ReconcileMembers re-submits throttled requests in each batch
+const groupMembersReconcileMaxRetries = 5
+
 // GroupsClient performs operations on Groups.
 type GroupsClient struct {
 	BaseClient Client
@@ -25,6 +34,7 @@ func NewGroupsClient(tenantId string) *GroupsClient {
 // List returns a list of Groups, optionally queried using OData.
 func (c *GroupsClient) List(ctx context.Context, query odata.Query) (*[]Group, int, error) {
 	resp, status, _, err := c.BaseClient.Get(ctx, GetHttpRequestInput{
+		ConsistencyLevel: query.ConsistencyLevel,
 		DisablePaging:    query.Top > 0,
 		ValidStatusCodes: []int{http.StatusOK},
 		Uri: Uri{
@@ -34,7 +44,7 @@ func (c *GroupsClient) List(ctx context.Context, query odata.Query) (*[]Group, i
 		},
 	})
 	if err != nil {
-		return nil, status, fmt.Errorf("GroupsClient.BaseClient.Get(): %v", err)
+		return nil, status, fmt.Errorf("GroupsClient.BaseClient.Get(): %w", err)
 	}
 
 	defer resp.Body.Close()
@@ -46,7 +56,7 @@ func (c *GroupsClient) List(ctx context.Context, query odata.Query) (*[]Group, i
 	var data struct {
 		Groups []Group `json:"value"`
 	}
-	if err := json.Unmarshal(respBody, &data); err != nil {
+	if err := c.BaseClient.decode(respBody, &data); err != nil {
 		return nil, status, fmt.Errorf("json.Unmarshal(): %v", err)
 	}
 
@@ -76,7 +86,7 @@ func (c *GroupsClient) Create(ctx context.Context, group Group) (*Group, int, er
 		},
 	})
 	if err != nil {
-		return nil, status, fmt.Errorf("GroupsClient.BaseClient.Post(): %v", err)
+		return nil, status, fmt.Errorf("GroupsClient.BaseClient.Post(): %w", err)
 	}
 
 	defer resp.Body.Close()
@@ -86,7 +96,7 @@ func (c *GroupsClient) Create(ctx context.Context, group Group) (*Group, int, er
 	}
 
 	var newGroup Group
-	if err := json.Unmarshal(respBody, &newGroup); err != nil {
+	if err := c.BaseClient.decode(respBody, &newGroup); err != nil {
 		return nil, status, fmt.Errorf("json.Unmarshal(): %v", err)
 	}
 
@@ -97,6 +107,7 @@ func (c *GroupsClient) Create(ctx context.Context, group Group) (*Group, int, er
 func (c *GroupsClient) Get(ctx context.Context, id string, query odata.Query) (*Group, int, error) {
 	resp, status, _, err := c.BaseClient.Get(ctx, GetHttpRequestInput{
 		ConsistencyFailureFunc: RetryOn404ConsistencyFailureFunc,
+		ConsistencyLevel:       query.ConsistencyLevel,
 		ValidStatusCodes:       []int{http.StatusOK},
 		Uri: Uri{
 			Entity:      fmt.Sprintf("/groups/%s", id),
@@ -105,7 +116,7 @@ func (c *GroupsClient) Get(ctx context.Context, id string, query odata.Query) (*
 		},
 	})
 	if err != nil {
-		return nil, status, fmt.Errorf("GroupsClient.BaseClient.Get(): %v", err)
+		return nil, status, fmt.Errorf("GroupsClient.BaseClient.Get(): %w", err)
 	}
 
 	defer resp.Body.Close()
@@ -115,7 +126,7 @@ func (c *GroupsClient) Get(ctx context.Context, id string, query odata.Query) (*
 	}
 
 	var group Group
-	if err := json.Unmarshal(respBody, &group); err != nil {
+	if err := c.BaseClient.decode(respBody, &group); err != nil {
 		return nil, status, fmt.Errorf("json.Unmarshal(): %v", err)
 	}
 
@@ -142,6 +153,7 @@ func (c *GroupsClient) GetWithSchemaExtensions(ctx context.Context, id string, q
 	var resp *http.Response
 	resp, status, _, err = c.BaseClient.Get(ctx, GetHttpRequestInput{
 		ConsistencyFailureFunc: RetryOn404ConsistencyFailureFunc,
+		ConsistencyLevel:       query.ConsistencyLevel,
 		ValidStatusCodes:       []int{http.StatusOK},
 		Uri: Uri{
 			Entity:      fmt.Sprintf("/groups/%s", id),
@@ -150,7 +162,7 @@ func (c *GroupsClient) GetWithSchemaExtensions(ctx context.Context, id string, q
 		},
 	})
 	if err != nil {
-		return nil, status, fmt.Errorf("GroupsClient.BaseClient.Get(): %v", err)
+		return nil, status, fmt.Errorf("GroupsClient.BaseClient.Get(): %w", err)
 	}
 
 	defer resp.Body.Close()
@@ -160,7 +172,7 @@ func (c *GroupsClient) GetWithSchemaExtensions(ctx context.Context, id string, q
 	}
 
 	group.SchemaExtensions = schemaExtensions
-	if err := json.Unmarshal(respBody, group); err != nil {
+	if err := c.BaseClient.decode(respBody, group); err != nil {
 		return nil, status, fmt.Errorf("json.Unmarshal(): %v", err)
 	}
 
@@ -171,6 +183,7 @@ func (c *GroupsClient) GetWithSchemaExtensions(ctx context.Context, id string, q
 func (c *GroupsClient) GetDeleted(ctx context.Context, id string, query odata.Query) (*Group, int, error) {
 	resp, status, _, err := c.BaseClient.Get(ctx, GetHttpRequestInput{
 		ConsistencyFailureFunc: RetryOn404ConsistencyFailureFunc,
+		ConsistencyLevel:       query.ConsistencyLevel,
 		ValidStatusCodes:       []int{http.StatusOK},
 		Uri: Uri{
 			Entity:      fmt.Sprintf("/directory/deletedItems/%s", id),
@@ -179,7 +192,7 @@ func (c *GroupsClient) GetDeleted(ctx context.Context, id string, query odata.Qu
 		},
 	})
 	if err != nil {
-		return nil, status, fmt.Errorf("GroupsClient.BaseClient.Get(): %v", err)
+		return nil, status, fmt.Errorf("GroupsClient.BaseClient.Get(): %w", err)
 	}
 
 	defer resp.Body.Close()
@@ -189,7 +202,7 @@ func (c *GroupsClient) GetDeleted(ctx context.Context, id string, query odata.Qu
 	}
 
 	var group Group
-	if err := json.Unmarshal(respBody, &group); err != nil {
+	if err := c.BaseClient.decode(respBody, &group); err != nil {
 		return nil, status, fmt.Errorf("json.Unmarshal(): %v", err)
 	}
 
@@ -198,6 +211,13 @@ func (c *GroupsClient) GetDeleted(ctx context.Context, id string, query odata.Qu
 
 // Update amends an existing Group.
 func (c *GroupsClient) Update(ctx context.Context, group Group) (int, error) {
+	return c.UpdateAndClear(ctx, group, nil)
+}
+
+// UpdateAndClear amends an existing Group, additionally clearing the properties named in clear by setting them to
+// null. Since empty fields are omitted when a Group is marshaled, this is the way to remove a property value,
+// e.g. `[]string{"description"}`. Property names are those used by the API.
+func (c *GroupsClient) UpdateAndClear(ctx context.Context, group Group, clear []string) (int, error) {
 	var status int
 
 	body, err := json.Marshal(group)
@@ -208,6 +228,7 @@ func (c *GroupsClient) Update(ctx context.Context, group Group) (int, error) {
 	_, status, _, err = c.BaseClient.Patch(ctx, PatchHttpRequestInput{
 		Body:                   body,
 		ConsistencyFailureFunc: RetryOn404ConsistencyFailureFunc,
+		NullProperties:         clear,
 		ValidStatusCodes:       []int{http.StatusNoContent},
 		Uri: Uri{
 			Entity:      fmt.Sprintf("/groups/%s", *group.ID),
@@ -215,12 +236,46 @@ func (c *GroupsClient) Update(ctx context.Context, group Group) (int, error) {
 		},
 	})
 	if err != nil {
-		return status, fmt.Errorf("GroupsClient.BaseClient.Patch(): %v", err)
+		return status, fmt.Errorf("GroupsClient.BaseClient.Patch(): %w", err)
 	}
 
 	return status, nil
 }
 
+// UpdateAndReturn amends an existing Group as for Update, and returns the updated Group as represented by the API,
+// which saves retrieving it afterwards. Where the API does not return the updated object, the specified group is
+// returned instead.
+func (c *GroupsClient) UpdateAndReturn(ctx context.Context, group Group) (*Group, int, error) {
+	var status int
+
+	if group.ID == nil {
+		return nil, status, fmt.Errorf("GroupsClient.UpdateAndReturn(): cannot update group with nil ID")
+	}
+
+	body, err := json.Marshal(group)
+	if err != nil {
+		return nil, status, fmt.Errorf("json.Marshal(): %v", err)
+	}
+
+	var updated Group
+	returned, status, err := c.BaseClient.patchAndReturn(ctx, PatchHttpRequestInput{
+		Body:                   body,
+		ConsistencyFailureFunc: RetryOn404ConsistencyFailureFunc,
+		Uri: Uri{
+			Entity:      fmt.Sprintf("/groups/%s", *group.ID),
+			HasTenantId: true,
+		},
+	}, &updated)
+	if err != nil {
+		return nil, status, fmt.Errorf("GroupsClient.BaseClient.Patch(): %w", err)
+	}
+	if !returned {
+		return &group, status, nil
+	}
+
+	return &updated, status, nil
+}
+
 // Delete removes a Group.
 func (c *GroupsClient) Delete(ctx context.Context, id string) (int, error) {
 	_, status, _, err := c.BaseClient.Delete(ctx, DeleteHttpRequestInput{
@@ -232,7 +287,7 @@ func (c *GroupsClient) Delete(ctx context.Context, id string) (int, error) {
 		},
 	})
 	if err != nil {
-		return status, fmt.Errorf("GroupsClient.BaseClient.Delete(): %v", err)
+		return status, fmt.Errorf("GroupsClient.BaseClient.Delete(): %w", err)
 	}
 
 	return status, nil
@@ -249,7 +304,7 @@ func (c *GroupsClient) DeletePermanently(ctx context.Context, id string) (int, e
 		},
 	})
 	if err != nil {
-		return status, fmt.Errorf("GroupsClient.BaseClient.Delete(): %v", err)
+		return status, fmt.Errorf("GroupsClient.BaseClient.Delete(): %w", err)
 	}
 
 	return status, nil
@@ -258,6 +313,7 @@ func (c *GroupsClient) DeletePermanently(ctx context.Context, id string) (int, e
 // ListDeleted retrieves a list of recently deleted O365 groups, optionally queried using OData.
 func (c *GroupsClient) ListDeleted(ctx context.Context, query odata.Query) (*[]Group, int, error) {
 	resp, status, _, err := c.BaseClient.Get(ctx, GetHttpRequestInput{
+		ConsistencyLevel: query.ConsistencyLevel,
 		DisablePaging:    query.Top > 0,
 		ValidStatusCodes: []int{http.StatusOK},
 		Uri: Uri{
@@ -275,7 +331,7 @@ func (c *GroupsClient) ListDeleted(ctx context.Context, query odata.Query) (*[]G
 	var data struct {
 		DeletedGroups []Group `json:"value"`
 	}
-	if err = json.Unmarshal(respBody, &data); err != nil {
+	if err = c.BaseClient.decode(respBody, &data); err != nil {
 		return nil, status, err
 	}
 
@@ -293,7 +349,7 @@ func (c *GroupsClient) RestoreDeleted(ctx context.Context, id string) (*Group, i
 		},
 	})
 	if err != nil {
-		return nil, status, fmt.Errorf("GroupsClient.BaseClient.Post(): %v", err)
+		return nil, status, fmt.Errorf("GroupsClient.BaseClient.Post(): %w", err)
 	}
 
 	defer resp.Body.Close()
@@ -303,7 +359,7 @@ func (c *GroupsClient) RestoreDeleted(ctx context.Context, id string) (*Group, i
 	}
 
 	var restoredGroup Group
-	if err = json.Unmarshal(respBody, &restoredGroup); err != nil {
+	if err = c.BaseClient.decode(respBody, &restoredGroup); err != nil {
 		return nil, status, fmt.Errorf("json.Unmarshal(): %v", err)
 	}
 
@@ -323,7 +379,7 @@ func (c *GroupsClient) ListMembers(ctx context.Context, id string) (*[]string, i
 		},
 	})
 	if err != nil {
-		return nil, status, fmt.Errorf("GroupsClient.BaseClient.Get(): %v", err)
+		return nil, status, fmt.Errorf("GroupsClient.BaseClient.Get(): %w", err)
 	}
 
 	defer resp.Body.Close()
@@ -338,7 +394,7 @@ func (c *GroupsClient) ListMembers(ctx context.Context, id string) (*[]string, i
 			Id   string `json:"id"`
 		} `json:"value"`
 	}
-	if err := json.Unmarshal(respBody, &data); err != nil {
+	if err := c.BaseClient.decode(respBody, &data); err != nil {
 		return nil, status, fmt.Errorf("json.Unmarshal(): %v", err)
 	}
 
@@ -350,6 +406,95 @@ func (c *GroupsClient) ListMembers(ctx context.Context, id string) (*[]string, i
 	return &ret, status, nil
 }
 
+// ListTransitiveMembers returns the members of the specified Group, including the members of nested groups, optionally
+// queried using OData. The returned DirectoryObjects can be users, groups, devices, contacts or service principals,
+// which can be distinguished by their ODataType. For very large groups, use ListTransitiveMembersEach.
+// id is the object ID of the group.
+func (c *GroupsClient) ListTransitiveMembers(ctx context.Context, id string, query odata.Query) (*[]DirectoryObject, int, error) {
+	if query.ConsistencyLevel == "" && query.AdvancedQuery() {
+		query.ConsistencyLevel = odata.ConsistencyLevelEventual
+	}
+
+	resp, status, _, err := c.BaseClient.Get(ctx, GetHttpRequestInput{
+		ConsistencyFailureFunc: RetryOn404ConsistencyFailureFunc,
+		ConsistencyLevel:       query.ConsistencyLevel,
+		DisablePaging:          query.Top > 0,
+		ValidStatusCodes:       []int{http.StatusOK},
+		Uri: Uri{
+			Entity:      fmt.Sprintf("/groups/%s/transitiveMembers", id),
+			Params:      query.Values(),
+			HasTenantId: true,
+		},
+	})
+	if err != nil {
+		return nil, status, fmt.Errorf("GroupsClient.BaseClient.Get(): %w", err)
+	}
+
+	defer resp.Body.Close()
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, status, fmt.Errorf("io.ReadAll(): %v", err)
+	}
+
+	var data struct {
+		Members []DirectoryObject `json:"value"`
+	}
+	if err := c.BaseClient.decode(respBody, &data); err != nil {
+		return nil, status, fmt.Errorf("json.Unmarshal(): %v", err)
+	}
+
+	return &data.Members, status, nil
+}
+
+// ListTransitiveMembersEach invokes fn for each member of the specified Group, including the members of nested groups,
+// as each page of results is retrieved, which avoids holding the entire membership of a large group in memory.
+// Iteration stops when fn returns an error, which is returned, or ErrStopIteration, in which case no error is
+// returned. Cancelling ctx also stops iteration.
+// id is the object ID of the group.
+func (c *GroupsClient) ListTransitiveMembersEach(ctx context.Context, id string, query odata.Query, fn func(DirectoryObject) error) (int, error) {
+	if query.ConsistencyLevel == "" && query.AdvancedQuery() {
+		query.ConsistencyLevel = odata.ConsistencyLevelEventual
+	}
+
+	var callbackErr error
+	status, err := c.BaseClient.getEach(ctx, GetHttpRequestInput{
+		ConsistencyFailureFunc: RetryOn404ConsistencyFailureFunc,
+		ConsistencyLevel:       query.ConsistencyLevel,
+		DisablePaging:          query.Top > 0,
+		ValidStatusCodes:       []int{http.StatusOK},
+		Uri: Uri{
+			Entity:      fmt.Sprintf("/groups/%s/transitiveMembers", id),
+			Params:      query.Values(),
+			HasTenantId: true,
+		},
+	}, func(item json.RawMessage) error {
+		var member DirectoryObject
+		if err := c.BaseClient.decode(item, &member); err != nil {
+			return fmt.Errorf("json.Unmarshal(): %v", err)
+		}
+		if err := fn(member); err != nil {
+			callbackErr = err
+			return err
+		}
+		return nil
+	})
+	if err != nil {
+		if callbackErr != nil {
+			return status, callbackErr
+		}
+		return status, fmt.Errorf("GroupsClient.BaseClient.Get(): %w", err)
+	}
+
+	return status, nil
+}
+
+// ListMemberRefs retrieves the object IDs of the members of the specified Group, by requesting only references to the
+// members rather than the member objects themselves. This is cheaper than ListMembers for large groups.
+// id is the object ID of the group.
+func (c *GroupsClient) ListMemberRefs(ctx context.Context, id string, query odata.Query) (*[]string, int, error) {
+	return c.listRefs(ctx, fmt.Sprintf("/groups/%s/members/$ref", id), query)
+}
+
 // GetMember retrieves a single member of the specified Group.
 // groupId is the object ID of the group.
 // memberId is the object ID of the member object.
@@ -364,7 +509,7 @@ func (c *GroupsClient) GetMember(ctx context.Context, groupId, memberId string)
 		},
 	})
 	if err != nil {
-		return nil, status, fmt.Errorf("GroupsClient.BaseClient.Get(): %v", err)
+		return nil, status, fmt.Errorf("GroupsClient.BaseClient.Get(): %w", err)
 	}
 
 	defer resp.Body.Close()
@@ -379,7 +524,7 @@ func (c *GroupsClient) GetMember(ctx context.Context, groupId, memberId string)
 		Id      string `json:"id"`
 		Url     string `json:"url"`
 	}
-	if err := json.Unmarshal(respBody, &data); err != nil {
+	if err := c.BaseClient.decode(respBody, &data); err != nil {
 		return nil, status, fmt.Errorf("json.Unmarshal(): %v", err)
 	}
 
@@ -424,7 +569,7 @@ func (c *GroupsClient) AddMembers(ctx context.Context, group *Group) (int, error
 			},
 		})
 		if err != nil {
-			return status, fmt.Errorf("GroupsClient.BaseClient.Post(): %v", err)
+			return status, fmt.Errorf("GroupsClient.BaseClient.Post(): %w", err)
 		}
 	}
 
@@ -469,13 +614,171 @@ func (c *GroupsClient) RemoveMembers(ctx context.Context, id string, memberIds *
 			},
 		})
 		if err != nil {
-			return status, fmt.Errorf("GroupsClient.BaseClient.Delete(): %v", err)
+			return status, fmt.Errorf("GroupsClient.BaseClient.Delete(): %w", err)
 		}
 	}
 
 	return status, nil
 }
 
+// ReconcileMembers updates the direct members of a Group to match the specified member IDs, adding and removing members
+// as necessary using JSON batching. New members are added up to 20 at a time using `members@odata.bind`, and surplus
+// members are removed individually. Member IDs are compared case-insensitively and duplicates are ignored, so calling
+// this method repeatedly with the same IDs makes no further changes. Members which cannot be removed because they are
+// the last owner of the group are retained, and are listed in the result. An error is only returned when the current
+// members could not be retrieved or a batch could not be sent, in which case later batches are not sent. When a batch
+// could not be sent, the result describing the changes made by any earlier batches is returned along with the error.
+// groupId is the object ID of the group.
+// desiredMemberIds contains the object IDs of all the members the group should have.
+func (c *GroupsClient) ReconcileMembers(ctx context.Context, groupId string, desiredMemberIds []string) (*GroupMembersReconcileResult, int, error) {
+	current, status, err := c.ListMembers(ctx, groupId)
+	if err != nil {
+		return nil, status, fmt.Errorf("GroupsClient.ListMembers(): %v", err)
+	}
+
+	existing := make(map[string]bool, len(*current))
+	for _, id := range *current {
+		existing[strings.ToLower(id)] = true
+	}
+
+	desired := make(map[string]bool, len(desiredMemberIds))
+	toAdd := make([]string, 0)
+	for _, id := range desiredMemberIds {
+		id = strings.TrimSpace(id)
+		key := strings.ToLower(id)
+		if id == "" || desired[key] {
+			continue
+		}
+		desired[key] = true
+		if !existing[key] {
+			toAdd = append(toAdd, id)
+		}
+	}
+
+	toRemove := make([]string, 0)
+	for _, id := range *current {
+		if !desired[strings.ToLower(id)] {
+			toRemove = append(toRemove, id)
+		}
+	}
+
+	// each request adds a chunk of members, or removes a single member
+	requests := make([]BatchRequest, 0)
+	memberIds := make(map[string][]string)
+	for start := 0; start < len(toAdd); start += groupMembersBindMaxMembers {
+		end := start + groupMembersBindMaxMembers
+		if end > len(toAdd) {
+			end = len(toAdd)
+		}
+		chunk := toAdd[start:end]
+
+		refs := make([]odata.Id, len(chunk))
+		for i := range chunk {
+			member := DirectoryObject{ID: &chunk[i]}
+			refs[i] = odata.Id(member.Uri(c.BaseClient.Endpoint, c.BaseClient.ApiVersion))
+		}
+		body, err := json.Marshal(struct {
+			Members []odata.Id `json:"members@odata.bind"`
+		}{
+			Members: refs,
+		})
+		if err != nil {
+			return nil, status, fmt.Errorf("json.Marshal(): %v", err)
+		}
+
+		id := fmt.Sprintf("add-%d", start)
+		memberIds[id] = chunk
+		requests = append(requests, BatchRequest{
+			ID:      id,
+			Method:  http.MethodPatch,
+			Url:     fmt.Sprintf("/groups/%s", groupId),
+			Body:    body,
+			Headers: map[string]string{"Content-Type": "application/json"},
+		})
+	}
+	for i, memberId := range toRemove {
+		id := "remove-" + strconv.Itoa(i)
+		memberIds[id] = []string{memberId}
+		requests = append(requests, BatchRequest{
+			ID:     id,
+			Method: http.MethodDelete,
+			Url:    fmt.Sprintf("/groups/%s/members/%s/$ref", groupId, memberId),
+		})
+	}
+
+	batchClient := &BatchClient{BaseClient: c.BaseClient}
+	result := GroupMembersReconcileResult{
+		Retained: make([]string, 0),
+		Failures: make([]GroupMembersReconcileFailure, 0),
+	}
+
+	for start := 0; start < len(requests); start += batchMaxRequests {
+		end := start + batchMaxRequests
+		if end > len(requests) {
+			end = len(requests)
+		}
+
+		responses, _, s, err := batchClient.SendWithRetries(ctx, requests[start:end], groupMembersReconcileMaxRetries)
+		status = s
+		if err != nil {
+			return &result, status, fmt.Errorf("BatchClient.SendWithRetries(): %v", err)
+		}
+
+		for _, req := range requests[start:end] {
+			resp := responses[req.ID]
+			removal := req.Method == http.MethodDelete
+			ids := memberIds[req.ID]
+			respErr := batchResponseError(resp)
+
+			switch {
+			case resp.Status >= 200 && resp.Status < 300:
+				if removal {
+					result.Removed++
+				} else {
+					result.Added += len(ids)
+				}
+				continue
+
+			case removal && (resp.Status == http.StatusNotFound || respErr != nil && respErr.Match(odata.ErrorRemovedObjectReferencesDoNotExist)):
+				// the member has already been removed
+				continue
+
+			case removal && respErr != nil && respErr.Match(odata.ErrorCannotRemoveLastOwner):
+				result.Retained = append(result.Retained, ids[0])
+				continue
+
+			case !removal && respErr != nil && respErr.Match(odata.ErrorAddedObjectReferencesAlreadyExist):
+				// a member was added since the current members were retrieved, which fails the entire chunk, so fall
+				// back to adding the members individually
+				members := make(Members, len(ids))
+				for i := range ids {
+					member := DirectoryObject{ID: &ids[i]}
+					ref := odata.Id(member.Uri(c.BaseClient.Endpoint, c.BaseClient.ApiVersion))
+					member.ODataId = &ref
+					members[i] = member
+				}
+				s, err := c.AddMembers(ctx, &Group{DirectoryObject: DirectoryObject{ID: &groupId}, Members: &members})
+				if err == nil {
+					result.Added += len(ids)
+					continue
+				}
+				resp.Status, respErr = s, nil
+			}
+
+			for _, memberId := range ids {
+				result.Failures = append(result.Failures, GroupMembersReconcileFailure{
+					MemberId: memberId,
+					Removal:  removal,
+					Status:   resp.Status,
+					Error:    respErr,
+				})
+			}
+		}
+	}
+
+	return &result, status, nil
+}
+
 // ListOwners retrieves the owners of the specified Group.
 // id is the object ID of the group.
 func (c *GroupsClient) ListOwners(ctx context.Context, id string) (*[]string, int, error) {
@@ -489,7 +792,7 @@ func (c *GroupsClient) ListOwners(ctx context.Context, id string) (*[]string, in
 		},
 	})
 	if err != nil {
-		return nil, status, fmt.Errorf("GroupsClient.BaseClient.Get(): %v", err)
+		return nil, status, fmt.Errorf("GroupsClient.BaseClient.Get(): %w", err)
 	}
 
 	defer resp.Body.Close()
@@ -504,7 +807,7 @@ func (c *GroupsClient) ListOwners(ctx context.Context, id string) (*[]string, in
 			Id   string `json:"id"`
 		} `json:"value"`
 	}
-	if err := json.Unmarshal(respBody, &data); err != nil {
+	if err := c.BaseClient.decode(respBody, &data); err != nil {
 		return nil, status, fmt.Errorf("json.Unmarshal(): %v", err)
 	}
 
@@ -516,6 +819,55 @@ func (c *GroupsClient) ListOwners(ctx context.Context, id string) (*[]string, in
 	return &ret, status, nil
 }
 
+// ListOwnerRefs retrieves the object IDs of the owners of the specified Group, by requesting only references to the
+// owners rather than the owner objects themselves.
+// id is the object ID of the group.
+func (c *GroupsClient) ListOwnerRefs(ctx context.Context, id string, query odata.Query) (*[]string, int, error) {
+	return c.listRefs(ctx, fmt.Sprintf("/groups/%s/owners/$ref", id), query)
+}
+
+// listRefs retrieves a collection of references, returning the object IDs parsed from each @odata.id
+func (c *GroupsClient) listRefs(ctx context.Context, entity string, query odata.Query) (*[]string, int, error) {
+	resp, status, _, err := c.BaseClient.Get(ctx, GetHttpRequestInput{
+		ConsistencyFailureFunc: RetryOn404ConsistencyFailureFunc,
+		ConsistencyLevel:       query.ConsistencyLevel,
+		DisablePaging:          query.Top > 0,
+		ValidStatusCodes:       []int{http.StatusOK},
+		Uri: Uri{
+			Entity:      entity,
+			Params:      query.Values(),
+			HasTenantId: true,
+		},
+	})
+	if err != nil {
+		return nil, status, fmt.Errorf("GroupsClient.BaseClient.Get(): %w", err)
+	}
+
+	defer resp.Body.Close()
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, status, fmt.Errorf("io.ReadAll(): %v", err)
+	}
+
+	var data struct {
+		Refs []struct {
+			Id odata.Id `json:"@odata.id"`
+		} `json:"value"`
+	}
+	if err := c.BaseClient.decode(respBody, &data); err != nil {
+		return nil, status, fmt.Errorf("json.Unmarshal(): %v", err)
+	}
+
+	ret := make([]string, len(data.Refs))
+	for i, v := range data.Refs {
+		if ret[i], err = v.Id.ObjectId(); err != nil {
+			return nil, status, err
+		}
+	}
+
+	return &ret, status, nil
+}
+
 // GetOwner retrieves a single owner for the specified Group.
 // groupId is the object ID of the group.
 // ownerId is the object ID of the owning object.
@@ -530,7 +882,7 @@ func (c *GroupsClient) GetOwner(ctx context.Context, groupId, ownerId string) (*
 		},
 	})
 	if err != nil {
-		return nil, status, fmt.Errorf("GroupsClient.BaseClient.Get(): %v", err)
+		return nil, status, fmt.Errorf("GroupsClient.BaseClient.Get(): %w", err)
 	}
 
 	defer resp.Body.Close()
@@ -545,7 +897,7 @@ func (c *GroupsClient) GetOwner(ctx context.Context, groupId, ownerId string) (*
 		Id      string `json:"id"`
 		Url     string `json:"url"`
 	}
-	if err := json.Unmarshal(respBody, &data); err != nil {
+	if err := c.BaseClient.decode(respBody, &data); err != nil {
 		return nil, status, fmt.Errorf("json.Unmarshal(): %v", err)
 	}
 
@@ -562,36 +914,54 @@ func (c *GroupsClient) AddOwners(ctx context.Context, group *Group) (int, error)
 	}
 
 	for _, owner := range *group.Owners {
-		// don't fail if an owner already exists
-		checkOwnerAlreadyExists := func(resp *http.Response, o *odata.OData) bool {
-			if resp.StatusCode == http.StatusBadRequest && o != nil && o.Error != nil {
-				return o.Error.Match(odata.ErrorAddedObjectReferencesAlreadyExist)
-			}
-			return false
-		}
-
-		body, err := json.Marshal(struct {
-			Owner odata.Id `json:"@odata.id"`
-		}{
-			Owner: *owner.ODataId,
-		})
+		var err error
+		status, err = c.addOwner(ctx, *group.ID, *owner.ODataId)
 		if err != nil {
-			return status, fmt.Errorf("json.Marshal(): %v", err)
+			return status, err
 		}
+	}
 
-		_, status, _, err = c.BaseClient.Post(ctx, PostHttpRequestInput{
-			Body:                   body,
-			ConsistencyFailureFunc: RetryOn404ConsistencyFailureFunc,
-			ValidStatusCodes:       []int{http.StatusNoContent},
-			ValidStatusFunc:        checkOwnerAlreadyExists,
-			Uri: Uri{
-				Entity:      fmt.Sprintf("/groups/%s/owners/$ref", *group.ID),
-				HasTenantId: true,
-			},
-		})
-		if err != nil {
-			return status, fmt.Errorf("GroupsClient.BaseClient.Post(): %v", err)
+	return status, nil
+}
+
+// AddOwner adds a single owner to a Group. Adding an existing owner is not considered an error.
+// groupId is the object ID of the group.
+// ownerId is the object ID of the user or service principal to add as an owner.
+func (c *GroupsClient) AddOwner(ctx context.Context, groupId, ownerId string) (int, error) {
+	owner := DirectoryObject{ID: &ownerId}
+	return c.addOwner(ctx, groupId, odata.Id(owner.Uri(c.BaseClient.Endpoint, c.BaseClient.ApiVersion)))
+}
+
+func (c *GroupsClient) addOwner(ctx context.Context, groupId string, ownerODataId odata.Id) (int, error) {
+	// don't fail if an owner already exists
+	checkOwnerAlreadyExists := func(resp *http.Response, o *odata.OData) bool {
+		if resp.StatusCode == http.StatusBadRequest && o != nil && o.Error != nil {
+			return o.Error.Match(odata.ErrorAddedObjectReferencesAlreadyExist)
 		}
+		return false
+	}
+
+	body, err := json.Marshal(struct {
+		Owner odata.Id `json:"@odata.id"`
+	}{
+		Owner: ownerODataId,
+	})
+	if err != nil {
+		return 0, fmt.Errorf("json.Marshal(): %v", err)
+	}
+
+	_, status, _, err := c.BaseClient.Post(ctx, PostHttpRequestInput{
+		Body:                   body,
+		ConsistencyFailureFunc: RetryOn404ConsistencyFailureFunc,
+		ValidStatusCodes:       []int{http.StatusNoContent},
+		ValidStatusFunc:        checkOwnerAlreadyExists,
+		Uri: Uri{
+			Entity:      fmt.Sprintf("/groups/%s/owners/$ref", groupId),
+			HasTenantId: true,
+		},
+	})
+	if err != nil {
+		return status, fmt.Errorf("GroupsClient.BaseClient.Post(): %w", err)
 	}
 
 	return status, nil
@@ -616,28 +986,78 @@ func (c *GroupsClient) RemoveOwners(ctx context.Context, id string, ownerIds *[]
 			return status, err
 		}
 
-		// despite the above check, sometimes owners are just gone
-		checkOwnerGone := func(resp *http.Response, o *odata.OData) bool {
-			if resp.StatusCode == http.StatusBadRequest && o != nil && o.Error != nil {
-				return o.Error.Match(odata.ErrorRemovedObjectReferencesDoNotExist)
-			}
-			return false
-		}
-
 		var err error
-		_, status, _, err = c.BaseClient.Delete(ctx, DeleteHttpRequestInput{
-			ConsistencyFailureFunc: RetryOn404ConsistencyFailureFunc,
-			ValidStatusCodes:       []int{http.StatusNoContent},
-			ValidStatusFunc:        checkOwnerGone,
-			Uri: Uri{
-				Entity:      fmt.Sprintf("/groups/%s/owners/%s/$ref", id, ownerId),
-				HasTenantId: true,
-			},
-		})
+		status, err = c.RemoveOwner(ctx, id, ownerId)
 		if err != nil {
-			return status, fmt.Errorf("GroupsClient.BaseClient.Delete(): %v", err)
+			return status, err
+		}
+	}
+
+	return status, nil
+}
+
+// RemoveOwner removes a single owner from a Group. Removing an owner that does not exist is not considered an error.
+// A group which has owners must retain at least one of them, so attempting to remove the last owner returns an
+// errors.LastOwnerError.
+// groupId is the object ID of the group.
+// ownerId is the object ID of the owner to remove.
+func (c *GroupsClient) RemoveOwner(ctx context.Context, groupId, ownerId string) (int, error) {
+	// sometimes owners are already gone
+	checkOwnerGone := func(resp *http.Response, o *odata.OData) bool {
+		if resp.StatusCode == http.StatusBadRequest && o != nil && o.Error != nil {
+			return o.Error.Match(odata.ErrorRemovedObjectReferencesDoNotExist)
+		}
+		return false
+	}
+
+	_, status, o, err := c.BaseClient.Delete(ctx, DeleteHttpRequestInput{
+		ConsistencyFailureFunc: RetryOn404ConsistencyFailureFunc,
+		ValidStatusCodes:       []int{http.StatusNoContent},
+		ValidStatusFunc:        checkOwnerGone,
+		Uri: Uri{
+			Entity:      fmt.Sprintf("/groups/%s/owners/%s/$ref", groupId, ownerId),
+			HasTenantId: true,
+		},
+	})
+	if err != nil {
+		if status == http.StatusBadRequest && o != nil && o.Error != nil && o.Error.Match(odata.ErrorCannotRemoveLastOwner) {
+			return status, errors.LastOwnerError{Obj: "Group", Id: groupId, OwnerId: ownerId}
 		}
+		return status, fmt.Errorf("GroupsClient.BaseClient.Delete(): %w", err)
 	}
 
 	return status, nil
 }
+
+// GetPhoto retrieves the profile photo of a group, returning its content and media type. When etag is not
+// empty, the photo is only retrieved if it has changed since, otherwise ErrNotModified is returned. The ETag of the
+// current photo is available from GetPhotoMetadata().
+// id is the object ID of the group.
+func (c *GroupsClient) GetPhoto(ctx context.Context, id, etag string) ([]byte, string, int, error) {
+	content, contentType, status, err := c.BaseClient.getPhoto(ctx, Uri{
+		Entity:      fmt.Sprintf("/groups/%s/photo", id),
+		HasTenantId: true,
+	}, etag)
+	if err == ErrNotModified {
+		return nil, "", status, err
+	}
+	if err != nil {
+		return nil, "", status, fmt.Errorf("GroupsClient.BaseClient.Get(): %w", err)
+	}
+
+	return content, contentType, status, nil
+}
+
+// GetPhotoMetadata retrieves the ProfilePhoto of a group, including the ETag of the current photo.
+// id is the object ID of the group.
+func (c *GroupsClient) GetPhotoMetadata(ctx context.Context, id string) (*ProfilePhoto, int, error) {
+	photo, status, err := c.BaseClient.getPhotoMetadata(ctx, Uri{
+		Entity:      fmt.Sprintf("/groups/%s/photo", id),
+		HasTenantId: true,
+	})
+	if err != nil {
+		return nil, status, fmt.Errorf("GroupsClient.BaseClient.Get(): %w", err)
+	}
+
+	return photo, status, nil
+}
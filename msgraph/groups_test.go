@@ -1,10 +1,19 @@
 package msgraph_test
 
 import (
+	"context"
+	"encoding/json"
 	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
 	"testing"
 
 	"github.com/manicminer/hamilton/auth"
+	"github.com/manicminer/hamilton/environments"
+	"github.com/manicminer/hamilton/errors"
 	"github.com/manicminer/hamilton/internal/test"
 	"github.com/manicminer/hamilton/internal/utils"
 	"github.com/manicminer/hamilton/msgraph"
@@ -64,9 +73,11 @@ func TestGroupsClient(t *testing.T) {
 
 	owners := testGroupsClient_ListOwners(t, c, *group.ID)
 	testGroupsClient_GetOwner(t, c, *group.ID, (*owners)[0])
+	testGroupsClient_ListOwnerRefs(t, c, *group.ID, (*owners)[0])
 
 	members := testGroupsClient_ListMembers(t, c, *group.ID)
 	testGroupsClient_GetMember(t, c, *group.ID, (*members)[0])
+	testGroupsClient_ListMemberRefs(t, c, *group.ID, (*members)[0])
 
 	group.DisplayName = utils.StringPtr(fmt.Sprintf("test-updated-group-%s", c.randomString))
 	testGroupsClient_Update(t, c, *group)
@@ -84,6 +95,8 @@ func TestGroupsClient(t *testing.T) {
 	group.Owners = &msgraph.Owners{user.DirectoryObject}
 	testGroupsClient_AddOwners(t, c, group)
 	testGroupsClient_RemoveOwners(t, c, *group.ID, &([]string{claims.ObjectId}))
+	testGroupsClient_AddOwner(t, c, *group.ID, claims.ObjectId)
+	testGroupsClient_RemoveOwner(t, c, *group.ID, *user.ID)
 
 	group.Members = &msgraph.Members{user.DirectoryObject}
 	testGroupsClient_AddMembers(t, c, group)
@@ -198,6 +211,20 @@ func testGroupsClient_ListOwners(t *testing.T, c GroupsClientTest, id string) (o
 	return
 }
 
+func testGroupsClient_ListOwnerRefs(t *testing.T, c GroupsClientTest, id, expectedOwnerId string) (owners *[]string) {
+	owners, status, err := c.client.ListOwnerRefs(c.connection.Context, id, odata.Query{})
+	if err != nil {
+		t.Fatalf("GroupsClient.ListOwnerRefs(): %v", err)
+	}
+	if status < 200 || status >= 300 {
+		t.Fatalf("GroupsClient.ListOwnerRefs(): invalid status: %d", status)
+	}
+	if owners == nil || len(*owners) == 0 || (*owners)[0] != expectedOwnerId {
+		t.Fatalf("GroupsClient.ListOwnerRefs(): expected owner %q, got %v", expectedOwnerId, owners)
+	}
+	return
+}
+
 func testGroupsClient_GetOwner(t *testing.T, c GroupsClientTest, groupId string, ownerId string) (owner *string) {
 	owner, status, err := c.client.GetOwner(c.connection.Context, groupId, ownerId)
 	if err != nil {
@@ -232,6 +259,40 @@ func testGroupsClient_RemoveOwners(t *testing.T, c GroupsClientTest, groupId str
 	}
 }
 
+func testGroupsClient_AddOwner(t *testing.T, c GroupsClientTest, groupId, ownerId string) {
+	status, err := c.client.AddOwner(c.connection.Context, groupId, ownerId)
+	if err != nil {
+		t.Fatalf("GroupsClient.AddOwner(): %v", err)
+	}
+	if status < 200 || status >= 300 {
+		t.Fatalf("GroupsClient.AddOwner(): invalid status: %d", status)
+	}
+}
+
+func testGroupsClient_RemoveOwner(t *testing.T, c GroupsClientTest, groupId, ownerId string) {
+	status, err := c.client.RemoveOwner(c.connection.Context, groupId, ownerId)
+	if err != nil {
+		t.Fatalf("GroupsClient.RemoveOwner(): %v", err)
+	}
+	if status < 200 || status >= 300 {
+		t.Fatalf("GroupsClient.RemoveOwner(): invalid status: %d", status)
+	}
+}
+
+func testGroupsClient_ListMemberRefs(t *testing.T, c GroupsClientTest, id, expectedMemberId string) (members *[]string) {
+	members, status, err := c.client.ListMemberRefs(c.connection.Context, id, odata.Query{})
+	if err != nil {
+		t.Fatalf("GroupsClient.ListMemberRefs(): %v", err)
+	}
+	if status < 200 || status >= 300 {
+		t.Fatalf("GroupsClient.ListMemberRefs(): invalid status: %d", status)
+	}
+	if members == nil || len(*members) == 0 || (*members)[0] != expectedMemberId {
+		t.Fatalf("GroupsClient.ListMemberRefs(): expected member %q, got %v", expectedMemberId, members)
+	}
+	return
+}
+
 func testGroupsClient_ListMembers(t *testing.T, c GroupsClientTest, id string) (members *[]string) {
 	members, status, err := c.client.ListMembers(c.connection.Context, id)
 	if err != nil {
@@ -345,3 +406,302 @@ func testGroupsClient_RestoreDeleted(t *testing.T, c GroupsClientTest, id string
 		t.Fatal("GroupsClient.RestoreDeleted(): group IDs do not match")
 	}
 }
+
+func TestGroupsClient_RemoveOwnerLastOwner(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodDelete || r.URL.Path != "/beta/00000000-0000-0000-0000-000000000000/groups/group1/owners/owner1/$ref" {
+			t.Errorf("unexpected request: %s %s", r.Method, r.URL.Path)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		fmt.Fprint(w, `{"error":{"code":"Request_BadRequest","message":"The group must have at least one owner, hence this owner cannot be removed."}}`)
+	}))
+	defer server.Close()
+
+	c := msgraph.NewGroupsClient("00000000-0000-0000-0000-000000000000")
+	c.BaseClient.Endpoint = environments.ApiEndpoint(server.URL)
+
+	status, err := c.RemoveOwner(context.Background(), "group1", "owner1")
+	if status != http.StatusBadRequest {
+		t.Fatalf("GroupsClient.RemoveOwner(): unexpected status: %d", status)
+	}
+	if _, ok := err.(errors.LastOwnerError); !ok {
+		t.Fatalf("GroupsClient.RemoveOwner(): expected errors.LastOwnerError, got %T: %v", err, err)
+	}
+}
+
+func TestGroupsClient_ReconcileMembers(t *testing.T) {
+	const basePath = "/beta/00000000-0000-0000-0000-000000000000"
+	var server *httptest.Server
+	var added []odata.Id
+	var removed []string
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case basePath + "/groups/group1/members":
+			fmt.Fprint(w, `{"value":[{"@odata.type":"#microsoft.graph.user","id":"USER1"},{"@odata.type":"#microsoft.graph.user","id":"user2"},{"@odata.type":"#microsoft.graph.user","id":"owner1"}]}`)
+		case basePath + "/$batch":
+			var batch struct {
+				Requests []msgraph.BatchRequest `json:"requests"`
+			}
+			if err := json.NewDecoder(r.Body).Decode(&batch); err != nil {
+				t.Errorf("json.Decode(): %v", err)
+			}
+			responses := make([]msgraph.BatchResponse, 0)
+			for _, req := range batch.Requests {
+				resp := msgraph.BatchResponse{ID: req.ID, Status: http.StatusNoContent}
+				switch {
+				case req.Method == http.MethodPatch && req.Url == "/groups/group1":
+					var body struct {
+						Members []odata.Id `json:"members@odata.bind"`
+					}
+					if err := json.Unmarshal(req.Body, &body); err != nil {
+						t.Errorf("json.Unmarshal(): %v", err)
+					}
+					added = append(added, body.Members...)
+				case req.Method == http.MethodDelete && req.Url == "/groups/group1/members/owner1/$ref":
+					resp.Status = http.StatusBadRequest
+					resp.Body = json.RawMessage(`{"error":{"code":"Request_BadRequest","message":"The group must have at least one owner, hence this owner cannot be removed."}}`)
+				case req.Method == http.MethodDelete:
+					removed = append(removed, req.Url)
+				default:
+					t.Errorf("unexpected batch request %s %s", req.Method, req.Url)
+				}
+				responses = append(responses, resp)
+			}
+			json.NewEncoder(w).Encode(map[string]interface{}{"responses": responses})
+		default:
+			t.Errorf("unexpected request: %s %s", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	c := msgraph.NewGroupsClient("00000000-0000-0000-0000-000000000000")
+	c.BaseClient.Endpoint = environments.ApiEndpoint(server.URL)
+
+	result, _, err := c.ReconcileMembers(context.Background(), "group1", []string{"user1", "user3", "user3", "", "user4"})
+	if err != nil {
+		t.Fatalf("GroupsClient.ReconcileMembers(): %v", err)
+	}
+	if result.Added != 2 || result.Removed != 1 || len(result.Failures) != 0 {
+		t.Fatalf("GroupsClient.ReconcileMembers(): expected 2 added and 1 removed without failures, got %+v", result)
+	}
+	if expected := []string{"owner1"}; !reflect.DeepEqual(result.Retained, expected) {
+		t.Fatalf("GroupsClient.ReconcileMembers(): expected retained members %v, got %v", expected, result.Retained)
+	}
+	if expected := []odata.Id{
+		odata.Id(server.URL + "/beta/directoryObjects/user3"),
+		odata.Id(server.URL + "/beta/directoryObjects/user4"),
+	}; !reflect.DeepEqual(added, expected) {
+		t.Fatalf("GroupsClient.ReconcileMembers(): expected members %v to be bound, got %v", expected, added)
+	}
+	if expected := []string{"/groups/group1/members/user2/$ref"}; !reflect.DeepEqual(removed, expected) {
+		t.Fatalf("GroupsClient.ReconcileMembers(): expected removals %v, got %v", expected, removed)
+	}
+}
+
+func TestGroupsClient_ListMemberRefs(t *testing.T) {
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/beta/00000000-0000-0000-0000-000000000000/groups/group1/members/$ref" {
+			t.Errorf("unexpected request: %s %s", r.Method, r.URL.Path)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Query().Get("$skiptoken") == "" {
+			fmt.Fprintf(w, `{"@odata.nextLink":"%s%s?$skiptoken=page2","value":[{"@odata.id":"https://graph.microsoft.com/v2/00000000-0000-0000-0000-000000000000/directoryObjects/11111111-1111-1111-1111-111111111111/Microsoft.DirectoryServices.User"}]}`, server.URL, r.URL.Path)
+			return
+		}
+		fmt.Fprint(w, `{"value":[{"@odata.id":"https://graph.microsoft.com/v2/00000000-0000-0000-0000-000000000000/directoryObjects/22222222-2222-2222-2222-222222222222/Microsoft.DirectoryServices.Group"}]}`)
+	}))
+	defer server.Close()
+
+	c := msgraph.NewGroupsClient("00000000-0000-0000-0000-000000000000")
+	c.BaseClient.Endpoint = environments.ApiEndpoint(server.URL)
+
+	members, _, err := c.ListMemberRefs(context.Background(), "group1", odata.Query{})
+	if err != nil {
+		t.Fatalf("GroupsClient.ListMemberRefs(): %v", err)
+	}
+	if expected := []string{"11111111-1111-1111-1111-111111111111", "22222222-2222-2222-2222-222222222222"}; members == nil || !reflect.DeepEqual(*members, expected) {
+		t.Fatalf("GroupsClient.ListMemberRefs(): expected %v, got %v", expected, members)
+	}
+}
+
+func TestGroupsClient_ListTransitiveMembers(t *testing.T) {
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/beta/00000000-0000-0000-0000-000000000000/groups/group1/transitiveMembers" {
+			t.Errorf("unexpected request: %s %s", r.Method, r.URL.Path)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Query().Get("$skiptoken") == "" {
+			fmt.Fprintf(w, `{"@odata.nextLink":"%s%s?$skiptoken=page2","value":[{"@odata.type":"#microsoft.graph.user","id":"user1"},{"@odata.type":"#microsoft.graph.group","id":"group2"}]}`, server.URL, r.URL.Path)
+			return
+		}
+		fmt.Fprint(w, `{"value":[{"@odata.type":"#microsoft.graph.user","id":"user2"}]}`)
+	}))
+	defer server.Close()
+
+	c := msgraph.NewGroupsClient("00000000-0000-0000-0000-000000000000")
+	c.BaseClient.Endpoint = environments.ApiEndpoint(server.URL)
+
+	members, _, err := c.ListTransitiveMembers(context.Background(), "group1", odata.Query{})
+	if err != nil {
+		t.Fatalf("GroupsClient.ListTransitiveMembers(): %v", err)
+	}
+	if members == nil || len(*members) != 3 {
+		t.Fatalf("GroupsClient.ListTransitiveMembers(): expected 3 members across pages, got %v", members)
+	}
+	if m := (*members)[1]; m.ODataType == nil || *m.ODataType != odata.TypeGroup || *m.ID != "group2" {
+		t.Fatalf("GroupsClient.ListTransitiveMembers(): expected nested group, got %+v", m)
+	}
+
+	var ids []string
+	if _, err := c.ListTransitiveMembersEach(context.Background(), "group1", odata.Query{}, func(m msgraph.DirectoryObject) error {
+		if m.ODataType != nil && *m.ODataType == odata.TypeUser {
+			ids = append(ids, *m.ID)
+		}
+		return nil
+	}); err != nil {
+		t.Fatalf("GroupsClient.ListTransitiveMembersEach(): %v", err)
+	}
+	if expected := []string{"user1", "user2"}; !reflect.DeepEqual(ids, expected) {
+		t.Fatalf("GroupsClient.ListTransitiveMembersEach(): expected users %v, got %v", expected, ids)
+	}
+}
+
+func TestGroupsClient_ReconcileMembersPartialFailure(t *testing.T) {
+	const basePath = "/beta/00000000-0000-0000-0000-000000000000"
+	members := make([]string, 25)
+	for i := range members {
+		members[i] = fmt.Sprintf(`{"@odata.type":"#microsoft.graph.user","id":"user%d"}`, i)
+	}
+	var batches int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case basePath + "/groups/group1/members":
+			w.Header().Set("Content-Type", "application/json")
+			fmt.Fprintf(w, `{"value":[%s]}`, strings.Join(members, ","))
+		case basePath + "/$batch":
+			batches++
+			w.Header().Set("Content-Type", "application/json")
+			if batches > 1 {
+				w.WriteHeader(http.StatusBadRequest)
+				fmt.Fprint(w, `{"error":{"code":"BadRequest","message":"Invalid batch payload format."}}`)
+				return
+			}
+			var batch struct {
+				Requests []msgraph.BatchRequest `json:"requests"`
+			}
+			if err := json.NewDecoder(r.Body).Decode(&batch); err != nil {
+				t.Errorf("json.Decode(): %v", err)
+			}
+			responses := make([]msgraph.BatchResponse, 0)
+			for _, req := range batch.Requests {
+				responses = append(responses, msgraph.BatchResponse{ID: req.ID, Status: http.StatusNoContent})
+			}
+			json.NewEncoder(w).Encode(map[string]interface{}{"responses": responses})
+		default:
+			t.Errorf("unexpected request: %s %s", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	c := msgraph.NewGroupsClient("00000000-0000-0000-0000-000000000000")
+	c.BaseClient.Endpoint = environments.ApiEndpoint(server.URL)
+
+	result, status, err := c.ReconcileMembers(context.Background(), "group1", []string{})
+	if err == nil {
+		t.Fatal("GroupsClient.ReconcileMembers(): expected an error when a batch could not be sent")
+	}
+	if status != http.StatusBadRequest {
+		t.Fatalf("GroupsClient.ReconcileMembers(): expected status %d, got %d", http.StatusBadRequest, status)
+	}
+	if result == nil {
+		t.Fatal("GroupsClient.ReconcileMembers(): expected a result for the batches which were sent")
+	}
+	if result.Added != 0 || result.Removed != 20 || len(result.Failures) != 0 {
+		t.Fatalf("GroupsClient.ReconcileMembers(): expected 20 removed without failures, got %+v", result)
+	}
+}
+
+func TestGroupsClient_UpdateAndClear(t *testing.T) {
+	var method, path, body string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		b, _ := io.ReadAll(r.Body)
+		method, path, body = r.Method, r.URL.Path, string(b)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	c := msgraph.NewGroupsClient("00000000-0000-0000-0000-000000000000")
+	c.BaseClient.Endpoint = environments.ApiEndpoint(server.URL)
+
+	group := msgraph.Group{
+		DirectoryObject: msgraph.DirectoryObject{ID: utils.StringPtr("group1")},
+		DisplayName:     utils.StringPtr("Test Group"),
+	}
+	if _, err := c.UpdateAndClear(context.Background(), group, []string{"description", "classification"}); err != nil {
+		t.Fatalf("GroupsClient.UpdateAndClear(): %v", err)
+	}
+	if method != http.MethodPatch || path != "/beta/00000000-0000-0000-0000-000000000000/groups/group1" {
+		t.Fatalf("GroupsClient.UpdateAndClear(): unexpected request %s %s", method, path)
+	}
+	if expected := `{"classification":null,"description":null,"displayName":"Test Group","id":"group1"}`; body != expected {
+		t.Fatalf("GroupsClient.UpdateAndClear(): unexpected request body\nexpected: %s\nreceived: %s", expected, body)
+	}
+
+	if _, err := c.UpdateAndClear(context.Background(), group, []string{"displayName"}); err == nil {
+		t.Fatal("GroupsClient.UpdateAndClear(): expected error when clearing a property that is also being updated")
+	}
+}
+
+func TestGroupsClient_UpdateAndReturn(t *testing.T) {
+	representation := true
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPatch || r.URL.Path != "/beta/00000000-0000-0000-0000-000000000000/groups/group1" {
+			t.Errorf("unexpected request: %s %s", r.Method, r.URL.Path)
+		}
+		if prefer := r.Header.Get("Prefer"); prefer != "return=representation" {
+			t.Errorf("expected Prefer header %q, got %q", "return=representation", prefer)
+		}
+		if !representation {
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"id":"group1","displayName":"Updated Group","mailNickname":"updated-group"}`)
+	}))
+	defer server.Close()
+
+	c := msgraph.NewGroupsClient("00000000-0000-0000-0000-000000000000")
+	c.BaseClient.Endpoint = environments.ApiEndpoint(server.URL)
+
+	input := msgraph.Group{
+		DirectoryObject: msgraph.DirectoryObject{ID: utils.StringPtr("group1")},
+		DisplayName:     utils.StringPtr("Updated Group"),
+	}
+
+	group, status, err := c.UpdateAndReturn(context.Background(), input)
+	if err != nil {
+		t.Fatalf("GroupsClient.UpdateAndReturn(): %v", err)
+	}
+	if status != http.StatusOK || group.MailNickname == nil || *group.MailNickname != "updated-group" {
+		t.Fatalf("GroupsClient.UpdateAndReturn(): expected returned representation, got status %d and %+v", status, group)
+	}
+
+	representation = false
+	group, status, err = c.UpdateAndReturn(context.Background(), input)
+	if err != nil {
+		t.Fatalf("GroupsClient.UpdateAndReturn(): %v", err)
+	}
+	if status != http.StatusNoContent || group.ID == nil || *group.ID != "group1" || group.MailNickname != nil {
+		t.Fatalf("GroupsClient.UpdateAndReturn(): expected input to be returned for no content, got status %d and %+v", status, group)
+	}
+
+	if _, _, err := c.UpdateAndReturn(context.Background(), msgraph.Group{DisplayName: utils.StringPtr("Updated Group")}); err == nil {
+		t.Fatal("GroupsClient.UpdateAndReturn(): expected error for group with nil ID")
+	}
+}
@@ -0,0 +1,162 @@
+package msgraph
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/manicminer/hamilton/odata"
+)
+
+// IdentityProtectionClient performs operations on risky users and risk detections.
+type IdentityProtectionClient struct {
+	BaseClient Client
+}
+
+// NewIdentityProtectionClient returns a new IdentityProtectionClient.
+func NewIdentityProtectionClient(tenantId string) *IdentityProtectionClient {
+	return &IdentityProtectionClient{
+		BaseClient: NewClient(Version10, tenantId),
+	}
+}
+
+// ListRiskyUsers returns a list of RiskyUsers, optionally queried using OData. For example, filter on
+// `riskLevel eq 'high'`, or use odata.DateRangeFilter() with the `riskLastUpdatedDateTime` property.
+func (c *IdentityProtectionClient) ListRiskyUsers(ctx context.Context, query odata.Query) (*[]RiskyUser, int, error) {
+	resp, status, _, err := c.BaseClient.Get(ctx, GetHttpRequestInput{
+		ConsistencyLevel: query.ConsistencyLevel,
+		DisablePaging:    query.Top > 0,
+		ValidStatusCodes: []int{http.StatusOK},
+		Uri: Uri{
+			Entity:      "/identityProtection/riskyUsers",
+			Params:      query.Values(),
+			HasTenantId: true,
+		},
+	})
+	if err != nil {
+		return nil, status, fmt.Errorf("IdentityProtectionClient.BaseClient.Get(): %w", err)
+	}
+
+	defer resp.Body.Close()
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, status, fmt.Errorf("io.ReadAll(): %v", err)
+	}
+
+	var data struct {
+		RiskyUsers []RiskyUser `json:"value"`
+	}
+	if err := c.BaseClient.decode(respBody, &data); err != nil {
+		return nil, status, fmt.Errorf("json.Unmarshal(): %v", err)
+	}
+
+	return &data.RiskyUsers, status, nil
+}
+
+// GetRiskyUser retrieves a RiskyUser.
+// id is the object ID of the user.
+func (c *IdentityProtectionClient) GetRiskyUser(ctx context.Context, id string, query odata.Query) (*RiskyUser, int, error) {
+	resp, status, _, err := c.BaseClient.Get(ctx, GetHttpRequestInput{
+		ConsistencyLevel: query.ConsistencyLevel,
+		ValidStatusCodes: []int{http.StatusOK},
+		Uri: Uri{
+			Entity:      fmt.Sprintf("/identityProtection/riskyUsers/%s", id),
+			Params:      query.Values(),
+			HasTenantId: true,
+		},
+	})
+	if err != nil {
+		return nil, status, fmt.Errorf("IdentityProtectionClient.BaseClient.Get(): %w", err)
+	}
+
+	defer resp.Body.Close()
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, status, fmt.Errorf("io.ReadAll(): %v", err)
+	}
+
+	var riskyUser RiskyUser
+	if err := c.BaseClient.decode(respBody, &riskyUser); err != nil {
+		return nil, status, fmt.Errorf("json.Unmarshal(): %v", err)
+	}
+
+	return &riskyUser, status, nil
+}
+
+// ConfirmCompromised marks the specified users as compromised, setting their risk level to high.
+// userIds are the object IDs of the users, up to 60 per request.
+func (c *IdentityProtectionClient) ConfirmCompromised(ctx context.Context, userIds []string) (int, error) {
+	return c.riskyUsersAction(ctx, "confirmCompromised", userIds)
+}
+
+// Dismiss dismisses the risk of the specified users, setting their risk level to none.
+// userIds are the object IDs of the users, up to 60 per request.
+func (c *IdentityProtectionClient) Dismiss(ctx context.Context, userIds []string) (int, error) {
+	return c.riskyUsersAction(ctx, "dismiss", userIds)
+}
+
+func (c *IdentityProtectionClient) riskyUsersAction(ctx context.Context, action string, userIds []string) (int, error) {
+	var status int
+
+	if len(userIds) == 0 {
+		return status, fmt.Errorf("no users specified")
+	}
+
+	body, err := json.Marshal(struct {
+		UserIds []string `json:"userIds"`
+	}{
+		UserIds: userIds,
+	})
+	if err != nil {
+		return status, fmt.Errorf("json.Marshal(): %v", err)
+	}
+
+	_, status, _, err = c.BaseClient.Post(ctx, PostHttpRequestInput{
+		Body:             body,
+		ValidStatusCodes: []int{http.StatusNoContent},
+		Uri: Uri{
+			Entity:      fmt.Sprintf("/identityProtection/riskyUsers/%s", action),
+			HasTenantId: true,
+		},
+	})
+	if err != nil {
+		return status, fmt.Errorf("IdentityProtectionClient.BaseClient.Post(): %w", err)
+	}
+
+	return status, nil
+}
+
+// ListRiskDetections returns a list of RiskDetections, optionally queried using OData. For example, use
+// odata.DateRangeFilter() with the `detectedDateTime` property to retrieve detections within a date range.
+func (c *IdentityProtectionClient) ListRiskDetections(ctx context.Context, query odata.Query) (*[]RiskDetection, int, error) {
+	resp, status, _, err := c.BaseClient.Get(ctx, GetHttpRequestInput{
+		ConsistencyLevel: query.ConsistencyLevel,
+		DisablePaging:    query.Top > 0,
+		ValidStatusCodes: []int{http.StatusOK},
+		Uri: Uri{
+			Entity:      "/identityProtection/riskDetections",
+			Params:      query.Values(),
+			HasTenantId: true,
+		},
+	})
+	if err != nil {
+		return nil, status, fmt.Errorf("IdentityProtectionClient.BaseClient.Get(): %w", err)
+	}
+
+	defer resp.Body.Close()
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, status, fmt.Errorf("io.ReadAll(): %v", err)
+	}
+
+	var data struct {
+		RiskDetections []RiskDetection `json:"value"`
+	}
+	if err := c.BaseClient.decode(respBody, &data); err != nil {
+		return nil, status, fmt.Errorf("json.Unmarshal(): %v", err)
+	}
+
+	return &data.RiskDetections, status, nil
+}
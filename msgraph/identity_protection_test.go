@@ -0,0 +1,84 @@
+package msgraph_test
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/manicminer/hamilton/environments"
+	"github.com/manicminer/hamilton/msgraph"
+	"github.com/manicminer/hamilton/odata"
+)
+
+func TestIdentityProtectionClient_RiskyUsers(t *testing.T) {
+	var filter string
+	actions := make(map[string]string)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+			filter = r.URL.Query().Get("$filter")
+			w.Header().Set("Content-Type", "application/json")
+			switch r.URL.Path {
+			case "/v1.0/00000000-0000-0000-0000-000000000000/identityProtection/riskyUsers":
+				fmt.Fprint(w, `{"value":[{"id":"user1","riskLevel":"high","riskState":"atRisk","riskLastUpdatedDateTime":"2021-06-02T10:00:00Z"}]}`)
+			case "/v1.0/00000000-0000-0000-0000-000000000000/identityProtection/riskDetections":
+				fmt.Fprint(w, `{"value":[{"id":"detection1","userId":"user1","riskEventType":"unfamiliarFeatures","riskLevel":"medium","riskState":"atRisk","detectedDateTime":"2021-06-02T09:00:00Z","location":{"city":"Redmond"}}]}`)
+			default:
+				t.Errorf("unexpected request: %s %s", r.Method, r.URL.Path)
+			}
+		case http.MethodPost:
+			body, _ := io.ReadAll(r.Body)
+			actions[r.URL.Path] = string(body)
+			w.WriteHeader(http.StatusNoContent)
+		}
+	}))
+	defer server.Close()
+
+	c := msgraph.NewIdentityProtectionClient("00000000-0000-0000-0000-000000000000")
+	c.BaseClient.Endpoint = environments.ApiEndpoint(server.URL)
+	ctx := context.Background()
+
+	riskyUsers, _, err := c.ListRiskyUsers(ctx, odata.Query{Filter: "riskLevel eq 'high'"})
+	if err != nil {
+		t.Fatalf("IdentityProtectionClient.ListRiskyUsers(): %v", err)
+	}
+	if riskyUsers == nil || len(*riskyUsers) != 1 || *(*riskyUsers)[0].RiskLevel != msgraph.RiskLevelHigh || *(*riskyUsers)[0].RiskState != msgraph.RiskStateAtRisk {
+		t.Fatalf("IdentityProtectionClient.ListRiskyUsers(): unexpected result: %#v", riskyUsers)
+	}
+
+	start := time.Date(2021, 6, 1, 0, 0, 0, 0, time.UTC)
+	detections, _, err := c.ListRiskDetections(ctx, odata.Query{Filter: odata.DateRangeFilter("detectedDateTime", start, start.AddDate(0, 0, 7))})
+	if err != nil {
+		t.Fatalf("IdentityProtectionClient.ListRiskDetections(): %v", err)
+	}
+	if expected := "detectedDateTime ge 2021-06-01T00:00:00Z and detectedDateTime lt 2021-06-08T00:00:00Z"; filter != expected {
+		t.Fatalf("IdentityProtectionClient.ListRiskDetections(): expected filter %q, got %q", expected, filter)
+	}
+	if detections == nil || len(*detections) != 1 || (*detections)[0].Location == nil || *(*detections)[0].Location.City != "Redmond" {
+		t.Fatalf("IdentityProtectionClient.ListRiskDetections(): unexpected result: %#v", detections)
+	}
+
+	if _, err := c.ConfirmCompromised(ctx, []string{"user1"}); err != nil {
+		t.Fatalf("IdentityProtectionClient.ConfirmCompromised(): %v", err)
+	}
+	if _, err := c.Dismiss(ctx, []string{"user2", "user3"}); err != nil {
+		t.Fatalf("IdentityProtectionClient.Dismiss(): %v", err)
+	}
+	expected := map[string]string{
+		"/v1.0/00000000-0000-0000-0000-000000000000/identityProtection/riskyUsers/confirmCompromised": `{"userIds":["user1"]}`,
+		"/v1.0/00000000-0000-0000-0000-000000000000/identityProtection/riskyUsers/dismiss":            `{"userIds":["user2","user3"]}`,
+	}
+	for path, body := range expected {
+		if actions[path] != body {
+			t.Fatalf("IdentityProtectionClient: expected POST %s with body %s, got %q", path, body, actions[path])
+		}
+	}
+
+	if _, err := c.Dismiss(ctx, nil); err == nil {
+		t.Fatal("IdentityProtectionClient.Dismiss(): expected error when no users are specified")
+	}
+}
@@ -31,7 +31,7 @@ func (c *IdentityProvidersClient) List(ctx context.Context) (*[]IdentityProvider
 		},
 	})
 	if err != nil {
-		return nil, status, fmt.Errorf("IdentityProvidersClient.BaseClient.Get(): %v", err)
+		return nil, status, fmt.Errorf("IdentityProvidersClient.BaseClient.Get(): %w", err)
 	}
 
 	defer resp.Body.Close()
@@ -43,7 +43,7 @@ func (c *IdentityProvidersClient) List(ctx context.Context) (*[]IdentityProvider
 	var data struct {
 		IdentityProviders []IdentityProvider `json:"value"`
 	}
-	if err := json.Unmarshal(respBody, &data); err != nil {
+	if err := c.BaseClient.decode(respBody, &data); err != nil {
 		return nil, status, fmt.Errorf("json.Unmarshal(): %v", err)
 	}
 
@@ -68,7 +68,7 @@ func (c *IdentityProvidersClient) Create(ctx context.Context, provider IdentityP
 		},
 	})
 	if err != nil {
-		return nil, status, fmt.Errorf("IdentityProvidersClient.BaseClient.Post(): %v", err)
+		return nil, status, fmt.Errorf("IdentityProvidersClient.BaseClient.Post(): %w", err)
 	}
 
 	defer resp.Body.Close()
@@ -78,7 +78,7 @@ func (c *IdentityProvidersClient) Create(ctx context.Context, provider IdentityP
 	}
 
 	var newProvider IdentityProvider
-	if err := json.Unmarshal(respBody, &newProvider); err != nil {
+	if err := c.BaseClient.decode(respBody, &newProvider); err != nil {
 		return nil, status, fmt.Errorf("json.Unmarshal(): %v", err)
 	}
 
@@ -96,7 +96,7 @@ func (c *IdentityProvidersClient) Get(ctx context.Context, id string) (*Identity
 		},
 	})
 	if err != nil {
-		return nil, status, fmt.Errorf("IdentityProvidersClient.BaseClient.Get(): %v", err)
+		return nil, status, fmt.Errorf("IdentityProvidersClient.BaseClient.Get(): %w", err)
 	}
 
 	defer resp.Body.Close()
@@ -106,7 +106,7 @@ func (c *IdentityProvidersClient) Get(ctx context.Context, id string) (*Identity
 	}
 
 	var provider IdentityProvider
-	if err := json.Unmarshal(respBody, &provider); err != nil {
+	if err := c.BaseClient.decode(respBody, &provider); err != nil {
 		return nil, status, fmt.Errorf("json.Unmarshal(): %v", err)
 	}
 
@@ -136,7 +136,7 @@ func (c *IdentityProvidersClient) Update(ctx context.Context, provider IdentityP
 		},
 	})
 	if err != nil {
-		return status, fmt.Errorf("IdentityProvidersClient.BaseClient.Patch(): %v", err)
+		return status, fmt.Errorf("IdentityProvidersClient.BaseClient.Patch(): %w", err)
 	}
 
 	return status, nil
@@ -153,7 +153,7 @@ func (c *IdentityProvidersClient) Delete(ctx context.Context, id string) (int, e
 		},
 	})
 	if err != nil {
-		return status, fmt.Errorf("IdentityProvidersClient.BaseClient.Delete(): %v", err)
+		return status, fmt.Errorf("IdentityProvidersClient.BaseClient.Delete(): %w", err)
 	}
 
 	return status, nil
@@ -169,7 +169,7 @@ func (c *IdentityProvidersClient) ListAvailableProviderTypes(ctx context.Context
 		},
 	})
 	if err != nil {
-		return nil, status, fmt.Errorf("IdentityProvidersClient.BaseClient.Get(): %v", err)
+		return nil, status, fmt.Errorf("IdentityProvidersClient.BaseClient.Get(): %w", err)
 	}
 
 	defer resp.Body.Close()
@@ -181,7 +181,7 @@ func (c *IdentityProvidersClient) ListAvailableProviderTypes(ctx context.Context
 	var data struct {
 		IdentityProviderTypes []string `json:"value"`
 	}
-	if err := json.Unmarshal(respBody, &data); err != nil {
+	if err := c.BaseClient.decode(respBody, &data); err != nil {
 		return nil, status, fmt.Errorf("json.Unmarshal(): %v", err)
 	}
 
@@ -38,7 +38,7 @@ func (c *InvitationsClient) Create(ctx context.Context, invitation Invitation) (
 		},
 	})
 	if err != nil {
-		return nil, status, fmt.Errorf("InvitationsClient.BaseClient.Post(): %v", err)
+		return nil, status, fmt.Errorf("InvitationsClient.BaseClient.Post(): %w", err)
 	}
 
 	defer resp.Body.Close()
@@ -48,7 +48,7 @@ func (c *InvitationsClient) Create(ctx context.Context, invitation Invitation) (
 	}
 
 	var newInvitation Invitation
-	if err := json.Unmarshal(respBody, &newInvitation); err != nil {
+	if err := c.BaseClient.decode(respBody, &newInvitation); err != nil {
 		return nil, status, fmt.Errorf("json.Unmarshal(): %v", err)
 	}
 
@@ -0,0 +1,69 @@
+package msgraph
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/manicminer/hamilton/odata"
+)
+
+// ErrStopIteration can be returned by the callback supplied to a ListEach method to stop iterating without error.
+var ErrStopIteration = errors.New("stop iteration")
+
+// getEach performs a GET request for a collection, invoking fn for each item as each page of results is retrieved
+// rather than accumulating the entire collection. Iteration stops when fn returns an error, which is returned unless
+// it is ErrStopIteration, or when ctx is cancelled. When input.DisablePaging is set, only the first page is retrieved.
+func (c Client) getEach(ctx context.Context, input GetHttpRequestInput, fn func(json.RawMessage) error) (int, error) {
+	disablePaging := input.DisablePaging
+	input.DisablePaging = true
+
+	var status int
+	for {
+		if err := ctx.Err(); err != nil {
+			return status, err
+		}
+
+		resp, respStatus, _, err := c.Get(ctx, input)
+		status = respStatus
+		if err != nil {
+			return status, err
+		}
+
+		respBody, err := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if err != nil {
+			return status, fmt.Errorf("io.ReadAll(): %v", err)
+		}
+
+		page, err := odata.UnmarshalResponse(respBody)
+		if err != nil {
+			return status, fmt.Errorf("json.Unmarshal(): %v", err)
+		}
+		items, err := page.Items()
+		if err != nil {
+			return status, fmt.Errorf("json.Unmarshal(): %v", err)
+		}
+
+		for _, item := range items {
+			if err := ctx.Err(); err != nil {
+				return status, err
+			}
+			if err := fn(item); err != nil {
+				if errors.Is(err, ErrStopIteration) {
+					return status, nil
+				}
+				return status, err
+			}
+		}
+
+		if disablePaging || page.NextLink == "" {
+			return status, nil
+		}
+		if input.rawUri, err = c.rebaseUri(page.NextLink); err != nil {
+			return status, fmt.Errorf("invalid next link %q: %v", page.NextLink, err)
+		}
+	}
+}
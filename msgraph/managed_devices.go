@@ -0,0 +1,132 @@
+package msgraph
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/manicminer/hamilton/odata"
+)
+
+// ManagedDevicesClient performs operations on devices managed by Microsoft Intune.
+//
+// This client requires the DeviceManagementManagedDevices.Read.All permission to list and retrieve devices, and the
+// DeviceManagementManagedDevices.PrivilegedOperations.All permission to perform remote actions.
+type ManagedDevicesClient struct {
+	BaseClient Client
+}
+
+// NewManagedDevicesClient returns a new ManagedDevicesClient.
+func NewManagedDevicesClient(tenantId string) *ManagedDevicesClient {
+	return &ManagedDevicesClient{
+		BaseClient: NewClient(Version10, tenantId),
+	}
+}
+
+// List returns a list of ManagedDevices, optionally queried using OData. Devices can be filtered by their
+// operatingSystem and complianceState, e.g. `odata.Eq("complianceState", msgraph.ManagedDeviceComplianceStateNoncompliant)`.
+func (c *ManagedDevicesClient) List(ctx context.Context, query odata.Query) (*[]ManagedDevice, int, error) {
+	resp, status, _, err := c.BaseClient.Get(ctx, GetHttpRequestInput{
+		ConsistencyLevel: query.ConsistencyLevel,
+		DisablePaging:    query.Top > 0,
+		ValidStatusCodes: []int{http.StatusOK},
+		Uri: Uri{
+			Entity:      "/deviceManagement/managedDevices",
+			Params:      query.Values(),
+			HasTenantId: true,
+		},
+	})
+	if err != nil {
+		return nil, status, fmt.Errorf("ManagedDevicesClient.BaseClient.Get(): %w", err)
+	}
+
+	defer resp.Body.Close()
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, status, fmt.Errorf("io.ReadAll(): %v", err)
+	}
+
+	var data struct {
+		ManagedDevices []ManagedDevice `json:"value"`
+	}
+	if err := c.BaseClient.decode(respBody, &data); err != nil {
+		return nil, status, fmt.Errorf("json.Unmarshal(): %v", err)
+	}
+
+	return &data.ManagedDevices, status, nil
+}
+
+// Get retrieves a ManagedDevice.
+func (c *ManagedDevicesClient) Get(ctx context.Context, id string, query odata.Query) (*ManagedDevice, int, error) {
+	resp, status, _, err := c.BaseClient.Get(ctx, GetHttpRequestInput{
+		ConsistencyFailureFunc: RetryOn404ConsistencyFailureFunc,
+		ConsistencyLevel:       query.ConsistencyLevel,
+		ValidStatusCodes:       []int{http.StatusOK},
+		Uri: Uri{
+			Entity:      fmt.Sprintf("/deviceManagement/managedDevices/%s", id),
+			Params:      query.Values(),
+			HasTenantId: true,
+		},
+	})
+	if err != nil {
+		return nil, status, fmt.Errorf("ManagedDevicesClient.BaseClient.Get(): %w", err)
+	}
+
+	defer resp.Body.Close()
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, status, fmt.Errorf("io.ReadAll(): %v", err)
+	}
+
+	var managedDevice ManagedDevice
+	if err := c.BaseClient.decode(respBody, &managedDevice); err != nil {
+		return nil, status, fmt.Errorf("json.Unmarshal(): %v", err)
+	}
+
+	return &managedDevice, status, nil
+}
+
+// Wipe resets a ManagedDevice to its factory settings, removing all company and user data unless otherwise specified
+// in options, which may be nil.
+func (c *ManagedDevicesClient) Wipe(ctx context.Context, id string, options *ManagedDeviceWipeOptions) (int, error) {
+	var body []byte
+	if options != nil {
+		var err error
+		body, err = json.Marshal(options)
+		if err != nil {
+			return 0, fmt.Errorf("json.Marshal(): %v", err)
+		}
+	}
+	return c.action(ctx, id, "wipe", body)
+}
+
+// Retire removes company data and settings from a ManagedDevice, leaving personal data intact, and removes the device
+// from management.
+func (c *ManagedDevicesClient) Retire(ctx context.Context, id string) (int, error) {
+	return c.action(ctx, id, "retire", nil)
+}
+
+// SyncDevice requests a ManagedDevice to check in with Intune, so that pending policies and actions are applied.
+func (c *ManagedDevicesClient) SyncDevice(ctx context.Context, id string) (int, error) {
+	return c.action(ctx, id, "syncDevice", nil)
+}
+
+// action invokes a remote action on a ManagedDevice
+func (c *ManagedDevicesClient) action(ctx context.Context, id, action string, body []byte) (int, error) {
+	_, status, _, err := c.BaseClient.Post(ctx, PostHttpRequestInput{
+		Body:                   body,
+		ConsistencyFailureFunc: RetryOn404ConsistencyFailureFunc,
+		ValidStatusCodes:       []int{http.StatusNoContent},
+		Uri: Uri{
+			Entity:      fmt.Sprintf("/deviceManagement/managedDevices/%s/%s", id, action),
+			HasTenantId: true,
+		},
+	})
+	if err != nil {
+		return status, fmt.Errorf("ManagedDevicesClient.BaseClient.Post(): %w", err)
+	}
+
+	return status, nil
+}
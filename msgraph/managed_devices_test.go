@@ -0,0 +1,88 @@
+package msgraph_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/manicminer/hamilton/environments"
+	"github.com/manicminer/hamilton/internal/utils"
+	"github.com/manicminer/hamilton/msgraph"
+	"github.com/manicminer/hamilton/odata"
+)
+
+func TestManagedDevicesClient(t *testing.T) {
+	const basePath = "/v1.0/00000000-0000-0000-0000-000000000000/deviceManagement/managedDevices"
+	const device = `{"id":"11111111-1111-1111-1111-111111111111","deviceName":"DESKTOP-01","complianceState":"noncompliant","operatingSystem":"Windows","osVersion":"10.0.22621.1702","userPrincipalName":"alice@contoso.com","managedDeviceOwnerType":"company"}`
+	var filter string
+	actions := make(map[string]string)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == basePath:
+			filter = r.URL.Query().Get("$filter")
+			w.Header().Set("Content-Type", "application/json")
+			w.Write([]byte(`{"value":[` + device + `]}`))
+		case r.Method == http.MethodGet && r.URL.Path == basePath+"/11111111-1111-1111-1111-111111111111":
+			w.Header().Set("Content-Type", "application/json")
+			w.Write([]byte(device))
+		case r.Method == http.MethodPost:
+			var body map[string]interface{}
+			json.NewDecoder(r.Body).Decode(&body)
+			b, _ := json.Marshal(body)
+			actions[r.URL.Path] = string(b)
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"error":{"code":"NotFound","message":"not found"}}`))
+		}
+	}))
+	defer server.Close()
+
+	c := msgraph.NewManagedDevicesClient("00000000-0000-0000-0000-000000000000")
+	c.BaseClient.Endpoint = environments.ApiEndpoint(server.URL)
+
+	devices, _, err := c.List(context.Background(), odata.Query{
+		Filter: odata.And(odata.Eq("operatingSystem", "Windows"), odata.Eq("complianceState", msgraph.ManagedDeviceComplianceStateNoncompliant)).String(),
+	})
+	if err != nil {
+		t.Fatalf("ManagedDevicesClient.List(): %v", err)
+	}
+	if devices == nil || len(*devices) != 1 {
+		t.Fatalf("ManagedDevicesClient.List(): expected 1 device, got %v", devices)
+	}
+	if expected := "operatingSystem eq 'Windows' and complianceState eq 'noncompliant'"; filter != expected {
+		t.Fatalf("ManagedDevicesClient.List(): expected filter %q, got %q", expected, filter)
+	}
+
+	d, _, err := c.Get(context.Background(), *(*devices)[0].ID, odata.Query{})
+	if err != nil {
+		t.Fatalf("ManagedDevicesClient.Get(): %v", err)
+	}
+	if d.DeviceName == nil || *d.DeviceName != "DESKTOP-01" || d.ComplianceState == nil || *d.ComplianceState != msgraph.ManagedDeviceComplianceStateNoncompliant {
+		t.Fatalf("ManagedDevicesClient.Get(): unexpected device %+v", d)
+	}
+
+	if _, err := c.SyncDevice(context.Background(), *d.ID); err != nil {
+		t.Fatalf("ManagedDevicesClient.SyncDevice(): %v", err)
+	}
+	if _, err := c.Retire(context.Background(), *d.ID); err != nil {
+		t.Fatalf("ManagedDevicesClient.Retire(): %v", err)
+	}
+	if _, err := c.Wipe(context.Background(), *d.ID, &msgraph.ManagedDeviceWipeOptions{KeepUserData: utils.BoolPtr(true)}); err != nil {
+		t.Fatalf("ManagedDevicesClient.Wipe(): %v", err)
+	}
+
+	expected := map[string]string{
+		basePath + "/11111111-1111-1111-1111-111111111111/syncDevice": "null",
+		basePath + "/11111111-1111-1111-1111-111111111111/retire":     "null",
+		basePath + "/11111111-1111-1111-1111-111111111111/wipe":       `{"keepUserData":true}`,
+	}
+	for path, body := range expected {
+		if actions[path] != body {
+			t.Errorf("ManagedDevicesClient: expected request to %q with body %s, got %q", path, body, actions[path])
+		}
+	}
+}
@@ -27,6 +27,7 @@ func (c *MeClient) Get(ctx context.Context, query odata.Query) (*Me, int, error)
 	var status int
 
 	resp, status, _, err := c.BaseClient.Get(ctx, GetHttpRequestInput{
+		ConsistencyLevel: query.ConsistencyLevel,
 		ValidStatusCodes: []int{http.StatusOK},
 		Uri: Uri{
 			Entity:      "/me",
@@ -35,7 +36,7 @@ func (c *MeClient) Get(ctx context.Context, query odata.Query) (*Me, int, error)
 		},
 	})
 	if err != nil {
-		return nil, status, fmt.Errorf("MeClient.BaseClient.Get(): %v", err)
+		return nil, status, fmt.Errorf("MeClient.BaseClient.Get(): %w", err)
 	}
 
 	defer resp.Body.Close()
@@ -45,7 +46,7 @@ func (c *MeClient) Get(ctx context.Context, query odata.Query) (*Me, int, error)
 	}
 
 	var me Me
-	if err := json.Unmarshal(respBody, &me); err != nil {
+	if err := c.BaseClient.decode(respBody, &me); err != nil {
 		return nil, status, fmt.Errorf("json.Unmarshal(): %v", err)
 	}
 
@@ -57,6 +58,7 @@ func (c *MeClient) GetProfile(ctx context.Context, query odata.Query) (*Me, int,
 	var status int
 
 	resp, status, _, err := c.BaseClient.Get(ctx, GetHttpRequestInput{
+		ConsistencyLevel: query.ConsistencyLevel,
 		ValidStatusCodes: []int{http.StatusOK},
 		Uri: Uri{
 			Entity:      "/me/profile",
@@ -65,7 +67,7 @@ func (c *MeClient) GetProfile(ctx context.Context, query odata.Query) (*Me, int,
 		},
 	})
 	if err != nil {
-		return nil, status, fmt.Errorf("MeClient.BaseClient.Get(): %v", err)
+		return nil, status, fmt.Errorf("MeClient.BaseClient.Get(): %w", err)
 	}
 
 	defer resp.Body.Close()
@@ -75,7 +77,7 @@ func (c *MeClient) GetProfile(ctx context.Context, query odata.Query) (*Me, int,
 	}
 
 	var me Me
-	if err := json.Unmarshal(respBody, &me); err != nil {
+	if err := c.BaseClient.decode(respBody, &me); err != nil {
 		return nil, status, fmt.Errorf("json.Unmarshal(): %v", err)
 	}
 
@@ -101,8 +103,39 @@ func (c *MeClient) Sendmail(ctx context.Context, message MailMessage) (int, erro
 		},
 	})
 	if err != nil {
-		return status, fmt.Errorf("MeClient.BaseClient.Post(): %v", err)
+		return status, fmt.Errorf("MeClient.BaseClient.Post(): %w", err)
 	}
 
 	return status, nil
 }
+
+// GetPhoto retrieves the profile photo of the authenticated user, returning its content and media type. When etag is not
+// empty, the photo is only retrieved if it has changed since, otherwise ErrNotModified is returned. The ETag of the
+// current photo is available from GetPhotoMetadata().
+func (c *MeClient) GetPhoto(ctx context.Context, etag string) ([]byte, string, int, error) {
+	content, contentType, status, err := c.BaseClient.getPhoto(ctx, Uri{
+		Entity:      "/me/photo",
+		HasTenantId: false,
+	}, etag)
+	if err == ErrNotModified {
+		return nil, "", status, err
+	}
+	if err != nil {
+		return nil, "", status, fmt.Errorf("MeClient.BaseClient.Get(): %w", err)
+	}
+
+	return content, contentType, status, nil
+}
+
+// GetPhotoMetadata retrieves the ProfilePhoto of the authenticated user, including the ETag of the current photo.
+func (c *MeClient) GetPhotoMetadata(ctx context.Context) (*ProfilePhoto, int, error) {
+	photo, status, err := c.BaseClient.getPhotoMetadata(ctx, Uri{
+		Entity:      "/me/photo",
+		HasTenantId: false,
+	})
+	if err != nil {
+		return nil, status, fmt.Errorf("MeClient.BaseClient.Get(): %w", err)
+	}
+
+	return photo, status, nil
+}
@@ -0,0 +1,223 @@
+package msgraph
+
+import (
+	"context"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// MetadataClient retrieves the service metadata document, which describes the entity types and properties exposed by
+// Microsoft Graph.
+type MetadataClient struct {
+	BaseClient Client
+}
+
+// NewMetadataClient returns a new MetadataClient.
+func NewMetadataClient(tenantId string) *MetadataClient {
+	return &MetadataClient{
+		BaseClient: NewClient(Version10, tenantId),
+	}
+}
+
+// Get retrieves and parses the `$metadata` CSDL document for the configured API version. The document is large, so
+// callers should retain the result rather than retrieving it repeatedly.
+func (c *MetadataClient) Get(ctx context.Context) (*Metadata, int, error) {
+	resp, status, _, err := c.BaseClient.Get(ctx, GetHttpRequestInput{
+		ValidStatusCodes: []int{http.StatusOK},
+		Uri: Uri{
+			Entity:      "/$metadata",
+			HasTenantId: false,
+		},
+	})
+	if err != nil {
+		return nil, status, fmt.Errorf("MetadataClient.BaseClient.Get(): %w", err)
+	}
+
+	defer resp.Body.Close()
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, status, fmt.Errorf("io.ReadAll(): %v", err)
+	}
+
+	metadata, err := ParseMetadata(respBody)
+	if err != nil {
+		return nil, status, err
+	}
+
+	return metadata, status, nil
+}
+
+// ParseMetadata parses a CSDL metadata document.
+func ParseMetadata(data []byte) (*Metadata, error) {
+	var metadata Metadata
+	if err := xml.Unmarshal(data, &metadata); err != nil {
+		return nil, fmt.Errorf("xml.Unmarshal(): %v", err)
+	}
+	return &metadata, nil
+}
+
+// Metadata describes the schemas published in a CSDL metadata document.
+type Metadata struct {
+	Version string           `xml:"Version,attr"`
+	Schemas []MetadataSchema `xml:"DataServices>Schema"`
+}
+
+type MetadataSchema struct {
+	Namespace       string                   `xml:"Namespace,attr"`
+	Alias           string                   `xml:"Alias,attr"`
+	EntityTypes     []MetadataEntityType     `xml:"EntityType"`
+	ComplexTypes    []MetadataEntityType     `xml:"ComplexType"`
+	EntityContainer *MetadataEntityContainer `xml:"EntityContainer"`
+}
+
+type MetadataEntityContainer struct {
+	Name       string              `xml:"Name,attr"`
+	EntitySets []MetadataEntitySet `xml:"EntitySet"`
+	Singletons []MetadataEntitySet `xml:"Singleton"`
+}
+
+// MetadataEntitySet describes an entity set or singleton, such as `users` or `me`, and the qualified name of its
+// entity type.
+type MetadataEntitySet struct {
+	Name       string `xml:"Name,attr"`
+	EntityType string `xml:"EntityType,attr"`
+	Type       string `xml:"Type,attr"`
+}
+
+// TypeName returns the qualified name of the entity type for the entity set or singleton.
+func (s MetadataEntitySet) TypeName() string {
+	if s.EntityType != "" {
+		return s.EntityType
+	}
+	return s.Type
+}
+
+// MetadataEntityType describes an entity type or complex type. Properties inherited from the base type are not
+// included, use Metadata.Properties() to retrieve all properties of a type.
+type MetadataEntityType struct {
+	Name                 string                       `xml:"Name,attr"`
+	BaseType             string                       `xml:"BaseType,attr"`
+	Abstract             bool                         `xml:"Abstract,attr"`
+	OpenType             bool                         `xml:"OpenType,attr"`
+	Properties           []MetadataProperty           `xml:"Property"`
+	NavigationProperties []MetadataNavigationProperty `xml:"NavigationProperty"`
+}
+
+type MetadataProperty struct {
+	Name     string `xml:"Name,attr"`
+	Type     string `xml:"Type,attr"`
+	Nullable *bool  `xml:"Nullable,attr"`
+}
+
+type MetadataNavigationProperty struct {
+	Name           string `xml:"Name,attr"`
+	Type           string `xml:"Type,attr"`
+	ContainsTarget bool   `xml:"ContainsTarget,attr"`
+}
+
+// EntityType returns the entity type or complex type with the specified name, which can be qualified with the schema
+// namespace or alias (e.g. `microsoft.graph.user`) or unqualified (e.g. `user`). Returns nil when not found.
+func (m Metadata) EntityType(name string) *MetadataEntityType {
+	namespace, typeName := "", name
+	if i := strings.LastIndex(name, "."); i >= 0 {
+		namespace, typeName = name[:i], name[i+1:]
+	}
+
+	for _, schema := range m.Schemas {
+		if namespace != "" && namespace != schema.Namespace && namespace != schema.Alias {
+			continue
+		}
+		for _, types := range [][]MetadataEntityType{schema.EntityTypes, schema.ComplexTypes} {
+			for i := range types {
+				if types[i].Name == typeName {
+					return &types[i]
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// EntitySet returns the entity set or singleton with the specified name, e.g. `users`. Returns nil when not found.
+func (m Metadata) EntitySet(name string) *MetadataEntitySet {
+	for _, schema := range m.Schemas {
+		if schema.EntityContainer == nil {
+			continue
+		}
+		for _, sets := range [][]MetadataEntitySet{schema.EntityContainer.EntitySets, schema.EntityContainer.Singletons} {
+			for i := range sets {
+				if sets[i].Name == name {
+					return &sets[i]
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// Properties returns all structural properties of the named entity type or complex type, including those inherited
+// from its base types. Returns nil when the type is not found.
+func (m Metadata) Properties(typeName string) []MetadataProperty {
+	var ret []MetadataProperty
+	seen := make(map[string]bool)
+	for t := m.EntityType(typeName); t != nil && !seen[t.Name]; t = m.EntityType(t.BaseType) {
+		seen[t.Name] = true
+		ret = append(ret, t.Properties...)
+		if t.BaseType == "" {
+			break
+		}
+	}
+	return ret
+}
+
+// EntitySetProperties returns all structural properties of the entity type for the named entity set or singleton,
+// including those inherited from its base types. Returns nil when the entity set is not found.
+func (m Metadata) EntitySetProperties(name string) []MetadataProperty {
+	set := m.EntitySet(name)
+	if set == nil {
+		return nil
+	}
+	return m.Properties(set.TypeName())
+}
+
+// ValidateSelect checks that the specified properties, e.g. from an odata.Query Select field, are exposed by the
+// entity type of the named entity set or singleton. Navigation properties are accepted, as are directory extension
+// properties (which are named `extension_{appId}_{name}`) when the entity type is an open type.
+func (m Metadata) ValidateSelect(entitySet string, properties []string) error {
+	set := m.EntitySet(entitySet)
+	if set == nil {
+		return fmt.Errorf("entity set %q was not found in metadata", entitySet)
+	}
+
+	known := make(map[string]bool)
+	open := false
+	seen := make(map[string]bool)
+	for t := m.EntityType(set.TypeName()); t != nil && !seen[t.Name]; t = m.EntityType(t.BaseType) {
+		seen[t.Name] = true
+		open = open || t.OpenType
+		for _, p := range t.Properties {
+			known[strings.ToLower(p.Name)] = true
+		}
+		for _, p := range t.NavigationProperties {
+			known[strings.ToLower(p.Name)] = true
+		}
+		if t.BaseType == "" {
+			break
+		}
+	}
+
+	var unknown []string
+	for _, p := range properties {
+		if known[strings.ToLower(p)] || (open && strings.HasPrefix(p, "extension_")) {
+			continue
+		}
+		unknown = append(unknown, p)
+	}
+	if len(unknown) > 0 {
+		return fmt.Errorf("unknown properties for entity set %q: %s", entitySet, strings.Join(unknown, ", "))
+	}
+	return nil
+}
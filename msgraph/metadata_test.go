@@ -0,0 +1,87 @@
+package msgraph_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/manicminer/hamilton/environments"
+	"github.com/manicminer/hamilton/msgraph"
+)
+
+const testMetadataDocument = `<?xml version="1.0" encoding="utf-8"?>
+<edmx:Edmx Version="4.0" xmlns:edmx="http://docs.oasis-open.org/odata/ns/edmx">
+  <edmx:DataServices>
+    <Schema Namespace="microsoft.graph" Alias="graph" xmlns="http://docs.oasis-open.org/odata/ns/edm">
+      <EntityType Name="entity" Abstract="true">
+        <Key><PropertyRef Name="id" /></Key>
+        <Property Name="id" Type="Edm.String" Nullable="false" />
+      </EntityType>
+      <EntityType Name="directoryObject" BaseType="graph.entity" OpenType="true">
+        <Property Name="deletedDateTime" Type="Edm.DateTimeOffset" />
+      </EntityType>
+      <EntityType Name="user" BaseType="graph.directoryObject" OpenType="true">
+        <Property Name="department" Type="Edm.String" />
+        <Property Name="displayName" Type="Edm.String" />
+        <NavigationProperty Name="manager" Type="graph.directoryObject" />
+      </EntityType>
+      <ComplexType Name="passwordProfile">
+        <Property Name="password" Type="Edm.String" />
+      </ComplexType>
+      <EntityContainer Name="GraphService">
+        <EntitySet Name="users" EntityType="microsoft.graph.user" />
+        <Singleton Name="me" Type="microsoft.graph.user" />
+      </EntityContainer>
+    </Schema>
+  </edmx:DataServices>
+</edmx:Edmx>`
+
+func TestMetadataClient_Get(t *testing.T) {
+	var path string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path = r.URL.Path
+		w.Header().Set("Content-Type", "application/xml")
+		fmt.Fprint(w, testMetadataDocument)
+	}))
+	defer server.Close()
+
+	c := msgraph.NewMetadataClient("00000000-0000-0000-0000-000000000000")
+	c.BaseClient.Endpoint = environments.ApiEndpoint(server.URL)
+
+	metadata, _, err := c.Get(context.Background())
+	if err != nil {
+		t.Fatalf("MetadataClient.Get(): %v", err)
+	}
+	if path != "/v1.0/$metadata" {
+		t.Fatalf("MetadataClient.Get(): unexpected path: %q", path)
+	}
+	if len(metadata.Schemas) != 1 || metadata.Schemas[0].Namespace != "microsoft.graph" {
+		t.Fatalf("MetadataClient.Get(): unexpected schemas: %#v", metadata.Schemas)
+	}
+
+	if user := metadata.EntityType("microsoft.graph.user"); user == nil || user.BaseType != "graph.directoryObject" || !user.OpenType {
+		t.Fatalf("Metadata.EntityType(): unexpected entity type: %#v", user)
+	}
+	if profile := metadata.EntityType("passwordProfile"); profile == nil || len(profile.Properties) != 1 {
+		t.Fatalf("Metadata.EntityType(): unexpected complex type: %#v", profile)
+	}
+
+	for _, name := range []string{"users", "me"} {
+		properties := metadata.EntitySetProperties(name)
+		if len(properties) != 4 {
+			t.Fatalf("Metadata.EntitySetProperties(%q): expected 4 properties including inherited, got %#v", name, properties)
+		}
+	}
+
+	if err := metadata.ValidateSelect("users", []string{"id", "displayName", "manager", "extension_00000000000000000000000000000000_costCenter"}); err != nil {
+		t.Fatalf("Metadata.ValidateSelect(): unexpected error: %v", err)
+	}
+	if err := metadata.ValidateSelect("users", []string{"displayName", "nonExistent"}); err == nil {
+		t.Fatal("Metadata.ValidateSelect(): expected error for unknown property")
+	}
+	if err := metadata.ValidateSelect("widgets", []string{"id"}); err == nil {
+		t.Fatal("Metadata.ValidateSelect(): expected error for unknown entity set")
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	goerrors "errors"
 	"fmt"
+	"math"
 	"strings"
 	"time"
 
@@ -13,6 +14,106 @@ import (
 	"github.com/manicminer/hamilton/errors"
 )
 
+// AccessPackage is a collection of resources, such as group memberships and application roles, which users can
+// request access to in Entitlement Management. Access packages belong to an AccessPackageCatalog.
+type AccessPackage struct {
+	ID               *string    `json:"id,omitempty"`
+	CatalogId        *string    `json:"catalogId,omitempty"`
+	CreatedBy        *string    `json:"createdBy,omitempty"`
+	CreatedDateTime  *time.Time `json:"createdDateTime,omitempty"`
+	Description      *string    `json:"description,omitempty"`
+	DisplayName      *string    `json:"displayName,omitempty"`
+	IsHidden         *bool      `json:"isHidden,omitempty"`
+	ModifiedBy       *string    `json:"modifiedBy,omitempty"`
+	ModifiedDateTime *time.Time `json:"modifiedDateTime,omitempty"`
+}
+
+// AccessPackageAssignmentPolicy describes who can request an AccessPackage, whether approval is required, and how
+// long assignments last.
+type AccessPackageAssignmentPolicy struct {
+	ID                      *string            `json:"id,omitempty"`
+	AccessPackageId         *string            `json:"accessPackageId,omitempty"`
+	CanExtend               *bool              `json:"canExtend,omitempty"`
+	CreatedDateTime         *time.Time         `json:"createdDateTime,omitempty"`
+	Description             *string            `json:"description,omitempty"`
+	DisplayName             *string            `json:"displayName,omitempty"`
+	DurationInDays          *int32             `json:"durationInDays,omitempty"`
+	ExpirationDateTime      *time.Time         `json:"expirationDateTime,omitempty"`
+	ModifiedDateTime        *time.Time         `json:"modifiedDateTime,omitempty"`
+	RequestApprovalSettings *ApprovalSettings  `json:"requestApprovalSettings,omitempty"`
+	RequestorSettings       *RequestorSettings `json:"requestorSettings,omitempty"`
+}
+
+// AccessPackageCatalog is a container for AccessPackages and the resources they grant access to.
+type AccessPackageCatalog struct {
+	ID                  *string                     `json:"id,omitempty"`
+	CatalogStatus       *AccessPackageCatalogStatus `json:"catalogStatus,omitempty"`
+	CatalogType         *AccessPackageCatalogType   `json:"catalogType,omitempty"`
+	CreatedDateTime     *time.Time                  `json:"createdDateTime,omitempty"`
+	Description         *string                     `json:"description,omitempty"`
+	DisplayName         *string                     `json:"displayName,omitempty"`
+	IsExternallyVisible *bool                       `json:"isExternallyVisible,omitempty"`
+	ModifiedDateTime    *time.Time                  `json:"modifiedDateTime,omitempty"`
+}
+
+// AccessReviewInstance describes a single occurrence of an AccessReviewScheduleDefinition.
+type AccessReviewInstance struct {
+	ID            *string                      `json:"id,omitempty"`
+	EndDateTime   *time.Time                   `json:"endDateTime,omitempty"`
+	Reviewers     *[]AccessReviewReviewerScope `json:"reviewers,omitempty"`
+	Scope         *AccessReviewScope           `json:"scope,omitempty"`
+	StartDateTime *time.Time                   `json:"startDateTime,omitempty"`
+	Status        *string                      `json:"status,omitempty"`
+}
+
+// AccessReviewReviewerScope identifies the reviewers for an access review using a query, e.g. `/users/{id}` for a
+// specific user or `./manager` for the managers of the users being reviewed.
+type AccessReviewReviewerScope struct {
+	Query     *string `json:"query,omitempty"`
+	QueryRoot *string `json:"queryRoot,omitempty"`
+	QueryType *string `json:"queryType,omitempty"`
+}
+
+// AccessReviewScheduleDefinition describes an access review series, including what is reviewed, who performs the
+// review and how often it recurs.
+type AccessReviewScheduleDefinition struct {
+	ID                       *string                       `json:"id,omitempty"`
+	CreatedBy                *UserIdentity                 `json:"createdBy,omitempty"`
+	CreatedDateTime          *time.Time                    `json:"createdDateTime,omitempty"`
+	DescriptionForAdmins     *string                       `json:"descriptionForAdmins,omitempty"`
+	DescriptionForReviewers  *string                       `json:"descriptionForReviewers,omitempty"`
+	DisplayName              *string                       `json:"displayName,omitempty"`
+	FallbackReviewers        *[]AccessReviewReviewerScope  `json:"fallbackReviewers,omitempty"`
+	InstanceEnumerationScope *AccessReviewScope            `json:"instanceEnumerationScope,omitempty"`
+	LastModifiedDateTime     *time.Time                    `json:"lastModifiedDateTime,omitempty"`
+	Reviewers                *[]AccessReviewReviewerScope  `json:"reviewers,omitempty"`
+	Scope                    *AccessReviewScope            `json:"scope,omitempty"`
+	Settings                 *AccessReviewScheduleSettings `json:"settings,omitempty"`
+	Status                   *string                       `json:"status,omitempty"`
+}
+
+type AccessReviewScheduleSettings struct {
+	AutoApplyDecisionsEnabled       *bool                        `json:"autoApplyDecisionsEnabled,omitempty"`
+	DefaultDecision                 *AccessReviewDefaultDecision `json:"defaultDecision,omitempty"`
+	DefaultDecisionEnabled          *bool                        `json:"defaultDecisionEnabled,omitempty"`
+	InstanceDurationInDays          *int32                       `json:"instanceDurationInDays,omitempty"`
+	JustificationRequiredOnApproval *bool                        `json:"justificationRequiredOnApproval,omitempty"`
+	MailNotificationsEnabled        *bool                        `json:"mailNotificationsEnabled,omitempty"`
+	RecommendationsEnabled          *bool                        `json:"recommendationsEnabled,omitempty"`
+	Recurrence                      *PatternedRecurrence         `json:"recurrence,omitempty"`
+	ReminderNotificationsEnabled    *bool                        `json:"reminderNotificationsEnabled,omitempty"`
+}
+
+// AccessReviewScope describes the principals or resources being reviewed. For a review of group memberships, set
+// Query to `/groups/{id}/transitiveMembers` and QueryType to `MicrosoftGraph`. For a review of assignments to an
+// application, set Query to `/servicePrincipals/{id}/appRoleAssignedTo`.
+type AccessReviewScope struct {
+	ODataType *odata.Type `json:"@odata.type,omitempty"`
+	Query     *string     `json:"query,omitempty"`
+	QueryRoot *string     `json:"queryRoot,omitempty"`
+	QueryType *string     `json:"queryType,omitempty"`
+}
+
 type AddIn struct {
 	ID         *string          `json:"id,omitempty"`
 	Properties *[]AddInKeyValue `json:"properties,omitempty"`
@@ -112,6 +213,9 @@ func (a *Application) UnmarshalJSON(data []byte) error {
 	if err := json.Unmarshal(data, &app); err != nil {
 		return err
 	}
+	if err := a.recordResponseFields(data); err != nil {
+		return err
+	}
 	if app.GroupMembershipClaims != nil {
 		var groupMembershipClaims []GroupMembershipClaim
 		for _, c := range strings.Split(*app.GroupMembershipClaims, ",") {
@@ -278,6 +382,17 @@ type ApplicationEnforcedRestrictionsSessionControl struct {
 	IsEnabled *bool `json:"isEnabled,omitempty"`
 }
 
+// ApplicationExtension describes a directory extension property, which extends the schema of the TargetObjects.
+//
+// The Name of a created extension property is `extension_{appId}_{name}`, where appId is the application ID of the
+// owning application without hyphens. To set its value on a user, add it to User.AdditionalData using this name and
+// call UsersClient.Update(), for example:
+//
+//	user := msgraph.User{AdditionalData: map[string]interface{}{*extension.Name: "HR-001"}}
+//	user.ID = &userId
+//	_, err := usersClient.Update(ctx, user)
+//
+// To read values, include the name in the $select of the query, and they are populated in User.AdditionalData.
 type ApplicationExtension struct {
 	Id                     *string                             `json:"id,omitempty"`
 	AppDisplayName         *string                             `json:"appDisplayName,omitempty"`
@@ -321,6 +436,22 @@ type AppliedConditionalAccessPolicy struct {
 	Result                  *string   `json:"appliedConditionalAccessPolicyResult,omitempty"`
 }
 
+// AppManagementConfiguration describes the restrictions placed on the credentials of applications and service principals.
+type AppManagementConfiguration struct {
+	KeyCredentials      *[]KeyCredentialConfiguration      `json:"keyCredentials,omitempty"`
+	PasswordCredentials *[]PasswordCredentialConfiguration `json:"passwordCredentials,omitempty"`
+}
+
+// AppManagementPolicy restricts the credentials of the applications and service principals to which it is applied,
+// overriding the tenant default policy.
+type AppManagementPolicy struct {
+	ID           *string                     `json:"id,omitempty"`
+	Description  *string                     `json:"description,omitempty"`
+	DisplayName  *string                     `json:"displayName,omitempty"`
+	IsEnabled    *bool                       `json:"isEnabled,omitempty"`
+	Restrictions *AppManagementConfiguration `json:"restrictions,omitempty"`
+}
+
 type AppRole struct {
 	ID                 *string                     `json:"id,omitempty"`
 	AllowedMemberTypes *[]AppRoleAllowedMemberType `json:"allowedMemberTypes,omitempty"`
@@ -343,6 +474,29 @@ type AppRoleAssignment struct {
 	ResourceId           *string    `json:"resourceId,omitempty"`
 }
 
+type ApprovalSettings struct {
+	ApprovalMode                     *ApprovalMode `json:"approvalMode,omitempty"`
+	IsApprovalRequired               *bool         `json:"isApprovalRequired,omitempty"`
+	IsApprovalRequiredForExtension   *bool         `json:"isApprovalRequiredForExtension,omitempty"`
+	IsRequestorJustificationRequired *bool         `json:"isRequestorJustificationRequired,omitempty"`
+}
+
+type AssignedPlan struct {
+	AssignedDateTime *time.Time `json:"assignedDateTime,omitempty"`
+	CapabilityStatus *string    `json:"capabilityStatus,omitempty"`
+	Service          *string    `json:"service,omitempty"`
+	ServicePlanId    *string    `json:"servicePlanId,omitempty"`
+}
+
+// AttributeDefinition describes an attribute of an object in a SynchronizationSchema directory.
+type AttributeDefinition struct {
+	Anchor      *bool   `json:"anchor,omitempty"`
+	Multivalued *bool   `json:"multivalued,omitempty"`
+	Name        *string `json:"name,omitempty"`
+	Required    *bool   `json:"required,omitempty"`
+	Type        *string `json:"type,omitempty"`
+}
+
 type AuditActivityInitiator struct {
 	App  *AppIdentity  `json:"app,omitempty"`
 	User *UserIdentity `json:"user,omitempty"`
@@ -350,6 +504,64 @@ type AuditActivityInitiator struct {
 
 type AuthenticationMethod interface{}
 
+// AuthenticationMethodConfiguration is one of the method configurations of an AuthenticationMethodsPolicy, which can
+// be type asserted to Fido2AuthenticationMethodConfiguration, MicrosoftAuthenticatorAuthenticationMethodConfiguration
+// or SmsAuthenticationMethodConfiguration. Configurations of other methods are BaseAuthenticationMethodConfiguration.
+type AuthenticationMethodConfiguration interface{}
+
+// AuthenticationMethodsPolicy defines the authentication methods that users in a tenant can register and use.
+type AuthenticationMethodsPolicy struct {
+	ID                                 *string                              `json:"id,omitempty"`
+	DisplayName                        *string                              `json:"displayName,omitempty"`
+	Description                        *string                              `json:"description,omitempty"`
+	LastModifiedDateTime               *time.Time                           `json:"lastModifiedDateTime,omitempty"`
+	PolicyVersion                      *string                              `json:"policyVersion,omitempty"`
+	ReconfirmationInDays               *int                                 `json:"reconfirmationInDays,omitempty"`
+	AuthenticationMethodConfigurations *[]AuthenticationMethodConfiguration `json:"authenticationMethodConfigurations,omitempty"`
+}
+
+func (p *AuthenticationMethodsPolicy) UnmarshalJSON(data []byte) error {
+	// Local type needed to avoid recursive UnmarshalJSON calls
+	type policy AuthenticationMethodsPolicy
+	var p2 struct {
+		*policy
+		AuthenticationMethodConfigurations *[]json.RawMessage `json:"authenticationMethodConfigurations"`
+	}
+	p2.policy = (*policy)(p)
+	if err := json.Unmarshal(data, &p2); err != nil {
+		return err
+	}
+	p.AuthenticationMethodConfigurations = nil
+	if p2.AuthenticationMethodConfigurations != nil {
+		configs := make([]AuthenticationMethodConfiguration, 0, len(*p2.AuthenticationMethodConfigurations))
+		for _, raw := range *p2.AuthenticationMethodConfigurations {
+			config, err := unmarshalAuthenticationMethodConfiguration(raw)
+			if err != nil {
+				return err
+			}
+			configs = append(configs, config)
+		}
+		p.AuthenticationMethodConfigurations = &configs
+	}
+	return nil
+}
+
+// AuthenticationMethodTarget is a user or group for which an authentication method is enabled.
+type AuthenticationMethodTarget struct {
+	ID                     *string                                   `json:"id,omitempty"`
+	TargetType             *AuthenticationMethodTargetType           `json:"targetType,omitempty"`
+	IsRegistrationRequired *bool                                     `json:"isRegistrationRequired,omitempty"`
+	AuthenticationMode     *MicrosoftAuthenticatorAuthenticationMode `json:"authenticationMode,omitempty"`
+	IsUsableForSignIn      *bool                                     `json:"isUsableForSignIn,omitempty"`
+}
+
+type BaseAuthenticationMethodConfiguration struct {
+	ODataType      *odata.Type                `json:"@odata.type,omitempty"`
+	ID             *string                    `json:"id,omitempty"`
+	State          *AuthenticationMethodState `json:"state,omitempty"`
+	ExcludeTargets *[]ExcludeTarget           `json:"excludeTargets,omitempty"`
+}
+
 type BaseNamedLocation struct {
 	ODataType        *odata.Type `json:"@odata.type,omitempty"`
 	ID               *string     `json:"id,omitempty"`
@@ -358,6 +570,50 @@ type BaseNamedLocation struct {
 	ModifiedDateTime *time.Time  `json:"modifiedDateTime,omitempty"`
 }
 
+// BatchDeleteFailure describes an object which could not be deleted by BatchClient.DeleteMany().
+type BatchDeleteFailure struct {
+	// Ref is the relative URL of the object.
+	Ref string
+
+	// Status is the HTTP status of the delete request, which is zero if no response was received for it.
+	Status int
+
+	// Error contains the error returned by Microsoft Graph, if any.
+	Error *odata.Error
+}
+
+// BatchDeleteResult summarizes the outcome of BatchClient.DeleteMany().
+type BatchDeleteResult struct {
+	// Deleted is the number of objects which were deleted.
+	Deleted int
+
+	// NotFound is the number of objects which did not exist, and are assumed to have already been deleted.
+	NotFound int
+
+	// Failures describes the objects which could not be deleted.
+	Failures []BatchDeleteFailure
+}
+
+// BatchRequest describes an individual request to be sent as part of a JSON batch.
+type BatchRequest struct {
+	// ID uniquely identifies the request within the batch, and is used to correlate the corresponding BatchResponse.
+	ID string `json:"id"`
+
+	Method    string            `json:"method"`
+	Url       string            `json:"url"`
+	Body      json.RawMessage   `json:"body,omitempty"`
+	DependsOn []string          `json:"dependsOn,omitempty"`
+	Headers   map[string]string `json:"headers,omitempty"`
+}
+
+// BatchResponse describes the response to an individual request which was sent as part of a JSON batch.
+type BatchResponse struct {
+	ID      string            `json:"id"`
+	Status  int               `json:"status"`
+	Body    json.RawMessage   `json:"body,omitempty"`
+	Headers map[string]string `json:"headers,omitempty"`
+}
+
 type CloudAppSecurityControl struct {
 	IsEnabled            *bool   `json:"isEnabled,omitempty"`
 	CloudAppSecurityType *string `json:"cloudAppSecurityType,omitempty"`
@@ -431,6 +687,69 @@ type CountryNamedLocation struct {
 	IncludeUnknownCountriesAndRegions *bool     `json:"includeUnknownCountriesAndRegions,omitempty"`
 }
 
+// CredentialReport describes the status of the credentials belonging to an application or service principal.
+type CredentialReport struct {
+	ID          *string
+	AppId       *string
+	DisplayName *string
+	Credentials []CredentialStatus
+}
+
+// Expiring returns the credentials which have expired or which will expire within the specified number of days.
+func (r CredentialReport) Expiring(days int) []CredentialStatus {
+	ret := make([]CredentialStatus, 0)
+	for _, s := range r.Credentials {
+		if s.EndDateTime != nil && s.DaysUntilExpiry <= days {
+			ret = append(ret, s)
+		}
+	}
+	return ret
+}
+
+// CredentialStatus describes the expiry of a single key or password credential.
+// DaysUntilExpiry is negative for credentials which have already expired.
+type CredentialStatus struct {
+	KeyId           *string
+	DisplayName     *string
+	Type            CredentialStatusType
+	EndDateTime     *time.Time
+	DaysUntilExpiry int
+}
+
+// Expired returns true if the credential has expired.
+func (s CredentialStatus) Expired() bool {
+	return s.EndDateTime != nil && s.DaysUntilExpiry < 0
+}
+
+// CredentialStatuses computes the status of the provided key and password credentials, relative to the specified time.
+func CredentialStatuses(keyCredentials *[]KeyCredential, passwordCredentials *[]PasswordCredential, now time.Time) []CredentialStatus {
+	ret := make([]CredentialStatus, 0)
+	if keyCredentials != nil {
+		for _, k := range *keyCredentials {
+			ret = append(ret, newCredentialStatus(k.KeyId, k.DisplayName, CredentialStatusTypeCertificate, k.EndDateTime, now))
+		}
+	}
+	if passwordCredentials != nil {
+		for _, p := range *passwordCredentials {
+			ret = append(ret, newCredentialStatus(p.KeyId, p.DisplayName, CredentialStatusTypePassword, p.EndDateTime, now))
+		}
+	}
+	return ret
+}
+
+func newCredentialStatus(keyId, displayName *string, credentialType CredentialStatusType, endDateTime *time.Time, now time.Time) CredentialStatus {
+	s := CredentialStatus{
+		KeyId:       keyId,
+		DisplayName: displayName,
+		Type:        credentialType,
+		EndDateTime: endDateTime,
+	}
+	if endDateTime != nil {
+		s.DaysUntilExpiry = int(math.Floor(endDateTime.Sub(now).Hours() / 24))
+	}
+	return s
+}
+
 type CredentialUserRegistrationCount struct {
 	ID                     *string                  `json:"id,omitempty"`
 	TotalUserCount         *int64                   `json:"totalUserCount,omitempty"`
@@ -455,6 +774,56 @@ type CredentialUserRegistrationDetails struct {
 	UserPrincipalName *string                   `json:"UserPrincipalName,omitempty"`
 }
 
+// DelegatedAdminAccessDetails describes the Azure AD roles granted to the partner by a DelegatedAdminRelationship.
+type DelegatedAdminAccessDetails struct {
+	UnifiedRoles *[]UnifiedRole `json:"unifiedRoles,omitempty"`
+}
+
+// DelegatedAdminRelationship is a granular delegated admin privileges (GDAP) relationship between a partner and a
+// customer tenant.
+type DelegatedAdminRelationship struct {
+	ID                   *string                             `json:"id,omitempty"`
+	AccessDetails        *DelegatedAdminAccessDetails        `json:"accessDetails,omitempty"`
+	ActivatedDateTime    *time.Time                          `json:"activatedDateTime,omitempty"`
+	CreatedDateTime      *time.Time                          `json:"createdDateTime,omitempty"`
+	Customer             *DelegatedAdminRelationshipCustomer `json:"customer,omitempty"`
+	DisplayName          *string                             `json:"displayName,omitempty"`
+	Duration             *string                             `json:"duration,omitempty"`
+	EndDateTime          *time.Time                          `json:"endDateTime,omitempty"`
+	LastModifiedDateTime *time.Time                          `json:"lastModifiedDateTime,omitempty"`
+	Status               *DelegatedAdminRelationshipStatus   `json:"status,omitempty"`
+}
+
+type DelegatedAdminRelationshipCustomer struct {
+	DisplayName *string `json:"displayName,omitempty"`
+	TenantId    *string `json:"tenantId,omitempty"`
+}
+
+// DeletedItem describes a soft-deleted directory object, which can be type asserted back to an Application, Group,
+// ServicePrincipal or User. Any other object types are returned as a DirectoryObject.
+type DeletedItem interface{}
+
+// DeletedItemsRestoreFailure describes a deleted object which could not be restored by DeletedItemsClient.RestoreMany().
+type DeletedItemsRestoreFailure struct {
+	// ID is the object ID of the deleted object.
+	ID string
+
+	// Status is the HTTP status of the restore request, which is zero if no response was received for it.
+	Status int
+
+	// Error contains the error returned by Microsoft Graph, if any.
+	Error *odata.Error
+}
+
+// DeletedItemsRestoreResult describes the outcome of DeletedItemsClient.RestoreMany().
+type DeletedItemsRestoreResult struct {
+	// Restored contains the restored objects, which can be type asserted back to the appropriate models.
+	Restored []DeletedItem
+
+	// Failures describes the objects which could not be restored.
+	Failures []DeletedItemsRestoreFailure
+}
+
 type DeviceDetail struct {
 	Browser         *string `json:"browser,omitempty"`
 	DeviceId        *string `json:"deviceId,omitempty"`
@@ -465,6 +834,18 @@ type DeviceDetail struct {
 	TrustType       *string `json:"trustType,omitempty"`
 }
 
+// DirectoryDefinition describes a directory, and the objects it contains, which takes part in synchronization.
+type DirectoryDefinition struct {
+	ID      *string             `json:"id,omitempty"`
+	Name    *string             `json:"name,omitempty"`
+	Objects *[]ObjectDefinition `json:"objects,omitempty"`
+	Version *string             `json:"version,omitempty"`
+}
+
+// DirectReport is a user or organizational contact who reports to a user, which can be type asserted back to the
+// appropriate model. Organizational contacts are returned as a DirectoryObject.
+type DirectReport interface{}
+
 type DirectoryAudit struct {
 	ActivityDateTime    *time.Time              `json:"activityDateTime,omitempty"`
 	ActivityDisplayName *string                 `json:"activityDisplayName,omitempty"`
@@ -483,6 +864,8 @@ type DirectoryObject struct {
 	ODataId   *odata.Id   `json:"@odata.id,omitempty"`
 	ODataType *odata.Type `json:"@odata.type,omitempty"`
 	ID        *string     `json:"id,omitempty"`
+
+	responseFields map[string]struct{}
 }
 
 func (o *DirectoryObject) Uri(endpoint environments.ApiEndpoint, apiVersion ApiVersion) string {
@@ -492,6 +875,31 @@ func (o *DirectoryObject) Uri(endpoint environments.ApiEndpoint, apiVersion ApiV
 	return fmt.Sprintf("%s/%s/directoryObjects/%s", endpoint, apiVersion, *o.ID)
 }
 
+// WasSelected returns true if the named property was present in the API response from which the object was unmarshaled.
+// When an object is retrieved using an OData $select query, properties which were not selected are left as nil, which
+// is otherwise indistinguishable from properties that were returned empty. Objects which were not unmarshaled from an
+// API response report all properties as selected.
+func (o DirectoryObject) WasSelected(property string) bool {
+	if o.responseFields == nil {
+		return true
+	}
+	_, ok := o.responseFields[property]
+	return ok
+}
+
+// recordResponseFields captures the names of the top-level properties present in an API response, for WasSelected.
+func (o *DirectoryObject) recordResponseFields(data []byte) error {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return err
+	}
+	o.responseFields = make(map[string]struct{}, len(fields))
+	for k := range fields {
+		o.responseFields[k] = struct{}{}
+	}
+	return nil
+}
+
 type DirectoryRole struct {
 	DirectoryObject
 	Members *Members `json:"-"`
@@ -508,9 +916,73 @@ func (r *DirectoryRole) UnmarshalJSON(data []byte) error {
 	if err := json.Unmarshal(data, r2); err != nil {
 		return err
 	}
+	if err := r.recordResponseFields(data); err != nil {
+		return err
+	}
+	return nil
+}
+
+// DirectorySetting describes a tenant-wide or group-specific directory setting, created from a DirectorySettingTemplate.
+type DirectorySetting struct {
+	ID          *string         `json:"id,omitempty"`
+	DisplayName *string         `json:"displayName,omitempty"`
+	TemplateId  *string         `json:"templateId,omitempty"`
+	Values      *[]SettingValue `json:"values,omitempty"`
+}
+
+// GetValue returns the value of the named setting, or nil if it is not present.
+func (s *DirectorySetting) GetValue(name string) *string {
+	if s.Values == nil {
+		return nil
+	}
+	for _, v := range *s.Values {
+		if v.Name != nil && *v.Name == name {
+			return v.Value
+		}
+	}
 	return nil
 }
 
+// SetValue sets the value of the named setting, adding it if it is not already present.
+func (s *DirectorySetting) SetValue(name, value string) {
+	if s.Values == nil {
+		s.Values = &[]SettingValue{}
+	}
+	for i, v := range *s.Values {
+		if v.Name != nil && *v.Name == name {
+			(*s.Values)[i].Value = &value
+			return
+		}
+	}
+	*s.Values = append(*s.Values, SettingValue{Name: &name, Value: &value})
+}
+
+// DirectorySettingTemplate describes a template from which DirectorySettings can be created.
+type DirectorySettingTemplate struct {
+	ID              *string                 `json:"id,omitempty"`
+	DeletedDateTime *time.Time              `json:"deletedDateTime,omitempty"`
+	Description     *string                 `json:"description,omitempty"`
+	DisplayName     *string                 `json:"displayName,omitempty"`
+	Values          *[]SettingTemplateValue `json:"values,omitempty"`
+}
+
+// NewSetting returns a DirectorySetting based on this template, populated with the default values for each setting.
+func (t DirectorySettingTemplate) NewSetting() DirectorySetting {
+	setting := DirectorySetting{
+		TemplateId: t.ID,
+		Values:     &[]SettingValue{},
+	}
+	if t.Values != nil {
+		for _, v := range *t.Values {
+			*setting.Values = append(*setting.Values, SettingValue{
+				Name:  v.Name,
+				Value: v.DefaultValue,
+			})
+		}
+	}
+	return setting
+}
+
 // DirectoryRoleTemplate describes a Directory Role Template.
 type DirectoryRoleTemplate struct {
 	ID              *string    `json:"id,omitempty"`
@@ -541,6 +1013,60 @@ type DomainState struct {
 	Status             *string    `json:"status,omitempty"`
 }
 
+// EducationClass is a class within a school, whose members are students and teachers.
+type EducationClass struct {
+	ID                   *string                  `json:"id,omitempty"`
+	DisplayName          *string                  `json:"displayName,omitempty"`
+	Description          *string                  `json:"description,omitempty"`
+	MailNickname         *string                  `json:"mailNickname,omitempty"`
+	ClassCode            *string                  `json:"classCode,omitempty"`
+	ExternalId           *string                  `json:"externalId,omitempty"`
+	ExternalName         *string                  `json:"externalName,omitempty"`
+	ExternalSource       *EducationExternalSource `json:"externalSource,omitempty"`
+	ExternalSourceDetail *string                  `json:"externalSourceDetail,omitempty"`
+	Grade                *string                  `json:"grade,omitempty"`
+	Term                 *EducationTerm           `json:"term,omitempty"`
+}
+
+// EducationSchool is a school in an education tenant, which contains users and classes.
+type EducationSchool struct {
+	ID                   *string                  `json:"id,omitempty"`
+	DisplayName          *string                  `json:"displayName,omitempty"`
+	Description          *string                  `json:"description,omitempty"`
+	ExternalId           *string                  `json:"externalId,omitempty"`
+	ExternalPrincipalId  *string                  `json:"externalPrincipalId,omitempty"`
+	ExternalSource       *EducationExternalSource `json:"externalSource,omitempty"`
+	ExternalSourceDetail *string                  `json:"externalSourceDetail,omitempty"`
+	SchoolNumber         *string                  `json:"schoolNumber,omitempty"`
+	PrincipalName        *string                  `json:"principalName,omitempty"`
+	PrincipalEmail       *string                  `json:"principalEmail,omitempty"`
+	HighestGrade         *string                  `json:"highestGrade,omitempty"`
+	LowestGrade          *string                  `json:"lowestGrade,omitempty"`
+	Phone                *string                  `json:"phone,omitempty"`
+}
+
+// EducationTerm describes the term of an EducationClass.
+type EducationTerm struct {
+	DisplayName *string `json:"displayName,omitempty"`
+	ExternalId  *string `json:"externalId,omitempty"`
+	StartDate   *string `json:"startDate,omitempty"`
+	EndDate     *string `json:"endDate,omitempty"`
+}
+
+// EducationUser is a student, teacher or other user in an education tenant.
+type EducationUser struct {
+	ID                *string                  `json:"id,omitempty"`
+	AccountEnabled    *bool                    `json:"accountEnabled,omitempty"`
+	DisplayName       *string                  `json:"displayName,omitempty"`
+	GivenName         *string                  `json:"givenName,omitempty"`
+	Surname           *string                  `json:"surname,omitempty"`
+	Mail              *string                  `json:"mail,omitempty"`
+	MailNickname      *string                  `json:"mailNickname,omitempty"`
+	UserPrincipalName *string                  `json:"userPrincipalName,omitempty"`
+	PrimaryRole       *EducationUserRole       `json:"primaryRole,omitempty"`
+	ExternalSource    *EducationExternalSource `json:"externalSource,omitempty"`
+}
+
 type EmailAddress struct {
 	Address *string `json:"address,omitempty"`
 	Name    *string `json:"name,omitempty"`
@@ -551,6 +1077,13 @@ type EmailAuthenticationMethod struct {
 	EmailAddress *string `json:"emailAddress,omitempty"`
 }
 
+// ExcludeTarget is a user or group for which an authentication method is disabled.
+type ExcludeTarget struct {
+	ID         *string                         `json:"id,omitempty"`
+	TargetType *AuthenticationMethodTargetType `json:"targetType,omitempty"`
+}
+
+// ExtensionSchemaProperty defines a property of a SchemaExtension.
 type ExtensionSchemaProperty struct {
 	Name *string                         `json:"name,omitempty"`
 	Type ExtensionSchemaPropertyDataType `json:"type,omitempty"`
@@ -566,6 +1099,14 @@ type Fido2AuthenticationMethod struct {
 	AttestationLevel        *AttestationLevel `json:"attestationLevel,omitempty"`
 }
 
+// Fido2AuthenticationMethodConfiguration configures the use of FIDO2 security keys in an AuthenticationMethodsPolicy.
+type Fido2AuthenticationMethodConfiguration struct {
+	*BaseAuthenticationMethodConfiguration
+	IsAttestationEnforced            *bool                         `json:"isAttestationEnforced,omitempty"`
+	IsSelfServiceRegistrationAllowed *bool                         `json:"isSelfServiceRegistrationAllowed,omitempty"`
+	IncludeTargets                   *[]AuthenticationMethodTarget `json:"includeTargets,omitempty"`
+}
+
 type GeoCoordinates struct {
 	Altitude  *float64 `json:"altitude,omitempty"`
 	Latitude  *float64 `json:"latitude,omitempty"`
@@ -649,6 +1190,9 @@ func (g *Group) UnmarshalJSON(data []byte) error {
 	if err := json.Unmarshal(data, g2); err != nil {
 		return err
 	}
+	if err := g.recordResponseFields(data); err != nil {
+		return err
+	}
 	if g.SchemaExtensions != nil {
 		var fields map[string]json.RawMessage
 		if err := json.Unmarshal(data, &fields); err != nil {
@@ -665,6 +1209,11 @@ func (g *Group) UnmarshalJSON(data []byte) error {
 	return nil
 }
 
+// isDynamicProperty indicates whether a property holds the data for one of the specified SchemaExtensions
+func (g *Group) isDynamicProperty(name string) bool {
+	return isSchemaExtensionProperty(g.SchemaExtensions, name)
+}
+
 // HasTypes returns true if the group has all the specified GroupTypes
 func (g *Group) HasTypes(types []GroupType) bool {
 	for _, t := range types {
@@ -692,6 +1241,37 @@ type GroupAssignedLicense struct {
 	SkuId         *string   `json:"skuId,omitempty"`
 }
 
+// GroupMembersReconcileFailure describes a member which could not be added or removed by
+// GroupsClient.ReconcileMembers().
+type GroupMembersReconcileFailure struct {
+	// MemberId is the object ID of the member.
+	MemberId string
+
+	// Removal is true when the member could not be removed, or false when it could not be added.
+	Removal bool
+
+	// Status is the HTTP status of the request, which is zero if no response was received for it.
+	Status int
+
+	// Error contains the error returned by Microsoft Graph, if any.
+	Error *odata.Error
+}
+
+// GroupMembersReconcileResult describes the outcome of GroupsClient.ReconcileMembers().
+type GroupMembersReconcileResult struct {
+	// Added is the number of members which were added.
+	Added int
+
+	// Removed is the number of members which were removed.
+	Removed int
+
+	// Retained contains the object IDs of members which were not removed because they are the last owner of the group.
+	Retained []string
+
+	// Failures describes the members which could not be added or removed.
+	Failures []GroupMembersReconcileFailure
+}
+
 type GroupOnPremisesProvisioningError struct {
 	Category             *string   `json:"category,omitempty"`
 	OccurredDateTime     time.Time `json:"occurredDateTime,omitempty"`
@@ -775,6 +1355,14 @@ type KeyCredential struct {
 	Key                 *string            `json:"key,omitempty"`
 }
 
+// KeyCredentialConfiguration restricts the key (certificate) credentials of applications and service principals.
+// MaxLifetime is an ISO 8601 duration, e.g. `P90D`, and applies to lifetime restrictions.
+type KeyCredentialConfiguration struct {
+	MaxLifetime                         *string                      `json:"maxLifetime,omitempty"`
+	RestrictForAppsCreatedAfterDateTime *time.Time                   `json:"restrictForAppsCreatedAfterDateTime,omitempty"`
+	RestrictionType                     *AppKeyCredentialRestriction `json:"restrictionType,omitempty"`
+}
+
 type KeyValue struct {
 	Key   *string `json:"key,omitempty"`
 	Value *string `json:"value,omitempty"`
@@ -791,6 +1379,36 @@ type MailMessage struct {
 	Message *Message `json:"message,omitempty"`
 }
 
+// ManagedDevice describes a device which is managed by Microsoft Intune.
+type ManagedDevice struct {
+	ID                     *string                       `json:"id,omitempty"`
+	AzureADDeviceId        *string                       `json:"azureADDeviceId,omitempty"`
+	ComplianceState        *ManagedDeviceComplianceState `json:"complianceState,omitempty"`
+	DeviceName             *string                       `json:"deviceName,omitempty"`
+	EmailAddress           *string                       `json:"emailAddress,omitempty"`
+	EnrolledDateTime       *time.Time                    `json:"enrolledDateTime,omitempty"`
+	IsEncrypted            *bool                         `json:"isEncrypted,omitempty"`
+	IsSupervised           *bool                         `json:"isSupervised,omitempty"`
+	LastSyncDateTime       *time.Time                    `json:"lastSyncDateTime,omitempty"`
+	ManagedDeviceOwnerType *ManagedDeviceOwnerType       `json:"managedDeviceOwnerType,omitempty"`
+	Manufacturer           *string                       `json:"manufacturer,omitempty"`
+	Model                  *string                       `json:"model,omitempty"`
+	OperatingSystem        *string                       `json:"operatingSystem,omitempty"`
+	OsVersion              *string                       `json:"osVersion,omitempty"`
+	SerialNumber           *string                       `json:"serialNumber,omitempty"`
+	UserDisplayName        *string                       `json:"userDisplayName,omitempty"`
+	UserId                 *string                       `json:"userId,omitempty"`
+	UserPrincipalName      *string                       `json:"userPrincipalName,omitempty"`
+}
+
+// ManagedDeviceWipeOptions specifies which data is retained when wiping a ManagedDevice.
+type ManagedDeviceWipeOptions struct {
+	KeepEnrollmentData  *bool   `json:"keepEnrollmentData,omitempty"`
+	KeepUserData        *bool   `json:"keepUserData,omitempty"`
+	MacOsUnlockCode     *string `json:"macOsUnlockCode,omitempty"`
+	PersistEsimDataPlan *bool   `json:"persistEsimDataPlan,omitempty"`
+}
+
 // Me describes the authenticated user.
 type Me struct {
 	ID                *string `json:"id"`
@@ -808,6 +1426,14 @@ type Message struct {
 	BccRecipients *[]Recipient `json:"bccRecipients,omitempty"`
 }
 
+// Manager is a user or organizational contact who manages a user, which can be type asserted back to the appropriate
+// model. Organizational contacts are returned as a DirectoryObject.
+type Manager interface{}
+
+// Membership is a group, directory role or administrative unit of which a directory object is a member, which can be
+// type asserted back to the appropriate model. Administrative units are returned as a DirectoryObject.
+type Membership interface{}
+
 type MicrosoftAuthenticatorAuthenticationMethod struct {
 	CreatedDateTime *time.Time `json:"createdDateTime,omitempty"`
 	DisplayName     *string    `json:"displayName,omitempty"`
@@ -816,6 +1442,14 @@ type MicrosoftAuthenticatorAuthenticationMethod struct {
 	PhoneAppVersion *string    `json:"phoneAppVersion,omitempty"`
 }
 
+// MicrosoftAuthenticatorAuthenticationMethodConfiguration configures the use of the Microsoft Authenticator app in an
+// AuthenticationMethodsPolicy.
+type MicrosoftAuthenticatorAuthenticationMethodConfiguration struct {
+	*BaseAuthenticationMethodConfiguration
+	IsSoftwareOathEnabled *bool                         `json:"isSoftwareOathEnabled,omitempty"`
+	IncludeTargets        *[]AuthenticationMethodTarget `json:"includeTargets,omitempty"`
+}
+
 type ModifiedProperty struct {
 	DisplayName *string `json:"displayName,omitempty"`
 	NewValue    *string `json:"newValue,omitempty"`
@@ -824,6 +1458,43 @@ type ModifiedProperty struct {
 
 type NamedLocation interface{}
 
+// OAuth2PermissionGrant describes delegated permissions granted to a client service principal for a resource service principal.
+type OAuth2PermissionGrant struct {
+	ID          *string                           `json:"id,omitempty"`
+	ClientId    *string                           `json:"clientId,omitempty"`
+	ConsentType *OAuth2PermissionGrantConsentType `json:"consentType,omitempty"`
+	PrincipalId *string                           `json:"principalId,omitempty"`
+	ResourceId  *string                           `json:"resourceId,omitempty"`
+	Scope       *string                           `json:"scope,omitempty"`
+}
+
+// ObjectDefinition describes a type of object in a DirectoryDefinition.
+type ObjectDefinition struct {
+	Attributes    *[]AttributeDefinition `json:"attributes,omitempty"`
+	Name          *string                `json:"name,omitempty"`
+	SupportedApis *[]string              `json:"supportedApis,omitempty"`
+}
+
+// OnPremisesExtensionAttributes contains extension attributes 1-15 for a User, which are synchronized from
+// on-premises Active Directory or, for cloud-only users, can be set directly.
+type OnPremisesExtensionAttributes struct {
+	ExtensionAttribute1  *string `json:"extensionAttribute1,omitempty"`
+	ExtensionAttribute2  *string `json:"extensionAttribute2,omitempty"`
+	ExtensionAttribute3  *string `json:"extensionAttribute3,omitempty"`
+	ExtensionAttribute4  *string `json:"extensionAttribute4,omitempty"`
+	ExtensionAttribute5  *string `json:"extensionAttribute5,omitempty"`
+	ExtensionAttribute6  *string `json:"extensionAttribute6,omitempty"`
+	ExtensionAttribute7  *string `json:"extensionAttribute7,omitempty"`
+	ExtensionAttribute8  *string `json:"extensionAttribute8,omitempty"`
+	ExtensionAttribute9  *string `json:"extensionAttribute9,omitempty"`
+	ExtensionAttribute10 *string `json:"extensionAttribute10,omitempty"`
+	ExtensionAttribute11 *string `json:"extensionAttribute11,omitempty"`
+	ExtensionAttribute12 *string `json:"extensionAttribute12,omitempty"`
+	ExtensionAttribute13 *string `json:"extensionAttribute13,omitempty"`
+	ExtensionAttribute14 *string `json:"extensionAttribute14,omitempty"`
+	ExtensionAttribute15 *string `json:"extensionAttribute15,omitempty"`
+}
+
 type OnPremisesPublishing struct {
 	AlternateUrl                  *string `json:"alternateUrl,omitempty"`
 	ApplicationServerTimeout      *string `json:"applicationServerTimeout,omitempty"`
@@ -870,6 +1541,86 @@ type OptionalClaims struct {
 	Saml2Token  *[]OptionalClaim `json:"saml2Token,omitempty"`
 }
 
+// Organization describes the tenant in which the calling application or user is registered.
+type Organization struct {
+	ID                                   *string            `json:"id,omitempty"`
+	AssignedPlans                        *[]AssignedPlan    `json:"assignedPlans,omitempty"`
+	BusinessPhones                       *[]string          `json:"businessPhones,omitempty"`
+	City                                 *string            `json:"city,omitempty"`
+	Country                              *string            `json:"country,omitempty"`
+	CountryLetterCode                    *string            `json:"countryLetterCode,omitempty"`
+	CreatedDateTime                      *time.Time         `json:"createdDateTime,omitempty"`
+	DisplayName                          *string            `json:"displayName,omitempty"`
+	MarketingNotificationEmails          *[]string          `json:"marketingNotificationEmails,omitempty"`
+	OnPremisesLastSyncDateTime           *time.Time         `json:"onPremisesLastSyncDateTime,omitempty"`
+	OnPremisesSyncEnabled                *bool              `json:"onPremisesSyncEnabled,omitempty"`
+	PostalCode                           *string            `json:"postalCode,omitempty"`
+	PreferredLanguage                    *string            `json:"preferredLanguage,omitempty"`
+	PrivacyProfile                       *PrivacyProfile    `json:"privacyProfile,omitempty"`
+	ProvisionedPlans                     *[]ProvisionedPlan `json:"provisionedPlans,omitempty"`
+	SecurityComplianceNotificationMails  *[]string          `json:"securityComplianceNotificationMails,omitempty"`
+	SecurityComplianceNotificationPhones *[]string          `json:"securityComplianceNotificationPhones,omitempty"`
+	State                                *string            `json:"state,omitempty"`
+	Street                               *string            `json:"street,omitempty"`
+	TechnicalNotificationMails           *[]string          `json:"technicalNotificationMails,omitempty"`
+	TenantType                           *string            `json:"tenantType,omitempty"`
+	VerifiedDomains                      *[]VerifiedDomain  `json:"verifiedDomains,omitempty"`
+}
+
+// DefaultDomain returns the default verified domain for the tenant, or nil if it is not known.
+func (o *Organization) DefaultDomain() *VerifiedDomain {
+	if o.VerifiedDomains == nil {
+		return nil
+	}
+	for _, d := range *o.VerifiedDomains {
+		if d.IsDefault != nil && *d.IsDefault {
+			d := d
+			return &d
+		}
+	}
+	return nil
+}
+
+// OrganizationalBranding describes the default sign-in page branding for a tenant. Images are not included and are
+// managed using OrganizationalBrandingClient.GetImage and OrganizationalBrandingClient.UploadImage.
+type OrganizationalBranding struct {
+	OrganizationalBrandingProperties
+}
+
+// OrganizationalBrandingLocalization describes the sign-in page branding for a specific locale, which overrides the
+// default branding for users whose browser requests that locale. The ID is the locale, e.g. `fr-FR`.
+type OrganizationalBrandingLocalization struct {
+	OrganizationalBrandingProperties
+}
+
+// OrganizationalBrandingProperties holds the properties shared by the default branding and its localizations.
+type OrganizationalBrandingProperties struct {
+	ID                                *string   `json:"id,omitempty"`
+	BackgroundColor                   *string   `json:"backgroundColor,omitempty"`
+	BackgroundImageRelativeUrl        *string   `json:"backgroundImageRelativeUrl,omitempty"`
+	BannerLogoRelativeUrl             *string   `json:"bannerLogoRelativeUrl,omitempty"`
+	CdnList                           *[]string `json:"cdnList,omitempty"`
+	CustomAccountResetCredentialsUrl  *string   `json:"customAccountResetCredentialsUrl,omitempty"`
+	CustomCannotAccessYourAccountText *string   `json:"customCannotAccessYourAccountText,omitempty"`
+	CustomCannotAccessYourAccountUrl  *string   `json:"customCannotAccessYourAccountUrl,omitempty"`
+	CustomForgotMyPasswordText        *string   `json:"customForgotMyPasswordText,omitempty"`
+	CustomPrivacyAndCookiesText       *string   `json:"customPrivacyAndCookiesText,omitempty"`
+	CustomPrivacyAndCookiesUrl        *string   `json:"customPrivacyAndCookiesUrl,omitempty"`
+	CustomResetItNowText              *string   `json:"customResetItNowText,omitempty"`
+	CustomTermsOfUseText              *string   `json:"customTermsOfUseText,omitempty"`
+	CustomTermsOfUseUrl               *string   `json:"customTermsOfUseUrl,omitempty"`
+	FaviconRelativeUrl                *string   `json:"faviconRelativeUrl,omitempty"`
+	HeaderBackgroundColor             *string   `json:"headerBackgroundColor,omitempty"`
+	HeaderLogoRelativeUrl             *string   `json:"headerLogoRelativeUrl,omitempty"`
+	SignInPageText                    *string   `json:"signInPageText,omitempty"`
+	SquareLogoDarkRelativeUrl         *string   `json:"squareLogoDarkRelativeUrl,omitempty"`
+	SquareLogoRelativeUrl             *string   `json:"squareLogoRelativeUrl,omitempty"`
+	UsernameHintText                  *string   `json:"usernameHintText,omitempty"`
+}
+
+// OwnedObject is a directory object owned by a user, such as an Application, Group or ServicePrincipal.
+type OwnedObject interface{}
+
 type ParentalControlSettings struct {
 	CountriesBlockedForMinors *[]string `json:"countriesBlockedForMinors,omitempty"`
 	LegalAgeGroupRule         *string   `json:"legalAgeGroupRule,omitempty"`
@@ -892,10 +1643,23 @@ type PasswordAuthenticationMethod struct {
 	Password         *string    `json:"password,omitempty"`
 }
 
+// PasswordCredentialConfiguration restricts the password (secret) credentials of applications and service principals.
+// MaxLifetime is an ISO 8601 duration, e.g. `P90D`, and applies to lifetime restrictions.
+type PasswordCredentialConfiguration struct {
+	MaxLifetime                         *string                   `json:"maxLifetime,omitempty"`
+	RestrictForAppsCreatedAfterDateTime *time.Time                `json:"restrictForAppsCreatedAfterDateTime,omitempty"`
+	RestrictionType                     *AppCredentialRestriction `json:"restrictionType,omitempty"`
+}
+
 type PasswordSingleSignOnSettings struct {
 	Fields *[]SingleSignOnField `json:"fields,omitempty"`
 }
 
+type PatternedRecurrence struct {
+	Pattern *RecurrencePattern `json:"pattern,omitempty"`
+	Range   *RecurrenceRange   `json:"range,omitempty"`
+}
+
 type PermissionScope struct {
 	ID                      *string             `json:"id,omitempty"`
 	AdminConsentDescription *string             `json:"adminConsentDescription,omitempty"`
@@ -917,6 +1681,34 @@ type PhoneAuthenticationMethod struct {
 	PhoneNumber *string                  `json:"phoneNumber,omitempty"`
 	PhoneType   *AuthenticationPhoneType `json:"phoneType,omitempty"`
 }
+
+// Presence describes the availability and activity of a user in Microsoft Teams.
+type Presence struct {
+	ID           *string               `json:"id,omitempty"`
+	Availability *PresenceAvailability `json:"availability,omitempty"`
+	Activity     *PresenceActivity     `json:"activity,omitempty"`
+}
+
+type PrivacyProfile struct {
+	ContactEmail *string `json:"contactEmail,omitempty"`
+	StatementUrl *string `json:"statementUrl,omitempty"`
+}
+
+// ProfilePhoto describes the profile photo of a user or group. MediaEtag identifies the current photo, and can be
+// supplied when retrieving the photo to avoid downloading it again when it has not changed.
+type ProfilePhoto struct {
+	ID        *string `json:"id,omitempty"`
+	Height    *int32  `json:"height,omitempty"`
+	MediaEtag *string `json:"@odata.mediaEtag,omitempty"`
+	Width     *int32  `json:"width,omitempty"`
+}
+
+type ProvisionedPlan struct {
+	CapabilityStatus   *string `json:"capabilityStatus,omitempty"`
+	ProvisioningStatus *string `json:"provisioningStatus,omitempty"`
+	Service            *string `json:"service,omitempty"`
+}
+
 type PublicClient struct {
 	RedirectUris *[]string `json:"redirectUris,omitempty"`
 }
@@ -925,20 +1717,127 @@ type Recipient struct {
 	EmailAddress *EmailAddress `json:"emailAddress,omitempty"`
 }
 
+type RecurrencePattern struct {
+	DayOfMonth     *int32                 `json:"dayOfMonth,omitempty"`
+	DaysOfWeek     *[]string              `json:"daysOfWeek,omitempty"`
+	FirstDayOfWeek *string                `json:"firstDayOfWeek,omitempty"`
+	Index          *string                `json:"index,omitempty"`
+	Interval       *int32                 `json:"interval,omitempty"`
+	Month          *int32                 `json:"month,omitempty"`
+	Type           *RecurrencePatternType `json:"type,omitempty"`
+}
+
+// RecurrenceRange describes the duration of a recurrence. StartDate and EndDate are dates formatted as `YYYY-MM-DD`.
+type RecurrenceRange struct {
+	EndDate             *string              `json:"endDate,omitempty"`
+	NumberOfOccurrences *int32               `json:"numberOfOccurrences,omitempty"`
+	RecurrenceTimeZone  *string              `json:"recurrenceTimeZone,omitempty"`
+	StartDate           *string              `json:"startDate,omitempty"`
+	Type                *RecurrenceRangeType `json:"type,omitempty"`
+}
+
+// RequestedPermissions describes the permissions to be requested from a resource application by their values, for use
+// with ApplicationPermissionsClient.BuildRequiredResourceAccess().
+type RequestedPermissions struct {
+	// ResourceAppId is the application ID of the resource application, e.g. environments.PublishedApis["MicrosoftGraph"].
+	ResourceAppId string
+
+	// Roles are the values of the app roles to request as application permissions, e.g. `User.Read.All`.
+	Roles []string
+
+	// Scopes are the values of the permission scopes to request as delegated permissions, e.g. `User.Read`.
+	Scopes []string
+}
+
+// RequestorSettings describes who can request an AccessPackage under an AccessPackageAssignmentPolicy.
+type RequestorSettings struct {
+	AcceptRequests *bool                       `json:"acceptRequests,omitempty"`
+	ScopeType      *RequestorSettingsScopeType `json:"scopeType,omitempty"`
+}
+
 type RequiredResourceAccess struct {
 	ResourceAccess *[]ResourceAccess `json:"resourceAccess,omitempty"`
 	ResourceAppId  *string           `json:"resourceAppId,omitempty"`
 }
 
+// ResolvedPermission describes a permission requested by an application, resolved to the app role or permission scope
+// published by the resource service principal.
+type ResolvedPermission struct {
+	ID                  *string
+	DisplayName         *string
+	ResourceAppId       *string
+	ResourceDisplayName *string
+	Type                ResourceAccessType
+	Value               *string
+}
+
+// String returns the value of the permission along with its kind, e.g. `User.Read.All (application)`. When the
+// permission could not be resolved, its ID is used instead.
+func (p ResolvedPermission) String() string {
+	name := "unknown"
+	if p.Value != nil {
+		name = *p.Value
+	} else if p.ID != nil {
+		name = *p.ID
+	}
+
+	switch p.Type {
+	case ResourceAccessTypeRole:
+		return fmt.Sprintf("%s (application)", name)
+	case ResourceAccessTypeScope:
+		return fmt.Sprintf("%s (delegated)", name)
+	}
+	return name
+}
+
 type ResourceAccess struct {
 	ID   *string            `json:"id,omitempty"`
 	Type ResourceAccessType `json:"type,omitempty"`
 }
 
+// RiskDetection describes a risk event detected by Identity Protection for a user or sign-in.
+type RiskDetection struct {
+	ID                  *string    `json:"id,omitempty"`
+	Activity            *string    `json:"activity,omitempty"`
+	ActivityDateTime    *time.Time `json:"activityDateTime,omitempty"`
+	AdditionalInfo      *string    `json:"additionalInfo,omitempty"`
+	CorrelationId       *string    `json:"correlationId,omitempty"`
+	DetectedDateTime    *time.Time `json:"detectedDateTime,omitempty"`
+	DetectionTimingType *string    `json:"detectionTimingType,omitempty"`
+	IPAddress           *string    `json:"ipAddress,omitempty"`
+	LastUpdatedDateTime *time.Time `json:"lastUpdatedDateTime,omitempty"`
+	Location            *Location  `json:"location,omitempty"`
+	RequestId           *string    `json:"requestId,omitempty"`
+	RiskDetail          *string    `json:"riskDetail,omitempty"`
+	RiskEventType       *string    `json:"riskEventType,omitempty"`
+	RiskLevel           *RiskLevel `json:"riskLevel,omitempty"`
+	RiskState           *RiskState `json:"riskState,omitempty"`
+	Source              *string    `json:"source,omitempty"`
+	TokenIssuerType     *string    `json:"tokenIssuerType,omitempty"`
+	UserDisplayName     *string    `json:"userDisplayName,omitempty"`
+	UserId              *string    `json:"userId,omitempty"`
+	UserPrincipalName   *string    `json:"userPrincipalName,omitempty"`
+}
+
+// RiskyUser describes a user flagged as risky by Identity Protection.
+type RiskyUser struct {
+	ID                      *string    `json:"id,omitempty"`
+	IsDeleted               *bool      `json:"isDeleted,omitempty"`
+	IsProcessing            *bool      `json:"isProcessing,omitempty"`
+	RiskDetail              *string    `json:"riskDetail,omitempty"`
+	RiskLastUpdatedDateTime *time.Time `json:"riskLastUpdatedDateTime,omitempty"`
+	RiskLevel               *RiskLevel `json:"riskLevel,omitempty"`
+	RiskState               *RiskState `json:"riskState,omitempty"`
+	UserDisplayName         *string    `json:"userDisplayName,omitempty"`
+	UserPrincipalName       *string    `json:"userPrincipalName,omitempty"`
+}
+
 type SamlSingleSignOnSettings struct {
 	RelayState *string `json:"relayState,omitempty"`
 }
 
+// SchemaExtension defines a set of typed properties which can be added to the target resource types, such as users
+// and groups. The Status describes its lifecycle, see SchemaExtensionsClient.SetStatus.
 type SchemaExtension struct {
 	ID          *string                      `json:"id,omitempty"`
 	Description *string                      `json:"description,omitempty"`
@@ -960,6 +1859,19 @@ func (se SchemaExtensionData) MarshalJSON() ([]byte, error) {
 	return json.Marshal(in)
 }
 
+// isSchemaExtensionProperty indicates whether a property holds the data for one of the specified schema extensions
+func isSchemaExtensionProperty(schemaExtensions *[]SchemaExtensionData, name string) bool {
+	if schemaExtensions == nil {
+		return false
+	}
+	for _, ext := range *schemaExtensions {
+		if strings.EqualFold(ext.ID, name) {
+			return true
+		}
+	}
+	return false
+}
+
 // ServicePrincipal describes a Service Principal object.
 type ServicePrincipal struct {
 	DirectoryObject
@@ -1007,9 +1919,24 @@ func (s *ServicePrincipal) UnmarshalJSON(data []byte) error {
 	if err := json.Unmarshal(data, s2); err != nil {
 		return err
 	}
+	if err := s.recordResponseFields(data); err != nil {
+		return err
+	}
 	return nil
 }
 
+type SettingTemplateValue struct {
+	DefaultValue *string `json:"defaultValue,omitempty"`
+	Description  *string `json:"description,omitempty"`
+	Name         *string `json:"name,omitempty"`
+	Type         *string `json:"type,omitempty"`
+}
+
+type SettingValue struct {
+	Name  *string `json:"name,omitempty"`
+	Value *string `json:"value,omitempty"`
+}
+
 type SignInActivity struct {
 	LastSignInDateTime  *time.Time `json:"lastSignInDateTime,omitempty"`
 	LastSignInRequestId *string    `json:"lastSignInRequestId,omitempty"`
@@ -1054,12 +1981,85 @@ type SingleSignOnField struct {
 	Type            *string `json:"type,omitempty"`
 }
 
+// SmsAuthenticationMethodConfiguration configures the use of text messages in an AuthenticationMethodsPolicy.
+type SmsAuthenticationMethodConfiguration struct {
+	*BaseAuthenticationMethodConfiguration
+	IncludeTargets *[]AuthenticationMethodTarget `json:"includeTargets,omitempty"`
+}
+
 type Status struct {
 	ErrorCode         *int32  `json:"errorCode,omitempty"`
 	FailureReason     *string `json:"failureReason,omitempty"`
 	AdditionalDetails *string `json:"additionalDetails,omitempty"`
 }
 
+// SynchronizationError describes an error encountered during synchronization.
+type SynchronizationError struct {
+	Code             *string `json:"code,omitempty"`
+	Message          *string `json:"message,omitempty"`
+	TenantActionable *bool   `json:"tenantActionable,omitempty"`
+}
+
+// SynchronizationJob performs synchronization, such as SCIM provisioning, for a service principal. Jobs are created
+// from a synchronization template and run periodically according to their Schedule.
+type SynchronizationJob struct {
+	ID         *string                  `json:"id,omitempty"`
+	Schedule   *SynchronizationSchedule `json:"schedule,omitempty"`
+	Status     *SynchronizationStatus   `json:"status,omitempty"`
+	TemplateId *string                  `json:"templateId,omitempty"`
+}
+
+// SynchronizationQuarantine describes why a SynchronizationJob has been quarantined, which stops synchronization
+// until the issue is resolved and the job is restarted.
+type SynchronizationQuarantine struct {
+	CurrentBegan *time.Time            `json:"currentBegan,omitempty"`
+	Error        *SynchronizationError `json:"error,omitempty"`
+	NextAttempt  *time.Time            `json:"nextAttempt,omitempty"`
+	Reason       *string               `json:"reason,omitempty"`
+	SeriesBegan  *time.Time            `json:"seriesBegan,omitempty"`
+	SeriesCount  *int64                `json:"seriesCount,omitempty"`
+}
+
+// SynchronizationSchedule describes when a SynchronizationJob runs. Interval is an ISO 8601 duration, e.g. `PT40M`.
+type SynchronizationSchedule struct {
+	Expiration *time.Time                    `json:"expiration,omitempty"`
+	Interval   *string                       `json:"interval,omitempty"`
+	State      *SynchronizationScheduleState `json:"state,omitempty"`
+}
+
+// SynchronizationSchema describes the directories, objects and attributes which take part in a SynchronizationJob.
+type SynchronizationSchema struct {
+	ID          *string                `json:"id,omitempty"`
+	Directories *[]DirectoryDefinition `json:"directories,omitempty"`
+	Version     *string                `json:"version,omitempty"`
+}
+
+// SynchronizationStatus describes the current state of a SynchronizationJob and the outcome of its recent runs.
+type SynchronizationStatus struct {
+	Code                               *SynchronizationStatusCode    `json:"code,omitempty"`
+	CountSuccessiveCompleteFailures    *int64                        `json:"countSuccessiveCompleteFailures,omitempty"`
+	EscrowsPruned                      *bool                         `json:"escrowsPruned,omitempty"`
+	LastExecution                      *SynchronizationTaskExecution `json:"lastExecution,omitempty"`
+	LastSuccessfulExecution            *SynchronizationTaskExecution `json:"lastSuccessfulExecution,omitempty"`
+	LastSuccessfulExecutionWithExports *SynchronizationTaskExecution `json:"lastSuccessfulExecutionWithExports,omitempty"`
+	Quarantine                         *SynchronizationQuarantine    `json:"quarantine,omitempty"`
+	SteadyStateFirstAchievedTime       *time.Time                    `json:"steadyStateFirstAchievedTime,omitempty"`
+	SteadyStateLastAchievedTime        *time.Time                    `json:"steadyStateLastAchievedTime,omitempty"`
+	TroubleshootingUrl                 *string                       `json:"troubleshootingUrl,omitempty"`
+}
+
+// SynchronizationTaskExecution summarizes a single run of a SynchronizationJob.
+type SynchronizationTaskExecution struct {
+	ActivityIdentifier *string                             `json:"activityIdentifier,omitempty"`
+	CountEntitled      *int64                              `json:"countEntitled,omitempty"`
+	CountExported      *int64                              `json:"countExported,omitempty"`
+	CountImported      *int64                              `json:"countImported,omitempty"`
+	Error              *SynchronizationError               `json:"error,omitempty"`
+	State              *SynchronizationTaskExecutionResult `json:"state,omitempty"`
+	TimeBegan          *time.Time                          `json:"timeBegan,omitempty"`
+	TimeEnded          *time.Time                          `json:"timeEnded,omitempty"`
+}
+
 type TargetResource struct {
 	Id                 *string             `json:"id,omitempty"`
 	DisplayName        *string             `json:"displayName,omitempty"`
@@ -1080,72 +2080,101 @@ type TemporaryAccessPassAuthenticationMethod struct {
 	MethodUsabilityReason *MethodUsabilityReason `json:"methodUsabilityReason,omitempty"`
 }
 
+// TenantAppManagementPolicy describes the default credential restrictions for all applications and service principals
+// in a tenant, which apply unless overridden by an AppManagementPolicy.
+type TenantAppManagementPolicy struct {
+	ID                           *string                     `json:"id,omitempty"`
+	ApplicationRestrictions      *AppManagementConfiguration `json:"applicationRestrictions,omitempty"`
+	Description                  *string                     `json:"description,omitempty"`
+	DisplayName                  *string                     `json:"displayName,omitempty"`
+	IsEnabled                    *bool                       `json:"isEnabled,omitempty"`
+	ServicePrincipalRestrictions *AppManagementConfiguration `json:"servicePrincipalRestrictions,omitempty"`
+}
+
+type UnifiedRole struct {
+	RoleDefinitionId *string `json:"roleDefinitionId,omitempty"`
+}
+
+// UploadSession describes a resumable session for uploading large content.
+type UploadSession struct {
+	ExpirationDateTime *time.Time `json:"expirationDateTime,omitempty"`
+	NextExpectedRanges *[]string  `json:"nextExpectedRanges,omitempty"`
+	UploadUrl          *string    `json:"uploadUrl,omitempty"`
+}
+
 // User describes a User object.
 type User struct {
 	DirectoryObject
 
-	AboutMe                         *string                  `json:"aboutMe,omitempty"`
-	AccountEnabled                  *bool                    `json:"accountEnabled,omitempty"`
-	AgeGroup                        *AgeGroup                `json:"ageGroup,omitempty"`
-	BusinessPhones                  *[]string                `json:"businessPhones,omitempty"`
-	City                            *StringNullWhenEmpty     `json:"city,omitempty"`
-	CompanyName                     *StringNullWhenEmpty     `json:"companyName,omitempty"`
-	ConsentProvidedForMinor         *ConsentProvidedForMinor `json:"consentProvidedForMinor,omitempty"`
-	Country                         *StringNullWhenEmpty     `json:"country,omitempty"`
-	CreatedDateTime                 *time.Time               `json:"createdDateTime,omitempty"`
-	CreationType                    *string                  `json:"creationType,omitempty"`
-	DeletedDateTime                 *time.Time               `json:"deletedDateTime,omitempty"`
-	Department                      *StringNullWhenEmpty     `json:"department,omitempty"`
-	DisplayName                     *string                  `json:"displayName,omitempty"`
-	EmployeeHireDate                *time.Time               `json:"employeeHireDate,omitempty"`
-	EmployeeId                      *StringNullWhenEmpty     `json:"employeeId,omitempty"`
-	EmployeeType                    *string                  `json:"employeeType,omitempty"`
-	ExternalUserState               *string                  `json:"externalUserState,omitempty"`
-	FaxNumber                       *StringNullWhenEmpty     `json:"faxNumber,omitempty"`
-	GivenName                       *StringNullWhenEmpty     `json:"givenName,omitempty"`
-	ImAddresses                     *[]string                `json:"imAddresses,omitempty"`
-	Interests                       *[]string                `json:"interests,omitempty"`
-	IsManagementRestricted          *bool                    `json:"isManagementRestricted,omitempty"`
-	IsResourceAccount               *bool                    `json:"isResourceAccount,omitempty"`
-	JobTitle                        *StringNullWhenEmpty     `json:"jobTitle,omitempty"`
-	Mail                            *StringNullWhenEmpty     `json:"mail,omitempty"`
-	MailNickname                    *string                  `json:"mailNickname,omitempty"`
-	MemberOf                        *[]DirectoryObject       `json:"memberOf,omitempty"`
-	MobilePhone                     *StringNullWhenEmpty     `json:"mobilePhone,omitempty"`
-	MySite                          *string                  `json:"mySite,omitempty"`
-	OfficeLocation                  *StringNullWhenEmpty     `json:"officeLocation,omitempty"`
-	OnPremisesDistinguishedName     *string                  `json:"onPremisesDistinguishedName,omitempty"`
-	OnPremisesDomainName            *string                  `json:"onPremisesDomainName,omitempty"`
-	OnPremisesImmutableId           *string                  `json:"onPremisesImmutableId,omitempty"`
-	OnPremisesLastSyncDateTime      *string                  `json:"onPremisesLastSyncDateTime,omitempty"`
-	OnPremisesSamAccountName        *string                  `json:"onPremisesSamAccountName,omitempty"`
-	OnPremisesSecurityIdentifier    *string                  `json:"onPremisesSecurityIdentifier,omitempty"`
-	OnPremisesSyncEnabled           *bool                    `json:"onPremisesSyncEnabled,omitempty"`
-	OnPremisesUserPrincipalName     *string                  `json:"onPremisesUserPrincipalName,omitempty"`
-	OtherMails                      *[]string                `json:"otherMails,omitempty"`
-	PasswordPolicies                *StringNullWhenEmpty     `json:"passwordPolicies,omitempty"`
-	PasswordProfile                 *UserPasswordProfile     `json:"passwordProfile,omitempty"`
-	PastProjects                    *[]string                `json:"pastProjects,omitempty"`
-	PostalCode                      *StringNullWhenEmpty     `json:"postalCode,omitempty"`
-	PreferredDataLocation           *string                  `json:"preferredDataLocation,omitempty"`
-	PreferredLanguage               *StringNullWhenEmpty     `json:"preferredLanguage,omitempty"`
-	PreferredName                   *string                  `json:"preferredName,omitempty"`
-	ProxyAddresses                  *[]string                `json:"proxyAddresses,omitempty"`
-	RefreshTokensValidFromDateTime  *time.Time               `json:"refreshTokensValidFromDateTime,omitempty"`
-	Responsibilities                *[]string                `json:"responsibilities,omitempty"`
-	Schools                         *[]string                `json:"schools,omitempty"`
-	ShowInAddressList               *bool                    `json:"showInAddressList,omitempty"`
-	SignInActivity                  *SignInActivity          `json:"signInActivity,omitempty"`
-	SignInSessionsValidFromDateTime *time.Time               `json:"signInSessionsValidFromDateTime,omitempty"`
-	Skills                          *[]string                `json:"skills,omitempty"`
-	State                           *StringNullWhenEmpty     `json:"state,omitempty"`
-	StreetAddress                   *StringNullWhenEmpty     `json:"streetAddress,omitempty"`
-	Surname                         *StringNullWhenEmpty     `json:"surname,omitempty"`
-	UsageLocation                   *StringNullWhenEmpty     `json:"usageLocation,omitempty"`
-	UserPrincipalName               *string                  `json:"userPrincipalName,omitempty"`
-	UserType                        *string                  `json:"userType,omitempty"`
+	AboutMe                         *string                        `json:"aboutMe,omitempty"`
+	AccountEnabled                  *bool                          `json:"accountEnabled,omitempty"`
+	AgeGroup                        *AgeGroup                      `json:"ageGroup,omitempty"`
+	BusinessPhones                  *[]string                      `json:"businessPhones,omitempty"`
+	City                            *StringNullWhenEmpty           `json:"city,omitempty"`
+	CompanyName                     *StringNullWhenEmpty           `json:"companyName,omitempty"`
+	ConsentProvidedForMinor         *ConsentProvidedForMinor       `json:"consentProvidedForMinor,omitempty"`
+	Country                         *StringNullWhenEmpty           `json:"country,omitempty"`
+	CreatedDateTime                 *time.Time                     `json:"createdDateTime,omitempty"`
+	CreationType                    *string                        `json:"creationType,omitempty"`
+	DeletedDateTime                 *time.Time                     `json:"deletedDateTime,omitempty"`
+	Department                      *StringNullWhenEmpty           `json:"department,omitempty"`
+	DisplayName                     *string                        `json:"displayName,omitempty"`
+	EmployeeHireDate                *time.Time                     `json:"employeeHireDate,omitempty"`
+	EmployeeId                      *StringNullWhenEmpty           `json:"employeeId,omitempty"`
+	EmployeeType                    *string                        `json:"employeeType,omitempty"`
+	ExternalUserState               *string                        `json:"externalUserState,omitempty"`
+	FaxNumber                       *StringNullWhenEmpty           `json:"faxNumber,omitempty"`
+	GivenName                       *StringNullWhenEmpty           `json:"givenName,omitempty"`
+	ImAddresses                     *[]string                      `json:"imAddresses,omitempty"`
+	Interests                       *[]string                      `json:"interests,omitempty"`
+	IsManagementRestricted          *bool                          `json:"isManagementRestricted,omitempty"`
+	IsResourceAccount               *bool                          `json:"isResourceAccount,omitempty"`
+	JobTitle                        *StringNullWhenEmpty           `json:"jobTitle,omitempty"`
+	Mail                            *StringNullWhenEmpty           `json:"mail,omitempty"`
+	MailNickname                    *string                        `json:"mailNickname,omitempty"`
+	MemberOf                        *[]DirectoryObject             `json:"memberOf,omitempty"`
+	MobilePhone                     *StringNullWhenEmpty           `json:"mobilePhone,omitempty"`
+	MySite                          *string                        `json:"mySite,omitempty"`
+	OfficeLocation                  *StringNullWhenEmpty           `json:"officeLocation,omitempty"`
+	OnPremisesDistinguishedName     *string                        `json:"onPremisesDistinguishedName,omitempty"`
+	OnPremisesDomainName            *string                        `json:"onPremisesDomainName,omitempty"`
+	OnPremisesExtensionAttributes   *OnPremisesExtensionAttributes `json:"onPremisesExtensionAttributes,omitempty"`
+	OnPremisesImmutableId           *string                        `json:"onPremisesImmutableId,omitempty"`
+	OnPremisesLastSyncDateTime      *string                        `json:"onPremisesLastSyncDateTime,omitempty"`
+	OnPremisesSamAccountName        *string                        `json:"onPremisesSamAccountName,omitempty"`
+	OnPremisesSecurityIdentifier    *string                        `json:"onPremisesSecurityIdentifier,omitempty"`
+	OnPremisesSyncEnabled           *bool                          `json:"onPremisesSyncEnabled,omitempty"`
+	OnPremisesUserPrincipalName     *string                        `json:"onPremisesUserPrincipalName,omitempty"`
+	OtherMails                      *[]string                      `json:"otherMails,omitempty"`
+	PasswordPolicies                *StringNullWhenEmpty           `json:"passwordPolicies,omitempty"`
+	PasswordProfile                 *UserPasswordProfile           `json:"passwordProfile,omitempty"`
+	PastProjects                    *[]string                      `json:"pastProjects,omitempty"`
+	PostalCode                      *StringNullWhenEmpty           `json:"postalCode,omitempty"`
+	PreferredDataLocation           *string                        `json:"preferredDataLocation,omitempty"`
+	PreferredLanguage               *StringNullWhenEmpty           `json:"preferredLanguage,omitempty"`
+	PreferredName                   *string                        `json:"preferredName,omitempty"`
+	ProxyAddresses                  *[]string                      `json:"proxyAddresses,omitempty"`
+	RefreshTokensValidFromDateTime  *time.Time                     `json:"refreshTokensValidFromDateTime,omitempty"`
+	Responsibilities                *[]string                      `json:"responsibilities,omitempty"`
+	Schools                         *[]string                      `json:"schools,omitempty"`
+	ShowInAddressList               *bool                          `json:"showInAddressList,omitempty"`
+	SignInActivity                  *SignInActivity                `json:"signInActivity,omitempty"`
+	SignInSessionsValidFromDateTime *time.Time                     `json:"signInSessionsValidFromDateTime,omitempty"`
+	Skills                          *[]string                      `json:"skills,omitempty"`
+	State                           *StringNullWhenEmpty           `json:"state,omitempty"`
+	StreetAddress                   *StringNullWhenEmpty           `json:"streetAddress,omitempty"`
+	Surname                         *StringNullWhenEmpty           `json:"surname,omitempty"`
+	UsageLocation                   *StringNullWhenEmpty           `json:"usageLocation,omitempty"`
+	UserPrincipalName               *string                        `json:"userPrincipalName,omitempty"`
+	UserType                        *string                        `json:"userType,omitempty"`
 
 	SchemaExtensions *[]SchemaExtensionData `json:"-"`
+
+	// AdditionalData holds the values of directory extension properties, which are named `extension_{appId}_{name}`
+	// where appId is the application ID without hyphens. These are populated when selected in a query, and are
+	// included when the User is marshaled so they can be set when creating or updating a user. Numeric values are
+	// decoded as json.Number to preserve their full precision.
+	AdditionalData map[string]interface{} `json:"-"`
 }
 
 func (u User) MarshalJSON() ([]byte, error) {
@@ -1166,6 +2195,13 @@ func (u User) MarshalJSON() ([]byte, error) {
 			docs = append(docs, d)
 		}
 	}
+	if len(u.AdditionalData) > 0 {
+		d, err := json.Marshal(u.AdditionalData)
+		if err != nil {
+			return d, err
+		}
+		docs = append(docs, d)
+	}
 	return MarshalDocs(docs)
 }
 
@@ -1176,11 +2212,27 @@ func (u *User) UnmarshalJSON(data []byte) error {
 	if err := json.Unmarshal(data, u2); err != nil {
 		return err
 	}
-	if u.SchemaExtensions != nil {
-		var fields map[string]json.RawMessage
-		if err := json.Unmarshal(data, &fields); err != nil {
+	if err := u.recordResponseFields(data); err != nil {
+		return err
+	}
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return err
+	}
+	for k, v := range fields {
+		if !strings.HasPrefix(k, "extension_") {
+			continue
+		}
+		var val interface{}
+		if err := unmarshalUseNumber(v, &val); err != nil {
 			return err
 		}
+		if u.AdditionalData == nil {
+			u.AdditionalData = make(map[string]interface{})
+		}
+		u.AdditionalData[k] = val
+	}
+	if u.SchemaExtensions != nil {
 		for _, ext := range *u.SchemaExtensions {
 			if v, ok := fields[ext.ID]; ok {
 				if err := json.Unmarshal(v, &ext.Properties); err != nil {
@@ -1192,6 +2244,12 @@ func (u *User) UnmarshalJSON(data []byte) error {
 	return nil
 }
 
+// isDynamicProperty indicates whether a property is a directory extension property, or holds the data for one of the
+// specified SchemaExtensions
+func (u *User) isDynamicProperty(name string) bool {
+	return strings.HasPrefix(name, "extension_") || isSchemaExtensionProperty(u.SchemaExtensions, name)
+}
+
 type UserIdentity struct {
 	DisplayName       *string `json:"displayName,omitempty"`
 	Id                *string `json:"id,omitempty"`
@@ -1199,6 +2257,9 @@ type UserIdentity struct {
 	UserPrincipalName *string `json:"userPrincipalName,omitempty"`
 }
 
+// UserPasswordProfile holds the password for a user. Set ForceChangePasswordNextSignIn to provision the user with a
+// temporary password, and ForceChangePasswordNextSignInWithMfa to additionally require multi-factor authentication
+// before the password is changed. The password is never returned by the API.
 type UserPasswordProfile struct {
 	ForceChangePasswordNextSignIn        *bool   `json:"forceChangePasswordNextSignIn,omitempty"`
 	ForceChangePasswordNextSignInWithMfa *bool   `json:"forceChangePasswordNextSignInWithMfa,omitempty"`
@@ -1243,6 +2304,15 @@ type UserCredentialUsageDetails struct {
 	UserDisplayName   *string          `json:"userDisplayName,omitempty"`
 	UserPrincipalName *string          `json:"userPrincipalName,omitempty"`
 }
+
+type VerifiedDomain struct {
+	Capabilities *string `json:"capabilities,omitempty"`
+	IsDefault    *bool   `json:"isDefault,omitempty"`
+	IsInitial    *bool   `json:"isInitial,omitempty"`
+	Name         *string `json:"name,omitempty"`
+	Type         *string `json:"type,omitempty"`
+}
+
 type VerifiedPublisher struct {
 	AddedDateTime       *time.Time `json:"addedDateTime,omitempty"`
 	DisplayName         *string    `json:"displayName,omitempty"`
@@ -26,6 +26,7 @@ func NewNamedLocationsClient(tenantId string) *NamedLocationsClient {
 // List returns a list of Named Locations, optionally queried using OData.
 func (c *NamedLocationsClient) List(ctx context.Context, query odata.Query) (*[]NamedLocation, int, error) {
 	resp, status, _, err := c.BaseClient.Get(ctx, GetHttpRequestInput{
+		ConsistencyLevel: query.ConsistencyLevel,
 		DisablePaging:    query.Top > 0,
 		ValidStatusCodes: []int{http.StatusOK},
 		Uri: Uri{
@@ -36,7 +37,7 @@ func (c *NamedLocationsClient) List(ctx context.Context, query odata.Query) (*[]
 	})
 
 	if err != nil {
-		return nil, status, fmt.Errorf("NamedLocationsClient.BaseClient.Get(): %v", err)
+		return nil, status, fmt.Errorf("NamedLocationsClient.BaseClient.Get(): %w", err)
 	}
 
 	defer resp.Body.Close()
@@ -49,7 +50,7 @@ func (c *NamedLocationsClient) List(ctx context.Context, query odata.Query) (*[]
 		NamedLocations *[]json.RawMessage `json:"value"`
 	}
 
-	if err := json.Unmarshal(respBody, &data); err != nil {
+	if err := c.BaseClient.decode(respBody, &data); err != nil {
 		return nil, status, fmt.Errorf("json.Unmarshal(): %v", err)
 	}
 
@@ -101,7 +102,7 @@ func (c *NamedLocationsClient) Delete(ctx context.Context, id string) (int, erro
 		},
 	})
 	if err != nil {
-		return status, fmt.Errorf("NamedLocationsClient.BaseClient.Delete(): %v", err)
+		return status, fmt.Errorf("NamedLocationsClient.BaseClient.Delete(): %w", err)
 	}
 
 	return status, nil
@@ -126,7 +127,7 @@ func (c *NamedLocationsClient) CreateIP(ctx context.Context, ipNamedLocation IPN
 		},
 	})
 	if err != nil {
-		return nil, status, fmt.Errorf("NamedLocationsClient.BaseClient.Post(): %v", err)
+		return nil, status, fmt.Errorf("NamedLocationsClient.BaseClient.Post(): %w", err)
 	}
 
 	defer resp.Body.Close()
@@ -136,7 +137,7 @@ func (c *NamedLocationsClient) CreateIP(ctx context.Context, ipNamedLocation IPN
 	}
 
 	var newIPNamedLocation IPNamedLocation
-	if err := json.Unmarshal(respBody, &newIPNamedLocation); err != nil {
+	if err := c.BaseClient.decode(respBody, &newIPNamedLocation); err != nil {
 		return nil, status, fmt.Errorf("json.Unmarshal(): %v", err)
 	}
 
@@ -162,7 +163,7 @@ func (c *NamedLocationsClient) CreateCountry(ctx context.Context, countryNamedLo
 		},
 	})
 	if err != nil {
-		return nil, status, fmt.Errorf("NamedLocationsClient.BaseClient.Post(): %v", err)
+		return nil, status, fmt.Errorf("NamedLocationsClient.BaseClient.Post(): %w", err)
 	}
 
 	defer resp.Body.Close()
@@ -172,7 +173,7 @@ func (c *NamedLocationsClient) CreateCountry(ctx context.Context, countryNamedLo
 	}
 
 	var newCountryNamedLocation CountryNamedLocation
-	if err := json.Unmarshal(respBody, &newCountryNamedLocation); err != nil {
+	if err := c.BaseClient.decode(respBody, &newCountryNamedLocation); err != nil {
 		return nil, status, fmt.Errorf("json.Unmarshal(): %v", err)
 	}
 
@@ -183,6 +184,7 @@ func (c *NamedLocationsClient) CreateCountry(ctx context.Context, countryNamedLo
 func (c *NamedLocationsClient) GetIP(ctx context.Context, id string, query odata.Query) (*IPNamedLocation, int, error) {
 	resp, status, _, err := c.BaseClient.Get(ctx, GetHttpRequestInput{
 		ConsistencyFailureFunc: RetryOn404ConsistencyFailureFunc,
+		ConsistencyLevel:       query.ConsistencyLevel,
 		ValidStatusCodes:       []int{http.StatusOK},
 		Uri: Uri{
 			Entity:      fmt.Sprintf("/identity/conditionalAccess/namedLocations/%s", id),
@@ -191,7 +193,7 @@ func (c *NamedLocationsClient) GetIP(ctx context.Context, id string, query odata
 		},
 	})
 	if err != nil {
-		return nil, status, fmt.Errorf("NamedLocationsClient.BaseClient.Get(): %v", err)
+		return nil, status, fmt.Errorf("NamedLocationsClient.BaseClient.Get(): %w", err)
 	}
 
 	defer resp.Body.Close()
@@ -201,7 +203,7 @@ func (c *NamedLocationsClient) GetIP(ctx context.Context, id string, query odata
 	}
 
 	var ipNamedLocation IPNamedLocation
-	if err := json.Unmarshal(respBody, &ipNamedLocation); err != nil {
+	if err := c.BaseClient.decode(respBody, &ipNamedLocation); err != nil {
 		return nil, status, fmt.Errorf("json.Unmarshal(): %v", err)
 	}
 
@@ -212,6 +214,7 @@ func (c *NamedLocationsClient) GetIP(ctx context.Context, id string, query odata
 func (c *NamedLocationsClient) Get(ctx context.Context, id string, query odata.Query) (*NamedLocation, int, error) {
 	resp, status, _, err := c.BaseClient.Get(ctx, GetHttpRequestInput{
 		ConsistencyFailureFunc: RetryOn404ConsistencyFailureFunc,
+		ConsistencyLevel:       query.ConsistencyLevel,
 		ValidStatusCodes:       []int{http.StatusOK},
 		Uri: Uri{
 			Entity:      fmt.Sprintf("/identity/conditionalAccess/namedLocations/%s", id),
@@ -220,7 +223,7 @@ func (c *NamedLocationsClient) Get(ctx context.Context, id string, query odata.Q
 		},
 	})
 	if err != nil {
-		return nil, status, fmt.Errorf("NamedLocationsClient.BaseClient.Get(): %v", err)
+		return nil, status, fmt.Errorf("NamedLocationsClient.BaseClient.Get(): %w", err)
 	}
 
 	defer resp.Body.Close()
@@ -230,7 +233,7 @@ func (c *NamedLocationsClient) Get(ctx context.Context, id string, query odata.Q
 	}
 
 	var o odata.OData
-	if err := json.Unmarshal(respBody, &o); err != nil {
+	if err := c.BaseClient.decode(respBody, &o); err != nil {
 		return nil, status, fmt.Errorf("json.Unmarshal(): %v", err)
 	}
 
@@ -245,13 +248,13 @@ func (c *NamedLocationsClient) Get(ctx context.Context, id string, query odata.Q
 	switch *o.Type {
 	case odata.TypeCountryNamedLocation:
 		var loc CountryNamedLocation
-		if err := json.Unmarshal(respBody, &loc); err != nil {
+		if err := c.BaseClient.decode(respBody, &loc); err != nil {
 			return nil, status, fmt.Errorf("json.Unmarshal(): %v", err)
 		}
 		ret = loc
 	case odata.TypeIpNamedLocation:
 		var loc IPNamedLocation
-		if err := json.Unmarshal(respBody, &loc); err != nil {
+		if err := c.BaseClient.decode(respBody, &loc); err != nil {
 			return nil, status, fmt.Errorf("json.Unmarshal(): %v", err)
 		}
 		ret = loc
@@ -265,6 +268,7 @@ func (c *NamedLocationsClient) Get(ctx context.Context, id string, query odata.Q
 func (c *NamedLocationsClient) GetCountry(ctx context.Context, id string, query odata.Query) (*CountryNamedLocation, int, error) {
 	resp, status, _, err := c.BaseClient.Get(ctx, GetHttpRequestInput{
 		ConsistencyFailureFunc: RetryOn404ConsistencyFailureFunc,
+		ConsistencyLevel:       query.ConsistencyLevel,
 		ValidStatusCodes:       []int{http.StatusOK},
 		Uri: Uri{
 			Entity:      fmt.Sprintf("/identity/conditionalAccess/namedLocations/%s", id),
@@ -273,7 +277,7 @@ func (c *NamedLocationsClient) GetCountry(ctx context.Context, id string, query
 		},
 	})
 	if err != nil {
-		return nil, status, fmt.Errorf("NamedLocationsClient.BaseClient.Get(): %v", err)
+		return nil, status, fmt.Errorf("NamedLocationsClient.BaseClient.Get(): %w", err)
 	}
 
 	defer resp.Body.Close()
@@ -283,7 +287,7 @@ func (c *NamedLocationsClient) GetCountry(ctx context.Context, id string, query
 	}
 
 	var countryNamedLocation CountryNamedLocation
-	if err := json.Unmarshal(respBody, &countryNamedLocation); err != nil {
+	if err := c.BaseClient.decode(respBody, &countryNamedLocation); err != nil {
 		return nil, status, fmt.Errorf("json.Unmarshal(): %v", err)
 	}
 
@@ -310,7 +314,7 @@ func (c *NamedLocationsClient) UpdateIP(ctx context.Context, ipNamedLocation IPN
 		},
 	})
 	if err != nil {
-		return status, fmt.Errorf("NamedLocationsClient.BaseClient.Patch(): %v", err)
+		return status, fmt.Errorf("NamedLocationsClient.BaseClient.Patch(): %w", err)
 	}
 
 	return status, nil
@@ -336,7 +340,7 @@ func (c *NamedLocationsClient) UpdateCountry(ctx context.Context, countryNamedLo
 		},
 	})
 	if err != nil {
-		return status, fmt.Errorf("NamedLocationsClient.BaseClient.Patch(): %v", err)
+		return status, fmt.Errorf("NamedLocationsClient.BaseClient.Patch(): %w", err)
 	}
 
 	return status, nil
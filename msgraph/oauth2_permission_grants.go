@@ -0,0 +1,170 @@
+package msgraph
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/manicminer/hamilton/odata"
+)
+
+// OAuth2PermissionGrantsClient performs operations on OAuth2PermissionGrants.
+type OAuth2PermissionGrantsClient struct {
+	BaseClient Client
+}
+
+// NewOAuth2PermissionGrantsClient returns a new OAuth2PermissionGrantsClient.
+func NewOAuth2PermissionGrantsClient(tenantId string) *OAuth2PermissionGrantsClient {
+	return &OAuth2PermissionGrantsClient{
+		BaseClient: NewClient(Version10, tenantId),
+	}
+}
+
+// List returns a list of OAuth2PermissionGrants, optionally queried using OData.
+func (c *OAuth2PermissionGrantsClient) List(ctx context.Context, query odata.Query) (*[]OAuth2PermissionGrant, int, error) {
+	resp, status, _, err := c.BaseClient.Get(ctx, GetHttpRequestInput{
+		ConsistencyLevel: query.ConsistencyLevel,
+		DisablePaging:    query.Top > 0,
+		ValidStatusCodes: []int{http.StatusOK},
+		Uri: Uri{
+			Entity:      "/oauth2PermissionGrants",
+			Params:      query.Values(),
+			HasTenantId: true,
+		},
+	})
+	if err != nil {
+		return nil, status, fmt.Errorf("OAuth2PermissionGrantsClient.BaseClient.Get(): %w", err)
+	}
+
+	defer resp.Body.Close()
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, status, fmt.Errorf("io.ReadAll(): %v", err)
+	}
+
+	var data struct {
+		OAuth2PermissionGrants []OAuth2PermissionGrant `json:"value"`
+	}
+	if err := c.BaseClient.decode(respBody, &data); err != nil {
+		return nil, status, fmt.Errorf("json.Unmarshal(): %v", err)
+	}
+
+	return &data.OAuth2PermissionGrants, status, nil
+}
+
+// Create creates a new OAuth2PermissionGrant.
+func (c *OAuth2PermissionGrantsClient) Create(ctx context.Context, oAuth2PermissionGrant OAuth2PermissionGrant) (*OAuth2PermissionGrant, int, error) {
+	var status int
+
+	body, err := json.Marshal(oAuth2PermissionGrant)
+	if err != nil {
+		return nil, status, fmt.Errorf("json.Marshal(): %v", err)
+	}
+
+	resp, status, _, err := c.BaseClient.Post(ctx, PostHttpRequestInput{
+		Body:                   body,
+		ConsistencyFailureFunc: RetryOn404ConsistencyFailureFunc,
+		ValidStatusCodes:       []int{http.StatusCreated},
+		Uri: Uri{
+			Entity:      "/oauth2PermissionGrants",
+			HasTenantId: true,
+		},
+	})
+	if err != nil {
+		return nil, status, fmt.Errorf("OAuth2PermissionGrantsClient.BaseClient.Post(): %w", err)
+	}
+
+	defer resp.Body.Close()
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, status, fmt.Errorf("io.ReadAll(): %v", err)
+	}
+
+	var newOAuth2PermissionGrant OAuth2PermissionGrant
+	if err := c.BaseClient.decode(respBody, &newOAuth2PermissionGrant); err != nil {
+		return nil, status, fmt.Errorf("json.Unmarshal(): %v", err)
+	}
+
+	return &newOAuth2PermissionGrant, status, nil
+}
+
+// Get retrieves an OAuth2PermissionGrant.
+func (c *OAuth2PermissionGrantsClient) Get(ctx context.Context, id string, query odata.Query) (*OAuth2PermissionGrant, int, error) {
+	resp, status, _, err := c.BaseClient.Get(ctx, GetHttpRequestInput{
+		ConsistencyFailureFunc: RetryOn404ConsistencyFailureFunc,
+		ConsistencyLevel:       query.ConsistencyLevel,
+		ValidStatusCodes:       []int{http.StatusOK},
+		Uri: Uri{
+			Entity:      fmt.Sprintf("/oauth2PermissionGrants/%s", id),
+			Params:      query.Values(),
+			HasTenantId: true,
+		},
+	})
+	if err != nil {
+		return nil, status, fmt.Errorf("OAuth2PermissionGrantsClient.BaseClient.Get(): %w", err)
+	}
+
+	defer resp.Body.Close()
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, status, fmt.Errorf("io.ReadAll(): %v", err)
+	}
+
+	var oAuth2PermissionGrant OAuth2PermissionGrant
+	if err := c.BaseClient.decode(respBody, &oAuth2PermissionGrant); err != nil {
+		return nil, status, fmt.Errorf("json.Unmarshal(): %v", err)
+	}
+
+	return &oAuth2PermissionGrant, status, nil
+}
+
+// Update amends the scopes of an existing OAuth2PermissionGrant.
+func (c *OAuth2PermissionGrantsClient) Update(ctx context.Context, oAuth2PermissionGrant OAuth2PermissionGrant) (int, error) {
+	var status int
+
+	if oAuth2PermissionGrant.ID == nil {
+		return status, errors.New("OAuth2PermissionGrantsClient.Update(): cannot update OAuth2PermissionGrant with nil ID")
+	}
+
+	body, err := json.Marshal(OAuth2PermissionGrant{
+		Scope: oAuth2PermissionGrant.Scope,
+	})
+	if err != nil {
+		return status, fmt.Errorf("json.Marshal(): %v", err)
+	}
+
+	_, status, _, err = c.BaseClient.Patch(ctx, PatchHttpRequestInput{
+		Body:                   body,
+		ConsistencyFailureFunc: RetryOn404ConsistencyFailureFunc,
+		ValidStatusCodes:       []int{http.StatusNoContent},
+		Uri: Uri{
+			Entity:      fmt.Sprintf("/oauth2PermissionGrants/%s", *oAuth2PermissionGrant.ID),
+			HasTenantId: true,
+		},
+	})
+	if err != nil {
+		return status, fmt.Errorf("OAuth2PermissionGrantsClient.BaseClient.Patch(): %w", err)
+	}
+
+	return status, nil
+}
+
+// Delete removes an OAuth2PermissionGrant.
+func (c *OAuth2PermissionGrantsClient) Delete(ctx context.Context, id string) (int, error) {
+	_, status, _, err := c.BaseClient.Delete(ctx, DeleteHttpRequestInput{
+		ConsistencyFailureFunc: RetryOn404ConsistencyFailureFunc,
+		ValidStatusCodes:       []int{http.StatusNoContent},
+		Uri: Uri{
+			Entity:      fmt.Sprintf("/oauth2PermissionGrants/%s", id),
+			HasTenantId: true,
+		},
+	})
+	if err != nil {
+		return status, fmt.Errorf("OAuth2PermissionGrantsClient.BaseClient.Delete(): %w", err)
+	}
+
+	return status, nil
+}
@@ -0,0 +1,136 @@
+package msgraph_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/manicminer/hamilton/auth"
+	"github.com/manicminer/hamilton/internal/test"
+	"github.com/manicminer/hamilton/internal/utils"
+	"github.com/manicminer/hamilton/msgraph"
+	"github.com/manicminer/hamilton/odata"
+)
+
+type OAuth2PermissionGrantsClientTest struct {
+	connection   *test.Connection
+	client       *msgraph.OAuth2PermissionGrantsClient
+	randomString string
+}
+
+func TestOAuth2PermissionGrantsClient(t *testing.T) {
+	rs := test.RandomString()
+	c := OAuth2PermissionGrantsClientTest{
+		connection:   test.NewConnection(auth.MsGraph, auth.TokenVersion2),
+		randomString: rs,
+	}
+	c.client = msgraph.NewOAuth2PermissionGrantsClient(c.connection.AuthConfig.TenantID)
+	c.client.BaseClient.Authorizer = c.connection.Authorizer
+
+	a := ApplicationsClientTest{
+		connection:   test.NewConnection(auth.MsGraph, auth.TokenVersion2),
+		randomString: rs,
+	}
+	a.client = msgraph.NewApplicationsClient(a.connection.AuthConfig.TenantID)
+	a.client.BaseClient.Authorizer = a.connection.Authorizer
+
+	s := ServicePrincipalsClientTest{
+		connection:   test.NewConnection(auth.MsGraph, auth.TokenVersion2),
+		randomString: rs,
+	}
+	s.client = msgraph.NewServicePrincipalsClient(s.connection.AuthConfig.TenantID)
+	s.client.BaseClient.Authorizer = s.connection.Authorizer
+
+	app := testApplicationsClient_Create(t, a, msgraph.Application{
+		DisplayName: utils.StringPtr(fmt.Sprintf("test-oauth2PermissionGrant-%s", a.randomString)),
+	})
+	sp := testServicePrincipalsClient_Create(t, s, msgraph.ServicePrincipal{
+		AccountEnabled: utils.BoolPtr(true),
+		AppId:          app.AppId,
+		DisplayName:    app.DisplayName,
+	})
+
+	msGraphSps, _, err := s.client.List(s.connection.Context, odata.Query{Filter: "appId eq '00000003-0000-0000-c000-000000000000'"})
+	if err != nil {
+		t.Fatalf("ServicePrincipalsClient.List(): %v", err)
+	}
+	if msGraphSps == nil || len(*msGraphSps) != 1 {
+		t.Fatal("ServicePrincipalsClient.List(): could not find Microsoft Graph service principal")
+	}
+
+	grant := testOAuth2PermissionGrantsClient_Create(t, c, msgraph.OAuth2PermissionGrant{
+		ClientId:    sp.ID,
+		ConsentType: utils.StringPtr(msgraph.OAuth2PermissionGrantConsentTypeAllPrincipals),
+		ResourceId:  (*msGraphSps)[0].ID,
+		Scope:       utils.StringPtr("User.Read"),
+	})
+	testOAuth2PermissionGrantsClient_Get(t, c, *grant.ID)
+	grant.Scope = utils.StringPtr("User.Read openid")
+	testOAuth2PermissionGrantsClient_Update(t, c, *grant)
+	testOAuth2PermissionGrantsClient_List(t, c, *sp.ID)
+	testOAuth2PermissionGrantsClient_Delete(t, c, *grant.ID)
+
+	testServicePrincipalsClient_Delete(t, s, *sp.ID)
+	testApplicationsClient_Delete(t, a, *app.ID)
+}
+
+func testOAuth2PermissionGrantsClient_Create(t *testing.T, c OAuth2PermissionGrantsClientTest, g msgraph.OAuth2PermissionGrant) (grant *msgraph.OAuth2PermissionGrant) {
+	grant, status, err := c.client.Create(c.connection.Context, g)
+	if err != nil {
+		t.Fatalf("OAuth2PermissionGrantsClient.Create(): %v", err)
+	}
+	if status < 200 || status >= 300 {
+		t.Fatalf("OAuth2PermissionGrantsClient.Create(): invalid status: %d", status)
+	}
+	if grant == nil {
+		t.Fatal("OAuth2PermissionGrantsClient.Create(): grant was nil")
+	}
+	if grant.ID == nil {
+		t.Fatal("OAuth2PermissionGrantsClient.Create(): grant.ID was nil")
+	}
+	return
+}
+
+func testOAuth2PermissionGrantsClient_Get(t *testing.T, c OAuth2PermissionGrantsClientTest, id string) (grant *msgraph.OAuth2PermissionGrant) {
+	grant, status, err := c.client.Get(c.connection.Context, id, odata.Query{})
+	if err != nil {
+		t.Fatalf("OAuth2PermissionGrantsClient.Get(): %v", err)
+	}
+	if status < 200 || status >= 300 {
+		t.Fatalf("OAuth2PermissionGrantsClient.Get(): invalid status: %d", status)
+	}
+	if grant == nil {
+		t.Fatal("OAuth2PermissionGrantsClient.Get(): grant was nil")
+	}
+	return
+}
+
+func testOAuth2PermissionGrantsClient_Update(t *testing.T, c OAuth2PermissionGrantsClientTest, g msgraph.OAuth2PermissionGrant) {
+	status, err := c.client.Update(c.connection.Context, g)
+	if err != nil {
+		t.Fatalf("OAuth2PermissionGrantsClient.Update(): %v", err)
+	}
+	if status < 200 || status >= 300 {
+		t.Fatalf("OAuth2PermissionGrantsClient.Update(): invalid status: %d", status)
+	}
+}
+
+func testOAuth2PermissionGrantsClient_List(t *testing.T, c OAuth2PermissionGrantsClientTest, clientId string) (grants *[]msgraph.OAuth2PermissionGrant) {
+	grants, _, err := c.client.List(c.connection.Context, odata.Query{Filter: fmt.Sprintf("clientId eq '%s'", clientId)})
+	if err != nil {
+		t.Fatalf("OAuth2PermissionGrantsClient.List(): %v", err)
+	}
+	if grants == nil {
+		t.Fatal("OAuth2PermissionGrantsClient.List(): grants was nil")
+	}
+	return
+}
+
+func testOAuth2PermissionGrantsClient_Delete(t *testing.T, c OAuth2PermissionGrantsClientTest, id string) {
+	status, err := c.client.Delete(c.connection.Context, id)
+	if err != nil {
+		t.Fatalf("OAuth2PermissionGrantsClient.Delete(): %v", err)
+	}
+	if status < 200 || status >= 300 {
+		t.Fatalf("OAuth2PermissionGrantsClient.Delete(): invalid status: %d", status)
+	}
+}
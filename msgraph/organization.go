@@ -0,0 +1,101 @@
+package msgraph
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/manicminer/hamilton/odata"
+)
+
+// OrganizationClient performs operations on the Organization of the tenant.
+type OrganizationClient struct {
+	BaseClient Client
+}
+
+// NewOrganizationClient returns a new OrganizationClient.
+func NewOrganizationClient(tenantId string) *OrganizationClient {
+	return &OrganizationClient{
+		BaseClient: NewClient(Version10, tenantId),
+	}
+}
+
+// List returns a list of Organizations. The collection always contains exactly one Organization, describing the tenant.
+func (c *OrganizationClient) List(ctx context.Context, query odata.Query) (*[]Organization, int, error) {
+	resp, status, _, err := c.BaseClient.Get(ctx, GetHttpRequestInput{
+		ConsistencyLevel: query.ConsistencyLevel,
+		ValidStatusCodes: []int{http.StatusOK},
+		Uri: Uri{
+			Entity:      "/organization",
+			Params:      query.Values(),
+			HasTenantId: true,
+		},
+	})
+	if err != nil {
+		return nil, status, fmt.Errorf("OrganizationClient.BaseClient.Get(): %w", err)
+	}
+
+	defer resp.Body.Close()
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, status, fmt.Errorf("io.ReadAll(): %v", err)
+	}
+
+	var data struct {
+		Organizations []Organization `json:"value"`
+	}
+	if err := c.BaseClient.decode(respBody, &data); err != nil {
+		return nil, status, fmt.Errorf("json.Unmarshal(): %v", err)
+	}
+
+	return &data.Organizations, status, nil
+}
+
+// Get retrieves an Organization.
+// id is the ID of the tenant.
+func (c *OrganizationClient) Get(ctx context.Context, id string, query odata.Query) (*Organization, int, error) {
+	resp, status, _, err := c.BaseClient.Get(ctx, GetHttpRequestInput{
+		ConsistencyFailureFunc: RetryOn404ConsistencyFailureFunc,
+		ConsistencyLevel:       query.ConsistencyLevel,
+		ValidStatusCodes:       []int{http.StatusOK},
+		Uri: Uri{
+			Entity:      fmt.Sprintf("/organization/%s", id),
+			Params:      query.Values(),
+			HasTenantId: true,
+		},
+	})
+	if err != nil {
+		return nil, status, fmt.Errorf("OrganizationClient.BaseClient.Get(): %w", err)
+	}
+
+	defer resp.Body.Close()
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, status, fmt.Errorf("io.ReadAll(): %v", err)
+	}
+
+	var organization Organization
+	if err := c.BaseClient.decode(respBody, &organization); err != nil {
+		return nil, status, fmt.Errorf("json.Unmarshal(): %v", err)
+	}
+
+	return &organization, status, nil
+}
+
+// GetTenantDetails retrieves the Organization for the tenant of the authenticated principal, without needing to know its ID.
+func (c *OrganizationClient) GetTenantDetails(ctx context.Context, query odata.Query) (*Organization, int, error) {
+	organizations, status, err := c.List(ctx, query)
+	if err != nil {
+		return nil, status, fmt.Errorf("OrganizationClient.List(): %v", err)
+	}
+	if organizations == nil || len(*organizations) != 1 {
+		count := 0
+		if organizations != nil {
+			count = len(*organizations)
+		}
+		return nil, status, fmt.Errorf("OrganizationClient.List(): expected 1 organization, received %d", count)
+	}
+
+	return &(*organizations)[0], status, nil
+}
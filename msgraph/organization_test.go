@@ -0,0 +1,77 @@
+package msgraph_test
+
+import (
+	"testing"
+
+	"github.com/manicminer/hamilton/auth"
+	"github.com/manicminer/hamilton/internal/test"
+	"github.com/manicminer/hamilton/msgraph"
+	"github.com/manicminer/hamilton/odata"
+)
+
+type OrganizationClientTest struct {
+	connection   *test.Connection
+	client       *msgraph.OrganizationClient
+	randomString string
+}
+
+func TestOrganizationClient(t *testing.T) {
+	c := OrganizationClientTest{
+		connection:   test.NewConnection(auth.MsGraph, auth.TokenVersion2),
+		randomString: test.RandomString(),
+	}
+	c.client = msgraph.NewOrganizationClient(c.connection.AuthConfig.TenantID)
+	c.client.BaseClient.Authorizer = c.connection.Authorizer
+
+	organizations := testOrganizationClient_List(t, c)
+	organization := testOrganizationClient_Get(t, c, *(*organizations)[0].ID)
+	tenant := testOrganizationClient_GetTenantDetails(t, c)
+	if *tenant.ID != *organization.ID {
+		t.Fatal("OrganizationClient.GetTenantDetails(): organization ids do not match")
+	}
+	if tenant.DefaultDomain() == nil {
+		t.Fatal("OrganizationClient.GetTenantDetails(): no default domain found")
+	}
+}
+
+func testOrganizationClient_List(t *testing.T, c OrganizationClientTest) (organizations *[]msgraph.Organization) {
+	organizations, _, err := c.client.List(c.connection.Context, odata.Query{})
+	if err != nil {
+		t.Fatalf("OrganizationClient.List(): %v", err)
+	}
+	if organizations == nil {
+		t.Fatal("OrganizationClient.List(): organizations was nil")
+	}
+	if len(*organizations) != 1 {
+		t.Fatalf("OrganizationClient.List(): expected 1 organization. was: %d", len(*organizations))
+	}
+	return
+}
+
+func testOrganizationClient_Get(t *testing.T, c OrganizationClientTest, id string) (organization *msgraph.Organization) {
+	organization, status, err := c.client.Get(c.connection.Context, id, odata.Query{})
+	if err != nil {
+		t.Fatalf("OrganizationClient.Get(): %v", err)
+	}
+	if status < 200 || status >= 300 {
+		t.Fatalf("OrganizationClient.Get(): invalid status: %d", status)
+	}
+	if organization == nil {
+		t.Fatal("OrganizationClient.Get(): organization was nil")
+	}
+	return
+}
+
+func testOrganizationClient_GetTenantDetails(t *testing.T, c OrganizationClientTest) (organization *msgraph.Organization) {
+	organization, status, err := c.client.GetTenantDetails(c.connection.Context, odata.Query{})
+	if err != nil {
+		t.Fatalf("OrganizationClient.GetTenantDetails(): %v", err)
+	}
+	if status < 200 || status >= 300 {
+		t.Fatalf("OrganizationClient.GetTenantDetails(): invalid status: %d", status)
+	}
+	if organization == nil {
+		t.Fatal("OrganizationClient.GetTenantDetails(): organization was nil")
+	}
+	return
+}
@@ -0,0 +1,314 @@
+package msgraph
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/manicminer/hamilton/odata"
+)
+
+// brandingDefaultLocale is the localization ID used to address images belonging to the default branding
+const brandingDefaultLocale = "0"
+
+// OrganizationalBrandingClient performs operations on the sign-in page branding of an Organization, including its
+// localizations and images.
+type OrganizationalBrandingClient struct {
+	BaseClient Client
+}
+
+// NewOrganizationalBrandingClient returns a new OrganizationalBrandingClient.
+func NewOrganizationalBrandingClient(tenantId string) *OrganizationalBrandingClient {
+	return &OrganizationalBrandingClient{
+		BaseClient: NewClient(Version10, tenantId),
+	}
+}
+
+// Get retrieves the default OrganizationalBranding for a tenant. A 404 status is returned when no branding has been
+// configured.
+// organizationId is the ID of the tenant.
+func (c *OrganizationalBrandingClient) Get(ctx context.Context, organizationId string, query odata.Query) (*OrganizationalBranding, int, error) {
+	resp, status, _, err := c.BaseClient.Get(ctx, GetHttpRequestInput{
+		ConsistencyLevel: query.ConsistencyLevel,
+		ValidStatusCodes: []int{http.StatusOK},
+		Uri: Uri{
+			Entity:      fmt.Sprintf("/organization/%s/branding", organizationId),
+			Params:      query.Values(),
+			HasTenantId: true,
+		},
+	})
+	if err != nil {
+		return nil, status, fmt.Errorf("OrganizationalBrandingClient.BaseClient.Get(): %w", err)
+	}
+
+	defer resp.Body.Close()
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, status, fmt.Errorf("io.ReadAll(): %v", err)
+	}
+
+	var branding OrganizationalBranding
+	if err := c.BaseClient.decode(respBody, &branding); err != nil {
+		return nil, status, fmt.Errorf("json.Unmarshal(): %v", err)
+	}
+
+	return &branding, status, nil
+}
+
+// Update amends the default OrganizationalBranding for a tenant, creating it if it has not yet been configured.
+// organizationId is the ID of the tenant.
+func (c *OrganizationalBrandingClient) Update(ctx context.Context, organizationId string, branding OrganizationalBranding) (int, error) {
+	var status int
+
+	body, err := json.Marshal(branding)
+	if err != nil {
+		return status, fmt.Errorf("json.Marshal(): %v", err)
+	}
+
+	_, status, _, err = c.BaseClient.Patch(ctx, PatchHttpRequestInput{
+		Body:             body,
+		ValidStatusCodes: []int{http.StatusNoContent},
+		Uri: Uri{
+			Entity:      fmt.Sprintf("/organization/%s/branding", organizationId),
+			HasTenantId: true,
+		},
+	})
+	if err != nil {
+		return status, fmt.Errorf("OrganizationalBrandingClient.BaseClient.Patch(): %w", err)
+	}
+
+	return status, nil
+}
+
+// Delete removes the default OrganizationalBranding for a tenant. All localizations must be deleted first.
+// organizationId is the ID of the tenant.
+func (c *OrganizationalBrandingClient) Delete(ctx context.Context, organizationId string) (int, error) {
+	_, status, _, err := c.BaseClient.Delete(ctx, DeleteHttpRequestInput{
+		ValidStatusCodes: []int{http.StatusNoContent},
+		Uri: Uri{
+			Entity:      fmt.Sprintf("/organization/%s/branding", organizationId),
+			HasTenantId: true,
+		},
+	})
+	if err != nil {
+		return status, fmt.Errorf("OrganizationalBrandingClient.BaseClient.Delete(): %w", err)
+	}
+
+	return status, nil
+}
+
+// ListLocalizations returns the localizations of the OrganizationalBranding for a tenant, optionally queried using OData.
+// The default branding is included with an ID of `0`.
+// organizationId is the ID of the tenant.
+func (c *OrganizationalBrandingClient) ListLocalizations(ctx context.Context, organizationId string, query odata.Query) (*[]OrganizationalBrandingLocalization, int, error) {
+	resp, status, _, err := c.BaseClient.Get(ctx, GetHttpRequestInput{
+		ConsistencyLevel: query.ConsistencyLevel,
+		DisablePaging:    query.Top > 0,
+		ValidStatusCodes: []int{http.StatusOK},
+		Uri: Uri{
+			Entity:      fmt.Sprintf("/organization/%s/branding/localizations", organizationId),
+			Params:      query.Values(),
+			HasTenantId: true,
+		},
+	})
+	if err != nil {
+		return nil, status, fmt.Errorf("OrganizationalBrandingClient.BaseClient.Get(): %w", err)
+	}
+
+	defer resp.Body.Close()
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, status, fmt.Errorf("io.ReadAll(): %v", err)
+	}
+
+	var data struct {
+		Localizations []OrganizationalBrandingLocalization `json:"value"`
+	}
+	if err := c.BaseClient.decode(respBody, &data); err != nil {
+		return nil, status, fmt.Errorf("json.Unmarshal(): %v", err)
+	}
+
+	return &data.Localizations, status, nil
+}
+
+// CreateLocalization creates a new OrganizationalBrandingLocalization. The ID must be set to the locale being branded.
+// organizationId is the ID of the tenant.
+func (c *OrganizationalBrandingClient) CreateLocalization(ctx context.Context, organizationId string, localization OrganizationalBrandingLocalization) (*OrganizationalBrandingLocalization, int, error) {
+	var status int
+
+	if localization.ID == nil {
+		return nil, status, errors.New("OrganizationalBrandingClient.CreateLocalization(): cannot create localization with nil ID")
+	}
+
+	body, err := json.Marshal(localization)
+	if err != nil {
+		return nil, status, fmt.Errorf("json.Marshal(): %v", err)
+	}
+
+	resp, status, _, err := c.BaseClient.Post(ctx, PostHttpRequestInput{
+		Body:             body,
+		ValidStatusCodes: []int{http.StatusCreated},
+		Uri: Uri{
+			Entity:      fmt.Sprintf("/organization/%s/branding/localizations", organizationId),
+			HasTenantId: true,
+		},
+	})
+	if err != nil {
+		return nil, status, fmt.Errorf("OrganizationalBrandingClient.BaseClient.Post(): %w", err)
+	}
+
+	defer resp.Body.Close()
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, status, fmt.Errorf("io.ReadAll(): %v", err)
+	}
+
+	var newLocalization OrganizationalBrandingLocalization
+	if err := c.BaseClient.decode(respBody, &newLocalization); err != nil {
+		return nil, status, fmt.Errorf("json.Unmarshal(): %v", err)
+	}
+
+	return &newLocalization, status, nil
+}
+
+// GetLocalization retrieves an OrganizationalBrandingLocalization.
+// organizationId is the ID of the tenant.
+// locale is the ID of the localization, e.g. `fr-FR`.
+func (c *OrganizationalBrandingClient) GetLocalization(ctx context.Context, organizationId, locale string, query odata.Query) (*OrganizationalBrandingLocalization, int, error) {
+	resp, status, _, err := c.BaseClient.Get(ctx, GetHttpRequestInput{
+		ConsistencyFailureFunc: RetryOn404ConsistencyFailureFunc,
+		ConsistencyLevel:       query.ConsistencyLevel,
+		ValidStatusCodes:       []int{http.StatusOK},
+		Uri: Uri{
+			Entity:      fmt.Sprintf("/organization/%s/branding/localizations/%s", organizationId, locale),
+			Params:      query.Values(),
+			HasTenantId: true,
+		},
+	})
+	if err != nil {
+		return nil, status, fmt.Errorf("OrganizationalBrandingClient.BaseClient.Get(): %w", err)
+	}
+
+	defer resp.Body.Close()
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, status, fmt.Errorf("io.ReadAll(): %v", err)
+	}
+
+	var localization OrganizationalBrandingLocalization
+	if err := c.BaseClient.decode(respBody, &localization); err != nil {
+		return nil, status, fmt.Errorf("json.Unmarshal(): %v", err)
+	}
+
+	return &localization, status, nil
+}
+
+// UpdateLocalization amends an existing OrganizationalBrandingLocalization.
+// organizationId is the ID of the tenant.
+func (c *OrganizationalBrandingClient) UpdateLocalization(ctx context.Context, organizationId string, localization OrganizationalBrandingLocalization) (int, error) {
+	var status int
+
+	if localization.ID == nil {
+		return status, errors.New("OrganizationalBrandingClient.UpdateLocalization(): cannot update localization with nil ID")
+	}
+
+	body, err := json.Marshal(localization)
+	if err != nil {
+		return status, fmt.Errorf("json.Marshal(): %v", err)
+	}
+
+	_, status, _, err = c.BaseClient.Patch(ctx, PatchHttpRequestInput{
+		Body:                   body,
+		ConsistencyFailureFunc: RetryOn404ConsistencyFailureFunc,
+		ValidStatusCodes:       []int{http.StatusNoContent},
+		Uri: Uri{
+			Entity:      fmt.Sprintf("/organization/%s/branding/localizations/%s", organizationId, *localization.ID),
+			HasTenantId: true,
+		},
+	})
+	if err != nil {
+		return status, fmt.Errorf("OrganizationalBrandingClient.BaseClient.Patch(): %w", err)
+	}
+
+	return status, nil
+}
+
+// DeleteLocalization removes an OrganizationalBrandingLocalization.
+// organizationId is the ID of the tenant.
+// locale is the ID of the localization, e.g. `fr-FR`.
+func (c *OrganizationalBrandingClient) DeleteLocalization(ctx context.Context, organizationId, locale string) (int, error) {
+	_, status, _, err := c.BaseClient.Delete(ctx, DeleteHttpRequestInput{
+		ConsistencyFailureFunc: RetryOn404ConsistencyFailureFunc,
+		ValidStatusCodes:       []int{http.StatusNoContent},
+		Uri: Uri{
+			Entity:      fmt.Sprintf("/organization/%s/branding/localizations/%s", organizationId, locale),
+			HasTenantId: true,
+		},
+	})
+	if err != nil {
+		return status, fmt.Errorf("OrganizationalBrandingClient.BaseClient.Delete(): %w", err)
+	}
+
+	return status, nil
+}
+
+// GetImage retrieves a branding image, returning its content and media type.
+// organizationId is the ID of the tenant.
+// locale is the ID of the localization, or an empty string for the default branding.
+func (c *OrganizationalBrandingClient) GetImage(ctx context.Context, organizationId, locale string, image BrandingImage) ([]byte, string, int, error) {
+	if locale == "" {
+		locale = brandingDefaultLocale
+	}
+
+	resp, status, _, err := c.BaseClient.Get(ctx, GetHttpRequestInput{
+		ValidStatusCodes: []int{http.StatusOK},
+		Uri: Uri{
+			Entity:      fmt.Sprintf("/organization/%s/branding/localizations/%s/%s", organizationId, locale, image),
+			HasTenantId: true,
+		},
+	})
+	if err != nil {
+		return nil, "", status, fmt.Errorf("OrganizationalBrandingClient.BaseClient.Get(): %w", err)
+	}
+
+	defer resp.Body.Close()
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, "", status, fmt.Errorf("io.ReadAll(): %v", err)
+	}
+
+	return respBody, resp.Header.Get("Content-Type"), status, nil
+}
+
+// UploadImage uploads a branding image, replacing any existing image.
+// organizationId is the ID of the tenant.
+// locale is the ID of the localization, or an empty string for the default branding.
+// contentType is the media type of the image, e.g. `image/png`.
+func (c *OrganizationalBrandingClient) UploadImage(ctx context.Context, organizationId, locale string, image BrandingImage, contentType string, content []byte) (int, error) {
+	var status int
+
+	if contentType == "" {
+		return status, errors.New("OrganizationalBrandingClient.UploadImage(): contentType must be specified")
+	}
+	if locale == "" {
+		locale = brandingDefaultLocale
+	}
+
+	_, status, _, err := c.BaseClient.Put(ctx, PutHttpRequestInput{
+		Body:             content,
+		ContentType:      contentType,
+		ValidStatusCodes: []int{http.StatusNoContent},
+		Uri: Uri{
+			Entity:      fmt.Sprintf("/organization/%s/branding/localizations/%s/%s", organizationId, locale, image),
+			HasTenantId: true,
+		},
+	})
+	if err != nil {
+		return status, fmt.Errorf("OrganizationalBrandingClient.BaseClient.Put(): %w", err)
+	}
+
+	return status, nil
+}
@@ -0,0 +1,90 @@
+package msgraph_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/manicminer/hamilton/environments"
+	"github.com/manicminer/hamilton/internal/utils"
+	"github.com/manicminer/hamilton/msgraph"
+)
+
+func TestOrganizationalBrandingClient_Images(t *testing.T) {
+	logo := []byte{0x89, 'P', 'N', 'G', 0x0d, 0x0a, 0x1a, 0x0a}
+	var uploaded []byte
+	var uploadedType string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1.0/00000000-0000-0000-0000-000000000000/organization/00000000-0000-0000-0000-000000000000/branding/localizations/0/bannerLogo" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		switch r.Method {
+		case http.MethodPut:
+			uploaded, _ = io.ReadAll(r.Body)
+			uploadedType = r.Header.Get("Content-Type")
+			w.WriteHeader(http.StatusNoContent)
+		case http.MethodGet:
+			w.Header().Set("Content-Type", uploadedType)
+			w.Write(uploaded)
+		}
+	}))
+	defer server.Close()
+
+	c := msgraph.NewOrganizationalBrandingClient("00000000-0000-0000-0000-000000000000")
+	c.BaseClient.Endpoint = environments.ApiEndpoint(server.URL)
+
+	if _, err := c.UploadImage(context.Background(), "00000000-0000-0000-0000-000000000000", "", msgraph.BrandingImageBannerLogo, "image/png", logo); err != nil {
+		t.Fatalf("OrganizationalBrandingClient.UploadImage(): %v", err)
+	}
+	if uploadedType != "image/png" {
+		t.Fatalf("OrganizationalBrandingClient.UploadImage(): expected Content-Type %q, got %q", "image/png", uploadedType)
+	}
+
+	content, contentType, _, err := c.GetImage(context.Background(), "00000000-0000-0000-0000-000000000000", "", msgraph.BrandingImageBannerLogo)
+	if err != nil {
+		t.Fatalf("OrganizationalBrandingClient.GetImage(): %v", err)
+	}
+	if !bytes.Equal(content, logo) || contentType != "image/png" {
+		t.Fatalf("OrganizationalBrandingClient.GetImage(): expected uploaded image, got %q (%s)", content, contentType)
+	}
+}
+
+func TestOrganizationalBrandingClient_Update(t *testing.T) {
+	var received map[string]interface{}
+	var contentType string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		contentType = r.Header.Get("Content-Type")
+		if err := json.NewDecoder(r.Body).Decode(&received); err != nil {
+			t.Errorf("could not decode request body: %v", err)
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	c := msgraph.NewOrganizationalBrandingClient("00000000-0000-0000-0000-000000000000")
+	c.BaseClient.Endpoint = environments.ApiEndpoint(server.URL)
+
+	if _, err := c.Update(context.Background(), "00000000-0000-0000-0000-000000000000", msgraph.OrganizationalBranding{
+		OrganizationalBrandingProperties: msgraph.OrganizationalBrandingProperties{
+			BackgroundColor: utils.StringPtr("#FFFFFF"),
+			SignInPageText:  utils.StringPtr("Welcome"),
+		},
+	}); err != nil {
+		t.Fatalf("OrganizationalBrandingClient.Update(): %v", err)
+	}
+	if contentType != "application/json; charset=utf-8" {
+		t.Fatalf("OrganizationalBrandingClient.Update(): expected JSON Content-Type, got %q", contentType)
+	}
+	if received["backgroundColor"] != "#FFFFFF" || received["signInPageText"] != "Welcome" || len(received) != 2 {
+		t.Fatalf("OrganizationalBrandingClient.Update(): unexpected request body: %v", received)
+	}
+
+	if _, _, err := c.CreateLocalization(context.Background(), "00000000-0000-0000-0000-000000000000", msgraph.OrganizationalBrandingLocalization{}); err == nil {
+		t.Fatal("OrganizationalBrandingClient.CreateLocalization(): expected error for localization with nil ID")
+	}
+}
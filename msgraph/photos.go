@@ -0,0 +1,71 @@
+package msgraph
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// ErrNotModified is returned when a conditional request finds that a resource has not changed, e.g. when the ETag
+// supplied when retrieving a profile photo matches that of the current photo.
+var ErrNotModified = errors.New("not modified")
+
+// getPhoto retrieves the profile photo at uri, returning its content and media type. When etag is not empty, the
+// photo is only retrieved if it has changed, otherwise ErrNotModified is returned.
+func (c Client) getPhoto(ctx context.Context, uri Uri, etag string) ([]byte, string, int, error) {
+	if etag != "" {
+		headers := HeadersFromContext(ctx).Clone()
+		if headers == nil {
+			headers = http.Header{}
+		}
+		headers.Set("If-None-Match", etag)
+		ctx = context.WithValue(ctx, headersContextKey, headers)
+	}
+
+	uri.Entity = fmt.Sprintf("%s/$value", uri.Entity)
+	resp, status, _, err := c.Get(ctx, GetHttpRequestInput{
+		ValidStatusCodes: []int{http.StatusOK, http.StatusNotModified},
+		Uri:              uri,
+	})
+	if err != nil {
+		return nil, "", status, err
+	}
+
+	defer resp.Body.Close()
+	if status == http.StatusNotModified {
+		return nil, "", status, ErrNotModified
+	}
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, "", status, fmt.Errorf("io.ReadAll(): %v", err)
+	}
+
+	return respBody, resp.Header.Get("Content-Type"), status, nil
+}
+
+// getPhotoMetadata retrieves the ProfilePhoto at uri, including the ETag of the current photo.
+func (c Client) getPhotoMetadata(ctx context.Context, uri Uri) (*ProfilePhoto, int, error) {
+	resp, status, _, err := c.Get(ctx, GetHttpRequestInput{
+		ValidStatusCodes: []int{http.StatusOK},
+		Uri:              uri,
+	})
+	if err != nil {
+		return nil, status, err
+	}
+
+	defer resp.Body.Close()
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, status, fmt.Errorf("io.ReadAll(): %v", err)
+	}
+
+	var photo ProfilePhoto
+	if err := c.decode(respBody, &photo); err != nil {
+		return nil, status, fmt.Errorf("json.Unmarshal(): %v", err)
+	}
+
+	return &photo, status, nil
+}
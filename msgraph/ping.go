@@ -0,0 +1,84 @@
+package msgraph
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/manicminer/hamilton/auth"
+	"github.com/manicminer/hamilton/odata"
+)
+
+// PingResult describes the outcome of a connectivity check performed with Client.Ping.
+type PingResult struct {
+	// Uri is the URI of the endpoint which was probed
+	Uri string
+
+	// Latency is the duration of the request to the probe endpoint, including acquiring an access token
+	Latency time.Duration
+
+	// Status is the HTTP status code returned by the probe endpoint
+	Status int
+
+	// TenantId is the ID of the tenant in which the client is authenticated, as determined from the access token
+	// claims, or else the tenant ID configured for the client
+	TenantId string
+}
+
+// DefaultPingUri returns the Uri probed by Client.Ping when no Uri is specified. This retrieves the ID of the
+// organization, which requires an access token with permission to read the organization.
+func DefaultPingUri() Uri {
+	return Uri{
+		Entity:      "/organization",
+		Params:      odata.Query{Select: []string{"id"}}.Values(),
+		HasTenantId: true,
+	}
+}
+
+// Ping performs a minimal request to validate authentication and connectivity with Microsoft Graph, for use as a
+// pre-flight check. The endpoint probed can be specified with uri, for example `/me` when using delegated
+// authentication. When uri is nil, DefaultPingUri() is used.
+func (c Client) Ping(ctx context.Context, uri *Uri) (*PingResult, int, error) {
+	if uri == nil {
+		u := DefaultPingUri()
+		uri = &u
+	}
+
+	endpoint, err := c.buildUri(*uri)
+	if err != nil {
+		return nil, 0, fmt.Errorf("unable to build probe URI: %v", err)
+	}
+
+	result := PingResult{
+		Uri:      endpoint,
+		TenantId: c.TenantId,
+	}
+
+	start := time.Now()
+	resp, status, _, err := c.Get(ctx, GetHttpRequestInput{
+		DisablePaging:    true,
+		ValidStatusCodes: []int{http.StatusOK},
+		Uri:              *uri,
+	})
+	result.Latency = time.Since(start)
+	result.Status = status
+	if err != nil {
+		return &result, status, fmt.Errorf("Client.Get(): %v", err)
+	}
+	defer resp.Body.Close()
+	if _, err := io.Copy(io.Discard, resp.Body); err != nil {
+		return &result, status, fmt.Errorf("io.Copy(): %v", err)
+	}
+
+	if c.Authorizer != nil {
+		if token, err := auth.TokenWithContext(ctx, c.Authorizer); err == nil {
+			if claims, err := auth.ParseClaims(token); err == nil && claims.TenantId != "" {
+				result.TenantId = claims.TenantId
+			}
+		}
+	}
+
+	return &result, status, nil
+}
@@ -0,0 +1,108 @@
+package msgraph
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// presenceMaxIds is the maximum number of user IDs supported in a single request for presences
+const presenceMaxIds = 650
+
+// PresenceClient reads the presence of users in Microsoft Teams.
+//
+// This client requires the Presence.Read permission to read the presence of the signed-in user, or the
+// Presence.Read.All permission to read the presence of other users. Both delegated and application permissions are
+// supported, however the presence of users can only be read for users in the same tenant as the signed-in user or
+// application.
+type PresenceClient struct {
+	BaseClient Client
+}
+
+// NewPresenceClient returns a new PresenceClient.
+func NewPresenceClient(tenantId string) *PresenceClient {
+	return &PresenceClient{
+		BaseClient: NewClient(Version10, tenantId),
+	}
+}
+
+// Get retrieves the Presence of a user.
+func (c *PresenceClient) Get(ctx context.Context, userId string) (*Presence, int, error) {
+	resp, status, _, err := c.BaseClient.Get(ctx, GetHttpRequestInput{
+		ConsistencyFailureFunc: RetryOn404ConsistencyFailureFunc,
+		ValidStatusCodes:       []int{http.StatusOK},
+		Uri: Uri{
+			Entity:      fmt.Sprintf("/users/%s/presence", userId),
+			HasTenantId: true,
+		},
+	})
+	if err != nil {
+		return nil, status, fmt.Errorf("PresenceClient.BaseClient.Get(): %w", err)
+	}
+
+	defer resp.Body.Close()
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, status, fmt.Errorf("io.ReadAll(): %v", err)
+	}
+
+	var presence Presence
+	if err := c.BaseClient.decode(respBody, &presence); err != nil {
+		return nil, status, fmt.Errorf("json.Unmarshal(): %v", err)
+	}
+
+	return &presence, status, nil
+}
+
+// ListByUserIds retrieves the Presence of multiple users. Requests are made for up to 650 users at a time, which is
+// the most supported by the API.
+func (c *PresenceClient) ListByUserIds(ctx context.Context, userIds []string) (*[]Presence, int, error) {
+	var status int
+	presences := make([]Presence, 0, len(userIds))
+
+	for len(userIds) > 0 {
+		chunk := userIds
+		if len(chunk) > presenceMaxIds {
+			chunk = chunk[:presenceMaxIds]
+		}
+		userIds = userIds[len(chunk):]
+
+		body, err := json.Marshal(struct {
+			Ids []string `json:"ids"`
+		}{Ids: chunk})
+		if err != nil {
+			return nil, status, fmt.Errorf("json.Marshal(): %v", err)
+		}
+
+		resp, respStatus, _, err := c.BaseClient.Post(ctx, PostHttpRequestInput{
+			Body:             body,
+			ValidStatusCodes: []int{http.StatusOK},
+			Uri: Uri{
+				Entity:      "/communications/getPresencesByUserId",
+				HasTenantId: true,
+			},
+		})
+		status = respStatus
+		if err != nil {
+			return nil, status, fmt.Errorf("PresenceClient.BaseClient.Post(): %w", err)
+		}
+
+		respBody, err := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if err != nil {
+			return nil, status, fmt.Errorf("io.ReadAll(): %v", err)
+		}
+
+		var data struct {
+			Presences []Presence `json:"value"`
+		}
+		if err := c.BaseClient.decode(respBody, &data); err != nil {
+			return nil, status, fmt.Errorf("json.Unmarshal(): %v", err)
+		}
+		presences = append(presences, data.Presences...)
+	}
+
+	return &presences, status, nil
+}
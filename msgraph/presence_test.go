@@ -0,0 +1,68 @@
+package msgraph_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/manicminer/hamilton/environments"
+	"github.com/manicminer/hamilton/msgraph"
+)
+
+func TestPresenceClient(t *testing.T) {
+	var batches [][]string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/v1.0/00000000-0000-0000-0000-000000000000/users/11111111-1111-1111-1111-111111111111/presence":
+			fmt.Fprint(w, `{"id":"11111111-1111-1111-1111-111111111111","availability":"Busy","activity":"InAMeeting"}`)
+		case r.Method == http.MethodPost && r.URL.Path == "/v1.0/00000000-0000-0000-0000-000000000000/communications/getPresencesByUserId":
+			var body struct {
+				Ids []string `json:"ids"`
+			}
+			if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+				t.Errorf("could not decode request body: %v", err)
+			}
+			batches = append(batches, body.Ids)
+			presences := make([]string, 0, len(body.Ids))
+			for _, id := range body.Ids {
+				presences = append(presences, fmt.Sprintf(`{"id":%q,"availability":"Available","activity":"Available"}`, id))
+			}
+			fmt.Fprintf(w, `{"value":[%s]}`, strings.Join(presences, ","))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprint(w, `{"error":{"code":"NotFound","message":"not found"}}`)
+		}
+	}))
+	defer server.Close()
+
+	c := msgraph.NewPresenceClient("00000000-0000-0000-0000-000000000000")
+	c.BaseClient.Endpoint = environments.ApiEndpoint(server.URL)
+
+	presence, _, err := c.Get(context.Background(), "11111111-1111-1111-1111-111111111111")
+	if err != nil {
+		t.Fatalf("PresenceClient.Get(): %v", err)
+	}
+	if presence.Availability == nil || *presence.Availability != msgraph.PresenceAvailabilityBusy || presence.Activity == nil || *presence.Activity != msgraph.PresenceActivityInAMeeting {
+		t.Fatalf("PresenceClient.Get(): unexpected presence %+v", presence)
+	}
+
+	userIds := make([]string, 700)
+	for i := range userIds {
+		userIds[i] = fmt.Sprintf("00000000-0000-0000-0000-%012d", i)
+	}
+	presences, _, err := c.ListByUserIds(context.Background(), userIds)
+	if err != nil {
+		t.Fatalf("PresenceClient.ListByUserIds(): %v", err)
+	}
+	if len(batches) != 2 || len(batches[0]) != 650 || len(batches[1]) != 50 {
+		t.Fatalf("PresenceClient.ListByUserIds(): expected requests for 650 and 50 users, got %d requests", len(batches))
+	}
+	if presences == nil || len(*presences) != 700 || *(*presences)[699].ID != userIds[699] || *(*presences)[0].Availability != msgraph.PresenceAvailabilityAvailable {
+		t.Fatalf("PresenceClient.ListByUserIds(): unexpected presences")
+	}
+}
@@ -2,7 +2,6 @@ package msgraph
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
@@ -24,6 +23,7 @@ func NewReportsClient(tenantId string) *ReportsClient {
 
 func (c *ReportsClient) GetCredentialUserRegistrationCount(ctx context.Context, query odata.Query) (*[]CredentialUserRegistrationCount, int, error) {
 	resp, status, _, err := c.BaseClient.Get(ctx, GetHttpRequestInput{
+		ConsistencyLevel: query.ConsistencyLevel,
 		DisablePaging:    query.Top > 0,
 		ValidStatusCodes: []int{http.StatusOK},
 		Uri: Uri{
@@ -33,7 +33,7 @@ func (c *ReportsClient) GetCredentialUserRegistrationCount(ctx context.Context,
 		},
 	})
 	if err != nil {
-		return nil, status, fmt.Errorf("ReportsClient.BaseClient.Get(): %v", err)
+		return nil, status, fmt.Errorf("ReportsClient.BaseClient.Get(): %w", err)
 	}
 
 	defer resp.Body.Close()
@@ -45,7 +45,7 @@ func (c *ReportsClient) GetCredentialUserRegistrationCount(ctx context.Context,
 	var data struct {
 		CredentialUserRegistrationCount []CredentialUserRegistrationCount `json:"value"`
 	}
-	if err := json.Unmarshal(respBody, &data); err != nil {
+	if err := c.BaseClient.decode(respBody, &data); err != nil {
 		return nil, status, fmt.Errorf("json.Unmarshal(): %v", err)
 	}
 
@@ -54,6 +54,7 @@ func (c *ReportsClient) GetCredentialUserRegistrationCount(ctx context.Context,
 
 func (c *ReportsClient) GetCredentialUserRegistrationDetails(ctx context.Context, query odata.Query) (*[]CredentialUserRegistrationDetails, int, error) {
 	resp, status, _, err := c.BaseClient.Get(ctx, GetHttpRequestInput{
+		ConsistencyLevel: query.ConsistencyLevel,
 		DisablePaging:    query.Top > 0,
 		ValidStatusCodes: []int{http.StatusOK},
 		Uri: Uri{
@@ -63,7 +64,7 @@ func (c *ReportsClient) GetCredentialUserRegistrationDetails(ctx context.Context
 		},
 	})
 	if err != nil {
-		return nil, status, fmt.Errorf("ReportsClient.BaseClient.Get(): %v", err)
+		return nil, status, fmt.Errorf("ReportsClient.BaseClient.Get(): %w", err)
 	}
 
 	defer resp.Body.Close()
@@ -75,7 +76,7 @@ func (c *ReportsClient) GetCredentialUserRegistrationDetails(ctx context.Context
 	var data struct {
 		CredentialUserRegistrationDetails []CredentialUserRegistrationDetails `json:"value"`
 	}
-	if err := json.Unmarshal(respBody, &data); err != nil {
+	if err := c.BaseClient.decode(respBody, &data); err != nil {
 		return nil, status, fmt.Errorf("json.Unmarshal(): %v", err)
 	}
 
@@ -84,6 +85,7 @@ func (c *ReportsClient) GetCredentialUserRegistrationDetails(ctx context.Context
 
 func (c *ReportsClient) GetUserCredentialUsageDetails(ctx context.Context, query odata.Query) (*[]UserCredentialUsageDetails, int, error) {
 	resp, status, _, err := c.BaseClient.Get(ctx, GetHttpRequestInput{
+		ConsistencyLevel: query.ConsistencyLevel,
 		DisablePaging:    query.Top > 0,
 		ValidStatusCodes: []int{http.StatusOK},
 		Uri: Uri{
@@ -93,7 +95,7 @@ func (c *ReportsClient) GetUserCredentialUsageDetails(ctx context.Context, query
 		},
 	})
 	if err != nil {
-		return nil, status, fmt.Errorf("ReportsClient.BaseClient.Get(): %v", err)
+		return nil, status, fmt.Errorf("ReportsClient.BaseClient.Get(): %w", err)
 	}
 
 	defer resp.Body.Close()
@@ -105,7 +107,7 @@ func (c *ReportsClient) GetUserCredentialUsageDetails(ctx context.Context, query
 	var data struct {
 		UserCredentialUsageDetails []UserCredentialUsageDetails `json:"value"`
 	}
-	if err := json.Unmarshal(respBody, &data); err != nil {
+	if err := c.BaseClient.decode(respBody, &data); err != nil {
 		return nil, status, fmt.Errorf("json.Unmarshal(): %v", err)
 	}
 
@@ -114,6 +116,7 @@ func (c *ReportsClient) GetUserCredentialUsageDetails(ctx context.Context, query
 
 func (c *ReportsClient) GetCredentialUsageSummary(ctx context.Context, period CredentialUsageSummaryPeriod, query odata.Query) (*[]CredentialUsageSummary, int, error) {
 	resp, status, _, err := c.BaseClient.Get(ctx, GetHttpRequestInput{
+		ConsistencyLevel: query.ConsistencyLevel,
 		DisablePaging:    query.Top > 0,
 		ValidStatusCodes: []int{http.StatusOK},
 		Uri: Uri{
@@ -123,7 +126,7 @@ func (c *ReportsClient) GetCredentialUsageSummary(ctx context.Context, period Cr
 		},
 	})
 	if err != nil {
-		return nil, status, fmt.Errorf("ReportsClient.BaseClient.Get(): %v", err)
+		return nil, status, fmt.Errorf("ReportsClient.BaseClient.Get(): %w", err)
 	}
 
 	defer resp.Body.Close()
@@ -135,7 +138,7 @@ func (c *ReportsClient) GetCredentialUsageSummary(ctx context.Context, period Cr
 	var data struct {
 		CredentialUsageSummary []CredentialUsageSummary `json:"value"`
 	}
-	if err := json.Unmarshal(respBody, &data); err != nil {
+	if err := c.BaseClient.decode(respBody, &data); err != nil {
 		return nil, status, fmt.Errorf("json.Unmarshal(): %v", err)
 	}
 
@@ -144,6 +147,7 @@ func (c *ReportsClient) GetCredentialUsageSummary(ctx context.Context, period Cr
 
 func (c *ReportsClient) GetAuthenticationMethodsUsersRegisteredByFeature(ctx context.Context, query odata.Query) (*UserRegistrationFeatureSummary, int, error) {
 	resp, status, _, err := c.BaseClient.Get(ctx, GetHttpRequestInput{
+		ConsistencyLevel: query.ConsistencyLevel,
 		DisablePaging:    query.Top > 0,
 		ValidStatusCodes: []int{http.StatusOK},
 		Uri: Uri{
@@ -153,7 +157,7 @@ func (c *ReportsClient) GetAuthenticationMethodsUsersRegisteredByFeature(ctx con
 		},
 	})
 	if err != nil {
-		return nil, status, fmt.Errorf("ReportsClient.BaseClient.Get(): %v", err)
+		return nil, status, fmt.Errorf("ReportsClient.BaseClient.Get(): %w", err)
 	}
 
 	defer resp.Body.Close()
@@ -163,7 +167,7 @@ func (c *ReportsClient) GetAuthenticationMethodsUsersRegisteredByFeature(ctx con
 	}
 
 	var userRegistrationFeatureSummary UserRegistrationFeatureSummary
-	if err := json.Unmarshal(respBody, &userRegistrationFeatureSummary); err != nil {
+	if err := c.BaseClient.decode(respBody, &userRegistrationFeatureSummary); err != nil {
 		return nil, status, fmt.Errorf("json.Unmarshal(): %v", err)
 	}
 
@@ -172,6 +176,7 @@ func (c *ReportsClient) GetAuthenticationMethodsUsersRegisteredByFeature(ctx con
 
 func (c *ReportsClient) GetAuthenticationMethodsUsersRegisteredByMethod(ctx context.Context, query odata.Query) (*UserRegistrationMethodSummary, int, error) {
 	resp, status, _, err := c.BaseClient.Get(ctx, GetHttpRequestInput{
+		ConsistencyLevel: query.ConsistencyLevel,
 		DisablePaging:    query.Top > 0,
 		ValidStatusCodes: []int{http.StatusOK},
 		Uri: Uri{
@@ -181,7 +186,7 @@ func (c *ReportsClient) GetAuthenticationMethodsUsersRegisteredByMethod(ctx cont
 		},
 	})
 	if err != nil {
-		return nil, status, fmt.Errorf("ReportsClient.BaseClient.Get(): %v", err)
+		return nil, status, fmt.Errorf("ReportsClient.BaseClient.Get(): %w", err)
 	}
 
 	defer resp.Body.Close()
@@ -191,7 +196,7 @@ func (c *ReportsClient) GetAuthenticationMethodsUsersRegisteredByMethod(ctx cont
 	}
 
 	var userRegistrationMethodSummary UserRegistrationMethodSummary
-	if err := json.Unmarshal(respBody, &userRegistrationMethodSummary); err != nil {
+	if err := c.BaseClient.decode(respBody, &userRegistrationMethodSummary); err != nil {
 		return nil, status, fmt.Errorf("json.Unmarshal(): %v", err)
 	}
 
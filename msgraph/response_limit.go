@@ -0,0 +1,32 @@
+package msgraph
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// ErrResponseTooLarge is returned when a response body, or the combined bodies of a paged response, exceed the
+// MaxResponseBytes configured for a Client.
+var ErrResponseTooLarge = errors.New("response too large")
+
+// limitResponseBody reads the body of resp, returning an error wrapping ErrResponseTooLarge if it exceeds max bytes,
+// so that an unexpectedly large response is not read into memory in its entirety. The body is replaced so that it can
+// be read again.
+func limitResponseBody(resp *http.Response, max int64) error {
+	if resp == nil || resp.Body == nil || max <= 0 {
+		return nil
+	}
+	body, err := io.ReadAll(io.LimitReader(resp.Body, max+1))
+	resp.Body.Close()
+	if err != nil {
+		return fmt.Errorf("could not read response body: %v", err)
+	}
+	if int64(len(body)) > max {
+		return fmt.Errorf("%w: response body exceeds the limit of %d bytes", ErrResponseTooLarge, max)
+	}
+	resp.Body = io.NopCloser(bytes.NewBuffer(body))
+	return nil
+}
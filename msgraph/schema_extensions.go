@@ -25,6 +25,7 @@ func NewSchemaExtensionsClient(tenantId string) *SchemaExtensionsClient {
 // List returns a list of Schema Extensions, optionally filtered using OData.
 func (c *SchemaExtensionsClient) List(ctx context.Context, query odata.Query) (*[]SchemaExtension, int, error) {
 	resp, status, _, err := c.BaseClient.Get(ctx, GetHttpRequestInput{
+		ConsistencyLevel: query.ConsistencyLevel,
 		DisablePaging:    query.Top > 0,
 		ValidStatusCodes: []int{http.StatusOK},
 		Uri: Uri{
@@ -34,7 +35,7 @@ func (c *SchemaExtensionsClient) List(ctx context.Context, query odata.Query) (*
 		},
 	})
 	if err != nil {
-		return nil, status, fmt.Errorf("SchemaExtensionsClient.BaseClient.Get(): %v", err)
+		return nil, status, fmt.Errorf("SchemaExtensionsClient.BaseClient.Get(): %w", err)
 	}
 
 	defer resp.Body.Close()
@@ -46,7 +47,7 @@ func (c *SchemaExtensionsClient) List(ctx context.Context, query odata.Query) (*
 	var data struct {
 		SchemaExtensions []SchemaExtension `json:"value"`
 	}
-	if err := json.Unmarshal(respBody, &data); err != nil {
+	if err := c.BaseClient.decode(respBody, &data); err != nil {
 		return nil, status, fmt.Errorf("json.Unmarshal(): %v", err)
 	}
 
@@ -57,6 +58,7 @@ func (c *SchemaExtensionsClient) List(ctx context.Context, query odata.Query) (*
 func (c *SchemaExtensionsClient) Get(ctx context.Context, id string, query odata.Query) (*SchemaExtension, int, error) {
 	resp, status, _, err := c.BaseClient.Get(ctx, GetHttpRequestInput{
 		ConsistencyFailureFunc: RetryOn404ConsistencyFailureFunc,
+		ConsistencyLevel:       query.ConsistencyLevel,
 		ValidStatusCodes:       []int{http.StatusOK},
 		Uri: Uri{
 			Entity:      fmt.Sprintf("/schemaExtensions/%s", id),
@@ -65,7 +67,7 @@ func (c *SchemaExtensionsClient) Get(ctx context.Context, id string, query odata
 		},
 	})
 	if err != nil {
-		return nil, status, fmt.Errorf("SchemaExtensionsClient.BaseClient.Get(): %v", err)
+		return nil, status, fmt.Errorf("SchemaExtensionsClient.BaseClient.Get(): %w", err)
 	}
 
 	defer resp.Body.Close()
@@ -75,7 +77,7 @@ func (c *SchemaExtensionsClient) Get(ctx context.Context, id string, query odata
 	}
 
 	var schemaExtension SchemaExtension
-	if err := json.Unmarshal(respBody, &schemaExtension); err != nil {
+	if err := c.BaseClient.decode(respBody, &schemaExtension); err != nil {
 		return nil, status, fmt.Errorf("json.Unmarshal(): %v", err)
 	}
 
@@ -101,12 +103,47 @@ func (c *SchemaExtensionsClient) Update(ctx context.Context, schemaExtension Sch
 		},
 	})
 	if err != nil {
-		return status, fmt.Errorf("SchemaExtensionsClient.BaseClient.Patch(): %v", err)
+		return status, fmt.Errorf("SchemaExtensionsClient.BaseClient.Patch(): %w", err)
 	}
 
 	return status, nil
 }
 
+// SetStatus transitions the lifecycle status of a Schema Extension. Only the owner application can change the status,
+// and a status can only move forward, i.e. from InDevelopment to Available or Deprecated, or from Available to
+// Deprecated. An error is returned without making any changes when the requested transition is not permitted.
+func (c *SchemaExtensionsClient) SetStatus(ctx context.Context, id string, newStatus SchemaExtensionStatus) (int, error) {
+	schemaExtension, status, err := c.Get(ctx, id, odata.Query{Select: []string{"id", "owner", "status"}})
+	if err != nil {
+		return status, err
+	}
+
+	if !schemaExtensionStatusTransitionValid(schemaExtension.Status, newStatus) {
+		return status, fmt.Errorf("cannot change status of schema extension %q from %q to %q", id, schemaExtension.Status, newStatus)
+	}
+
+	return c.Update(ctx, SchemaExtension{
+		ID:     schemaExtension.ID,
+		Owner:  schemaExtension.Owner,
+		Status: newStatus,
+	})
+}
+
+// schemaExtensionStatusTransitionValid determines whether a schema extension can move from one lifecycle status to another
+func schemaExtensionStatusTransitionValid(from, to SchemaExtensionStatus) bool {
+	order := map[SchemaExtensionStatus]int{
+		SchemaExtensionStatusInDevelopment: 0,
+		SchemaExtensionStatusAvailable:     1,
+		SchemaExtensionStatusDeprecated:    2,
+	}
+	f, ok := order[from]
+	if !ok {
+		return false
+	}
+	t, ok := order[to]
+	return ok && t > f
+}
+
 // Create creates a new Schema Extension
 func (c *SchemaExtensionsClient) Create(ctx context.Context, schemaExtension SchemaExtension) (*SchemaExtension, int, error) {
 	var status int
@@ -125,7 +162,7 @@ func (c *SchemaExtensionsClient) Create(ctx context.Context, schemaExtension Sch
 		},
 	})
 	if err != nil {
-		return nil, status, fmt.Errorf("SchemaExtensionsClient.BaseClient.Post(): %v", err)
+		return nil, status, fmt.Errorf("SchemaExtensionsClient.BaseClient.Post(): %w", err)
 	}
 
 	defer resp.Body.Close()
@@ -135,7 +172,7 @@ func (c *SchemaExtensionsClient) Create(ctx context.Context, schemaExtension Sch
 	}
 
 	var newSchemaExtension SchemaExtension
-	if err := json.Unmarshal(respBody, &newSchemaExtension); err != nil {
+	if err := c.BaseClient.decode(respBody, &newSchemaExtension); err != nil {
 		return nil, status, fmt.Errorf("json.Unmarshal(): %v", err)
 	}
 
@@ -153,7 +190,7 @@ func (c *SchemaExtensionsClient) Delete(ctx context.Context, id string) (int, er
 		},
 	})
 	if err != nil {
-		return status, fmt.Errorf("SchemaExtensionsClient.BaseClient.Delete(): %v", err)
+		return status, fmt.Errorf("SchemaExtensionsClient.BaseClient.Delete(): %w", err)
 	}
 
 	return status, nil
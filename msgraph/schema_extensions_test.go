@@ -1,12 +1,17 @@
 package msgraph_test
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
 	"testing"
 	"time"
 
 	"github.com/manicminer/hamilton/auth"
+	"github.com/manicminer/hamilton/environments"
 	"github.com/manicminer/hamilton/internal/test"
 	"github.com/manicminer/hamilton/internal/utils"
 	"github.com/manicminer/hamilton/msgraph"
@@ -324,3 +329,41 @@ func testSchemaExtensionsClient_Delete(t *testing.T, c SchemaExtensionsClientTes
 		t.Fatalf("SchemaExtensionsClient.Delete(): invalid status: %d", status)
 	}
 }
+
+func TestSchemaExtensionsClient_SetStatus(t *testing.T) {
+	currentStatus := msgraph.SchemaExtensionStatusInDevelopment
+	var patched []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/beta/00000000-0000-0000-0000-000000000000/schemaExtensions/extabc_testschema" {
+			t.Errorf("unexpected request: %s %s", r.Method, r.URL.Path)
+		}
+		switch r.Method {
+		case http.MethodGet:
+			w.Header().Set("Content-Type", "application/json")
+			fmt.Fprintf(w, `{"id":"extabc_testschema","owner":"11111111-1111-1111-1111-111111111111","status":%q}`, currentStatus)
+		case http.MethodPatch:
+			body, _ := io.ReadAll(r.Body)
+			patched = append(patched, string(body))
+			w.WriteHeader(http.StatusNoContent)
+		}
+	}))
+	defer server.Close()
+
+	c := msgraph.NewSchemaExtensionsClient("00000000-0000-0000-0000-000000000000")
+	c.BaseClient.Endpoint = environments.ApiEndpoint(server.URL)
+
+	if _, err := c.SetStatus(context.Background(), "extabc_testschema", msgraph.SchemaExtensionStatusAvailable); err != nil {
+		t.Fatalf("SchemaExtensionsClient.SetStatus(): %v", err)
+	}
+	if expected := `{"id":"extabc_testschema","owner":"11111111-1111-1111-1111-111111111111","status":"Available"}`; len(patched) != 1 || patched[0] != expected {
+		t.Fatalf("SchemaExtensionsClient.SetStatus(): expected request body %s, got %v", expected, patched)
+	}
+
+	currentStatus = msgraph.SchemaExtensionStatusDeprecated
+	if _, err := c.SetStatus(context.Background(), "extabc_testschema", msgraph.SchemaExtensionStatusAvailable); err == nil {
+		t.Fatal("SchemaExtensionsClient.SetStatus(): expected error when reverting a deprecated schema extension")
+	}
+	if len(patched) != 1 {
+		t.Fatalf("SchemaExtensionsClient.SetStatus(): expected no request for invalid transition, got %v", patched)
+	}
+}
@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"strings"
 
 	"github.com/manicminer/hamilton/odata"
 )
@@ -26,6 +27,7 @@ func NewServicePrincipalsClient(tenantId string) *ServicePrincipalsClient {
 // List returns a list of Service Principals, optionally queried using OData.
 func (c *ServicePrincipalsClient) List(ctx context.Context, query odata.Query) (*[]ServicePrincipal, int, error) {
 	resp, status, _, err := c.BaseClient.Get(ctx, GetHttpRequestInput{
+		ConsistencyLevel: query.ConsistencyLevel,
 		DisablePaging:    query.Top > 0,
 		ValidStatusCodes: []int{http.StatusOK},
 		Uri: Uri{
@@ -35,7 +37,7 @@ func (c *ServicePrincipalsClient) List(ctx context.Context, query odata.Query) (
 		},
 	})
 	if err != nil {
-		return nil, status, fmt.Errorf("ServicePrincipalsClient.BaseClient.Get(): %v", err)
+		return nil, status, fmt.Errorf("ServicePrincipalsClient.BaseClient.Get(): %w", err)
 	}
 
 	defer resp.Body.Close()
@@ -47,7 +49,7 @@ func (c *ServicePrincipalsClient) List(ctx context.Context, query odata.Query) (
 	var data struct {
 		ServicePrincipals []ServicePrincipal `json:"value"`
 	}
-	if err := json.Unmarshal(respBody, &data); err != nil {
+	if err := c.BaseClient.decode(respBody, &data); err != nil {
 		return nil, status, fmt.Errorf("json.Unmarshal(): %v", err)
 	}
 
@@ -77,7 +79,7 @@ func (c *ServicePrincipalsClient) Create(ctx context.Context, servicePrincipal S
 		},
 	})
 	if err != nil {
-		return nil, status, fmt.Errorf("ServicePrincipalsClient.BaseClient.Post(): %v", err)
+		return nil, status, fmt.Errorf("ServicePrincipalsClient.BaseClient.Post(): %w", err)
 	}
 
 	defer resp.Body.Close()
@@ -87,17 +89,66 @@ func (c *ServicePrincipalsClient) Create(ctx context.Context, servicePrincipal S
 	}
 
 	var newServicePrincipal ServicePrincipal
-	if err := json.Unmarshal(respBody, &newServicePrincipal); err != nil {
+	if err := c.BaseClient.decode(respBody, &newServicePrincipal); err != nil {
 		return nil, status, fmt.Errorf("json.Unmarshal(): %v", err)
 	}
 
 	return &newServicePrincipal, status, nil
 }
 
+// CreateIfNotExists creates a new Service Principal for the application specified by its AppId, unless one already
+// exists in the tenant, in which case the existing Service Principal is returned instead. This is useful for ensuring
+// that a multi-tenant application is present in the current tenant. The returned status is http.StatusCreated when a
+// new Service Principal was created, or http.StatusOK when an existing Service Principal was found.
+func (c *ServicePrincipalsClient) CreateIfNotExists(ctx context.Context, servicePrincipal ServicePrincipal) (*ServicePrincipal, int, error) {
+	var status int
+
+	if servicePrincipal.AppId == nil {
+		return nil, status, errors.New("ServicePrincipalsClient.CreateIfNotExists(): cannot create ServicePrincipal with nil AppId")
+	}
+
+	existing, status, err := c.findByAppId(ctx, *servicePrincipal.AppId)
+	if err != nil {
+		return nil, status, err
+	}
+	if existing != nil {
+		return existing, status, nil
+	}
+
+	newServicePrincipal, status, err := c.Create(ctx, servicePrincipal)
+	if err != nil {
+		// Another caller may have created the service principal in the meantime
+		if status == http.StatusBadRequest || status == http.StatusConflict {
+			existing, findStatus, findErr := c.findByAppId(ctx, *servicePrincipal.AppId)
+			if findErr == nil && existing != nil {
+				return existing, findStatus, nil
+			}
+		}
+		return nil, status, fmt.Errorf("ServicePrincipalsClient.Create(): %v", err)
+	}
+
+	return newServicePrincipal, status, nil
+}
+
+// findByAppId returns the Service Principal for the specified application, or nil if one does not exist.
+func (c *ServicePrincipalsClient) findByAppId(ctx context.Context, appId string) (*ServicePrincipal, int, error) {
+	servicePrincipals, status, err := c.List(ctx, odata.Query{
+		Filter: fmt.Sprintf("appId eq '%s'", appId),
+	})
+	if err != nil {
+		return nil, status, fmt.Errorf("ServicePrincipalsClient.List(): %v", err)
+	}
+	if servicePrincipals == nil || len(*servicePrincipals) == 0 {
+		return nil, status, nil
+	}
+	return &(*servicePrincipals)[0], status, nil
+}
+
 // Get retrieves a Service Principal.
 func (c *ServicePrincipalsClient) Get(ctx context.Context, id string, query odata.Query) (*ServicePrincipal, int, error) {
 	resp, status, _, err := c.BaseClient.Get(ctx, GetHttpRequestInput{
 		ConsistencyFailureFunc: RetryOn404ConsistencyFailureFunc,
+		ConsistencyLevel:       query.ConsistencyLevel,
 		ValidStatusCodes:       []int{http.StatusOK},
 		Uri: Uri{
 			Entity:      fmt.Sprintf("/servicePrincipals/%s", id),
@@ -106,7 +157,7 @@ func (c *ServicePrincipalsClient) Get(ctx context.Context, id string, query odat
 		},
 	})
 	if err != nil {
-		return nil, status, fmt.Errorf("ServicePrincipalsClient.BaseClient.Get(): %v", err)
+		return nil, status, fmt.Errorf("ServicePrincipalsClient.BaseClient.Get(): %w", err)
 	}
 
 	defer resp.Body.Close()
@@ -116,7 +167,7 @@ func (c *ServicePrincipalsClient) Get(ctx context.Context, id string, query odat
 	}
 
 	var servicePrincipal ServicePrincipal
-	if err := json.Unmarshal(respBody, &servicePrincipal); err != nil {
+	if err := c.BaseClient.decode(respBody, &servicePrincipal); err != nil {
 		return nil, status, fmt.Errorf("json.Unmarshal(): %v", err)
 	}
 
@@ -146,12 +197,46 @@ func (c *ServicePrincipalsClient) Update(ctx context.Context, servicePrincipal S
 		},
 	})
 	if err != nil {
-		return status, fmt.Errorf("ServicePrincipalsClient.BaseClient.Patch(): %v", err)
+		return status, fmt.Errorf("ServicePrincipalsClient.BaseClient.Patch(): %w", err)
 	}
 
 	return status, nil
 }
 
+// UpdateAndReturn amends an existing Service Principal as for Update, and returns the updated Service Principal as
+// represented by the API, which saves retrieving it afterwards. Where the API does not return the updated object, the
+// specified service principal is returned instead.
+func (c *ServicePrincipalsClient) UpdateAndReturn(ctx context.Context, servicePrincipal ServicePrincipal) (*ServicePrincipal, int, error) {
+	var status int
+
+	if servicePrincipal.ID == nil {
+		return nil, status, errors.New("cannot update service principal with nil ID")
+	}
+
+	body, err := json.Marshal(servicePrincipal)
+	if err != nil {
+		return nil, status, fmt.Errorf("json.Marshal(): %v", err)
+	}
+
+	var updated ServicePrincipal
+	returned, status, err := c.BaseClient.patchAndReturn(ctx, PatchHttpRequestInput{
+		Body:                   body,
+		ConsistencyFailureFunc: RetryOn404ConsistencyFailureFunc,
+		Uri: Uri{
+			Entity:      fmt.Sprintf("/servicePrincipals/%s", *servicePrincipal.ID),
+			HasTenantId: true,
+		},
+	}, &updated)
+	if err != nil {
+		return nil, status, fmt.Errorf("ServicePrincipalsClient.BaseClient.Patch(): %w", err)
+	}
+	if !returned {
+		return &servicePrincipal, status, nil
+	}
+
+	return &updated, status, nil
+}
+
 // Delete removes a Service Principal.
 func (c *ServicePrincipalsClient) Delete(ctx context.Context, id string) (int, error) {
 	_, status, _, err := c.BaseClient.Delete(ctx, DeleteHttpRequestInput{
@@ -163,7 +248,7 @@ func (c *ServicePrincipalsClient) Delete(ctx context.Context, id string) (int, e
 		},
 	})
 	if err != nil {
-		return status, fmt.Errorf("ServicePrincipalsClient.BaseClient.Delete(): %v", err)
+		return status, fmt.Errorf("ServicePrincipalsClient.BaseClient.Delete(): %w", err)
 	}
 
 	return status, nil
@@ -182,7 +267,7 @@ func (c *ServicePrincipalsClient) ListOwners(ctx context.Context, id string) (*[
 		},
 	})
 	if err != nil {
-		return nil, status, fmt.Errorf("ServicePrincipalsClient.BaseClient.Get(): %v", err)
+		return nil, status, fmt.Errorf("ServicePrincipalsClient.BaseClient.Get(): %w", err)
 	}
 
 	defer resp.Body.Close()
@@ -197,7 +282,7 @@ func (c *ServicePrincipalsClient) ListOwners(ctx context.Context, id string) (*[
 			Id   string `json:"id"`
 		} `json:"value"`
 	}
-	if err := json.Unmarshal(respBody, &data); err != nil {
+	if err := c.BaseClient.decode(respBody, &data); err != nil {
 		return nil, status, fmt.Errorf("json.Unmarshal(): %v", err)
 	}
 
@@ -223,7 +308,7 @@ func (c *ServicePrincipalsClient) GetOwner(ctx context.Context, servicePrincipal
 		},
 	})
 	if err != nil {
-		return nil, status, fmt.Errorf("ServicePrincipalsClient.BaseClient.Get(): %v", err)
+		return nil, status, fmt.Errorf("ServicePrincipalsClient.BaseClient.Get(): %w", err)
 	}
 
 	defer resp.Body.Close()
@@ -238,7 +323,7 @@ func (c *ServicePrincipalsClient) GetOwner(ctx context.Context, servicePrincipal
 		Id      string `json:"id"`
 		Url     string `json:"url"`
 	}
-	if err := json.Unmarshal(respBody, &data); err != nil {
+	if err := c.BaseClient.decode(respBody, &data); err != nil {
 		return nil, status, fmt.Errorf("json.Unmarshal(): %v", err)
 	}
 
@@ -286,7 +371,7 @@ func (c *ServicePrincipalsClient) AddOwners(ctx context.Context, servicePrincipa
 			},
 		})
 		if err != nil {
-			return status, fmt.Errorf("ServicePrincipalsClient.BaseClient.Post(): %v", err)
+			return status, fmt.Errorf("ServicePrincipalsClient.BaseClient.Post(): %w", err)
 		}
 	}
 
@@ -330,7 +415,7 @@ func (c *ServicePrincipalsClient) RemoveOwners(ctx context.Context, servicePrinc
 			},
 		})
 		if err != nil {
-			return status, fmt.Errorf("ServicePrincipalsClient.BaseClient.Delete(): %v", err)
+			return status, fmt.Errorf("ServicePrincipalsClient.BaseClient.Delete(): %w", err)
 		}
 	}
 
@@ -340,6 +425,7 @@ func (c *ServicePrincipalsClient) RemoveOwners(ctx context.Context, servicePrinc
 // ListGroupMemberships returns a list of Groups the Service Principal is member of, optionally queried using OData.
 func (c *ServicePrincipalsClient) ListGroupMemberships(ctx context.Context, id string, query odata.Query) (*[]Group, int, error) {
 	resp, status, _, err := c.BaseClient.Get(ctx, GetHttpRequestInput{
+		ConsistencyLevel:       query.ConsistencyLevel,
 		DisablePaging:          query.Top > 0,
 		ConsistencyFailureFunc: RetryOn404ConsistencyFailureFunc,
 		ValidStatusCodes:       []int{http.StatusOK},
@@ -350,7 +436,7 @@ func (c *ServicePrincipalsClient) ListGroupMemberships(ctx context.Context, id s
 		},
 	})
 	if err != nil {
-		return nil, status, fmt.Errorf("ServicePrincipalsClient.BaseClient.Get(): %v", err)
+		return nil, status, fmt.Errorf("ServicePrincipalsClient.BaseClient.Get(): %w", err)
 	}
 
 	defer resp.Body.Close()
@@ -362,7 +448,7 @@ func (c *ServicePrincipalsClient) ListGroupMemberships(ctx context.Context, id s
 	var data struct {
 		Groups []Group `json:"value"`
 	}
-	if err := json.Unmarshal(respBody, &data); err != nil {
+	if err := c.BaseClient.decode(respBody, &data); err != nil {
 		return nil, status, fmt.Errorf("json.Unmarshal(): %v", err)
 	}
 
@@ -392,7 +478,7 @@ func (c *ServicePrincipalsClient) AddPassword(ctx context.Context, servicePrinci
 		},
 	})
 	if err != nil {
-		return nil, status, fmt.Errorf("ServicePrincipalsClient.BaseClient.Post(): %v", err)
+		return nil, status, fmt.Errorf("ServicePrincipalsClient.BaseClient.Post(): %w", err)
 	}
 
 	defer resp.Body.Close()
@@ -402,7 +488,7 @@ func (c *ServicePrincipalsClient) AddPassword(ctx context.Context, servicePrinci
 	}
 
 	var newPasswordCredential PasswordCredential
-	if err := json.Unmarshal(respBody, &newPasswordCredential); err != nil {
+	if err := c.BaseClient.decode(respBody, &newPasswordCredential); err != nil {
 		return nil, status, fmt.Errorf("json.Unmarshal(): %v", err)
 	}
 
@@ -432,7 +518,7 @@ func (c *ServicePrincipalsClient) RemovePassword(ctx context.Context, servicePri
 		},
 	})
 	if err != nil {
-		return status, fmt.Errorf("ServicePrincipalsClient.BaseClient.Post(): %v", err)
+		return status, fmt.Errorf("ServicePrincipalsClient.BaseClient.Post(): %w", err)
 	}
 
 	return status, nil
@@ -462,7 +548,7 @@ func (c *ServicePrincipalsClient) ListOwnedObjects(ctx context.Context, id strin
 			Id   string `json:"id"`
 		} `json:"value"`
 	}
-	if err := json.Unmarshal(respBody, &data); err != nil {
+	if err := c.BaseClient.decode(respBody, &data); err != nil {
 		return nil, status, err
 	}
 
@@ -478,6 +564,7 @@ func (c *ServicePrincipalsClient) ListOwnedObjects(ctx context.Context, id strin
 func (c *ServicePrincipalsClient) ListAppRoleAssignments(ctx context.Context, resourceId string, query odata.Query) (*[]AppRoleAssignment, int, error) {
 	resp, status, _, err := c.BaseClient.Get(ctx, GetHttpRequestInput{
 		ConsistencyFailureFunc: RetryOn404ConsistencyFailureFunc,
+		ConsistencyLevel:       query.ConsistencyLevel,
 		ValidStatusCodes:       []int{http.StatusOK},
 		Uri: Uri{
 			Entity:      fmt.Sprintf("/servicePrincipals/%s/appRoleAssignedTo", resourceId),
@@ -486,7 +573,7 @@ func (c *ServicePrincipalsClient) ListAppRoleAssignments(ctx context.Context, re
 		},
 	})
 	if err != nil {
-		return nil, status, fmt.Errorf("ServicePrincipalsClient.BaseClient.Get(): %v", err)
+		return nil, status, fmt.Errorf("ServicePrincipalsClient.BaseClient.Get(): %w", err)
 	}
 
 	defer resp.Body.Close()
@@ -498,7 +585,7 @@ func (c *ServicePrincipalsClient) ListAppRoleAssignments(ctx context.Context, re
 	var data struct {
 		AppRoleAssignments []AppRoleAssignment `json:"value"`
 	}
-	if err := json.Unmarshal(respBody, &data); err != nil {
+	if err := c.BaseClient.decode(respBody, &data); err != nil {
 		return nil, status, fmt.Errorf("json.Unmarshal(): %v", err)
 	}
 
@@ -516,7 +603,7 @@ func (c *ServicePrincipalsClient) RemoveAppRoleAssignment(ctx context.Context, r
 		},
 	})
 	if err != nil {
-		return status, fmt.Errorf("AppRoleAssignmentsClient.BaseClient.Delete(): %v", err)
+		return status, fmt.Errorf("AppRoleAssignmentsClient.BaseClient.Delete(): %w", err)
 	}
 
 	return status, nil
@@ -555,7 +642,7 @@ func (c *ServicePrincipalsClient) AssignAppRoleForResource(ctx context.Context,
 		},
 	})
 	if err != nil {
-		return nil, status, fmt.Errorf("ServicePrincipalsClient.BaseClient.Post(): %v", err)
+		return nil, status, fmt.Errorf("ServicePrincipalsClient.BaseClient.Post(): %w", err)
 	}
 
 	defer resp.Body.Close()
@@ -565,9 +652,135 @@ func (c *ServicePrincipalsClient) AssignAppRoleForResource(ctx context.Context,
 	}
 
 	var appRoleAssignment AppRoleAssignment
-	if err := json.Unmarshal(respBody, &appRoleAssignment); err != nil {
+	if err := c.BaseClient.decode(respBody, &appRoleAssignment); err != nil {
 		return nil, status, fmt.Errorf("json.Unmarshal(): %v", err)
 	}
 
 	return &appRoleAssignment, status, nil
 }
+
+// GrantAdminConsent grants tenant-wide admin consent for the client service principal to access the resource service
+// principal, equivalent to the "Grant admin consent" action in the Azure portal. Delegated permissions are granted for
+// all principals via an OAuth2PermissionGrant, and application permissions are granted via appRoleAssignments.
+//
+// spId: The id of the client servicePrincipal being consented.
+// resourceSpId: The id of the resource servicePrincipal which exposes the permissions.
+// delegatedScopes: The values of the delegated permission scopes to grant.
+// appRoles: The ids or values of the app roles (defined on the resource service principal) to assign.
+//
+// This method is idempotent. Scopes already granted are retained, and app roles which are already assigned are skipped.
+func (c *ServicePrincipalsClient) GrantAdminConsent(ctx context.Context, spId, resourceSpId string, delegatedScopes []string, appRoles []string) (int, error) {
+	var status int
+	var err error
+
+	if len(delegatedScopes) > 0 {
+		grantsClient := &OAuth2PermissionGrantsClient{BaseClient: c.BaseClient}
+		var grants *[]OAuth2PermissionGrant
+		grants, status, err = grantsClient.List(ctx, odata.Query{
+			Filter: fmt.Sprintf("clientId eq '%s' and consentType eq '%s' and resourceId eq '%s'", spId, OAuth2PermissionGrantConsentTypeAllPrincipals, resourceSpId),
+		})
+		if err != nil {
+			return status, fmt.Errorf("OAuth2PermissionGrantsClient.List(): %v", err)
+		}
+
+		if grants != nil && len(*grants) > 0 {
+			grant := (*grants)[0]
+			var existing []string
+			if grant.Scope != nil {
+				existing = strings.Fields(*grant.Scope)
+			}
+			scopes := mergeGrantScopes(existing, delegatedScopes)
+			if len(scopes) > len(existing) {
+				scope := strings.Join(scopes, " ")
+				grant.Scope = &scope
+				if status, err = grantsClient.Update(ctx, grant); err != nil {
+					return status, fmt.Errorf("OAuth2PermissionGrantsClient.Update(): %v", err)
+				}
+			}
+		} else {
+			consentType := OAuth2PermissionGrantConsentTypeAllPrincipals
+			scope := strings.Join(mergeGrantScopes(nil, delegatedScopes), " ")
+			if _, status, err = grantsClient.Create(ctx, OAuth2PermissionGrant{
+				ClientId:    &spId,
+				ConsentType: &consentType,
+				ResourceId:  &resourceSpId,
+				Scope:       &scope,
+			}); err != nil {
+				return status, fmt.Errorf("OAuth2PermissionGrantsClient.Create(): %v", err)
+			}
+		}
+	}
+
+	if len(appRoles) > 0 {
+		var resource *ServicePrincipal
+		resource, status, err = c.Get(ctx, resourceSpId, odata.Query{})
+		if err != nil {
+			return status, fmt.Errorf("ServicePrincipalsClient.Get(): %v", err)
+		}
+
+		appRoleIds := make([]string, 0, len(appRoles))
+		for _, r := range appRoles {
+			var appRoleId string
+			if resource.AppRoles != nil {
+				for _, appRole := range *resource.AppRoles {
+					if appRole.ID != nil && (*appRole.ID == r || (appRole.Value != nil && *appRole.Value == r)) {
+						appRoleId = *appRole.ID
+						break
+					}
+				}
+			}
+			if appRoleId == "" {
+				return status, fmt.Errorf("ServicePrincipalsClient.GrantAdminConsent(): app role %q not found for resource service principal %q", r, resourceSpId)
+			}
+			appRoleIds = append(appRoleIds, appRoleId)
+		}
+
+		var assignments *[]AppRoleAssignment
+		assignments, status, err = c.ListAppRoleAssignments(ctx, resourceSpId, odata.Query{
+			Filter: fmt.Sprintf("principalId eq %s", spId),
+		})
+		if err != nil {
+			return status, fmt.Errorf("ServicePrincipalsClient.ListAppRoleAssignments(): %v", err)
+		}
+
+		assigned := make(map[string]bool)
+		if assignments != nil {
+			for _, a := range *assignments {
+				if a.PrincipalId != nil && *a.PrincipalId == spId && a.AppRoleId != nil {
+					assigned[*a.AppRoleId] = true
+				}
+			}
+		}
+
+		for _, appRoleId := range appRoleIds {
+			if assigned[appRoleId] {
+				continue
+			}
+			if _, status, err = c.AssignAppRoleForResource(ctx, spId, resourceSpId, appRoleId); err != nil {
+				return status, fmt.Errorf("ServicePrincipalsClient.AssignAppRoleForResource(): %v", err)
+			}
+			assigned[appRoleId] = true
+		}
+	}
+
+	return status, nil
+}
+
+// mergeGrantScopes appends any scopes not already present to the existing scopes, preserving order.
+func mergeGrantScopes(existing, scopes []string) []string {
+	seen := make(map[string]bool, len(existing))
+	result := make([]string, 0, len(existing)+len(scopes))
+	for _, s := range existing {
+		if !seen[s] {
+			seen[s] = true
+			result = append(result, s)
+		}
+	}
+	for _, s := range scopes {
+		if s = strings.TrimSpace(s); s != "" && !seen[s] {
+			seen[s] = true
+			result = append(result, s)
+		}
+	}
+	return result
+}
@@ -43,6 +43,12 @@ func TestServicePrincipalsClient(t *testing.T) {
 		AppId:          app.AppId,
 		DisplayName:    app.DisplayName,
 	})
+	existingSp := testServicePrincipalsClient_CreateIfNotExists(t, c, msgraph.ServicePrincipal{
+		AppId: app.AppId,
+	})
+	if *existingSp.ID != *sp.ID {
+		t.Fatalf("ServicePrincipalsClient.CreateIfNotExists(): expected existing service principal %q, got %q", *sp.ID, *existingSp.ID)
+	}
 
 	appChild := testApplicationsClient_Create(t, a, msgraph.Application{
 		DisplayName: utils.StringPtr(fmt.Sprintf("test-serviceprincipal-child%s", a.randomString)),
@@ -189,10 +195,34 @@ func TestServicePrincipalsClient_AppRoleAssignments(t *testing.T) {
 	// removes app role assignment previously set to the test group
 	testServicePrincipalsClient_RemoveAppRoleAssignment(t, c, *sp.ID, *appRoleAssignment.Id)
 
+	// Admin consent
+	appClient := testApplicationsClient_Create(t, a, msgraph.Application{
+		DisplayName: utils.StringPtr(fmt.Sprintf("test-serviceprincipal-adminConsent-%s", a.randomString)),
+	})
+	spClient := testServicePrincipalsClient_Create(t, c, msgraph.ServicePrincipal{
+		AccountEnabled: utils.BoolPtr(true),
+		AppId:          appClient.AppId,
+		DisplayName:    appClient.DisplayName,
+	})
+	testServicePrincipalsClient_GrantAdminConsent(t, c, *spClient.ID, *sp.ID, []string{*(*app.AppRoles)[0].Value})
+	// granting again should not create a duplicate assignment
+	testServicePrincipalsClient_GrantAdminConsent(t, c, *spClient.ID, *sp.ID, []string{testResourceAppRoleId})
+	consented := 0
+	for _, assignment := range *testServicePrincipalsClient_ListAppRoleAssignments(t, c, *sp.ID) {
+		if assignment.PrincipalId != nil && *assignment.PrincipalId == *spClient.ID {
+			consented++
+		}
+	}
+	if consented != 1 {
+		t.Fatalf("ServicePrincipalsClient.GrantAdminConsent(): expected 1 app role assignment for client service principal, got %d", consented)
+	}
+
 	// remove all test resources
 	testGroupsClient_Delete(t, g, *groupParent.ID)
 	testGroupsClient_Delete(t, g, *groupChild.ID)
+	testServicePrincipalsClient_Delete(t, c, *spClient.ID)
 	testServicePrincipalsClient_Delete(t, c, *sp.ID)
+	testApplicationsClient_Delete(t, a, *appClient.ID)
 	testApplicationsClient_Delete(t, a, *app.ID)
 
 }
@@ -214,6 +244,23 @@ func testServicePrincipalsClient_Create(t *testing.T, c ServicePrincipalsClientT
 	return
 }
 
+func testServicePrincipalsClient_CreateIfNotExists(t *testing.T, c ServicePrincipalsClientTest, sp msgraph.ServicePrincipal) (servicePrincipal *msgraph.ServicePrincipal) {
+	servicePrincipal, status, err := c.client.CreateIfNotExists(c.connection.Context, sp)
+	if err != nil {
+		t.Fatalf("ServicePrincipalsClient.CreateIfNotExists(): %v", err)
+	}
+	if status < 200 || status >= 300 {
+		t.Fatalf("ServicePrincipalsClient.CreateIfNotExists(): invalid status: %d", status)
+	}
+	if servicePrincipal == nil {
+		t.Fatal("ServicePrincipalsClient.CreateIfNotExists(): servicePrincipal was nil")
+	}
+	if servicePrincipal.ID == nil {
+		t.Fatal("ServicePrincipalsClient.CreateIfNotExists(): servicePrincipal.ID was nil")
+	}
+	return
+}
+
 func testServicePrincipalsClient_Update(t *testing.T, c ServicePrincipalsClientTest, sp msgraph.ServicePrincipal) (servicePrincipal *msgraph.ServicePrincipal) {
 	status, err := c.client.Update(c.connection.Context, sp)
 	if err != nil {
@@ -421,3 +468,13 @@ func testServicePrincipalsClient_RemoveAppRoleAssignment(t *testing.T, c Service
 		t.Fatalf("ServicePrincipalsClient.RemoveAppRoleAssignment(): invalid status: %d", status)
 	}
 }
+
+func testServicePrincipalsClient_GrantAdminConsent(t *testing.T, c ServicePrincipalsClientTest, spId, resourceSpId string, appRoles []string) {
+	status, err := c.client.GrantAdminConsent(c.connection.Context, spId, resourceSpId, nil, appRoles)
+	if err != nil {
+		t.Fatalf("ServicePrincipalsClient.GrantAdminConsent(): %v", err)
+	}
+	if status < 200 || status >= 300 {
+		t.Fatalf("ServicePrincipalsClient.GrantAdminConsent(): invalid status: %d", status)
+	}
+}
@@ -2,7 +2,6 @@ package msgraph
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
@@ -25,6 +24,7 @@ func NewSignInLogsClient(tenantId string) *SignInReportsClient {
 // List returns a list of Sign-in Reports, optionally queried using OData.
 func (c *SignInReportsClient) List(ctx context.Context, query odata.Query) (*[]SignInReport, int, error) {
 	resp, status, _, err := c.BaseClient.Get(ctx, GetHttpRequestInput{
+		ConsistencyLevel: query.ConsistencyLevel,
 		DisablePaging:    query.Top > 0,
 		ValidStatusCodes: []int{http.StatusOK},
 		Uri: Uri{
@@ -34,7 +34,7 @@ func (c *SignInReportsClient) List(ctx context.Context, query odata.Query) (*[]S
 		},
 	})
 	if err != nil {
-		return nil, status, fmt.Errorf("SignInLogsClient.BaseClient.Get(): %v", err)
+		return nil, status, fmt.Errorf("SignInLogsClient.BaseClient.Get(): %w", err)
 	}
 
 	defer resp.Body.Close()
@@ -46,7 +46,7 @@ func (c *SignInReportsClient) List(ctx context.Context, query odata.Query) (*[]S
 	var data struct {
 		SignInLogs []SignInReport `json:"value"`
 	}
-	if err := json.Unmarshal(respBody, &data); err != nil {
+	if err := c.BaseClient.decode(respBody, &data); err != nil {
 		return nil, status, fmt.Errorf("json.Unmarshal(): %v", err)
 	}
 
@@ -57,6 +57,7 @@ func (c *SignInReportsClient) List(ctx context.Context, query odata.Query) (*[]S
 func (c *SignInReportsClient) Get(ctx context.Context, id string, query odata.Query) (*SignInReport, int, error) {
 	resp, status, _, err := c.BaseClient.Get(ctx, GetHttpRequestInput{
 		ConsistencyFailureFunc: RetryOn404ConsistencyFailureFunc,
+		ConsistencyLevel:       query.ConsistencyLevel,
 		ValidStatusCodes:       []int{http.StatusOK},
 		Uri: Uri{
 			Entity:      fmt.Sprintf("/auditLogs/signIns/%s", id),
@@ -65,7 +66,7 @@ func (c *SignInReportsClient) Get(ctx context.Context, id string, query odata.Qu
 		},
 	})
 	if err != nil {
-		return nil, status, fmt.Errorf("SignInLogsClient.BaseClient.Get(): %v", err)
+		return nil, status, fmt.Errorf("SignInLogsClient.BaseClient.Get(): %w", err)
 	}
 
 	defer resp.Body.Close()
@@ -75,7 +76,7 @@ func (c *SignInReportsClient) Get(ctx context.Context, id string, query odata.Qu
 	}
 
 	var signInReport SignInReport
-	if err := json.Unmarshal(respBody, &signInReport); err != nil {
+	if err := c.BaseClient.decode(respBody, &signInReport); err != nil {
 		return nil, status, fmt.Errorf("json.Unmarshal(): %v", err)
 	}
 
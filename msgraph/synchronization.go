@@ -0,0 +1,232 @@
+package msgraph
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/manicminer/hamilton/odata"
+)
+
+// SynchronizationClient performs operations on the SynchronizationJobs of service principals, which are used for
+// provisioning users and groups to applications, e.g. via SCIM.
+type SynchronizationClient struct {
+	BaseClient Client
+}
+
+// NewSynchronizationClient returns a new SynchronizationClient.
+func NewSynchronizationClient(tenantId string) *SynchronizationClient {
+	return &SynchronizationClient{
+		BaseClient: NewClient(Version10, tenantId),
+	}
+}
+
+// ListJobs returns the SynchronizationJobs for a service principal, optionally queried using OData.
+// servicePrincipalId is the object ID of the service principal.
+func (c *SynchronizationClient) ListJobs(ctx context.Context, servicePrincipalId string, query odata.Query) (*[]SynchronizationJob, int, error) {
+	resp, status, _, err := c.BaseClient.Get(ctx, GetHttpRequestInput{
+		ConsistencyFailureFunc: RetryOn404ConsistencyFailureFunc,
+		ConsistencyLevel:       query.ConsistencyLevel,
+		DisablePaging:          query.Top > 0,
+		ValidStatusCodes:       []int{http.StatusOK},
+		Uri: Uri{
+			Entity:      fmt.Sprintf("/servicePrincipals/%s/synchronization/jobs", servicePrincipalId),
+			Params:      query.Values(),
+			HasTenantId: true,
+		},
+	})
+	if err != nil {
+		return nil, status, fmt.Errorf("SynchronizationClient.BaseClient.Get(): %w", err)
+	}
+
+	defer resp.Body.Close()
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, status, fmt.Errorf("io.ReadAll(): %v", err)
+	}
+
+	var data struct {
+		Jobs []SynchronizationJob `json:"value"`
+	}
+	if err := c.BaseClient.decode(respBody, &data); err != nil {
+		return nil, status, fmt.Errorf("json.Unmarshal(): %v", err)
+	}
+
+	return &data.Jobs, status, nil
+}
+
+// CreateJob creates a new SynchronizationJob for a service principal. The TemplateId of the job must be set to the ID
+// of one of the synchronization templates for the application.
+// servicePrincipalId is the object ID of the service principal.
+func (c *SynchronizationClient) CreateJob(ctx context.Context, servicePrincipalId string, job SynchronizationJob) (*SynchronizationJob, int, error) {
+	var status int
+
+	body, err := json.Marshal(job)
+	if err != nil {
+		return nil, status, fmt.Errorf("json.Marshal(): %v", err)
+	}
+
+	resp, status, _, err := c.BaseClient.Post(ctx, PostHttpRequestInput{
+		Body:                   body,
+		ConsistencyFailureFunc: RetryOn404ConsistencyFailureFunc,
+		ValidStatusCodes:       []int{http.StatusCreated},
+		Uri: Uri{
+			Entity:      fmt.Sprintf("/servicePrincipals/%s/synchronization/jobs", servicePrincipalId),
+			HasTenantId: true,
+		},
+	})
+	if err != nil {
+		return nil, status, fmt.Errorf("SynchronizationClient.BaseClient.Post(): %w", err)
+	}
+
+	defer resp.Body.Close()
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, status, fmt.Errorf("io.ReadAll(): %v", err)
+	}
+
+	var newJob SynchronizationJob
+	if err := c.BaseClient.decode(respBody, &newJob); err != nil {
+		return nil, status, fmt.Errorf("json.Unmarshal(): %v", err)
+	}
+
+	return &newJob, status, nil
+}
+
+// GetJob retrieves a SynchronizationJob, including its current Status.
+// servicePrincipalId is the object ID of the service principal.
+// jobId is the ID of the synchronization job.
+func (c *SynchronizationClient) GetJob(ctx context.Context, servicePrincipalId, jobId string, query odata.Query) (*SynchronizationJob, int, error) {
+	resp, status, _, err := c.BaseClient.Get(ctx, GetHttpRequestInput{
+		ConsistencyFailureFunc: RetryOn404ConsistencyFailureFunc,
+		ConsistencyLevel:       query.ConsistencyLevel,
+		ValidStatusCodes:       []int{http.StatusOK},
+		Uri: Uri{
+			Entity:      fmt.Sprintf("/servicePrincipals/%s/synchronization/jobs/%s", servicePrincipalId, jobId),
+			Params:      query.Values(),
+			HasTenantId: true,
+		},
+	})
+	if err != nil {
+		return nil, status, fmt.Errorf("SynchronizationClient.BaseClient.Get(): %w", err)
+	}
+
+	defer resp.Body.Close()
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, status, fmt.Errorf("io.ReadAll(): %v", err)
+	}
+
+	var job SynchronizationJob
+	if err := c.BaseClient.decode(respBody, &job); err != nil {
+		return nil, status, fmt.Errorf("json.Unmarshal(): %v", err)
+	}
+
+	return &job, status, nil
+}
+
+// DeleteJob removes a SynchronizationJob, stopping any further synchronization.
+// servicePrincipalId is the object ID of the service principal.
+// jobId is the ID of the synchronization job.
+func (c *SynchronizationClient) DeleteJob(ctx context.Context, servicePrincipalId, jobId string) (int, error) {
+	_, status, _, err := c.BaseClient.Delete(ctx, DeleteHttpRequestInput{
+		ConsistencyFailureFunc: RetryOn404ConsistencyFailureFunc,
+		ValidStatusCodes:       []int{http.StatusNoContent},
+		Uri: Uri{
+			Entity:      fmt.Sprintf("/servicePrincipals/%s/synchronization/jobs/%s", servicePrincipalId, jobId),
+			HasTenantId: true,
+		},
+	})
+	if err != nil {
+		return status, fmt.Errorf("SynchronizationClient.BaseClient.Delete(): %w", err)
+	}
+
+	return status, nil
+}
+
+// StartJob starts a SynchronizationJob, or resumes a paused job from where it left off. A job which is already
+// running is not affected.
+// servicePrincipalId is the object ID of the service principal.
+// jobId is the ID of the synchronization job.
+func (c *SynchronizationClient) StartJob(ctx context.Context, servicePrincipalId, jobId string) (int, error) {
+	return c.jobAction(ctx, servicePrincipalId, jobId, "start", nil)
+}
+
+// PauseJob temporarily stops a SynchronizationJob, retaining its progress so that it can be resumed with StartJob.
+// servicePrincipalId is the object ID of the service principal.
+// jobId is the ID of the synchronization job.
+func (c *SynchronizationClient) PauseJob(ctx context.Context, servicePrincipalId, jobId string) (int, error) {
+	return c.jobAction(ctx, servicePrincipalId, jobId, "pause", nil)
+}
+
+// RestartJob restarts a SynchronizationJob, e.g. to release it from quarantine, optionally resetting state according
+// to resetScope. When resetScope is empty, the job is restarted without clearing any state.
+// servicePrincipalId is the object ID of the service principal.
+// jobId is the ID of the synchronization job.
+func (c *SynchronizationClient) RestartJob(ctx context.Context, servicePrincipalId, jobId string, resetScope SynchronizationJobRestartScope) (int, error) {
+	var body []byte
+	if resetScope != "" {
+		var err error
+		body, err = json.Marshal(map[string]interface{}{
+			"criteria": map[string]interface{}{
+				"resetScope": resetScope,
+			},
+		})
+		if err != nil {
+			return 0, fmt.Errorf("json.Marshal(): %v", err)
+		}
+	}
+	return c.jobAction(ctx, servicePrincipalId, jobId, "restart", body)
+}
+
+// jobAction invokes an action on a synchronization job, such as start or pause
+func (c *SynchronizationClient) jobAction(ctx context.Context, servicePrincipalId, jobId, action string, body []byte) (int, error) {
+	_, status, _, err := c.BaseClient.Post(ctx, PostHttpRequestInput{
+		Body:                   body,
+		ConsistencyFailureFunc: RetryOn404ConsistencyFailureFunc,
+		ValidStatusCodes:       []int{http.StatusNoContent},
+		Uri: Uri{
+			Entity:      fmt.Sprintf("/servicePrincipals/%s/synchronization/jobs/%s/%s", servicePrincipalId, jobId, action),
+			HasTenantId: true,
+		},
+	})
+	if err != nil {
+		return status, fmt.Errorf("SynchronizationClient.BaseClient.Post(): %w", err)
+	}
+
+	return status, nil
+}
+
+// GetSchema retrieves the SynchronizationSchema of a SynchronizationJob.
+// servicePrincipalId is the object ID of the service principal.
+// jobId is the ID of the synchronization job.
+func (c *SynchronizationClient) GetSchema(ctx context.Context, servicePrincipalId, jobId string, query odata.Query) (*SynchronizationSchema, int, error) {
+	resp, status, _, err := c.BaseClient.Get(ctx, GetHttpRequestInput{
+		ConsistencyFailureFunc: RetryOn404ConsistencyFailureFunc,
+		ConsistencyLevel:       query.ConsistencyLevel,
+		ValidStatusCodes:       []int{http.StatusOK},
+		Uri: Uri{
+			Entity:      fmt.Sprintf("/servicePrincipals/%s/synchronization/jobs/%s/schema", servicePrincipalId, jobId),
+			Params:      query.Values(),
+			HasTenantId: true,
+		},
+	})
+	if err != nil {
+		return nil, status, fmt.Errorf("SynchronizationClient.BaseClient.Get(): %w", err)
+	}
+
+	defer resp.Body.Close()
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, status, fmt.Errorf("io.ReadAll(): %v", err)
+	}
+
+	var schema SynchronizationSchema
+	if err := c.BaseClient.decode(respBody, &schema); err != nil {
+		return nil, status, fmt.Errorf("json.Unmarshal(): %v", err)
+	}
+
+	return &schema, status, nil
+}
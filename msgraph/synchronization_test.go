@@ -0,0 +1,86 @@
+package msgraph_test
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/manicminer/hamilton/environments"
+	"github.com/manicminer/hamilton/msgraph"
+	"github.com/manicminer/hamilton/odata"
+)
+
+func TestSynchronizationClient_Jobs(t *testing.T) {
+	const jobsPath = "/v1.0/00000000-0000-0000-0000-000000000000/servicePrincipals/11111111-1111-1111-1111-111111111111/synchronization/jobs"
+	actions := make(map[string][]byte)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == jobsPath:
+			w.Header().Set("Content-Type", "application/json")
+			w.Write([]byte(`{"value":[{"id":"scim.abc","templateId":"scim","status":{"code":"Quarantine","quarantine":{"reason":"EncounteredQuarantineException"}}}]}`))
+		case r.Method == http.MethodGet && r.URL.Path == jobsPath+"/scim.abc/schema":
+			w.Header().Set("Content-Type", "application/json")
+			w.Write([]byte(`{"id":"scim.abc","version":"1","directories":[{"name":"Azure Active Directory","objects":[{"name":"User","attributes":[{"name":"userPrincipalName","type":"String"}]}]}]}`))
+		case r.Method == http.MethodPost:
+			body, _ := io.ReadAll(r.Body)
+			actions[r.URL.Path] = body
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	c := msgraph.NewSynchronizationClient("00000000-0000-0000-0000-000000000000")
+	c.BaseClient.Endpoint = environments.ApiEndpoint(server.URL)
+	ctx := context.Background()
+
+	jobs, _, err := c.ListJobs(ctx, "11111111-1111-1111-1111-111111111111", odata.Query{})
+	if err != nil {
+		t.Fatalf("SynchronizationClient.ListJobs(): %v", err)
+	}
+	if jobs == nil || len(*jobs) != 1 {
+		t.Fatalf("SynchronizationClient.ListJobs(): expected 1 job, got %v", jobs)
+	}
+	job := (*jobs)[0]
+	if job.Status == nil || job.Status.Code == nil || *job.Status.Code != msgraph.SynchronizationStatusCodeQuarantine {
+		t.Fatalf("SynchronizationClient.ListJobs(): expected quarantined job, got %+v", job.Status)
+	}
+
+	if _, err := c.StartJob(ctx, "11111111-1111-1111-1111-111111111111", *job.ID); err != nil {
+		t.Fatalf("SynchronizationClient.StartJob(): %v", err)
+	}
+	if _, err := c.PauseJob(ctx, "11111111-1111-1111-1111-111111111111", *job.ID); err != nil {
+		t.Fatalf("SynchronizationClient.PauseJob(): %v", err)
+	}
+	if _, err := c.RestartJob(ctx, "11111111-1111-1111-1111-111111111111", *job.ID, msgraph.SynchronizationJobRestartScopeQuarantineState); err != nil {
+		t.Fatalf("SynchronizationClient.RestartJob(): %v", err)
+	}
+	for _, action := range []string{"start", "pause", "restart"} {
+		if _, ok := actions[jobsPath+"/scim.abc/"+action]; !ok {
+			t.Fatalf("SynchronizationClient: %s action was not invoked", action)
+		}
+	}
+	var restart struct {
+		Criteria struct {
+			ResetScope string `json:"resetScope"`
+		} `json:"criteria"`
+	}
+	if err := json.Unmarshal(actions[jobsPath+"/scim.abc/restart"], &restart); err != nil {
+		t.Fatalf("SynchronizationClient.RestartJob(): invalid request body: %v", err)
+	}
+	if restart.Criteria.ResetScope != msgraph.SynchronizationJobRestartScopeQuarantineState {
+		t.Fatalf("SynchronizationClient.RestartJob(): expected resetScope %q, got %q", msgraph.SynchronizationJobRestartScopeQuarantineState, restart.Criteria.ResetScope)
+	}
+
+	schema, _, err := c.GetSchema(ctx, "11111111-1111-1111-1111-111111111111", *job.ID, odata.Query{})
+	if err != nil {
+		t.Fatalf("SynchronizationClient.GetSchema(): %v", err)
+	}
+	if schema.Directories == nil || len(*schema.Directories) != 1 {
+		t.Fatalf("SynchronizationClient.GetSchema(): expected 1 directory, got %v", schema.Directories)
+	}
+}
@@ -0,0 +1,212 @@
+package msgraph
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// uploadSessionDefaultChunkSize is the default size of each byte range uploaded, which must be a multiple of 320 KiB
+const uploadSessionDefaultChunkSize = 10 * 320 * 1024
+
+// UploadProgressFunc is called after each byte range is uploaded, with the number of bytes uploaded so far and the total size.
+type UploadProgressFunc func(uploaded, total int64)
+
+// UploadSessionsClient performs resumable uploads of large content, such as files larger than 4 MB in a drive.
+type UploadSessionsClient struct {
+	BaseClient Client
+
+	// ChunkSize is the size in bytes of each byte range uploaded. It should be a multiple of 320 KiB, and not
+	// exceed 60 MiB. When not set, a default of 3.2 MiB is used.
+	ChunkSize int64
+}
+
+// NewUploadSessionsClient returns a new UploadSessionsClient.
+func NewUploadSessionsClient(tenantId string) *UploadSessionsClient {
+	return &UploadSessionsClient{
+		BaseClient: NewClient(Version10, tenantId),
+	}
+}
+
+// Create creates an UploadSession for the specified item.
+// entity is the path of the item to be uploaded, e.g. `/drives/{drive-id}/items/{parent-id}:/{filename}:`
+func (c *UploadSessionsClient) Create(ctx context.Context, entity string) (*UploadSession, int, error) {
+	resp, status, _, err := c.BaseClient.Post(ctx, PostHttpRequestInput{
+		Body:             []byte("{}"),
+		ValidStatusCodes: []int{http.StatusOK},
+		Uri: Uri{
+			Entity:      fmt.Sprintf("%s/createUploadSession", strings.TrimSuffix(entity, "/")),
+			HasTenantId: true,
+		},
+	})
+	if err != nil {
+		return nil, status, fmt.Errorf("UploadSessionsClient.BaseClient.Post(): %w", err)
+	}
+
+	defer resp.Body.Close()
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, status, fmt.Errorf("io.ReadAll(): %v", err)
+	}
+
+	var uploadSession UploadSession
+	if err := c.BaseClient.decode(respBody, &uploadSession); err != nil {
+		return nil, status, fmt.Errorf("json.Unmarshal(): %v", err)
+	}
+
+	return &uploadSession, status, nil
+}
+
+// Get retrieves the current state of an UploadSession, including the byte ranges which have yet to be received.
+// This can be used to resume an interrupted upload by passing the returned UploadSession to Upload().
+func (c *UploadSessionsClient) Get(ctx context.Context, uploadSession UploadSession) (*UploadSession, int, error) {
+	var status int
+
+	if uploadSession.UploadUrl == nil {
+		return nil, status, errors.New("UploadSessionsClient.Get(): cannot get UploadSession with nil UploadUrl")
+	}
+
+	resp, status, err := c.send(ctx, http.MethodGet, *uploadSession.UploadUrl, nil, nil)
+	if err != nil {
+		return nil, status, err
+	}
+	if status != http.StatusOK {
+		return nil, status, fmt.Errorf("UploadSessionsClient.Get(): unexpected status %d with response: %s", status, resp)
+	}
+
+	var newUploadSession UploadSession
+	if err := c.BaseClient.decode(resp, &newUploadSession); err != nil {
+		return nil, status, fmt.Errorf("json.Unmarshal(): %v", err)
+	}
+	newUploadSession.UploadUrl = uploadSession.UploadUrl
+
+	return &newUploadSession, status, nil
+}
+
+// Upload uploads content of the specified size using an UploadSession, starting from the first of its
+// NextExpectedRanges, and returns the raw response describing the uploaded item once complete.
+// progress is optional, and is called after each byte range is uploaded.
+func (c *UploadSessionsClient) Upload(ctx context.Context, uploadSession UploadSession, content io.ReaderAt, size int64, progress UploadProgressFunc) (json.RawMessage, int, error) {
+	var status int
+
+	if uploadSession.UploadUrl == nil {
+		return nil, status, errors.New("UploadSessionsClient.Upload(): cannot upload using UploadSession with nil UploadUrl")
+	}
+	if size <= 0 {
+		return nil, status, fmt.Errorf("UploadSessionsClient.Upload(): size must be greater than zero, got %d", size)
+	}
+
+	chunkSize := c.ChunkSize
+	if chunkSize <= 0 {
+		chunkSize = uploadSessionDefaultChunkSize
+	}
+
+	offset, err := nextExpectedOffset(uploadSession.NextExpectedRanges)
+	if err != nil {
+		return nil, status, fmt.Errorf("UploadSessionsClient.Upload(): %v", err)
+	}
+
+	for {
+		if offset >= size {
+			return nil, status, fmt.Errorf("UploadSessionsClient.Upload(): the next expected offset %d is not within the content of size %d", offset, size)
+		}
+
+		end := offset + chunkSize
+		if end > size {
+			end = size
+		}
+
+		chunk := make([]byte, end-offset)
+		if _, err := content.ReadAt(chunk, offset); err != nil && err != io.EOF {
+			return nil, status, fmt.Errorf("reading content: %v", err)
+		}
+
+		headers := http.Header{
+			"Content-Range": []string{fmt.Sprintf("bytes %d-%d/%d", offset, end-1, size)},
+		}
+		resp, s, err := c.send(ctx, http.MethodPut, *uploadSession.UploadUrl, chunk, headers)
+		status = s
+		if err != nil {
+			return nil, status, err
+		}
+
+		switch status {
+		case http.StatusOK, http.StatusCreated:
+			if progress != nil {
+				progress(size, size)
+			}
+			return resp, status, nil
+		case http.StatusAccepted:
+			var next UploadSession
+			if err := json.Unmarshal(resp, &next); err != nil {
+				return nil, status, fmt.Errorf("json.Unmarshal(): %v", err)
+			}
+			if offset, err = nextExpectedOffset(next.NextExpectedRanges); err != nil {
+				return nil, status, fmt.Errorf("UploadSessionsClient.Upload(): %v", err)
+			}
+			if progress != nil {
+				progress(offset, size)
+			}
+		default:
+			return nil, status, fmt.Errorf("UploadSessionsClient.Upload(): unexpected status %d with response: %s", status, resp)
+		}
+	}
+}
+
+// send makes a request to an upload URL. Upload URLs are pre-authenticated, so no Authorization header is sent. When
+// DryRun is enabled for the BaseClient, requests other than GET are logged instead of being sent.
+func (c *UploadSessionsClient) send(ctx context.Context, method, url string, body []byte, headers http.Header) ([]byte, int, error) {
+	var status int
+
+	req, err := http.NewRequestWithContext(ctx, method, url, bytes.NewReader(body))
+	if err != nil {
+		return nil, status, fmt.Errorf("http.NewRequestWithContext(): %v", err)
+	}
+	for k, v := range headers {
+		req.Header[k] = v
+	}
+	if c.BaseClient.UserAgent != "" {
+		req.Header.Set("User-Agent", c.BaseClient.UserAgent)
+	}
+	req.ContentLength = int64(len(body))
+
+	if c.BaseClient.DryRun && method != http.MethodGet && method != http.MethodHead {
+		// Uploaded content is not logged, since it is likely to be large and may be binary
+		req.Body = nil
+		_, status, err = c.BaseClient.dryRun(req)
+		return nil, status, fmt.Errorf("UploadSessionsClient.BaseClient.dryRun(): %w", err)
+	}
+
+	resp, err := c.BaseClient.HttpClient.Do(req)
+	if err != nil {
+		return nil, status, fmt.Errorf("UploadSessionsClient.BaseClient.HttpClient.Do(): %v", err)
+	}
+	status = resp.StatusCode
+
+	defer resp.Body.Close()
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, status, fmt.Errorf("io.ReadAll(): %v", err)
+	}
+
+	return respBody, status, nil
+}
+
+// nextExpectedOffset returns the start of the first expected byte range, e.g. "26-" or "26-100"
+func nextExpectedOffset(ranges *[]string) (int64, error) {
+	if ranges == nil || len(*ranges) == 0 {
+		return 0, nil
+	}
+	start := strings.SplitN((*ranges)[0], "-", 2)[0]
+	offset, err := strconv.ParseInt(start, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid expected range %q: %v", (*ranges)[0], err)
+	}
+	return offset, nil
+}
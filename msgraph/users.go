@@ -6,10 +6,24 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"sync"
 
+	"github.com/manicminer/hamilton/errors"
 	"github.com/manicminer/hamilton/odata"
 )
 
+const (
+	// idFilterMaxValues is the maximum number of values supported by Microsoft Graph in an `in` filter expression
+	idFilterMaxValues = 15
+
+	// idFilterMaxLength is the maximum URL encoded length of an `in` filter expression, allowing headroom within the
+	// URL length limit for the remainder of the request URL
+	idFilterMaxLength = 1500
+
+	// usersMaxPageSize is the maximum page size supported when listing users
+	usersMaxPageSize = 999
+)
+
 // UsersClient performs operations on Users.
 type UsersClient struct {
 	BaseClient Client
@@ -23,8 +37,19 @@ func NewUsersClient(tenantId string) *UsersClient {
 }
 
 // List returns a list of Users, optionally queried using OData.
+//
+// Advanced queries, such as those using $search or filtering with the `ne` or `not` operators, the `endsWith`
+// function, a navigation property count or a directory extension property, are detected and sent with eventual
+// consistency and $count=true, which Microsoft Graph requires for these queries. Other queries are sent with the
+// ConsistencyLevel specified in the query. On large tenants, setting odata.ConsistencyLevelEventual may also be needed to obtain complete results for
+// other filters, such as `userType eq 'Guest'`.
 func (c *UsersClient) List(ctx context.Context, query odata.Query) (*[]User, int, error) {
+	if query.ConsistencyLevel == "" && query.AdvancedQuery() {
+		query.ConsistencyLevel = odata.ConsistencyLevelEventual
+	}
+
 	resp, status, _, err := c.BaseClient.Get(ctx, GetHttpRequestInput{
+		ConsistencyLevel: query.ConsistencyLevel,
 		DisablePaging:    query.Top > 0,
 		ValidStatusCodes: []int{http.StatusOK},
 		Uri: Uri{
@@ -34,7 +59,7 @@ func (c *UsersClient) List(ctx context.Context, query odata.Query) (*[]User, int
 		},
 	})
 	if err != nil {
-		return nil, status, fmt.Errorf("UsersClient.BaseClient.Get(): %v", err)
+		return nil, status, fmt.Errorf("UsersClient.BaseClient.Get(): %w", err)
 	}
 
 	defer resp.Body.Close()
@@ -46,17 +71,195 @@ func (c *UsersClient) List(ctx context.Context, query odata.Query) (*[]User, int
 	var data struct {
 		Users []User `json:"value"`
 	}
-	if err := json.Unmarshal(respBody, &data); err != nil {
+	if err := c.BaseClient.decode(respBody, &data); err != nil {
 		return nil, status, fmt.Errorf("json.Unmarshal(): %v", err)
 	}
 
 	return &data.Users, status, nil
 }
 
-// Create creates a new User.
+// ListByIds retrieves the Users with the specified object IDs, optionally queried using OData. The IDs are split into
+// as many `id in (...)` filters as needed to respect the limits imposed by Microsoft Graph on the number of values in
+// a filter and on the URL length, which is far more efficient than retrieving each user individually. When a Filter
+// is specified in the query, it is combined with each of these filters. Up to concurrency requests are made at once,
+// or one at a time when concurrency is less than 2. Duplicate IDs are ignored, and IDs which do not exist are omitted
+// from the result.
+func (c *UsersClient) ListByIds(ctx context.Context, ids []string, query odata.Query, concurrency int) (*[]User, int, error) {
+	filters := odata.InChunks("id", ids, idFilterMaxValues, idFilterMaxLength)
+	if concurrency < 1 {
+		concurrency = 1
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	results := make([]*[]User, len(filters))
+	var mutex sync.Mutex
+	var status int
+	var firstErr error
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, concurrency)
+	for i, f := range filters {
+		sem <- struct{}{}
+		if ctx.Err() != nil {
+			<-sem
+			break
+		}
+
+		wg.Add(1)
+		go func(i int, f odata.Filter) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			q := query
+			if q.Filter != "" {
+				f = odata.And(f, odata.RawFilter(q.Filter))
+			}
+			q.Filter = f.String()
+
+			users, s, err := c.List(ctx, q)
+
+			mutex.Lock()
+			defer mutex.Unlock()
+			status = s
+			if err != nil {
+				if firstErr == nil {
+					firstErr = err
+					cancel()
+				}
+				return
+			}
+			results[i] = users
+		}(i, f)
+	}
+	wg.Wait()
+
+	if firstErr != nil {
+		return nil, status, firstErr
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, status, err
+	}
+
+	seen := make(map[string]bool, len(ids))
+	ret := make([]User, 0, len(ids))
+	for _, users := range results {
+		if users == nil {
+			continue
+		}
+		for _, u := range *users {
+			if u.ID != nil {
+				if seen[*u.ID] {
+					continue
+				}
+				seen[*u.ID] = true
+			}
+			ret = append(ret, u)
+		}
+	}
+
+	return &ret, status, nil
+}
+
+// ListN returns at most n Users, optionally queried using OData. Unlike List, further pages are only retrieved until n
+// users have been collected, so that only a sample of a large directory is retrieved. When query.Top is set, it
+// specifies the page size, otherwise a page size of n is requested where possible. Cancelling ctx stops retrieving
+// further pages, in which case an error is returned.
+func (c *UsersClient) ListN(ctx context.Context, query odata.Query, n int) (*[]User, int, error) {
+	if n <= 0 {
+		return nil, 0, fmt.Errorf("UsersClient.ListN(): n must be greater than zero")
+	}
+	if query.ConsistencyLevel == "" && query.AdvancedQuery() {
+		query.ConsistencyLevel = odata.ConsistencyLevelEventual
+	}
+	if query.Top == 0 && n <= usersMaxPageSize {
+		query.Top = n
+	}
+
+	users := make([]User, 0)
+	status, err := c.BaseClient.getEach(ctx, GetHttpRequestInput{
+		ConsistencyLevel: query.ConsistencyLevel,
+		ValidStatusCodes: []int{http.StatusOK},
+		Uri: Uri{
+			Entity:      "/users",
+			Params:      query.Values(),
+			HasTenantId: true,
+		},
+	}, func(item json.RawMessage) error {
+		var user User
+		if err := c.BaseClient.decode(item, &user); err != nil {
+			return fmt.Errorf("json.Unmarshal(): %v", err)
+		}
+		users = append(users, user)
+		if len(users) >= n {
+			return ErrStopIteration
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, status, fmt.Errorf("UsersClient.BaseClient.Get(): %w", err)
+	}
+
+	return &users, status, nil
+}
+
+// ListEach invokes fn for each User, optionally queried using OData, as each page of results is retrieved. This avoids
+// holding the entire collection in memory, e.g. when processing a large directory. Iteration stops when fn returns an
+// error, which is returned, or ErrStopIteration, in which case no error is returned. Cancelling ctx also stops
+// iteration.
+func (c *UsersClient) ListEach(ctx context.Context, query odata.Query, fn func(User) error) (int, error) {
+	if query.ConsistencyLevel == "" && query.AdvancedQuery() {
+		query.ConsistencyLevel = odata.ConsistencyLevelEventual
+	}
+
+	var callbackErr error
+	status, err := c.BaseClient.getEach(ctx, GetHttpRequestInput{
+		ConsistencyLevel: query.ConsistencyLevel,
+		DisablePaging:    query.Top > 0,
+		ValidStatusCodes: []int{http.StatusOK},
+		Uri: Uri{
+			Entity:      "/users",
+			Params:      query.Values(),
+			HasTenantId: true,
+		},
+	}, func(item json.RawMessage) error {
+		var user User
+		if err := c.BaseClient.decode(item, &user); err != nil {
+			return fmt.Errorf("json.Unmarshal(): %v", err)
+		}
+		if err := fn(user); err != nil {
+			callbackErr = err
+			return err
+		}
+		return nil
+	})
+	if err != nil {
+		if callbackErr != nil {
+			return status, callbackErr
+		}
+		return status, fmt.Errorf("UsersClient.BaseClient.Get(): %w", err)
+	}
+
+	return status, nil
+}
+
+// Create creates a new User. An enabled account must be created with an initial password in its PasswordProfile,
+// which can be marked as temporary using the ForceChangePasswordNextSignIn fields. The UsageLocation, which is
+// required before licenses can be assigned, can be set at the same time and must be an ISO 3166-1 alpha-2 country
+// code, e.g. `US`.
 func (c *UsersClient) Create(ctx context.Context, user User) (*User, int, error) {
 	var status int
 
+	if user.AccountEnabled != nil && *user.AccountEnabled {
+		if user.PasswordProfile == nil || user.PasswordProfile.Password == nil || *user.PasswordProfile.Password == "" {
+			return nil, status, fmt.Errorf("UsersClient.Create(): a password must be specified in PasswordProfile when creating an enabled account")
+		}
+	}
+
+	if user.UsageLocation != nil && *user.UsageLocation != "" && !validUsageLocation(string(*user.UsageLocation)) {
+		return nil, status, fmt.Errorf("UsersClient.Create(): invalid UsageLocation %q, must be a two-letter ISO 3166-1 alpha-2 country code such as \"US\"", *user.UsageLocation)
+	}
+
 	body, err := json.Marshal(user)
 	if err != nil {
 		return nil, status, fmt.Errorf("json.Marshal(): %v", err)
@@ -71,7 +274,7 @@ func (c *UsersClient) Create(ctx context.Context, user User) (*User, int, error)
 		},
 	})
 	if err != nil {
-		return nil, status, fmt.Errorf("UsersClient.BaseClient.Post(): %v", err)
+		return nil, status, fmt.Errorf("UsersClient.BaseClient.Post(): %w", err)
 	}
 
 	defer resp.Body.Close()
@@ -81,7 +284,7 @@ func (c *UsersClient) Create(ctx context.Context, user User) (*User, int, error)
 	}
 
 	var newUser User
-	if err := json.Unmarshal(respBody, &newUser); err != nil {
+	if err := c.BaseClient.decode(respBody, &newUser); err != nil {
 		return nil, status, fmt.Errorf("json.Unmarshal(): %v", err)
 	}
 
@@ -92,6 +295,7 @@ func (c *UsersClient) Create(ctx context.Context, user User) (*User, int, error)
 func (c *UsersClient) Get(ctx context.Context, id string, query odata.Query) (*User, int, error) {
 	resp, status, _, err := c.BaseClient.Get(ctx, GetHttpRequestInput{
 		ConsistencyFailureFunc: RetryOn404ConsistencyFailureFunc,
+		ConsistencyLevel:       query.ConsistencyLevel,
 		ValidStatusCodes:       []int{http.StatusOK},
 		Uri: Uri{
 			Entity:      fmt.Sprintf("/users/%s", id),
@@ -100,7 +304,7 @@ func (c *UsersClient) Get(ctx context.Context, id string, query odata.Query) (*U
 		},
 	})
 	if err != nil {
-		return nil, status, fmt.Errorf("UsersClient.BaseClient.Get(): %v", err)
+		return nil, status, fmt.Errorf("UsersClient.BaseClient.Get(): %w", err)
 	}
 
 	defer resp.Body.Close()
@@ -110,13 +314,77 @@ func (c *UsersClient) Get(ctx context.Context, id string, query odata.Query) (*U
 	}
 
 	var user User
-	if err := json.Unmarshal(respBody, &user); err != nil {
+	if err := c.BaseClient.decode(respBody, &user); err != nil {
 		return nil, status, fmt.Errorf("json.Unmarshal(): %v", err)
 	}
 
 	return &user, status, nil
 }
 
+// GetMe retrieves the signed-in User. This requires a delegated token, such as one obtained using the device code or
+// authorization code flows, and returns a DelegatedAuthRequiredError when the client is authorized with an app-only token.
+func (c *UsersClient) GetMe(ctx context.Context, query odata.Query) (*User, int, error) {
+	if err := c.BaseClient.requireDelegatedToken(ctx, "/me"); err != nil {
+		return nil, 0, err
+	}
+
+	resp, status, o, err := c.BaseClient.Get(ctx, GetHttpRequestInput{
+		ConsistencyLevel: query.ConsistencyLevel,
+		ValidStatusCodes: []int{http.StatusOK},
+		Uri: Uri{
+			Entity:      "/me",
+			Params:      query.Values(),
+			HasTenantId: false,
+		},
+	})
+	if delegatedAuthRequired(status, o) {
+		return nil, status, errors.DelegatedAuthRequiredError{Entity: "/me"}
+	}
+	if err != nil {
+		return nil, status, fmt.Errorf("UsersClient.BaseClient.Get(): %w", err)
+	}
+
+	defer resp.Body.Close()
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, status, fmt.Errorf("io.ReadAll(): %v", err)
+	}
+
+	var user User
+	if err := c.BaseClient.decode(respBody, &user); err != nil {
+		return nil, status, fmt.Errorf("json.Unmarshal(): %v", err)
+	}
+
+	return &user, status, nil
+}
+
+// ListMyMemberOf returns the groups, directory roles and administrative units that the signed-in User is a direct
+// member of. Each Membership can be type asserted back to the appropriate model. This requires a delegated token.
+func (c *UsersClient) ListMyMemberOf(ctx context.Context, query odata.Query) (*[]Membership, int, error) {
+	if err := c.BaseClient.requireDelegatedToken(ctx, "/me/memberOf"); err != nil {
+		return nil, 0, err
+	}
+	return c.listMemberships(ctx, Uri{Entity: "/me/memberOf", HasTenantId: false}, query)
+}
+
+// ListMyTransitiveMemberOf returns the groups, directory roles and administrative units that the signed-in User is a
+// member of, either directly or through nested group membership. This requires a delegated token.
+func (c *UsersClient) ListMyTransitiveMemberOf(ctx context.Context, query odata.Query) (*[]Membership, int, error) {
+	if err := c.BaseClient.requireDelegatedToken(ctx, "/me/transitiveMemberOf"); err != nil {
+		return nil, 0, err
+	}
+	return c.listMemberships(ctx, Uri{Entity: "/me/transitiveMemberOf", HasTenantId: false}, query)
+}
+
+// ListMyOwnedObjects returns the directory objects owned by the signed-in User. Each OwnedObject can be type asserted
+// back to the appropriate model. This requires a delegated token.
+func (c *UsersClient) ListMyOwnedObjects(ctx context.Context, query odata.Query) (*[]OwnedObject, int, error) {
+	if err := c.BaseClient.requireDelegatedToken(ctx, "/me/ownedObjects"); err != nil {
+		return nil, 0, err
+	}
+	return c.listOwnedObjects(ctx, Uri{Entity: "/me/ownedObjects", HasTenantId: false}, query)
+}
+
 // GetWithSchemaExtensions retrieves a User, including the values for any specified schema extensions
 func (c *UsersClient) GetWithSchemaExtensions(ctx context.Context, id string, query odata.Query, schemaExtensions *[]SchemaExtensionData) (*User, int, error) {
 	var sel []string
@@ -137,6 +405,7 @@ func (c *UsersClient) GetWithSchemaExtensions(ctx context.Context, id string, qu
 	var resp *http.Response
 	resp, status, _, err = c.BaseClient.Get(ctx, GetHttpRequestInput{
 		ConsistencyFailureFunc: RetryOn404ConsistencyFailureFunc,
+		ConsistencyLevel:       query.ConsistencyLevel,
 		ValidStatusCodes:       []int{http.StatusOK},
 		Uri: Uri{
 			Entity:      fmt.Sprintf("/users/%s", id),
@@ -145,7 +414,7 @@ func (c *UsersClient) GetWithSchemaExtensions(ctx context.Context, id string, qu
 		},
 	})
 	if err != nil {
-		return nil, status, fmt.Errorf("UsersClient.BaseClient.Get(): %v", err)
+		return nil, status, fmt.Errorf("UsersClient.BaseClient.Get(): %w", err)
 	}
 
 	defer resp.Body.Close()
@@ -155,7 +424,7 @@ func (c *UsersClient) GetWithSchemaExtensions(ctx context.Context, id string, qu
 	}
 
 	user.SchemaExtensions = schemaExtensions
-	if err := json.Unmarshal(respBody, user); err != nil {
+	if err := c.BaseClient.decode(respBody, user); err != nil {
 		return nil, status, fmt.Errorf("json.Unmarshal(): %v", err)
 	}
 
@@ -166,6 +435,7 @@ func (c *UsersClient) GetWithSchemaExtensions(ctx context.Context, id string, qu
 func (c *UsersClient) GetDeleted(ctx context.Context, id string, query odata.Query) (*User, int, error) {
 	resp, status, _, err := c.BaseClient.Get(ctx, GetHttpRequestInput{
 		ConsistencyFailureFunc: RetryOn404ConsistencyFailureFunc,
+		ConsistencyLevel:       query.ConsistencyLevel,
 		ValidStatusCodes:       []int{http.StatusOK},
 		Uri: Uri{
 			Entity:      fmt.Sprintf("/directory/deletedItems/%s", id),
@@ -174,7 +444,7 @@ func (c *UsersClient) GetDeleted(ctx context.Context, id string, query odata.Que
 		},
 	})
 	if err != nil {
-		return nil, status, fmt.Errorf("UsersClient.BaseClient.Get(): %v", err)
+		return nil, status, fmt.Errorf("UsersClient.BaseClient.Get(): %w", err)
 	}
 
 	defer resp.Body.Close()
@@ -184,7 +454,7 @@ func (c *UsersClient) GetDeleted(ctx context.Context, id string, query odata.Que
 	}
 
 	var user User
-	if err := json.Unmarshal(respBody, &user); err != nil {
+	if err := c.BaseClient.decode(respBody, &user); err != nil {
 		return nil, status, fmt.Errorf("json.Unmarshal(): %v", err)
 	}
 
@@ -193,6 +463,13 @@ func (c *UsersClient) GetDeleted(ctx context.Context, id string, query odata.Que
 
 // Update amends an existing User.
 func (c *UsersClient) Update(ctx context.Context, user User) (int, error) {
+	return c.UpdateAndClear(ctx, user, nil)
+}
+
+// UpdateAndClear amends an existing User, additionally clearing the properties named in clear by setting them to
+// null. Since empty fields are omitted when a User is marshaled, this is the way to remove a property value,
+// e.g. `[]string{"department"}`. Property names are those used by the API.
+func (c *UsersClient) UpdateAndClear(ctx context.Context, user User, clear []string) (int, error) {
 	var status int
 
 	body, err := json.Marshal(user)
@@ -203,6 +480,7 @@ func (c *UsersClient) Update(ctx context.Context, user User) (int, error) {
 	_, status, _, err = c.BaseClient.Patch(ctx, PatchHttpRequestInput{
 		Body:                   body,
 		ConsistencyFailureFunc: RetryOn404ConsistencyFailureFunc,
+		NullProperties:         clear,
 		ValidStatusCodes:       []int{http.StatusNoContent},
 		Uri: Uri{
 			Entity:      fmt.Sprintf("/users/%s", *user.ID),
@@ -210,12 +488,46 @@ func (c *UsersClient) Update(ctx context.Context, user User) (int, error) {
 		},
 	})
 	if err != nil {
-		return status, fmt.Errorf("UsersClient.BaseClient.Patch(): %v", err)
+		return status, fmt.Errorf("UsersClient.BaseClient.Patch(): %w", err)
 	}
 
 	return status, nil
 }
 
+// UpdateAndReturn amends an existing User as for Update, and returns the updated User as represented by the API, which
+// saves retrieving it afterwards. Where the API does not return the updated object, the specified user is returned
+// instead.
+func (c *UsersClient) UpdateAndReturn(ctx context.Context, user User) (*User, int, error) {
+	var status int
+
+	if user.ID == nil {
+		return nil, status, fmt.Errorf("UsersClient.UpdateAndReturn(): cannot update user with nil ID")
+	}
+
+	body, err := json.Marshal(user)
+	if err != nil {
+		return nil, status, fmt.Errorf("json.Marshal(): %v", err)
+	}
+
+	var updated User
+	returned, status, err := c.BaseClient.patchAndReturn(ctx, PatchHttpRequestInput{
+		Body:                   body,
+		ConsistencyFailureFunc: RetryOn404ConsistencyFailureFunc,
+		Uri: Uri{
+			Entity:      fmt.Sprintf("/users/%s", *user.ID),
+			HasTenantId: true,
+		},
+	}, &updated)
+	if err != nil {
+		return nil, status, fmt.Errorf("UsersClient.BaseClient.Patch(): %w", err)
+	}
+	if !returned {
+		return &user, status, nil
+	}
+
+	return &updated, status, nil
+}
+
 // Delete removes a User.
 func (c *UsersClient) Delete(ctx context.Context, id string) (int, error) {
 	_, status, _, err := c.BaseClient.Delete(ctx, DeleteHttpRequestInput{
@@ -227,7 +539,7 @@ func (c *UsersClient) Delete(ctx context.Context, id string) (int, error) {
 		},
 	})
 	if err != nil {
-		return status, fmt.Errorf("UsersClient.BaseClient.Delete(): %v", err)
+		return status, fmt.Errorf("UsersClient.BaseClient.Delete(): %w", err)
 	}
 
 	return status, nil
@@ -244,7 +556,7 @@ func (c *UsersClient) DeletePermanently(ctx context.Context, id string) (int, er
 		},
 	})
 	if err != nil {
-		return status, fmt.Errorf("UsersClient.BaseClient.Delete(): %v", err)
+		return status, fmt.Errorf("UsersClient.BaseClient.Delete(): %w", err)
 	}
 
 	return status, nil
@@ -253,6 +565,7 @@ func (c *UsersClient) DeletePermanently(ctx context.Context, id string) (int, er
 // ListDeleted retrieves a list of recently deleted users, optionally queried using OData.
 func (c *UsersClient) ListDeleted(ctx context.Context, query odata.Query) (*[]User, int, error) {
 	resp, status, _, err := c.BaseClient.Get(ctx, GetHttpRequestInput{
+		ConsistencyLevel: query.ConsistencyLevel,
 		DisablePaging:    query.Top > 0,
 		ValidStatusCodes: []int{http.StatusOK},
 		Uri: Uri{
@@ -270,7 +583,7 @@ func (c *UsersClient) ListDeleted(ctx context.Context, query odata.Query) (*[]Us
 	var data struct {
 		DeletedUsers []User `json:"value"`
 	}
-	if err = json.Unmarshal(respBody, &data); err != nil {
+	if err = c.BaseClient.decode(respBody, &data); err != nil {
 		return nil, status, err
 	}
 
@@ -288,7 +601,7 @@ func (c *UsersClient) RestoreDeleted(ctx context.Context, id string) (*User, int
 		},
 	})
 	if err != nil {
-		return nil, status, fmt.Errorf("UsersClient.BaseClient.Post(): %v", err)
+		return nil, status, fmt.Errorf("UsersClient.BaseClient.Post(): %w", err)
 	}
 
 	defer resp.Body.Close()
@@ -298,7 +611,7 @@ func (c *UsersClient) RestoreDeleted(ctx context.Context, id string) (*User, int
 	}
 
 	var restoredUser User
-	if err = json.Unmarshal(respBody, &restoredUser); err != nil {
+	if err = c.BaseClient.decode(respBody, &restoredUser); err != nil {
 		return nil, status, fmt.Errorf("json.Unmarshal(): %v", err)
 	}
 
@@ -308,6 +621,7 @@ func (c *UsersClient) RestoreDeleted(ctx context.Context, id string) (*User, int
 // ListGroupMemberships returns a list of Groups the user is member of, optionally queried using OData.
 func (c *UsersClient) ListGroupMemberships(ctx context.Context, id string, query odata.Query) (*[]Group, int, error) {
 	resp, status, _, err := c.BaseClient.Get(ctx, GetHttpRequestInput{
+		ConsistencyLevel:       query.ConsistencyLevel,
 		DisablePaging:          query.Top > 0,
 		ConsistencyFailureFunc: RetryOn404ConsistencyFailureFunc,
 		ValidStatusCodes:       []int{http.StatusOK},
@@ -318,7 +632,7 @@ func (c *UsersClient) ListGroupMemberships(ctx context.Context, id string, query
 		},
 	})
 	if err != nil {
-		return nil, status, fmt.Errorf("UsersClient.BaseClient.Get(): %v", err)
+		return nil, status, fmt.Errorf("UsersClient.BaseClient.Get(): %w", err)
 	}
 
 	defer resp.Body.Close()
@@ -330,13 +644,451 @@ func (c *UsersClient) ListGroupMemberships(ctx context.Context, id string, query
 	var data struct {
 		Groups []Group `json:"value"`
 	}
-	if err := json.Unmarshal(respBody, &data); err != nil {
+	if err := c.BaseClient.decode(respBody, &data); err != nil {
 		return nil, status, fmt.Errorf("json.Unmarshal(): %v", err)
 	}
 
 	return &data.Groups, status, nil
 }
 
+// GetMemberGroups retrieves IDs of the groups that a User is a member of, either directly or transitively.
+// This is more efficient than listing memberships for users who are a member of many groups.
+// id is the object ID of the user.
+func (c *UsersClient) GetMemberGroups(ctx context.Context, id string, securityEnabledOnly bool) (*[]string, int, error) {
+	var status int
+
+	body, err := json.Marshal(struct {
+		SecurityEnabledOnly bool `json:"securityEnabledOnly"`
+	}{
+		SecurityEnabledOnly: securityEnabledOnly,
+	})
+	if err != nil {
+		return nil, status, fmt.Errorf("json.Marshal(): %v", err)
+	}
+
+	resp, status, _, err := c.BaseClient.Post(ctx, PostHttpRequestInput{
+		Body:                   body,
+		ConsistencyFailureFunc: RetryOn404ConsistencyFailureFunc,
+		ValidStatusCodes:       []int{http.StatusOK},
+		Uri: Uri{
+			Entity:      fmt.Sprintf("/users/%s/getMemberGroups", id),
+			HasTenantId: true,
+		},
+	})
+	if err != nil {
+		return nil, status, fmt.Errorf("UsersClient.BaseClient.Post(): %w", err)
+	}
+
+	defer resp.Body.Close()
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, status, fmt.Errorf("io.ReadAll(): %v", err)
+	}
+
+	var data struct {
+		IDs []string `json:"value"`
+	}
+	if err := c.BaseClient.decode(respBody, &data); err != nil {
+		return nil, status, fmt.Errorf("json.Unmarshal(): %v", err)
+	}
+
+	return &data.IDs, status, nil
+}
+
+// CheckMemberGroups checks whether a User is a member of the specified groups, either directly or transitively, and
+// returns the IDs of those groups which the user is a member of. Up to 20 group IDs can be checked in a single request.
+// id is the object ID of the user.
+func (c *UsersClient) CheckMemberGroups(ctx context.Context, id string, groupIds []string) (*[]string, int, error) {
+	var status int
+
+	if len(groupIds) > 20 {
+		return nil, status, fmt.Errorf("UsersClient.CheckMemberGroups(): cannot check more than 20 groups, got %d", len(groupIds))
+	}
+
+	body, err := json.Marshal(struct {
+		GroupIds []string `json:"groupIds"`
+	}{
+		GroupIds: groupIds,
+	})
+	if err != nil {
+		return nil, status, fmt.Errorf("json.Marshal(): %v", err)
+	}
+
+	resp, status, _, err := c.BaseClient.Post(ctx, PostHttpRequestInput{
+		Body:                   body,
+		ConsistencyFailureFunc: RetryOn404ConsistencyFailureFunc,
+		ValidStatusCodes:       []int{http.StatusOK},
+		Uri: Uri{
+			Entity:      fmt.Sprintf("/users/%s/checkMemberGroups", id),
+			HasTenantId: true,
+		},
+	})
+	if err != nil {
+		return nil, status, fmt.Errorf("UsersClient.BaseClient.Post(): %w", err)
+	}
+
+	defer resp.Body.Close()
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, status, fmt.Errorf("io.ReadAll(): %v", err)
+	}
+
+	var data struct {
+		IDs []string `json:"value"`
+	}
+	if err := c.BaseClient.decode(respBody, &data); err != nil {
+		return nil, status, fmt.Errorf("json.Unmarshal(): %v", err)
+	}
+
+	return &data.IDs, status, nil
+}
+
+// ListMemberOf returns the groups, directory roles and administrative units that a User is a direct member of.
+// Each Membership can be type asserted back to the appropriate model.
+// id is the object ID of the user.
+func (c *UsersClient) ListMemberOf(ctx context.Context, id string, query odata.Query) (*[]Membership, int, error) {
+	return c.listMemberships(ctx, Uri{Entity: fmt.Sprintf("/users/%s/memberOf", id), HasTenantId: true}, query)
+}
+
+// ListTransitiveMemberOf returns the groups, directory roles and administrative units that a User is a member of,
+// either directly or through nested group membership. Each Membership can be type asserted back to the appropriate model.
+// id is the object ID of the user.
+func (c *UsersClient) ListTransitiveMemberOf(ctx context.Context, id string, query odata.Query) (*[]Membership, int, error) {
+	return c.listMemberships(ctx, Uri{Entity: fmt.Sprintf("/users/%s/transitiveMemberOf", id), HasTenantId: true}, query)
+}
+
+func (c *UsersClient) listMemberships(ctx context.Context, uri Uri, query odata.Query) (*[]Membership, int, error) {
+	uri.Params = query.Values()
+	resp, status, o, err := c.BaseClient.Get(ctx, GetHttpRequestInput{
+		ConsistencyLevel:       query.ConsistencyLevel,
+		DisablePaging:          query.Top > 0,
+		ConsistencyFailureFunc: RetryOn404ConsistencyFailureFunc,
+		ValidStatusCodes:       []int{http.StatusOK},
+		Uri:                    uri,
+	})
+	if delegatedAuthRequired(status, o) {
+		return nil, status, errors.DelegatedAuthRequiredError{Entity: uri.Entity}
+	}
+	if err != nil {
+		return nil, status, fmt.Errorf("UsersClient.BaseClient.Get(): %w", err)
+	}
+
+	defer resp.Body.Close()
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, status, fmt.Errorf("io.ReadAll(): %v", err)
+	}
+
+	var data struct {
+		Memberships []json.RawMessage `json:"value"`
+	}
+	if err := c.BaseClient.decode(respBody, &data); err != nil {
+		return nil, status, fmt.Errorf("json.Unmarshal(): %v", err)
+	}
+
+	ret := make([]Membership, 0, len(data.Memberships))
+	for _, m := range data.Memberships {
+		membership, err := unmarshalMembership(m)
+		if err != nil {
+			return nil, status, err
+		}
+		ret = append(ret, membership)
+	}
+
+	return &ret, status, nil
+}
+
+// unmarshalMembership matches up a membership to the appropriate model using its @odata.type.
+func unmarshalMembership(data []byte) (Membership, error) {
+	var o odata.OData
+	if err := json.Unmarshal(data, &o); err != nil {
+		return nil, fmt.Errorf("json.Unmarshal(): %v", err)
+	}
+
+	var ret Membership
+	if o.Type == nil {
+		var directoryObject DirectoryObject
+		if err := json.Unmarshal(data, &directoryObject); err != nil {
+			return nil, fmt.Errorf("json.Unmarshal(): %v", err)
+		}
+		return directoryObject, nil
+	}
+
+	switch *o.Type {
+	case odata.TypeDirectoryRole:
+		var directoryRole DirectoryRole
+		if err := json.Unmarshal(data, &directoryRole); err != nil {
+			return nil, fmt.Errorf("json.Unmarshal(): %v", err)
+		}
+		ret = directoryRole
+	case odata.TypeGroup:
+		var group Group
+		if err := json.Unmarshal(data, &group); err != nil {
+			return nil, fmt.Errorf("json.Unmarshal(): %v", err)
+		}
+		ret = group
+	default:
+		var directoryObject DirectoryObject
+		if err := json.Unmarshal(data, &directoryObject); err != nil {
+			return nil, fmt.Errorf("json.Unmarshal(): %v", err)
+		}
+		ret = directoryObject
+	}
+
+	return ret, nil
+}
+
+// ListOwnedObjects returns the directory objects owned by a User, which may include applications, groups and service
+// principals. Each OwnedObject can be type asserted back to the appropriate model.
+// id is the object ID of the user.
+func (c *UsersClient) ListOwnedObjects(ctx context.Context, id string, query odata.Query) (*[]OwnedObject, int, error) {
+	return c.listOwnedObjects(ctx, Uri{Entity: fmt.Sprintf("/users/%s/ownedObjects", id), HasTenantId: true}, query)
+}
+
+func (c *UsersClient) listOwnedObjects(ctx context.Context, uri Uri, query odata.Query) (*[]OwnedObject, int, error) {
+	uri.Params = query.Values()
+	resp, status, o, err := c.BaseClient.Get(ctx, GetHttpRequestInput{
+		ConsistencyLevel:       query.ConsistencyLevel,
+		DisablePaging:          query.Top > 0,
+		ConsistencyFailureFunc: RetryOn404ConsistencyFailureFunc,
+		ValidStatusCodes:       []int{http.StatusOK},
+		Uri:                    uri,
+	})
+	if delegatedAuthRequired(status, o) {
+		return nil, status, errors.DelegatedAuthRequiredError{Entity: uri.Entity}
+	}
+	if err != nil {
+		return nil, status, fmt.Errorf("UsersClient.BaseClient.Get(): %w", err)
+	}
+
+	defer resp.Body.Close()
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, status, fmt.Errorf("io.ReadAll(): %v", err)
+	}
+
+	var data struct {
+		OwnedObjects []json.RawMessage `json:"value"`
+	}
+	if err := c.BaseClient.decode(respBody, &data); err != nil {
+		return nil, status, fmt.Errorf("json.Unmarshal(): %v", err)
+	}
+
+	ret := make([]OwnedObject, 0, len(data.OwnedObjects))
+	for _, v := range data.OwnedObjects {
+		ownedObject, err := unmarshalOwnedObject(v)
+		if err != nil {
+			return nil, status, err
+		}
+		ret = append(ret, ownedObject)
+	}
+
+	return &ret, status, nil
+}
+
+// unmarshalOwnedObject decodes an owned object according to its @odata.type, falling back to a DirectoryObject
+func unmarshalOwnedObject(data []byte) (OwnedObject, error) {
+	var o odata.OData
+	if err := json.Unmarshal(data, &o); err != nil {
+		return nil, fmt.Errorf("json.Unmarshal(): %v", err)
+	}
+
+	var ret OwnedObject
+	var err error
+	switch {
+	case o.Type != nil && *o.Type == odata.TypeApplication:
+		var application Application
+		err = json.Unmarshal(data, &application)
+		ret = application
+	case o.Type != nil && *o.Type == odata.TypeGroup:
+		var group Group
+		err = json.Unmarshal(data, &group)
+		ret = group
+	case o.Type != nil && *o.Type == odata.TypeServicePrincipal:
+		var servicePrincipal ServicePrincipal
+		err = json.Unmarshal(data, &servicePrincipal)
+		ret = servicePrincipal
+	default:
+		var directoryObject DirectoryObject
+		err = json.Unmarshal(data, &directoryObject)
+		ret = directoryObject
+	}
+	if err != nil {
+		return nil, fmt.Errorf("json.Unmarshal(): %v", err)
+	}
+
+	return ret, nil
+}
+
+// GetManager retrieves the manager of a User, which is usually another User but can also be an organizational contact.
+// The Manager can be type asserted back to the appropriate model, with organizational contacts returned as a
+// DirectoryObject. When the user does not have a manager, an errors.NoManagerError is returned.
+// id is the object ID of the user.
+func (c *UsersClient) GetManager(ctx context.Context, id string, query odata.Query) (Manager, int, error) {
+	resp, status, o, err := c.BaseClient.Get(ctx, GetHttpRequestInput{
+		ConsistencyFailureFunc: retryOn404UnlessNoManager,
+		ConsistencyLevel:       query.ConsistencyLevel,
+		ValidStatusCodes:       []int{http.StatusOK},
+		Uri: Uri{
+			Entity:      fmt.Sprintf("/users/%s/manager", id),
+			Params:      query.Values(),
+			HasTenantId: true,
+		},
+	})
+	if err != nil {
+		if noManager(status, o) {
+			return nil, status, errors.NoManagerError{UserId: id}
+		}
+		return nil, status, fmt.Errorf("UsersClient.BaseClient.Get(): %w", err)
+	}
+
+	defer resp.Body.Close()
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, status, fmt.Errorf("io.ReadAll(): %v", err)
+	}
+
+	manager, err := unmarshalOrgContactOrUser(respBody)
+	if err != nil {
+		return nil, status, err
+	}
+
+	return manager, status, nil
+}
+
+// AssignManager sets the manager of a User, replacing any existing manager.
+// id is the object ID of the user.
+// managerId is the object ID of the user or organizational contact to assign as manager.
+func (c *UsersClient) AssignManager(ctx context.Context, id, managerId string) (int, error) {
+	var status int
+
+	manager := DirectoryObject{ID: &managerId}
+	data := struct {
+		Manager odata.Id `json:"@odata.id"`
+	}{
+		Manager: odata.Id(manager.Uri(c.BaseClient.Endpoint, c.BaseClient.ApiVersion)),
+	}
+	body, err := json.Marshal(data)
+	if err != nil {
+		return status, fmt.Errorf("json.Marshal(): %v", err)
+	}
+
+	_, status, _, err = c.BaseClient.Put(ctx, PutHttpRequestInput{
+		Body:                   body,
+		ConsistencyFailureFunc: RetryOn404ConsistencyFailureFunc,
+		ValidStatusCodes:       []int{http.StatusNoContent},
+		Uri: Uri{
+			Entity:      fmt.Sprintf("/users/%s/manager/$ref", id),
+			HasTenantId: true,
+		},
+	})
+	if err != nil {
+		return status, fmt.Errorf("UsersClient.BaseClient.Put(): %w", err)
+	}
+
+	return status, nil
+}
+
+// RemoveManager removes the manager of a User. When the user does not have a manager, an errors.NoManagerError is
+// returned.
+// id is the object ID of the user.
+func (c *UsersClient) RemoveManager(ctx context.Context, id string) (int, error) {
+	_, status, o, err := c.BaseClient.Delete(ctx, DeleteHttpRequestInput{
+		ConsistencyFailureFunc: retryOn404UnlessNoManager,
+		ValidStatusCodes:       []int{http.StatusNoContent},
+		Uri: Uri{
+			Entity:      fmt.Sprintf("/users/%s/manager/$ref", id),
+			HasTenantId: true,
+		},
+	})
+	if err != nil {
+		if noManager(status, o) {
+			return status, errors.NoManagerError{UserId: id}
+		}
+		return status, fmt.Errorf("UsersClient.BaseClient.Delete(): %w", err)
+	}
+
+	return status, nil
+}
+
+// ListDirectReports returns the users and organizational contacts who report to a User. Each DirectReport can be type
+// asserted back to the appropriate model, with organizational contacts returned as a DirectoryObject.
+// id is the object ID of the user.
+func (c *UsersClient) ListDirectReports(ctx context.Context, id string, query odata.Query) (*[]DirectReport, int, error) {
+	resp, status, _, err := c.BaseClient.Get(ctx, GetHttpRequestInput{
+		ConsistencyLevel:       query.ConsistencyLevel,
+		DisablePaging:          query.Top > 0,
+		ConsistencyFailureFunc: RetryOn404ConsistencyFailureFunc,
+		ValidStatusCodes:       []int{http.StatusOK},
+		Uri: Uri{
+			Entity:      fmt.Sprintf("/users/%s/directReports", id),
+			Params:      query.Values(),
+			HasTenantId: true,
+		},
+	})
+	if err != nil {
+		return nil, status, fmt.Errorf("UsersClient.BaseClient.Get(): %w", err)
+	}
+
+	defer resp.Body.Close()
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, status, fmt.Errorf("io.ReadAll(): %v", err)
+	}
+
+	var data struct {
+		DirectReports []json.RawMessage `json:"value"`
+	}
+	if err := c.BaseClient.decode(respBody, &data); err != nil {
+		return nil, status, fmt.Errorf("json.Unmarshal(): %v", err)
+	}
+
+	ret := make([]DirectReport, 0, len(data.DirectReports))
+	for _, v := range data.DirectReports {
+		directReport, err := unmarshalOrgContactOrUser(v)
+		if err != nil {
+			return nil, status, err
+		}
+		ret = append(ret, directReport)
+	}
+
+	return &ret, status, nil
+}
+
+// retryOn404UnlessNoManager retries a request when a 404 response is received, except when the user does not have a
+// manager, which is not a consistency failure
+func retryOn404UnlessNoManager(resp *http.Response, o *odata.OData) bool {
+	return RetryOn404ConsistencyFailureFunc(resp, o) && !noManager(resp.StatusCode, o)
+}
+
+// noManager determines whether a response indicates that the user does not have a manager
+func noManager(status int, o *odata.OData) bool {
+	return status == http.StatusNotFound && o != nil && o.Error != nil && o.Error.Match(odata.ErrorManagerDoesNotExist)
+}
+
+// unmarshalOrgContactOrUser decodes a User according to its @odata.type, falling back to a DirectoryObject for
+// organizational contacts and any other types
+func unmarshalOrgContactOrUser(data []byte) (interface{}, error) {
+	var o odata.OData
+	if err := json.Unmarshal(data, &o); err != nil {
+		return nil, fmt.Errorf("json.Unmarshal(): %v", err)
+	}
+
+	if o.Type != nil && *o.Type == odata.TypeUser {
+		var user User
+		if err := json.Unmarshal(data, &user); err != nil {
+			return nil, fmt.Errorf("json.Unmarshal(): %v", err)
+		}
+		return user, nil
+	}
+
+	var directoryObject DirectoryObject
+	if err := json.Unmarshal(data, &directoryObject); err != nil {
+		return nil, fmt.Errorf("json.Unmarshal(): %v", err)
+	}
+	return directoryObject, nil
+}
+
 // SendMail sends message specified in the request body.
 // TODO: Needs testing with an O365 user principal
 func (c *UsersClient) Sendmail(ctx context.Context, id string, message MailMessage) (int, error) {
@@ -356,8 +1108,96 @@ func (c *UsersClient) Sendmail(ctx context.Context, id string, message MailMessa
 		},
 	})
 	if err != nil {
-		return status, fmt.Errorf("UsersClient.BaseClient.Post(): %v", err)
+		return status, fmt.Errorf("UsersClient.BaseClient.Post(): %w", err)
 	}
 
 	return status, nil
 }
+
+// RevokeSignInSessions invalidates all refresh tokens and session cookies issued to a user, requiring the user to
+// sign in again. There may be a short delay of a few minutes before the tokens are revoked.
+func (c *UsersClient) RevokeSignInSessions(ctx context.Context, id string) (bool, int, error) {
+	return c.revokeTokens(ctx, fmt.Sprintf("/users/%s/revokeSignInSessions", id))
+}
+
+// InvalidateAllRefreshTokens invalidates all refresh tokens issued to applications for a user. This is available in
+// the beta API only, and has been superseded by RevokeSignInSessions which also invalidates session cookies.
+func (c *UsersClient) InvalidateAllRefreshTokens(ctx context.Context, id string) (bool, int, error) {
+	return c.revokeTokens(ctx, fmt.Sprintf("/users/%s/invalidateAllRefreshTokens", id))
+}
+
+// revokeTokens invokes a token revocation action for a user, returning the result of the action.
+func (c *UsersClient) revokeTokens(ctx context.Context, entity string) (bool, int, error) {
+	resp, status, _, err := c.BaseClient.Post(ctx, PostHttpRequestInput{
+		ConsistencyFailureFunc: RetryOn404ConsistencyFailureFunc,
+		ValidStatusCodes:       []int{http.StatusOK},
+		Uri: Uri{
+			Entity:      entity,
+			HasTenantId: true,
+		},
+	})
+	if err != nil {
+		return false, status, fmt.Errorf("UsersClient.BaseClient.Post(): %w", err)
+	}
+
+	defer resp.Body.Close()
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return false, status, fmt.Errorf("io.ReadAll(): %v", err)
+	}
+
+	var data struct {
+		Value bool `json:"value"`
+	}
+	if err := c.BaseClient.decode(respBody, &data); err != nil {
+		return false, status, fmt.Errorf("json.Unmarshal(): %v", err)
+	}
+
+	return data.Value, status, nil
+}
+
+// GetPhoto retrieves the profile photo of a user, returning its content and media type. When etag is not
+// empty, the photo is only retrieved if it has changed since, otherwise ErrNotModified is returned. The ETag of the
+// current photo is available from GetPhotoMetadata().
+// id is the object ID of the user.
+func (c *UsersClient) GetPhoto(ctx context.Context, id, etag string) ([]byte, string, int, error) {
+	content, contentType, status, err := c.BaseClient.getPhoto(ctx, Uri{
+		Entity:      fmt.Sprintf("/users/%s/photo", id),
+		HasTenantId: true,
+	}, etag)
+	if err == ErrNotModified {
+		return nil, "", status, err
+	}
+	if err != nil {
+		return nil, "", status, fmt.Errorf("UsersClient.BaseClient.Get(): %w", err)
+	}
+
+	return content, contentType, status, nil
+}
+
+// GetPhotoMetadata retrieves the ProfilePhoto of a user, including the ETag of the current photo.
+// id is the object ID of the user.
+func (c *UsersClient) GetPhotoMetadata(ctx context.Context, id string) (*ProfilePhoto, int, error) {
+	photo, status, err := c.BaseClient.getPhotoMetadata(ctx, Uri{
+		Entity:      fmt.Sprintf("/users/%s/photo", id),
+		HasTenantId: true,
+	})
+	if err != nil {
+		return nil, status, fmt.Errorf("UsersClient.BaseClient.Get(): %w", err)
+	}
+
+	return photo, status, nil
+}
+
+// validUsageLocation returns whether location is formatted as an ISO 3166-1 alpha-2 country code
+func validUsageLocation(location string) bool {
+	if len(location) != 2 {
+		return false
+	}
+	for _, r := range location {
+		if (r < 'A' || r > 'Z') && (r < 'a' || r > 'z') {
+			return false
+		}
+	}
+	return true
+}
@@ -1,10 +1,24 @@
 package msgraph_test
 
 import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
 	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"regexp"
+	"strings"
+	"sync"
 	"testing"
+	"time"
+
+	"golang.org/x/oauth2"
 
 	"github.com/manicminer/hamilton/auth"
+	"github.com/manicminer/hamilton/environments"
+	"github.com/manicminer/hamilton/errors"
 	"github.com/manicminer/hamilton/internal/test"
 	"github.com/manicminer/hamilton/internal/utils"
 	"github.com/manicminer/hamilton/msgraph"
@@ -36,9 +50,30 @@ func TestUsersClient(t *testing.T) {
 		},
 	})
 	testUsersClient_Get(t, c, *user.ID)
+	testUsersClient_GetSelected(t, c, *user.ID)
 	user.DisplayName = utils.StringPtr(fmt.Sprintf("test-updated-user-%s", c.randomString))
 	testUsersClient_Update(t, c, *user)
+	testUsersClient_UpdateAndClear(t, c, msgraph.User{DirectoryObject: msgraph.DirectoryObject{ID: user.ID}}, []string{"department"})
 	testUsersClient_List(t, c)
+	testUsersClient_RevokeSignInSessions(t, c, *user.ID)
+
+	manager := testUsersClient_Create(t, c, msgraph.User{
+		AccountEnabled:    utils.BoolPtr(true),
+		DisplayName:       utils.StringPtr("test-manager"),
+		MailNickname:      utils.StringPtr(fmt.Sprintf("test-manager-%s", c.randomString)),
+		UserPrincipalName: utils.StringPtr(fmt.Sprintf("test-manager-%s@%s", c.randomString, c.connection.DomainName)),
+		PasswordProfile: &msgraph.UserPasswordProfile{
+			ForceChangePasswordNextSignIn:        utils.BoolPtr(true),
+			ForceChangePasswordNextSignInWithMfa: utils.BoolPtr(false),
+			Password:                             utils.StringPtr(fmt.Sprintf("IrPa55w0rd%s", c.randomString)),
+		},
+	})
+	testUsersClient_AssignManager(t, c, *user.ID, *manager.ID)
+	testUsersClient_GetManager(t, c, *user.ID, *manager.ID)
+	testUsersClient_ListDirectReports(t, c, *manager.ID, *user.ID)
+	testUsersClient_RemoveManager(t, c, *user.ID)
+	testUsersClient_GetManagerNotSet(t, c, *user.ID)
+	testUsersClient_Delete(t, c, *manager.ID)
 
 	g := GroupsClientTest{
 		connection:   test.NewConnection(auth.MsGraph, auth.TokenVersion2),
@@ -68,6 +103,12 @@ func TestUsersClient(t *testing.T) {
 	testGroupsClient_AddMembers(t, g, groupChild)
 
 	testUsersClient_ListGroupMemberships(t, c, *user.ID)
+	testUsersClient_ListMemberOf(t, c, *user.ID, *groupChild.ID)
+	testUsersClient_ListTransitiveMemberOf(t, c, *user.ID, *groupParent.ID)
+	testUsersClient_GetMemberGroups(t, c, *user.ID, []string{*groupParent.ID, *groupChild.ID})
+	testUsersClient_CheckMemberGroups(t, c, *user.ID, []string{*groupParent.ID, *groupChild.ID})
+	testGroupsClient_AddOwner(t, g, *groupChild.ID, *user.ID)
+	testUsersClient_ListOwnedObjects(t, c, *user.ID, *groupChild.ID)
 	testGroupsClient_Delete(t, g, *groupParent.ID)
 	testGroupsClient_Delete(t, g, *groupChild.ID)
 
@@ -106,6 +147,16 @@ func testUsersClient_Update(t *testing.T, c UsersClientTest, u msgraph.User) {
 	}
 }
 
+func testUsersClient_UpdateAndClear(t *testing.T, c UsersClientTest, u msgraph.User, clear []string) {
+	status, err := c.client.UpdateAndClear(c.connection.Context, u, clear)
+	if err != nil {
+		t.Fatalf("UsersClient.UpdateAndClear(): %v", err)
+	}
+	if status < 200 || status >= 300 {
+		t.Fatalf("UsersClient.UpdateAndClear(): invalid status: %d", status)
+	}
+}
+
 func testUsersClient_List(t *testing.T, c UsersClientTest) (users *[]msgraph.User) {
 	users, _, err := c.client.List(c.connection.Context, odata.Query{Top: 10, Expand: odata.Expand{Relationship: "memberOf"}})
 	if err != nil {
@@ -131,6 +182,26 @@ func testUsersClient_Get(t *testing.T, c UsersClientTest, id string) (user *msgr
 	return
 }
 
+func testUsersClient_GetSelected(t *testing.T, c UsersClientTest, id string) (user *msgraph.User) {
+	user, status, err := c.client.Get(c.connection.Context, id, odata.Query{Select: []string{"id", "displayName"}})
+	if err != nil {
+		t.Fatalf("UsersClient.Get(): %v", err)
+	}
+	if status < 200 || status >= 300 {
+		t.Fatalf("UsersClient.Get(): invalid status: %d", status)
+	}
+	if user == nil {
+		t.Fatal("UsersClient.Get(): user was nil")
+	}
+	if !user.WasSelected("displayName") {
+		t.Fatal("UsersClient.Get(): expected displayName to be selected")
+	}
+	if user.WasSelected("mailNickname") {
+		t.Fatal("UsersClient.Get(): expected mailNickname not to be selected")
+	}
+	return
+}
+
 func testUsersClient_GetDeleted(t *testing.T, c UsersClientTest, id string) (user *msgraph.User) {
 	user, status, err := c.client.GetDeleted(c.connection.Context, id, odata.Query{})
 	if err != nil {
@@ -230,3 +301,700 @@ func testUsersClient_RestoreDeleted(t *testing.T, c UsersClientTest, id string)
 		t.Fatal("UsersClient.RestoreDeleted(): user ids do not match")
 	}
 }
+
+func testUsersClient_ListMemberOf(t *testing.T, c UsersClientTest, id string, expectedGroupId string) (memberships *[]msgraph.Membership) {
+	memberships, _, err := c.client.ListMemberOf(c.connection.Context, id, odata.Query{})
+	if err != nil {
+		t.Fatalf("UsersClient.ListMemberOf(): %v", err)
+	}
+	if memberships == nil {
+		t.Fatal("UsersClient.ListMemberOf(): memberships was nil")
+	}
+	if !testUsersClient_hasGroupMembership(*memberships, expectedGroupId) {
+		t.Fatalf("UsersClient.ListMemberOf(): expected group %q in result", expectedGroupId)
+	}
+	return
+}
+
+func testUsersClient_ListTransitiveMemberOf(t *testing.T, c UsersClientTest, id string, expectedGroupId string) (memberships *[]msgraph.Membership) {
+	memberships, _, err := c.client.ListTransitiveMemberOf(c.connection.Context, id, odata.Query{})
+	if err != nil {
+		t.Fatalf("UsersClient.ListTransitiveMemberOf(): %v", err)
+	}
+	if memberships == nil {
+		t.Fatal("UsersClient.ListTransitiveMemberOf(): memberships was nil")
+	}
+	if !testUsersClient_hasGroupMembership(*memberships, expectedGroupId) {
+		t.Fatalf("UsersClient.ListTransitiveMemberOf(): expected group %q in result", expectedGroupId)
+	}
+	return
+}
+
+func testUsersClient_ListOwnedObjects(t *testing.T, c UsersClientTest, id string, expectedGroupId string) (ownedObjects *[]msgraph.OwnedObject) {
+	ownedObjects, _, err := c.client.ListOwnedObjects(c.connection.Context, id, odata.Query{})
+	if err != nil {
+		t.Fatalf("UsersClient.ListOwnedObjects(): %v", err)
+	}
+	if ownedObjects == nil {
+		t.Fatal("UsersClient.ListOwnedObjects(): ownedObjects was nil")
+	}
+	for _, o := range *ownedObjects {
+		if group, ok := o.(msgraph.Group); ok && group.ID != nil && *group.ID == expectedGroupId {
+			return
+		}
+	}
+	t.Fatalf("UsersClient.ListOwnedObjects(): expected group %q in result", expectedGroupId)
+	return
+}
+
+func testUsersClient_AssignManager(t *testing.T, c UsersClientTest, id, managerId string) {
+	status, err := c.client.AssignManager(c.connection.Context, id, managerId)
+	if err != nil {
+		t.Fatalf("UsersClient.AssignManager(): %v", err)
+	}
+	if status < 200 || status >= 300 {
+		t.Fatalf("UsersClient.AssignManager(): invalid status: %d", status)
+	}
+}
+
+func testUsersClient_GetManager(t *testing.T, c UsersClientTest, id, expectedManagerId string) (manager msgraph.Manager) {
+	manager, status, err := c.client.GetManager(c.connection.Context, id, odata.Query{})
+	if err != nil {
+		t.Fatalf("UsersClient.GetManager(): %v", err)
+	}
+	if status < 200 || status >= 300 {
+		t.Fatalf("UsersClient.GetManager(): invalid status: %d", status)
+	}
+	user, ok := manager.(msgraph.User)
+	if !ok {
+		t.Fatalf("UsersClient.GetManager(): expected manager to be a User, got %T", manager)
+	}
+	if user.ID == nil || *user.ID != expectedManagerId {
+		t.Fatalf("UsersClient.GetManager(): expected manager with ID %q, got %v", expectedManagerId, user.ID)
+	}
+	return
+}
+
+func testUsersClient_GetManagerNotSet(t *testing.T, c UsersClientTest, id string) {
+	_, _, err := c.client.GetManager(c.connection.Context, id, odata.Query{})
+	if _, ok := err.(errors.NoManagerError); !ok {
+		t.Fatalf("UsersClient.GetManager(): expected NoManagerError, got: %v", err)
+	}
+}
+
+func testUsersClient_ListDirectReports(t *testing.T, c UsersClientTest, id, expectedReportId string) (directReports *[]msgraph.DirectReport) {
+	directReports, _, err := c.client.ListDirectReports(c.connection.Context, id, odata.Query{})
+	if err != nil {
+		t.Fatalf("UsersClient.ListDirectReports(): %v", err)
+	}
+	if directReports == nil {
+		t.Fatal("UsersClient.ListDirectReports(): directReports was nil")
+	}
+	for _, r := range *directReports {
+		if user, ok := r.(msgraph.User); ok && user.ID != nil && *user.ID == expectedReportId {
+			return
+		}
+	}
+	t.Fatalf("UsersClient.ListDirectReports(): expected direct report with ID %q", expectedReportId)
+	return
+}
+
+func testUsersClient_RemoveManager(t *testing.T, c UsersClientTest, id string) {
+	status, err := c.client.RemoveManager(c.connection.Context, id)
+	if err != nil {
+		t.Fatalf("UsersClient.RemoveManager(): %v", err)
+	}
+	if status < 200 || status >= 300 {
+		t.Fatalf("UsersClient.RemoveManager(): invalid status: %d", status)
+	}
+}
+
+func testUsersClient_hasGroupMembership(memberships []msgraph.Membership, groupId string) bool {
+	for _, m := range memberships {
+		if group, ok := m.(msgraph.Group); ok && group.ID != nil && *group.ID == groupId {
+			return true
+		}
+	}
+	return false
+}
+
+func testUsersClient_GetMemberGroups(t *testing.T, c UsersClientTest, id string, expectedGroupIds []string) (groupIds *[]string) {
+	groupIds, _, err := c.client.GetMemberGroups(c.connection.Context, id, true)
+	if err != nil {
+		t.Fatalf("UsersClient.GetMemberGroups(): %v", err)
+	}
+	if groupIds == nil {
+		t.Fatal("UsersClient.GetMemberGroups(): groupIds was nil")
+	}
+	testUsersClient_containsIds(t, "UsersClient.GetMemberGroups()", *groupIds, expectedGroupIds)
+	return
+}
+
+func testUsersClient_CheckMemberGroups(t *testing.T, c UsersClientTest, id string, groupIds []string) (memberGroupIds *[]string) {
+	memberGroupIds, _, err := c.client.CheckMemberGroups(c.connection.Context, id, groupIds)
+	if err != nil {
+		t.Fatalf("UsersClient.CheckMemberGroups(): %v", err)
+	}
+	if memberGroupIds == nil {
+		t.Fatal("UsersClient.CheckMemberGroups(): memberGroupIds was nil")
+	}
+	testUsersClient_containsIds(t, "UsersClient.CheckMemberGroups()", *memberGroupIds, groupIds)
+	return
+}
+
+func testUsersClient_containsIds(t *testing.T, method string, ids []string, expectedIds []string) {
+	for _, expected := range expectedIds {
+		found := false
+		for _, id := range ids {
+			if id == expected {
+				found = true
+				break
+			}
+		}
+		if !found {
+			t.Fatalf("%s: expected group %q in result", method, expected)
+		}
+	}
+}
+
+func testUsersClient_RevokeSignInSessions(t *testing.T, c UsersClientTest, id string) {
+	revoked, status, err := c.client.RevokeSignInSessions(c.connection.Context, id)
+	if err != nil {
+		t.Fatalf("UsersClient.RevokeSignInSessions(): %v", err)
+	}
+	if status < 200 || status >= 300 {
+		t.Fatalf("UsersClient.RevokeSignInSessions(): invalid status: %d", status)
+	}
+	if !revoked {
+		t.Fatal("UsersClient.RevokeSignInSessions(): expected sessions to be revoked")
+	}
+}
+
+func TestUsersClient_ExtensionAttributes(t *testing.T) {
+	const extensionName = "extension_0000000000000000000000000000000a_hrCode"
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprintf(w, `{"value":[{"id":"user1","onPremisesExtensionAttributes":{"extensionAttribute1":"HR-001","extensionAttribute15":null},%q:"HR-A"}]}`, extensionName)
+	}))
+	defer server.Close()
+
+	c := msgraph.NewUsersClient("00000000-0000-0000-0000-000000000000")
+	c.BaseClient.Endpoint = environments.ApiEndpoint(server.URL)
+	c.BaseClient.StrictDecode = true
+
+	users, _, err := c.List(context.Background(), odata.Query{Select: []string{"id", "onPremisesExtensionAttributes", extensionName}})
+	if err != nil {
+		t.Fatalf("UsersClient.List(): %v", err)
+	}
+	if users == nil || len(*users) != 1 {
+		t.Fatalf("UsersClient.List(): expected 1 user, got %v", users)
+	}
+	user := (*users)[0]
+	if user.OnPremisesExtensionAttributes == nil || user.OnPremisesExtensionAttributes.ExtensionAttribute1 == nil || *user.OnPremisesExtensionAttributes.ExtensionAttribute1 != "HR-001" {
+		t.Fatalf("UsersClient.List(): unexpected onPremisesExtensionAttributes: %#v", user.OnPremisesExtensionAttributes)
+	}
+	if user.OnPremisesExtensionAttributes.ExtensionAttribute15 != nil {
+		t.Fatalf("UsersClient.List(): expected extensionAttribute15 to be nil")
+	}
+	if v, ok := user.AdditionalData[extensionName]; !ok || v != "HR-A" {
+		t.Fatalf("UsersClient.List(): expected %s in AdditionalData, got %#v", extensionName, user.AdditionalData)
+	}
+
+	body, err := json.Marshal(msgraph.User{AdditionalData: map[string]interface{}{extensionName: "HR-B"}})
+	if err != nil {
+		t.Fatalf("json.Marshal(): %v", err)
+	}
+	if expected := fmt.Sprintf(`{%q:"HR-B"}`, extensionName); string(body) != expected {
+		t.Fatalf("json.Marshal(): expected %s, got %s", expected, body)
+	}
+}
+
+func TestUsersClient_NoManager(t *testing.T) {
+	var requests int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusNotFound)
+		fmt.Fprint(w, `{"error":{"code":"Request_ResourceNotFound","message":"Resource 'manager' does not exist or one of its queried reference-property objects are not present."}}`)
+	}))
+	defer server.Close()
+
+	c := msgraph.NewUsersClient("00000000-0000-0000-0000-000000000000")
+	c.BaseClient.Endpoint = environments.ApiEndpoint(server.URL)
+
+	_, status, err := c.GetManager(context.Background(), "11111111-1111-1111-1111-111111111111", odata.Query{})
+	if _, ok := err.(errors.NoManagerError); !ok {
+		t.Fatalf("UsersClient.GetManager(): expected NoManagerError, got: %v", err)
+	}
+	if status != http.StatusNotFound {
+		t.Fatalf("UsersClient.GetManager(): expected status 404, got %d", status)
+	}
+	if requests != 1 {
+		t.Fatalf("UsersClient.GetManager(): expected no retries, got %d requests", requests)
+	}
+
+	if _, err := c.RemoveManager(context.Background(), "11111111-1111-1111-1111-111111111111"); err == nil {
+		t.Fatal("UsersClient.RemoveManager(): expected error")
+	} else if _, ok := err.(errors.NoManagerError); !ok {
+		t.Fatalf("UsersClient.RemoveManager(): expected NoManagerError, got: %v", err)
+	}
+}
+
+func TestUsersClient_CreatePasswordProfile(t *testing.T) {
+	var received map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := json.NewDecoder(r.Body).Decode(&received); err != nil {
+			t.Errorf("could not decode request body: %v", err)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		fmt.Fprint(w, `{"id":"11111111-1111-1111-1111-111111111111"}`)
+	}))
+	defer server.Close()
+
+	c := msgraph.NewUsersClient("00000000-0000-0000-0000-000000000000")
+	c.BaseClient.Endpoint = environments.ApiEndpoint(server.URL)
+
+	if _, _, err := c.Create(context.Background(), msgraph.User{
+		AccountEnabled: utils.BoolPtr(true),
+		DisplayName:    utils.StringPtr("test-user"),
+	}); err == nil {
+		t.Fatal("UsersClient.Create(): expected error when creating an enabled account without a password")
+	}
+	if received != nil {
+		t.Fatal("UsersClient.Create(): expected no request to be sent without a password")
+	}
+
+	if _, _, err := c.Create(context.Background(), msgraph.User{
+		AccountEnabled: utils.BoolPtr(true),
+		DisplayName:    utils.StringPtr("test-user"),
+		PasswordProfile: &msgraph.UserPasswordProfile{
+			ForceChangePasswordNextSignIn:        utils.BoolPtr(true),
+			ForceChangePasswordNextSignInWithMfa: utils.BoolPtr(false),
+			Password:                             utils.StringPtr("IrPa55w0rd"),
+		},
+	}); err != nil {
+		t.Fatalf("UsersClient.Create(): %v", err)
+	}
+
+	expected := map[string]interface{}{
+		"forceChangePasswordNextSignIn":        true,
+		"forceChangePasswordNextSignInWithMfa": false,
+		"password":                             "IrPa55w0rd",
+	}
+	if profile, ok := received["passwordProfile"].(map[string]interface{}); !ok || !reflect.DeepEqual(profile, expected) {
+		t.Fatalf("UsersClient.Create(): expected passwordProfile %v, got %v", expected, received["passwordProfile"])
+	}
+}
+
+func TestUsersClient_CreateUsageLocation(t *testing.T) {
+	var received map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := json.NewDecoder(r.Body).Decode(&received); err != nil {
+			t.Errorf("could not decode request body: %v", err)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		fmt.Fprint(w, `{"id":"11111111-1111-1111-1111-111111111111","accountEnabled":true,"usageLocation":"GB"}`)
+	}))
+	defer server.Close()
+
+	c := msgraph.NewUsersClient("00000000-0000-0000-0000-000000000000")
+	c.BaseClient.Endpoint = environments.ApiEndpoint(server.URL)
+
+	user := msgraph.User{
+		AccountEnabled: utils.BoolPtr(true),
+		DisplayName:    utils.StringPtr("test-user"),
+		PasswordProfile: &msgraph.UserPasswordProfile{
+			Password: utils.StringPtr("IrPa55w0rd"),
+		},
+	}
+
+	for _, location := range []string{"GBR", "G", "G1", "United Kingdom"} {
+		usageLocation := msgraph.StringNullWhenEmpty(location)
+		user.UsageLocation = &usageLocation
+		if _, _, err := c.Create(context.Background(), user); err == nil || !strings.Contains(err.Error(), "ISO 3166-1 alpha-2") {
+			t.Fatalf("UsersClient.Create(): expected usageLocation error for %q, got: %v", location, err)
+		}
+	}
+	if received != nil {
+		t.Fatal("UsersClient.Create(): expected no request to be sent with an invalid usageLocation")
+	}
+
+	usageLocation := msgraph.StringNullWhenEmpty("GB")
+	user.UsageLocation = &usageLocation
+	newUser, _, err := c.Create(context.Background(), user)
+	if err != nil {
+		t.Fatalf("UsersClient.Create(): %v", err)
+	}
+	if received["accountEnabled"] != true || received["usageLocation"] != "GB" {
+		t.Fatalf("UsersClient.Create(): expected accountEnabled and usageLocation in request, got %v", received)
+	}
+	if newUser.UsageLocation == nil || *newUser.UsageLocation != "GB" {
+		t.Fatalf("UsersClient.Create(): expected usageLocation in response, got %v", newUser.UsageLocation)
+	}
+}
+
+type testClaimsAuthorizer struct {
+	claims string
+}
+
+func (a testClaimsAuthorizer) Token() (*oauth2.Token, error) {
+	return &oauth2.Token{
+		AccessToken: fmt.Sprintf("e30.%s.c2ln", base64.RawURLEncoding.EncodeToString([]byte(a.claims))),
+		TokenType:   "Bearer",
+		Expiry:      time.Now().Add(time.Hour),
+	}, nil
+}
+
+func TestUsersClient_UpdateAndReturn(t *testing.T) {
+	representation := true
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPatch || r.URL.Path != "/beta/00000000-0000-0000-0000-000000000000/users/user1" {
+			t.Errorf("unexpected request: %s %s", r.Method, r.URL.Path)
+		}
+		if prefer := r.Header.Get("Prefer"); prefer != "return=representation" {
+			t.Errorf("expected Prefer header %q, got %q", "return=representation", prefer)
+		}
+		if !representation {
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"id":"user1","displayName":"Updated User","userPrincipalName":"user1@example.com"}`)
+	}))
+	defer server.Close()
+
+	c := msgraph.NewUsersClient("00000000-0000-0000-0000-000000000000")
+	c.BaseClient.Endpoint = environments.ApiEndpoint(server.URL)
+
+	input := msgraph.User{DisplayName: utils.StringPtr("Updated User")}
+	input.ID = utils.StringPtr("user1")
+
+	user, status, err := c.UpdateAndReturn(context.Background(), input)
+	if err != nil {
+		t.Fatalf("UsersClient.UpdateAndReturn(): %v", err)
+	}
+	if status != http.StatusOK || user.UserPrincipalName == nil || *user.UserPrincipalName != "user1@example.com" {
+		t.Fatalf("UsersClient.UpdateAndReturn(): expected returned representation, got status %d and %+v", status, user)
+	}
+
+	representation = false
+	user, status, err = c.UpdateAndReturn(context.Background(), input)
+	if err != nil {
+		t.Fatalf("UsersClient.UpdateAndReturn(): %v", err)
+	}
+	if status != http.StatusNoContent || user.ID == nil || *user.ID != "user1" || user.UserPrincipalName != nil {
+		t.Fatalf("UsersClient.UpdateAndReturn(): expected input to be returned for no content, got status %d and %+v", status, user)
+	}
+
+	if _, _, err := c.UpdateAndReturn(context.Background(), msgraph.User{DisplayName: utils.StringPtr("Updated User")}); err == nil {
+		t.Fatal("UsersClient.UpdateAndReturn(): expected error for user with nil ID")
+	}
+}
+
+func TestUsersClient_GetMe(t *testing.T) {
+	var paths []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		paths = append(paths, r.URL.Path)
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/beta/me":
+			fmt.Fprint(w, `{"id":"11111111-1111-1111-1111-111111111111","displayName":"Test User"}`)
+		case "/beta/me/memberOf":
+			fmt.Fprint(w, `{"value":[{"@odata.type":"#microsoft.graph.group","id":"22222222-2222-2222-2222-222222222222"}]}`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	c := msgraph.NewUsersClient("00000000-0000-0000-0000-000000000000")
+	c.BaseClient.Endpoint = environments.ApiEndpoint(server.URL)
+
+	c.BaseClient.Authorizer = testClaimsAuthorizer{claims: `{"oid":"11111111-1111-1111-1111-111111111111","scp":"User.Read"}`}
+	user, _, err := c.GetMe(context.Background(), odata.Query{})
+	if err != nil {
+		t.Fatalf("UsersClient.GetMe(): %v", err)
+	}
+	if user.ID == nil || *user.ID != "11111111-1111-1111-1111-111111111111" {
+		t.Fatalf("UsersClient.GetMe(): unexpected user: %v", user.ID)
+	}
+	memberships, _, err := c.ListMyMemberOf(context.Background(), odata.Query{})
+	if err != nil {
+		t.Fatalf("UsersClient.ListMyMemberOf(): %v", err)
+	}
+	if len(*memberships) != 1 {
+		t.Fatalf("UsersClient.ListMyMemberOf(): expected 1 membership, got %d", len(*memberships))
+	}
+	if _, ok := (*memberships)[0].(msgraph.Group); !ok {
+		t.Fatalf("UsersClient.ListMyMemberOf(): expected Group, got %T", (*memberships)[0])
+	}
+
+	paths = nil
+	c.BaseClient.Authorizer = testClaimsAuthorizer{claims: `{"oid":"33333333-3333-3333-3333-333333333333","idtyp":"app","roles":["User.Read.All"]}`}
+	if _, _, err := c.GetMe(context.Background(), odata.Query{}); err == nil {
+		t.Fatal("UsersClient.GetMe(): expected error for app-only token")
+	} else if _, ok := err.(errors.DelegatedAuthRequiredError); !ok {
+		t.Fatalf("UsersClient.GetMe(): expected DelegatedAuthRequiredError, got: %v", err)
+	}
+	if _, _, err := c.ListMyOwnedObjects(context.Background(), odata.Query{}); err == nil {
+		t.Fatal("UsersClient.ListMyOwnedObjects(): expected error for app-only token")
+	} else if _, ok := err.(errors.DelegatedAuthRequiredError); !ok {
+		t.Fatalf("UsersClient.ListMyOwnedObjects(): expected DelegatedAuthRequiredError, got: %v", err)
+	}
+	if len(paths) > 0 {
+		t.Fatalf("UsersClient.GetMe(): expected no requests with an app-only token, got %v", paths)
+	}
+}
+
+func TestUsersClient_AdditionalDataPrecision(t *testing.T) {
+	var user msgraph.User
+	if err := json.Unmarshal([]byte(`{"id":"11111111-1111-1111-1111-111111111111","extension_abc_employeeNumber":9007199254740993}`), &user); err != nil {
+		t.Fatalf("json.Unmarshal(): %v", err)
+	}
+	v, ok := user.AdditionalData["extension_abc_employeeNumber"].(json.Number)
+	if !ok {
+		t.Fatalf("expected json.Number, got %T", user.AdditionalData["extension_abc_employeeNumber"])
+	}
+	if v.String() != "9007199254740993" {
+		t.Fatalf("expected 9007199254740993, got %s", v)
+	}
+
+	out, err := json.Marshal(user)
+	if err != nil {
+		t.Fatalf("json.Marshal(): %v", err)
+	}
+	if !strings.Contains(string(out), `"extension_abc_employeeNumber":9007199254740993`) {
+		t.Fatalf("expected full precision to be retained when marshaling, got %s", out)
+	}
+}
+
+func TestUsersClient_ListByIds(t *testing.T) {
+	idPattern := regexp.MustCompile(`'([^']+)'`)
+	var mutex sync.Mutex
+	var filters []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		filter := r.URL.Query().Get("$filter")
+		mutex.Lock()
+		filters = append(filters, filter)
+		mutex.Unlock()
+
+		var users []string
+		for _, m := range idPattern.FindAllStringSubmatch(filter, -1) {
+			// pretend that user9 does not exist, and return user0 with every response
+			if m[1] != "user9" {
+				users = append(users, fmt.Sprintf(`{"id":%q}`, m[1]))
+			}
+		}
+		users = append(users, `{"id":"user0"}`)
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprintf(w, `{"value":[%s]}`, strings.Join(users, ","))
+	}))
+	defer server.Close()
+
+	c := msgraph.NewUsersClient("00000000-0000-0000-0000-000000000000")
+	c.BaseClient.Endpoint = environments.ApiEndpoint(server.URL)
+
+	var ids []string
+	for i := 1; i <= 40; i++ {
+		ids = append(ids, fmt.Sprintf("user%d", i))
+	}
+	ids = append(ids, "user1", "user2")
+
+	users, _, err := c.ListByIds(context.Background(), ids, odata.Query{Filter: "accountEnabled eq true"}, 3)
+	if err != nil {
+		t.Fatalf("UsersClient.ListByIds(): %v", err)
+	}
+	if users == nil {
+		t.Fatal("UsersClient.ListByIds(): users was nil")
+	}
+	if len(filters) != 3 {
+		t.Fatalf("UsersClient.ListByIds(): expected 3 requests for 40 unique IDs, got %d", len(filters))
+	}
+	for _, f := range filters {
+		if !strings.HasPrefix(f, "id in (") || !strings.HasSuffix(f, " and (accountEnabled eq true)") {
+			t.Errorf("UsersClient.ListByIds(): unexpected filter %q", f)
+		}
+	}
+
+	got := make(map[string]bool)
+	for _, u := range *users {
+		if got[*u.ID] {
+			t.Errorf("UsersClient.ListByIds(): duplicate user %q in result", *u.ID)
+		}
+		got[*u.ID] = true
+	}
+	if len(*users) != 40 || got["user9"] || !got["user0"] || !got["user40"] {
+		t.Fatalf("UsersClient.ListByIds(): unexpected users in result: %v", got)
+	}
+}
+
+func TestUsersClient_ListN(t *testing.T) {
+	var requests []string
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.URL.RawQuery)
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Query().Get("$skiptoken") {
+		case "":
+			fmt.Fprintf(w, `{"@odata.nextLink":"%s/beta/00000000-0000-0000-0000-000000000000/users?$skiptoken=page2","value":[{"id":"user1"},{"id":"user2"}]}`, server.URL)
+		case "page2":
+			fmt.Fprintf(w, `{"@odata.nextLink":"%s/beta/00000000-0000-0000-0000-000000000000/users?$skiptoken=page3","value":[{"id":"user3"},{"id":"user4"}]}`, server.URL)
+		default:
+			fmt.Fprint(w, `{"value":[{"id":"user5"}]}`)
+		}
+	}))
+	defer server.Close()
+
+	c := msgraph.NewUsersClient("00000000-0000-0000-0000-000000000000")
+	c.BaseClient.Endpoint = environments.ApiEndpoint(server.URL)
+
+	users, _, err := c.ListN(context.Background(), odata.Query{Top: 2}, 3)
+	if err != nil {
+		t.Fatalf("UsersClient.ListN(): %v", err)
+	}
+	var ids []string
+	for _, u := range *users {
+		ids = append(ids, *u.ID)
+	}
+	if !reflect.DeepEqual(ids, []string{"user1", "user2", "user3"}) {
+		t.Fatalf("UsersClient.ListN(): expected first 3 users, got %v", ids)
+	}
+	if len(requests) != 2 || requests[0] != "%24top=2" {
+		t.Fatalf("UsersClient.ListN(): expected 2 requests with a page size of 2, got %v", requests)
+	}
+
+	requests = nil
+	if _, _, err := c.ListN(context.Background(), odata.Query{}, 10); err != nil {
+		t.Fatalf("UsersClient.ListN(): %v", err)
+	}
+	if len(requests) != 3 || requests[0] != "%24top=10" {
+		t.Fatalf("UsersClient.ListN(): expected 3 requests with a page size of 10, got %v", requests)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, _, err := c.ListN(ctx, odata.Query{}, 10); err == nil {
+		t.Fatal("UsersClient.ListN(): expected an error with a cancelled context")
+	}
+	if _, _, err := c.ListN(context.Background(), odata.Query{}, 0); err == nil {
+		t.Fatal("UsersClient.ListN(): expected an error when n is zero")
+	}
+}
+
+func TestUsersClient_ListEach(t *testing.T) {
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Query().Get("$skiptoken") == "" {
+			fmt.Fprintf(w, `{"@odata.nextLink":"%s/beta/00000000-0000-0000-0000-000000000000/users?$skiptoken=page2","value":[{"id":"user1"},{"id":"user2"}]}`, server.URL)
+			return
+		}
+		fmt.Fprint(w, `{"value":[{"id":"user3"}]}`)
+	}))
+	defer server.Close()
+
+	c := msgraph.NewUsersClient("00000000-0000-0000-0000-000000000000")
+	c.BaseClient.Endpoint = environments.ApiEndpoint(server.URL)
+
+	var ids []string
+	if _, err := c.ListEach(context.Background(), odata.Query{}, func(u msgraph.User) error {
+		ids = append(ids, *u.ID)
+		return nil
+	}); err != nil {
+		t.Fatalf("UsersClient.ListEach(): %v", err)
+	}
+	if !reflect.DeepEqual(ids, []string{"user1", "user2", "user3"}) {
+		t.Fatalf("UsersClient.ListEach(): expected all users across pages, got %v", ids)
+	}
+
+	ids = nil
+	if _, err := c.ListEach(context.Background(), odata.Query{}, func(u msgraph.User) error {
+		ids = append(ids, *u.ID)
+		if len(ids) == 2 {
+			return msgraph.ErrStopIteration
+		}
+		return nil
+	}); err != nil {
+		t.Fatalf("UsersClient.ListEach(): expected no error when stopping iteration, got %v", err)
+	}
+	if len(ids) != 2 {
+		t.Fatalf("UsersClient.ListEach(): expected iteration to stop after 2 users, got %v", ids)
+	}
+
+	callbackErr := fmt.Errorf("processing failed")
+	if _, err := c.ListEach(context.Background(), odata.Query{}, func(u msgraph.User) error {
+		return callbackErr
+	}); err != callbackErr {
+		t.Fatalf("UsersClient.ListEach(): expected callback error to be returned, got %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	ids = nil
+	_, err := c.ListEach(ctx, odata.Query{}, func(u msgraph.User) error {
+		ids = append(ids, *u.ID)
+		cancel()
+		return nil
+	})
+	if err == nil || !strings.Contains(err.Error(), context.Canceled.Error()) {
+		t.Fatalf("UsersClient.ListEach(): expected context cancellation error, got %v", err)
+	}
+	if len(ids) != 1 {
+		t.Fatalf("UsersClient.ListEach(): expected iteration to stop after cancellation, got %v", ids)
+	}
+}
+
+func TestUsersClient_GetPhoto(t *testing.T) {
+	const etag = `"BA09D118"`
+	photo := []byte{0xff, 0xd8, 0xff, 0xe0}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/beta/00000000-0000-0000-0000-000000000000/users/11111111-1111-1111-1111-111111111111/photo":
+			w.Header().Set("Content-Type", "application/json")
+			fmt.Fprintf(w, `{"@odata.mediaEtag":%q,"id":"default","height":96,"width":96}`, etag)
+		case "/beta/00000000-0000-0000-0000-000000000000/users/11111111-1111-1111-1111-111111111111/photo/$value":
+			if r.Header.Get("If-None-Match") == etag {
+				w.WriteHeader(http.StatusNotModified)
+				return
+			}
+			w.Header().Set("Content-Type", "image/jpeg")
+			w.Header().Set("ETag", etag)
+			w.Write(photo)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	c := msgraph.NewUsersClient("00000000-0000-0000-0000-000000000000")
+	c.BaseClient.Endpoint = environments.ApiEndpoint(server.URL)
+
+	metadata, _, err := c.GetPhotoMetadata(context.Background(), "11111111-1111-1111-1111-111111111111")
+	if err != nil {
+		t.Fatalf("UsersClient.GetPhotoMetadata(): %v", err)
+	}
+	if metadata.MediaEtag == nil || *metadata.MediaEtag != etag {
+		t.Fatalf("UsersClient.GetPhotoMetadata(): expected ETag %q, got %v", etag, metadata.MediaEtag)
+	}
+
+	content, contentType, _, err := c.GetPhoto(context.Background(), "11111111-1111-1111-1111-111111111111", "")
+	if err != nil {
+		t.Fatalf("UsersClient.GetPhoto(): %v", err)
+	}
+	if !reflect.DeepEqual(content, photo) || contentType != "image/jpeg" {
+		t.Fatalf("UsersClient.GetPhoto(): expected photo, got %v (%s)", content, contentType)
+	}
+
+	content, _, status, err := c.GetPhoto(context.Background(), "11111111-1111-1111-1111-111111111111", *metadata.MediaEtag)
+	if err != msgraph.ErrNotModified {
+		t.Fatalf("UsersClient.GetPhoto(): expected ErrNotModified, got %v", err)
+	}
+	if status != http.StatusNotModified || content != nil {
+		t.Fatalf("UsersClient.GetPhoto(): expected status 304 and no content, got %d and %v", status, content)
+	}
+
+	if _, _, _, err := c.GetPhoto(context.Background(), "11111111-1111-1111-1111-111111111111", `"stale"`); err != nil {
+		t.Fatalf("UsersClient.GetPhoto(): expected changed photo to be retrieved, got %v", err)
+	}
+}
@@ -1,12 +1,15 @@
 package msgraph
 
-import "encoding/json"
+import (
+	"bytes"
+	"encoding/json"
+)
 
 func MarshalDocs(docs [][]byte) ([]byte, error) {
 	out := make(map[string]interface{})
 	for _, d := range docs {
 		var o map[string]interface{}
-		err := json.Unmarshal(d, &o)
+		err := unmarshalUseNumber(d, &o)
 		if err != nil {
 			return d, err
 		}
@@ -16,3 +19,11 @@ func MarshalDocs(docs [][]byte) ([]byte, error) {
 	}
 	return json.Marshal(out)
 }
+
+// unmarshalUseNumber unmarshals data into v, decoding numbers held in interface{} values as json.Number rather than
+// float64, so that large integers such as counts and IDs retain their full precision
+func unmarshalUseNumber(data []byte, v interface{}) error {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	return dec.Decode(v)
+}
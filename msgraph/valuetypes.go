@@ -19,6 +19,29 @@ func (s StringNullWhenEmpty) MarshalJSON() ([]byte, error) {
 	return json.Marshal(string(s))
 }
 
+type AccessPackageCatalogStatus = string
+
+const (
+	AccessPackageCatalogStatusPublished   AccessPackageCatalogStatus = "Published"
+	AccessPackageCatalogStatusUnpublished AccessPackageCatalogStatus = "Unpublished"
+)
+
+type AccessPackageCatalogType = string
+
+const (
+	AccessPackageCatalogTypeServiceDefault AccessPackageCatalogType = "ServiceDefault"
+	AccessPackageCatalogTypeUserManaged    AccessPackageCatalogType = "UserManaged"
+)
+
+type AccessReviewDefaultDecision = string
+
+const (
+	AccessReviewDefaultDecisionApprove        AccessReviewDefaultDecision = "Approve"
+	AccessReviewDefaultDecisionDeny           AccessReviewDefaultDecision = "Deny"
+	AccessReviewDefaultDecisionNone           AccessReviewDefaultDecision = "None"
+	AccessReviewDefaultDecisionRecommendation AccessReviewDefaultDecision = "Recommendation"
+)
+
 type AgeGroup = StringNullWhenEmpty
 
 const (
@@ -28,6 +51,22 @@ const (
 	AgeGroupNotAdult AgeGroup = "NotAdult"
 )
 
+type AppCredentialRestriction = string
+
+const (
+	AppCredentialRestrictionCustomPasswordAddition AppCredentialRestriction = "customPasswordAddition"
+	AppCredentialRestrictionPasswordAddition       AppCredentialRestriction = "passwordAddition"
+	AppCredentialRestrictionPasswordLifetime       AppCredentialRestriction = "passwordLifetime"
+	AppCredentialRestrictionSymmetricKeyAddition   AppCredentialRestriction = "symmetricKeyAddition"
+	AppCredentialRestrictionSymmetricKeyLifetime   AppCredentialRestriction = "symmetricKeyLifetime"
+)
+
+type AppKeyCredentialRestriction = string
+
+const (
+	AppKeyCredentialRestrictionAsymmetricKeyLifetime AppKeyCredentialRestriction = "asymmetricKeyLifetime"
+)
+
 type ApplicationExtensionDataType = string
 
 const (
@@ -85,6 +124,14 @@ const (
 	AppRoleAllowedMemberTypeUser        AppRoleAllowedMemberType = "User"
 )
 
+type ApprovalMode = string
+
+const (
+	ApprovalModeNoApproval  ApprovalMode = "NoApproval"
+	ApprovalModeSerial      ApprovalMode = "Serial"
+	ApprovalModeSingleStage ApprovalMode = "SingleStage"
+)
+
 type AttestationLevel = string
 
 const (
@@ -110,6 +157,20 @@ const (
 	AuthenticationMethodKeyStrengthUnknown AuthenticationMethodKeyStrength = "unknown"
 )
 
+type AuthenticationMethodState = string
+
+const (
+	AuthenticationMethodStateDisabled AuthenticationMethodState = "disabled"
+	AuthenticationMethodStateEnabled  AuthenticationMethodState = "enabled"
+)
+
+type AuthenticationMethodTargetType = string
+
+const (
+	AuthenticationMethodTargetTypeGroup AuthenticationMethodTargetType = "group"
+	AuthenticationMethodTargetTypeUser  AuthenticationMethodTargetType = "user"
+)
+
 type AuthenticationPhoneType = string
 
 const (
@@ -118,6 +179,17 @@ const (
 	AuthenticationPhoneTypeOffice          AuthenticationPhoneType = "office"
 )
 
+type BrandingImage = string
+
+const (
+	BrandingImageBackground     BrandingImage = "backgroundImage"
+	BrandingImageBannerLogo     BrandingImage = "bannerLogo"
+	BrandingImageFavicon        BrandingImage = "favicon"
+	BrandingImageHeaderLogo     BrandingImage = "headerLogo"
+	BrandingImageSquareLogo     BrandingImage = "squareLogo"
+	BrandingImageSquareLogoDark BrandingImage = "squareLogoDark"
+)
+
 type BodyType = string
 
 const (
@@ -134,6 +206,13 @@ const (
 	ConsentProvidedForMinorNotRequired ConsentProvidedForMinor = "NotRequired"
 )
 
+type CredentialStatusType = string
+
+const (
+	CredentialStatusTypeCertificate CredentialStatusType = "Certificate"
+	CredentialStatusTypePassword    CredentialStatusType = "Password"
+)
+
 type CredentialUsageSummaryPeriod = string
 
 const (
@@ -150,6 +229,37 @@ const (
 	ConditionalAccessPolicyStateEnabledForReportingButNotEnforced ConditionalAccessPolicyState = "enabledForReportingButNotEnforced"
 )
 
+type DelegatedAdminRelationshipStatus = string
+
+const (
+	DelegatedAdminRelationshipStatusActivating           DelegatedAdminRelationshipStatus = "activating"
+	DelegatedAdminRelationshipStatusActive               DelegatedAdminRelationshipStatus = "active"
+	DelegatedAdminRelationshipStatusApprovalPending      DelegatedAdminRelationshipStatus = "approvalPending"
+	DelegatedAdminRelationshipStatusApproved             DelegatedAdminRelationshipStatus = "approved"
+	DelegatedAdminRelationshipStatusCreated              DelegatedAdminRelationshipStatus = "created"
+	DelegatedAdminRelationshipStatusExpired              DelegatedAdminRelationshipStatus = "expired"
+	DelegatedAdminRelationshipStatusExpiring             DelegatedAdminRelationshipStatus = "expiring"
+	DelegatedAdminRelationshipStatusTerminated           DelegatedAdminRelationshipStatus = "terminated"
+	DelegatedAdminRelationshipStatusTerminating          DelegatedAdminRelationshipStatus = "terminating"
+	DelegatedAdminRelationshipStatusTerminationRequested DelegatedAdminRelationshipStatus = "terminationRequested"
+)
+
+type EducationExternalSource = string
+
+const (
+	EducationExternalSourceManual EducationExternalSource = "manual"
+	EducationExternalSourceSis    EducationExternalSource = "sis"
+)
+
+type EducationUserRole = string
+
+const (
+	EducationUserRoleFaculty EducationUserRole = "faculty"
+	EducationUserRoleNone    EducationUserRole = "none"
+	EducationUserRoleStudent EducationUserRole = "student"
+	EducationUserRoleTeacher EducationUserRole = "teacher"
+)
+
 type ExtensionSchemaTargetType = string
 
 const (
@@ -255,6 +365,41 @@ const (
 	KeyCredentialUsageVerify KeyCredentialUsage = "Verify"
 )
 
+type ManagedDeviceComplianceState = string
+
+const (
+	ManagedDeviceComplianceStateCompliant     ManagedDeviceComplianceState = "compliant"
+	ManagedDeviceComplianceStateConfigManager ManagedDeviceComplianceState = "configManager"
+	ManagedDeviceComplianceStateConflict      ManagedDeviceComplianceState = "conflict"
+	ManagedDeviceComplianceStateError         ManagedDeviceComplianceState = "error"
+	ManagedDeviceComplianceStateInGracePeriod ManagedDeviceComplianceState = "inGracePeriod"
+	ManagedDeviceComplianceStateNoncompliant  ManagedDeviceComplianceState = "noncompliant"
+	ManagedDeviceComplianceStateUnknown       ManagedDeviceComplianceState = "unknown"
+)
+
+type ManagedDeviceOwnerType = string
+
+const (
+	ManagedDeviceOwnerTypeCompany  ManagedDeviceOwnerType = "company"
+	ManagedDeviceOwnerTypePersonal ManagedDeviceOwnerType = "personal"
+	ManagedDeviceOwnerTypeUnknown  ManagedDeviceOwnerType = "unknown"
+)
+
+type MicrosoftAuthenticatorAuthenticationMode = string
+
+const (
+	MicrosoftAuthenticatorAuthenticationModeAny             MicrosoftAuthenticatorAuthenticationMode = "any"
+	MicrosoftAuthenticatorAuthenticationModeDeviceBasedPush MicrosoftAuthenticatorAuthenticationMode = "deviceBasedPush"
+	MicrosoftAuthenticatorAuthenticationModePush            MicrosoftAuthenticatorAuthenticationMode = "push"
+)
+
+type OAuth2PermissionGrantConsentType = string
+
+const (
+	OAuth2PermissionGrantConsentTypeAllPrincipals OAuth2PermissionGrantConsentType = "AllPrincipals"
+	OAuth2PermissionGrantConsentTypePrincipal     OAuth2PermissionGrantConsentType = "Principal"
+)
+
 type Members []DirectoryObject
 
 func (o Members) MarshalJSON() ([]byte, error) {
@@ -330,6 +475,59 @@ const (
 	PreferredSingleSignOnModeSaml         PreferredSingleSignOnMode = "saml"
 )
 
+type PresenceActivity = string
+
+const (
+	PresenceActivityAvailable               PresenceActivity = "Available"
+	PresenceActivityAway                    PresenceActivity = "Away"
+	PresenceActivityBeRightBack             PresenceActivity = "BeRightBack"
+	PresenceActivityBusy                    PresenceActivity = "Busy"
+	PresenceActivityDoNotDisturb            PresenceActivity = "DoNotDisturb"
+	PresenceActivityInACall                 PresenceActivity = "InACall"
+	PresenceActivityInAConferenceCall       PresenceActivity = "InAConferenceCall"
+	PresenceActivityInactive                PresenceActivity = "Inactive"
+	PresenceActivityInAMeeting              PresenceActivity = "InAMeeting"
+	PresenceActivityOffline                 PresenceActivity = "Offline"
+	PresenceActivityOffWork                 PresenceActivity = "OffWork"
+	PresenceActivityOutOfOffice             PresenceActivity = "OutOfOffice"
+	PresenceActivityPresenceUnknown         PresenceActivity = "PresenceUnknown"
+	PresenceActivityPresenting              PresenceActivity = "Presenting"
+	PresenceActivityUrgentInterruptionsOnly PresenceActivity = "UrgentInterruptionsOnly"
+)
+
+type PresenceAvailability = string
+
+const (
+	PresenceAvailabilityAvailable       PresenceAvailability = "Available"
+	PresenceAvailabilityAvailableIdle   PresenceAvailability = "AvailableIdle"
+	PresenceAvailabilityAway            PresenceAvailability = "Away"
+	PresenceAvailabilityBeRightBack     PresenceAvailability = "BeRightBack"
+	PresenceAvailabilityBusy            PresenceAvailability = "Busy"
+	PresenceAvailabilityBusyIdle        PresenceAvailability = "BusyIdle"
+	PresenceAvailabilityDoNotDisturb    PresenceAvailability = "DoNotDisturb"
+	PresenceAvailabilityOffline         PresenceAvailability = "Offline"
+	PresenceAvailabilityPresenceUnknown PresenceAvailability = "PresenceUnknown"
+)
+
+type RecurrencePatternType = string
+
+const (
+	RecurrencePatternTypeAbsoluteMonthly RecurrencePatternType = "absoluteMonthly"
+	RecurrencePatternTypeAbsoluteYearly  RecurrencePatternType = "absoluteYearly"
+	RecurrencePatternTypeDaily           RecurrencePatternType = "daily"
+	RecurrencePatternTypeRelativeMonthly RecurrencePatternType = "relativeMonthly"
+	RecurrencePatternTypeRelativeYearly  RecurrencePatternType = "relativeYearly"
+	RecurrencePatternTypeWeekly          RecurrencePatternType = "weekly"
+)
+
+type RecurrenceRangeType = string
+
+const (
+	RecurrenceRangeTypeEndDate  RecurrenceRangeType = "endDate"
+	RecurrenceRangeTypeNoEnd    RecurrenceRangeType = "noEnd"
+	RecurrenceRangeTypeNumbered RecurrenceRangeType = "numbered"
+)
+
 type RegistrationAuthMethod = string
 
 const (
@@ -354,6 +552,19 @@ const (
 	RegistrationStatusMfaRegistered RegistrationStatus = "mfaRegistered"
 )
 
+type RequestorSettingsScopeType = string
+
+const (
+	RequestorSettingsScopeTypeAllConfiguredConnectedOrganizationSubjects RequestorSettingsScopeType = "AllConfiguredConnectedOrganizationSubjects"
+	RequestorSettingsScopeTypeAllExistingConnectedOrganizationSubjects   RequestorSettingsScopeType = "AllExistingConnectedOrganizationSubjects"
+	RequestorSettingsScopeTypeAllExistingDirectoryMemberUsers            RequestorSettingsScopeType = "AllExistingDirectoryMemberUsers"
+	RequestorSettingsScopeTypeAllExistingDirectorySubjects               RequestorSettingsScopeType = "AllExistingDirectorySubjects"
+	RequestorSettingsScopeTypeAllExternalSubjects                        RequestorSettingsScopeType = "AllExternalSubjects"
+	RequestorSettingsScopeTypeNoSubjects                                 RequestorSettingsScopeType = "NoSubjects"
+	RequestorSettingsScopeTypeSpecificConnectedOrganizationSubjects      RequestorSettingsScopeType = "SpecificConnectedOrganizationSubjects"
+	RequestorSettingsScopeTypeSpecificDirectorySubjects                  RequestorSettingsScopeType = "SpecificDirectorySubjects"
+)
+
 type ResourceAccessType = string
 
 const (
@@ -361,6 +572,27 @@ const (
 	ResourceAccessTypeScope ResourceAccessType = "Scope"
 )
 
+type RiskLevel = string
+
+const (
+	RiskLevelHidden RiskLevel = "hidden"
+	RiskLevelHigh   RiskLevel = "high"
+	RiskLevelLow    RiskLevel = "low"
+	RiskLevelMedium RiskLevel = "medium"
+	RiskLevelNone   RiskLevel = "none"
+)
+
+type RiskState = string
+
+const (
+	RiskStateAtRisk               RiskState = "atRisk"
+	RiskStateConfirmedCompromised RiskState = "confirmedCompromised"
+	RiskStateConfirmedSafe        RiskState = "confirmedSafe"
+	RiskStateDismissed            RiskState = "dismissed"
+	RiskStateNone                 RiskState = "none"
+	RiskStateRemediated           RiskState = "remediated"
+)
+
 type SchemaExtensionStatus = string
 
 const (
@@ -378,7 +610,7 @@ type SchemaExtensionMap map[string]interface{}
 func (m *SchemaExtensionMap) UnmarshalJSON(data []byte) error {
 	type sem SchemaExtensionMap
 	m2 := (*sem)(m)
-	return json.Unmarshal(data, m2)
+	return unmarshalUseNumber(data, m2)
 }
 
 type SignInAudience = string
@@ -390,6 +622,42 @@ const (
 	SignInAudiencePersonalMicrosoftAccount           SignInAudience = "PersonalMicrosoftAccount"
 )
 
+type SynchronizationJobRestartScope = string
+
+const (
+	SynchronizationJobRestartScopeConnectorDataStore SynchronizationJobRestartScope = "ConnectorDataStore"
+	SynchronizationJobRestartScopeEscrows            SynchronizationJobRestartScope = "Escrows"
+	SynchronizationJobRestartScopeFull               SynchronizationJobRestartScope = "Full"
+	SynchronizationJobRestartScopeQuarantineState    SynchronizationJobRestartScope = "QuarantineState"
+	SynchronizationJobRestartScopeWatermark          SynchronizationJobRestartScope = "Watermark"
+)
+
+type SynchronizationScheduleState = string
+
+const (
+	SynchronizationScheduleStateActive   SynchronizationScheduleState = "Active"
+	SynchronizationScheduleStateDisabled SynchronizationScheduleState = "Disabled"
+	SynchronizationScheduleStatePaused   SynchronizationScheduleState = "Paused"
+)
+
+type SynchronizationStatusCode = string
+
+const (
+	SynchronizationStatusCodeActive        SynchronizationStatusCode = "Active"
+	SynchronizationStatusCodeNotConfigured SynchronizationStatusCode = "NotConfigured"
+	SynchronizationStatusCodeNotRun        SynchronizationStatusCode = "NotRun"
+	SynchronizationStatusCodePaused        SynchronizationStatusCode = "Paused"
+	SynchronizationStatusCodeQuarantine    SynchronizationStatusCode = "Quarantine"
+)
+
+type SynchronizationTaskExecutionResult = string
+
+const (
+	SynchronizationTaskExecutionResultEntryLevelErrors SynchronizationTaskExecutionResult = "EntryLevelErrors"
+	SynchronizationTaskExecutionResultFailed           SynchronizationTaskExecutionResult = "Failed"
+	SynchronizationTaskExecutionResultSucceeded        SynchronizationTaskExecutionResult = "Succeeded"
+)
+
 type UsageAuthMethod = string
 
 const (
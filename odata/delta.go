@@ -0,0 +1,112 @@
+package odata
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// DeltaState records the progress of an incremental sync using a delta function, such as `/users/delta`, so that it
+// can be persisted between sync jobs. Rather than storing the full deltaLink or nextLink returned by the API, only the
+// path of the delta function and the relevant token are kept.
+//
+// When a delta query completes, the state holds a DeltaToken from which the next sync should begin. When a delta
+// query is interrupted partway, the state can instead hold the SkipToken for the next page of the current sync.
+type DeltaState struct {
+	// Path is the path of the delta function, including the API version and any tenant ID, e.g. `/v1.0/users/delta`
+	Path string `json:"path"`
+
+	// DeltaToken identifies the point from which changes should be retrieved in the next sync
+	DeltaToken string `json:"deltaToken,omitempty"`
+
+	// SkipToken identifies the next page of an incomplete sync
+	SkipToken string `json:"skipToken,omitempty"`
+}
+
+// ParseDeltaLink returns the DeltaState for a deltaLink, or for the nextLink of a delta query which has not yet
+// completed. An error is returned when the link contains neither a $deltatoken nor a $skiptoken parameter.
+func ParseDeltaLink(link string) (*DeltaState, error) {
+	u, err := url.Parse(link)
+	if err != nil {
+		return nil, fmt.Errorf("parsing link: %v", err)
+	}
+	if u.Path == "" {
+		return nil, fmt.Errorf("link %q has no path", link)
+	}
+	state := DeltaState{
+		Path:       u.Path,
+		DeltaToken: u.Query().Get("$deltatoken"),
+		SkipToken:  u.Query().Get("$skiptoken"),
+	}
+	if err := state.Validate(); err != nil {
+		return nil, err
+	}
+	return &state, nil
+}
+
+// DeltaState returns the DeltaState for a page of a delta query, using the DeltaLink when present and otherwise the
+// NextLink. Returns nil when the response has neither.
+func (o OData) DeltaState() (*DeltaState, error) {
+	if o.DeltaLink != nil {
+		return ParseDeltaLink(*o.DeltaLink)
+	}
+	if o.NextLink != nil {
+		return ParseDeltaLink(*o.NextLink)
+	}
+	return nil, nil
+}
+
+// Validate returns an error if the state cannot be used to resume a delta query.
+func (s DeltaState) Validate() error {
+	if s.Path == "" {
+		return fmt.Errorf("delta state has no path")
+	}
+	if s.DeltaToken == "" && s.SkipToken == "" {
+		return fmt.Errorf("delta state for %q has neither a delta token nor a skip token", s.Path)
+	}
+	return nil
+}
+
+// Query returns a Query which resumes the delta query from this state, for use with the path of the delta function.
+func (s DeltaState) Query() Query {
+	return Query{
+		DeltaToken: s.DeltaToken,
+		SkipToken:  s.SkipToken,
+	}
+}
+
+// Link reconstructs the URL of the next delta request from this state, using the specified API endpoint, e.g.
+// `https://graph.microsoft.com`.
+func (s DeltaState) Link(endpoint string) (string, error) {
+	if err := s.Validate(); err != nil {
+		return "", err
+	}
+	u, err := url.Parse(strings.TrimRight(endpoint, "/") + s.Path)
+	if err != nil {
+		return "", fmt.Errorf("parsing endpoint: %v", err)
+	}
+	s.Query().AppendToURL(u)
+	return u.String(), nil
+}
+
+// Marshal encodes the state for persistence.
+func (s DeltaState) Marshal() ([]byte, error) {
+	if err := s.Validate(); err != nil {
+		return nil, err
+	}
+	return json.Marshal(s)
+}
+
+// Unmarshal decodes a state previously encoded with Marshal.
+func (s *DeltaState) Unmarshal(data []byte) error {
+	var state DeltaState
+	if err := json.Unmarshal(data, &state); err != nil {
+		return err
+	}
+	if err := state.Validate(); err != nil {
+		return err
+	}
+	*s = state
+	return nil
+}
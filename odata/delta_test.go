@@ -0,0 +1,91 @@
+package odata_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/manicminer/hamilton/internal/utils"
+	"github.com/manicminer/hamilton/odata"
+)
+
+func TestDeltaState_RoundTrip(t *testing.T) {
+	type testCase struct {
+		link     string
+		expected odata.DeltaState
+	}
+	testCases := []testCase{
+		{
+			link: "https://graph.microsoft.com/v1.0/users/delta?$deltatoken=oEcOySpF_hWYmTIUZBOIfPzcwisr_rPe8o9M54L45qEXQGmvQC6T2dbL-9O7nSU-njKhFiGlAZqewNAThmCVnNxqPu5gOBegrm1CaVZ-ZtFZ2tPOAO98OD9y0ao460",
+			expected: odata.DeltaState{
+				Path:       "/v1.0/users/delta",
+				DeltaToken: "oEcOySpF_hWYmTIUZBOIfPzcwisr_rPe8o9M54L45qEXQGmvQC6T2dbL-9O7nSU-njKhFiGlAZqewNAThmCVnNxqPu5gOBegrm1CaVZ-ZtFZ2tPOAO98OD9y0ao460",
+			},
+		},
+		{
+			link: "https://graph.microsoft.com/beta/00000000-0000-0000-0000-000000000000/groups/delta?$skiptoken=X%274453707402000100%27",
+			expected: odata.DeltaState{
+				Path:      "/beta/00000000-0000-0000-0000-000000000000/groups/delta",
+				SkipToken: "X'4453707402000100'",
+			},
+		},
+	}
+	for _, c := range testCases {
+		state, err := odata.ParseDeltaLink(c.link)
+		if err != nil {
+			t.Fatalf("ParseDeltaLink(): %v", err)
+		}
+		if !reflect.DeepEqual(*state, c.expected) {
+			t.Fatalf("ParseDeltaLink(): expected %#v, got %#v", c.expected, *state)
+		}
+
+		data, err := state.Marshal()
+		if err != nil {
+			t.Fatalf("DeltaState.Marshal(): %v", err)
+		}
+		var restored odata.DeltaState
+		if err := restored.Unmarshal(data); err != nil {
+			t.Fatalf("DeltaState.Unmarshal(): %v", err)
+		}
+		if !reflect.DeepEqual(restored, c.expected) {
+			t.Fatalf("DeltaState.Unmarshal(): expected %#v, got %#v", c.expected, restored)
+		}
+
+		link, err := restored.Link("https://graph.microsoft.com/")
+		if err != nil {
+			t.Fatalf("DeltaState.Link(): %v", err)
+		}
+		reparsed, err := odata.ParseDeltaLink(link)
+		if err != nil {
+			t.Fatalf("ParseDeltaLink(): %v", err)
+		}
+		if !reflect.DeepEqual(*reparsed, c.expected) {
+			t.Fatalf("DeltaState.Link(): expected link for %#v, got %q", c.expected, link)
+		}
+	}
+}
+
+func TestDeltaState_Invalid(t *testing.T) {
+	if _, err := odata.ParseDeltaLink("https://graph.microsoft.com/v1.0/users/delta"); err == nil {
+		t.Fatal("ParseDeltaLink(): expected error for link without a token")
+	}
+	var state odata.DeltaState
+	if err := state.Unmarshal([]byte(`{"path":"/v1.0/users/delta"}`)); err == nil {
+		t.Fatal("DeltaState.Unmarshal(): expected error for state without a token")
+	}
+	if _, err := (odata.DeltaState{DeltaToken: "abc"}).Marshal(); err == nil {
+		t.Fatal("DeltaState.Marshal(): expected error for state without a path")
+	}
+}
+
+func TestOData_DeltaToken(t *testing.T) {
+	o := odata.OData{DeltaLink: utils.StringPtr("https://graph.microsoft.com/v1.0/users/delta?$deltatoken=abc123")}
+	if token := o.DeltaToken(); token != "abc123" {
+		t.Fatalf("DeltaToken(): expected %q, got %q", "abc123", token)
+	}
+	if token := (odata.OData{}).DeltaToken(); token != "" {
+		t.Fatalf("DeltaToken(): expected empty token, got %q", token)
+	}
+	if v := (odata.Query{DeltaToken: "abc123"}).Values().Get("$deltatoken"); v != "abc123" {
+		t.Fatalf("Query.Values(): expected $deltatoken %q, got %q", "abc123", v)
+	}
+}
@@ -0,0 +1,189 @@
+package odata
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// filterPrecedence describes how tightly an expression binds, and is used to determine where parentheses are needed
+// when combining expressions.
+type filterPrecedence int
+
+const (
+	filterPrecedenceRaw filterPrecedence = iota
+	filterPrecedenceOr
+	filterPrecedenceAnd
+	filterPrecedencePrimary
+)
+
+// Filter is a $filter expression built from comparisons, functions and logical operators. Use Filter.String() to
+// obtain the expression for a Query, e.g.
+//
+//	Query{Filter: And(Not(StartsWith("displayName", "svc")), Or(Eq("accountEnabled", true), Eq("userType", "Guest"))).String()}
+//
+// which produces `not(startsWith(displayName,'svc')) and (accountEnabled eq true or userType eq 'Guest')`.
+// Expressions are parenthesized only where needed to preserve their grouping when combined.
+type Filter struct {
+	expr       string
+	precedence filterPrecedence
+}
+
+// String returns the filter expression.
+func (f Filter) String() string {
+	return f.expr
+}
+
+// IsEmpty returns true when the filter has no expression, in which case it is ignored by And and Or.
+func (f Filter) IsEmpty() bool {
+	return f.expr == ""
+}
+
+// RawFilter returns a Filter for a hand-written expression. Since its grouping is not known, it is parenthesized when
+// combined with other expressions.
+func RawFilter(expr string) Filter {
+	return Filter{expr: expr, precedence: filterPrecedenceRaw}
+}
+
+// Eq returns a filter expression matching objects where property equals value.
+func Eq(property string, value interface{}) Filter {
+	return compare(property, "eq", value)
+}
+
+// Ne returns a filter expression matching objects where property does not equal value.
+func Ne(property string, value interface{}) Filter {
+	return compare(property, "ne", value)
+}
+
+// Gt returns a filter expression matching objects where property is greater than value.
+func Gt(property string, value interface{}) Filter {
+	return compare(property, "gt", value)
+}
+
+// Ge returns a filter expression matching objects where property is greater than or equal to value.
+func Ge(property string, value interface{}) Filter {
+	return compare(property, "ge", value)
+}
+
+// Lt returns a filter expression matching objects where property is less than value.
+func Lt(property string, value interface{}) Filter {
+	return compare(property, "lt", value)
+}
+
+// Le returns a filter expression matching objects where property is less than or equal to value.
+func Le(property string, value interface{}) Filter {
+	return compare(property, "le", value)
+}
+
+// StartsWith returns a filter expression matching objects where the string property starts with value.
+func StartsWith(property, value string) Filter {
+	return Filter{expr: fmt.Sprintf("startsWith(%s,%s)", property, FilterValue(value)), precedence: filterPrecedencePrimary}
+}
+
+// In returns a filter expression matching objects where property equals any of the specified values.
+func In(property string, values ...interface{}) Filter {
+	literals := make([]string, 0, len(values))
+	for _, v := range values {
+		literals = append(literals, FilterValue(v))
+	}
+	return Filter{expr: fmt.Sprintf("%s in (%s)", property, strings.Join(literals, ",")), precedence: filterPrecedencePrimary}
+}
+
+// InChunks returns one or more `in` filter expressions which together match all the specified values, omitting any
+// duplicate values. Each expression contains at most maxValues values, and its URL encoded length does not exceed
+// maxLength, so that the limits imposed by the API on the number of values and on the URL length can be respected. A
+// value of zero for either limit means no limit.
+func InChunks(property string, values []string, maxValues, maxLength int) []Filter {
+	seen := make(map[string]bool, len(values))
+	ret := make([]Filter, 0)
+	var chunk []interface{}
+	for _, v := range values {
+		if seen[v] {
+			continue
+		}
+		seen[v] = true
+
+		if len(chunk) > 0 {
+			exceedsValues := maxValues > 0 && len(chunk) >= maxValues
+			exceedsLength := maxLength > 0 && len(url.QueryEscape(In(property, append(chunk, v)...).String())) > maxLength
+			if exceedsValues || exceedsLength {
+				ret = append(ret, In(property, chunk...))
+				chunk = nil
+			}
+		}
+		chunk = append(chunk, v)
+	}
+	if len(chunk) > 0 {
+		ret = append(ret, In(property, chunk...))
+	}
+	return ret
+}
+
+// Not returns a filter expression negating f.
+func Not(f Filter) Filter {
+	if f.IsEmpty() {
+		return f
+	}
+	return Filter{expr: fmt.Sprintf("not(%s)", f.expr), precedence: filterPrecedencePrimary}
+}
+
+// Group returns f enclosed in parentheses.
+func Group(f Filter) Filter {
+	if f.IsEmpty() {
+		return f
+	}
+	return Filter{expr: fmt.Sprintf("(%s)", f.expr), precedence: filterPrecedencePrimary}
+}
+
+// And returns a filter expression matching objects which match all the specified filters. Empty filters are ignored.
+func And(filters ...Filter) Filter {
+	return join("and", filterPrecedenceAnd, filters)
+}
+
+// Or returns a filter expression matching objects which match any of the specified filters. Empty filters are ignored.
+func Or(filters ...Filter) Filter {
+	return join("or", filterPrecedenceOr, filters)
+}
+
+// FilterValue formats a value as a literal for use in a filter expression. Strings are quoted and have any single
+// quotes escaped, and times are formatted as RFC 3339 date-times in UTC.
+func FilterValue(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return "null"
+	case string:
+		return fmt.Sprintf("'%s'", strings.ReplaceAll(v, "'", "''"))
+	case time.Time:
+		return v.UTC().Format(time.RFC3339)
+	case *time.Time:
+		if v == nil {
+			return "null"
+		}
+		return v.UTC().Format(time.RFC3339)
+	}
+	return fmt.Sprintf("%v", value)
+}
+
+func compare(property, operator string, value interface{}) Filter {
+	return Filter{expr: fmt.Sprintf("%s %s %s", property, operator, FilterValue(value)), precedence: filterPrecedencePrimary}
+}
+
+func join(operator string, precedence filterPrecedence, filters []Filter) Filter {
+	var clauses []string
+	var last Filter
+	for _, f := range filters {
+		if f.IsEmpty() {
+			continue
+		}
+		last = f
+		if f.precedence < precedence {
+			f = Group(f)
+		}
+		clauses = append(clauses, f.expr)
+	}
+	if len(clauses) <= 1 {
+		return last
+	}
+	return Filter{expr: strings.Join(clauses, fmt.Sprintf(" %s ", operator)), precedence: precedence}
+}
@@ -0,0 +1,86 @@
+package odata_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/manicminer/hamilton/odata"
+)
+
+func TestFilter(t *testing.T) {
+	for n, c := range []struct {
+		filter   odata.Filter
+		expected string
+	}{
+		{
+			filter:   odata.And(odata.Not(odata.StartsWith("displayName", "svc")), odata.Or(odata.Eq("accountEnabled", true), odata.Eq("userType", "Guest"))),
+			expected: "not(startsWith(displayName,'svc')) and (accountEnabled eq true or userType eq 'Guest')",
+		},
+		{
+			filter:   odata.Or(odata.And(odata.Ne("userType", "Guest"), odata.Ge("createdDateTime", time.Date(2021, 6, 1, 2, 0, 0, 0, time.FixedZone("CEST", 2*60*60)))), odata.Le("signInActivity/lastSignInDateTime", nil)),
+			expected: "userType ne 'Guest' and createdDateTime ge 2021-06-01T00:00:00Z or signInActivity/lastSignInDateTime le null",
+		},
+		{
+			filter:   odata.Not(odata.Or(odata.Eq("displayName", "O'Brien"), odata.And(odata.Gt("age", 18), odata.Or(odata.Lt("age", 65), odata.Eq("department", "Sales"))))),
+			expected: "not(displayName eq 'O''Brien' or age gt 18 and (age lt 65 or department eq 'Sales'))",
+		},
+		{
+			filter:   odata.And(odata.Or(odata.Eq("a", 1), odata.Eq("b", 2)), odata.Or(odata.Eq("c", 3), odata.Not(odata.And(odata.Eq("d", 4), odata.Eq("e", 5))))),
+			expected: "(a eq 1 or b eq 2) and (c eq 3 or not(d eq 4 and e eq 5))",
+		},
+		{
+			filter:   odata.And(odata.RawFilter("a eq 1 or b eq 2"), odata.Eq("c", 3)),
+			expected: "(a eq 1 or b eq 2) and c eq 3",
+		},
+		{
+			filter:   odata.Or(odata.Group(odata.Eq("a", 1)), odata.Group(odata.And(odata.Eq("b", 2), odata.Eq("c", 3)))),
+			expected: "(a eq 1) or (b eq 2 and c eq 3)",
+		},
+		{
+			filter:   odata.And(odata.Filter{}, odata.Or(odata.Eq("a", 1), odata.Eq("b", 2)), odata.Filter{}),
+			expected: "a eq 1 or b eq 2",
+		},
+		{
+			filter:   odata.Or(),
+			expected: "",
+		},
+	} {
+		if actual := c.filter.String(); actual != c.expected {
+			t.Errorf("test case %d: expected %q, got %q", n, c.expected, actual)
+		}
+	}
+}
+
+func TestInChunks(t *testing.T) {
+	values := []string{"a", "b", "c", "b", "d", "e"}
+
+	chunks := odata.InChunks("id", values, 2, 0)
+	expected := []string{"id in ('a','b')", "id in ('c','d')", "id in ('e')"}
+	if len(chunks) != len(expected) {
+		t.Fatalf("expected %d chunks, got %d: %v", len(expected), len(chunks), chunks)
+	}
+	for i, c := range chunks {
+		if c.String() != expected[i] {
+			t.Errorf("chunk %d: expected %q, got %q", i, expected[i], c.String())
+		}
+	}
+
+	// `id in ('a','b')` is 29 characters once URL encoded
+	chunks = odata.InChunks("id", values, 0, 29)
+	if len(chunks) != len(expected) {
+		t.Fatalf("expected %d chunks for length limit, got %d: %v", len(expected), len(chunks), chunks)
+	}
+	for i, c := range chunks {
+		if c.String() != expected[i] {
+			t.Errorf("chunk %d for length limit: expected %q, got %q", i, expected[i], c.String())
+		}
+	}
+
+	if chunks := odata.InChunks("id", nil, 15, 1500); len(chunks) != 0 {
+		t.Errorf("expected no chunks for no values, got %v", chunks)
+	}
+
+	if f := odata.And(odata.In("id", "a", "b"), odata.RawFilter("accountEnabled eq true")); f.String() != "id in ('a','b') and (accountEnabled eq true)" {
+		t.Errorf("unexpected combined filter %q", f.String())
+	}
+}
@@ -1,15 +1,20 @@
 package odata
 
 import (
+	"bytes"
 	"encoding/json"
 	"fmt"
+	"net/url"
 	"regexp"
 	"strings"
 )
 
 const (
 	ErrorAddedObjectReferencesAlreadyExist   = "One or more added object references already exist"
+	ErrorCannotRemoveLastOwner               = "must have at least one owner, hence this owner cannot be removed"
 	ErrorConflictingObjectPresentInDirectory = "A conflicting object with one or more of the specified property values is present in the directory"
+	ErrorDelegatedAuthenticationRequired     = "/me request is only valid with delegated authentication flow"
+	ErrorManagerDoesNotExist                 = "Resource 'manager' does not exist"
 	ErrorResourceDoesNotExist                = "Resource '.+' does not exist or one of its queried reference-property objects are not present"
 	ErrorRemovedObjectReferencesDoNotExist   = "One or more removed object references do not exist"
 	ErrorServicePrincipalInvalidAppId        = "The appId '.+' of the service principal does not reference a valid application object"
@@ -26,58 +31,83 @@ func (o *Id) UnmarshalJSON(data []byte) error {
 	return nil
 }
 
+// ObjectId returns the object ID of the directory object referenced by an @odata.id, such as those returned when listing
+// a collection of references with `$ref`. These are in the form `https://graph.microsoft.com/v1.0/{tenantId}/directoryObjects/{id}`,
+// optionally followed by a type cast segment such as `Microsoft.DirectoryServices.User`.
+func (o Id) ObjectId() (string, error) {
+	u, err := url.Parse(string(o))
+	if err != nil {
+		return "", fmt.Errorf("parsing @odata.id %q: %v", o, err)
+	}
+	segments := strings.Split(strings.Trim(u.Path, "/"), "/")
+	for i, s := range segments {
+		if strings.EqualFold(s, "directoryObjects") && i+1 < len(segments) && segments[i+1] != "" {
+			return segments[i+1], nil
+		}
+	}
+	return "", fmt.Errorf("could not determine object ID from @odata.id %q", o)
+}
+
 type ShortType = string
 
 const (
-	ShortTypeAdministrativeUnit                          ShortType = "administrativeUnit"
-	ShortTypeApplication                                 ShortType = "application"
-	ShortTypeConditionalAccessPolicy                     ShortType = "conditionalAccessPolicy"
-	ShortTypeCountryNamedLocation                        ShortType = "countryNamedLocation"
-	ShortTypeDevice                                      ShortType = "device"
-	ShortTypeDirectoryRole                               ShortType = "directoryRole"
-	ShortTypeDirectoryRoleTemplate                       ShortType = "directoryRoleTemplate"
-	ShortTypeDomain                                      ShortType = "domain"
-	ShortTypeEmailAuthenticationMethod                   ShortType = "emailAuthenticationMethod"
-	ShortTypeFido2AuthenticationMethod                   ShortType = "fido2AuthenticationMethod"
-	ShortTypeGroup                                       ShortType = "group"
-	ShortTypeIpNamedLocation                             ShortType = "ipNamedLocation"
-	ShortTypeNamedLocation                               ShortType = "namedLocation"
-	ShortTypeMicrosoftAuthenticatorAuthenticationMethod  ShortType = "microsoftAuthenticatorAuthenticationMethod"
-	ShortTypeOrganization                                ShortType = "organization"
-	ShortTypePasswordAuthenticationMethod                ShortType = "passwordAuthenticationMethod"
-	ShortTypePhoneAuthenticationMethod                   ShortType = "phoneAuthenticationMethod"
-	ShortTypeServicePrincipal                            ShortType = "servicePrincipal"
-	ShortTypeSocialIdentityProvider                      ShortType = "socialIdentityProvider"
-	ShortTypeTemporaryAccessPassAuthenticationMethod     ShortType = "temporaryAccessPassAuthenticationMethod"
-	ShortTypeUser                                        ShortType = "user"
-	ShortTypeWindowsHelloForBusinessAuthenticationMethod ShortType = "windowsHelloForBusinessAuthenticationMethod"
+	ShortTypeAccessReviewQueryScope                                  ShortType = "accessReviewQueryScope"
+	ShortTypeAdministrativeUnit                                      ShortType = "administrativeUnit"
+	ShortTypeApplication                                             ShortType = "application"
+	ShortTypeConditionalAccessPolicy                                 ShortType = "conditionalAccessPolicy"
+	ShortTypeCountryNamedLocation                                    ShortType = "countryNamedLocation"
+	ShortTypeDevice                                                  ShortType = "device"
+	ShortTypeDirectoryRole                                           ShortType = "directoryRole"
+	ShortTypeDirectoryRoleTemplate                                   ShortType = "directoryRoleTemplate"
+	ShortTypeDomain                                                  ShortType = "domain"
+	ShortTypeEmailAuthenticationMethod                               ShortType = "emailAuthenticationMethod"
+	ShortTypeFido2AuthenticationMethod                               ShortType = "fido2AuthenticationMethod"
+	ShortTypeFido2AuthenticationMethodConfiguration                  ShortType = "fido2AuthenticationMethodConfiguration"
+	ShortTypeGroup                                                   ShortType = "group"
+	ShortTypeIpNamedLocation                                         ShortType = "ipNamedLocation"
+	ShortTypeNamedLocation                                           ShortType = "namedLocation"
+	ShortTypeMicrosoftAuthenticatorAuthenticationMethod              ShortType = "microsoftAuthenticatorAuthenticationMethod"
+	ShortTypeMicrosoftAuthenticatorAuthenticationMethodConfiguration ShortType = "microsoftAuthenticatorAuthenticationMethodConfiguration"
+	ShortTypeOrganization                                            ShortType = "organization"
+	ShortTypePasswordAuthenticationMethod                            ShortType = "passwordAuthenticationMethod"
+	ShortTypePhoneAuthenticationMethod                               ShortType = "phoneAuthenticationMethod"
+	ShortTypeServicePrincipal                                        ShortType = "servicePrincipal"
+	ShortTypeSmsAuthenticationMethodConfiguration                    ShortType = "smsAuthenticationMethodConfiguration"
+	ShortTypeSocialIdentityProvider                                  ShortType = "socialIdentityProvider"
+	ShortTypeTemporaryAccessPassAuthenticationMethod                 ShortType = "temporaryAccessPassAuthenticationMethod"
+	ShortTypeUser                                                    ShortType = "user"
+	ShortTypeWindowsHelloForBusinessAuthenticationMethod             ShortType = "windowsHelloForBusinessAuthenticationMethod"
 )
 
 type Type = string
 
 const (
-	TypeAdministrativeUnit                          Type = "#microsoft.graph.administrativeUnit"
-	TypeApplication                                 Type = "#microsoft.graph.application"
-	TypeConditionalAccessPolicy                     Type = "#microsoft.graph.conditionalAccessPolicy"
-	TypeCountryNamedLocation                        Type = "#microsoft.graph.countryNamedLocation"
-	TypeDevice                                      Type = "#microsoft.graph.device"
-	TypeDirectoryRole                               Type = "#microsoft.graph.directoryRole"
-	TypeDirectoryRoleTemplate                       Type = "#microsoft.graph.directoryRoleTemplate"
-	TypeDomain                                      Type = "#microsoft.graph.domain"
-	TypeEmailAuthenticationMethod                   Type = "#microsoft.graph.emailAuthenticationMethod"
-	TypeFido2AuthenticationMethod                   Type = "#microsoft.graph.fido2AuthenticationMethod"
-	TypeGroup                                       Type = "#microsoft.graph.group"
-	TypeIpNamedLocation                             Type = "#microsoft.graph.ipNamedLocation"
-	TypeNamedLocation                               Type = "#microsoft.graph.namedLocation"
-	TypeMicrosoftAuthenticatorAuthenticationMethod  Type = "#microsoft.graph.microsoftAuthenticatorAuthenticationMethod"
-	TypeOrganization                                Type = "#microsoft.graph.organization"
-	TypePasswordAuthenticationMethod                Type = "#microsoft.graph.passwordAuthenticationMethod"
-	TypePhoneAuthenticationMethod                   Type = "#microsoft.graph.phoneAuthenticationMethod"
-	TypeServicePrincipal                            Type = "#microsoft.graph.servicePrincipal"
-	TypeSocialIdentityProvider                      Type = "#microsoft.graph.socialIdentityProvider"
-	TypeTemporaryAccessPassAuthenticationMethod     Type = "#microsoft.graph.temporaryAccessPassAuthenticationMethod"
-	TypeUser                                        Type = "#microsoft.graph.user"
-	TypeWindowsHelloForBusinessAuthenticationMethod Type = "#microsoft.graph.windowsHelloForBusinessAuthenticationMethod"
+	TypeAccessReviewQueryScope                                  Type = "#microsoft.graph.accessReviewQueryScope"
+	TypeAdministrativeUnit                                      Type = "#microsoft.graph.administrativeUnit"
+	TypeApplication                                             Type = "#microsoft.graph.application"
+	TypeConditionalAccessPolicy                                 Type = "#microsoft.graph.conditionalAccessPolicy"
+	TypeCountryNamedLocation                                    Type = "#microsoft.graph.countryNamedLocation"
+	TypeDevice                                                  Type = "#microsoft.graph.device"
+	TypeDirectoryRole                                           Type = "#microsoft.graph.directoryRole"
+	TypeDirectoryRoleTemplate                                   Type = "#microsoft.graph.directoryRoleTemplate"
+	TypeDomain                                                  Type = "#microsoft.graph.domain"
+	TypeEmailAuthenticationMethod                               Type = "#microsoft.graph.emailAuthenticationMethod"
+	TypeFido2AuthenticationMethod                               Type = "#microsoft.graph.fido2AuthenticationMethod"
+	TypeFido2AuthenticationMethodConfiguration                  Type = "#microsoft.graph.fido2AuthenticationMethodConfiguration"
+	TypeGroup                                                   Type = "#microsoft.graph.group"
+	TypeIpNamedLocation                                         Type = "#microsoft.graph.ipNamedLocation"
+	TypeNamedLocation                                           Type = "#microsoft.graph.namedLocation"
+	TypeMicrosoftAuthenticatorAuthenticationMethod              Type = "#microsoft.graph.microsoftAuthenticatorAuthenticationMethod"
+	TypeMicrosoftAuthenticatorAuthenticationMethodConfiguration Type = "#microsoft.graph.microsoftAuthenticatorAuthenticationMethodConfiguration"
+	TypeOrganization                                            Type = "#microsoft.graph.organization"
+	TypePasswordAuthenticationMethod                            Type = "#microsoft.graph.passwordAuthenticationMethod"
+	TypePhoneAuthenticationMethod                               Type = "#microsoft.graph.phoneAuthenticationMethod"
+	TypeServicePrincipal                                        Type = "#microsoft.graph.servicePrincipal"
+	TypeSmsAuthenticationMethodConfiguration                    Type = "#microsoft.graph.smsAuthenticationMethodConfiguration"
+	TypeSocialIdentityProvider                                  Type = "#microsoft.graph.socialIdentityProvider"
+	TypeTemporaryAccessPassAuthenticationMethod                 Type = "#microsoft.graph.temporaryAccessPassAuthenticationMethod"
+	TypeUser                                                    Type = "#microsoft.graph.user"
+	TypeWindowsHelloForBusinessAuthenticationMethod             Type = "#microsoft.graph.windowsHelloForBusinessAuthenticationMethod"
 )
 
 // OData is used to unmarshall OData metadata from an API response.
@@ -85,7 +115,7 @@ type OData struct {
 	Context      *string `json:"@odata.context"`
 	MetadataEtag *string `json:"@odata.metadataEtag"`
 	Type         *Type   `json:"@odata.type"`
-	Count        *string `json:"@odata.count"`
+	Count        *int64  `json:"@odata.count"`
 	NextLink     *string `json:"@odata.nextLink"`
 	Delta        *string `json:"@odata.delta"`
 	DeltaLink    *string `json:"@odata.deltaLink"`
@@ -97,11 +127,33 @@ type OData struct {
 	Value interface{} `json:"value"`
 }
 
+// SkipToken returns the $skiptoken parameter from the NextLink, which can be supplied in a Query to retrieve the next
+// page of results. Returns an empty string when there are no further pages.
+func (o OData) SkipToken() string {
+	if o.NextLink == nil {
+		return ""
+	}
+	return linkParam(*o.NextLink, "$skiptoken")
+}
+
+// DeltaToken returns the $deltatoken parameter from the DeltaLink, which can be supplied in a Query to a delta function
+// to retrieve changes made since this response. Returns an empty string when the response has no DeltaLink, which is
+// only returned with the final page of a delta query.
+func (o OData) DeltaToken() string {
+	if o.DeltaLink == nil {
+		return ""
+	}
+	return linkParam(*o.DeltaLink, "$deltatoken")
+}
+
 func (o *OData) UnmarshalJSON(data []byte) error {
 	// Perform unmarshalling using a local type
+	// Numbers in the Value are decoded as json.Number, so that they retain full precision when pages are combined
 	type odata OData
 	var o2 odata
-	if err := json.Unmarshal(data, &o2); err != nil {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	if err := dec.Decode(&o2); err != nil {
 		return err
 	}
 	*o = OData(o2)
@@ -39,6 +39,18 @@ func TestOData(t *testing.T) {
 				}},
 			},
 		},
+		{
+			response: `{
+  "@odata.context": "https://graph.microsoft.com/v1.0/$metadata#users",
+  "@odata.count": 42,
+  "value": []
+}`,
+			expected: odata.OData{
+				Context: utils.StringPtr("https://graph.microsoft.com/v1.0/$metadata#users"),
+				Count:   utils.Int64Ptr(42),
+				Value:   []interface{}{},
+			},
+		},
 	}
 	for n, c := range testCases {
 		var o odata.OData
@@ -120,3 +132,115 @@ func TestError(t *testing.T) {
 		}
 	}
 }
+
+func TestId_ObjectId(t *testing.T) {
+	for _, c := range []struct {
+		id       odata.Id
+		expected string
+		valid    bool
+	}{
+		{id: "https://graph.microsoft.com/v1.0/00000000-0000-0000-0000-000000000000/directoryObjects/11111111-1111-1111-1111-111111111111", expected: "11111111-1111-1111-1111-111111111111", valid: true},
+		{id: "https://graph.microsoft.com/v1.0/00000000-0000-0000-0000-000000000000/directoryObjects/11111111-1111-1111-1111-111111111111/Microsoft.DirectoryServices.User", expected: "11111111-1111-1111-1111-111111111111", valid: true},
+		{id: "https://graph.microsoft.com/beta/directoryObjects/11111111-1111-1111-1111-111111111111", expected: "11111111-1111-1111-1111-111111111111", valid: true},
+		{id: "https://graph.microsoft.com/v1.0/users/11111111-1111-1111-1111-111111111111", valid: false},
+		{id: "", valid: false},
+	} {
+		objectId, err := c.id.ObjectId()
+		if c.valid && err != nil {
+			t.Fatalf("ObjectId(%q): unexpected error: %v", c.id, err)
+		}
+		if !c.valid && err == nil {
+			t.Fatalf("ObjectId(%q): expected error", c.id)
+		}
+		if objectId != c.expected {
+			t.Fatalf("ObjectId(%q): expected %q, got %q", c.id, c.expected, objectId)
+		}
+	}
+}
+
+func TestOData_SkipToken(t *testing.T) {
+	for _, c := range []struct {
+		nextLink *string
+		expected string
+	}{
+		{nextLink: nil, expected: ""},
+		{nextLink: utils.StringPtr("https://graph.microsoft.com/v1.0/users?$top=10&$skiptoken=RFNwdAIAAQAAAB8"), expected: "RFNwdAIAAQAAAB8"},
+		{nextLink: utils.StringPtr("https://graph.microsoft.com/v1.0/users?$top=10"), expected: ""},
+	} {
+		if token := (odata.OData{NextLink: c.nextLink}).SkipToken(); token != c.expected {
+			t.Fatalf("SkipToken(): expected %q, got %q", c.expected, token)
+		}
+	}
+}
+
+func TestOData_UseNumber(t *testing.T) {
+	var o odata.OData
+	if err := json.Unmarshal([]byte(`{"value":[{"count":9007199254740993}]}`), &o); err != nil {
+		t.Fatalf("json.Unmarshal(): %v", err)
+	}
+	out, err := json.Marshal(o.Value)
+	if err != nil {
+		t.Fatalf("json.Marshal(): %v", err)
+	}
+	if expected := `[{"count":9007199254740993}]`; string(out) != expected {
+		t.Fatalf("expected value %s, got %s", expected, out)
+	}
+}
+
+func TestUnmarshalResponse(t *testing.T) {
+	r, err := odata.UnmarshalResponse([]byte(`{"@odata.context":"https://graph.microsoft.com/v1.0/$metadata#users","@odata.count":3,"@odata.nextLink":"https://graph.microsoft.com/v1.0/users?$skiptoken=RFNwdAIAAQAAAB8","value":[{"id":"1"},{"id":"2"}]}`))
+	if err != nil {
+		t.Fatalf("UnmarshalResponse(): %v", err)
+	}
+	if r.Context != "https://graph.microsoft.com/v1.0/$metadata#users" || r.Count == nil || *r.Count != 3 || r.DeltaLink != "" {
+		t.Fatalf("UnmarshalResponse(): unexpected envelope %+v", r)
+	}
+	if token := r.SkipToken(); token != "RFNwdAIAAQAAAB8" {
+		t.Fatalf("Response.SkipToken(): expected %q, got %q", "RFNwdAIAAQAAAB8", token)
+	}
+
+	items, err := r.Items()
+	if err != nil {
+		t.Fatalf("Response.Items(): %v", err)
+	}
+	if len(items) != 2 || string(items[1]) != `{"id":"2"}` {
+		t.Fatalf("Response.Items(): unexpected items %s", items)
+	}
+
+	var users []struct {
+		ID string `json:"id"`
+	}
+	if err := r.UnmarshalValue(&users); err != nil {
+		t.Fatalf("Response.UnmarshalValue(): %v", err)
+	}
+	if len(users) != 2 || users[0].ID != "1" {
+		t.Fatalf("Response.UnmarshalValue(): unexpected value %v", users)
+	}
+
+	r, err = odata.UnmarshalResponse([]byte(`{"@odata.deltaLink":"https://graph.microsoft.com/v1.0/users/delta?$deltatoken=abc123","value":[]}`))
+	if err != nil {
+		t.Fatalf("UnmarshalResponse(): %v", err)
+	}
+	if r.NextLink != "" || r.SkipToken() != "" || r.DeltaToken() != "abc123" {
+		t.Fatalf("UnmarshalResponse(): unexpected links in %+v", r)
+	}
+	if items, err := r.Items(); err != nil || len(items) != 0 {
+		t.Fatalf("Response.Items(): expected no items, got %s (%v)", items, err)
+	}
+
+	r, err = odata.UnmarshalResponse([]byte(`{"value":"Onboarding"}`))
+	if err != nil {
+		t.Fatalf("UnmarshalResponse(): %v", err)
+	}
+	if _, err := r.Items(); err == nil {
+		t.Fatal("Response.Items(): expected error for a value which is not a collection")
+	}
+
+	r, err = odata.UnmarshalResponse([]byte(`{"@odata.count":8589934592,"value":[]}`))
+	if err != nil {
+		t.Fatalf("UnmarshalResponse(): %v", err)
+	}
+	if r.Count == nil || *r.Count != 8589934592 {
+		t.Fatalf("UnmarshalResponse(): unexpected count in %+v", r)
+	}
+}
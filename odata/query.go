@@ -1,13 +1,25 @@
 package odata
 
 import (
+	"errors"
 	"fmt"
+	"net/http"
 	"net/url"
+	"regexp"
 	"strconv"
 	"strings"
+	"time"
 )
 
 type Query struct {
+	// ConsistencyLevel sets the corresponding http header, which is required for advanced queries such as filtering
+	// on the count of a navigation property
+	ConsistencyLevel ConsistencyLevel
+
+	// DeltaToken retrieves changes made since a previous delta query, when querying a delta function. The token is
+	// obtained from the final page of the previous query (see OData.DeltaToken).
+	DeltaToken string
+
 	// Count includes a count of the total number of items in a collection alongside the page of data values
 	Count bool
 
@@ -32,14 +44,79 @@ type Query struct {
 	// Skip sets the number of items to skip at the start of a collection
 	Skip int
 
+	// SkipToken resumes a paged collection from the page identified by the token, which is obtained from a previous
+	// request (see OData.SkipToken). Since the remaining pages are otherwise retrieved automatically, this should be
+	// used with Top to retrieve a single page at a time.
+	SkipToken string
+
 	// Top specifies the page size of the result set
 	Top int
 }
 
+// Headers returns the http.Header values to be sent with requests using this query.
+func (q Query) Headers() http.Header {
+	h := http.Header{}
+	if q.ConsistencyLevel != "" {
+		h.Set("ConsistencyLevel", string(q.ConsistencyLevel))
+	}
+	return h
+}
+
+// Validate returns an error if the query cannot be satisfied by the API. Filtering on the count of a navigation
+// property or on a directory extension property is an advanced query and is only supported with eventual consistency.
+func (q Query) Validate() error {
+	if q.countFilter() && q.ConsistencyLevel != ConsistencyLevelEventual {
+		return errors.New("filtering on a navigation property count requires ConsistencyLevel to be ConsistencyLevelEventual")
+	}
+	if q.extensionFilter() && q.ConsistencyLevel != ConsistencyLevelEventual {
+		return errors.New("filtering on a directory extension property requires ConsistencyLevel to be ConsistencyLevelEventual")
+	}
+	return nil
+}
+
+// AdvancedQuery indicates whether the query uses capabilities that Microsoft Graph only supports for directory objects
+// as advanced queries, which require eventual consistency and $count=true. These are $search, and filters using the
+// `ne` or `not` operators, the `endsWith` function, the count of a navigation property, or a directory extension
+// property.
+func (q Query) AdvancedQuery() bool {
+	if q.Search != "" {
+		return true
+	}
+	filter := stringLiteralRegex.ReplaceAllString(q.Filter, "''")
+	return advancedFilterRegex.MatchString(filter) || extensionPropertyRegex.MatchString(filter)
+}
+
+var (
+	// advancedFilterRegex matches operators and functions which are only supported in advanced queries
+	advancedFilterRegex = regexp.MustCompile(`(?i)\bne\b|\bnot\b|\bendsWith\s*\(|/\$count\b`)
+
+	// extensionPropertyRegex matches the names of directory extension properties, e.g.
+	// `extension_b7d8e648520f41d3b9c0fdeb91768a0a_jobGroup`
+	extensionPropertyRegex = regexp.MustCompile(`(?i)\bextension_[0-9a-f]{32}_\w+`)
+
+	// stringLiteralRegex matches string literals in a filter, including escaped quotes
+	stringLiteralRegex = regexp.MustCompile(`'(?:[^']|'')*'`)
+)
+
+// countFilter indicates whether the filter references the count of a navigation property, e.g. `members/$count eq 0`
+func (q Query) countFilter() bool {
+	return strings.Contains(stringLiteralRegex.ReplaceAllString(q.Filter, "''"), "/$count")
+}
+
+// extensionFilter indicates whether the filter references a directory extension property
+func (q Query) extensionFilter() bool {
+	return extensionPropertyRegex.MatchString(stringLiteralRegex.ReplaceAllString(q.Filter, "''"))
+}
+
+// Values returns the url.Values for the query. For advanced queries with eventual consistency, $count is always
+// included since it is required by the API.
 func (q Query) Values() url.Values {
 	p := url.Values{}
-	if q.Count {
-		p.Add("$count", fmt.Sprintf("%t", q.Count))
+	if q.Count || (q.ConsistencyLevel == ConsistencyLevelEventual && q.AdvancedQuery()) {
+		p.Add("$count", "true")
+	}
+	if q.DeltaToken != "" {
+		p.Add("$deltatoken", q.DeltaToken)
 	}
 	if expand := q.Expand.String(); expand != "" {
 		p.Add("$expand", expand)
@@ -62,12 +139,71 @@ func (q Query) Values() url.Values {
 	if q.Skip > 0 {
 		p.Add("$skip", strconv.Itoa(q.Skip))
 	}
+	if q.SkipToken != "" {
+		p.Add("$skiptoken", q.SkipToken)
+	}
 	if q.Top > 0 {
 		p.Add("$top", strconv.Itoa(q.Top))
 	}
 	return p
 }
 
+// Encode returns the query as a URL-encoded query string, suitable for logging or previewing the exact query which
+// will be sent. Parameters are sorted by name, and names and values are percent-encoded as by url.Values.Encode(), so
+// that characters such as spaces, quotes and dollar signs are escaped (e.g. `$filter=a eq 'b'` is encoded as
+// `%24filter=a+eq+%27b%27`).
+func (q Query) Encode() string {
+	return q.Values().Encode()
+}
+
+// AppendToURL adds the query parameters to the query string of the provided URL, replacing any existing parameters
+// with the same names and preserving all others. The resulting query string is encoded as by Encode().
+func (q Query) AppendToURL(u *url.URL) {
+	if u == nil {
+		return
+	}
+	v := u.Query()
+	for key, values := range q.Values() {
+		v[key] = values
+	}
+	u.RawQuery = v.Encode()
+}
+
+// CountFilter returns a filter expression comparing the number of objects in a navigation property, such as members or
+// appRoleAssignedTo, with the specified value. The operator should be one of eq, ne, gt, ge, lt or le. Queries using
+// this filter require eventual consistency.
+func CountFilter(relationship, operator string, value int) string {
+	return fmt.Sprintf("%s/$count %s %d", relationship, operator, value)
+}
+
+// ExtensionProperty returns the name of the directory extension property with the specified name, registered by the
+// application with the specified application ID, in the form `extension_{appId}_{name}` where the application ID has
+// no hyphens. This can be used in filter expressions, e.g. `Eq(ExtensionProperty(appId, "jobGroup"), "Sales")`, which
+// are advanced queries requiring eventual consistency.
+func ExtensionProperty(appId, name string) string {
+	return fmt.Sprintf("extension_%s_%s", strings.ReplaceAll(appId, "-", ""), name)
+}
+
+// DateRangeFilter returns a filter expression matching objects where the specified date-time property is within the
+// range [start, end). Either bound can be omitted by passing a zero time.Time. Returns an empty string when both
+// bounds are omitted.
+func DateRangeFilter(property string, start, end time.Time) string {
+	var clauses []string
+	if !start.IsZero() {
+		clauses = append(clauses, fmt.Sprintf("%s ge %s", property, start.UTC().Format(time.RFC3339)))
+	}
+	if !end.IsZero() {
+		clauses = append(clauses, fmt.Sprintf("%s lt %s", property, end.UTC().Format(time.RFC3339)))
+	}
+	return strings.Join(clauses, " and ")
+}
+
+type ConsistencyLevel string
+
+const (
+	ConsistencyLevelEventual ConsistencyLevel = "eventual"
+)
+
 type Expand struct {
 	Relationship string
 	Select       []string
@@ -89,6 +225,15 @@ const (
 	FormatXml  Format = "xml"
 )
 
+// Metadata specifies the amount of OData control information to include in a JSON response
+type Metadata string
+
+const (
+	MetadataFull    Metadata = "full"
+	MetadataMinimal Metadata = "minimal"
+	MetadataNone    Metadata = "none"
+)
+
 type Direction string
 
 const (
@@ -4,6 +4,7 @@ import (
 	"net/url"
 	"reflect"
 	"testing"
+	"time"
 
 	"github.com/manicminer/hamilton/odata"
 )
@@ -32,6 +33,16 @@ func TestQuery(t *testing.T) {
 				"$top":    []string{"10"},
 			},
 		},
+		{
+			query: odata.Query{
+				SkipToken: "X'4453707402000100'",
+				Top:       10,
+			},
+			expected: url.Values{
+				"$skiptoken": []string{"X'4453707402000100'"},
+				"$top":       []string{"10"},
+			},
+		},
 		{
 			query: odata.Query{
 				OrderBy: odata.OrderBy{
@@ -78,6 +89,16 @@ func TestQuery(t *testing.T) {
 				"$select": []string{"id,userPrincipalName"},
 			},
 		},
+		{
+			query: odata.Query{
+				ConsistencyLevel: odata.ConsistencyLevelEventual,
+				Filter:           odata.CountFilter("members", "eq", 0),
+			},
+			expected: url.Values{
+				"$count":  []string{"true"},
+				"$filter": []string{"members/$count eq 0"},
+			},
+		},
 	}
 	for n, c := range testCases {
 		v := c.query.Values()
@@ -86,3 +107,106 @@ func TestQuery(t *testing.T) {
 		}
 	}
 }
+
+func TestQuery_Validate(t *testing.T) {
+	filter := odata.CountFilter("appRoleAssignedTo", "gt", 0)
+	if err := (odata.Query{Filter: filter}).Validate(); err == nil {
+		t.Error("expected an error for a count filter without eventual consistency")
+	}
+	q := odata.Query{ConsistencyLevel: odata.ConsistencyLevelEventual, Filter: filter}
+	if err := q.Validate(); err != nil {
+		t.Errorf("unexpected error for a count filter with eventual consistency: %v", err)
+	}
+	if h := q.Headers().Get("ConsistencyLevel"); h != "eventual" {
+		t.Errorf("expected ConsistencyLevel header %q, got %q", "eventual", h)
+	}
+
+	filter = odata.Eq(odata.ExtensionProperty("b7d8e648-520f-41d3-b9c0-fdeb91768a0a", "jobGroup"), "Sales").String()
+	if err := (odata.Query{Filter: filter}).Validate(); err == nil {
+		t.Error("expected an error for an extension property filter without eventual consistency")
+	}
+	if err := (odata.Query{ConsistencyLevel: odata.ConsistencyLevelEventual, Filter: filter}).Validate(); err != nil {
+		t.Errorf("unexpected error for an extension property filter with eventual consistency: %v", err)
+	}
+	if err := (odata.Query{Filter: "displayName eq 'extension_b7d8e648520f41d3b9c0fdeb91768a0a_jobGroup'"}).Validate(); err != nil {
+		t.Errorf("unexpected error for an extension property name in a string literal: %v", err)
+	}
+	if err := (odata.Query{Filter: "displayName eq 'a/$count'"}).Validate(); err != nil {
+		t.Errorf("unexpected error for a count segment in a string literal: %v", err)
+	}
+}
+
+func TestExtensionProperty(t *testing.T) {
+	if p := odata.ExtensionProperty("b7d8e648-520f-41d3-b9c0-fdeb91768a0a", "jobGroup"); p != "extension_b7d8e648520f41d3b9c0fdeb91768a0a_jobGroup" {
+		t.Errorf("ExtensionProperty(): unexpected property name %q", p)
+	}
+}
+
+func TestQuery_AdvancedQuery(t *testing.T) {
+	for filter, expected := range map[string]bool{
+		"":                                       false,
+		"userType eq 'Guest'":                    false,
+		"displayName eq 'Anne Smith'":            false,
+		"displayName eq 'not ne endsWith(x)'":    false,
+		"displayName eq 'it''s not'":             false,
+		"userType ne 'Member'":                   true,
+		"not(startsWith(displayName,'a'))":       true,
+		"endsWith(mail,'@example.com')":          true,
+		odata.CountFilter("memberOf", "eq", 0):   true,
+		"userType eq 'Guest' and accountEnabled": false,
+		"extension_b7d8e648520f41d3b9c0fdeb91768a0a_jobGroup eq 'Sales'":       true,
+		"displayName eq 'extension_b7d8e648520f41d3b9c0fdeb91768a0a_jobGroup'": false,
+	} {
+		if actual := (odata.Query{Filter: filter}).AdvancedQuery(); actual != expected {
+			t.Errorf("Query.AdvancedQuery(): expected %t for filter %q, got %t", expected, filter, actual)
+		}
+	}
+	if !(odata.Query{Search: "displayName:Astley"}).AdvancedQuery() {
+		t.Error("Query.AdvancedQuery(): expected true for $search")
+	}
+
+	v := odata.Query{ConsistencyLevel: odata.ConsistencyLevelEventual, Filter: "userType ne 'Member'"}.Values()
+	if v.Get("$count") != "true" {
+		t.Errorf("Query.Values(): expected $count=true for advanced query, got %q", v.Get("$count"))
+	}
+}
+
+func TestQuery_Encode(t *testing.T) {
+	q := odata.Query{
+		Filter: "displayName eq 'Rick & Morty'",
+		Select: []string{"id", "displayName"},
+		Top:    5,
+	}
+	expected := "%24filter=displayName+eq+%27Rick+%26+Morty%27&%24select=id%2CdisplayName&%24top=5"
+	if encoded := q.Encode(); encoded != expected {
+		t.Errorf("Query.Encode(): expected %q, got %q", expected, encoded)
+	}
+
+	u, err := url.Parse("https://graph.microsoft.com/v1.0/users?%24top=100&foo=bar")
+	if err != nil {
+		t.Fatal(err)
+	}
+	q.AppendToURL(u)
+	expected = "%24filter=displayName+eq+%27Rick+%26+Morty%27&%24select=id%2CdisplayName&%24top=5&foo=bar"
+	if u.RawQuery != expected {
+		t.Errorf("Query.AppendToURL(): expected %q, got %q", expected, u.RawQuery)
+	}
+}
+
+func TestDateRangeFilter(t *testing.T) {
+	start := time.Date(2021, 6, 1, 0, 0, 0, 0, time.UTC)
+	end := time.Date(2021, 6, 8, 2, 0, 0, 0, time.FixedZone("CEST", 2*60*60))
+	for _, c := range []struct {
+		start, end time.Time
+		expected   string
+	}{
+		{start, end, "detectedDateTime ge 2021-06-01T00:00:00Z and detectedDateTime lt 2021-06-08T00:00:00Z"},
+		{start, time.Time{}, "detectedDateTime ge 2021-06-01T00:00:00Z"},
+		{time.Time{}, end, "detectedDateTime lt 2021-06-08T00:00:00Z"},
+		{time.Time{}, time.Time{}, ""},
+	} {
+		if filter := odata.DateRangeFilter("detectedDateTime", c.start, c.end); filter != c.expected {
+			t.Errorf("DateRangeFilter(): expected %q, got %q", c.expected, filter)
+		}
+	}
+}
@@ -0,0 +1,70 @@
+package odata
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+)
+
+// Response is the envelope of a collection response, with the annotations used for paging and delta queries decoded
+// and the items of the collection left undecoded in Value. It can be used to handle responses from endpoints for
+// which there are no typed methods.
+type Response struct {
+	Context   string          `json:"@odata.context"`
+	NextLink  string          `json:"@odata.nextLink"`
+	DeltaLink string          `json:"@odata.deltaLink"`
+	Count     *int64          `json:"@odata.count"`
+	Value     json.RawMessage `json:"value"`
+}
+
+// UnmarshalResponse decodes the envelope of a collection response.
+func UnmarshalResponse(data []byte) (*Response, error) {
+	var r Response
+	if err := json.Unmarshal(data, &r); err != nil {
+		return nil, err
+	}
+	return &r, nil
+}
+
+// Items returns the undecoded items in Value, which is empty when the response has no value.
+func (r Response) Items() ([]json.RawMessage, error) {
+	var items []json.RawMessage
+	if len(r.Value) == 0 || string(r.Value) == "null" {
+		return items, nil
+	}
+	if err := json.Unmarshal(r.Value, &items); err != nil {
+		return nil, fmt.Errorf("value is not a collection: %v", err)
+	}
+	return items, nil
+}
+
+// UnmarshalValue decodes Value into v, which should be a pointer to a slice.
+func (r Response) UnmarshalValue(v interface{}) error {
+	if len(r.Value) == 0 {
+		return nil
+	}
+	return json.Unmarshal(r.Value, v)
+}
+
+// SkipToken returns the $skiptoken parameter from the NextLink, or an empty string when there are no further pages.
+func (r Response) SkipToken() string {
+	return linkParam(r.NextLink, "$skiptoken")
+}
+
+// DeltaToken returns the $deltatoken parameter from the DeltaLink, or an empty string when the response has no
+// DeltaLink.
+func (r Response) DeltaToken() string {
+	return linkParam(r.DeltaLink, "$deltatoken")
+}
+
+// linkParam returns the value of the query parameter param in link, or an empty string if it cannot be found
+func linkParam(link, param string) string {
+	if link == "" {
+		return ""
+	}
+	u, err := url.Parse(link)
+	if err != nil {
+		return ""
+	}
+	return u.Query().Get(param)
+}